@@ -0,0 +1,51 @@
+// mock-oidc는 로컬 개발용 모의 OIDC 제공자를 실행합니다
+//
+//	mock-oidc [-addr :9400] [-client-id fleecy-local] [-client-secret ...] [-email dev@example.com] [-groups fl-admins,fl-ops]
+//
+// OIDC_PROVIDERS_CONFIG 예시:
+//
+//	{"providers":[{"name":"mock","issuer":"http://localhost:9400","client_id":"fleecy-local",
+//	  "redirect_url":"http://localhost:8080/api/auth/oidc/mock/callback"}]}
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Mungge/Fleecy-Cloud/services/oidc/oidctest"
+)
+
+func main() {
+	addr := flag.String("addr", ":9400", "수신 주소")
+	issuer := flag.String("issuer", "http://localhost:9400", "issuer 주소 (백엔드 설정의 issuer와 같아야 함)")
+	clientID := flag.String("client-id", "fleecy-local", "클라이언트 ID")
+	clientSecret := flag.String("client-secret", "", "클라이언트 시크릿 (비우면 공개 클라이언트)")
+	email := flag.String("email", "dev@example.com", "로그인할 사용자 이메일")
+	name := flag.String("name", "Local Developer", "로그인할 사용자 이름")
+	groups := flag.String("groups", "", "사용자 그룹 (쉼표 구분)")
+	flag.Parse()
+
+	idp, err := oidctest.NewMockIdP(*issuer, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatalf("모의 IdP 생성 실패: %v", err)
+	}
+
+	groupList := []string{}
+	for _, g := range strings.Split(*groups, ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			groupList = append(groupList, g)
+		}
+	}
+	idp.SetUser(map[string]interface{}{
+		"sub":            "mock-" + *email,
+		"email":          *email,
+		"email_verified": true,
+		"name":           *name,
+		"groups":         groupList,
+	})
+
+	log.Printf("모의 OIDC 제공자 시작: %s (issuer: %s, client_id: %s, 사용자: %s)", *addr, *issuer, *clientID, *email)
+	log.Fatal(http.ListenAndServe(*addr, idp))
+}
@@ -0,0 +1,549 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+const (
+	maxExecuteBodyBytes = 32 << 20 // 번들 파일을 포함한 실행 요청 최대 크기
+	maxStopBodyBytes    = 64 << 10
+	maxProbeBodyBytes   = 4 << 10
+	probeDialTimeout    = 5 * time.Second // 지연시간 측정 TCP 연결 한 번의 제한 시간
+	maxFinishedJobs     = 20              // 로그 조회를 위해 보관하는 끝난 작업 수
+	stopResponseWait    = 5 * time.Second // 중지 요청 응답 전 작업 종료를 기다리는 시간
+	jobLogFile          = "agent.log"
+)
+
+// Agent는 백엔드의 실행/중지 요청을 받아 작업을 실행하고 상태를 알립니다
+type Agent struct {
+	participantID string
+	secret        string
+	workDir       string
+	runner        Runner
+	reporter      *reporter
+
+	mu    sync.Mutex
+	jobs  map[string]*job
+	order []string // 작업 생성 순서 (오래된 끝난 작업 정리용)
+}
+
+// NewAgent는 새 Agent 인스턴스를 생성합니다
+func NewAgent(participantID, secret, workDir string, runner Runner, callbackClient *http.Client) *Agent {
+	return &Agent{
+		participantID: participantID,
+		secret:        secret,
+		workDir:       workDir,
+		runner:        runner,
+		reporter: &reporter{
+			client:        callbackClient,
+			secret:        secret,
+			participantID: participantID,
+			backoff:       callbackBackoff,
+		},
+		jobs: make(map[string]*job),
+	}
+}
+
+// job은 실행 요청 하나의 진행 상태입니다
+type job struct {
+	request *participantagent.ExecuteRequest
+	dir     string
+	log     *jobLog
+	reports *jobReporter
+	ctx     context.Context // 제한 시간이 적용된 실행 컨텍스트 (설치 + 학습)
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu         sync.Mutex
+	state      string
+	round      int
+	sequence   int64
+	stopReason string
+}
+
+// report는 작업 상태를 바꾸고 상태 알림을 큐에 넣습니다 (끝난 작업은 무시)
+func (j *job) report(state string, round int, message string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if participantagent.IsTerminalState(j.state) {
+		return
+	}
+	j.state = state
+	j.sequence++
+	if len(message) > 4096 {
+		message = message[:4096]
+	}
+	j.reports.enqueue(&participantagent.StatusCallback{
+		ProtocolVersion:     participantagent.ProtocolVersion,
+		JobID:               j.request.JobID,
+		FederatedLearningID: j.request.FederatedLearningID,
+		ParticipantID:       j.request.ParticipantID,
+		Sequence:            j.sequence,
+		State:               state,
+		Round:               round,
+		Message:             strings.ToValidUTF8(message, ""),
+		Timestamp:           time.Now().UTC(),
+	})
+}
+
+// observeLine은 Flower 클라이언트 출력에서 라운드 시작을 감지해 training 알림을 보냅니다
+func (j *job) observeLine(line string) {
+	var round int
+	if _, err := fmt.Sscanf(strings.TrimSpace(line), roundMarker, &round); err != nil || round <= 0 {
+		return
+	}
+	j.mu.Lock()
+	advanced := round > j.round && j.state == participantagent.StateTraining
+	if advanced {
+		j.round = round
+	}
+	j.mu.Unlock()
+	if advanced {
+		j.report(participantagent.StateTraining, round, fmt.Sprintf("라운드 %d 학습 시작", round))
+	}
+}
+
+// stop은 작업 중지를 요청합니다 (이미 끝난 작업은 영향 없음)
+func (j *job) stop(reason string) {
+	j.mu.Lock()
+	if j.stopReason == "" {
+		j.stopReason = reason
+	}
+	j.mu.Unlock()
+	j.cancel()
+}
+
+func (j *job) snapshot() (state string, round int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.state, j.round
+}
+
+func (j *job) finished() bool {
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Handler는 에이전트 API 라우터를 반환합니다
+func (a *Agent) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+participantagent.ExecutePath, a.handleExecute)
+	mux.HandleFunc("POST "+participantagent.StopPath, a.handleStop)
+	mux.HandleFunc("GET "+participantagent.LogsPath, a.handleLogs)
+	mux.HandleFunc("GET "+participantagent.HealthPath, a.handleHealth)
+	mux.HandleFunc("POST "+participantagent.LatencyProbePath, a.handleLatencyProbe)
+	return mux
+}
+
+// handleExecute는 실행 요청을 받아 작업을 시작합니다 (같은 job_id는 다시 실행하지 않음)
+func (a *Agent) handleExecute(w http.ResponseWriter, r *http.Request) {
+	body, ok := a.readAuthenticated(w, r, maxExecuteBodyBytes)
+	if !ok {
+		return
+	}
+	var req participantagent.ExecuteRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "잘못된 요청 형식입니다")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.ParticipantID != a.participantID {
+		writeError(w, http.StatusBadRequest, "participant_id가 이 에이전트와 일치하지 않습니다")
+		return
+	}
+
+	a.mu.Lock()
+	if existing, ok := a.jobs[req.JobID]; ok {
+		a.mu.Unlock()
+		state, _ := existing.snapshot()
+		writeJSON(w, http.StatusOK, participantagent.JobResponse{
+			ProtocolVersion: participantagent.ProtocolVersion,
+			JobID:           req.JobID,
+			State:           state,
+			Duplicate:       true,
+		})
+		return
+	}
+	// 같은 연합학습의 이전 회차 작업은 새 작업으로 대체
+	var superseded []*job
+	for _, other := range a.jobs {
+		if other.request.FederatedLearningID == req.FederatedLearningID && !other.finished() {
+			superseded = append(superseded, other)
+		}
+	}
+	j, err := a.newJob(&req)
+	if err != nil {
+		a.mu.Unlock()
+		log.Printf("작업 %s 준비 실패: %v", req.JobID, err)
+		writeError(w, http.StatusInternalServerError, "작업 준비에 실패했습니다")
+		return
+	}
+	a.jobs[req.JobID] = j
+	a.order = append(a.order, req.JobID)
+	a.pruneLocked()
+	a.mu.Unlock()
+
+	for _, other := range superseded {
+		other.stop(fmt.Sprintf("새 작업 %s로 대체되었습니다", req.JobID))
+	}
+
+	log.Printf("작업 시작: %s (서버 %s, 제한 시간 %ds)", req.JobID, req.ServerAddress, req.Timeout)
+	go a.run(j, superseded)
+
+	writeJSON(w, http.StatusAccepted, participantagent.JobResponse{
+		ProtocolVersion: participantagent.ProtocolVersion,
+		JobID:           req.JobID,
+		State:           participantagent.StateAccepted,
+	})
+}
+
+// newJob은 작업 디렉토리에 요청 파일을 저장하고 작업을 만듭니다
+func (a *Agent) newJob(req *participantagent.ExecuteRequest) (*job, error) {
+	dir := filepath.Join(a.workDir, "jobs", req.JobID)
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	for name, content := range req.Files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			return nil, fmt.Errorf("파일 저장 실패 (%s): %w", name, err)
+		}
+	}
+
+	j := &job{
+		request: req,
+		dir:     dir,
+		done:    make(chan struct{}),
+	}
+	jobLog, err := newJobLog(filepath.Join(dir, jobLogFile), j.observeLine)
+	if err != nil {
+		return nil, err
+	}
+	j.log = jobLog
+	j.reports = a.reporter.forJob(req.CallbackURL)
+	j.ctx, j.cancel = context.WithTimeout(context.Background(), time.Duration(req.Timeout)*time.Second)
+	return j, nil
+}
+
+// run은 설치와 학습을 차례로 실행하고 결과를 알립니다 (제한 시간은 두 단계 전체에 적용)
+func (a *Agent) run(j *job, superseded []*job) {
+	ctx := j.ctx
+	defer func() {
+		j.cancel()
+		j.log.Finish()
+		close(j.done)
+		j.reports.Close() // 남은 상태 알림 전송 (재시도 포함)
+	}()
+
+	j.report(participantagent.StateAccepted, 0, "")
+
+	// 대체된 작업이 Flower 클라이언트를 정리할 때까지 대기
+	for _, other := range superseded {
+		select {
+		case <-other.done:
+		case <-ctx.Done():
+		}
+	}
+
+	spec := &RunSpec{
+		JobID:         j.request.JobID,
+		Dir:           j.dir,
+		ServerAddress: j.request.ServerAddress,
+		LocalEpochs:   j.request.LocalEpochs,
+		Dependencies:  j.request.Dependencies,
+	}
+
+	var err error
+	if err = ctx.Err(); err == nil {
+		j.report(participantagent.StateInstalling, 0, "가상환경 준비 중")
+		j.log.Printf("가상환경 준비 (의존성 %d개)", len(spec.Dependencies))
+		err = a.runner.Install(ctx, spec, j.log)
+	}
+	if err == nil {
+		j.report(participantagent.StateTraining, 0, fmt.Sprintf("Flower 클라이언트 시작 (%s)", spec.ServerAddress))
+		j.log.Printf("Flower 클라이언트 시작: %s", spec.ServerAddress)
+		err = a.runner.Train(ctx, spec, j.log)
+	}
+
+	state, message := j.outcome(ctx, err)
+	_, round := j.snapshot()
+	j.log.Printf("작업 종료: %s %s", state, message)
+	log.Printf("작업 종료: %s (%s) %s", j.request.JobID, state, message)
+	j.report(state, round, message)
+}
+
+// outcome은 실행 결과로 최종 상태를 정합니다 (중지 요청 > 제한 시간 > 실행 오류)
+func (j *job) outcome(ctx context.Context, err error) (string, string) {
+	j.mu.Lock()
+	stopReason := j.stopReason
+	j.mu.Unlock()
+
+	switch {
+	case stopReason != "":
+		return participantagent.StateStopped, stopReason
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return participantagent.StateFailed, fmt.Sprintf("제한 시간(%d초)을 초과했습니다", j.request.Timeout)
+	case err != nil:
+		return participantagent.StateFailed, err.Error()
+	default:
+		return participantagent.StateFinished, "학습이 완료되었습니다"
+	}
+}
+
+// pruneLocked는 보관 개수를 넘는 오래된 끝난 작업을 정리합니다 (a.mu를 잡은 상태에서 호출)
+func (a *Agent) pruneLocked() {
+	finished := 0
+	for _, id := range a.order {
+		if a.jobs[id].finished() {
+			finished++
+		}
+	}
+	kept := a.order[:0]
+	for _, id := range a.order {
+		j := a.jobs[id]
+		if finished > maxFinishedJobs && j.finished() {
+			finished--
+			j.log.Close()
+			os.RemoveAll(j.dir)
+			delete(a.jobs, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	a.order = kept
+}
+
+// handleStop은 job_id 또는 연합학습의 모든 작업을 중지합니다
+func (a *Agent) handleStop(w http.ResponseWriter, r *http.Request) {
+	body, ok := a.readAuthenticated(w, r, maxStopBodyBytes)
+	if !ok {
+		return
+	}
+	var req participantagent.StopRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "잘못된 요청 형식입니다")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var targets []*job
+	a.mu.Lock()
+	for id, j := range a.jobs {
+		if j.request.FederatedLearningID == req.FederatedLearningID && (req.JobID == "" || req.JobID == id) {
+			targets = append(targets, j)
+		}
+	}
+	a.mu.Unlock()
+	if len(targets) == 0 {
+		writeError(w, http.StatusNotFound, "작업을 찾을 수 없습니다")
+		return
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "백엔드 중지 요청"
+	}
+	deadline := time.After(stopResponseWait)
+	for _, j := range targets {
+		j.stop(reason)
+	}
+	for _, j := range targets {
+		select {
+		case <-j.done:
+		case <-deadline:
+		}
+	}
+
+	jobID := req.JobID
+	state, _ := targets[0].snapshot()
+	if jobID == "" {
+		jobID = targets[0].request.JobID
+	}
+	writeJSON(w, http.StatusOK, participantagent.JobResponse{
+		ProtocolVersion: participantagent.ProtocolVersion,
+		JobID:           jobID,
+		State:           state,
+	})
+}
+
+// handleLogs는 작업 로그를 offset부터 보내고, follow면 작업이 끝날 때까지 스트리밍합니다
+func (a *Agent) handleLogs(w http.ResponseWriter, r *http.Request) {
+	if !a.authenticate(w, r, []byte(r.URL.RequestURI())) {
+		return
+	}
+	query := r.URL.Query()
+	a.mu.Lock()
+	j, ok := a.jobs[query.Get("job_id")]
+	a.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "작업을 찾을 수 없습니다")
+		return
+	}
+
+	offset, _ := strconv.ParseInt(query.Get("offset"), 10, 64)
+	if size := j.log.Size(); offset < 0 || offset > size {
+		offset = size
+	}
+	follow, _ := strconv.ParseBool(query.Get("follow"))
+
+	state, _ := j.snapshot()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set(participantagent.ProtocolHeader, participantagent.ProtocolVersion)
+	w.Header().Set(participantagent.LogOffsetHeader, strconv.FormatInt(offset, 10))
+	w.Header().Set(participantagent.JobStateHeader, state)
+	w.WriteHeader(http.StatusOK)
+
+	flush := func() {}
+	if flusher, ok := w.(http.Flusher); ok {
+		flush = flusher.Flush
+	}
+	if err := j.log.Stream(r.Context(), w, offset, follow, flush); err != nil {
+		log.Printf("작업 %s 로그 전송 중단: %v", j.request.JobID, err)
+	}
+}
+
+// handleLatencyProbe는 이 VM에서 target까지 TCP 연결 시간을 count회 측정합니다
+// 연결에 실패하면 그때까지의 측정값과 함께 오류를 돌려줍니다
+func (a *Agent) handleLatencyProbe(w http.ResponseWriter, r *http.Request) {
+	body, ok := a.readAuthenticated(w, r, maxProbeBodyBytes)
+	if !ok {
+		return
+	}
+	var req participantagent.LatencyProbeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "잘못된 요청 형식입니다")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := participantagent.LatencyProbeResponse{
+		ProtocolVersion: participantagent.ProtocolVersion,
+		SamplesMs:       make([]float64, 0, req.Count),
+	}
+	dialer := &net.Dialer{Timeout: probeDialTimeout}
+	for i := 0; i < req.Count; i++ {
+		start := time.Now()
+		conn, err := dialer.DialContext(r.Context(), "tcp", req.Target)
+		if err != nil {
+			response.Error = fmt.Sprintf("TCP 연결 실패 (%s): %v", req.Target, err)
+			break
+		}
+		response.SamplesMs = append(response.SamplesMs, float64(time.Since(start))/float64(time.Millisecond))
+		conn.Close()
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleHealth는 에이전트 상태를 반환합니다 (인증 불필요)
+func (a *Agent) handleHealth(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	running := 0
+	for _, j := range a.jobs {
+		if !j.finished() {
+			running++
+		}
+	}
+	a.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"protocol_version": participantagent.ProtocolVersion,
+		"participant_id":   a.participantID,
+		"running_jobs":     running,
+	})
+}
+
+// Shutdown은 실행 중인 작업을 모두 중지하고 종료를 기다립니다
+func (a *Agent) Shutdown(ctx context.Context) {
+	a.mu.Lock()
+	jobs := make([]*job, 0, len(a.jobs))
+	for _, j := range a.jobs {
+		jobs = append(jobs, j)
+	}
+	a.mu.Unlock()
+
+	for _, j := range jobs {
+		j.stop("에이전트 종료")
+	}
+	for _, j := range jobs {
+		select {
+		case <-j.done:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// readAuthenticated는 본문을 읽고 서명 토큰을 확인합니다
+func (a *Agent) readAuthenticated(w http.ResponseWriter, r *http.Request, limit int64) ([]byte, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "요청 본문을 읽을 수 없습니다")
+		return nil, false
+	}
+	if int64(len(body)) > limit {
+		writeError(w, http.StatusRequestEntityTooLarge, "요청 본문이 너무 큽니다")
+		return nil, false
+	}
+	if !a.authenticate(w, r, body) {
+		return nil, false
+	}
+	return body, true
+}
+
+// authenticate는 payload(본문 또는 GET의 경로+쿼리)에 대한 백엔드 서명을 확인합니다
+func (a *Agent) authenticate(w http.ResponseWriter, r *http.Request, payload []byte) bool {
+	if version := r.Header.Get(participantagent.ProtocolHeader); version != "" && version != participantagent.ProtocolVersion {
+		writeError(w, http.StatusBadRequest, "지원하지 않는 프로토콜 버전입니다: "+version)
+		return false
+	}
+	token := participantagent.BearerToken(r.Header.Get("Authorization"))
+	if err := participantagent.VerifyRequest(a.secret, token, a.participantID, participantagent.AudienceAgent, payload); err != nil {
+		log.Printf("요청 인증 실패 (%s %s): %v", r.Method, r.URL.Path, err)
+		writeError(w, http.StatusUnauthorized, "인증에 실패했습니다")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(participantagent.ProtocolHeader, participantagent.ProtocolVersion)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, participantagent.ErrorResponse{
+		ProtocolVersion: participantagent.ProtocolVersion,
+		Error:           message,
+	})
+}
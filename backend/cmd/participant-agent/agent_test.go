@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+const testParticipantID = "participant-1"
+
+// fakeBackend는 에이전트의 상태 알림을 서명 확인 후 기록합니다
+type fakeBackend struct {
+	t      *testing.T
+	secret string
+	mu     sync.Mutex
+	calls  []participantagent.StatusCallback
+}
+
+func (b *fakeBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	token := participantagent.BearerToken(r.Header.Get("Authorization"))
+	if err := participantagent.VerifyRequest(b.secret, token, testParticipantID, participantagent.AudienceBackend, body); err != nil {
+		b.t.Errorf("callback signature: %v", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var callback participantagent.StatusCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		b.t.Errorf("callback body: %v", err)
+	}
+	if err := callback.Validate(); err != nil {
+		b.t.Errorf("callback validation: %v", err)
+	}
+	b.mu.Lock()
+	b.calls = append(b.calls, callback)
+	b.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (b *fakeBackend) callbacks() []participantagent.StatusCallback {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]participantagent.StatusCallback{}, b.calls...)
+}
+
+// waitForTerminal은 작업의 마지막 상태 알림을 기다립니다
+func (b *fakeBackend) waitForTerminal(jobID string) participantagent.StatusCallback {
+	b.t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, callback := range b.callbacks() {
+			if callback.JobID == jobID && participantagent.IsTerminalState(callback.State) {
+				return callback
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	b.t.Fatalf("no terminal callback for %s", jobID)
+	return participantagent.StatusCallback{}
+}
+
+type testEnv struct {
+	t       *testing.T
+	secret  string
+	agent   *httptest.Server
+	backend *fakeBackend
+	callURL string
+}
+
+func newTestEnv(t *testing.T, runner Runner) *testEnv {
+	t.Helper()
+	secret, err := participantagent.NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &fakeBackend{t: t, secret: secret}
+	backendServer := httptest.NewServer(backend)
+	t.Cleanup(backendServer.Close)
+
+	agent := NewAgent(testParticipantID, secret, t.TempDir(), runner, &http.Client{Timeout: 5 * time.Second})
+	agentServer := httptest.NewServer(agent.Handler())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		agent.Shutdown(ctx)
+		agentServer.Close()
+	})
+
+	return &testEnv{
+		t:       t,
+		secret:  secret,
+		agent:   agentServer,
+		backend: backend,
+		callURL: participantagent.CallbackURL(backendServer.URL, testParticipantID),
+	}
+}
+
+func (e *testEnv) executeRequest(flID string, attempt, timeout int) *participantagent.ExecuteRequest {
+	return &participantagent.ExecuteRequest{
+		ProtocolVersion:     participantagent.ProtocolVersion,
+		JobID:               participantagent.JobID(flID, attempt, testParticipantID),
+		FederatedLearningID: flID,
+		ParticipantID:       testParticipantID,
+		Attempt:             attempt,
+		ServerAddress:       "aggregator:9092",
+		LocalEpochs:         1,
+		Timeout:             timeout,
+		Files:               map[string]string{"client_app.py": "print('hi')"},
+		CallbackURL:         e.callURL,
+	}
+}
+
+func (e *testEnv) post(path string, payload interface{}) (int, participantagent.JobResponse) {
+	e.t.Helper()
+	status, body, err := participantagent.PostSigned(context.Background(), http.DefaultClient, e.agent.URL+path, e.secret, testParticipantID, participantagent.AudienceAgent, payload)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	var response participantagent.JobResponse
+	json.Unmarshal(body, &response)
+	return status, response
+}
+
+func TestExecuteReportsRoundsAndFinishes(t *testing.T) {
+	env := newTestEnv(t, &StubRunner{Rounds: 3, RoundDelay: 10 * time.Millisecond})
+	req := env.executeRequest("fl-1", 0, 60)
+
+	status, response := env.post(participantagent.ExecutePath, req)
+	if status != http.StatusAccepted || response.JobID != req.JobID || response.Duplicate {
+		t.Fatalf("execute: status %d, response %+v", status, response)
+	}
+
+	final := env.backend.waitForTerminal(req.JobID)
+	if final.State != participantagent.StateFinished || final.Round != 3 {
+		t.Fatalf("final callback: %+v", final)
+	}
+
+	var got []string
+	for i, callback := range env.backend.callbacks() {
+		if callback.Sequence != int64(i+1) {
+			t.Fatalf("callback %d has sequence %d", i, callback.Sequence)
+		}
+		got = append(got, callback.State)
+	}
+	want := []string{"accepted", "installing", "training", "training", "training", "training", "finished"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("states = %v, want %v", got, want)
+	}
+
+	// 같은 job_id 재전송은 다시 실행하지 않음
+	status, response = env.post(participantagent.ExecutePath, req)
+	if status != http.StatusOK || !response.Duplicate || response.State != participantagent.StateFinished {
+		t.Fatalf("duplicate execute: status %d, response %+v", status, response)
+	}
+
+	// 로그에 라운드 출력이 남음
+	resp, err := participantagent.GetSigned(context.Background(), http.DefaultClient,
+		env.agent.URL+participantagent.LogsPath+"?job_id="+req.JobID, env.secret, testParticipantID, participantagent.AudienceAgent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	logs, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(logs), "[Client] Round 3 started") {
+		t.Fatalf("logs: status %d, body %q", resp.StatusCode, logs)
+	}
+}
+
+func TestExecuteRejectsUnauthenticatedRequests(t *testing.T) {
+	env := newTestEnv(t, &StubRunner{})
+	req := env.executeRequest("fl-1", 0, 60)
+
+	otherSecret, _ := participantagent.NewSecret()
+	status, _, err := participantagent.PostSigned(context.Background(), http.DefaultClient, env.agent.URL+participantagent.ExecutePath, otherSecret, testParticipantID, participantagent.AudienceAgent, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusUnauthorized {
+		t.Fatalf("wrong secret: status %d", status)
+	}
+
+	// 백엔드 방향 토큰은 에이전트 요청에 쓸 수 없음
+	status, _, err = participantagent.PostSigned(context.Background(), http.DefaultClient, env.agent.URL+participantagent.ExecutePath, env.secret, testParticipantID, participantagent.AudienceBackend, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusUnauthorized {
+		t.Fatalf("wrong audience: status %d", status)
+	}
+}
+
+func TestExecuteEnforcesTimeout(t *testing.T) {
+	env := newTestEnv(t, &StubRunner{Rounds: 100, RoundDelay: 50 * time.Millisecond})
+	req := env.executeRequest("fl-1", 0, 1)
+
+	if status, _ := env.post(participantagent.ExecutePath, req); status != http.StatusAccepted {
+		t.Fatalf("execute: status %d", status)
+	}
+	final := env.backend.waitForTerminal(req.JobID)
+	if final.State != participantagent.StateFailed || !strings.Contains(final.Message, "제한 시간") {
+		t.Fatalf("final callback: %+v", final)
+	}
+}
+
+func TestStopAndSupersede(t *testing.T) {
+	env := newTestEnv(t, &StubRunner{Rounds: 100, RoundDelay: 50 * time.Millisecond})
+
+	// 새 회차 요청은 같은 연합학습의 이전 작업을 중지
+	first := env.executeRequest("fl-1", 0, 60)
+	env.post(participantagent.ExecutePath, first)
+	second := env.executeRequest("fl-1", 1, 60)
+	if status, _ := env.post(participantagent.ExecutePath, second); status != http.StatusAccepted {
+		t.Fatalf("execute second attempt: status %d", status)
+	}
+	if final := env.backend.waitForTerminal(first.JobID); final.State != participantagent.StateStopped {
+		t.Fatalf("superseded job: %+v", final)
+	}
+
+	status, response := env.post(participantagent.StopPath, &participantagent.StopRequest{
+		ProtocolVersion:     participantagent.ProtocolVersion,
+		FederatedLearningID: "fl-1",
+		Reason:              "user stop",
+	})
+	if status != http.StatusOK {
+		t.Fatalf("stop: status %d, response %+v", status, response)
+	}
+	final := env.backend.waitForTerminal(second.JobID)
+	if final.State != participantagent.StateStopped || final.Message != "user stop" {
+		t.Fatalf("stopped job: %+v", final)
+	}
+
+	status, _ = env.post(participantagent.StopPath, &participantagent.StopRequest{
+		ProtocolVersion:     participantagent.ProtocolVersion,
+		FederatedLearningID: "unknown",
+	})
+	if status != http.StatusNotFound {
+		t.Fatalf("stop unknown job: status %d", status)
+	}
+}
+
+func TestLatencyProbeMeasuresTarget(t *testing.T) {
+	env := newTestEnv(t, &StubRunner{})
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	t.Cleanup(func() { target.Close() })
+
+	probe := func(secret string, req participantagent.LatencyProbeRequest) (int, participantagent.LatencyProbeResponse) {
+		t.Helper()
+		status, body, err := participantagent.PostSigned(context.Background(), http.DefaultClient, env.agent.URL+participantagent.LatencyProbePath, secret, testParticipantID, participantagent.AudienceAgent, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var response participantagent.LatencyProbeResponse
+		json.Unmarshal(body, &response)
+		return status, response
+	}
+	req := participantagent.LatencyProbeRequest{ProtocolVersion: participantagent.ProtocolVersion, Target: target.Addr().String(), Count: 3}
+
+	status, response := probe(env.secret, req)
+	if status != http.StatusOK || response.Error != "" || len(response.SamplesMs) != 3 {
+		t.Fatalf("probe: status %d, response %+v", status, response)
+	}
+
+	otherSecret, _ := participantagent.NewSecret()
+	if status, _ := probe(otherSecret, req); status != http.StatusUnauthorized {
+		t.Fatalf("wrong secret: status %d", status)
+	}
+
+	if status, _ := probe(env.secret, participantagent.LatencyProbeRequest{ProtocolVersion: participantagent.ProtocolVersion, Target: target.Addr().String(), Count: 100}); status != http.StatusBadRequest {
+		t.Fatalf("count over limit: status %d", status)
+	}
+
+	// 닫힌 포트는 측정 실패로 응답
+	closed := target.Addr().String()
+	target.Close()
+	status, response = probe(env.secret, participantagent.LatencyProbeRequest{ProtocolVersion: participantagent.ProtocolVersion, Target: closed, Count: 2})
+	if status != http.StatusOK || response.Error == "" || len(response.SamplesMs) != 0 {
+		t.Fatalf("closed target: status %d, response %+v", status, response)
+	}
+}
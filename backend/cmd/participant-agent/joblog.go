@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// jobLog는 작업 로그 파일에 출력을 기록하고, 이어 받기(offset)와 실시간 스트리밍을 제공합니다
+// 완성된 줄마다 onLine을 호출합니다 (라운드 진행 감지용)
+type jobLog struct {
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	partial []byte
+	changed chan struct{} // 기록할 때마다 닫고 새로 만듦 (스트리밍 대기 해제)
+	done    bool
+	onLine  func(line string)
+}
+
+func newJobLog(path string, onLine func(line string)) (*jobLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &jobLog{file: file, changed: make(chan struct{}), onLine: onLine}, nil
+}
+
+// Write는 출력을 로그 파일에 추가합니다 (표준 출력/에러를 함께 받음)
+func (l *jobLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	n, err := l.file.WriteAt(p, l.size)
+	l.size += int64(n)
+	close(l.changed)
+	l.changed = make(chan struct{})
+
+	var lines []string
+	l.partial = append(l.partial, p[:n]...)
+	for {
+		i := bytes.IndexByte(l.partial, '\n')
+		if i < 0 {
+			break
+		}
+		lines = append(lines, string(bytes.TrimRight(l.partial[:i], "\r")))
+		l.partial = l.partial[i+1:]
+	}
+	l.mu.Unlock()
+
+	if l.onLine != nil {
+		for _, line := range lines {
+			l.onLine(line)
+		}
+	}
+	return n, err
+}
+
+// Printf는 에이전트 메시지를 로그에 남깁니다
+func (l *jobLog) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(l, "[agent] "+format+"\n", args...)
+}
+
+// Finish는 더 이상 기록하지 않음을 표시해 스트리밍을 끝냅니다
+func (l *jobLog) Finish() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.done {
+		l.done = true
+		close(l.changed)
+	}
+}
+
+// Close는 로그 파일을 닫습니다 (작업 정리 시)
+func (l *jobLog) Close() error {
+	l.Finish()
+	return l.file.Close()
+}
+
+// Size는 현재까지 기록된 로그 크기입니다
+func (l *jobLog) Size() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.size
+}
+
+// Stream은 offset부터의 로그를 w로 보냅니다
+// follow면 작업이 끝나거나 ctx가 끝날 때까지 새로 기록되는 로그를 계속 보냅니다
+func (l *jobLog) Stream(ctx context.Context, w io.Writer, offset int64, follow bool, flush func()) error {
+	for {
+		l.mu.Lock()
+		size, changed, done := l.size, l.changed, l.done
+		l.mu.Unlock()
+
+		if offset < size {
+			n, err := io.Copy(w, io.NewSectionReader(l.file, offset, size-offset))
+			offset += n
+			if err != nil {
+				return err
+			}
+			if flush != nil {
+				flush()
+			}
+		}
+		if !follow || (done && offset >= size) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
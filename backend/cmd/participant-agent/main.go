@@ -0,0 +1,162 @@
+// participant-agent는 참여자 VM에서 백엔드의 연합학습 실행 요청을 받아 Flower 클라이언트를 실행하는 에이전트입니다
+//
+//	participant-agent -participant-id <ID> -secret-file agent.secret [-listen :5000] [-work-dir ./fleecy-agent]
+//	                  [-runner python|stub] [-python python3] [-tls-cert cert.pem -tls-key key.pem [-client-ca ca.pem]]
+//
+// 비밀 키는 백엔드의 POST /api/participants/:id/agent-credentials로 발급받으며,
+// -secret-file 대신 PARTICIPANT_AGENT_SECRET 환경 변수로도 전달할 수 있습니다
+//
+// 작업마다 상태(accepted, installing, training 라운드 N, finished/failed/stopped)를 요청의 callback_url로 알리고,
+// 학습 로그는 GET /api/fl/logs로 제공합니다 (백엔드가 프록시)
+// 백엔드의 지연시간 측정기는 POST /api/latency/probe로 이 VM에서 후보 집계자 리전까지의 TCP 연결 시간을 측정합니다
+// 백엔드로 보내는 요청의 TLS 설정은 PARTICIPANT_AGENT_CA_FILE, PARTICIPANT_AGENT_CLIENT_CERT_FILE, PARTICIPANT_AGENT_CLIENT_KEY_FILE을 사용합니다
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+// defaultBasePackages는 client_app.py 실행에 필요한 기본 패키지입니다 (handlers/templates/pyproject.toml과 맞춤)
+const defaultBasePackages = "flwr[simulation]>=1.20.0,flwr-datasets[vision]>=0.5.0,torch==2.7.1,torchvision==0.22.1"
+
+const (
+	callbackTimeout = 10 * time.Second
+	shutdownTimeout = 30 * time.Second
+)
+
+func main() {
+	listen := flag.String("listen", ":5000", "수신 주소")
+	participantID := flag.String("participant-id", os.Getenv("PARTICIPANT_ID"), "백엔드에 등록된 참여자 ID")
+	secretFile := flag.String("secret-file", os.Getenv("PARTICIPANT_AGENT_SECRET_FILE"), "참여자 비밀 키 파일")
+	workDir := flag.String("work-dir", "./fleecy-agent", "작업 파일, 로그, 가상환경 디렉토리")
+	runnerName := flag.String("runner", "python", "실행기: python | stub (Python 없이 프로토콜 확인)")
+	python := flag.String("python", "python3", "가상환경을 만들 Python 실행 파일")
+	basePackages := flag.String("base-packages", defaultBasePackages, "모든 작업에 설치할 기본 패키지 (쉼표 구분)")
+	stubRounds := flag.Int("stub-rounds", 3, "stub 실행기의 라운드 수")
+	stubRoundDelay := flag.Duration("stub-round-delay", 2*time.Second, "stub 실행기의 라운드 간격")
+	tlsCert := flag.String("tls-cert", "", "https 서버 인증서 (비우면 http)")
+	tlsKey := flag.String("tls-key", "", "https 서버 개인 키")
+	clientCA := flag.String("client-ca", "", "지정하면 이 CA가 서명한 클라이언트 인증서를 요구 (mTLS)")
+	flag.Parse()
+
+	if *participantID == "" {
+		log.Fatal("-participant-id가 필요합니다")
+	}
+	secret, err := loadSecret(*secretFile)
+	if err != nil {
+		log.Fatalf("비밀 키 로드 실패: %v", err)
+	}
+
+	var runner Runner
+	switch *runnerName {
+	case "python":
+		runner = NewPythonRunner(*python, filepath.Join(*workDir, "venvs"), splitList(*basePackages))
+	case "stub":
+		runner = &StubRunner{Rounds: *stubRounds, RoundDelay: *stubRoundDelay}
+	default:
+		log.Fatalf("알 수 없는 실행기입니다: %s", *runnerName)
+	}
+
+	callbackClient, err := participantagent.NewHTTPClient(callbackTimeout)
+	if err != nil {
+		log.Fatalf("백엔드 TLS 설정 실패: %v", err)
+	}
+
+	agent := NewAgent(*participantID, secret, *workDir, runner, callbackClient)
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           agent.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if *clientCA != "" {
+		tlsConfig, err := clientAuthTLSConfig(*clientCA)
+		if err != nil {
+			log.Fatalf("클라이언트 CA 로드 실패: %v", err)
+		}
+		server.TLSConfig = tlsConfig
+	}
+
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+		<-stop
+		log.Printf("종료 신호 수신, 실행 중인 작업을 중지합니다")
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		agent.Shutdown(ctx)
+		server.Shutdown(ctx)
+	}()
+
+	log.Printf("참여자 에이전트 시작: %s (참여자 %s, 실행기 %s, 프로토콜 %s)", *listen, *participantID, *runnerName, participantagent.ProtocolVersion)
+	if *tlsCert != "" {
+		err = server.ListenAndServeTLS(*tlsCert, *tlsKey)
+	} else {
+		if *clientCA != "" {
+			log.Fatal("-client-ca는 -tls-cert, -tls-key와 함께 사용해야 합니다")
+		}
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("서버 실행 실패: %v", err)
+	}
+}
+
+// loadSecret은 비밀 키 파일 또는 PARTICIPANT_AGENT_SECRET 환경 변수에서 비밀 키를 읽습니다
+func loadSecret(path string) (string, error) {
+	if path == "" {
+		if secret := strings.TrimSpace(os.Getenv("PARTICIPANT_AGENT_SECRET")); secret != "" {
+			return secret, nil
+		}
+		return "", errors.New("-secret-file 또는 PARTICIPANT_AGENT_SECRET이 필요합니다")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", errors.New("비밀 키 파일이 비어 있습니다")
+	}
+	return secret, nil
+}
+
+// clientAuthTLSConfig는 지정한 CA가 서명한 클라이언트 인증서를 요구하는 TLS 설정입니다
+func clientAuthTLSConfig(caFile string) (*tls.Config, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("CA 파일에 인증서가 없습니다")
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientCAs:  pool,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	}, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+// 상태 알림 재시도 설정
+const (
+	callbackAttempts    = 5
+	callbackBackoff     = time.Second
+	callbackMaxBackoff  = 30 * time.Second
+	callbackQueueLength = 256
+)
+
+// reporter는 작업별 상태 알림을 순서대로 백엔드에 보냅니다
+// 실패하면 재시도하고, 백엔드가 이미 지난 작업이라고 응답하면(409) 더 보내지 않습니다
+type reporter struct {
+	client        *http.Client
+	secret        string
+	participantID string
+	backoff       time.Duration
+}
+
+// jobReporter는 한 작업의 알림 큐입니다 (순번은 큐에 넣을 때 부여)
+type jobReporter struct {
+	reporter *reporter
+	url      string
+	queue    chan *participantagent.StatusCallback
+	done     chan struct{}
+	stale    bool
+}
+
+func (r *reporter) forJob(url string) *jobReporter {
+	jr := &jobReporter{
+		reporter: r,
+		url:      url,
+		queue:    make(chan *participantagent.StatusCallback, callbackQueueLength),
+		done:     make(chan struct{}),
+	}
+	go jr.loop()
+	return jr
+}
+
+// enqueue는 알림을 큐에 넣습니다 (Close 이후에는 호출하지 않아야 함)
+func (jr *jobReporter) enqueue(callback *participantagent.StatusCallback) {
+	jr.queue <- callback
+}
+
+// Close는 큐를 닫고 남은 알림을 모두 보낼 때까지 기다립니다
+func (jr *jobReporter) Close() {
+	close(jr.queue)
+	<-jr.done
+}
+
+func (jr *jobReporter) loop() {
+	defer close(jr.done)
+	for callback := range jr.queue {
+		if jr.stale {
+			continue
+		}
+		jr.send(callback)
+	}
+}
+
+func (jr *jobReporter) send(callback *participantagent.StatusCallback) {
+	r := jr.reporter
+	backoff := r.backoff
+	for attempt := 1; attempt <= callbackAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), r.client.Timeout+time.Second)
+		status, body, err := participantagent.PostSigned(ctx, r.client, jr.url, r.secret, r.participantID, participantagent.AudienceBackend, callback)
+		cancel()
+
+		switch {
+		case err != nil:
+			log.Printf("상태 알림 전송 실패 (%s #%d, %d/%d): %v", callback.JobID, callback.Sequence, attempt, callbackAttempts, err)
+		case status == http.StatusConflict:
+			// 재시작 등으로 백엔드가 더 이상 이 작업을 추적하지 않음
+			log.Printf("백엔드가 작업 %s의 알림을 거부했습니다 (이후 알림 생략): %s", callback.JobID, string(body))
+			jr.stale = true
+			return
+		case status >= 200 && status < 300:
+			return
+		case status >= 400 && status < 500 && status != http.StatusTooManyRequests:
+			// 인증/형식 오류는 재시도해도 같은 결과
+			log.Printf("상태 알림 거부 (%s #%d): 상태 코드 %d, 응답: %s", callback.JobID, callback.Sequence, status, string(body))
+			return
+		default:
+			log.Printf("상태 알림 실패 (%s #%d, %d/%d): 상태 코드 %d", callback.JobID, callback.Sequence, attempt, callbackAttempts, status)
+		}
+
+		if attempt < callbackAttempts {
+			time.Sleep(backoff)
+			backoff = min(backoff*2, callbackMaxBackoff)
+		}
+	}
+}
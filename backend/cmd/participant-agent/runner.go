@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RunSpec은 러너가 실행할 작업 정보입니다
+type RunSpec struct {
+	JobID         string
+	Dir           string // 요청 파일이 저장된 작업 디렉토리
+	ServerAddress string
+	LocalEpochs   int
+	Dependencies  []string
+}
+
+// Runner는 작업 환경 준비와 Flower 클라이언트 실행을 담당합니다
+// 두 단계 모두 ctx가 끝나면(중지/타임아웃) 즉시 반환해야 합니다
+type Runner interface {
+	Install(ctx context.Context, spec *RunSpec, log io.Writer) error
+	Train(ctx context.Context, spec *RunSpec, log io.Writer) error
+}
+
+// roundMarker는 Flower 클라이언트가 라운드 시작 시 출력하는 줄의 형식입니다 (client_app.py와 공유)
+const roundMarker = "[Client] Round %d started"
+
+// 프로세스 종료 시 SIGTERM 이후 강제 종료까지 기다리는 시간
+const processStopGrace = 10 * time.Second
+
+// venvReadyFile은 의존성 설치가 끝난 가상환경에 만드는 표시 파일입니다
+const venvReadyFile = ".fleecy-ready"
+
+// PythonRunner는 의존성 조합별 가상환경에서 client_app.py를 실행합니다
+// 같은 의존성을 쓰는 작업은 가상환경을 재사용하고, 다른 번들과는 분리됩니다
+type PythonRunner struct {
+	Python       string   // 가상환경을 만들 Python 실행 파일
+	VenvRoot     string   // 가상환경 상위 디렉토리
+	BasePackages []string // 모든 작업에 설치할 기본 패키지 (flwr, torch 등)
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewPythonRunner는 새 PythonRunner 인스턴스를 생성합니다
+func NewPythonRunner(python, venvRoot string, basePackages []string) *PythonRunner {
+	return &PythonRunner{
+		Python:       python,
+		VenvRoot:     venvRoot,
+		BasePackages: basePackages,
+		locks:        make(map[string]*sync.Mutex),
+	}
+}
+
+// packages는 설치할 패키지 목록 (기본 + 번들 의존성)을 반환합니다
+func (r *PythonRunner) packages(spec *RunSpec) ([]string, error) {
+	packages := append(append([]string{}, r.BasePackages...), spec.Dependencies...)
+	for _, pkg := range packages {
+		// pip 옵션으로 해석되지 않도록 (예: --index-url)
+		if strings.HasPrefix(strings.TrimSpace(pkg), "-") {
+			return nil, fmt.Errorf("허용되지 않는 의존성입니다: %q", pkg)
+		}
+	}
+	return packages, nil
+}
+
+// venvDir은 패키지 조합으로 정해지는 가상환경 경로입니다
+func (r *PythonRunner) venvDir(packages []string) string {
+	sorted := append([]string{}, packages...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return filepath.Join(r.VenvRoot, hex.EncodeToString(sum[:8]))
+}
+
+func (r *PythonRunner) venvLock(dir string) *sync.Mutex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lock, ok := r.locks[dir]
+	if !ok {
+		lock = &sync.Mutex{}
+		r.locks[dir] = lock
+	}
+	return lock
+}
+
+// Install은 가상환경을 만들고 패키지를 설치합니다 (이미 준비된 가상환경은 재사용)
+func (r *PythonRunner) Install(ctx context.Context, spec *RunSpec, log io.Writer) error {
+	packages, err := r.packages(spec)
+	if err != nil {
+		return err
+	}
+	dir := r.venvDir(packages)
+
+	lock := r.venvLock(dir)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := os.Stat(filepath.Join(dir, venvReadyFile)); err == nil {
+		fmt.Fprintf(log, "가상환경 재사용: %s\n", dir)
+		return nil
+	}
+
+	// 설치가 중간에 끊긴 가상환경은 새로 생성
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("가상환경 정리 실패: %w", err)
+	}
+	if err := os.MkdirAll(r.VenvRoot, 0o755); err != nil {
+		return fmt.Errorf("가상환경 디렉토리 생성 실패: %w", err)
+	}
+
+	fmt.Fprintf(log, "가상환경 생성: %s\n", dir)
+	if err := r.run(ctx, spec.Dir, log, r.Python, "-m", "venv", dir); err != nil {
+		return fmt.Errorf("가상환경 생성 실패: %w", err)
+	}
+	if len(packages) > 0 {
+		fmt.Fprintf(log, "패키지 설치: %s\n", strings.Join(packages, " "))
+		args := append([]string{"install", "--disable-pip-version-check", "--no-input"}, packages...)
+		if err := r.run(ctx, spec.Dir, log, filepath.Join(dir, "bin", "pip"), args...); err != nil {
+			return fmt.Errorf("패키지 설치 실패: %w", err)
+		}
+	}
+	return os.WriteFile(filepath.Join(dir, venvReadyFile), []byte(time.Now().Format(time.RFC3339)), 0o644)
+}
+
+// Train은 가상환경의 Python으로 Flower 클라이언트를 실행합니다
+func (r *PythonRunner) Train(ctx context.Context, spec *RunSpec, log io.Writer) error {
+	packages, err := r.packages(spec)
+	if err != nil {
+		return err
+	}
+	python := filepath.Join(r.venvDir(packages), "bin", "python")
+	return r.run(ctx, spec.Dir, log, python, "-u", "client_app.py",
+		"--server-address", spec.ServerAddress,
+		"--local-epochs", strconv.Itoa(spec.LocalEpochs))
+}
+
+// run은 명령을 실행하고 표준 출력/에러를 log로 보냅니다
+// ctx가 끝나면 SIGTERM을 보내고 processStopGrace 뒤에도 살아 있으면 강제 종료합니다
+func (r *PythonRunner) run(ctx context.Context, dir string, log io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+	cmd.Stdout = log
+	cmd.Stderr = log
+	cmd.Cancel = func() error { return cmd.Process.Signal(syscall.SIGTERM) }
+	cmd.WaitDelay = processStopGrace
+	return cmd.Run()
+}
+
+// StubRunner는 Python 없이 프로토콜 흐름을 확인하기 위한 러너입니다
+// 설치를 건너뛰고 Rounds번 라운드 시작 줄을 RoundDelay 간격으로 출력합니다
+type StubRunner struct {
+	Rounds     int
+	RoundDelay time.Duration
+	InstallErr error // 설정하면 설치 단계에서 실패
+	TrainErr   error // 설정하면 모든 라운드 후 실패
+}
+
+// Install은 설치를 흉내 냅니다
+func (r *StubRunner) Install(ctx context.Context, spec *RunSpec, log io.Writer) error {
+	fmt.Fprintf(log, "stub: 의존성 %d개 설치 생략\n", len(spec.Dependencies))
+	return r.InstallErr
+}
+
+// Train은 라운드 진행을 흉내 냅니다
+func (r *StubRunner) Train(ctx context.Context, spec *RunSpec, log io.Writer) error {
+	fmt.Fprintf(log, "stub: %s에 연결 (local epochs %d)\n", spec.ServerAddress, spec.LocalEpochs)
+	for round := 1; round <= r.Rounds; round++ {
+		fmt.Fprintf(log, roundMarker+"\n", round)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.RoundDelay):
+		}
+	}
+	return r.TrainErr
+}
@@ -0,0 +1,164 @@
+// price-catalog는 클라우드 가격표 스냅샷을 관리하는 관리자용 CLI입니다
+//
+//	price-catalog import -file prices.csv [-format csv|json] [-effective 2025-01-01] [-note "..."]
+//	price-catalog list
+//	price-catalog diff -version 3 [-against 1]
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/Mungge/Fleecy-Cloud/config"
+	"github.com/Mungge/Fleecy-Cloud/initialization"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/pricing"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	_ = godotenv.Load()
+	if err := config.ConnectDatabase(); err != nil {
+		log.Fatalf("데이터베이스 연결 실패: %v", err)
+	}
+	if err := initialization.RunDatabaseMigration(); err != nil {
+		log.Fatalf("데이터베이스 마이그레이션 실패: %v", err)
+	}
+
+	db := config.GetDB()
+	catalog := pricing.NewCatalog(
+		repository.NewPriceSnapshotRepository(db),
+		repository.NewCloudPriceRepository(db),
+		repository.NewProviderRepository(db),
+		repository.NewRegionRepository(db),
+	)
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(catalog, os.Args[2:])
+	case "list":
+		err = runList(catalog)
+	case "diff":
+		err = runDiff(catalog, os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatalf("%s 실패: %v", os.Args[1], err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "사용법: price-catalog <import|list|diff> [옵션]")
+	fmt.Fprintln(os.Stderr, "  import -file <경로> [-format csv|json] [-effective RFC3339|YYYY-MM-DD] [-note 메모]")
+	fmt.Fprintln(os.Stderr, "  list")
+	fmt.Fprintln(os.Stderr, "  diff -version <버전> [-against <버전>]")
+	os.Exit(2)
+}
+
+func runImport(catalog *pricing.Catalog, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "가격표 파일 경로 (CSV 또는 JSON)")
+	format := fs.String("format", "", "가격표 형식 (비어있으면 확장자로 판단)")
+	effective := fs.String("effective", "", "적용 시점 (비어있으면 즉시)")
+	note := fs.String("note", "", "스냅샷 메모")
+	fs.Parse(args)
+
+	if *file == "" {
+		return errors.New("-file이 필요합니다")
+	}
+	if *format == "" {
+		*format = pricing.DetectFormat(*file)
+	}
+
+	effectiveAt, err := parseEffective(*effective)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := pricing.ParseSheet(f, *format)
+	if err != nil {
+		return err
+	}
+
+	result, err := catalog.Import(pricing.ImportRequest{
+		Rows:        rows,
+		Format:      *format,
+		Source:      filepath.Base(*file),
+		Note:        *note,
+		EffectiveAt: effectiveAt,
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
+
+func runList(catalog *pricing.Catalog) error {
+	snapshots, err := catalog.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		fmt.Printf("v%-4d  %s  %5d개  %-24s %s\n", s.Version, s.EffectiveAt.Format("2006-01-02 15:04"), s.ItemCount, s.Source, s.Note)
+	}
+	return nil
+}
+
+func runDiff(catalog *pricing.Catalog, args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	version := fs.Int("version", 0, "비교할 스냅샷 버전")
+	against := fs.Int("against", 0, "비교 기준 버전 (비어있으면 직전 버전)")
+	fs.Parse(args)
+
+	if *version <= 0 {
+		return errors.New("-version이 필요합니다")
+	}
+	var base *int
+	if *against > 0 {
+		base = against
+	}
+
+	diff, err := catalog.DiffVersions(*version, base)
+	if err != nil {
+		return err
+	}
+	return printJSON(diff)
+}
+
+func parseEffective(value string) (t time.Time, err error) {
+	if value == "" {
+		return t, nil
+	}
+	if t, err = time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err = time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return t, fmt.Errorf("적용 시점 형식 오류: %s", value)
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
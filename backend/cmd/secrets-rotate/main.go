@@ -0,0 +1,72 @@
+// secrets-rotate는 저장된 모든 비밀 값의 데이터 키를 현재 활성 마스터 키로 다시 감싸는 관리자용 CLI입니다
+//
+//	secrets-rotate [-batch 100] [-dry-run] [-new-key]
+//
+// 마스터 키 교체 절차:
+//  1. 새 마스터 키를 추가하고 활성 키로 지정 (env/file 제공자는 이전 키도 함께 유지)
+//  2. 실행 중인 서버에 새 키 설정을 배포 (file/local-kms 제공자는 모르는 키 ID를 만나면 파일을 다시 읽음)
+//  3. secrets-rotate 실행
+//  4. 모든 레코드가 새 키 ID를 가진 뒤 이전 마스터 키 제거
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	"github.com/Mungge/Fleecy-Cloud/config"
+	"github.com/Mungge/Fleecy-Cloud/initialization"
+	"github.com/Mungge/Fleecy-Cloud/services"
+	"github.com/Mungge/Fleecy-Cloud/utils/secrets"
+)
+
+func main() {
+	batch := flag.Int("batch", 100, "한 번에 처리할 행 수")
+	dryRun := flag.Bool("dry-run", false, "변경 없이 재암호화 대상 건수만 출력")
+	newKey := flag.Bool("new-key", false, "local-kms 제공자에서 새 마스터 키를 생성해 활성화한 뒤 재암호화")
+	flag.Parse()
+
+	_ = godotenv.Load()
+	envelope, err := secrets.InitFromEnv()
+	if err != nil {
+		log.Fatalf("비밀 값 암호화 초기화 실패: %v", err)
+	}
+
+	if *newKey {
+		kms, ok := envelope.Provider().(*secrets.LocalKMSProvider)
+		if !ok {
+			log.Fatalf("-new-key는 local-kms 제공자에서만 사용할 수 있습니다 (현재: %s)", envelope.Provider().Name())
+		}
+		keyID, err := kms.CreateKey()
+		if err != nil {
+			log.Fatalf("새 마스터 키 생성 실패: %v", err)
+		}
+		log.Printf("새 마스터 키 생성: %s", keyID)
+	}
+
+	if err := config.ConnectDatabase(); err != nil {
+		log.Fatalf("데이터베이스 연결 실패: %v", err)
+	}
+	if err := initialization.RunDatabaseMigration(); err != nil {
+		log.Fatalf("데이터베이스 마이그레이션 실패: %v", err)
+	}
+
+	rotator := services.NewSecretRotationService(config.GetDB(), envelope, *batch)
+	results, err := rotator.Rotate(*dryRun)
+	if err != nil {
+		log.Fatalf("재암호화 실패: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(results)
+
+	for _, result := range results {
+		if result.Failed > 0 {
+			os.Exit(1)
+		}
+	}
+}
@@ -1,16 +1,17 @@
 package aggregator
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/services/aggregator"
-	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
 	aggregatorvalidator "github.com/Mungge/Fleecy-Cloud/validators/aggregator"
 )
 
@@ -140,13 +141,12 @@ func NewAggregatorHandler(
 // @Failure 500 {object} map[string]string
 // @Router /api/aggregators [get]
 func (h *AggregatorHandler) GetAggregators(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
+	principal, ok := authz.AuthorizeActive(c, authz.ActionView)
+	if !ok {
 		return
 	}
 
-	aggregators, err := h.aggregatorService.GetAggregatorsByUser(userID)
+	aggregators, err := h.aggregatorService.GetAggregatorsByOrganization(principal.ActiveOrganizationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregator 목록 조회 실패"})
 		return
@@ -168,14 +168,8 @@ func (h *AggregatorHandler) GetAggregators(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/aggregators/{id} [get]
 func (h *AggregatorHandler) GetAggregator(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	id := c.Param("id")
-	aggregator, err := h.aggregatorService.GetAggregatorByID(id, userID)
+	aggregator, err := h.aggregatorService.GetAggregatorByID(id, authz.PrincipalFromContext(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregator 조회 실패"})
 		return
@@ -191,20 +185,20 @@ func (h *AggregatorHandler) GetAggregator(c *gin.Context) {
 
 // CreateAggregator godoc
 // @Summary 새 Aggregator 생성
-// @Description 새로운 Aggregator 인스턴스를 생성하고 Terraform으로 인프라를 배포합니다.
+// @Description 새로운 Aggregator를 등록하고 Terraform 배포 작업을 백그라운드에서 시작합니다.
 // @Tags aggregators
 // @Accept json
 // @Produce json
 // @Param aggregator body CreateAggregatorRequest true "Aggregator 생성 정보"
-// @Success 201 {object} CreateAggregatorResponse
+// @Success 202 {object} CreateAggregatorResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /api/aggregators [post]
 func (h *AggregatorHandler) CreateAggregator(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
+	principal, ok := authz.AuthorizeActive(c, authz.ActionManage)
+	if !ok {
 		return
 	}
 
@@ -229,81 +223,93 @@ func (h *AggregatorHandler) CreateAggregator(c *gin.Context) {
 
 	input := aggregator.CreateAggregatorInput{
 		Name:          request.Name,
-		Algorithm:     request.Algorithm,
+		Algorithm:     strings.ToLower(strings.TrimSpace(request.Algorithm)),
 		Region:        request.Region,
 		Storage:       request.Storage,
 		InstanceType:  request.InstanceType,
-		UserID:        userID,
-		CloudProvider: request.CloudProvider,
+		UserID:         principal.UserID,
+		OrganizationID: principal.ActiveOrganizationID,
+		CloudProvider:  request.CloudProvider,
 		ProjectName:   sanitizeGCPName(request.Name + "-project"),
 		Zone:          getZoneName(request.CloudProvider, request.Region),
 		EstimatedCost: request.EstimatedCost,
 	}
 
-	// 타임아웃 설정 (최대 10분)
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Minute)
-	defer cancel()
-
-	// Aggregator 생성 및 배포 (동기 처리)
-	result, err := h.aggregatorService.CreateAggregatorWithContext(ctx, input)
+	// Aggregator 생성 및 배포 작업 등록 (Terraform 배포는 백그라운드 워커에서 진행)
+	result, err := h.aggregatorService.CreateAggregatorWithContext(c.Request.Context(), input)
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			c.JSON(http.StatusRequestTimeout, gin.H{
-				"error": "Aggregator 배포가 타임아웃되었습니다. 나중에 상태를 확인해주세요.",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Aggregator 생성 및 배포 실패: " + err.Error(),
+		if errors.Is(err, aggregator.ErrDeploymentQueueFull) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "배포 대기열이 가득 찼습니다. 잠시 후 다시 시도해주세요.",
 			})
+			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Aggregator 생성 실패: " + err.Error(),
+		})
 		return
 	}
 
-	// 응답 반환 (배포 완료된 상태)
+	audit.Annotate(c, "aggregator.create", "aggregators", result.AggregatorID)
+	audit.AddDetail(c, "deployment_id", result.DeploymentID)
+
 	response := CreateAggregatorResponse{
 		AggregatorID:    result.AggregatorID,
-		Status:          result.Status,          // "running" 또는 "failed"
-		TerraformStatus: result.TerraformStatus, // "completed"
+		DeploymentID:    result.DeploymentID,
+		Status:          result.Status,          // "creating"
+		TerraformStatus: result.TerraformStatus, // "queued"
 	}
 
-	// 성공 시 201, 배포 완료 메시지와 함께 반환
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Aggregator가 성공적으로 생성되고 배포되었습니다",
+	// 배포 작업이 등록되었으므로 202 반환 (진행 상황은 /progress로 조회)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Aggregator 배포 작업이 등록되었습니다",
 		"data":    response,
 	})
 }
 
 // DeleteAggregator godoc
 // @Summary Aggregator 삭제
-// @Description Aggregator를 삭제합니다.
+// @Description terraform destroy 작업을 등록하고 202를 반환합니다. destroy는 백그라운드 워커에서 실행되며 진행 상황은 /progress/stream으로 전달되고, 완료되면 terminated 상태로 변경됩니다.
 // @Tags aggregators
 // @Accept json
 // @Produce json
 // @Param id path string true "Aggregator ID"
 // @Success 200 {object} map[string]string
+// @Success 202 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /api/aggregators/{id} [delete]
 func (h *AggregatorHandler) DeleteAggregator(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	id := c.Param("id")
 
-	if err := h.aggregatorService.DeleteAggregator(id, userID); err != nil {
-		if err == aggregator.ErrAggregatorNotFound {
+	// 요청 컨텍스트와 분리된 워커에서 destroy가 실행되므로 클라이언트 연결이 끊겨도 중단되지 않음
+	status, err := h.aggregatorService.DeleteAggregator(id, authz.PrincipalFromContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, aggregator.ErrAggregatorNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": "Aggregator를 찾을 수 없습니다"})
-			return
+		case errors.Is(err, aggregator.ErrAggregatorAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"error": "권한이 없습니다"})
+		case errors.Is(err, aggregator.ErrTerraformStateNotFound):
+			c.JSON(http.StatusConflict, gin.H{"error": "Terraform 상태 파일이 없어 인프라를 삭제할 수 없습니다"})
+		case errors.Is(err, aggregator.ErrDeploymentQueueFull):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "작업 대기열이 가득 찼습니다. 잠시 후 다시 시도해주세요."})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregator 삭제 실패: " + err.Error()})
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregator 삭제 실패"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Aggregator가 삭제되었습니다"})
+	if status == models.AggregatorStatusTerminated {
+		c.JSON(http.StatusOK, gin.H{"message": "Aggregator 인프라가 이미 삭제되었습니다", "status": status})
+		return
+	}
+
+	// 삭제 작업이 등록되었으므로 202 반환 (진행 상황은 /progress/stream으로 조회)
+	c.JSON(http.StatusAccepted, gin.H{"message": "Aggregator 삭제 작업이 등록되었습니다", "status": status})
 }
 
 // getStringValue 헬퍼 함수: *string을 string으로 변환
@@ -315,22 +321,16 @@ func getStringValue(s *string) string {
 }
 
 // WebSocketProgress godoc
-// @Summary 집계자 배포 진행 상황 WebSocket 연결
-// @Description 집계자 배포의 실시간 진행 상황을 WebSocket으로 전송합니다.
+// @Summary 집계자 배포 진행 상황 SSE 연결
+// @Description 집계자 배포의 실시간 진행 상황을 Server-Sent Events로 전송합니다. Last-Event-ID 이후의 이벤트를 재전송합니다.
 // @Tags aggregators
 // @Param id path string true "Aggregator ID"
-// @Router /api/aggregators/{id}/progress/ws [get]
+// @Router /api/aggregators/{id}/progress/stream [get]
 func (h *AggregatorHandler) WebSocketProgress(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	aggregatorID := c.Param("id")
 
 	// 권한 확인
-	aggregator, err := h.aggregatorService.GetAggregatorByID(aggregatorID, userID)
+	aggregator, err := h.aggregatorService.GetAggregatorByID(aggregatorID, authz.PrincipalFromContext(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregator 조회 실패"})
 		return
@@ -341,6 +341,6 @@ func (h *AggregatorHandler) WebSocketProgress(c *gin.Context) {
 		return
 	}
 
-	// WebSocket 연결 업그레이드
+	// SSE 스트림 연결 (연결 종료 시까지 블로킹)
 	h.aggregatorService.HandleWebSocketProgress(c.Writer, c.Request, aggregatorID)
 }
@@ -6,7 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/Mungge/Fleecy-Cloud/services/aggregator"
-	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
 	"github.com/Mungge/Fleecy-Cloud/validators/aggregator"
 )
 
@@ -25,12 +25,6 @@ import (
 // @Failure 500 {object} map[string]string
 // @Router /api/aggregators/{id}/status [put]
 func (h *AggregatorHandler) UpdateAggregatorStatus(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	id := c.Param("id")
 	
 	var request UpdateStatusRequest
@@ -45,11 +39,15 @@ func (h *AggregatorHandler) UpdateAggregatorStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.metricsService.UpdateStatus(id, userID, request.Status); err != nil {
+	if err := h.metricsService.UpdateStatus(id, authz.PrincipalFromContext(c), request.Status); err != nil {
 		if err == aggregator.ErrAggregatorNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Aggregator를 찾을 수 없습니다"})
 			return
 		}
+		if err == aggregator.ErrAggregatorAccessDenied {
+			c.JSON(http.StatusForbidden, gin.H{"error": "권한이 없습니다"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "상태 업데이트 실패"})
 		return
 	}
@@ -72,12 +70,6 @@ func (h *AggregatorHandler) UpdateAggregatorStatus(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/aggregators/{id}/metrics [put]
 func (h *AggregatorHandler) UpdateAggregatorMetrics(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	id := c.Param("id")
 	
 	var request UpdateMetricsRequest
@@ -92,12 +84,16 @@ func (h *AggregatorHandler) UpdateAggregatorMetrics(c *gin.Context) {
 		return
 	}
 
-	err = h.metricsService.UpdateMetrics(id, userID, request.CPUUsage, request.MemoryUsage, request.NetworkUsage)
+	err := h.metricsService.UpdateMetrics(id, authz.PrincipalFromContext(c), request.CPUUsage, request.MemoryUsage, request.NetworkUsage)
 	if err != nil {
 		if err == aggregator.ErrAggregatorNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Aggregator를 찾을 수 없습니다"})
 			return
 		}
+		if err == aggregator.ErrAggregatorAccessDenied {
+			c.JSON(http.StatusForbidden, gin.H{"error": "권한이 없습니다"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "메트릭 업데이트 실패"})
 		return
 	}
@@ -107,7 +103,7 @@ func (h *AggregatorHandler) UpdateAggregatorMetrics(c *gin.Context) {
 
 // GetAggregatorStats godoc
 // @Summary Aggregator 통계 조회
-// @Description 사용자의 Aggregator 통계를 조회합니다.
+// @Description 현재 조직의 Aggregator 통계를 조회합니다.
 // @Tags aggregators
 // @Accept json
 // @Produce json
@@ -116,13 +112,12 @@ func (h *AggregatorHandler) UpdateAggregatorMetrics(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/aggregators/stats [get]
 func (h *AggregatorHandler) GetAggregatorStats(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
+	principal, ok := authz.AuthorizeActive(c, authz.ActionView)
+	if !ok {
 		return
 	}
 
-	stats, err := h.metricsService.GetStats(userID)
+	stats, err := h.metricsService.GetStats(principal.ActiveOrganizationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "통계 조회 실패"})
 		return
@@ -1,11 +1,13 @@
 package aggregator
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/Mungge/Fleecy-Cloud/services/aggregator"
+	"github.com/Mungge/Fleecy-Cloud/services/pricing"
 	"github.com/Mungge/Fleecy-Cloud/utils"
 	"github.com/Mungge/Fleecy-Cloud/validators/aggregator"
 )
@@ -24,11 +26,7 @@ import (
 // @Router /api/aggregators/optimization [post]
 func (h *AggregatorHandler) OptimizeAggregatorPlacement(c *gin.Context) {
 	// 1. 사용자 인증 확인
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
+	userID := utils.GetUserIDFromMiddleware(c)
 
 	// 2. 요청 데이터 파싱
 	var request aggregator.OptimizationRequest
@@ -47,23 +45,22 @@ func (h *AggregatorHandler) OptimizeAggregatorPlacement(c *gin.Context) {
 		return
 	}
 
-	// 4. Python 환경 및 스크립트 존재 여부 확인
-	if err := h.optimizationService.ValidatePythonEnvironment(); err != nil {
+	// 4. 최적화 백엔드 실행 환경 확인 (Python 백엔드 사용 시 런타임/스크립트 검증)
+	if err := h.optimizationService.ValidateEnvironment(); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Python 환경 오류: " + err.Error(),
+			"error": "최적화 환경 오류: " + err.Error(),
 		})
 		return
 	}
 
-	if err := h.optimizationService.ValidatePythonScript(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Python 스크립트 오류: " + err.Error(),
+	// 5. 최적화 실행
+	result, err := h.optimizationService.RunOptimization(request)
+	if errors.Is(err, pricing.ErrSnapshotNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "요청한 가격 스냅샷 버전을 찾을 수 없습니다",
 		})
 		return
 	}
-
-	// 5. 최적화 실행
-	result, err := h.optimizationService.RunOptimization(request)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "집계자 배치 최적화 실행 실패: " + err.Error(),
@@ -4,17 +4,20 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
 	"github.com/gin-gonic/gin"
 )
 
 // DeploymentProgress 배포 진행 상황
 type DeploymentProgress struct {
 	AggregatorID string `json:"aggregator_id"`
+	DeploymentID string `json:"deployment_id,omitempty"`
+	State        string `json:"state,omitempty"` // creating, keypair, workspace, applying, running, failed
 	Stage        int    `json:"stage"`
 	TotalStages  int    `json:"total_stages"`
 	Message      string `json:"message"`
-	Status       string `json:"status"` // "creating", "progress", "completed", "failed"
+	Status       string `json:"status"` // "progress", "completed", "failed"
 	Error        string `json:"error,omitempty"`
 	LastUpdated  int64  `json:"last_updated"`
 }
@@ -31,16 +34,10 @@ type DeploymentProgress struct {
 // @Failure 404 {object} map[string]string
 // @Router /api/aggregators/{id}/progress [get]
 func (h *AggregatorHandler) GetAggregatorProgress(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	aggregatorID := c.Param("id")
 
 	// 권한 확인
-	aggregator, err := h.aggregatorService.GetAggregatorByID(aggregatorID, userID)
+	aggregator, err := h.aggregatorService.GetAggregatorByID(aggregatorID, authz.PrincipalFromContext(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Aggregator 조회 실패"})
 		return
@@ -51,55 +48,47 @@ func (h *AggregatorHandler) GetAggregatorProgress(c *gin.Context) {
 		return
 	}
 
-	// 진행 상황 조회 (메모리 캐시 또는 DB에서)
-	progress := getDeploymentProgress(aggregatorID, aggregator.Status)
+	// 배포 작업 상태 조회 (aggregator_deployments 테이블)
+	deployment, err := h.aggregatorService.GetLatestDeployment(aggregatorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "배포 진행 상황 조회 실패"})
+		return
+	}
 
-	c.JSON(http.StatusOK, progress)
+	c.JSON(http.StatusOK, toDeploymentProgress(aggregatorID, aggregator.Status, deployment))
 }
 
-// getDeploymentProgress 배포 진행 상황 반환 (실제 구현에서는 캐시나 DB 사용)
-func getDeploymentProgress(aggregatorID, status string) DeploymentProgress {
-	switch status {
-	case "creating":
-		return DeploymentProgress{
-			AggregatorID: aggregatorID,
-			Stage:        1,
-			TotalStages:  5,
-			Message:      "배포 준비 중...",
-			Status:       "progress",
-			LastUpdated:  getCurrentTimestamp(),
-		}
-	case "running":
-		return DeploymentProgress{
-			AggregatorID: aggregatorID,
-			Stage:        5,
-			TotalStages:  5,
-			Message:      "배포 완료",
-			Status:       "completed",
-			LastUpdated:  getCurrentTimestamp(),
-		}
-	case "failed":
-		return DeploymentProgress{
-			AggregatorID: aggregatorID,
-			Stage:        0,
-			TotalStages:  5,
-			Message:      "배포 실패",
-			Status:       "failed",
-			Error:        "배포 중 오류가 발생했습니다",
-			LastUpdated:  getCurrentTimestamp(),
-		}
-	default:
+// toDeploymentProgress 배포 작업 레코드를 진행 상황 응답으로 변환
+func toDeploymentProgress(aggregatorID, aggregatorStatus string, deployment *models.AggregatorDeployment) DeploymentProgress {
+	// 배포 작업 기록이 없는 집계자 (비동기 배포 도입 이전 생성)
+	if deployment == nil {
 		return DeploymentProgress{
 			AggregatorID: aggregatorID,
-			Stage:        0,
-			TotalStages:  5,
-			Message:      "상태 불명",
-			Status:       "unknown",
-			LastUpdated:  getCurrentTimestamp(),
+			TotalStages:  models.DeploymentTotalStages,
+			Message:      "배포 기록이 없습니다",
+			Status:       aggregatorStatus,
+			LastUpdated:  time.Now().UnixMilli(),
 		}
 	}
-}
 
-func getCurrentTimestamp() int64 {
-	return time.Now().Unix() * 1000
+	progress := DeploymentProgress{
+		AggregatorID: aggregatorID,
+		DeploymentID: deployment.ID,
+		State:        deployment.State,
+		Stage:        deployment.Stage,
+		TotalStages:  deployment.TotalStages,
+		Message:      deployment.Message,
+		Error:        deployment.Error,
+		LastUpdated:  deployment.UpdatedAt.UnixMilli(),
+	}
+
+	switch deployment.State {
+	case models.DeploymentStateRunning:
+		progress.Status = "completed"
+	case models.DeploymentStateFailed:
+		progress.Status = "failed"
+	default:
+		progress.Status = "progress"
+	}
+	return progress
 }
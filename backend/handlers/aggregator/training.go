@@ -13,7 +13,7 @@ import (
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
 	"github.com/Mungge/Fleecy-Cloud/services"
-	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
 	"github.com/gin-gonic/gin"
 )
 
@@ -210,13 +210,6 @@ func (m *MLflowClient) CreateExperiment(experimentName string) (string, error) {
 // @Failure 500 {object} map[string]string
 // @Router /api/aggregators/{id}/training-history [get]
 func (h *MLflowHandler) GetTrainingHistory(c *gin.Context) {
-	// 사용자 인증 확인
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	aggregatorID := c.Param("id")
 
 	// DB에서 aggregator 정보 조회 (권한 확인 포함)
@@ -229,7 +222,7 @@ func (h *MLflowHandler) GetTrainingHistory(c *gin.Context) {
 		return
 	}
 
-	if aggregator == nil || aggregator.UserID != userID {
+	if aggregator == nil || !authz.PrincipalFromContext(c).Can(aggregator.OrganizationID, authz.ActionView) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Aggregator를 찾을 수 없습니다"})
 		return
 	}
@@ -433,13 +426,6 @@ func (h *MLflowHandler) saveMetricsToDatabase(aggregatorID, runID string, stepDa
 
 // 실시간 메트릭 조회
 func (h *MLflowHandler) GetRealTimeMetrics(c *gin.Context) {
-	// 사용자 인증 확인
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	aggregatorID := c.Param("id")
 
 	// DB에서 aggregator 정보 조회
@@ -449,7 +435,7 @@ func (h *MLflowHandler) GetRealTimeMetrics(c *gin.Context) {
 		return
 	}
 
-	if aggregator == nil || aggregator.UserID != userID {
+	if aggregator == nil || !authz.PrincipalFromContext(c).Can(aggregator.OrganizationID, authz.ActionView) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Aggregator를 찾을 수 없습니다"})
 		return
 	}
@@ -570,12 +556,6 @@ func (h *MLflowHandler) GetRealTimeMetrics(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/aggregators/{id}/system-metrics [get]
 func (h *MLflowHandler) GetSystemMetrics(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	aggregatorID := c.Param("id")
 
 	aggregator, err := h.aggregatorRepo.GetAggregatorByID(aggregatorID)
@@ -584,7 +564,7 @@ func (h *MLflowHandler) GetSystemMetrics(c *gin.Context) {
 		return
 	}
 
-	if aggregator == nil || aggregator.UserID != userID {
+	if aggregator == nil || !authz.PrincipalFromContext(c).Can(aggregator.OrganizationID, authz.ActionView) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Aggregator를 찾을 수 없습니다"})
 		return
 	}
@@ -663,13 +643,6 @@ func (h *MLflowHandler) GetSystemMetrics(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/aggregators/{id}/metric-history [get]
 func (h *MLflowHandler) GetSingleMetricHistory(c *gin.Context) {
-	// 사용자 인증 확인
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	aggregatorID := c.Param("id")
 	metricKey := c.Query("key")
 
@@ -688,7 +661,7 @@ func (h *MLflowHandler) GetSingleMetricHistory(c *gin.Context) {
 		return
 	}
 
-	if aggregator == nil || aggregator.UserID != userID {
+	if aggregator == nil || !authz.PrincipalFromContext(c).Can(aggregator.OrganizationID, authz.ActionView) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Aggregator를 찾을 수 없습니다"})
 		return
 	}
@@ -801,13 +774,6 @@ func (h *MLflowHandler) GetSingleMetricHistory(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/aggregators/{id}/mlflow-info [get]
 func (h *MLflowHandler) GetMLflowInfo(c *gin.Context) {
-	// 사용자 인증 확인
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	aggregatorID := c.Param("id")
 
 	// DB에서 aggregator 정보 조회
@@ -817,7 +783,7 @@ func (h *MLflowHandler) GetMLflowInfo(c *gin.Context) {
 		return
 	}
 
-	if aggregator == nil || aggregator.UserID != userID {
+	if aggregator == nil || !authz.PrincipalFromContext(c).Can(aggregator.OrganizationID, authz.ActionView) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Aggregator를 찾을 수 없습니다"})
 		return
 	}
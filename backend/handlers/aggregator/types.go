@@ -28,6 +28,7 @@ type CreateAggregatorRequest struct {
 // CreateAggregatorResponse Aggregator 생성 응답
 type CreateAggregatorResponse struct {
 	AggregatorID    string `json:"aggregatorId"`
+	DeploymentID    string `json:"deploymentId"`
 	Status          string `json:"status"`
 	TerraformStatus string `json:"terraformStatus,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/utils"
+)
+
+const (
+	defaultAPITokenExpiresInDays = 90
+	maxAPITokenExpiresInDays     = 365
+)
+
+// APITokenHandler는 자동화/CI용 개인 액세스 토큰 관리 요청을 처리합니다
+type APITokenHandler struct {
+	repo *repository.APITokenRepository
+}
+
+// NewAPITokenHandler는 새 APITokenHandler 인스턴스를 생성합니다
+func NewAPITokenHandler(repo *repository.APITokenRepository) *APITokenHandler {
+	return &APITokenHandler{repo: repo}
+}
+
+type createAPITokenRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"`
+	ExpiresInDays *int     `json:"expires_in_days"`
+}
+
+// GetAPITokens는 사용자의 개인 액세스 토큰 목록을 반환합니다 (토큰 원문은 포함하지 않음)
+func (h *APITokenHandler) GetAPITokens(c *gin.Context) {
+	userID := utils.GetUserIDFromMiddleware(c)
+
+	tokens, err := h.repo.ListTokensByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "API 토큰 목록 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tokens})
+}
+
+// GetAPITokenScopes는 부여 가능한 스코프 목록을 반환합니다
+func (h *APITokenHandler) GetAPITokenScopes(c *gin.Context) {
+	levels := []string{models.APITokenScopeRead, models.APITokenScopeWrite, models.APITokenScopeAdmin}
+	scopes := make([]string, 0, len(models.APITokenResources)*len(levels))
+	for _, resource := range models.APITokenResources {
+		for _, level := range levels {
+			scopes = append(scopes, resource+":"+level)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": scopes})
+}
+
+// CreateAPIToken은 새 개인 액세스 토큰을 발급합니다 (토큰 원문은 이 응답에서만 확인 가능)
+func (h *APITokenHandler) CreateAPIToken(c *gin.Context) {
+	userID := utils.GetUserIDFromMiddleware(c)
+
+	var req createAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "토큰 이름이 필요합니다"})
+		return
+	}
+
+	scopes, err := models.NormalizeAPITokenScopes(req.Scopes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days := defaultAPITokenExpiresInDays
+	if req.ExpiresInDays != nil {
+		days = *req.ExpiresInDays
+	}
+	if days < 1 || days > maxAPITokenExpiresInDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "만료 기간은 1일 이상 365일 이하로 지정해야 합니다"})
+		return
+	}
+	expiresAt := time.Now().AddDate(0, 0, days)
+
+	rawToken, tokenHash, err := models.GenerateAPIToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "토큰 생성 중 오류가 발생했습니다"})
+		return
+	}
+
+	token := &models.APIToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: tokenHash,
+		Prefix:    rawToken[:len(models.APITokenPrefix)+8],
+		Scopes:    strings.Join(scopes, " "),
+		ExpiresAt: &expiresAt,
+	}
+	if err := h.repo.CreateToken(token); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "토큰 저장 중 오류가 발생했습니다"})
+		return
+	}
+
+	log.Printf("API 토큰 발급: 사용자 %d, 토큰 %s (%s), 스코프: %s", userID, token.Name, token.Prefix, token.Scopes)
+	c.JSON(http.StatusCreated, gin.H{
+		"data":    token,
+		"token":   rawToken,
+		"message": "토큰은 지금만 확인할 수 있습니다. 안전한 곳에 보관하세요",
+	})
+}
+
+// RevokeAPIToken은 개인 액세스 토큰을 폐기합니다
+func (h *APITokenHandler) RevokeAPIToken(c *gin.Context) {
+	userID := utils.GetUserIDFromMiddleware(c)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 토큰 ID입니다"})
+		return
+	}
+
+	token, err := h.repo.GetTokenByID(userID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "API 토큰 조회에 실패했습니다"})
+		return
+	}
+	if token == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API 토큰을 찾을 수 없습니다"})
+		return
+	}
+
+	if err := h.repo.RevokeToken(userID, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "API 토큰 폐기에 실패했습니다"})
+		return
+	}
+
+	log.Printf("API 토큰 폐기: 사용자 %d, 토큰 %s (%s)", userID, token.Name, token.Prefix)
+	c.JSON(http.StatusOK, gin.H{"message": "API 토큰이 폐기되었습니다"})
+}
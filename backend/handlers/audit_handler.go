@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+// AuditHandler는 감사 로그 조회/내보내기 요청을 처리합니다
+type AuditHandler struct {
+	repo *repository.AuditLogRepository
+}
+
+// NewAuditHandler는 새 AuditHandler 인스턴스를 생성합니다
+func NewAuditHandler(repo *repository.AuditLogRepository) *AuditHandler {
+	return &AuditHandler{repo: repo}
+}
+
+// GetAuditLogs는 활성 조직의 감사 로그를 필터 조건으로 조회합니다 (admin 이상)
+func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+	principal, ok := authz.AuthorizeActive(c, authz.ActionManageMembers)
+	if !ok {
+		return
+	}
+
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.OrganizationID = &principal.ActiveOrganizationID
+
+	h.respondAuditLogs(c, filter)
+}
+
+// GetMyAuditLogs는 요청한 사용자가 행위자인 감사 로그를 조직과 관계없이 조회합니다 (로그인 기록 포함)
+func (h *AuditHandler) GetMyAuditLogs(c *gin.Context) {
+	principal := authz.PrincipalFromContext(c)
+	if principal == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
+		return
+	}
+
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.ActorUserID = &principal.UserID
+
+	h.respondAuditLogs(c, filter)
+}
+
+// ExportAuditLogs는 활성 조직의 감사 로그를 JSON Lines 형식으로 내보냅니다 (admin 이상)
+func (h *AuditHandler) ExportAuditLogs(c *gin.Context) {
+	principal, ok := authz.AuthorizeActive(c, authz.ActionManageMembers)
+	if !ok {
+		return
+	}
+
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.OrganizationID = &principal.ActiveOrganizationID
+	filter.Limit, filter.Offset = 0, 0
+
+	audit.Annotate(c, "audit.export", "organizations", strconv.FormatInt(principal.ActiveOrganizationID, 10))
+
+	filename := fmt.Sprintf("audit-org-%d-%s.jsonl", principal.ActiveOrganizationID, time.Now().UTC().Format("20060102T150405Z"))
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	err = h.repo.Export(filter, func(entries []*models.AuditLog) error {
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		// 헤더를 이미 보냈으므로 마지막 줄에 오류를 남김
+		encoder.Encode(gin.H{"error": "감사 로그 내보내기가 중단되었습니다"})
+	}
+}
+
+func (h *AuditHandler) respondAuditLogs(c *gin.Context, filter models.AuditLogFilter) {
+	entries, total, err := h.repo.List(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "감사 로그 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   entries,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// parseAuditFilter는 쿼리 파라미터를 감사 로그 조회 조건으로 변환합니다
+// actor_user_id, action(접두사), resource_type, resource_id, outcome, request_id, from/to(RFC3339), limit, offset
+func parseAuditFilter(c *gin.Context) (models.AuditLogFilter, error) {
+	filter := models.AuditLogFilter{
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		Outcome:      c.Query("outcome"),
+		RequestID:    c.Query("request_id"),
+		Limit:        defaultAuditPageSize,
+	}
+
+	if v := c.Query("actor_user_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("잘못된 actor_user_id입니다")
+		}
+		filter.ActorUserID = &id
+	}
+
+	for name, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := c.Query(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, fmt.Errorf("%s는 RFC3339 형식이어야 합니다", name)
+			}
+			*target = &t
+		}
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxAuditPageSize {
+			return filter, fmt.Errorf("limit은 1 이상 %d 이하여야 합니다", maxAuditPageSize)
+		}
+		filter.Limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return filter, fmt.Errorf("잘못된 offset입니다")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
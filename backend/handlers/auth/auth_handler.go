@@ -2,6 +2,9 @@ package handlers
 
 import (
 	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/mail"
+	"github.com/Mungge/Fleecy-Cloud/services/oidc"
+	"github.com/Mungge/Fleecy-Cloud/services/ratelimit"
 	"github.com/gin-gonic/gin"
 )
 
@@ -9,6 +12,7 @@ import (
 type AuthHandler struct {
 	Local  *LocalAuthHandler
 	GitHub *GitHubAuthHandler
+	OIDC   *OIDCAuthHandler
 	Base   *BaseAuthHandler
 }
 
@@ -16,16 +20,23 @@ type AuthHandler struct {
 func NewAuthHandler(
 	userRepo *repository.UserRepository, 
 	refreshTokenRepo *repository.RefreshTokenRepository, 
+	userTokenRepo *repository.UserTokenRepository,
+	orgRepo *repository.OrganizationRepository,
+	mailer mail.Mailer,
 	githubClientID, githubClientSecret string,
+	oidcProviders *oidc.Registry,
+	loginGuard *ratelimit.LoginGuard,
 ) *AuthHandler {
 	// 각 핸들러 초기화
-	localHandler := NewLocalAuthHandler(userRepo, refreshTokenRepo)
+	localHandler := NewLocalAuthHandler(userRepo, refreshTokenRepo, userTokenRepo, mailer, loginGuard)
 	githubHandler := NewGitHubAuthHandler(userRepo, refreshTokenRepo, githubClientID, githubClientSecret)
+	oidcHandler := NewOIDCAuthHandler(userRepo, refreshTokenRepo, orgRepo, oidcProviders)
 	baseHandler := NewBaseAuthHandler(userRepo, refreshTokenRepo)
 
 	return &AuthHandler{
 		Local:  localHandler,
 		GitHub: githubHandler,
+		OIDC:   oidcHandler,
 		Base:   baseHandler,
 	}
 }
@@ -49,6 +60,18 @@ func (h *AuthHandler) ResetPasswordHandler(c *gin.Context) {
 	h.Local.ResetPasswordHandler(c)
 }
 
+func (h *AuthHandler) ConfirmPasswordResetHandler(c *gin.Context) {
+	h.Local.ConfirmPasswordResetHandler(c)
+}
+
+func (h *AuthHandler) VerifyEmailHandler(c *gin.Context) {
+	h.Local.VerifyEmailHandler(c)
+}
+
+func (h *AuthHandler) ResendVerificationHandler(c *gin.Context) {
+	h.Local.ResendVerificationHandler(c)
+}
+
 // GitHub OAuth 관련
 func (h *AuthHandler) GitHubLoginHandler(c *gin.Context) {
 	h.GitHub.GitHubLoginHandler(c)
@@ -58,11 +81,28 @@ func (h *AuthHandler) GitHubCallbackHandler(c *gin.Context) {
 	h.GitHub.GitHubCallbackHandler(c)
 }
 
+// OIDC 관련
+func (h *AuthHandler) OIDCProvidersHandler(c *gin.Context) {
+	h.OIDC.ListProvidersHandler(c)
+}
+
+func (h *AuthHandler) OIDCLoginHandler(c *gin.Context) {
+	h.OIDC.LoginHandler(c)
+}
+
+func (h *AuthHandler) OIDCCallbackHandler(c *gin.Context) {
+	h.OIDC.CallbackHandler(c)
+}
+
 // 공통 기능 관련
 func (h *AuthHandler) RefreshTokenHandler(c *gin.Context) {
 	h.Base.RefreshTokenHandler(c)
 }
 
+func (h *AuthHandler) JWKSHandler(c *gin.Context) {
+	h.Base.JWKSHandler(c)
+}
+
 func (h *AuthHandler) LogoutHandler(c *gin.Context) {
 	h.Base.LogoutHandler(c)
 }
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
@@ -103,6 +105,10 @@ func (h *BaseAuthHandler) createAuthResponse(tokenPair *utils.TokenPair, user *m
 	}
 }
 
+// errUnverifiedAccountLink는 이메일 인증을 마치지 않은 기존 계정에 외부 로그인을 연결하려 할 때 반환됩니다
+// 다른 사람이 같은 이메일로 미리 가입해 둔 계정이 외부 로그인으로 소유자의 계정이 되는 것을 막습니다
+var errUnverifiedAccountLink = errors.New("unverified account cannot be linked to an external login")
+
 // 사용자 관리 함수들
 func (h *BaseAuthHandler) findOrCreateUser(email, name string) (*models.User, bool, error) {
 	// 기존 사용자 조회
@@ -112,6 +118,10 @@ func (h *BaseAuthHandler) findOrCreateUser(email, name string) (*models.User, bo
 	}
 
 	if user != nil {
+		if !user.IsEmailVerified() {
+			log.Printf("외부 로그인 연결 거부 - 이메일 미인증 계정: %s", email)
+			return nil, false, errUnverifiedAccountLink
+		}
 		// 기존 사용자 정보 업데이트 (이름이 다른 경우)
 		if user.Name != name && name != "" {
 			user.Name = name
@@ -122,11 +132,13 @@ func (h *BaseAuthHandler) findOrCreateUser(email, name string) (*models.User, bo
 		return user, false, nil // 기존 사용자
 	}
 
-	// 새 사용자 생성
+	// 새 사용자 생성 (OAuth 제공자가 확인한 이메일이므로 인증 완료로 처리)
+	now := time.Now()
 	newUser := &models.User{
-		Name:         name,
-		Email:        email,
-		PasswordHash: "", // OAuth 사용자는 비밀번호 없음
+		Name:            name,
+		Email:           email,
+		PasswordHash:    "", // OAuth 사용자는 비밀번호 없음
+		EmailVerifiedAt: &now,
 	}
 
 	if err := h.userRepo.CreateUser(newUser); err != nil {
@@ -192,6 +204,18 @@ func (h *BaseAuthHandler) LogoutHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "로그아웃되었습니다"})
 }
 
+// JWKSHandler는 액세스 토큰 검증용 공개 키 목록(JWKS)을 반환합니다 (RS256/EdDSA 사용 시)
+func (h *BaseAuthHandler) JWKSHandler(c *gin.Context) {
+	jwks, err := utils.JWKS()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "키 목록 조회 중 오류가 발생했습니다"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwks)
+}
+
 func (h *BaseAuthHandler) LogoutAllHandler(c *gin.Context) {
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
@@ -244,6 +268,15 @@ func isProduction() bool {
 	return env == "release" || env == "production"
 }
 
+// respondFindOrCreateUserError는 findOrCreateUser 실패를 응답합니다
+func respondFindOrCreateUserError(c *gin.Context, err error) {
+	if errors.Is(err, errUnverifiedAccountLink) {
+		c.JSON(http.StatusConflict, gin.H{"error": "같은 이메일로 가입된 계정이 아직 이메일 인증을 마치지 않았습니다. 메일함에서 인증을 완료한 뒤 다시 시도해주세요"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "사용자 처리 실패: " + err.Error()})
+}
+
 func getRefreshTokenExpirationDays() int {
 	days := os.Getenv("REFRESH_TOKEN_EXPIRATION_DAYS")
 	if days == "" {
@@ -10,6 +10,7 @@ import (
 	"os"
 
 	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
 	"github.com/gin-gonic/gin"
 )
 
@@ -78,6 +79,8 @@ func (h *GitHubAuthHandler) GitHubLoginHandler(c *gin.Context) {
 
 // GitHubCallbackHandler는 GitHub OAuth 콜백을 처리합니다
 func (h *GitHubAuthHandler) GitHubCallbackHandler(c *gin.Context) {
+	audit.Annotate(c, "auth.login.github", "users", "")
+
 	// 인증 코드와 상태 값 확인
 	code := c.Query("code")
 	state := c.Query("state")
@@ -130,9 +133,11 @@ func (h *GitHubAuthHandler) GitHubCallbackHandler(c *gin.Context) {
 	// 사용자 조회 또는 생성
 	user, isNewUser, err := h.findOrCreateUser(githubUser.Email, githubUser.Name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "사용자 처리 실패: " + err.Error()})
+		respondFindOrCreateUserError(c, err)
 		return
 	}
+	audit.SetActor(c, user.ID)
+	audit.SetResourceID(c, fmt.Sprint(user.ID))
 	
 	// 토큰 쌍 생성 및 저장
 	tokenPair, refreshTokenString, err := h.generateAndStoreTokenPair(user.ID, user.Email, user.Name)
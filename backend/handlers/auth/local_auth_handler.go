@@ -1,13 +1,22 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 	"unicode"
 
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/mail"
+	"github.com/Mungge/Fleecy-Cloud/services/ratelimit"
 	"github.com/Mungge/Fleecy-Cloud/utils"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
@@ -16,12 +25,24 @@ import (
 // LocalAuthHandler는 이메일/비밀번호 기반 인증을 처리합니다
 type LocalAuthHandler struct {
 	*BaseAuthHandler
+	userTokenRepo *repository.UserTokenRepository
+	mailer        mail.Mailer
+	loginGuard    *ratelimit.LoginGuard
 }
 
 // NewLocalAuthHandler는 새로운 LocalAuthHandler 인스턴스를 생성합니다
-func NewLocalAuthHandler(userRepo *repository.UserRepository, refreshTokenRepo *repository.RefreshTokenRepository) *LocalAuthHandler {
+func NewLocalAuthHandler(
+	userRepo *repository.UserRepository,
+	refreshTokenRepo *repository.RefreshTokenRepository,
+	userTokenRepo *repository.UserTokenRepository,
+	mailer mail.Mailer,
+	loginGuard *ratelimit.LoginGuard,
+) *LocalAuthHandler {
 	return &LocalAuthHandler{
 		BaseAuthHandler: NewBaseAuthHandler(userRepo, refreshTokenRepo),
+		userTokenRepo:   userTokenRepo,
+		mailer:          mailer,
+		loginGuard:      loginGuard,
 	}
 }
 
@@ -80,19 +101,35 @@ func (h *LocalAuthHandler) RegisterHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "사용자 생성 중 오류가 발생했습니다"})
 		return
 	}
+	audit.Annotate(c, "auth.register", "users", fmt.Sprint(user.ID))
+	audit.SetActor(c, user.ID)
+
+	// 이메일 인증 메일 발송 (실패해도 인증 메일 재발송으로 복구 가능)
+	if err := h.sendVerificationEmail(user); err != nil {
+		log.Printf("이메일 인증 토큰 생성 실패 (사용자: %s): %v", user.Email, err)
+	}
 
 	log.Printf("새 사용자 회원가입: %s", user.Email)
-	c.JSON(http.StatusCreated, gin.H{"message": "회원가입이 완료되었습니다"})
+	c.JSON(http.StatusCreated, gin.H{"message": "회원가입이 완료되었습니다. 이메일 인증 후 로그인할 수 있습니다"})
 }
 
 // LoginHandler는 이메일/비밀번호 로그인을 처리합니다
 func (h *LocalAuthHandler) LoginHandler(c *gin.Context) {
+	audit.Annotate(c, "auth.login", "users", "")
+
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
 		return
 	}
 
+	// 반복 실패로 잠긴 계정인지 확인 (비밀번호 검사 전에 거부)
+	if wait := h.loginGuard.Check(c.Request.Context(), req.Email); wait > 0 {
+		log.Printf("로그인 차단 - 반복 실패로 잠긴 계정: %s", req.Email)
+		h.respondLoginLocked(c, wait)
+		return
+	}
+
 	// 사용자 조회
 	user, err := h.userRepo.GetUserByEmail(req.Email)
 	if err != nil {
@@ -101,6 +138,7 @@ func (h *LocalAuthHandler) LoginHandler(c *gin.Context) {
 	}
 	if user == nil {
 		log.Printf("로그인 실패 시도 - 존재하지 않는 이메일: %s", req.Email)
+		h.loginGuard.RecordFailure(c.Request.Context(), req.Email)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "이메일 또는 비밀번호가 올바르지 않습니다"})
 		return
 	}
@@ -115,9 +153,22 @@ func (h *LocalAuthHandler) LoginHandler(c *gin.Context) {
 	// 비밀번호 확인
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
 		log.Printf("로그인 실패 시도 - 잘못된 비밀번호: %s", req.Email)
+		h.loginGuard.RecordFailure(c.Request.Context(), req.Email)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "이메일 또는 비밀번호가 올바르지 않습니다"})
 		return
 	}
+	h.loginGuard.Reset(c.Request.Context(), req.Email)
+
+	// 이후 실패(미인증 등)도 대상 계정이 남도록 기록
+	audit.SetActor(c, user.ID)
+	audit.SetResourceID(c, fmt.Sprint(user.ID))
+
+	// 이메일 인증 확인
+	if !user.IsEmailVerified() {
+		log.Printf("로그인 실패 시도 - 이메일 미인증: %s", req.Email)
+		c.JSON(http.StatusForbidden, gin.H{"error": "이메일 인증이 필요합니다. 메일함을 확인해주세요"})
+		return
+	}
 
 	// 토큰 쌍 생성 및 저장
 	tokenPair, refreshTokenString, err := h.generateAndStoreTokenPair(user.ID, user.Email, user.Name)
@@ -201,7 +252,7 @@ func (h *LocalAuthHandler) ChangePasswordHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "비밀번호가 변경되었습니다. 다시 로그인해주세요."})
 }
 
-// ResetPasswordHandler는 비밀번호 재설정을 처리합니다 (추가 기능 - 이메일 발송 등은 별도 구현 필요)
+// ResetPasswordHandler는 비밀번호 재설정 메일 발송을 처리합니다
 func (h *LocalAuthHandler) ResetPasswordHandler(c *gin.Context) {
 	var req struct {
 		Email string `json:"email" binding:"required,email"`
@@ -226,15 +277,246 @@ func (h *LocalAuthHandler) ResetPasswordHandler(c *gin.Context) {
 		return
 	}
 
-	// TODO: 실제로는 여기서 비밀번호 재설정 이메일을 발송해야 함
-	// 1. 임시 토큰 생성
-	// 2. 데이터베이스에 토큰 저장 (만료시간 포함)
-	// 3. 이메일 발송 서비스 호출
-	
+	if err := h.sendPasswordResetEmail(user); err != nil {
+		log.Printf("비밀번호 재설정 토큰 생성 실패 (사용자: %s): %v", user.Email, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "처리 중 오류가 발생했습니다"})
+		return
+	}
+
 	log.Printf("비밀번호 재설정 요청: %s", req.Email)
 	c.JSON(http.StatusOK, gin.H{"message": "비밀번호 재설정 이메일을 발송했습니다 (해당 이메일이 존재하는 경우)"})
 }
 
+// ConfirmPasswordResetHandler는 재설정 토큰을 확인하고 새 비밀번호를 설정합니다
+func (h *LocalAuthHandler) ConfirmPasswordResetHandler(c *gin.Context) {
+	var req struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required,min=8"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+
+	// 토큰을 사용하기 전에 새 비밀번호부터 검사 (형식 오류로 토큰이 소진되지 않도록)
+	if err := h.validatePassword(req.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "비밀번호 처리 중 오류가 발생했습니다"})
+		return
+	}
+
+	token, err := h.userTokenRepo.ConsumeToken(models.UserTokenPurposePasswordReset, models.HashUserToken(req.Token))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "처리 중 오류가 발생했습니다"})
+		return
+	}
+	if token == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "유효하지 않거나 만료된 재설정 링크입니다"})
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(token.UserID)
+	if err != nil || user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "사용자를 찾을 수 없습니다"})
+		return
+	}
+	audit.Annotate(c, "auth.password.reset", "users", fmt.Sprint(user.ID))
+	audit.SetActor(c, user.ID)
+
+	// 비밀번호 업데이트 (메일 링크로 재설정했으므로 이메일 소유도 확인된 것으로 처리)
+	user.PasswordHash = string(hashedPassword)
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := h.userRepo.UpdateUser(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "비밀번호 변경 중 오류가 발생했습니다"})
+		return
+	}
+
+	// 기존 세션은 모두 무효화
+	if err := h.refreshTokenRepo.RevokeAllUserRefreshTokens(user.ID); err != nil {
+		log.Printf("리프레시 토큰 무효화 실패 (사용자: %s): %v", user.Email, err)
+	}
+	h.clearRefreshTokenCookie(c)
+
+	// 메일로 소유를 확인했으므로 로그인 잠금도 해제
+	h.loginGuard.Reset(c.Request.Context(), user.Email)
+
+	log.Printf("비밀번호 재설정 완료: %s", user.Email)
+	c.JSON(http.StatusOK, gin.H{"message": "비밀번호가 재설정되었습니다. 다시 로그인해주세요."})
+}
+
+// VerifyEmailHandler는 이메일 인증 토큰을 확인합니다
+func (h *LocalAuthHandler) VerifyEmailHandler(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+
+	token, err := h.userTokenRepo.ConsumeToken(models.UserTokenPurposeEmailVerification, models.HashUserToken(req.Token))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "처리 중 오류가 발생했습니다"})
+		return
+	}
+	if token == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "유효하지 않거나 만료된 인증 링크입니다"})
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(token.UserID)
+	if err != nil || user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "사용자를 찾을 수 없습니다"})
+		return
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := h.userRepo.UpdateUser(user); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "이메일 인증 처리 중 오류가 발생했습니다"})
+			return
+		}
+	}
+
+	log.Printf("이메일 인증 완료: %s", user.Email)
+	c.JSON(http.StatusOK, gin.H{"message": "이메일 인증이 완료되었습니다"})
+}
+
+// ResendVerificationHandler는 이메일 인증 메일을 다시 발송합니다
+func (h *LocalAuthHandler) ResendVerificationHandler(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+
+	user, err := h.userRepo.GetUserByEmail(req.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "처리 중 오류가 발생했습니다"})
+		return
+	}
+
+	// 보안상 사용자 존재 여부나 인증 상태를 알려주지 않음
+	if user != nil && !user.IsEmailVerified() {
+		if err := h.sendVerificationEmail(user); err != nil {
+			log.Printf("이메일 인증 토큰 생성 실패 (사용자: %s): %v", user.Email, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "처리 중 오류가 발생했습니다"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "인증 메일을 발송했습니다 (인증이 필요한 계정인 경우)"})
+}
+
+// sendVerificationEmail은 이메일 인증 토큰을 발급하고 인증 메일을 발송합니다
+func (h *LocalAuthHandler) sendVerificationEmail(user *models.User) error {
+	ttl := getUserTokenTTL("EMAIL_VERIFICATION_TOKEN_TTL", 24*time.Hour)
+	token, err := h.issueUserToken(user.ID, models.UserTokenPurposeEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+
+	link := getFrontendURL() + "/auth/verify-email?token=" + url.QueryEscape(token)
+	h.sendMail(mail.Message{
+		To:      user.Email,
+		Subject: "[Fleecy Cloud] 이메일 인증",
+		Body: fmt.Sprintf("%s님, Fleecy Cloud 가입을 환영합니다.\n\n"+
+			"아래 링크를 열어 이메일 인증을 완료해주세요. 링크는 %s 동안 유효합니다.\n\n%s\n\n"+
+			"본인이 가입하지 않았다면 이 메일을 무시하세요.\n", user.Name, formatTTL(ttl), link),
+	})
+	return nil
+}
+
+// sendPasswordResetEmail은 비밀번호 재설정 토큰을 발급하고 재설정 메일을 발송합니다
+func (h *LocalAuthHandler) sendPasswordResetEmail(user *models.User) error {
+	ttl := getUserTokenTTL("PASSWORD_RESET_TOKEN_TTL", 30*time.Minute)
+	token, err := h.issueUserToken(user.ID, models.UserTokenPurposePasswordReset, ttl)
+	if err != nil {
+		return err
+	}
+
+	link := getFrontendURL() + "/auth/reset-password?token=" + url.QueryEscape(token)
+	h.sendMail(mail.Message{
+		To:      user.Email,
+		Subject: "[Fleecy Cloud] 비밀번호 재설정",
+		Body: fmt.Sprintf("%s님, 비밀번호 재설정 요청을 받았습니다.\n\n"+
+			"아래 링크에서 새 비밀번호를 설정해주세요. 링크는 %s 동안 한 번만 사용할 수 있습니다.\n\n%s\n\n"+
+			"본인이 요청하지 않았다면 이 메일을 무시하세요. 비밀번호는 변경되지 않습니다.\n", user.Name, formatTTL(ttl), link),
+	})
+	return nil
+}
+
+// issueUserToken은 일회용 토큰을 발급하고 해시만 저장한 뒤 원문을 반환합니다
+func (h *LocalAuthHandler) issueUserToken(userID int64, purpose string, ttl time.Duration) (string, error) {
+	token, tokenHash, err := models.GenerateUserToken()
+	if err != nil {
+		return "", err
+	}
+	if _, err := h.userTokenRepo.CreateToken(userID, purpose, tokenHash, ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// sendMail은 메일을 비동기로 발송합니다 (응답 시간으로 계정 존재 여부가 드러나지 않도록)
+func (h *LocalAuthHandler) sendMail(msg mail.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := h.mailer.Send(ctx, msg); err != nil {
+			log.Printf("메일 발송 실패 (수신자: %s, 제목: %s): %v", msg.To, msg.Subject, err)
+		}
+	}()
+}
+
+// respondLoginLocked는 잠긴 계정의 로그인 요청에 429와 Retry-After를 응답합니다
+func (h *LocalAuthHandler) respondLoginLocked(c *gin.Context, wait time.Duration) {
+	seconds := ratelimit.RetryAfterSeconds(wait)
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       fmt.Sprintf("로그인 실패가 반복되어 잠시 잠겼습니다. %d초 후 다시 시도해주세요", seconds),
+		"retry_after": seconds,
+	})
+}
+
+func getFrontendURL() string {
+	if v := os.Getenv("FRONTEND_URL"); v != "" {
+		return strings.TrimRight(v, "/")
+	}
+	return "http://localhost:3000"
+}
+
+func getUserTokenTTL(key string, fallback time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+		log.Printf("%s 값이 올바르지 않아 기본값(%s)을 사용합니다: %q", key, fallback, v)
+	}
+	return fallback
+}
+
+func formatTTL(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		return fmt.Sprintf("%d시간", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%d분", int(d.Round(time.Minute)/time.Minute))
+}
+
 // 비밀번호 유효성 검사
 func (h *LocalAuthHandler) validatePassword(password string) error {
 	var (
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/mail"
+	"github.com/Mungge/Fleecy-Cloud/services/ratelimit"
+)
+
+// newTestDB는 테스트마다 분리된 메모리 SQLite DB를 생성합니다
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", regexp.MustCompile(`\W`).ReplaceAllString(t.Name(), "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.User{}, &models.UserToken{}, &models.RefreshToken{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+type localAuthEnv struct {
+	t       *testing.T
+	db      *gorm.DB
+	users   *repository.UserRepository
+	mailer  *mail.MemoryMailer
+	handler *LocalAuthHandler
+	router  *gin.Engine
+}
+
+func newLocalAuthEnv(t *testing.T) *localAuthEnv {
+	gin.SetMode(gin.TestMode)
+	db := newTestDB(t)
+	env := &localAuthEnv{
+		t:      t,
+		db:     db,
+		users:  repository.NewUserRepository(db),
+		mailer: mail.NewMemoryMailer(),
+	}
+	env.handler = NewLocalAuthHandler(env.users, repository.NewRefreshTokenRepository(db), repository.NewUserTokenRepository(db),
+		env.mailer, ratelimit.NewLoginGuard(ratelimit.NewMemoryStore(), ratelimit.DefaultLockoutPolicy()))
+
+	env.router = gin.New()
+	env.router.POST("/reset-password", env.handler.ResetPasswordHandler)
+	env.router.POST("/reset-password/confirm", env.handler.ConfirmPasswordResetHandler)
+	env.router.POST("/verify-email", env.handler.VerifyEmailHandler)
+	env.router.POST("/verify-email/resend", env.handler.ResendVerificationHandler)
+	return env
+}
+
+func (e *localAuthEnv) createUser(email string, verified bool) *models.User {
+	e.t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("Old-password1"), bcrypt.MinCost)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	user := &models.User{Name: "tester", Email: email, PasswordHash: string(hash)}
+	if verified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := e.users.CreateUser(user); err != nil {
+		e.t.Fatal(err)
+	}
+	return user
+}
+
+func (e *localAuthEnv) post(path string, body interface{}) int {
+	e.t.Helper()
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	return w.Code
+}
+
+var mailTokenPattern = regexp.MustCompile(`token=([0-9a-f]+)`)
+
+// waitForToken은 비동기로 발송된 n번째 메일을 기다려 링크의 토큰을 꺼냅니다
+func (e *localAuthEnv) waitForToken(to string, n int) string {
+	e.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var received []mail.Message
+		for _, msg := range e.mailer.Sent() {
+			if msg.To == to {
+				received = append(received, msg)
+			}
+		}
+		if len(received) >= n {
+			match := mailTokenPattern.FindStringSubmatch(received[n-1].Body)
+			if match == nil {
+				e.t.Fatalf("mail without token link: %q", received[n-1].Body)
+			}
+			return match[1]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	e.t.Fatalf("no mail #%d to %s", n, to)
+	return ""
+}
+
+func (e *localAuthEnv) reload(user *models.User) *models.User {
+	e.t.Helper()
+	reloaded, err := e.users.GetUserByID(user.ID)
+	if err != nil || reloaded == nil {
+		e.t.Fatalf("reload user: %v", err)
+	}
+	return reloaded
+}
+
+func TestPasswordResetFlow(t *testing.T) {
+	env := newLocalAuthEnv(t)
+	user := env.createUser("reset@example.com", false)
+	sessions := repository.NewRefreshTokenRepository(env.db)
+	if _, err := sessions.CreateRefreshToken(user.ID, "session-token", 7); err != nil {
+		t.Fatal(err)
+	}
+
+	// 존재하지 않는 계정도 같은 응답, 메일은 발송하지 않음
+	if code := env.post("/reset-password", gin.H{"email": "nobody@example.com"}); code != http.StatusOK {
+		t.Fatalf("reset unknown email: %d", code)
+	}
+	if code := env.post("/reset-password", gin.H{"email": user.Email}); code != http.StatusOK {
+		t.Fatalf("reset: %d", code)
+	}
+	token := env.waitForToken(user.Email, 1)
+	if len(env.mailer.Sent()) != 1 {
+		t.Fatalf("sent = %d mails, want 1", len(env.mailer.Sent()))
+	}
+
+	// 정책에 맞지 않는 비밀번호는 토큰을 소진하지 않음
+	if code := env.post("/reset-password/confirm", gin.H{"token": token, "new_password": "weakpassword"}); code != http.StatusBadRequest {
+		t.Fatalf("weak password: %d", code)
+	}
+	if code := env.post("/reset-password/confirm", gin.H{"token": token, "new_password": "New-password1"}); code != http.StatusOK {
+		t.Fatalf("confirm: %d", code)
+	}
+
+	updated := env.reload(user)
+	if bcrypt.CompareHashAndPassword([]byte(updated.PasswordHash), []byte("New-password1")) != nil {
+		t.Fatal("password was not changed")
+	}
+	if !updated.IsEmailVerified() {
+		t.Fatal("reset via mail link must verify the email")
+	}
+	if active, _ := sessions.GetUserRefreshTokens(user.ID); len(active) != 0 {
+		t.Fatalf("sessions not revoked: %d active", len(active))
+	}
+
+	// 토큰은 한 번만 사용 가능
+	if code := env.post("/reset-password/confirm", gin.H{"token": token, "new_password": "Other-password1"}); code != http.StatusBadRequest {
+		t.Fatalf("reused token: %d", code)
+	}
+}
+
+func TestEmailVerificationFlow(t *testing.T) {
+	env := newLocalAuthEnv(t)
+	user := env.createUser("verify@example.com", false)
+
+	if code := env.post("/verify-email/resend", gin.H{"email": user.Email}); code != http.StatusOK {
+		t.Fatalf("resend: %d", code)
+	}
+	first := env.waitForToken(user.Email, 1)
+	if code := env.post("/verify-email/resend", gin.H{"email": user.Email}); code != http.StatusOK {
+		t.Fatalf("resend again: %d", code)
+	}
+	second := env.waitForToken(user.Email, 2)
+
+	// 다시 발송하면 이전 링크는 무효
+	if code := env.post("/verify-email", gin.H{"token": first}); code != http.StatusBadRequest {
+		t.Fatalf("superseded token: %d", code)
+	}
+	if code := env.post("/verify-email", gin.H{"token": "not-a-token"}); code != http.StatusBadRequest {
+		t.Fatalf("unknown token: %d", code)
+	}
+	if code := env.post("/verify-email", gin.H{"token": second}); code != http.StatusOK {
+		t.Fatalf("verify: %d", code)
+	}
+	if !env.reload(user).IsEmailVerified() {
+		t.Fatal("email was not verified")
+	}
+
+	// 인증된 계정과 없는 계정은 같은 응답, 메일은 발송하지 않음
+	sent := len(env.mailer.Sent())
+	for _, email := range []string{user.Email, "nobody@example.com"} {
+		if code := env.post("/verify-email/resend", gin.H{"email": email}); code != http.StatusOK {
+			t.Fatalf("resend to %s: %d", email, code)
+		}
+	}
+	if len(env.mailer.Sent()) != sent {
+		t.Fatal("resend must not mail verified or unknown accounts")
+	}
+}
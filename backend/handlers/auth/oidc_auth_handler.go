@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/oidc"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oidcFlowCookie     = "oidc_flow"
+	oidcFlowCookiePath = "/api/auth/oidc"
+	oidcFlowTTL        = 10 * time.Minute
+)
+
+// OIDCAuthHandler는 설정된 OIDC 제공자(Keycloak, Azure AD 등)를 통한 로그인을 처리합니다
+type OIDCAuthHandler struct {
+	*BaseAuthHandler
+	providers *oidc.Registry
+	orgRepo   *repository.OrganizationRepository
+}
+
+// NewOIDCAuthHandler는 새로운 OIDCAuthHandler 인스턴스를 생성합니다
+func NewOIDCAuthHandler(userRepo *repository.UserRepository, refreshTokenRepo *repository.RefreshTokenRepository, orgRepo *repository.OrganizationRepository, providers *oidc.Registry) *OIDCAuthHandler {
+	return &OIDCAuthHandler{
+		BaseAuthHandler: NewBaseAuthHandler(userRepo, refreshTokenRepo),
+		providers:       providers,
+		orgRepo:         orgRepo,
+	}
+}
+
+// oidcFlow는 인가 요청부터 콜백까지 쿠키로 보관하는 값입니다
+type oidcFlow struct {
+	provider     string
+	state        string
+	nonce        string
+	codeVerifier string
+	redirectURL  string
+}
+
+func (f oidcFlow) encode() string {
+	// state/nonce/verifier는 base64url이라 '.'을 포함하지 않음, redirectURL은 마지막에 둠
+	return strings.Join([]string{f.provider, f.state, f.nonce, f.codeVerifier, url.QueryEscape(f.redirectURL)}, ".")
+}
+
+func decodeOIDCFlow(value string) (oidcFlow, bool) {
+	parts := strings.SplitN(value, ".", 5)
+	if len(parts) != 5 {
+		return oidcFlow{}, false
+	}
+	redirectURL, err := url.QueryUnescape(parts[4])
+	if err != nil {
+		return oidcFlow{}, false
+	}
+	return oidcFlow{provider: parts[0], state: parts[1], nonce: parts[2], codeVerifier: parts[3], redirectURL: redirectURL}, true
+}
+
+// ListProvidersHandler는 로그인 화면에 표시할 OIDC 제공자 목록을 반환합니다
+func (h *OIDCAuthHandler) ListProvidersHandler(c *gin.Context) {
+	providers := h.providers.List()
+	result := make([]gin.H, 0, len(providers))
+	for _, p := range providers {
+		result = append(result, gin.H{
+			"name":         p.Name(),
+			"display_name": p.DisplayName(),
+			"login_url":    "/api/auth/oidc/" + p.Name() + "/login",
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// LoginHandler는 PKCE와 nonce를 생성해 쿠키에 보관하고 IdP 인가 페이지로 리다이렉트합니다
+func (h *OIDCAuthHandler) LoginHandler(c *gin.Context) {
+	provider, ok := h.providers.Get(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "지원하지 않는 로그인 제공자입니다"})
+		return
+	}
+
+	redirectURL := c.Query("redirect_url")
+	if redirectURL != "" && !strings.HasPrefix(redirectURL, getFrontendURL()+"/") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "허용되지 않은 redirect_url입니다"})
+		return
+	}
+
+	flow := oidcFlow{provider: provider.Name(), redirectURL: redirectURL}
+	for _, target := range []*string{&flow.state, &flow.nonce, &flow.codeVerifier} {
+		value, err := oidc.RandomToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "로그인 요청 생성에 실패했습니다"})
+			return
+		}
+		*target = value
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	authURL, err := provider.AuthCodeURL(ctx, flow.state, flow.nonce, flow.codeVerifier)
+	if err != nil {
+		log.Printf("OIDC 인가 URL 생성 실패 (제공자: %s): %v", provider.Name(), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "로그인 제공자에 연결할 수 없습니다"})
+		return
+	}
+
+	// IdP에서 돌아오는 요청은 교차 사이트 이동이므로 Lax로 설정해야 콜백에 쿠키가 전달됨
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcFlowCookie, flow.encode(), int(oidcFlowTTL.Seconds()), oidcFlowCookiePath, "", isProduction(), true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// CallbackHandler는 state를 확인하고 코드를 교환한 뒤 ID 토큰을 검증해 로그인 처리합니다
+func (h *OIDCAuthHandler) CallbackHandler(c *gin.Context) {
+	audit.Annotate(c, "auth.login.oidc", "users", "")
+	audit.AddDetail(c, "provider", c.Param("provider"))
+
+	provider, ok := h.providers.Get(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "지원하지 않는 로그인 제공자입니다"})
+		return
+	}
+
+	cookie, err := c.Cookie(oidcFlowCookie)
+	// 흐름 쿠키는 한 번만 사용
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcFlowCookie, "", -1, oidcFlowCookiePath, "", isProduction(), true)
+	flow, valid := decodeOIDCFlow(cookie)
+	if err != nil || !valid || flow.provider != provider.Name() || flow.state != c.Query("state") {
+		log.Printf("OIDC 상태 검증 실패 (제공자: %s)", provider.Name())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 상태 값입니다"})
+		return
+	}
+
+	if idpErr := c.Query("error"); idpErr != "" {
+		log.Printf("OIDC 인가 거부 (제공자: %s): %s %s", provider.Name(), idpErr, c.Query("error_description"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "로그인 제공자가 인증을 거부했습니다: " + idpErr})
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "인증 코드가 없습니다"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	token, err := provider.Exchange(ctx, code, flow.codeVerifier)
+	if err != nil {
+		log.Printf("OIDC 토큰 교환 실패 (제공자: %s): %v", provider.Name(), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "토큰 교환 실패"})
+		return
+	}
+	claims, err := provider.VerifyIDToken(ctx, token.IDToken, flow.nonce)
+	if err != nil {
+		log.Printf("OIDC ID 토큰 검증 실패 (제공자: %s): %v", provider.Name(), err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "ID 토큰 검증에 실패했습니다"})
+		return
+	}
+	identity, err := provider.Identity(ctx, claims, token.AccessToken)
+	if err != nil {
+		log.Printf("OIDC 사용자 정보 매핑 실패 (제공자: %s): %v", provider.Name(), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "사용자 정보 조회 실패"})
+		return
+	}
+	audit.AddDetail(c, "subject", identity.Subject)
+
+	if identity.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "로그인 제공자가 이메일을 제공하지 않았습니다"})
+		return
+	}
+	if !identity.EmailVerified {
+		c.JSON(http.StatusForbidden, gin.H{"error": "로그인 제공자에서 검증된 이메일이 필요합니다"})
+		return
+	}
+
+	// 사용자 조회 또는 생성
+	user, isNewUser, err := h.findOrCreateUser(identity.Email, identity.Name)
+	if err != nil {
+		respondFindOrCreateUserError(c, err)
+		return
+	}
+	audit.SetActor(c, user.ID)
+	audit.SetResourceID(c, fmt.Sprint(user.ID))
+
+	h.syncGroupRole(provider, user, identity.Groups)
+
+	// 토큰 쌍 생성 및 저장
+	_, refreshTokenString, err := h.generateAndStoreTokenPair(user.ID, user.Email, user.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "토큰 생성 실패: " + err.Error()})
+		return
+	}
+
+	// Access Token은 URL로 전달하지 않고, 프론트엔드가 리프레시 쿠키로 /api/auth/refresh를 호출해 발급받음
+	h.setSecureRefreshTokenCookie(c, refreshTokenString, getRefreshTokenExpirationDays())
+
+	if isNewUser {
+		log.Printf("OIDC(%s)를 통한 새 사용자 생성: %s", provider.Name(), user.Email)
+	} else {
+		log.Printf("OIDC(%s) 로그인 성공: %s", provider.Name(), user.Email)
+	}
+
+	redirectURL := flow.redirectURL
+	if redirectURL == "" {
+		redirectURL = getFrontendURL() + "/auth/oidc/callback"
+	}
+	separator := "?"
+	if strings.Contains(redirectURL, "?") {
+		separator = "&"
+	}
+	c.Redirect(http.StatusFound, redirectURL+separator+"provider="+url.QueryEscape(provider.Name()))
+}
+
+// syncGroupRole은 IdP 그룹에 매핑된 역할을 설정된 조직의 멤버 역할로 반영합니다
+// 매핑된 그룹이 없으면 기존 멤버십을 그대로 두며, 소유자(owner)의 역할은 변경하지 않습니다
+func (h *OIDCAuthHandler) syncGroupRole(provider *oidc.Provider, user *models.User, groups []string) {
+	orgID, role, ok := provider.RoleForGroups(groups)
+	if !ok {
+		return
+	}
+
+	org, err := h.orgRepo.GetOrganizationByID(orgID)
+	if err != nil || org == nil || org.Personal {
+		log.Printf("OIDC 그룹 역할 매핑 대상 조직을 사용할 수 없습니다 (제공자: %s, 조직: %d): %v", provider.Name(), orgID, err)
+		return
+	}
+
+	member, err := h.orgRepo.GetMember(orgID, user.ID)
+	if err != nil {
+		log.Printf("OIDC 그룹 역할 매핑 실패 (사용자: %d, 조직: %d): %v", user.ID, orgID, err)
+		return
+	}
+
+	switch {
+	case member == nil:
+		err = h.orgRepo.AddMember(&models.OrganizationMember{OrganizationID: orgID, UserID: user.ID, Role: role})
+	case member.Role == models.OrganizationRoleOwner || member.Role == role:
+		return
+	default:
+		err = h.orgRepo.UpdateMemberRole(orgID, user.ID, role)
+	}
+	if err != nil {
+		log.Printf("OIDC 그룹 역할 매핑 실패 (사용자: %d, 조직: %d): %v", user.ID, orgID, err)
+		return
+	}
+	log.Printf("OIDC(%s) 그룹 역할 반영: 사용자 %d, 조직 %d, 역할 %s", provider.Name(), user.ID, orgID, role)
+	audit.RecordSystemEvent(audit.SystemEvent{
+		Action:         "organization.member.role.sync",
+		ResourceType:   "organizations",
+		ResourceID:     fmt.Sprint(orgID),
+		OrganizationID: orgID,
+		UserID:         user.ID,
+		Detail:         map[string]interface{}{"provider": provider.Name(), "role": role},
+	})
+}
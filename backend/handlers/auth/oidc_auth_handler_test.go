@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/oidc"
+	"github.com/Mungge/Fleecy-Cloud/services/oidc/oidctest"
+)
+
+const oidcTestCallback = "http://app.test/api/auth/oidc/mock/callback"
+
+type oidcAuthEnv struct {
+	t      *testing.T
+	idp    *oidctest.MockIdP
+	users  *repository.UserRepository
+	orgs   *repository.OrganizationRepository
+	org    *models.Organization
+	router *gin.Engine
+}
+
+// newOIDCAuthEnv는 모의 IdP와 그를 가리키는 제공자 하나로 OIDC 핸들러를 구성합니다
+func newOIDCAuthEnv(t *testing.T, configure func(*oidc.ProviderConfig)) *oidcAuthEnv {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "oidc-test-secret-key-0123456789abcdef")
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.Organization{}, &models.OrganizationMember{}); err != nil {
+		t.Fatal(err)
+	}
+
+	idp, err := oidctest.StartMockIdP("fleecy", "mock-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(idp.Close)
+
+	env := &oidcAuthEnv{
+		t:     t,
+		idp:   idp,
+		users: repository.NewUserRepository(db),
+		orgs:  repository.NewOrganizationRepository(db),
+	}
+	env.org = &models.Organization{Name: "research"}
+	if err := db.Create(env.org).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	config := idp.ProviderConfig("mock", oidcTestCallback)
+	config.GroupRoles = &oidc.GroupRoleMapping{
+		OrganizationID: env.org.ID,
+		Roles:          map[string]string{"fl-admins": models.OrganizationRoleAdmin, "fl-viewers": models.OrganizationRoleViewer},
+	}
+	if configure != nil {
+		configure(&config)
+	}
+	registry, err := oidc.NewRegistry([]oidc.ProviderConfig{config}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewOIDCAuthHandler(env.users, repository.NewRefreshTokenRepository(db), env.orgs, registry)
+	env.router = gin.New()
+	env.router.GET("/api/auth/oidc/:provider/login", handler.LoginHandler)
+	env.router.GET("/api/auth/oidc/:provider/callback", handler.CallbackHandler)
+	return env
+}
+
+// authorize는 로그인 요청 후 IdP 인가까지 진행해 흐름 쿠키와 콜백 쿼리를 반환합니다
+func (e *oidcAuthEnv) authorize() (oidcFlow, url.Values) {
+	e.t.Helper()
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/mock/login", nil))
+	if w.Code != http.StatusFound {
+		e.t.Fatalf("login: %d %s", w.Code, w.Body.String())
+	}
+	var flow oidcFlow
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == oidcFlowCookie {
+			flow, _ = decodeOIDCFlow(cookie.Value)
+		}
+	}
+	if flow.state == "" {
+		e.t.Fatal("login did not set the flow cookie")
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(w.Header().Get("Location"))
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if resp.StatusCode != http.StatusFound || err != nil || !strings.HasPrefix(location.String(), oidcTestCallback) {
+		e.t.Fatalf("authorize: %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	return flow, location.Query()
+}
+
+func (e *oidcAuthEnv) callback(flow oidcFlow, query url.Values) *httptest.ResponseRecorder {
+	e.t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/mock/callback?"+query.Encode(), nil)
+	req.AddCookie(&http.Cookie{Name: oidcFlowCookie, Value: flow.encode()})
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+// login은 조작 없이 전체 흐름을 진행합니다
+func (e *oidcAuthEnv) login() *httptest.ResponseRecorder {
+	e.t.Helper()
+	flow, query := e.authorize()
+	return e.callback(flow, query)
+}
+
+func hasSession(w *httptest.ResponseRecorder) bool {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "refresh_token" && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func oidcUser(email string, verified interface{}, groups ...string) map[string]interface{} {
+	claims := map[string]interface{}{"sub": "sub-" + email, "email": email, "name": "OIDC User", "groups": groups}
+	if verified != nil {
+		claims["email_verified"] = verified
+	}
+	return claims
+}
+
+func TestOIDCCallbackLogsInAndMapsGroupRole(t *testing.T) {
+	env := newOIDCAuthEnv(t, nil)
+	env.idp.SetUser(oidcUser("researcher@example.com", true, "fl-viewers", "fl-admins"))
+
+	w := env.login()
+	if w.Code != http.StatusFound || !hasSession(w) {
+		t.Fatalf("login: %d %s", w.Code, w.Body.String())
+	}
+	user, err := env.users.GetUserByEmail("researcher@example.com")
+	if err != nil || user == nil || !user.IsEmailVerified() {
+		t.Fatalf("user = %+v, %v", user, err)
+	}
+	// 여러 그룹이 매핑되면 가장 높은 역할
+	member, err := env.orgs.GetMember(env.org.ID, user.ID)
+	if err != nil || member == nil || member.Role != models.OrganizationRoleAdmin {
+		t.Fatalf("member = %+v, %v", member, err)
+	}
+
+	// 다음 로그인에서 그룹이 바뀌면 역할도 갱신
+	env.idp.SetUser(oidcUser("researcher@example.com", true, "fl-viewers"))
+	if w := env.login(); w.Code != http.StatusFound {
+		t.Fatalf("second login: %d %s", w.Code, w.Body.String())
+	}
+	if member, _ := env.orgs.GetMember(env.org.ID, user.ID); member == nil || member.Role != models.OrganizationRoleViewer {
+		t.Fatalf("member after regroup = %+v", member)
+	}
+}
+
+func TestOIDCCallbackRejectsTamperedFlow(t *testing.T) {
+	env := newOIDCAuthEnv(t, nil)
+	env.idp.SetUser(oidcUser("flow@example.com", true))
+
+	cases := []struct {
+		name   string
+		tamper func(*oidcFlow, url.Values)
+		want   int
+	}{
+		{"state mismatch", func(_ *oidcFlow, q url.Values) { q.Set("state", "forged-state") }, http.StatusBadRequest},
+		{"missing cookie state", func(f *oidcFlow, _ url.Values) { f.state = "" }, http.StatusBadRequest},
+		{"nonce mismatch", func(f *oidcFlow, _ url.Values) { f.nonce = "forged-nonce" }, http.StatusUnauthorized},
+		{"wrong PKCE verifier", func(f *oidcFlow, _ url.Values) { f.codeVerifier = "forged-verifier" }, http.StatusBadGateway},
+	}
+	for _, tc := range cases {
+		flow, query := env.authorize()
+		tc.tamper(&flow, query)
+		if w := env.callback(flow, query); w.Code != tc.want {
+			t.Errorf("%s: status %d, want %d (%s)", tc.name, w.Code, tc.want, w.Body.String())
+		}
+	}
+	if user, _ := env.users.GetUserByEmail("flow@example.com"); user != nil {
+		t.Fatal("rejected callbacks must not create a user")
+	}
+}
+
+func TestOIDCCallbackRejectsForeignIDToken(t *testing.T) {
+	env := newOIDCAuthEnv(t, nil)
+	env.idp.SetUser(oidcUser("token@example.com", true))
+
+	cases := []struct {
+		name      string
+		overrides map[string]interface{}
+	}{
+		{"issuer", map[string]interface{}{"iss": "https://evil.example.com"}},
+		{"audience", map[string]interface{}{"aud": "other-client"}},
+		{"authorized party", map[string]interface{}{"aud": []string{"fleecy", "other-client"}, "azp": "other-client"}},
+		{"expired", map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()}},
+	}
+	for _, tc := range cases {
+		env.idp.SetIDTokenOverrides(tc.overrides)
+		if w := env.login(); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status %d, want 401 (%s)", tc.name, w.Code, w.Body.String())
+		}
+	}
+	env.idp.SetIDTokenOverrides(nil)
+	if w := env.login(); w.Code != http.StatusFound {
+		t.Fatalf("valid token: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestOIDCCallbackRequiresVerifiedEmail(t *testing.T) {
+	t.Run("claim false", func(t *testing.T) {
+		env := newOIDCAuthEnv(t, nil)
+		env.idp.SetUser(oidcUser("unverified@example.com", false))
+		if w := env.login(); w.Code != http.StatusForbidden {
+			t.Fatalf("status %d, want 403", w.Code)
+		}
+	})
+
+	t.Run("claim missing", func(t *testing.T) {
+		env := newOIDCAuthEnv(t, nil)
+		env.idp.SetUser(oidcUser("missing@example.com", nil))
+		if w := env.login(); w.Code != http.StatusForbidden {
+			t.Fatalf("status %d, want 403", w.Code)
+		}
+	})
+
+	// trust_email은 클레임이 없을 때만 적용되고, IdP가 명시적으로 false를 보내면 거부
+	t.Run("trust email", func(t *testing.T) {
+		env := newOIDCAuthEnv(t, func(c *oidc.ProviderConfig) { c.TrustEmail = true })
+		env.idp.SetUser(oidcUser("explicit@example.com", false))
+		if w := env.login(); w.Code != http.StatusForbidden {
+			t.Fatalf("explicit false: status %d, want 403", w.Code)
+		}
+		env.idp.SetUser(oidcUser("trusted@example.com", nil))
+		if w := env.login(); w.Code != http.StatusFound {
+			t.Fatalf("missing claim: status %d, want 302 (%s)", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestOIDCCallbackRefusesUnverifiedLocalAccount(t *testing.T) {
+	env := newOIDCAuthEnv(t, nil)
+	// 다른 사람이 피해자 이메일로 미리 가입해 둔 미인증 계정
+	squatter := &models.User{Name: "squatter", Email: "victim@example.com", PasswordHash: "hash"}
+	if err := env.users.CreateUser(squatter); err != nil {
+		t.Fatal(err)
+	}
+	env.idp.SetUser(oidcUser("victim@example.com", true))
+
+	w := env.login()
+	if w.Code != http.StatusConflict || hasSession(w) {
+		t.Fatalf("status %d, want 409 without a session (%s)", w.Code, w.Body.String())
+	}
+
+	// 인증을 마친 계정에는 연결
+	now := time.Now()
+	squatter.EmailVerifiedAt = &now
+	if err := env.users.UpdateUser(squatter); err != nil {
+		t.Fatal(err)
+	}
+	if w := env.login(); w.Code != http.StatusFound {
+		t.Fatalf("verified account: %d %s", w.Code, w.Body.String())
+	}
+}
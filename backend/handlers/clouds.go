@@ -9,7 +9,8 @@ import (
 
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
-	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -37,10 +38,13 @@ func NewCloudHandler(cloudRepo *repository.CloudRepository) *CloudHandler {
 // @Success 200 {object} map[string]interface{}
 // @Router /api/clouds [get]
 func (h *CloudHandler) GetClouds(c *gin.Context) {
-	// 세션에서 사용자 ID 가져오기
-	userID := utils.GetUserIDFromMiddleware(c)
+	// 현재 조직의 조회 권한 확인
+	principal, ok := authz.AuthorizeActive(c, authz.ActionView)
+	if !ok {
+		return
+	}
 
-	connections, err := h.cloudRepo.GetCloudConnectionsByUserID(userID)
+	connections, err := h.cloudRepo.GetCloudConnectionsByOrganizationID(principal.ActiveOrganizationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "클라우드 연결 목록 조회 실패"})
 		return
@@ -66,10 +70,14 @@ func (h *CloudHandler) AddCloud(c *gin.Context) {
 		return
 	}
 
-	userID:= utils.GetUserIDFromMiddleware(c)
-	
-	// 사용자 ID 설정
-	cloud.UserID = userID
+	principal, ok := authz.AuthorizeActive(c, authz.ActionManage)
+	if !ok {
+		return
+	}
+
+	// 생성자와 소유 조직 설정
+	cloud.UserID = principal.UserID
+	cloud.OrganizationID = principal.ActiveOrganizationID
 
 	// 클라우드 연결 테스트
 	if err := testCloudConnection(c.Request.Context(), cloud); err != nil {
@@ -83,6 +91,7 @@ func (h *CloudHandler) AddCloud(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "클라우드 연결 저장 실패"})
 		return
 	}
+	audit.Annotate(c, "cloud.create", "clouds", cloud.ID)
 
 	c.JSON(http.StatusOK, cloud)
 }
@@ -99,9 +108,6 @@ func (h *CloudHandler) AddCloud(c *gin.Context) {
 // @Router /api/clouds/{id} [delete]
 func (h *CloudHandler) DeleteCloud(c *gin.Context) {
 	id := c.Param("id")
-	
-	// 세션에서 사용자 ID 가져오기
-	userID := utils.GetUserIDFromMiddleware(c)
 
 	// 연결 조회
 	conn, err := h.cloudRepo.GetCloudConnectionByID(id)
@@ -114,9 +120,8 @@ func (h *CloudHandler) DeleteCloud(c *gin.Context) {
 		return
 	}
 
-	// 사용자 확인
-	if conn.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "권한이 없습니다"})
+	// 권한 확인
+	if !authz.Authorize(c, conn.OrganizationID, authz.ActionManage) {
 		return
 	}
 
@@ -277,8 +282,13 @@ func testCloudConnection(ctx context.Context, cloud models.CloudConnection) erro
 // @Failure 400 {object} map[string]string
 // @Router /api/clouds/upload [post]
 func (h *CloudHandler) UploadCloudCredential(c *gin.Context) {
-	// 세션에서 사용자 ID 가져오기
-	userID := utils.GetUserIDFromMiddleware(c)
+	audit.Annotate(c, "cloud.credential.upload", "clouds", "")
+
+	// 현재 조직의 리소스 생성 권한 확인
+	principal, ok := authz.AuthorizeActive(c, authz.ActionManage)
+	if !ok {
+		return
+	}
 
 	provider := c.PostForm("provider")
 	name := c.PostForm("name")
@@ -343,7 +353,8 @@ func (h *CloudHandler) UploadCloudCredential(c *gin.Context) {
 
 	// 클라우드 연결 생성
 	conn := &models.CloudConnection{
-		UserID:         userID,
+		UserID:         principal.UserID,
+		OrganizationID: principal.ActiveOrganizationID,
 		Provider:       provider,
 		Name:           name,
 		Region:         region,
@@ -370,6 +381,8 @@ func (h *CloudHandler) UploadCloudCredential(c *gin.Context) {
 		return
 	}
 
+	audit.SetResourceID(c, conn.ID)
+
 	// 응답에 ID와 상태를 포함하여 반환
 	c.JSON(http.StatusOK, gin.H{
 		"message": "클라우드 연결이 성공적으로 추가되었습니다",
@@ -395,8 +408,6 @@ func (h *CloudHandler) UploadCloudCredential(c *gin.Context) {
 // @Router /api/clouds/{id}/test [get]
 func (h *CloudHandler) TestCloudConnectionWithDetails(c *gin.Context) {
 	id := c.Param("id")
-	
-	userID := utils.GetUserIDFromMiddleware(c)
 
 	// 연결 조회
 	conn, err := h.cloudRepo.GetCloudConnectionByID(id)
@@ -409,9 +420,8 @@ func (h *CloudHandler) TestCloudConnectionWithDetails(c *gin.Context) {
 		return
 	}
 
-	// 사용자 확인
-	if conn.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "권한이 없습니다"})
+	// 권한 확인
+	if !authz.Authorize(c, conn.OrganizationID, authz.ActionOperate) {
 		return
 	}
 
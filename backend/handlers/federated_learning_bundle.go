@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services"
+)
+
+// bundleConfigFile은 번들 하이퍼파라미터와 모델 타입을 task.py에 전달하는 파일입니다 (집계자/참여자 공통)
+const bundleConfigFile = "bundle_config.json"
+
+// defaultLocalEpochs는 번들이 local-epochs를 지정하지 않았을 때 참여자 로컬 학습 에폭 수입니다
+const defaultLocalEpochs = 5
+
+// pyproject.toml 템플릿의 dependencies 배열 끝 (번들 의존성을 이 앞에 추가)
+const pyprojectDependenciesEnd = "\n]\n\n[tool.flwr.app]\n"
+
+// pyprojectConfigSection은 모델 타입과 번들 하이퍼파라미터를 쓰는 pyproject.toml 섹션입니다
+const pyprojectConfigSection = "[tool.flwr.app.config]"
+
+// builtinTaskBundle은 번들을 지정하지 않은 작업에 사용하는 내장 템플릿(COVID-19 CNN)입니다
+func builtinTaskBundle() *models.TaskBundle {
+	return &models.TaskBundle{
+		Name:  "builtin",
+		Files: map[string]string{models.TaskBundleEntryFile: taskTemplate},
+	}
+}
+
+// resolveTaskBundle은 연합학습 작업이 사용하는 번들 버전을 조회하고 내용 해시를 확인합니다
+// 번들을 지정하지 않은 기존 작업은 내장 템플릿을 사용합니다
+func (h *FederatedLearningHandler) resolveTaskBundle(fl *models.FederatedLearning) (*models.TaskBundle, error) {
+	if fl.TaskBundleID == nil || *fl.TaskBundleID == "" {
+		return builtinTaskBundle(), nil
+	}
+
+	bundle, err := h.taskBundleRepo.GetByID(*fl.TaskBundleID)
+	if err != nil {
+		return nil, fmt.Errorf("번들 조회 실패: %v", err)
+	}
+	if bundle == nil || bundle.OrganizationID != fl.OrganizationID {
+		return nil, fmt.Errorf("%w: 번들을 찾을 수 없습니다 (ID: %s)", services.ErrPermanentStepFailure, *fl.TaskBundleID)
+	}
+	if hash := bundle.ComputeContentHash(); hash != bundle.ContentHash {
+		return nil, fmt.Errorf("%w: 번들 %s v%d의 내용 해시가 일치하지 않습니다 (저장: %s, 계산: %s)",
+			services.ErrPermanentStepFailure, bundle.Name, bundle.Version, bundle.ContentHash, hash)
+	}
+	return bundle, nil
+}
+
+// renderBundlePyproject는 모델 타입, 번들 하이퍼파라미터와 의존성을 pyproject.toml에 반영합니다
+func renderBundlePyproject(content string, fl *models.FederatedLearning, bundle *models.TaskBundle) string {
+	if fl.ModelType != "" {
+		content = setPyprojectConfigValue(content, "model-type", tomlValue(fl.ModelType))
+	}
+
+	keys := make([]string, 0, len(bundle.Hyperparameters))
+	for key := range bundle.Hyperparameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		content = setPyprojectConfigValue(content, key, tomlValue(bundle.Hyperparameters[key]))
+	}
+
+	if len(bundle.Dependencies) > 0 {
+		var deps strings.Builder
+		for _, dep := range bundle.Dependencies {
+			fmt.Fprintf(&deps, "\n    %s,", tomlValue(dep))
+		}
+		content = strings.Replace(content, pyprojectDependenciesEnd, deps.String()+pyprojectDependenciesEnd, 1)
+	}
+	return content
+}
+
+// setPyprojectConfigValue는 [tool.flwr.app.config] 섹션 안의 키 값을 바꾸고, 없으면 섹션 끝에 추가합니다
+// 다른 섹션(address, name, version 등)에 같은 이름의 키가 있어도 건드리지 않습니다
+func setPyprojectConfigValue(content, key, value string) string {
+	entry := fmt.Sprintf("%s = %s", key, value)
+	body := strings.TrimRight(content, "\n")
+	trailing := content[len(body):]
+	lines := strings.Split(body, "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == pyprojectConfigSection {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return body + "\n\n" + pyprojectConfigSection + "\n" + entry + trailing
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "[") {
+			end = i
+			break
+		}
+	}
+	keyLine := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*=`)
+	for i := start + 1; i < end; i++ {
+		if keyLine.MatchString(lines[i]) {
+			lines[i] = entry
+			return strings.Join(lines, "\n") + trailing
+		}
+	}
+
+	// 섹션 끝의 빈 줄 앞에 추가
+	insert := end
+	for insert > start+1 && strings.TrimSpace(lines[insert-1]) == "" {
+		insert--
+	}
+	result := append([]string{}, lines[:insert]...)
+	result = append(result, entry)
+	result = append(result, lines[insert:]...)
+	return strings.Join(result, "\n") + trailing
+}
+
+// tomlValue는 검증된 하이퍼파라미터 값(숫자, 문자열, 불리언)을 TOML 값으로 변환합니다
+func tomlValue(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		// 정수 값은 TOML 정수로 써서 Python에서 int로 읽히도록 함 (예: local-epochs)
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(fmt.Sprint(v))
+		return `"` + escaped + `"`
+	}
+}
+
+// renderBundleConfig는 task.py가 읽을 수 있도록 번들 정보와 하이퍼파라미터를 JSON으로 직렬화합니다
+func renderBundleConfig(fl *models.FederatedLearning, bundle *models.TaskBundle) (string, error) {
+	config := map[string]interface{}{
+		"bundle":          bundle.Name,
+		"version":         bundle.Version,
+		"content_hash":    bundle.ContentHash,
+		"model_type":      fl.ModelType,
+		"hyperparameters": bundle.Hyperparameters,
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// bundleLocalEpochs는 번들의 local-epochs 하이퍼파라미터를 반환합니다 (없으면 기본값)
+func bundleLocalEpochs(bundle *models.TaskBundle) int {
+	if v, ok := bundle.Hyperparameters["local-epochs"].(float64); ok && v >= 1 {
+		return int(v)
+	}
+	return defaultLocalEpochs
+}
+
+// pipInstallArgs는 번들 의존성을 셸 명령 인수로 변환합니다
+// 의존성은 검증 단계에서 작은따옴표를 포함할 수 없으므로 작은따옴표로 감싸면 안전합니다
+func pipInstallArgs(deps []string) string {
+	var args strings.Builder
+	for _, dep := range deps {
+		args.WriteString(" '" + dep + "'")
+	}
+	return args.String()
+}
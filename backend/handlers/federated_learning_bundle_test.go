@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+// pyprojectSection은 content에서 header 섹션의 본문을 반환합니다
+func pyprojectSection(content, header string) string {
+	_, rest, ok := strings.Cut(content, header+"\n")
+	if !ok {
+		return ""
+	}
+	if i := strings.Index(rest, "\n["); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+func TestRenderBundlePyproject(t *testing.T) {
+	fl := &models.FederatedLearning{ModelType: "ResNet"}
+	bundle := &models.TaskBundle{
+		Hyperparameters: map[string]interface{}{
+			"local-epochs":  float64(7),
+			"learning-rate": 0.001,
+			"optimizer":     `adam"`,
+			// 다른 섹션에도 있는 키는 config 섹션에만 추가
+			"address":  "evil:9092",
+			"insecure": false,
+			"name":     "override",
+			"version":  "9.9.9",
+			"default":  "other-federation",
+		},
+		Dependencies: []string{"numpy>=1.26", "timm==1.0.9"},
+	}
+
+	rendered := renderBundlePyproject(pyprojectTemplate, fl, bundle)
+	config := pyprojectSection(rendered, pyprojectConfigSection)
+	for _, line := range []string{
+		`model-type = "ResNet"`,
+		"local-epochs = 7",
+		"learning-rate = 0.001",
+		`optimizer = "adam\""`,
+		`address = "evil:9092"`,
+		"insecure = false",
+		`version = "9.9.9"`,
+	} {
+		if !strings.Contains(config, line+"\n") && !strings.HasSuffix(config, line) {
+			t.Errorf("config section missing %q:\n%s", line, config)
+		}
+	}
+	if strings.Count(config, "local-epochs =") != 1 || strings.Count(config, "model-type =") != 1 {
+		t.Errorf("existing config keys must be replaced, not duplicated:\n%s", config)
+	}
+
+	unchanged := map[string]string{
+		"[project]":               `name = "flower-demo"` + "\n" + `version = "1.0.0"`,
+		"[tool.flwr.federations]": `default = "remote-federation"`,
+		"[tool.flwr.federations.remote-federation]": `address = "<HOST>:<PORT>"` + "\n" + "insecure = true",
+	}
+	for header, want := range unchanged {
+		if section := pyprojectSection(rendered, header); !strings.Contains(section, want) {
+			t.Errorf("%s was modified:\n%s", header, section)
+		}
+	}
+
+	deps := pyprojectSection(rendered, "[project]")
+	if !strings.Contains(deps, "    \"torchvision==0.22.1\",\n    \"numpy>=1.26\",\n    \"timm==1.0.9\",\n]") {
+		t.Errorf("dependencies not appended:\n%s", deps)
+	}
+}
+
+func TestSetPyprojectConfigValue(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"replace", "[tool.flwr.app.config]\nlr = 0.1\nseed = 1\n", "[tool.flwr.app.config]\nlr = 0.5\nseed = 1\n"},
+		{"append before next section", "[tool.flwr.app.config]\nseed = 1\n\n[other]\nlr = 0.1", "[tool.flwr.app.config]\nseed = 1\nlr = 0.5\n\n[other]\nlr = 0.1"},
+		{"missing section", "[project]\nlr = 0.1", "[project]\nlr = 0.1\n\n[tool.flwr.app.config]\nlr = 0.5"},
+	}
+	for _, tc := range cases {
+		if got := setPyprojectConfigValue(tc.content, "lr", "0.5"); got != tc.want {
+			t.Errorf("%s:\ngot  %q\nwant %q", tc.name, got, tc.want)
+		}
+	}
+}
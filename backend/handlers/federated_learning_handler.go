@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
 	"encoding/json"
-	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -22,6 +24,10 @@ import (
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
 	"github.com/Mungge/Fleecy-Cloud/services"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+	"github.com/Mungge/Fleecy-Cloud/services/modelregistry"
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
 	"github.com/Mungge/Fleecy-Cloud/utils"
 )
 
@@ -42,24 +48,49 @@ type FederatedLearningHandler struct {
 	repo              *repository.FederatedLearningRepository
 	participantRepo   *repository.ParticipantRepository
 	aggregatorRepo    *repository.AggregatorRepository
+	taskBundleRepo    *repository.TaskBundleRepository
+	cloudRepo         *repository.CloudRepository
 	sshKeypairService *services.SSHKeypairService
+	orchestrator      *services.FederatedLearningOrchestrator
+	modelRegistry     *modelregistry.Registry
+	agentRepo         *repository.ParticipantAgentRepository
 }
 
+const (
+	participantExecuteTimeoutSeconds = 600               // 참여자 로컬 학습 제한 시간 (10분)
+	participantExecuteRequestTimeout = 120 * time.Second // 실행 요청 응답 대기 시간
+)
+
 // NewFederatedLearningHandler는 새 FederatedLearningHandler 인스턴스를 생성합니다
-func NewFederatedLearningHandler(repo *repository.FederatedLearningRepository, participantRepo *repository.ParticipantRepository, aggregatorRepo *repository.AggregatorRepository, sshKeypairService *services.SSHKeypairService) *FederatedLearningHandler {
-	return &FederatedLearningHandler{
+func NewFederatedLearningHandler(repo *repository.FederatedLearningRepository, participantRepo *repository.ParticipantRepository, aggregatorRepo *repository.AggregatorRepository, taskBundleRepo *repository.TaskBundleRepository, cloudRepo *repository.CloudRepository, sshKeypairService *services.SSHKeypairService, stepRepo *repository.FederatedLearningStepRepository, modelRegistry *modelregistry.Registry, agentRepo *repository.ParticipantAgentRepository) *FederatedLearningHandler {
+	h := &FederatedLearningHandler{
 		repo:              repo,
 		participantRepo:   participantRepo,
 		aggregatorRepo:    aggregatorRepo,
+		taskBundleRepo:    taskBundleRepo,
+		cloudRepo:         cloudRepo,
 		sshKeypairService: sshKeypairService,
+		modelRegistry:     modelRegistry,
+		agentRepo:         agentRepo,
 	}
+	h.orchestrator = services.NewFederatedLearningOrchestrator(stepRepo, repo, &flStepExecutor{handler: h})
+	return h
+}
+
+// ResumeOrchestration은 서버 재시작 전에 완료되지 못한 연합학습 실행 단계를 재개합니다
+func (h *FederatedLearningHandler) ResumeOrchestration() {
+	h.orchestrator.ResumeUnfinished()
 }
 
 // GetFederatedLearnings는 사용자의 모든 연합학습 작업을 반환하는 핸들러입니다
 func (h *FederatedLearningHandler) GetFederatedLearnings(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-	// 사용자의 모든 연합학습 작업 조회
-	fls, err := h.repo.GetByUserID(userID)
+	principal, ok := authz.AuthorizeActive(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	// 활성 조직의 모든 연합학습 작업 조회
+	fls, err := h.repo.GetByOrganizationID(principal.ActiveOrganizationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 작업 조회에 실패했습니다"})
 		return
@@ -70,8 +101,6 @@ func (h *FederatedLearningHandler) GetFederatedLearnings(c *gin.Context) {
 
 // GetFederatedLearning은 특정 ID의 연합학습 작업을 반환하는 핸들러입니다
 func (h *FederatedLearningHandler) GetFederatedLearning(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 연합학습 ID 추출
 	id := c.Param("id")
 
@@ -86,9 +115,8 @@ func (h *FederatedLearningHandler) GetFederatedLearning(c *gin.Context) {
 		return
 	}
 
-	// 작업 소유자 확인
-	if fl.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업에 접근할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionView) {
 		return
 	}
 
@@ -112,13 +140,6 @@ func (h *FederatedLearningHandler) GetFederatedLearning(c *gin.Context) {
 
 // UpdateFederatedLearning은 연합학습 작업을 업데이트하는 핸들러입니다
 func (h *FederatedLearningHandler) UpdateFederatedLearning(c *gin.Context) {
-	// 사용자 ID 추출
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
-		return
-	}
-
 	// 경로 매개변수에서 연합학습 ID 추출
 	id := c.Param("id")
 
@@ -133,22 +154,22 @@ func (h *FederatedLearningHandler) UpdateFederatedLearning(c *gin.Context) {
 		return
 	}
 
-	// 작업 소유자 확인
-	if fl.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업을 수정할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionManage) {
 		return
 	}
 
 	// 요청 본문 파싱
 	var request struct {
-		Name         string   `json:"name"`
-		Description  string   `json:"description"`
-		Status       string   `json:"status"`
-		ModelType    string   `json:"modelType"`
-		Algorithm    string   `json:"algorithm"`
-		Rounds       int      `json:"rounds"`
-		Participants []string `json:"participants"`
-		Accuracy     string   `json:"accuracy"`
+		Name           string                 `json:"name"`
+		Description    string                 `json:"description"`
+		Status         string                 `json:"status"`
+		ModelType      string                 `json:"modelType"`
+		Algorithm      string                 `json:"algorithm"`
+		StrategyConfig *models.StrategyConfig `json:"strategyConfig"` // 알고리즘별 하이퍼파라미터
+		Rounds         int                    `json:"rounds"`
+		Participants   []string               `json:"participants"`
+		Accuracy       string                 `json:"accuracy"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -156,6 +177,16 @@ func (h *FederatedLearningHandler) UpdateFederatedLearning(c *gin.Context) {
 		return
 	}
 
+	// 상태는 허용된 전이만 가능 (완료 시간은 TransitionStatus가 기록)
+	statusChanged := request.Status != "" && models.NormalizeFLStatus(request.Status) != models.NormalizeFLStatus(fl.Status)
+	if statusChanged {
+		request.Status = models.NormalizeFLStatus(request.Status)
+		if err := models.ValidateFLStatusTransition(fl.Status, request.Status); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "status": fl.Status})
+			return
+		}
+	}
+
 	// 필드 업데이트
 	if request.Name != "" {
 		fl.Name = request.Name
@@ -163,20 +194,25 @@ func (h *FederatedLearningHandler) UpdateFederatedLearning(c *gin.Context) {
 	if request.Description != "" {
 		fl.Description = request.Description
 	}
-	if request.Status != "" {
-		fl.Status = request.Status
-
-		// 작업이 완료된 경우 완료 시간 설정
-		if request.Status == "완료" {
-			now := time.Now()
-			fl.CompletedAt = &now
-		}
-	}
 	if request.ModelType != "" {
 		fl.ModelType = request.ModelType
 	}
-	if request.Algorithm != "" {
-		fl.Algorithm = request.Algorithm
+	if request.Algorithm != "" || request.StrategyConfig != nil {
+		params := request.StrategyConfig
+		if params == nil && request.Algorithm == fl.Algorithm {
+			params = fl.StrategyConfig
+		}
+		algorithm := request.Algorithm
+		if algorithm == "" {
+			algorithm = fl.Algorithm
+		}
+		strategyConfig, err := buildStrategyConfig(algorithm, params)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 집계 전략 설정입니다: " + err.Error()})
+			return
+		}
+		fl.Algorithm = strategyConfig.Algorithm
+		fl.StrategyConfig = &strategyConfig
 	}
 	if request.Rounds > 0 {
 		fl.Rounds = request.Rounds
@@ -195,14 +231,41 @@ func (h *FederatedLearningHandler) UpdateFederatedLearning(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 작업 업데이트에 실패했습니다"})
 		return
 	}
+	if statusChanged {
+		principal := authz.PrincipalFromContext(c)
+		transition, err := h.repo.TransitionStatus(fl.ID, request.Status, "작업 정보 수정", &principal.UserID)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidFLStatusTransition) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 상태 변경에 실패했습니다"})
+			return
+		}
+		fl.Status = request.Status
+		if request.Status == models.FLStatusCompleted {
+			now := transition.CreatedAt
+			fl.CompletedAt = &now
+		}
+		audit.Annotate(c, "fl.status", "federated-learning", fl.ID)
+		audit.AddDetail(c, "from_status", transition.FromStatus)
+		audit.AddDetail(c, "to_status", transition.ToStatus)
+	}
+
+	// 작업이 끝나면 남은 체크포인트를 모델 레지스트리로 수집
+	if statusChanged && request.Status == models.FLStatusCompleted {
+		go func(fl *models.FederatedLearning) {
+			if _, err := h.modelRegistry.CollectCheckpoints(context.Background(), fl); err != nil {
+				log.Printf("완료된 작업 %s의 체크포인트 수집 실패: %v", fl.ID, err)
+			}
+		}(fl)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"data": fl})
 }
 
 // DeleteFederatedLearning은 연합학습 작업을 삭제
 func (h *FederatedLearningHandler) DeleteFederatedLearning(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 연합학습 ID 추출
 	id := c.Param("id")
 
@@ -217,12 +280,21 @@ func (h *FederatedLearningHandler) DeleteFederatedLearning(c *gin.Context) {
 		return
 	}
 
-	// 작업 소유자 확인
-	if fl.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업을 삭제할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionManage) {
 		return
 	}
 
+	// 실행 중인 학습은 집계자 서버와 참여자를 먼저 종료 (실패해도 삭제는 진행)
+	switch fl.Status {
+	case models.FLStatusStarting, models.FLStatusRunning, models.FLStatusPaused:
+		h.orchestrator.Cancel(fl.ID, "학습이 삭제되었습니다")
+		if err := h.stopAggregatorServer(fl); err != nil {
+			log.Printf("삭제할 작업 %s의 집계자 서버 중지 실패: %v", fl.ID, err)
+		}
+		h.stopParticipants(fl)
+	}
+
 	// DB에서 삭제
 	if err := h.repo.Delete(id); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 작업 삭제에 실패했습니다"})
@@ -234,11 +306,10 @@ func (h *FederatedLearningHandler) DeleteFederatedLearning(c *gin.Context) {
 
 // GetGlobalModels는 연합학습의 글로벌 모델 정보를 조회합니다
 func (h *FederatedLearningHandler) GetGlobalModels(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
 	id := c.Param("id")
 
 	fmt.Printf("=== 글로벌 모델 조회 요청 시작 ===\n")
-	fmt.Printf("사용자 ID: %d, 연합학습 ID: %s\n", userID, id)
+	fmt.Printf("연합학습 ID: %s\n", id)
 
 	// 연합학습 조회
 	fl, err := h.repo.GetByID(id)
@@ -253,10 +324,8 @@ func (h *FederatedLearningHandler) GetGlobalModels(c *gin.Context) {
 		return
 	}
 
-	// 권한 확인
-	if fl.UserID != userID {
-		fmt.Printf("권한 없음 - 요청자: %d, 소유자: %d\n", userID, fl.UserID)
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업에 접근할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionView) {
 		return
 	}
 
@@ -405,19 +474,8 @@ func (h *FederatedLearningHandler) getGlobalModelData(fl *models.FederatedLearni
 func (h *FederatedLearningHandler) getModelFileInfoFromAggregator(aggregator *models.Aggregator, fl *models.FederatedLearning) (map[int]map[string]interface{}, error) {
 	fmt.Printf("📁 모델 파일 정보 조회 시작\n")
 
-	// SSH 키페어 조회
-	keypairWithPrivateKey, err := h.sshKeypairService.GetKeypairWithPrivateKey(aggregator.ID)
-	if err != nil {
-		return nil, fmt.Errorf("SSH 키페어 조회 실패: %v", err)
-	}
-
-	// SSH 클라이언트 생성
-	sshClient := utils.NewSSHClient(
-		aggregator.PublicIP,
-		"22",
-		"ubuntu",
-		keypairWithPrivateKey.PrivateKey,
-	)
+	// 집계자 공유 SSH 연결
+	sshClient := h.sshKeypairService.AggregatorConnection(aggregator.ID, aggregator.PublicIP)
 
 	// SSH 연결 테스트
 	if err := sshClient.CheckConnection(); err != nil {
@@ -474,7 +532,7 @@ func (h *FederatedLearningHandler) getModelFileInfoFromAggregator(aggregator *mo
 }
 
 // parseModelFileInfo는 개별 모델 파일의 정보를 파싱합니다
-func (h *FederatedLearningHandler) parseModelFileInfo(sshClient *utils.SSHClient, filePath, flID string) (map[string]interface{}, int) {
+func (h *FederatedLearningHandler) parseModelFileInfo(sshClient *utils.SSHConnection, filePath, flID string) (map[string]interface{}, int) {
 	// 파일 크기 조회
 	sizeCmd := fmt.Sprintf("stat -c%%s %s", filePath)
 	sizeOutput, _, err := sshClient.ExecuteCommand(sizeCmd)
@@ -601,7 +659,6 @@ func getFloatValue(ptr *float64) float64 {
 
 // DownloadGlobalModel은 특정 라운드의 글로벌 모델 파일을 다운로드합니다
 func (h *FederatedLearningHandler) DownloadGlobalModel(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
 	
 	// 경로 매개변수 추출
 	flID := c.Param("id")
@@ -628,8 +685,8 @@ func (h *FederatedLearningHandler) DownloadGlobalModel(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "연합학습 작업을 찾을 수 없습니다"})
 		return
 	}
-	if fl.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업에 접근할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionView) {
 		return
 	}
 
@@ -662,19 +719,8 @@ func (h *FederatedLearningHandler) DownloadGlobalModel(c *gin.Context) {
 func (h *FederatedLearningHandler) downloadModelFileFromAggregator(c *gin.Context, aggregator *models.Aggregator, fl *models.FederatedLearning, round int, filename string) error {
 	fmt.Printf("📁 모델 파일 다운로드 시작 - Round: %d, File: %s\n", round, filename)
 
-	// SSH 키페어 조회
-	keypairWithPrivateKey, err := h.sshKeypairService.GetKeypairWithPrivateKey(aggregator.ID)
-	if err != nil {
-		return fmt.Errorf("SSH 키페어 조회 실패: %v", err)
-	}
-
-	// SSH 클라이언트 생성
-	sshClient := utils.NewSSHClient(
-		aggregator.PublicIP,
-		"22",
-		"ubuntu",
-		keypairWithPrivateKey.PrivateKey,
-	)
+	// 집계자 공유 SSH 연결
+	sshClient := h.sshKeypairService.AggregatorConnection(aggregator.ID, aggregator.PublicIP)
 
 	// SSH 연결 테스트
 	if err := sshClient.CheckConnection(); err != nil {
@@ -735,7 +781,7 @@ func (h *FederatedLearningHandler) downloadModelFileFromAggregator(c *gin.Contex
 }
 
 // findModelFilePath는 지정된 라운드와 파일명에 해당하는 모델 파일 경로를 찾습니다
-func (h *FederatedLearningHandler) findModelFilePath(sshClient *utils.SSHClient, workDir string, round int, filename string) (string, error) {
+func (h *FederatedLearningHandler) findModelFilePath(sshClient *utils.SSHConnection, workDir string, round int, filename string) (string, error) {
 	// 가능한 디렉토리들
 	searchDirs := []string{
 		fmt.Sprintf("%s/mlruns", workDir),
@@ -773,30 +819,19 @@ func (h *FederatedLearningHandler) findModelFilePath(sshClient *utils.SSHClient,
 	return "", fmt.Errorf("파일을 찾을 수 없습니다: %s (라운드 %d)", filename, round)
 }
 
-// downloadFileViaSSH는 SSH를 통해 원격 파일을 로컬 임시 파일로 다운로드합니다
-func (h *FederatedLearningHandler) downloadFileViaSSH(sshClient *utils.SSHClient, remotePath string) (*os.File, error) {
+// downloadFileViaSSH는 공유 SSH 연결의 SFTP로 원격 파일을 로컬 임시 파일로 다운로드합니다
+func (h *FederatedLearningHandler) downloadFileViaSSH(sshClient *utils.SSHConnection, remotePath string) (*os.File, error) {
 	// 임시 파일 생성
 	tempFile, err := os.CreateTemp("", "model_download_*")
 	if err != nil {
 		return nil, fmt.Errorf("임시 파일 생성 실패: %v", err)
 	}
 
-	// base64로 인코딩하여 안전하게 바이너리 파일 전송
-	base64Cmd := fmt.Sprintf("base64 %s", remotePath)
-	base64Content, _, err := sshClient.ExecuteCommand(base64Cmd)
-	if err != nil {
-		tempFile.Close()
-		os.Remove(tempFile.Name())
-		return nil, fmt.Errorf("파일 인코딩 실패: %v", err)
-	}
-
-	// base64 디코딩
-	decoder := base64.NewDecoder(base64.StdEncoding, strings.NewReader(base64Content))
-	_, err = io.Copy(tempFile, decoder)
-	if err != nil {
+	// SFTP로 바이너리 그대로 전송 (base64 인코딩 불필요)
+	if _, err := sshClient.DownloadFile(remotePath, tempFile); err != nil {
 		tempFile.Close()
 		os.Remove(tempFile.Name())
-		return nil, fmt.Errorf("파일 디코딩 실패: %v", err)
+		return nil, fmt.Errorf("파일 다운로드 실패: %v", err)
 	}
 
 	return tempFile, nil
@@ -816,9 +851,8 @@ func (h *FederatedLearningHandler) downloadFileViaSSH(sshClient *utils.SSHClient
 // @Failure 500 {object} map[string]string
 // @Router /api/federated-learning [post]
 func (h *FederatedLearningHandler) CreateFederatedLearning(c *gin.Context) {
-	userID, err := utils.GetUserIDFromContext(c)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
+	principal, ok := authz.AuthorizeActive(c, authz.ActionOperate)
+	if !ok {
 		return
 	}
 
@@ -838,10 +872,60 @@ func (h *FederatedLearningHandler) CreateFederatedLearning(c *gin.Context) {
 		return
 	}
 
+	strategyConfig, err := buildStrategyConfig(request.Algorithm, request.StrategyConfig)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 집계 전략 설정입니다: " + err.Error()})
+		return
+	}
+
+	// 클라우드 연결, 집계자, 참여자가 활성 조직에 속하는지 확인
+	orgID := principal.ActiveOrganizationID
+	cloudConnection, err := h.cloudRepo.GetCloudConnectionByID(request.CloudConnectionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "클라우드 연결 조회에 실패했습니다"})
+		return
+	}
+	if cloudConnection == nil || cloudConnection.OrganizationID != orgID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "클라우드 연결을 찾을 수 없습니다"})
+		return
+	}
+	aggregator, err := h.aggregatorRepo.GetAggregatorByID(request.AggregatorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "집계자 조회에 실패했습니다"})
+		return
+	}
+	if aggregator == nil || aggregator.OrganizationID != orgID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "집계자를 찾을 수 없습니다"})
+		return
+	}
+	if request.TaskBundleID != nil {
+		bundle, err := h.taskBundleRepo.GetByID(*request.TaskBundleID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "번들 조회에 실패했습니다"})
+			return
+		}
+		if bundle == nil || bundle.OrganizationID != orgID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "번들을 찾을 수 없습니다"})
+			return
+		}
+	}
+	for _, p := range request.Participants {
+		participant, err := h.participantRepo.GetByID(p.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "참여자 조회에 실패했습니다"})
+			return
+		}
+		if participant == nil || participant.OrganizationID != orgID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "참여자를 찾을 수 없습니다: " + p.ID})
+			return
+		}
+	}
+
 	// FederatedLearning 생성
 	federatedLearning := &models.FederatedLearning{
 		ID:                uuid.New().String(),
-		UserID:            userID,
+		UserID:            principal.UserID,
+		OrganizationID:    orgID,
 		CloudConnectionID: request.CloudConnectionID,
 		AggregatorID:      &request.AggregatorID,
 		Name:              request.Name,
@@ -849,8 +933,10 @@ func (h *FederatedLearningHandler) CreateFederatedLearning(c *gin.Context) {
 		Status:            "ready",
 		ParticipantCount:  len(request.Participants),
 		Rounds:            request.Rounds,
-		Algorithm:         request.Algorithm,
+		Algorithm:         strategyConfig.Algorithm,
+		StrategyConfig:    &strategyConfig,
 		ModelType:         request.ModelType,
+		TaskBundleID:      request.TaskBundleID,
 	}
 
 	// 참여자 ID 추출
@@ -865,235 +951,169 @@ func (h *FederatedLearningHandler) CreateFederatedLearning(c *gin.Context) {
 		return
 	}
 
+	// 실행 단계를 기록하고 오케스트레이터가 백그라운드에서 집계자/참여자 실행을 진행
+	log.Printf("연합학습 실행 오케스트레이션 시작 (ID: %s)", federatedLearning.ID)
+	if err := h.orchestrator.Start(federatedLearning, participantIDs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 실행 시작 실패: " + err.Error()})
+		return
+	}
+
 	// 응답 반환
 	response := CreateFederatedLearningResponse{
 		FederatedLearningID: federatedLearning.ID,
 		AggregatorID:        request.AggregatorID,
-		Status:              "ready",
+		Status:              federatedLearning.Status,
 	}
 
-	// 포트 9092 고정
-
-	// 참여자들과 집계자에게 연합학습 실행 요청 전송
-	fmt.Printf("🚀 고루틴 시작: 연합학습 실행 요청 전송 프로세스 시작 (ID: %s)\n", federatedLearning.ID)
-	go func() {
-		fmt.Printf("📍 고루틴 내부: sendFederatedLearningExecuteRequests 함수 호출 시작\n")
-		h.sendFederatedLearningExecuteRequests(federatedLearning, request.Participants)
-		fmt.Printf("✅ 고루틴 완료: sendFederatedLearningExecuteRequests 함수 호출 완료\n")
-	}()
+	audit.Annotate(c, "fl.start", "federated-learning", federatedLearning.ID)
+	audit.AddDetail(c, "aggregator_id", request.AggregatorID)
+	audit.AddDetail(c, "participant_ids", participantIDs)
+	if request.TaskBundleID != nil {
+		audit.AddDetail(c, "task_bundle_id", *request.TaskBundleID)
+	}
 
 	c.JSON(http.StatusCreated, gin.H{"data": response})
 }
 
-// sendFederatedLearningExecuteRequests는 집계자와 모든 참여자에게 연합학습 실행 요청을 보냅니다
-func (h *FederatedLearningHandler) sendFederatedLearningExecuteRequests(federatedLearning *models.FederatedLearning, participants []struct {
-	ID                string `json:"id"`
-	Name              string `json:"name"`
-	Status            string `json:"status"`
-	OpenstackEndpoint string `json:"openstack_endpoint,omitempty"`
-}) {
-	fmt.Printf("🔄 연합학습 실행 요청 프로세스 시작 - FL ID: %s\n", federatedLearning.ID)
-	fmt.Printf("📊 전달받은 참여자 수: %d명\n", len(participants))
-
-	// 1. 먼저 집계자에게 실행 요청 전송
-	fmt.Printf("1️⃣ 단계 1: 집계자 실행 요청 전송\n")
-	if federatedLearning.AggregatorID == nil {
-		fmt.Printf("❌ 집계자 ID가 설정되지 않았습니다\n")
-		return
-	}
+// sendExecuteRequestToParticipant는 개별 참여자 에이전트에게 서명한 연합학습 실행 요청을 보냅니다
+func (h *FederatedLearningHandler) sendExecuteRequestToParticipant(participant *models.Participant, federatedLearning *models.FederatedLearning) error {
 
-	fmt.Printf("🔍 집계자 조회 중 - ID: %s\n", *federatedLearning.AggregatorID)
-	aggregator, err := h.aggregatorRepo.GetAggregatorByID(*federatedLearning.AggregatorID)
-	if err != nil {
-		fmt.Printf("❌ 집계자 조회 실패: %v\n", err)
-		return
-	}
+	requestURL := participantagent.AgentURL(participant.OpenStackEndpoint, participantagent.ExecutePath)
 
-	if aggregator == nil {
-		fmt.Printf("❌ 집계자를 찾을 수 없습니다\n")
-		return
-	}
+	fmt.Printf("참여자 서버 URL: %s\n", requestURL)
 
-	fmt.Printf("✅ 집계자 조회 성공 - Name: %s, IP: %s\n", aggregator.Name, aggregator.PublicIP)
-	fmt.Printf("🚀 집계자에게 실행 요청 전송 중...\n")
-	if err := h.sendExecuteRequestToAggregator(aggregator, federatedLearning); err != nil {
-		fmt.Printf("❌ 집계자 실행 요청 실패: %v\n", err)
-		return // 집계자 실행 요청이 실패하면 전체 프로세스 중단
+	// 참여자 에이전트 비밀 키 (없으면 재시도해도 성공할 수 없음)
+	secret, err := h.agentRepo.GetSecret(participant.ID)
+	if err != nil {
+		return fmt.Errorf("참여자 에이전트 인증 정보 조회 실패: %v", err)
 	}
-	fmt.Printf("✅ 집계자 실행 요청 성공\n")
-
-	// 2. 집계자 실행 요청이 성공한 후, 집계자 서버가 준비될 때까지 대기
-	fmt.Printf("2️⃣ 단계 2: 집계자 서버 준비 상태 확인 중...\n")
-	if err := h.waitForAggregatorReady(aggregator); err != nil {
-		fmt.Printf("❌ 집계자 서버 준비 대기 실패: %v\n", err)
-		return
+	if secret == "" {
+		return fmt.Errorf("%w: 참여자 %s의 에이전트 비밀 키가 발급되지 않았습니다", services.ErrPermanentStepFailure, participant.Name)
 	}
 
-	fmt.Printf("✅ 집계자 서버 준비 완료!\n")
-	fmt.Printf("3️⃣ 단계 3: 참여자들에게 요청을 전송합니다.\n")
-	// 3. 집계자가 준비된 후 참여자들에게 실행 요청 전송
-	h.sendExecuteRequestToParticipants(federatedLearning, participants)
-	fmt.Printf("🎉 연합학습 실행 요청 프로세스 완료\n")
-}
-
-// sendExecuteRequestToParticipant는 개별 참여자에게 연합학습 실행 요청을 보냅니다
-func (h *FederatedLearningHandler) sendExecuteRequestToParticipant(participant *models.Participant, federatedLearning *models.FederatedLearning) error {
-
-	requestURL := fmt.Sprintf("%s:5000/api/fl/execute-local", participant.OpenStackEndpoint)
-
-	fmt.Printf("참여자 서버 URL: %s\n", requestURL)
-
 	// 집계자 주소 가져오기
 	aggregatorAddress, err := h.getAggregatorAddress(federatedLearning)
 	if err != nil {
 		return fmt.Errorf("집계자 주소 조회 실패: %v", err)
 	}
 
-	// 새로운 로컬 실행 API를 위한 페이로드 구성
-	payload := map[string]interface{}{
-		"server_address": aggregatorAddress,
-		"local_epochs":   5,   // 기본값 5로 설정 (COVID-19 데이터셋에 적합)
-		"timeout":        600, // 10분 타임아웃
-		"files": map[string]interface{}{
-			"client_app.py": clientAppTemplate,
-			"task.py":       taskTemplate,
-		},
+	// 작업이 사용하는 번들 버전 (없으면 내장 템플릿)
+	bundle, err := h.resolveTaskBundle(federatedLearning)
+	if err != nil {
+		return err
+	}
+	bundleConfig, err := renderBundleConfig(federatedLearning, bundle)
+	if err != nil {
+		return fmt.Errorf("번들 설정 생성 실패: %v", err)
+	}
+
+	// 플랫폼 클라이언트 앱 + 번들 파일 + 번들 설정
+	files := map[string]string{
+		"client_app.py":  clientAppTemplate,
+		bundleConfigFile: bundleConfig,
+	}
+	for name, content := range bundle.Files {
+		files[name] = content
 	}
 
-	// JSON 인코딩
-	jsonData, err := json.Marshal(payload)
+	// 같은 재시작 회차의 재전송은 같은 작업 ID (에이전트가 중복 실행하지 않음)
+	jobID := participantagent.JobID(federatedLearning.ID, federatedLearning.Attempt, participant.ID)
+	request := &participantagent.ExecuteRequest{
+		ProtocolVersion:     participantagent.ProtocolVersion,
+		JobID:               jobID,
+		FederatedLearningID: federatedLearning.ID,
+		ParticipantID:       participant.ID,
+		Attempt:             federatedLearning.Attempt,
+		ServerAddress:       aggregatorAddress,
+		LocalEpochs:         bundleLocalEpochs(bundle), // 번들 local-epochs (기본값 5)
+		Timeout:             participantExecuteTimeoutSeconds,
+		Files:               files,
+		Dependencies:        bundle.Dependencies,
+		CallbackURL:         participantagent.CallbackURL(participantAgentCallbackBaseURL(), participant.ID),
+	}
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("%w: 실행 요청이 올바르지 않습니다: %v", services.ErrPermanentStepFailure, err)
+	}
+
+	// 상태 알림은 전송한 작업 ID만 반영하므로 요청 전에 기록
+	previous, err := h.agentRepo.RecordDispatch(federatedLearning.ID, participant.ID, jobID)
 	if err != nil {
-		return fmt.Errorf("JSON 인코딩 실패: %v", err)
+		if errors.Is(err, repository.ErrAgentJobNotFound) {
+			return fmt.Errorf("%w: 참여자 %s가 연합학습 작업에 포함되어 있지 않습니다", services.ErrPermanentStepFailure, participant.Name)
+		}
+		return fmt.Errorf("작업 ID 기록 실패: %v", err)
+	}
+
+	// 재개된 단계: 에이전트가 이 작업의 상태를 이미 알렸으면 요청이 전달된 것이므로 다시 보내지 않음
+	// (알림이 없으면 전달 여부를 알 수 없어 재전송하며, 에이전트는 같은 job_id를 중복 실행하지 않음)
+	if previous.JobID == jobID && previous.LastSequence > 0 {
+		log.Printf("참여자 %s가 이미 작업 %s를 받았습니다 (상태: %s) - 재전송 생략", participant.ID, jobID, previous.Status)
+		return nil
 	}
 
 	// 요청 로깅
 	fmt.Printf("=== 참여자 %s에게 로컬 실행 요청 전송 ===\n", participant.ID)
 	fmt.Printf("요청 URL: %s\n", requestURL)
+	fmt.Printf("작업 ID: %s\n", jobID)
 	fmt.Printf("집계자 주소: %s\n", aggregatorAddress)
-	fmt.Printf("요청 페이로드: %s\n", string(jsonData))
-
-	// HTTP 요청 생성
-	req, err := http.NewRequest("POST", requestURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("HTTP 요청 생성 실패: %v", err)
-	}
-
-	// 헤더 설정
-	req.Header.Set("Content-Type", "application/json")
+	fmt.Printf("번들: %s v%d (파일 %d개)\n", bundle.Name, bundle.Version, len(files))
 
 	// HTTP 클라이언트 생성 및 요청 전송 (패키지 설치 시간 고려하여 타임아웃 증가)
-	client := &http.Client{
-		Timeout: 120 * time.Second, // 2분 타임아웃 (패키지 설치 + 초기 응답)
+	client, err := participantagent.NewHTTPClient(participantExecuteRequestTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: 에이전트 TLS 설정 오류: %v", services.ErrPermanentStepFailure, err)
 	}
 
 	fmt.Printf("HTTP 요청 전송 중...\n")
-	resp, err := client.Do(req)
+	statusCode, responseBody, err := participantagent.PostSigned(context.Background(), client, requestURL, secret, participant.ID, participantagent.AudienceAgent, request)
 	if err != nil {
 		fmt.Printf("❌ 참여자 %s에게 요청 전송 실패: %v\n", participant.ID, err)
-		return fmt.Errorf("HTTP 요청 전송 실패: %v", err)
+		return err
 	}
-	defer resp.Body.Close()
 
-	fmt.Printf("응답 상태 코드: %d\n", resp.StatusCode)
+	fmt.Printf("응답 상태 코드: %d\n", statusCode)
 
-	// 응답 본문 읽기 (디버깅용)
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("응답 본문 읽기 실패: %v\n", err)
-		responseBody = []byte("응답 본문 읽기 실패")
+	// 응답 상태 코드 확인 (인증/형식 오류는 재시도해도 같은 결과)
+	if statusCode < 200 || statusCode >= 300 {
+		fmt.Printf("❌ 참여자 %s 요청 실패: 상태 코드 %d\n", participant.ID, statusCode)
+		fmt.Printf("응답 본문: %s\n", string(responseBody))
+		err := fmt.Errorf("HTTP 요청 실패: 상태 코드 %d, 응답: %s", statusCode, string(responseBody))
+		if statusCode == http.StatusBadRequest || statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+			return fmt.Errorf("%w: %v", services.ErrPermanentStepFailure, err)
+		}
+		return err
 	}
 
-	// 응답 상태 코드 확인
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		fmt.Printf("❌ 참여자 %s 요청 실패: 상태 코드 %d\n", participant.ID, resp.StatusCode)
-		fmt.Printf("응답 본문: %s\n", string(responseBody))
-		return fmt.Errorf("HTTP 요청 실패: 상태 코드 %d, 응답: %s", resp.StatusCode, string(responseBody))
+	var response participantagent.JobResponse
+	if err := json.Unmarshal(responseBody, &response); err == nil && response.Duplicate {
+		fmt.Printf("참여자 %s가 이미 작업 %s를 받았습니다 (상태: %s)\n", participant.ID, jobID, response.State)
 	}
 
-	fmt.Printf("✅ 참여자 %s에게 로컬 실행 요청 전송 성공 (상태 코드: %d)\n", participant.ID, resp.StatusCode)
+	fmt.Printf("✅ 참여자 %s에게 로컬 실행 요청 전송 성공 (상태 코드: %d)\n", participant.ID, statusCode)
 	fmt.Printf("응답 본문: %s\n", string(responseBody))
 	fmt.Printf("=== 요청 완료 ===\n\n")
 	return nil
 }
 
-// sendExecuteRequestToParticipants는 모든 참여자에게 연합학습 실행 요청을 보냅니다
-func (h *FederatedLearningHandler) sendExecuteRequestToParticipants(federatedLearning *models.FederatedLearning, participants []struct {
-	ID                string `json:"id"`
-	Name              string `json:"name"`
-	Status            string `json:"status"`
-	OpenstackEndpoint string `json:"openstack_endpoint,omitempty"`
-}) {
-	fmt.Printf("🎯 참여자 요청 전송 시작: %d명의 참여자에게 연합학습 실행 요청을 전송합니다\n", len(participants))
-
-	if len(participants) == 0 {
-		fmt.Printf("⚠️ 경고: 참여자가 없습니다. 요청을 전송할 대상이 없습니다.\n")
-		return
-	}
-
-	for i, participant := range participants {
-		fmt.Printf("📋 참여자 %d/%d 처리 중 - ID: %s, Name: %s\n", i+1, len(participants), participant.ID, participant.Name)
-
-		// 참여자 정보 조회
-		participantData, err := h.participantRepo.GetByID(participant.ID)
-		if err != nil {
-			fmt.Printf("❌ 참여자 조회 실패 (ID: %s): %v\n", participant.ID, err)
-			continue
-		}
-
-		if participantData == nil {
-			fmt.Printf("❌ 참여자 데이터가 nil입니다 (ID: %s)\n", participant.ID)
-			continue
-		}
-
-		fmt.Printf("✅ 참여자 조회 성공 - Name: %s, Endpoint: %s\n", participantData.Name, participantData.OpenStackEndpoint)
-
-		// OpenStack 엔드포인트가 없으면 스킵
-		if participantData.OpenStackEndpoint == "" {
-			fmt.Printf("⚠️ 참여자 %s의 엔드포인트가 설정되지 않았습니다. 스킵합니다.\n", participantData.Name)
-			continue
-		}
-
-		// 연합학습 실행 요청 전송
-		fmt.Printf("🚀 참여자 %s에게 연합학습 실행 요청 전송 시작...\n", participantData.Name)
-		if err := h.sendExecuteRequestToParticipant(participantData, federatedLearning); err != nil {
-			fmt.Printf("❌ 참여자 %s에게 연합학습 실행 요청 전송 실패: %v\n", participantData.Name, err)
-		} else {
-			fmt.Printf("✅ 참여자 %s에게 연합학습 실행 요청 전송 성공\n", participantData.Name)
-		}
-	}
-
-	fmt.Printf("🏁 모든 참여자에 대한 요청 전송 프로세스 완료\n")
-}
-
-// sendExecuteRequestToAggregator는 집계자에게 SSH를 통해 연합학습 실행 요청을 보냅니다
-func (h *FederatedLearningHandler) sendExecuteRequestToAggregator(aggregator *models.Aggregator, federatedLearning *models.FederatedLearning) error {
-	fmt.Printf("🔧 집계자 실행 요청 시작 - Name: %s, IP: %s\n", aggregator.Name, aggregator.PublicIP)
-
+// newAggregatorSSHClient는 집계자 공유 SSH 연결을 확인한 뒤 반환합니다
+func (h *FederatedLearningHandler) newAggregatorSSHClient(aggregator *models.Aggregator) (*utils.SSHConnection, error) {
 	// 집계자 Public IP 확인
 	if aggregator.PublicIP == "" {
 		fmt.Printf("❌ 집계자 Public IP가 설정되지 않음\n")
-		return fmt.Errorf("집계자 %s의 Public IP가 설정되지 않았습니다", aggregator.Name)
+		return nil, fmt.Errorf("집계자 %s의 Public IP가 설정되지 않았습니다", aggregator.Name)
 	}
 	fmt.Printf("✅ 집계자 Public IP 확인 완료: %s\n", aggregator.PublicIP)
 
 	// SSH 키페어 조회
 	fmt.Printf("🔑 SSH 키페어 조회 중...\n")
-	keypairWithPrivateKey, err := h.sshKeypairService.GetKeypairWithPrivateKey(aggregator.ID)
-	if err != nil {
+	if _, err := h.sshKeypairService.GetKeypairByAggregatorID(aggregator.ID); err != nil {
 		fmt.Printf("❌ SSH 키페어 조회 실패: %v\n", err)
-		return fmt.Errorf("집계자 %s의 SSH 키페어 조회 실패: %v", aggregator.Name, err)
+		return nil, fmt.Errorf("집계자 %s의 SSH 키페어 조회 실패: %v", aggregator.Name, err)
 	}
 	fmt.Printf("✅ SSH 키페어 조회 성공\n")
 
-	// SSH 클라이언트 생성
-	fmt.Printf("🔗 SSH 클라이언트 생성 중...\n")
-	sshClient := utils.NewSSHClient(
-		aggregator.PublicIP,
-		"22",
-		"ubuntu",
-		keypairWithPrivateKey.PrivateKey,
-	)
-	fmt.Printf("✅ SSH 클라이언트 생성 완료\n")
+	// 집계자 공유 SSH 연결 (이미 연결되어 있으면 재사용)
+	sshClient := h.sshKeypairService.AggregatorConnection(aggregator.ID, aggregator.PublicIP)
 
 	// SSH 연결 테스트
 	fmt.Printf("🔌 SSH 연결 테스트 중... (IP: %s, 타임아웃: 30초)\n", aggregator.PublicIP)
@@ -1101,13 +1121,30 @@ func (h *FederatedLearningHandler) sendExecuteRequestToAggregator(aggregator *mo
 	if err := sshClient.CheckConnection(); err != nil {
 		elapsed := time.Since(startTime)
 		fmt.Printf("❌ SSH 연결 실패 (소요시간: %.2f초): %v\n", elapsed.Seconds(), err)
-		return fmt.Errorf("집계자 %s SSH 연결 실패: %v", aggregator.Name, err)
+		return nil, fmt.Errorf("집계자 %s SSH 연결 실패: %v", aggregator.Name, err)
 	}
 	elapsed := time.Since(startTime)
 	fmt.Printf("✅ SSH 연결 성공 (소요시간: %.2f초)\n", elapsed.Seconds())
 
+	return sshClient, nil
+}
+
+// aggregatorWorkDir는 집계자에서 연합학습 파일이 위치하는 작업 디렉토리를 반환합니다
+func aggregatorWorkDir(flID string) string {
+	return fmt.Sprintf("/home/ubuntu/fl-aggregator-%s", flID)
+}
+
+// uploadAggregatorBundle은 집계자에게 SSH를 통해 Flower 서버 실행 파일들을 업로드합니다
+func (h *FederatedLearningHandler) uploadAggregatorBundle(aggregator *models.Aggregator, federatedLearning *models.FederatedLearning) error {
+	log.Printf("집계자 번들 업로드 시작 - Name: %s, IP: %s", aggregator.Name, aggregator.PublicIP)
+
+	sshClient, err := h.newAggregatorSSHClient(aggregator)
+	if err != nil {
+		return err
+	}
+
 	// 작업 디렉토리 생성
-	workDir := fmt.Sprintf("/home/ubuntu/fl-aggregator-%s", federatedLearning.ID)
+	workDir := aggregatorWorkDir(federatedLearning.ID)
 	_, _, err = sshClient.ExecuteCommand(fmt.Sprintf("mkdir -p %s", workDir))
 	if err != nil {
 		return fmt.Errorf("작업 디렉토리 생성 실패: %v", err)
@@ -1125,6 +1162,20 @@ func (h *FederatedLearningHandler) sendExecuteRequestToAggregator(aggregator *mo
 	dynamicPyprojectContent = strings.ReplaceAll(dynamicPyprojectContent, "num-server-rounds = 10", fmt.Sprintf("num-server-rounds = %d", federatedLearning.Rounds))
 	dynamicPyprojectContent = strings.ReplaceAll(dynamicPyprojectContent, "address = \"<HOST>:<PORT>\"", fmt.Sprintf("address = \"%s\"", aggregatorAddress))
 
+	// 선택된 집계 알고리즘과 하이퍼파라미터를 서버 설정에 반영
+	strategyConfig, err := resolveStrategyConfig(federatedLearning)
+	if err != nil {
+		return fmt.Errorf("%w: 집계 전략 설정 오류: %v", services.ErrPermanentStepFailure, err)
+	}
+	dynamicPyprojectContent = strings.Replace(dynamicPyprojectContent, pyprojectAlgorithmLine, renderStrategyConfig(strategyConfig), 1)
+
+	// 작업이 사용하는 번들의 모델 타입, 하이퍼파라미터, 의존성 반영 (번들이 없으면 내장 템플릿)
+	bundle, err := h.resolveTaskBundle(federatedLearning)
+	if err != nil {
+		return err
+	}
+	dynamicPyprojectContent = renderBundlePyproject(dynamicPyprojectContent, federatedLearning, bundle)
+
 	err = sshClient.UploadFileContent(dynamicPyprojectContent, fmt.Sprintf("%s/pyproject.toml", workDir))
 	if err != nil {
 		return fmt.Errorf("pyproject.toml 파일 업로드 실패: %v", err)
@@ -1142,10 +1193,22 @@ func (h *FederatedLearningHandler) sendExecuteRequestToAggregator(aggregator *mo
 		return fmt.Errorf("서버 앱 파일 업로드 실패: %v", err)
 	}
 
-	// task.py 파일 업로드
-	err = sshClient.UploadFileContent(taskTemplate, fmt.Sprintf("%s/task.py", workDir))
+	// 번들 파일 업로드 (task.py 및 보조 모듈)
+	log.Printf("번들 업로드: %s v%d (%s)", bundle.Name, bundle.Version, bundle.ContentHash)
+	for _, name := range bundle.SortedFileNames() {
+		err = sshClient.UploadFileContent(bundle.Files[name], fmt.Sprintf("%s/%s", workDir, name))
+		if err != nil {
+			return fmt.Errorf("번들 파일 %s 업로드 실패: %v", name, err)
+		}
+	}
+
+	bundleConfig, err := renderBundleConfig(federatedLearning, bundle)
 	if err != nil {
-		return fmt.Errorf("task.py 파일 업로드 실패: %v", err)
+		return fmt.Errorf("번들 설정 생성 실패: %v", err)
+	}
+	err = sshClient.UploadFileContent(bundleConfig, fmt.Sprintf("%s/%s", workDir, bundleConfigFile))
+	if err != nil {
+		return fmt.Errorf("%s 파일 업로드 실패: %v", bundleConfigFile, err)
 	}
 
 	// client_app.py 파일을 작업 디렉토리에 직접 업로드
@@ -1184,7 +1247,7 @@ pip install --upgrade pip
 # 필수 Python 패키지 설치 (MLflow 포함)
 echo "필수 Python 패키지를 설치합니다..."
 pip install uv
-uv pip install flwr torch torchvision tomli scikit-learn mlflow
+uv pip install flwr torch torchvision tomli scikit-learn mlflow` + pipInstallArgs(bundle.Dependencies) + `
 
 # 설치된 패키지 확인
 echo "설치된 패키지 확인:"
@@ -1196,8 +1259,12 @@ echo "Python 패키지 설치가 완료되었습니다."
 echo "MLflow 서버를 시작합니다..."
 export MLFLOW_TRACKING_URI="file:./mlruns"
 export MLFLOW_EXPERIMENT_NAME="federated-learning-` + federatedLearning.ID + `"
-nohup mlflow server --backend-store-uri file:./mlruns --default-artifact-root ./mlruns --host 0.0.0.0 --port 5000 > mlflow.log 2>&1 &
-echo "MLflow 서버가 포트 5000에서 시작되었습니다."
+if nc -z localhost 5000; then
+    echo "MLflow 서버가 이미 실행 중입니다."
+else
+    nohup mlflow server --backend-store-uri file:./mlruns --default-artifact-root ./mlruns --host 0.0.0.0 --port 5000 > mlflow.log 2>&1 &
+    echo "MLflow 서버가 포트 5000에서 시작되었습니다."
+fi
 
 # MLflow 서버 시작 대기
 sleep 5
@@ -1214,10 +1281,22 @@ create_ready_file() {
     echo "✅ 서버 준비 상태 파일 생성됨"
 }
 
+# 체크포인트 재시작 설정 (재시작 API가 ` + warmStartEnvFile + `에 FL_INITIAL_CHECKPOINT, FL_START_ROUND를 기록)
+WARM_START_ARGS=""
+if [ -f ` + warmStartEnvFile + ` ]; then
+    source ` + warmStartEnvFile + `
+    WARM_START_ARGS="--initial-checkpoint $FL_INITIAL_CHECKPOINT --start-round $FL_START_ROUND"
+    echo "체크포인트 재시작: 라운드 $FL_START_ROUND 이후부터 학습합니다"
+fi
+rm -f server_ready.txt
+
 	// 서버 실행 후 준비 완료 표시
 (
-    source venv/bin/activate && \
-    python3 server_app.py --server-address 0.0.0.0:9092 --num-rounds ` + fmt.Sprintf("%d", federatedLearning.Rounds) + ` --min-fit-clients ` + fmt.Sprintf("%d", federatedLearning.ParticipantCount) + ` --min-available-clients ` + fmt.Sprintf("%d", federatedLearning.ParticipantCount) + ` &
+    source venv/bin/activate
+    python3 server_app.py --server-address 0.0.0.0:9092 --num-rounds ` + fmt.Sprintf("%d", federatedLearning.Rounds) + ` --min-fit-clients ` + fmt.Sprintf("%d", federatedLearning.ParticipantCount) + ` --min-available-clients ` + fmt.Sprintf("%d", federatedLearning.ParticipantCount) + ` $WARM_START_ARGS &
+
+    # 중지/일시정지 API가 신호를 보낼 서버 프로세스 ID 기록
+    echo $! > ` + serverPIDFile + `
     
     # 서버 프로세스 시작 후 충분한 대기 시간
     echo "Flower 서버 프로세스 시작 대기 중..."
@@ -1256,18 +1335,45 @@ create_ready_file() {
 		return fmt.Errorf("스크립트 권한 설정 실패: %v", err)
 	}
 
-	// 백그라운드에서 Flower 서버 실행
-	command := fmt.Sprintf("cd %s && nohup ./run_server.sh > flower_server.log 2>&1 &", workDir)
+	log.Printf("집계자 %s 번들 업로드 완료", aggregator.Name)
+	return nil
+}
+
+// startAggregatorServer는 업로드된 실행 스크립트로 집계자의 Flower 서버를 시작합니다
+func (h *FederatedLearningHandler) startAggregatorServer(ctx context.Context, aggregator *models.Aggregator, federatedLearning *models.FederatedLearning) error {
+	sshClient, err := h.newAggregatorSSHClient(aggregator)
+	if err != nil {
+		return err
+	}
+
+	workDir := aggregatorWorkDir(federatedLearning.ID)
+
+	// 재개된 단계가 같은 포트에 서버를 두 번 띄우지 않도록, 시작 스크립트나 서버 프로세스가 살아 있거나
+	// 포트가 이미 열려 있으면 실행을 건너뜀 (중지/재시작은 stopAggregatorServer가 먼저 서버를 종료)
+	command := fmt.Sprintf(`cd %[1]s || exit 1
+if [ -f %[2]s ] && kill -0 "$(cat %[2]s)" 2>/dev/null; then echo %[4]s; exit 0; fi
+if [ -f %[3]s ] && kill -0 "$(cat %[3]s)" 2>/dev/null; then echo %[4]s; exit 0; fi
+if nc -z localhost 9092; then echo %[4]s; exit 0; fi
+nohup ./run_server.sh > flower_server.log 2>&1 &
+echo $! > %[2]s`, workDir, serverLauncherPIDFile, serverPIDFile, serverAlreadyRunning)
 	stdout, stderr, err := sshClient.ExecuteCommand(command)
 	if err != nil {
 		return fmt.Errorf("flower 서버 실행 실패: %v, stdout: %s, stderr: %s", err, stdout, stderr)
 	}
+	if strings.TrimSpace(stdout) == serverAlreadyRunning {
+		log.Printf("집계자 %s의 Flower 서버가 이미 실행 중이므로 다시 시작하지 않습니다", aggregator.Name)
+		return nil
+	}
 
 	fmt.Printf("집계자 %s에서 Flower 서버 실행 성공\n", aggregator.Name)
 	fmt.Printf("서버가 완전히 시작될 때까지 잠시 대기합니다...\n")
 
 	// 서버 시작을 위한 초기 대기 시간 (30초)
-	time.Sleep(30 * time.Second)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(30 * time.Second):
+	}
 
 	return nil
 }
@@ -1296,7 +1402,7 @@ func (h *FederatedLearningHandler) getAggregatorAddress(federatedLearning *model
 }
 
 // waitForAggregatorReady는 집계자 서버가 준비될 때까지 대기합니다
-func (h *FederatedLearningHandler) waitForAggregatorReady(aggregator *models.Aggregator) error {
+func (h *FederatedLearningHandler) waitForAggregatorReady(ctx context.Context, aggregator *models.Aggregator, workDir string) error {
 	maxRetries := 40                  // 최대 40번 시도 (약 7분)
 	retryInterval := 10 * time.Second // 10초 간격
 
@@ -1305,18 +1411,25 @@ func (h *FederatedLearningHandler) waitForAggregatorReady(aggregator *models.Agg
 
 	// SSH 키페어 조회
 	fmt.Printf("🔑 대기 중 SSH 키페어 조회...\n")
-	keypairWithPrivateKey, err := h.sshKeypairService.GetKeypairWithPrivateKey(aggregator.ID)
-	if err != nil {
+	if _, err := h.sshKeypairService.GetKeypairByAggregatorID(aggregator.ID); err != nil {
 		return fmt.Errorf("SSH 키페어 조회 실패: %v", err)
 	}
 	fmt.Printf("✅ SSH 키페어 조회 성공\n")
 
+	// 40번 폴링하는 동안 하나의 공유 연결을 재사용
+	sshClient := h.sshKeypairService.AggregatorConnection(aggregator.ID, aggregator.PublicIP)
+
 	for i := 0; i < maxRetries; i++ {
-		sshClient := utils.NewSSHClient(aggregator.PublicIP, "22", "ubuntu", keypairWithPrivateKey.PrivateKey)
 		if sshClient != nil {
 			// 1. 로그 파일에서 Flower 서버 시작 확인
 			fmt.Printf("📋 로그 파일 확인 중... (시도 %d/%d)\n", i+1, maxRetries)
-			logOutput, logStderr, logErr := sshClient.ExecuteCommand("tail -20 ~/federated_learning/flower_server.log")
+			logOutput, logStderr, logErr := sshClient.ExecuteCommand(fmt.Sprintf("tail -20 %s/flower_server.log", workDir))
+
+			// 호스트 키 불일치는 재시도하지 않고 즉시 중단
+			var mismatch *utils.HostKeyMismatchError
+			if errors.As(logErr, &mismatch) {
+				return fmt.Errorf("집계자 호스트 키 검증 실패: %w", logErr)
+			}
 
 			if logErr != nil {
 				fmt.Printf("⚠️ 로그 파일 읽기 실패: %v, stderr: %s\n", logErr, logStderr)
@@ -1348,7 +1461,7 @@ func (h *FederatedLearningHandler) waitForAggregatorReady(aggregator *models.Agg
 			}
 
 			// 2. 상태 파일 존재 확인 (백업 방법)
-			_, _, fileErr := sshClient.ExecuteCommand("ls ~/federated_learning/server_ready.txt")
+			_, _, fileErr := sshClient.ExecuteCommand(fmt.Sprintf("ls %s/server_ready.txt", workDir))
 			if fileErr == nil {
 				fmt.Printf("✅ 집계자 서버 준비 완료! 상태 파일 확인됨 (시도 %d/%d)\n", i+1, maxRetries)
 				return nil
@@ -1370,7 +1483,11 @@ func (h *FederatedLearningHandler) waitForAggregatorReady(aggregator *models.Agg
 		fmt.Printf("⏳ 집계자 서버 준비 중... (시도 %d/%d)\n", i+1, maxRetries)
 
 		if i < maxRetries-1 {
-			time.Sleep(retryInterval)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryInterval):
+			}
 		}
 	}
 
@@ -1379,8 +1496,6 @@ func (h *FederatedLearningHandler) waitForAggregatorReady(aggregator *models.Agg
 
 // GetMLflowDashboardURL은 연합학습의 MLflow 대시보드 URL을 반환합니다
 func (h *FederatedLearningHandler) GetMLflowDashboardURL(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 연합학습 ID 추출
 	id := c.Param("id")
 
@@ -1395,9 +1510,8 @@ func (h *FederatedLearningHandler) GetMLflowDashboardURL(c *gin.Context) {
 		return
 	}
 
-	// 작업 소유자 확인
-	if fl.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업에 접근할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionView) {
 		return
 	}
 
@@ -1434,8 +1548,6 @@ func (h *FederatedLearningHandler) GetMLflowDashboardURL(c *gin.Context) {
 
 // GetMLflowMetrics는 연합학습의 MLflow 메트릭을 조회합니다
 func (h *FederatedLearningHandler) GetMLflowMetrics(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 연합학습 ID 추출
 	id := c.Param("id")
 
@@ -1450,9 +1562,8 @@ func (h *FederatedLearningHandler) GetMLflowMetrics(c *gin.Context) {
 		return
 	}
 
-	// 작업 소유자 확인
-	if fl.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업에 접근할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionView) {
 		return
 	}
 
@@ -1652,14 +1763,12 @@ func (h *FederatedLearningHandler) fetchMLflowMetrics(mlflowURL, experimentName
 
 // GetLatestMetrics는 최신 메트릭만 간단히 조회합니다 (폴링용)
 func (h *FederatedLearningHandler) GetLatestMetrics(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 연합학습 ID 추출
 	id := c.Param("id")
 
 	// DB에서 연합학습 조회
 	fl, err := h.repo.GetByID(id)
-	if err != nil || fl == nil || fl.UserID != userID {
+	if err != nil || fl == nil || !authz.PrincipalFromContext(c).Can(fl.OrganizationID, authz.ActionView) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "연합학습 작업을 찾을 수 없습니다"})
 		return
 	}
@@ -1803,13 +1912,15 @@ func (h *FederatedLearningHandler) fetchLatestMLflowMetrics(mlflowURL, experimen
 
 // FederatedLearning 생성 요청 구조 (AggregatorID 기반)
 type CreateFederatedLearningRequest struct {
-	AggregatorID      string `json:"aggregatorId" binding:"required"`
-	CloudConnectionID string `json:"cloudConnectionId" binding:"required"`
-	Name              string `json:"name" binding:"required"`
-	Description       string `json:"description"`
-	ModelType         string `json:"modelType" binding:"required"`
-	Algorithm         string `json:"algorithm" binding:"required"`
-	Rounds            int    `json:"rounds" binding:"required"`
+	AggregatorID      string                 `json:"aggregatorId" binding:"required"`
+	CloudConnectionID string                 `json:"cloudConnectionId" binding:"required"`
+	Name              string                 `json:"name" binding:"required"`
+	Description       string                 `json:"description"`
+	ModelType         string                 `json:"modelType" binding:"required"`
+	Algorithm         string                 `json:"algorithm" binding:"required"`
+	StrategyConfig    *models.StrategyConfig `json:"strategyConfig,omitempty"` // 알고리즘별 하이퍼파라미터 (생략 시 기본값)
+	TaskBundleID      *string                `json:"taskBundleId,omitempty"`   // 사용할 번들 버전 ID (생략 시 내장 템플릿)
+	Rounds            int                    `json:"rounds" binding:"required"`
 	Participants      []struct {
 		ID                string `json:"id"`
 		Name              string `json:"name"`
@@ -1827,20 +1938,14 @@ func (h *FederatedLearningHandler) GetFederatedLearningLogs(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	fmt.Printf("Authorization 헤더: %s\n", authHeader)
 
-	// Context에서 userID 확인
-	userIDInterface, exists := c.Get("userID")
-	if !exists {
-		fmt.Printf("userID가 Context에 없음 - 미들웨어가 실행되지 않았을 수 있음\n")
+	// Context에서 인증 주체 확인
+	principal := authz.PrincipalFromContext(c)
+	if principal == nil {
+		fmt.Printf("인증 주체가 Context에 없음 - 미들웨어가 실행되지 않았을 수 있음\n")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다. 미들웨어 오류"})
 		return
 	}
-
-	userID, ok := userIDInterface.(int64)
-	if !ok {
-		fmt.Printf("userID 타입 변환 실패: %T\n", userIDInterface)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증 정보가 올바르지 않습니다"})
-		return
-	}
+	userID := principal.UserID
 
 	fmt.Printf("사용자 ID: %d\n", userID)
 
@@ -1861,10 +1966,8 @@ func (h *FederatedLearningHandler) GetFederatedLearningLogs(c *gin.Context) {
 		return
 	}
 
-	// 작업 소유자 확인
-	if fl.UserID != userID {
-		fmt.Printf("권한 없음 - 요청자: %d, 소유자: %d\n", userID, fl.UserID)
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업에 접근할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionView) {
 		return
 	}
 
@@ -1890,8 +1993,6 @@ func (h *FederatedLearningHandler) GetFederatedLearningLogs(c *gin.Context) {
 
 // StreamFederatedLearningLogs는 연합학습 로그를 실시간으로 스트림하는 핸들러입니다
 func (h *FederatedLearningHandler) StreamFederatedLearningLogs(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 연합학습 ID 추출
 	id := c.Param("id")
 
@@ -1906,9 +2007,8 @@ func (h *FederatedLearningHandler) StreamFederatedLearningLogs(c *gin.Context) {
 		return
 	}
 
-	// 작업 소유자 확인
-	if fl.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업에 접근할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionView) {
 		return
 	}
 
@@ -1962,19 +2062,8 @@ func (h *FederatedLearningHandler) getAggregatorLogs(fl *models.FederatedLearnin
 		return nil, fmt.Errorf("집계자의 Public IP가 설정되지 않았습니다")
 	}
 
-	// SSH 키페어 조회
-	keypairWithPrivateKey, err := h.sshKeypairService.GetKeypairWithPrivateKey(aggregator.ID)
-	if err != nil {
-		return nil, fmt.Errorf("집계자 SSH 키페어 조회 실패: %v", err)
-	}
-
-	// SSH 클라이언트 생성
-	sshClient := utils.NewSSHClient(
-		aggregator.PublicIP,
-		"22",
-		"ubuntu",
-		keypairWithPrivateKey.PrivateKey,
-	)
+	// 집계자 공유 SSH 연결 (여러 대시보드의 로그 조회가 하나의 연결을 공유)
+	sshClient := h.sshKeypairService.AggregatorConnection(aggregator.ID, aggregator.PublicIP)
 
 	// 작업 디렉토리 경로 (federatedLearningID 사용)
 	workDir := fmt.Sprintf("/home/ubuntu/fl-aggregator-%s", fl.ID)
@@ -1999,8 +2088,6 @@ func (h *FederatedLearningHandler) getAggregatorLogs(fl *models.FederatedLearnin
 
 // SyncMLflowMetricsToDatabase는 MLflow 메트릭을 데이터베이스에 동기화합니다
 func (h *FederatedLearningHandler) SyncMLflowMetricsToDatabase(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 연합학습 ID 추출
 	id := c.Param("id")
 
@@ -2015,9 +2102,8 @@ func (h *FederatedLearningHandler) SyncMLflowMetricsToDatabase(c *gin.Context) {
 		return
 	}
 
-	// 작업 소유자 확인
-	if fl.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업에 접근할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionOperate) {
 		return
 	}
 
@@ -2061,8 +2147,6 @@ func (h *FederatedLearningHandler) SyncMLflowMetricsToDatabase(c *gin.Context) {
 
 // GetStoredTrainingHistory는 데이터베이스에 저장된 라운드별 학습 히스토리를 조회합니다
 func (h *FederatedLearningHandler) GetStoredTrainingHistory(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 연합학습 ID 추출
 	id := c.Param("id")
 
@@ -2077,9 +2161,8 @@ func (h *FederatedLearningHandler) GetStoredTrainingHistory(c *gin.Context) {
 		return
 	}
 
-	// 작업 소유자 확인
-	if fl.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 연합학습 작업에 접근할 권한이 없습니다"})
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionView) {
 		return
 	}
 
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+)
+
+// newTestDB는 테스트마다 분리된 메모리 SQLite DB를 생성합니다
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", regexp.MustCompile(`\W`).ReplaceAllString(t.Name(), "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.User{}, &models.CloudConnection{}, &models.Aggregator{}, &models.FederatedLearning{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestCreateFederatedLearningRejectsForeignCloudConnection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newTestDB(t)
+	for _, conn := range []*models.CloudConnection{
+		{ID: "conn-own", UserID: 1, OrganizationID: 1, Provider: "gcp", Name: "own"},
+		{ID: "conn-foreign", UserID: 2, OrganizationID: 2, Provider: "gcp", Name: "foreign"},
+	} {
+		if err := db.Create(conn).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := NewFederatedLearningHandler(repository.NewFederatedLearningRepository(db), repository.NewParticipantRepository(db),
+		repository.NewAggregatorRepository(db), repository.NewTaskBundleRepository(db), repository.NewCloudRepository(db), nil,
+		repository.NewFederatedLearningStepRepository(db), nil, nil)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		authz.SetPrincipal(c, authz.NewPrincipal(1, 1, []*models.OrganizationMember{{OrganizationID: 1, UserID: 1, Role: models.OrganizationRoleOperator}}))
+	})
+	router.POST("/api/federated-learning", handler.CreateFederatedLearning)
+
+	cases := []struct {
+		connectionID string
+		want         string
+	}{
+		{"conn-foreign", "클라우드 연결을 찾을 수 없습니다"},
+		{"conn-missing", "클라우드 연결을 찾을 수 없습니다"},
+		// 자기 조직의 연결은 통과하고 다음 검증(집계자)에서 멈춤
+		{"conn-own", "집계자를 찾을 수 없습니다"},
+	}
+	for _, tc := range cases {
+		body := fmt.Sprintf(`{"aggregatorId": "agg-missing", "cloudConnectionId": %q, "name": "fl", "modelType": "cnn",
+			"algorithm": "fedavg", "rounds": 3, "participants": []}`, tc.connectionID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/federated-learning", strings.NewReader(body)))
+
+		var resp struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if w.Code != http.StatusNotFound || resp.Error != tc.want {
+			t.Errorf("%s: status %d, error %q; want 404 %q", tc.connectionID, w.Code, resp.Error, tc.want)
+		}
+	}
+
+	var count int64
+	if err := db.Model(&models.FederatedLearning{}).Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("federated learnings created = %d, %v", count, err)
+	}
+}
@@ -0,0 +1,461 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+	"github.com/Mungge/Fleecy-Cloud/services/modelregistry"
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+// 집계자 작업 디렉토리의 실행 제어 파일 (run_server.sh와 공유)
+const (
+	serverPIDFile         = "server.pid"     // Flower 서버 프로세스 ID
+	serverLauncherPIDFile = "launcher.pid"   // run_server.sh 프로세스 ID (서버 PID 기록 전까지 시작 중 판단용)
+	warmStartEnvFile      = "warm_start.env" // 체크포인트 재시작 설정 (FL_INITIAL_CHECKPOINT, FL_START_ROUND)
+)
+
+// serverAlreadyRunning은 서버 시작 명령이 이미 실행 중인 서버를 발견했을 때의 출력입니다
+const serverAlreadyRunning = "already-running"
+
+// serverStopGraceSeconds는 SIGTERM 후 SIGKILL을 보내기 전까지 기다리는 시간입니다
+const serverStopGraceSeconds = 10
+
+// participantStopTimeout은 참여자 중지 요청 하나의 타임아웃입니다
+const participantStopTimeout = 15 * time.Second
+
+// restartCheckpointTimeout은 재시작 시 체크포인트 수집/복원에 허용하는 시간입니다
+// 클라이언트가 연결을 끊어도 전송 중인 체크포인트가 중간에 잘리지 않도록 요청 컨텍스트와 분리합니다
+const restartCheckpointTimeout = 10 * time.Minute
+
+// lifecycleRequest는 중지/일시정지/재개/재시작 요청 본문입니다 (생략 가능)
+type lifecycleRequest struct {
+	Reason string `json:"reason"`
+}
+
+// StopFederatedLearning은 학습을 중지하는 핸들러입니다
+// @Summary 연합학습 중지
+// @Description 실행 단계를 취소하고 집계자의 Flower 서버를 종료(SIGTERM, 유예 후 SIGKILL)한 뒤 참여자에게 중지를 요청합니다.
+// @Tags federated-learning
+// @Accept json
+// @Produce json
+// @Param id path string true "연합학습 ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /api/federated-learning/{id}/stop [post]
+func (h *FederatedLearningHandler) StopFederatedLearning(c *gin.Context) {
+	fl, reason, ok := h.beginLifecycleChange(c, models.FLStatusStopped, "사용자 요청으로 중지")
+	if !ok {
+		return
+	}
+
+	h.orchestrator.Cancel(fl.ID, "학습이 중지되었습니다")
+	if err := h.stopAggregatorServer(fl); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "집계자 서버 중지 실패: " + err.Error()})
+		return
+	}
+	participantErrors := h.stopParticipants(fl)
+
+	if !h.commitLifecycleChange(c, fl, models.FLStatusStopped, reason) {
+		return
+	}
+	audit.Annotate(c, "fl.stop", "federated-learning", fl.ID)
+	if len(participantErrors) > 0 {
+		audit.AddDetail(c, "participant_errors", participantErrors)
+	}
+
+	// 중지 시점까지의 체크포인트를 재시작에 쓸 수 있도록 수집
+	go func(fl *models.FederatedLearning) {
+		if _, err := h.modelRegistry.CollectCheckpoints(context.Background(), fl); err != nil {
+			log.Printf("중지된 작업 %s의 체크포인트 수집 실패: %v", fl.ID, err)
+		}
+	}(fl)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"status": fl.Status, "participant_errors": participantErrors}})
+}
+
+// PauseFederatedLearning은 집계자의 Flower 서버 프로세스를 SIGSTOP으로 일시정지하는 핸들러입니다
+// 참여자 에이전트에는 일시정지 요청이 없어 참여자의 Flower 클라이언트는 서버 응답을 기다리며 그대로 남습니다
+// 오래 멈추면 클라이언트의 gRPC 연결이 끊겨 재개 후 라운드가 실패할 수 있으므로 짧은 중단에만 사용하고,
+// 오래 멈춰야 하면 중지 후 체크포인트 재시작을 사용합니다
+// @Summary 연합학습 일시정지
+// @Description 집계자 서버만 일시정지하며 참여자는 멈추지 않습니다. 짧은 중단(수 분 이내)에만 사용하고, 오래 멈춰야 하면 중지 후 체크포인트에서 재시작하세요.
+// @Tags federated-learning
+// @Produce json
+// @Param id path string true "연합학습 ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /api/federated-learning/{id}/pause [post]
+func (h *FederatedLearningHandler) PauseFederatedLearning(c *gin.Context) {
+	h.signalLifecycleChange(c, models.FLStatusPaused, "STOP", "사용자 요청으로 일시정지", "fl.pause")
+}
+
+// ResumeFederatedLearning은 일시정지한 Flower 서버 프로세스를 SIGCONT로 재개하는 핸들러입니다
+// @Summary 연합학습 재개
+// @Tags federated-learning
+// @Produce json
+// @Param id path string true "연합학습 ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /api/federated-learning/{id}/resume [post]
+func (h *FederatedLearningHandler) ResumeFederatedLearning(c *gin.Context) {
+	h.signalLifecycleChange(c, models.FLStatusRunning, "CONT", "사용자 요청으로 재개", "fl.resume")
+}
+
+// RestartFederatedLearning은 중지/실패/완료된 학습을 라운드 체크포인트에서 다시 시작하는 핸들러입니다
+// @Summary 연합학습 체크포인트 재시작
+// @Description fromRound 라운드의 글로벌 모델(모델 레지스트리)로 초기화해 남은 라운드를 실행합니다. fromRound=0이면 처음부터 다시 학습합니다.
+// @Tags federated-learning
+// @Accept json
+// @Produce json
+// @Param id path string true "연합학습 ID"
+// @Param fromRound query int true "시작 체크포인트 라운드 (0이면 처음부터)"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /api/federated-learning/{id}/restart [post]
+func (h *FederatedLearningHandler) RestartFederatedLearning(c *gin.Context) {
+	fromRound, err := strconv.Atoi(c.Query("fromRound"))
+	if err != nil || fromRound < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "fromRound는 0 이상의 정수여야 합니다"})
+		return
+	}
+
+	fl, reason, ok := h.beginLifecycleChange(c, models.FLStatusStarting, fmt.Sprintf("라운드 %d 체크포인트에서 재시작", fromRound))
+	if !ok {
+		return
+	}
+	if fl.Rounds > 0 && fromRound >= fl.Rounds {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("fromRound는 전체 라운드 수(%d)보다 작아야 합니다", fl.Rounds)})
+		return
+	}
+
+	aggregator, err := (&flStepExecutor{handler: h}).loadAggregator(fl)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	sshClient, err := h.newAggregatorSSHClient(aggregator)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 이전 서버와 참여자 학습이 남아 있으면 종료
+	if err := h.stopAggregatorServer(fl); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "이전 집계자 서버 중지 실패: " + err.Error()})
+		return
+	}
+	if participantErrors := h.stopParticipants(fl); len(participantErrors) > 0 {
+		log.Printf("재시작 전 참여자 중지 요청 일부 실패 (%s): %v", fl.ID, participantErrors)
+	}
+
+	// 이전 회차 체크포인트를 레지스트리에 보존한 뒤 시작 체크포인트를 집계자에 준비
+	ctx, cancel := context.WithTimeout(context.Background(), restartCheckpointTimeout)
+	defer cancel()
+	if _, err := h.modelRegistry.CollectCheckpoints(ctx, fl); err != nil {
+		log.Printf("재시작 전 체크포인트 수집 실패 (%s): %v", fl.ID, err)
+	}
+	workDir := aggregatorWorkDir(fl.ID)
+	warmStart := "rm -f " + warmStartEnvFile
+	var source *models.ModelVersion
+	if fromRound > 0 {
+		checkpoint := fmt.Sprintf("restore/round-%03d.pt", fromRound)
+		source, err = h.modelRegistry.RestoreCheckpoint(ctx, fl, fromRound, workDir+"/"+checkpoint)
+		if err != nil {
+			if errors.Is(err, modelregistry.ErrCheckpointNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("라운드 %d 체크포인트를 찾을 수 없습니다", fromRound)})
+				return
+			}
+			c.JSON(http.StatusBadGateway, gin.H{"error": "체크포인트 준비 실패: " + err.Error()})
+			return
+		}
+		warmStart = fmt.Sprintf("printf 'FL_INITIAL_CHECKPOINT=%s\\nFL_START_ROUND=%d\\n' > %s", checkpoint, fromRound, warmStartEnvFile)
+	}
+
+	// 새 회차의 체크포인트가 섞이지 않도록 이전 회차 체크포인트 디렉토리를 옮김
+	command := fmt.Sprintf("cd %s && %s && if [ -d checkpoints ]; then mv checkpoints checkpoints-attempt-%d; fi", workDir, warmStart, fl.Attempt)
+	if stdout, stderr, err := sshClient.ExecuteCommand(command); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("재시작 준비 실패: %v, stdout: %s, stderr: %s", err, stdout, stderr)})
+		return
+	}
+
+	attempt, err := h.repo.IncrementAttempt(fl.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "재시작 횟수 저장 실패"})
+		return
+	}
+	fl.Attempt = attempt
+
+	participantIDs := make([]string, 0, len(fl.Participants))
+	for _, participant := range fl.Participants {
+		participantIDs = append(participantIDs, participant.ID)
+	}
+	principal := authz.PrincipalFromContext(c)
+	if err := h.orchestrator.Restart(fl, participantIDs, reason, principal.UserID); err != nil {
+		if errors.Is(err, models.ErrInvalidFLStatusTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 재시작 실패: " + err.Error()})
+		return
+	}
+
+	audit.Annotate(c, "fl.restart", "federated-learning", fl.ID)
+	audit.AddDetail(c, "from_round", fromRound)
+	audit.AddDetail(c, "attempt", attempt)
+	if source != nil {
+		audit.AddDetail(c, "model_version_id", source.ID)
+	}
+
+	response := gin.H{"status": fl.Status, "attempt": attempt, "from_round": fromRound}
+	if source != nil {
+		response["model_version_id"] = source.ID
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": response})
+}
+
+// GetFederatedLearningTransitions는 연합학습 상태 변경 이력을 반환하는 핸들러입니다
+// @Summary 연합학습 상태 변경 이력 조회
+// @Tags federated-learning
+// @Produce json
+// @Param id path string true "연합학습 ID"
+// @Success 200 {array} models.FederatedLearningStatusTransition
+// @Router /api/federated-learning/{id}/transitions [get]
+func (h *FederatedLearningHandler) GetFederatedLearningTransitions(c *gin.Context) {
+	fl, ok := h.loadFederatedLearning(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	transitions, err := h.repo.GetStatusTransitions(fl.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "상태 변경 이력 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"status": fl.Status, "transitions": transitions}})
+}
+
+// signalLifecycleChange는 서버 프로세스에 신호를 보내고 상태를 바꿉니다 (일시정지/재개)
+func (h *FederatedLearningHandler) signalLifecycleChange(c *gin.Context, status, signal, defaultReason, auditAction string) {
+	fl, reason, ok := h.beginLifecycleChange(c, status, defaultReason)
+	if !ok {
+		return
+	}
+
+	if err := h.signalAggregatorServer(fl, signal); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "집계자 서버 신호 전송 실패: " + err.Error()})
+		return
+	}
+	if !h.commitLifecycleChange(c, fl, status, reason) {
+		return
+	}
+	audit.Annotate(c, auditAction, "federated-learning", fl.ID)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"status": fl.Status}})
+}
+
+// beginLifecycleChange는 작업을 조회해 권한과 상태 변경 가능 여부를 확인하고 변경 사유를 반환합니다
+func (h *FederatedLearningHandler) beginLifecycleChange(c *gin.Context, status, defaultReason string) (*models.FederatedLearning, string, bool) {
+	var request lifecycleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+			return nil, "", false
+		}
+	}
+	if request.Reason == "" {
+		request.Reason = defaultReason
+	}
+
+	fl, ok := h.loadFederatedLearning(c, authz.ActionOperate)
+	if !ok {
+		return nil, "", false
+	}
+	if err := models.ValidateFLStatusTransition(fl.Status, status); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "status": fl.Status})
+		return nil, "", false
+	}
+	return fl, request.Reason, true
+}
+
+// commitLifecycleChange는 상태 변경을 기록하고 감사 로그에 이전/다음 상태를 남깁니다
+func (h *FederatedLearningHandler) commitLifecycleChange(c *gin.Context, fl *models.FederatedLearning, status, reason string) bool {
+	principal := authz.PrincipalFromContext(c)
+	transition, err := h.repo.TransitionStatus(fl.ID, status, reason, &principal.UserID)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidFLStatusTransition) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 상태 변경에 실패했습니다"})
+		return false
+	}
+	fl.Status = status
+
+	audit.AddDetail(c, "from_status", transition.FromStatus)
+	audit.AddDetail(c, "to_status", transition.ToStatus)
+	audit.AddDetail(c, "reason", reason)
+	return true
+}
+
+// loadFederatedLearning은 경로의 연합학습 작업을 조회하고 소유 조직 권한을 확인합니다
+func (h *FederatedLearningHandler) loadFederatedLearning(c *gin.Context, action authz.Action) (*models.FederatedLearning, bool) {
+	fl, err := h.repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 작업 조회에 실패했습니다"})
+		return nil, false
+	}
+	if fl == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "연합학습 작업을 찾을 수 없습니다"})
+		return nil, false
+	}
+	if !authz.Authorize(c, fl.OrganizationID, action) {
+		return nil, false
+	}
+	return fl, true
+}
+
+// signalAggregatorServer는 집계자의 Flower 서버 프로세스에 신호(STOP, CONT)를 보냅니다
+func (h *FederatedLearningHandler) signalAggregatorServer(fl *models.FederatedLearning, signal string) error {
+	aggregator, err := (&flStepExecutor{handler: h}).loadAggregator(fl)
+	if err != nil {
+		return err
+	}
+	sshClient, err := h.newAggregatorSSHClient(aggregator)
+	if err != nil {
+		return err
+	}
+
+	command := fmt.Sprintf(`cd %s && [ -f %s ] && PID=$(cat %s) && kill -0 "$PID" && kill -%s "$PID"`,
+		aggregatorWorkDir(fl.ID), serverPIDFile, serverPIDFile, signal)
+	if _, stderr, err := sshClient.ExecuteCommand(command); err != nil {
+		return fmt.Errorf("서버 프로세스가 실행 중이 아닙니다: %v %s", err, stderr)
+	}
+	log.Printf("집계자 %s의 Flower 서버에 SIG%s 전송", aggregator.Name, signal)
+	return nil
+}
+
+// stopAggregatorServer는 집계자의 Flower 서버를 종료합니다 (SIGTERM, 유예 후 SIGKILL)
+// 집계자가 이미 삭제되었으면 종료할 프로세스가 없으므로 성공으로 처리합니다
+func (h *FederatedLearningHandler) stopAggregatorServer(fl *models.FederatedLearning) error {
+	if fl.AggregatorID == nil {
+		return nil
+	}
+	aggregator, err := h.aggregatorRepo.GetAggregatorByID(*fl.AggregatorID)
+	if err != nil {
+		return fmt.Errorf("집계자 조회 실패: %v", err)
+	}
+	if aggregator == nil {
+		return nil
+	}
+	sshClient, err := h.newAggregatorSSHClient(aggregator)
+	if err != nil {
+		return err
+	}
+
+	// 서버 PID를 기록하기 전인 시작 스크립트는 서버를 띄우기 전에 종료
+	// 일시정지된 프로세스는 SIGCONT 후에야 SIGTERM을 처리함
+	// PID 파일이 없으면 이전 버전 스크립트로 시작된 서버이므로 프로세스 이름으로 종료
+	command := fmt.Sprintf(`cd %[1]s 2>/dev/null || exit 0
+if [ -f %[4]s ]; then
+    kill -TERM "$(cat %[4]s)" 2>/dev/null
+    rm -f %[4]s
+fi
+if [ -f %[2]s ]; then
+    PID=$(cat %[2]s)
+    kill -CONT "$PID" 2>/dev/null
+    kill -TERM "$PID" 2>/dev/null
+    for i in $(seq 1 %[3]d); do kill -0 "$PID" 2>/dev/null || break; sleep 1; done
+    kill -KILL "$PID" 2>/dev/null
+    rm -f %[2]s
+else
+    pkill -TERM -f 'server_app.py --server-address' || true
+fi
+rm -f server_ready.txt
+true`, aggregatorWorkDir(fl.ID), serverPIDFile, serverStopGraceSeconds, serverLauncherPIDFile)
+	if stdout, stderr, err := sshClient.ExecuteCommand(command); err != nil {
+		return fmt.Errorf("%v, stdout: %s, stderr: %s", err, stdout, stderr)
+	}
+	log.Printf("집계자 %s의 Flower 서버 종료 완료", aggregator.Name)
+	return nil
+}
+
+// stopParticipants는 참여자들에게 로컬 학습 중지를 요청하고 실패한 참여자별 오류를 반환합니다
+func (h *FederatedLearningHandler) stopParticipants(fl *models.FederatedLearning) map[string]string {
+	errs := make(map[string]string)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for i := range fl.Participants {
+		participant := &fl.Participants[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := h.sendStopRequestToParticipant(participant, fl); err != nil {
+				log.Printf("참여자 %s 중지 요청 실패: %v", participant.ID, err)
+				mutex.Lock()
+				errs[participant.ID] = err.Error()
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// sendStopRequestToParticipant는 참여자 에이전트에게 서명한 로컬 실행 중지 요청을 보냅니다
+func (h *FederatedLearningHandler) sendStopRequestToParticipant(participant *models.Participant, fl *models.FederatedLearning) error {
+	if participant.OpenStackEndpoint == "" {
+		return fmt.Errorf("참여자 엔드포인트가 설정되지 않았습니다")
+	}
+	secret, err := h.agentRepo.GetSecret(participant.ID)
+	if err != nil {
+		return fmt.Errorf("참여자 에이전트 인증 정보 조회 실패: %v", err)
+	}
+	if secret == "" {
+		// 비밀 키가 없으면 실행 요청도 보낼 수 없었으므로 중지할 작업이 없음
+		return nil
+	}
+
+	request := &participantagent.StopRequest{
+		ProtocolVersion:     participantagent.ProtocolVersion,
+		JobID:               participantagent.JobID(fl.ID, fl.Attempt, participant.ID),
+		FederatedLearningID: fl.ID,
+	}
+	client, err := participantagent.NewHTTPClient(participantStopTimeout)
+	if err != nil {
+		return fmt.Errorf("에이전트 TLS 설정 오류: %v", err)
+	}
+	requestURL := participantagent.AgentURL(participant.OpenStackEndpoint, participantagent.StopPath)
+	statusCode, _, err := participantagent.PostSigned(context.Background(), client, requestURL, secret, participant.ID, participantagent.AudienceAgent, request)
+	if err != nil {
+		return err
+	}
+
+	// 참여자에서 이미 끝난 작업(404)은 중지된 것으로 간주
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
+	if statusCode < 200 || statusCode >= 300 {
+		return fmt.Errorf("HTTP 요청 실패: 상태 코드 %d", statusCode)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+// participantLogsTimeout은 follow가 아닌 참여자 로그 조회의 응답 대기 시간입니다
+const participantLogsTimeout = 30 * time.Second
+
+// GetParticipantLogs는 참여자 에이전트의 로컬 학습 로그를 중계합니다
+// ?attempt=N으로 이전 재시작 회차, ?offset=바이트로 이어 받기, ?follow=true로 작업이 끝날 때까지 스트리밍합니다
+func (h *FederatedLearningHandler) GetParticipantLogs(c *gin.Context) {
+	fl, ok := h.loadFederatedLearning(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	var participant *models.Participant
+	for i := range fl.Participants {
+		if fl.Participants[i].ID == c.Param("participantId") {
+			participant = &fl.Participants[i]
+			break
+		}
+	}
+	if participant == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "연합학습 작업의 참여자가 아닙니다"})
+		return
+	}
+	if participant.OpenStackEndpoint == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "참여자 엔드포인트가 설정되지 않았습니다"})
+		return
+	}
+
+	attempt := fl.Attempt
+	if value := c.Query("attempt"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > fl.Attempt {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "attempt가 올바르지 않습니다"})
+			return
+		}
+		attempt = parsed
+	}
+	offset := int64(0)
+	if value := c.Query("offset"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset이 올바르지 않습니다"})
+			return
+		}
+		offset = parsed
+	}
+	follow := c.Query("follow") == "true"
+
+	secret, err := h.agentRepo.GetSecret(participant.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "참여자 에이전트 인증 정보 조회에 실패했습니다"})
+		return
+	}
+	if secret == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "참여자 에이전트 비밀 키가 발급되지 않았습니다"})
+		return
+	}
+
+	timeout := participantLogsTimeout
+	if follow {
+		timeout = 0 // 클라이언트 연결이 끊기거나 작업이 끝날 때까지
+	}
+	client, err := participantagent.NewHTTPClient(timeout)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "에이전트 TLS 설정 오류: " + err.Error()})
+		return
+	}
+
+	jobID := participantagent.JobID(fl.ID, attempt, participant.ID)
+	query := url.Values{}
+	query.Set("job_id", jobID)
+	query.Set("offset", strconv.FormatInt(offset, 10))
+	query.Set("follow", strconv.FormatBool(follow))
+	requestURL := participantagent.AgentURL(participant.OpenStackEndpoint, participantagent.LogsPath) + "?" + query.Encode()
+
+	resp, err := participantagent.GetSigned(c.Request.Context(), client, requestURL, secret, participant.ID, participantagent.AudienceAgent)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "참여자 에이전트 연결 실패: " + err.Error()})
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "참여자에 해당 작업의 로그가 없습니다", "job_id": jobID})
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("참여자 에이전트 응답 오류: 상태 코드 %d, 응답: %s", resp.StatusCode, string(body))})
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Job-ID", jobID)
+	c.Header(participantagent.LogOffsetHeader, resp.Header.Get(participantagent.LogOffsetHeader))
+	c.Header(participantagent.JobStateHeader, resp.Header.Get(participantagent.JobStateHeader))
+	c.Status(http.StatusOK)
+
+	// 에이전트에서 받는 대로 클라이언트로 전달
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			if err != io.EOF && c.Request.Context().Err() == nil {
+				fmt.Printf("참여자 %s 로그 중계 중단: %v\n", participant.ID, err)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+)
+
+// flStepExecutor는 FederatedLearningHandler의 SSH/HTTP 로직으로 오케스트레이터 단계를 수행합니다
+type flStepExecutor struct {
+	handler *FederatedLearningHandler
+}
+
+// loadAggregator는 연합학습에 연결된 집계자를 조회합니다
+func (e *flStepExecutor) loadAggregator(fl *models.FederatedLearning) (*models.Aggregator, error) {
+	if fl.AggregatorID == nil {
+		return nil, fmt.Errorf("%w: 집계자 ID가 설정되지 않았습니다", services.ErrPermanentStepFailure)
+	}
+
+	aggregator, err := e.handler.aggregatorRepo.GetAggregatorByID(*fl.AggregatorID)
+	if err != nil {
+		return nil, fmt.Errorf("집계자 조회 실패: %v", err)
+	}
+	if aggregator == nil {
+		return nil, fmt.Errorf("%w: 집계자를 찾을 수 없습니다", services.ErrPermanentStepFailure)
+	}
+	return aggregator, nil
+}
+
+// UploadAggregatorBundle은 집계자에 Flower 서버 실행 파일들을 업로드합니다
+func (e *flStepExecutor) UploadAggregatorBundle(ctx context.Context, fl *models.FederatedLearning) error {
+	aggregator, err := e.loadAggregator(fl)
+	if err != nil {
+		return err
+	}
+	return e.handler.uploadAggregatorBundle(aggregator, fl)
+}
+
+// StartAggregatorServer는 집계자의 Flower 서버를 시작합니다
+func (e *flStepExecutor) StartAggregatorServer(ctx context.Context, fl *models.FederatedLearning) error {
+	aggregator, err := e.loadAggregator(fl)
+	if err != nil {
+		return err
+	}
+	return e.handler.startAggregatorServer(ctx, aggregator, fl)
+}
+
+// WaitAggregatorReady는 집계자 서버가 준비될 때까지 대기합니다
+func (e *flStepExecutor) WaitAggregatorReady(ctx context.Context, fl *models.FederatedLearning) error {
+	aggregator, err := e.loadAggregator(fl)
+	if err != nil {
+		return err
+	}
+	return e.handler.waitForAggregatorReady(ctx, aggregator, aggregatorWorkDir(fl.ID))
+}
+
+// DispatchToParticipant는 참여자에게 연합학습 실행 요청을 전송합니다
+func (e *flStepExecutor) DispatchToParticipant(ctx context.Context, fl *models.FederatedLearning, participantID string) error {
+	participant, err := e.handler.participantRepo.GetByID(participantID)
+	if err != nil {
+		return fmt.Errorf("참여자 조회 실패 (ID: %s): %v", participantID, err)
+	}
+	if participant == nil {
+		return fmt.Errorf("%w: 참여자를 찾을 수 없습니다 (ID: %s)", services.ErrPermanentStepFailure, participantID)
+	}
+	if participant.OpenStackEndpoint == "" {
+		return fmt.Errorf("%w: 참여자 %s의 엔드포인트가 설정되지 않았습니다", services.ErrPermanentStepFailure, participant.Name)
+	}
+
+	return e.handler.sendExecuteRequestToParticipant(participant, fl)
+}
+
+// GetFederatedLearningSteps는 연합학습 실행 단계의 진행 상황을 반환하는 핸들러입니다
+// @Summary 연합학습 실행 단계 조회
+// @Description 집계자 번들 업로드부터 참여자 요청 전송까지 각 단계의 상태, 에러, 재시도 횟수를 조회합니다.
+// @Tags federated-learning
+// @Produce json
+// @Param id path string true "연합학습 ID"
+// @Success 200 {array} models.FederatedLearningStep
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/federated-learning/{id}/steps [get]
+func (h *FederatedLearningHandler) GetFederatedLearningSteps(c *gin.Context) {
+	id := c.Param("id")
+	fl, err := h.repo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 작업 조회에 실패했습니다"})
+		return
+	}
+	if fl == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "연합학습 작업을 찾을 수 없습니다"})
+		return
+	}
+
+	// 작업 소유 조직 권한 확인
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionView) {
+		return
+	}
+
+	steps, err := h.orchestrator.GetSteps(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "실행 단계 조회 실패"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"status": fl.Status, "steps": steps}})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+// pyproject.toml 템플릿의 algorithm 설정 줄 (렌더링 시 전략 설정 블록으로 교체)
+const pyprojectAlgorithmLine = `algorithm = "fedavg"`
+
+// strategyConfigKeys는 StrategyConfig 하이퍼파라미터 이름과 server_app.py가 읽는 TOML 키의 매핑입니다
+var strategyConfigKeys = []struct {
+	param string
+	key   string
+}{
+	{"proximal_mu", "proximal-mu"},
+	{"server_learning_rate", "server-learning-rate"},
+	{"client_learning_rate", "client-learning-rate"},
+	{"beta1", "beta1"},
+	{"beta2", "beta2"},
+	{"tau", "tau"},
+}
+
+// buildStrategyConfig는 요청의 알고리즘과 하이퍼파라미터를 검증하고 기본값을 채운 전략 설정을 반환합니다
+func buildStrategyConfig(algorithm string, params *models.StrategyConfig) (models.StrategyConfig, error) {
+	config := models.StrategyConfig{}
+	if params != nil {
+		config = *params
+	}
+	if algorithm != "" {
+		config.Algorithm = algorithm
+	}
+	return config.Resolve()
+}
+
+// resolveStrategyConfig는 연합학습에 저장된 전략 설정을 반환합니다
+// 전략 설정이 없는 기존 작업은 Algorithm 필드와 기본 하이퍼파라미터를 사용합니다
+func resolveStrategyConfig(fl *models.FederatedLearning) (models.StrategyConfig, error) {
+	if fl.StrategyConfig != nil {
+		return fl.StrategyConfig.Resolve()
+	}
+	return buildStrategyConfig(fl.Algorithm, nil)
+}
+
+// renderStrategyConfig는 전략 설정을 [tool.flwr.app.config] 섹션의 TOML 줄로 변환합니다
+func renderStrategyConfig(config models.StrategyConfig) string {
+	lines := []string{fmt.Sprintf("algorithm = %q", config.Algorithm)}
+
+	values := config.Values()
+	for _, k := range strategyConfigKeys {
+		if value, ok := values[k.param]; ok {
+			lines = append(lines, fmt.Sprintf("%s = %s", k.key, strconv.FormatFloat(value, 'g', -1, 64)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/latency"
+)
+
+// LatencyHandler는 리전 간 지연시간 측정 관련 API 핸들러입니다
+type LatencyHandler struct {
+	prober     *latency.Prober
+	sampleRepo *repository.LatencySampleRepository
+}
+
+// NewLatencyHandler는 새 LatencyHandler 인스턴스를 생성합니다
+func NewLatencyHandler(prober *latency.Prober, sampleRepo *repository.LatencySampleRepository) *LatencyHandler {
+	return &LatencyHandler{
+		prober:     prober,
+		sampleRepo: sampleRepo,
+	}
+}
+
+// TriggerLatencyProbe는 지연시간 측정을 백그라운드에서 즉시 실행합니다
+func (h *LatencyHandler) TriggerLatencyProbe(c *gin.Context) {
+	if err := h.prober.RunAsync(context.Background()); err != nil {
+		if errors.Is(err, latency.ErrProbeRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": "이미 지연시간 측정이 진행 중입니다"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "지연시간 측정 시작에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "지연시간 측정을 시작했습니다"})
+}
+
+// GetLatencySamples는 지연시간 측정 이력을 조회합니다
+func (h *LatencyHandler) GetLatencySamples(c *gin.Context) {
+	filter := repository.LatencySampleFilter{
+		SourceRegion:  c.Query("sourceRegion"),
+		TargetRegion:  c.Query("targetRegion"),
+		ParticipantID: c.Query("participantId"),
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since는 RFC3339 형식이어야 합니다"})
+			return
+		}
+		filter.Since = &t
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit은 양의 정수여야 합니다"})
+			return
+		}
+		filter.Limit = n
+	}
+
+	samples, err := h.sampleRepo.GetSamples(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "지연시간 측정 이력 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": samples})
+}
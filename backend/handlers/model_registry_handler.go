@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+	"github.com/Mungge/Fleecy-Cloud/services/modelregistry"
+)
+
+// ModelRegistryHandler는 글로벌 모델 레지스트리(버전 조회, 태그, 승격, 다운로드) 요청을 처리합니다
+type ModelRegistryHandler struct {
+	registry       *modelregistry.Registry
+	repo           *repository.ModelVersionRepository
+	flRepo         *repository.FederatedLearningRepository
+	taskBundleRepo *repository.TaskBundleRepository
+}
+
+// NewModelRegistryHandler는 새 ModelRegistryHandler 인스턴스를 생성합니다
+func NewModelRegistryHandler(registry *modelregistry.Registry, repo *repository.ModelVersionRepository, flRepo *repository.FederatedLearningRepository, taskBundleRepo *repository.TaskBundleRepository) *ModelRegistryHandler {
+	return &ModelRegistryHandler{
+		registry:       registry,
+		repo:           repo,
+		flRepo:         flRepo,
+		taskBundleRepo: taskBundleRepo,
+	}
+}
+
+// GetModelVersions는 활성 조직의 모델 버전 목록을 반환합니다 (필터: federated_learning_id, stage, tag)
+func (h *ModelRegistryHandler) GetModelVersions(c *gin.Context) {
+	principal, ok := authz.AuthorizeActive(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	stage := c.Query("stage")
+	if stage != "" && !models.IsValidModelStage(stage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "알 수 없는 단계입니다: " + stage})
+		return
+	}
+
+	versions, err := h.repo.List(repository.ModelVersionFilter{
+		OrganizationID:      principal.ActiveOrganizationID,
+		FederatedLearningID: c.Query("federated_learning_id"),
+		Stage:               stage,
+		Tag:                 c.Query("tag"),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "모델 버전 목록 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": versions})
+}
+
+// GetModelVersion은 모델 버전을 반환합니다
+func (h *ModelRegistryHandler) GetModelVersion(c *gin.Context) {
+	version, ok := h.loadVersion(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": version})
+}
+
+// GetModelVersionLineage는 모델 버전의 이전 라운드 계보와 학습 코드 번들을 반환합니다
+func (h *ModelRegistryHandler) GetModelVersionLineage(c *gin.Context) {
+	version, ok := h.loadVersion(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	siblings, err := h.repo.GetByFederatedLearningID(version.FederatedLearningID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "모델 계보 조회에 실패했습니다"})
+		return
+	}
+	byID := make(map[string]*models.ModelVersion, len(siblings))
+	for _, sibling := range siblings {
+		byID[sibling.ID] = sibling
+	}
+
+	// 직전 라운드부터 첫 라운드까지 (라운드 수만큼만 따라가 순환을 방지)
+	ancestors := []*models.ModelVersion{}
+	for parentID := version.ParentVersionID; parentID != nil && len(ancestors) < len(siblings); {
+		parent, ok := byID[*parentID]
+		if !ok {
+			break
+		}
+		ancestors = append(ancestors, parent)
+		parentID = parent.ParentVersionID
+	}
+
+	response := gin.H{
+		"version":               version,
+		"ancestors":             ancestors,
+		"federated_learning_id": version.FederatedLearningID,
+	}
+	if version.TaskBundleID != nil {
+		bundle, err := h.taskBundleRepo.GetByID(*version.TaskBundleID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "번들 조회에 실패했습니다"})
+			return
+		}
+		if bundle != nil {
+			// 계보에는 번들 식별 정보만 포함 (파일 내용 제외)
+			bundle.FileNames = bundle.SortedFileNames()
+			bundle.Files = nil
+			response["task_bundle"] = bundle
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// UpdateModelVersionTags는 모델 버전의 태그를 교체합니다
+func (h *ModelRegistryHandler) UpdateModelVersionTags(c *gin.Context) {
+	version, ok := h.loadVersion(c, authz.ActionManage)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Tags []string `json:"tags"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+	tags, err := models.NormalizeModelVersionTags(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateTags(version.ID, tags); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "태그 변경에 실패했습니다"})
+		return
+	}
+	version.Tags = tags
+
+	audit.Annotate(c, "model.version.tag", "model-versions", version.ID)
+	audit.AddDetail(c, "tags", tags)
+
+	c.JSON(http.StatusOK, gin.H{"data": version})
+}
+
+// PromoteModelVersion은 모델 버전의 단계를 변경합니다 (production 승격 시 기존 production은 archived)
+func (h *ModelRegistryHandler) PromoteModelVersion(c *gin.Context) {
+	version, ok := h.loadVersion(c, authz.ActionManage)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Stage string `json:"stage" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+	if !models.IsValidModelStage(req.Stage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "단계는 none, staging, production, archived 중 하나여야 합니다"})
+		return
+	}
+
+	principal := authz.PrincipalFromContext(c)
+	previous := version.Stage
+	if err := h.repo.SetStage(version, req.Stage, principal.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "모델 단계 변경에 실패했습니다"})
+		return
+	}
+
+	audit.Annotate(c, "model.version.promote", "model-versions", version.ID)
+	audit.AddDetail(c, "from_stage", previous)
+	audit.AddDetail(c, "to_stage", req.Stage)
+
+	c.JSON(http.StatusOK, gin.H{"data": version})
+}
+
+// DownloadModelVersion은 모델 저장소에서 모델 파일을 내려받습니다 (집계자 실행 여부와 무관)
+func (h *ModelRegistryHandler) DownloadModelVersion(c *gin.Context) {
+	version, ok := h.loadVersion(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	reader, err := h.registry.Open(c.Request.Context(), version)
+	if err != nil {
+		if errors.Is(err, modelregistry.ErrObjectNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "모델 파일이 저장소에 없습니다"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "모델 파일 읽기에 실패했습니다"})
+		return
+	}
+	defer reader.Close()
+
+	fileName := fmt.Sprintf("%s-round-%03d.pt", version.FederatedLearningID, version.Round)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Length", strconv.FormatInt(version.SizeBytes, 10))
+	c.Header("X-Content-SHA256", version.ContentHash)
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		fmt.Printf("모델 파일 전송 실패 (%s): %v\n", version.ID, err)
+	}
+}
+
+// CollectModelVersions는 연합학습 작업의 집계자에서 새 체크포인트를 즉시 수집합니다
+func (h *ModelRegistryHandler) CollectModelVersions(c *gin.Context) {
+	var req struct {
+		FederatedLearningID string `json:"federated_learning_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "federated_learning_id가 필요합니다"})
+		return
+	}
+
+	fl, err := h.flRepo.GetByID(req.FederatedLearningID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "연합학습 작업 조회에 실패했습니다"})
+		return
+	}
+	if fl == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "연합학습 작업을 찾을 수 없습니다"})
+		return
+	}
+	if !authz.Authorize(c, fl.OrganizationID, authz.ActionOperate) {
+		return
+	}
+
+	collected, err := h.registry.CollectCheckpoints(c.Request.Context(), fl)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "체크포인트 수집 실패: " + err.Error(), "collected": collected})
+		return
+	}
+
+	audit.Annotate(c, "model.version.collect", "federated-learning", fl.ID)
+	audit.AddDetail(c, "collected", collected)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"collected": collected}})
+}
+
+// loadVersion은 경로의 모델 버전을 조회하고 소유 조직 권한을 확인합니다
+func (h *ModelRegistryHandler) loadVersion(c *gin.Context, action authz.Action) (*models.ModelVersion, bool) {
+	version, err := h.repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "모델 버전 조회에 실패했습니다"})
+		return nil, false
+	}
+	if version == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "모델 버전을 찾을 수 없습니다"})
+		return nil, false
+	}
+	if !authz.Authorize(c, version.OrganizationID, action) {
+		return nil, false
+	}
+	return version, true
+}
@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+)
+
+// OrganizationHandler는 조직과 조직 멤버 관리 요청을 처리합니다
+type OrganizationHandler struct {
+	orgRepo  *repository.OrganizationRepository
+	userRepo *repository.UserRepository
+}
+
+// NewOrganizationHandler는 새 OrganizationHandler 인스턴스를 생성합니다
+func NewOrganizationHandler(orgRepo *repository.OrganizationRepository, userRepo *repository.UserRepository) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgRepo:  orgRepo,
+		userRepo: userRepo,
+	}
+}
+
+type organizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type addMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type updateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// GetOrganizations는 사용자가 속한 조직 목록을 역할과 함께 반환합니다
+func (h *OrganizationHandler) GetOrganizations(c *gin.Context) {
+	principal := authz.PrincipalFromContext(c)
+	if principal == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
+		return
+	}
+
+	orgs, err := h.orgRepo.ListOrganizationsByUserID(principal.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "조직 목록 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": orgs})
+}
+
+// CreateOrganization은 새 조직을 생성하고 요청자를 owner로 등록합니다
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	principal := authz.PrincipalFromContext(c)
+	if principal == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
+		return
+	}
+
+	var req organizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "조직 이름이 필요합니다"})
+		return
+	}
+
+	org := &models.Organization{Name: name}
+	if err := h.orgRepo.CreateOrganization(org, principal.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "조직 생성에 실패했습니다"})
+		return
+	}
+
+	log.Printf("조직 생성: %s (ID: %d, 생성자: %d)", org.Name, org.ID, principal.UserID)
+	c.JSON(http.StatusCreated, gin.H{"data": models.OrganizationWithRole{Organization: *org, Role: models.OrganizationRoleOwner}})
+}
+
+// GetOrganization은 조직 정보를 반환합니다
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	org, ok := h.loadOrganization(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	principal := authz.PrincipalFromContext(c)
+	c.JSON(http.StatusOK, gin.H{"data": models.OrganizationWithRole{Organization: *org, Role: principal.Role(org.ID)}})
+}
+
+// UpdateOrganization은 조직 이름을 변경합니다
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	org, ok := h.loadOrganization(c, authz.ActionManageMembers)
+	if !ok {
+		return
+	}
+
+	var req organizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "조직 이름이 필요합니다"})
+		return
+	}
+
+	org.Name = name
+	if err := h.orgRepo.UpdateOrganization(org); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "조직 수정에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": org})
+}
+
+// DeleteOrganization은 리소스가 없는 조직을 삭제합니다 (개인 조직은 삭제 불가)
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	org, ok := h.loadOrganization(c, authz.ActionDeleteOrganization)
+	if !ok {
+		return
+	}
+
+	if org.Personal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "개인 조직은 삭제할 수 없습니다"})
+		return
+	}
+
+	count, err := h.orgRepo.CountOrganizationResources(org.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "조직 리소스 확인에 실패했습니다"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "조직에 남아있는 리소스를 먼저 삭제해야 합니다"})
+		return
+	}
+
+	if err := h.orgRepo.DeleteOrganization(org.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "조직 삭제에 실패했습니다"})
+		return
+	}
+
+	log.Printf("조직 삭제: %s (ID: %d)", org.Name, org.ID)
+	c.JSON(http.StatusOK, gin.H{"message": "조직이 삭제되었습니다"})
+}
+
+// GetMembers는 조직 멤버 목록을 반환합니다
+func (h *OrganizationHandler) GetMembers(c *gin.Context) {
+	org, ok := h.loadOrganization(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	members, err := h.orgRepo.ListMembers(org.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "멤버 목록 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": members})
+}
+
+// AddMember는 이메일로 사용자를 찾아 조직 멤버로 추가합니다
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	org, ok := h.loadOrganization(c, authz.ActionManageMembers)
+	if !ok {
+		return
+	}
+
+	if org.Personal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "개인 조직에는 멤버를 추가할 수 없습니다"})
+		return
+	}
+
+	var req addMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+	if !h.checkAssignableRole(c, org.ID, req.Role) {
+		return
+	}
+
+	user, err := h.userRepo.GetUserByEmail(req.Email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "사용자 조회에 실패했습니다"})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "사용자를 찾을 수 없습니다"})
+		return
+	}
+
+	existing, err := h.orgRepo.GetMember(org.ID, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "멤버 조회에 실패했습니다"})
+		return
+	}
+	if existing != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "이미 조직에 속한 사용자입니다"})
+		return
+	}
+
+	member := &models.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         user.ID,
+		Role:           req.Role,
+	}
+	if err := h.orgRepo.AddMember(member); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "멤버 추가에 실패했습니다"})
+		return
+	}
+	member.User = user
+
+	log.Printf("조직 멤버 추가: 조직 %d, 사용자 %s (%s)", org.ID, user.Email, member.Role)
+	c.JSON(http.StatusCreated, gin.H{"data": member})
+}
+
+// UpdateMemberRole은 조직 멤버의 역할을 변경합니다
+func (h *OrganizationHandler) UpdateMemberRole(c *gin.Context) {
+	org, ok := h.loadOrganization(c, authz.ActionManageMembers)
+	if !ok {
+		return
+	}
+
+	member, ok := h.loadMember(c, org.ID)
+	if !ok {
+		return
+	}
+
+	var req updateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다"})
+		return
+	}
+	if !h.checkAssignableRole(c, org.ID, req.Role) {
+		return
+	}
+
+	// owner의 역할 변경은 owner만 가능하며, 마지막 owner는 강등할 수 없음
+	if member.Role == models.OrganizationRoleOwner && req.Role != models.OrganizationRoleOwner {
+		if !authz.Authorize(c, org.ID, authz.ActionDeleteOrganization) {
+			return
+		}
+		if !h.checkRemainingOwner(c, org.ID) {
+			return
+		}
+	}
+
+	if err := h.orgRepo.UpdateMemberRole(org.ID, member.UserID, req.Role); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "멤버 역할 변경에 실패했습니다"})
+		return
+	}
+	member.Role = req.Role
+
+	c.JSON(http.StatusOK, gin.H{"data": member})
+}
+
+// RemoveMember는 조직 멤버를 제거합니다 (본인은 권한과 관계없이 탈퇴 가능)
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	principal := authz.PrincipalFromContext(c)
+	targetUserID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 사용자 ID입니다"})
+		return
+	}
+
+	action := authz.ActionManageMembers
+	if principal != nil && principal.UserID == targetUserID {
+		action = authz.ActionView
+	}
+	org, ok := h.loadOrganization(c, action)
+	if !ok {
+		return
+	}
+
+	if org.Personal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "개인 조직의 멤버는 제거할 수 없습니다"})
+		return
+	}
+
+	member, ok := h.loadMember(c, org.ID)
+	if !ok {
+		return
+	}
+
+	if member.Role == models.OrganizationRoleOwner {
+		// 다른 owner 제거는 owner만 가능
+		if member.UserID != principal.UserID && !authz.Authorize(c, org.ID, authz.ActionDeleteOrganization) {
+			return
+		}
+		if !h.checkRemainingOwner(c, org.ID) {
+			return
+		}
+	}
+
+	if err := h.orgRepo.RemoveMember(org.ID, member.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "멤버 제거에 실패했습니다"})
+		return
+	}
+
+	log.Printf("조직 멤버 제거: 조직 %d, 사용자 %d", org.ID, member.UserID)
+	c.JSON(http.StatusOK, gin.H{"message": "멤버가 제거되었습니다"})
+}
+
+// loadOrganization은 경로의 조직을 조회하고 작업 권한을 확인합니다 (멤버가 아니면 404)
+func (h *OrganizationHandler) loadOrganization(c *gin.Context, action authz.Action) (*models.Organization, bool) {
+	orgID, err := strconv.ParseInt(c.Param("orgId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 조직 ID입니다"})
+		return nil, false
+	}
+
+	if !authz.PrincipalFromContext(c).IsMember(orgID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "조직을 찾을 수 없습니다"})
+		return nil, false
+	}
+
+	org, err := h.orgRepo.GetOrganizationByID(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "조직 조회에 실패했습니다"})
+		return nil, false
+	}
+	if org == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "조직을 찾을 수 없습니다"})
+		return nil, false
+	}
+
+	if !authz.Authorize(c, org.ID, action) {
+		return nil, false
+	}
+	return org, true
+}
+
+// loadMember는 경로의 사용자 ID로 조직 멤버를 조회합니다
+func (h *OrganizationHandler) loadMember(c *gin.Context, orgID int64) (*models.OrganizationMember, bool) {
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 사용자 ID입니다"})
+		return nil, false
+	}
+
+	member, err := h.orgRepo.GetMember(orgID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "멤버 조회에 실패했습니다"})
+		return nil, false
+	}
+	if member == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "멤버를 찾을 수 없습니다"})
+		return nil, false
+	}
+	return member, true
+}
+
+// checkAssignableRole은 역할이 유효한지, 요청자가 부여할 수 있는 역할인지 확인합니다
+func (h *OrganizationHandler) checkAssignableRole(c *gin.Context, orgID int64, role string) bool {
+	if !models.IsValidOrganizationRole(role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "지원하지 않는 역할입니다"})
+		return false
+	}
+	// owner 지정은 owner만 가능
+	if role == models.OrganizationRoleOwner {
+		return authz.Authorize(c, orgID, authz.ActionDeleteOrganization)
+	}
+	return true
+}
+
+// checkRemainingOwner는 owner가 한 명 이상 남는지 확인합니다
+func (h *OrganizationHandler) checkRemainingOwner(c *gin.Context, orgID int64) bool {
+	owners, err := h.orgRepo.CountOwners(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "소유자 확인에 실패했습니다"})
+		return false
+	}
+	if owners <= 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "조직에는 최소 한 명의 소유자가 있어야 합니다"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+// maxAgentCallbackBytes는 상태 알림 요청 본문의 최대 크기입니다
+const maxAgentCallbackBytes = 64 * 1024
+
+// ParticipantAgentHandler는 참여자 에이전트가 호출하는 API(상태 알림, 스키마)를 처리합니다
+// 사용자 인증 대신 참여자별 비밀 키로 서명한 토큰으로 인증합니다
+type ParticipantAgentHandler struct {
+	agentRepo *repository.ParticipantAgentRepository
+}
+
+// NewParticipantAgentHandler는 새 ParticipantAgentHandler 인스턴스를 생성합니다
+func NewParticipantAgentHandler(agentRepo *repository.ParticipantAgentRepository) *ParticipantAgentHandler {
+	return &ParticipantAgentHandler{agentRepo: agentRepo}
+}
+
+// participantAgentCallbackBaseURL은 에이전트가 상태 알림을 보낼 백엔드 주소입니다
+func participantAgentCallbackBaseURL() string {
+	if baseURL := os.Getenv("PARTICIPANT_AGENT_CALLBACK_BASE_URL"); baseURL != "" {
+		return baseURL
+	}
+	if baseURL := os.Getenv("API_BASE_URL"); baseURL != "" {
+		return baseURL
+	}
+	return "http://localhost:8080"
+}
+
+// GetProtocolSchema는 에이전트 프로토콜 v1의 JSON Schema를 반환합니다
+func (h *ParticipantAgentHandler) GetProtocolSchema(c *gin.Context) {
+	c.Header(participantagent.ProtocolHeader, participantagent.ProtocolVersion)
+	c.Data(http.StatusOK, "application/schema+json", participantagent.SchemaV1)
+}
+
+// ReceiveStatusCallback은 참여자 에이전트의 작업 상태 알림을 받아 참여자 작업 상태에 반영합니다
+func (h *ParticipantAgentHandler) ReceiveStatusCallback(c *gin.Context) {
+	participantID := c.Param("participantId")
+	c.Header(participantagent.ProtocolHeader, participantagent.ProtocolVersion)
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAgentCallbackBytes+1))
+	if err != nil {
+		agentError(c, http.StatusBadRequest, "요청 본문을 읽을 수 없습니다")
+		return
+	}
+	if len(body) > maxAgentCallbackBytes {
+		agentError(c, http.StatusRequestEntityTooLarge, "요청 본문이 너무 큽니다")
+		return
+	}
+
+	// 서명 확인 (비밀 키가 없는 참여자와 잘못된 서명은 구분하지 않음)
+	secret, err := h.agentRepo.GetSecret(participantID)
+	if err != nil {
+		agentError(c, http.StatusInternalServerError, "참여자 인증 정보 조회에 실패했습니다")
+		return
+	}
+	token := participantagent.BearerToken(c.GetHeader("Authorization"))
+	if secret == "" {
+		agentError(c, http.StatusUnauthorized, "인증에 실패했습니다")
+		return
+	}
+	if err := participantagent.VerifyRequest(secret, token, participantID, participantagent.AudienceBackend, body); err != nil {
+		fmt.Printf("참여자 %s 상태 알림 인증 실패: %v\n", participantID, err)
+		agentError(c, http.StatusUnauthorized, "인증에 실패했습니다")
+		return
+	}
+
+	var callback participantagent.StatusCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		agentError(c, http.StatusBadRequest, "잘못된 요청 형식입니다")
+		return
+	}
+	if err := callback.Validate(); err != nil {
+		agentError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if callback.ParticipantID != participantID {
+		agentError(c, http.StatusBadRequest, "participant_id가 경로와 일치하지 않습니다")
+		return
+	}
+
+	pfl, applied, err := h.agentRepo.ApplyStatusCallback(&callback)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAgentJobNotFound):
+			agentError(c, http.StatusNotFound, "참여자가 연합학습 작업에 포함되어 있지 않습니다")
+		case errors.Is(err, repository.ErrAgentJobMismatch):
+			// 재시작 이전 회차 등 더 이상 유효하지 않은 작업의 알림
+			agentError(c, http.StatusConflict, "현재 작업 ID와 일치하지 않습니다")
+		default:
+			agentError(c, http.StatusInternalServerError, "상태 반영에 실패했습니다")
+		}
+		return
+	}
+
+	audit.Annotate(c, "participant.agent.status", "participants", participantID)
+	audit.AddDetail(c, "applied", applied)
+
+	c.JSON(http.StatusOK, gin.H{
+		"protocol_version": participantagent.ProtocolVersion,
+		"job_id":           callback.JobID,
+		"applied":          applied, // false면 이미 반영한 순번 (재전송)
+		"status":           pfl.Status,
+		"tasks_completed":  pfl.TasksCompleted,
+	})
+}
+
+// agentError는 에이전트 프로토콜 형식의 오류 응답을 보냅니다
+func agentError(c *gin.Context, status int, message string) {
+	c.JSON(status, participantagent.ErrorResponse{
+		ProtocolVersion: participantagent.ProtocolVersion,
+		Error:           message,
+	})
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
 	"github.com/Mungge/Fleecy-Cloud/services"
-	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
 )
 
 type OpenStackConfig struct {
@@ -33,12 +35,14 @@ type OpenStackConfig struct {
 // ParticipantHandler는 참여자(OpenStack 클라우드) 관련 API 핸들러입니다
 type ParticipantHandler struct {
 	repo             *repository.ParticipantRepository
+	agentRepo        *repository.ParticipantAgentRepository
 	openStackService *services.OpenStackService
 }
 
-func NewParticipantHandler(repo *repository.ParticipantRepository) *ParticipantHandler {
+func NewParticipantHandler(repo *repository.ParticipantRepository, agentRepo *repository.ParticipantAgentRepository) *ParticipantHandler {
 	return &ParticipantHandler{
 		repo:             repo,
+		agentRepo:        agentRepo,
 		openStackService: services.NewOpenStackService("http://localhost:9090"),
 	}
 }
@@ -66,8 +70,11 @@ func (h *ParticipantHandler) parseOpenStackConfig(yamlContent []byte) (*OpenStac
 
 // CreateParticipant는 새 참여자를 생성하는 핸들러입니다
 func (h *ParticipantHandler) CreateParticipant(c *gin.Context) {
-	// 사용자 ID 추출
-	userID := utils.GetUserIDFromMiddleware(c)
+	// 현재 조직의 리소스 생성 권한 확인
+	principal, ok := authz.AuthorizeActive(c, authz.ActionManage)
+	if !ok {
+		return
+	}
 
 	name := c.PostForm("name")
 	if name == "" {
@@ -115,12 +122,13 @@ func (h *ParticipantHandler) CreateParticipant(c *gin.Context) {
 
 	// 참여자 객체 생성
 	participant := &models.Participant{
-		ID:       uuid.New().String(),
-		UserID:   userID,
-		Name:     name,
-		Status:   "inactive", // 기본적으로 비활성 상태로 생성
-		Metadata: metadata,
-		Region:   region,
+		ID:             uuid.New().String(),
+		UserID:         principal.UserID,
+		OrganizationID: principal.ActiveOrganizationID,
+		Name:           name,
+		Status:         "inactive", // 기본적으로 비활성 상태로 생성
+		Metadata:       metadata,
+		Region:         region,
 
 		// OpenStack 관련 필드
 		OpenStackEndpoint:                    authURL,
@@ -148,15 +156,20 @@ func (h *ParticipantHandler) CreateParticipant(c *gin.Context) {
 		return
 	}
 
+	audit.Annotate(c, "participant.create", "participants", participant.ID)
+
 	c.JSON(http.StatusCreated, gin.H{"data": participant})
 }
 
 // GetParticipants는 등록된 모든 참여자를 반환하는 핸들러입니다
 func (h *ParticipantHandler) GetParticipants(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
+	principal, ok := authz.AuthorizeActive(c, authz.ActionView)
+	if !ok {
+		return
+	}
 
-	// 등록된 모든 참여자 조회
-	participants, err := h.repo.GetByUserID(userID)
+	// 현재 조직의 모든 참여자 조회
+	participants, err := h.repo.GetByOrganizationID(principal.ActiveOrganizationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "참여자 목록 조회에 실패했습니다"})
 		return
@@ -167,10 +180,13 @@ func (h *ParticipantHandler) GetParticipants(c *gin.Context) {
 
 // GetAvailableParticipants는 사용 가능한 참여자 목록을 반환하는 핸들러입니다
 func (h *ParticipantHandler) GetAvailableParticipants(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
+	principal, ok := authz.AuthorizeActive(c, authz.ActionView)
+	if !ok {
+		return
+	}
 
 	// 사용 가능한 참여자 조회
-	participants, err := h.repo.GetAvailable(userID)
+	participants, err := h.repo.GetAvailable(principal.ActiveOrganizationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "사용 가능한 참여자 조회에 실패했습니다"})
 		return
@@ -181,8 +197,6 @@ func (h *ParticipantHandler) GetAvailableParticipants(c *gin.Context) {
 
 // GetParticipant는 특정 ID의 참여자를 반환하는 핸들러입니다
 func (h *ParticipantHandler) GetParticipant(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 참여자 ID 추출
 	id := c.Param("id")
 
@@ -197,9 +211,8 @@ func (h *ParticipantHandler) GetParticipant(c *gin.Context) {
 		return
 	}
 
-	// 참여자 소유자 확인
-	if participant.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 참여자에 접근할 권한이 없습니다"})
+	// 참여자 소유 조직 권한 확인
+	if !authz.Authorize(c, participant.OrganizationID, authz.ActionView) {
 		return
 	}
 
@@ -207,8 +220,6 @@ func (h *ParticipantHandler) GetParticipant(c *gin.Context) {
 }
 
 func (h *ParticipantHandler) UpdateParticipant(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 참여자 ID 추출
 	id := c.Param("id")
 
@@ -223,9 +234,8 @@ func (h *ParticipantHandler) UpdateParticipant(c *gin.Context) {
 		return
 	}
 
-	// 참여자 소유자 확인
-	if participant.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 참여자를 수정할 권한이 없습니다"})
+	// 참여자 소유 조직 권한 확인
+	if !authz.Authorize(c, participant.OrganizationID, authz.ActionManage) {
 		return
 	}
 
@@ -297,8 +307,6 @@ func (h *ParticipantHandler) UpdateParticipant(c *gin.Context) {
 }
 
 func (h *ParticipantHandler) DeleteParticipant(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-
 	// 경로 매개변수에서 참여자 ID 추출
 	id := c.Param("id")
 
@@ -313,9 +321,8 @@ func (h *ParticipantHandler) DeleteParticipant(c *gin.Context) {
 		return
 	}
 
-	// 참여자 소유자 확인
-	if participant.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "해당 참여자를 삭제할 권한이 없습니다"})
+	// 참여자 소유 조직 권한 확인
+	if !authz.Authorize(c, participant.OrganizationID, authz.ActionManage) {
 		return
 	}
 
@@ -355,6 +362,11 @@ func (h *ParticipantHandler) HealthCheckParticipant(c *gin.Context) {
 		return
 	}
 
+	// 참여자 소유 조직 권한 확인
+	if !authz.Authorize(c, participant.OrganizationID, authz.ActionOperate) {
+		return
+	}
+
 	// OpenStack 연결 테스트
 	startTime := time.Now()
 	err = h.testOpenStackConnection(participant)
@@ -403,3 +415,65 @@ func (h *ParticipantHandler) HealthCheckParticipant(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"data": result})
 }
+
+// GetAgentCredentialStatus는 참여자 에이전트 비밀 키 발급 여부를 반환합니다 (비밀 키는 포함하지 않음)
+func (h *ParticipantHandler) GetAgentCredentialStatus(c *gin.Context) {
+	participant, err := h.repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "참여자 조회에 실패했습니다"})
+		return
+	}
+	if participant == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "참여자를 찾을 수 없습니다"})
+		return
+	}
+	if !authz.Authorize(c, participant.OrganizationID, authz.ActionView) {
+		return
+	}
+
+	issued, err := h.agentRepo.HasCredential(participant.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "에이전트 인증 정보 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"participant_id":   participant.ID,
+		"issued":           issued,
+		"protocol_version": participantagent.ProtocolVersion,
+		"callback_url":     participantagent.CallbackURL(participantAgentCallbackBaseURL(), participant.ID),
+	}})
+}
+
+// IssueAgentCredential은 참여자 에이전트 비밀 키를 새로 발급합니다
+// 비밀 키는 이 응답에서만 확인할 수 있으며, 이전 키로 서명한 요청은 즉시 거부됩니다
+func (h *ParticipantHandler) IssueAgentCredential(c *gin.Context) {
+	participant, err := h.repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "참여자 조회에 실패했습니다"})
+		return
+	}
+	if participant == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "참여자를 찾을 수 없습니다"})
+		return
+	}
+	if !authz.Authorize(c, participant.OrganizationID, authz.ActionManage) {
+		return
+	}
+
+	secret, err := h.agentRepo.IssueCredential(participant.ID)
+	if err != nil {
+		fmt.Printf("참여자 %s 에이전트 비밀 키 발급 오류: %v\n", participant.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "에이전트 비밀 키 발급에 실패했습니다"})
+		return
+	}
+
+	audit.Annotate(c, "participant.agent.credential.issue", "participants", participant.ID)
+
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{
+		"participant_id":   participant.ID,
+		"secret":           secret,
+		"protocol_version": participantagent.ProtocolVersion,
+		"callback_url":     participantagent.CallbackURL(participantAgentCallbackBaseURL(), participant.ID),
+	}})
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/services/pricing"
+)
+
+// 가격표 업로드 최대 크기 (10MB)
+const maxPriceSheetSize = 10 << 20
+
+// PriceCatalogHandler는 클라우드 가격표 스냅샷 관련 API 핸들러입니다
+type PriceCatalogHandler struct {
+	catalog *pricing.Catalog
+}
+
+// NewPriceCatalogHandler는 새 PriceCatalogHandler 인스턴스를 생성합니다
+func NewPriceCatalogHandler(catalog *pricing.Catalog) *PriceCatalogHandler {
+	return &PriceCatalogHandler{catalog: catalog}
+}
+
+// ImportPriceSheet는 CSV/JSON 가격표를 새 스냅샷으로 임포트합니다
+// multipart 파일(file) 또는 요청 본문(Content-Type: text/csv, application/json)을 받습니다
+func (h *PriceCatalogHandler) ImportPriceSheet(c *gin.Context) {
+	userID := c.GetInt64("userID")
+
+	var data []byte
+	format := strings.ToLower(c.PostForm("format"))
+	if format == "" {
+		format = strings.ToLower(c.Query("format"))
+	}
+	source := c.PostForm("source")
+
+	if file, err := c.FormFile("file"); err == nil {
+		if file.Size > maxPriceSheetSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "가격표 파일이 너무 큽니다 (최대 10MB)"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "가격표 파일을 열 수 없습니다"})
+			return
+		}
+		defer f.Close()
+		if data, err = io.ReadAll(f); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "가격표 파일을 읽을 수 없습니다"})
+			return
+		}
+		if format == "" {
+			format = pricing.DetectFormat(file.Filename)
+		}
+		if source == "" {
+			source = file.Filename
+		}
+	} else {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPriceSheetSize+1))
+		if err != nil || len(body) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "가격표 파일(file) 또는 요청 본문이 필요합니다"})
+			return
+		}
+		if len(body) > maxPriceSheetSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "가격표가 너무 큽니다 (최대 10MB)"})
+			return
+		}
+		data = body
+		if format == "" {
+			switch contentType := c.ContentType(); {
+			case strings.Contains(contentType, "json"):
+				format = pricing.FormatJSON
+			case strings.Contains(contentType, "csv"):
+				format = pricing.FormatCSV
+			}
+		}
+		if source == "" {
+			source = c.Query("source")
+		}
+	}
+	if source == "" {
+		source = "api"
+	}
+
+	effectiveAt, err := parseEffectiveAt(firstNonEmpty(c.PostForm("effectiveAt"), c.Query("effectiveAt")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "effectiveAt은 RFC3339 또는 YYYY-MM-DD 형식이어야 합니다"})
+		return
+	}
+
+	rows, err := pricing.ParseSheet(bytes.NewReader(data), format)
+	if err != nil {
+		var sheetErr *pricing.SheetError
+		switch {
+		case errors.As(err, &sheetErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "가격표 검증에 실패했습니다", "problems": sheetErr.Problems})
+		case errors.Is(err, pricing.ErrUnsupportedFormat):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "지원하지 않는 가격표 형식입니다 (csv, json)"})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	result, err := h.catalog.Import(pricing.ImportRequest{
+		Rows:        rows,
+		Format:      format,
+		Source:      source,
+		Note:        firstNonEmpty(c.PostForm("note"), c.Query("note")),
+		EffectiveAt: effectiveAt,
+		CreatedBy:   &userID,
+	})
+	if err != nil {
+		if errors.Is(err, pricing.ErrIdenticalSnapshot) {
+			c.JSON(http.StatusConflict, gin.H{"error": "최신 가격 스냅샷과 내용이 같습니다"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "가격표 임포트에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": result})
+}
+
+// GetPriceSnapshots는 가격 스냅샷 목록을 조회합니다
+func (h *PriceCatalogHandler) GetPriceSnapshots(c *gin.Context) {
+	snapshots, err := h.catalog.ListSnapshots()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "가격 스냅샷 목록 조회에 실패했습니다"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": snapshots})
+}
+
+// GetPriceSnapshot은 특정 버전의 가격 스냅샷과 가격 항목을 조회합니다
+func (h *PriceCatalogHandler) GetPriceSnapshot(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 스냅샷 버전입니다"})
+		return
+	}
+
+	snapshot, items, err := h.catalog.GetSnapshot(version)
+	if err != nil {
+		if errors.Is(err, pricing.ErrSnapshotNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "가격 스냅샷을 찾을 수 없습니다"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "가격 스냅샷 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"snapshot": snapshot, "items": items}})
+}
+
+// GetPriceSnapshotDiff는 스냅샷과 이전 버전(또는 against 버전)의 차이를 조회합니다
+func (h *PriceCatalogHandler) GetPriceSnapshotDiff(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 스냅샷 버전입니다"})
+		return
+	}
+
+	var against *int
+	if value := c.Query("against"); value != "" {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 비교 대상 버전입니다"})
+			return
+		}
+		against = &v
+	}
+
+	diff, err := h.catalog.DiffVersions(version, against)
+	if err != nil {
+		if errors.Is(err, pricing.ErrSnapshotNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "가격 스냅샷을 찾을 수 없습니다"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "가격 스냅샷 비교에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": diff})
+}
+
+// parseEffectiveAt은 적용 시점을 파싱합니다 (비어있으면 즉시 적용)
+func parseEffectiveAt(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
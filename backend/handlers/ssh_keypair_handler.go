@@ -1,35 +1,39 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
 	"github.com/Mungge/Fleecy-Cloud/services"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+	"github.com/Mungge/Fleecy-Cloud/utils"
 	"github.com/gin-gonic/gin"
 )
 
 type SSHKeypairHandler struct {
-	service *services.SSHKeypairService
+	service        *services.SSHKeypairService
+	aggregatorRepo *repository.AggregatorRepository
 }
 
-func NewSSHKeypairHandler(service *services.SSHKeypairService) *SSHKeypairHandler {
-	return &SSHKeypairHandler{service: service}
+func NewSSHKeypairHandler(service *services.SSHKeypairService, aggregatorRepo *repository.AggregatorRepository) *SSHKeypairHandler {
+	return &SSHKeypairHandler{service: service, aggregatorRepo: aggregatorRepo}
 }
 
 // GetKeypairByAggregatorID 집계자 ID로 SSH 키페어 조회 (Private Key 제외)
 // GET /api/keypairs/aggregator/:aggregatorId
 func (h *SSHKeypairHandler) GetKeypairByAggregatorID(c *gin.Context) {
 	aggregatorID := c.Param("aggregatorId")
-	if aggregatorID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "aggregator ID is required",
-		})
+	if _, ok := h.authorizeAggregator(c, aggregatorID, authz.ActionView); !ok {
 		return
 	}
 
 	keypair, err := h.service.GetKeypairByAggregatorID(aggregatorID)
 	if err != nil {
-		if err.Error() == "keypair not found for aggregator: "+aggregatorID {
+		if errors.Is(err, services.ErrKeypairNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "keypair not found",
 			})
@@ -49,16 +53,17 @@ func (h *SSHKeypairHandler) GetKeypairByAggregatorID(c *gin.Context) {
 // GET /api/keypairs/aggregator/:aggregatorId/private-key
 func (h *SSHKeypairHandler) DownloadPrivateKey(c *gin.Context) {
 	aggregatorID := c.Param("aggregatorId")
-	if aggregatorID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "aggregator ID is required",
-		})
+	// 거부된 시도도 남도록 권한 확인 전에 감사 대상으로 지정
+	audit.Annotate(c, "keypair.private_key.download", "aggregators", aggregatorID)
+
+	// Private Key는 집계자 관리 권한(admin 이상)이 있어야 받을 수 있음
+	if _, ok := h.authorizeAggregator(c, aggregatorID, authz.ActionManage); !ok {
 		return
 	}
 
 	keypairWithPrivateKey, err := h.service.GetKeypairWithPrivateKey(aggregatorID)
 	if err != nil {
-		if err.Error() == "keypair not found for aggregator: "+aggregatorID {
+		if errors.Is(err, services.ErrKeypairNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "keypair not found",
 			})
@@ -78,7 +83,7 @@ func (h *SSHKeypairHandler) DownloadPrivateKey(c *gin.Context) {
 	c.String(http.StatusOK, keypairWithPrivateKey.PrivateKey)
 }
 
-// ListKeypairsByUser 사용자의 모든 키페어 목록 조회
+// ListKeypairsByUser 사용자의 현재 조직 키페어 목록 조회
 // GET /api/keypairs/user/:userId
 func (h *SSHKeypairHandler) ListKeypairsByUser(c *gin.Context) {
 	userID := c.Param("userId")
@@ -98,7 +103,19 @@ func (h *SSHKeypairHandler) ListKeypairsByUser(c *gin.Context) {
 		return
 	}
 
-	keypairs, err := h.service.ListKeypairsByUserID(userIDInt)
+	// 다른 사용자의 목록은 조회할 수 없음
+	principal, ok := authz.AuthorizeActive(c, authz.ActionView)
+	if !ok {
+		return
+	}
+	if userIDInt != principal.UserID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "cannot list keypairs of another user",
+		})
+		return
+	}
+
+	keypairs, err := h.service.ListKeypairsByOrganizationID(principal.ActiveOrganizationID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "failed to list keypairs",
@@ -115,16 +132,13 @@ func (h *SSHKeypairHandler) ListKeypairsByUser(c *gin.Context) {
 // DELETE /api/keypairs/aggregator/:aggregatorId
 func (h *SSHKeypairHandler) DeleteKeypairByAggregatorID(c *gin.Context) {
 	aggregatorID := c.Param("aggregatorId")
-	if aggregatorID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "aggregator ID is required",
-		})
+	if _, ok := h.authorizeAggregator(c, aggregatorID, authz.ActionManage); !ok {
 		return
 	}
 
 	err := h.service.DeleteKeypairByAggregatorID(aggregatorID)
 	if err != nil {
-		if err.Error() == "keypair not found for aggregator: "+aggregatorID {
+		if errors.Is(err, services.ErrKeypairNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "keypair not found",
 			})
@@ -141,3 +155,139 @@ func (h *SSHKeypairHandler) DeleteKeypairByAggregatorID(c *gin.Context) {
 		"message": "keypair deleted successfully",
 	})
 }
+
+// RepinHostKeyRequest 호스트 키 재고정 요청
+type RepinHostKeyRequest struct {
+	// HostKey 콘솔 출력 등으로 확인한 호스트 키 (authorized_keys 형식, 비어있으면 서버에서 조회)
+	HostKey string `json:"hostKey"`
+	// ExpectedFingerprint 서버에서 조회한 키가 이 SHA256 지문과 일치할 때만 고정 (HostKey가 없으면 필수)
+	ExpectedFingerprint string `json:"expectedFingerprint"`
+}
+
+// GetHostKey 집계자에 고정된 SSH 호스트 키 조회
+// GET /api/keypairs/aggregator/:aggregatorId/host-key
+func (h *SSHKeypairHandler) GetHostKey(c *gin.Context) {
+	aggregatorID := c.Param("aggregatorId")
+	if _, ok := h.authorizeAggregator(c, aggregatorID, authz.ActionView); !ok {
+		return
+	}
+
+	keypair, err := h.service.GetKeypairByAggregatorID(aggregatorID)
+	if err != nil {
+		if errors.Is(err, services.ErrKeypairNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "keypair not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "failed to get keypair",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"aggregator_id":        keypair.AggregatorID,
+			"pinned":               keypair.HostKey != "",
+			"host_key":             keypair.HostKey,
+			"host_key_fingerprint": keypair.HostKeyFingerprint,
+			"host_key_pinned_at":   keypair.HostKeyPinnedAt,
+		},
+	})
+}
+
+// RepinHostKey 인스턴스 재구성 후 집계자 SSH 호스트 키 재고정
+// POST /api/keypairs/aggregator/:aggregatorId/host-key/repin
+func (h *SSHKeypairHandler) RepinHostKey(c *gin.Context) {
+	aggregatorID := c.Param("aggregatorId")
+	audit.Annotate(c, "keypair.host_key.repin", "aggregators", aggregatorID)
+	aggregator, ok := h.authorizeAggregator(c, aggregatorID, authz.ActionManage)
+	if !ok {
+		return
+	}
+
+	var req RepinHostKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "invalid request body",
+			})
+			return
+		}
+	}
+	// 확인되지 않은 키를 고정하지 않도록 키 자체나 다른 경로로 확인한 지문 중 하나는 필수
+	if req.HostKey == "" && req.ExpectedFingerprint == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "hostKey or expectedFingerprint is required",
+		})
+		return
+	}
+	if req.HostKey == "" && aggregator.PublicIP == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "aggregator has no public IP; provide hostKey",
+		})
+		return
+	}
+
+	keypair, previous, err := h.service.RepinHostKey(aggregatorID, aggregator.PublicIP, req.HostKey, req.ExpectedFingerprint)
+	if err != nil {
+		var mismatch *utils.HostKeyMismatchError
+		switch {
+		case errors.As(err, &mismatch):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":    "host key fingerprint does not match expectedFingerprint",
+				"expected": mismatch.Expected,
+				"actual":   mismatch.Actual,
+			})
+		case errors.Is(err, services.ErrKeypairNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "keypair not found",
+			})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error": fmt.Sprintf("failed to repin host key: %v", err),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"aggregator_id":                 keypair.AggregatorID,
+			"host_key":                      keypair.HostKey,
+			"host_key_fingerprint":          keypair.HostKeyFingerprint,
+			"host_key_pinned_at":            keypair.HostKeyPinnedAt,
+			"previous_host_key_fingerprint": previous,
+		},
+	})
+}
+
+// authorizeAggregator 집계자 소유 조직에서 요청 사용자의 역할로 작업 권한 확인
+func (h *SSHKeypairHandler) authorizeAggregator(c *gin.Context, aggregatorID string, action authz.Action) (*models.Aggregator, bool) {
+	if aggregatorID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "aggregator ID is required",
+		})
+		return nil, false
+	}
+
+	aggregator, err := h.aggregatorRepo.GetAggregatorByID(aggregatorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "failed to get aggregator",
+		})
+		return nil, false
+	}
+	if aggregator == nil || !authz.PrincipalFromContext(c).IsMember(aggregator.OrganizationID) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "aggregator not found",
+		})
+		return nil, false
+	}
+	if !authz.Authorize(c, aggregator.OrganizationID, action) {
+		return nil, false
+	}
+	return aggregator, true
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+)
+
+// maxTaskBundleRequestBytes는 번들 업로드 요청 본문 최대 크기입니다 (JSON 이스케이프 여유 포함)
+const maxTaskBundleRequestBytes = 4 * models.MaxTaskBundleSizeBytes
+
+// TaskBundleHandler는 사용자 모델/학습 번들(task bundle) 관리 요청을 처리합니다
+type TaskBundleHandler struct {
+	repo *repository.TaskBundleRepository
+}
+
+// NewTaskBundleHandler는 새 TaskBundleHandler 인스턴스를 생성합니다
+func NewTaskBundleHandler(repo *repository.TaskBundleRepository) *TaskBundleHandler {
+	return &TaskBundleHandler{repo: repo}
+}
+
+type createTaskBundleRequest struct {
+	Name            string                 `json:"name" binding:"required"`
+	Description     string                 `json:"description"`
+	Files           map[string]string      `json:"files" binding:"required"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters"`
+	Dependencies    []string               `json:"dependencies"`
+}
+
+// GetTaskBundles는 활성 조직의 번들 버전 목록을 반환합니다 (?name=으로 특정 번들의 버전만 조회)
+func (h *TaskBundleHandler) GetTaskBundles(c *gin.Context) {
+	principal, ok := authz.AuthorizeActive(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	bundles, err := h.repo.ListByOrganizationID(principal.ActiveOrganizationID, c.Query("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "번들 목록 조회에 실패했습니다"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": bundles})
+}
+
+// GetTaskBundle은 번들 버전을 파일 내용과 함께 반환합니다
+func (h *TaskBundleHandler) GetTaskBundle(c *gin.Context) {
+	bundle, ok := h.loadBundle(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	bundle.FileNames = bundle.SortedFileNames()
+	c.JSON(http.StatusOK, gin.H{"data": bundle})
+}
+
+// CreateTaskBundle은 번들을 검증하고 같은 이름의 다음 버전으로 저장합니다
+// 마지막 버전과 내용이 같으면 새 버전을 만들지 않고 기존 버전을 반환합니다
+func (h *TaskBundleHandler) CreateTaskBundle(c *gin.Context) {
+	principal, ok := authz.AuthorizeActive(c, authz.ActionManage)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxTaskBundleRequestBytes)
+	var req createTaskBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "번들 요청이 너무 큽니다"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청 형식입니다: " + err.Error()})
+		return
+	}
+
+	bundle := &models.TaskBundle{
+		ID:              uuid.New().String(),
+		OrganizationID:  principal.ActiveOrganizationID,
+		CreatedByUserID: principal.UserID,
+		Name:            strings.TrimSpace(req.Name),
+		Description:     req.Description,
+		Files:           req.Files,
+		Hyperparameters: req.Hyperparameters,
+		Dependencies:    req.Dependencies,
+	}
+	if err := bundle.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 번들입니다: " + err.Error()})
+		return
+	}
+	bundle.ContentHash = bundle.ComputeContentHash()
+
+	saved, created, err := h.repo.CreateNextVersion(bundle)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "번들 저장에 실패했습니다"})
+		return
+	}
+	saved.FileNames = saved.SortedFileNames()
+
+	audit.Annotate(c, "task_bundle.create", "task-bundles", saved.ID)
+	audit.AddDetail(c, "name", saved.Name)
+	audit.AddDetail(c, "version", saved.Version)
+	audit.AddDetail(c, "content_hash", saved.ContentHash)
+
+	if !created {
+		c.JSON(http.StatusOK, gin.H{"data": saved, "message": "마지막 버전과 내용이 같아 새 버전을 만들지 않았습니다"})
+		return
+	}
+	log.Printf("번들 업로드: 조직 %d, %s v%d (%s)", saved.OrganizationID, saved.Name, saved.Version, saved.ContentHash)
+	c.JSON(http.StatusCreated, gin.H{"data": saved})
+}
+
+// DeleteTaskBundle은 연합학습 작업이 사용하지 않는 번들 버전을 삭제합니다
+func (h *TaskBundleHandler) DeleteTaskBundle(c *gin.Context) {
+	bundle, ok := h.loadBundle(c, authz.ActionManage)
+	if !ok {
+		return
+	}
+
+	referenced, err := h.repo.IsReferenced(bundle.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "번들 사용 여부 확인에 실패했습니다"})
+		return
+	}
+	if referenced {
+		c.JSON(http.StatusConflict, gin.H{"error": "연합학습 작업이 사용 중인 번들 버전은 삭제할 수 없습니다"})
+		return
+	}
+
+	if err := h.repo.Delete(bundle.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "번들 삭제에 실패했습니다"})
+		return
+	}
+
+	audit.Annotate(c, "task_bundle.delete", "task-bundles", bundle.ID)
+	audit.AddDetail(c, "name", bundle.Name)
+	audit.AddDetail(c, "version", bundle.Version)
+
+	c.JSON(http.StatusOK, gin.H{"message": "번들 버전이 삭제되었습니다"})
+}
+
+// loadBundle은 경로의 번들 버전을 조회하고 소유 조직 권한을 확인합니다
+func (h *TaskBundleHandler) loadBundle(c *gin.Context, action authz.Action) (*models.TaskBundle, bool) {
+	bundle, err := h.repo.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "번들 조회에 실패했습니다"})
+		return nil, false
+	}
+	if bundle == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "번들을 찾을 수 없습니다"})
+		return nil, false
+	}
+	if !authz.Authorize(c, bundle.OrganizationID, action) {
+		return nil, false
+	}
+	return bundle, true
+}
@@ -17,16 +17,21 @@ class FlowerClient(NumPyClient):
         self.trainloader = trainloader
         self.valloader = valloader
         self.local_epochs = local_epochs
+        self.round = 0
         self.device = torch.device("cuda:0" if torch.cuda.is_available() else "cpu")
         self.net.to(self.device)
 
     def fit(self, parameters, config):
+        # 참여자 에이전트가 이 줄로 라운드 진행을 감지 (cmd/participant-agent roundMarker)
+        self.round += 1
+        print(f"[Client] Round {self.round} started", flush=True)
         set_weights(self.net, parameters)
         train_loss = train(
             self.net,
             self.trainloader,
             self.local_epochs,
             self.device,
+            proximal_mu=float(config.get("proximal_mu", 0.0)),  # FedProx 전략이 fit 설정으로 전달
         )
         return (
             get_weights(self.net),
@@ -12,7 +12,7 @@ from pathlib import Path
 import flwr as fl
 from flwr.common import Context, ndarrays_to_parameters, parameters_to_ndarrays
 from flwr.server import ServerApp, ServerAppComponents, ServerConfig
-from flwr.server.strategy import FedAvg
+from flwr.server.strategy import FedAdagrad, FedAdam, FedAvg, FedProx, FedYogi
 
 from task import Net, get_weights, set_weights
 
@@ -40,10 +40,21 @@ def _metrics_agg_eval(metrics):
         agg[k] = sum(n * float(m.get(k, 0.0)) for n, m in metrics) / tot
     return agg
 
-# 라운드 종료 시마다 체크포인트 저장하는 커스텀 전략
-class SaveFedAvg(FedAvg):
-    def __init__(self, *, mlflow_conf: dict | None = None, **kwargs):
+# 지원하는 집계 알고리즘 (백엔드 models.SupportedAlgorithms와 동일하게 유지)
+STRATEGIES = {
+    "fedavg": FedAvg,
+    "fedprox": FedProx,
+    "fedadam": FedAdam,
+    "fedyogi": FedYogi,
+    "fedadagrad": FedAdagrad,
+}
+
+# 라운드 종료 시마다 체크포인트 저장하는 전략 믹스인 (모든 Flower 전략과 조합)
+class SaveStrategyMixin:
+    def __init__(self, *, mlflow_conf: dict | None = None, round_offset: int = 0, **kwargs):
         super().__init__(**kwargs)
+        # 체크포인트에서 재시작한 경우 이전 라운드 수 (체크포인트 이름과 MLflow step은 전체 라운드 번호 사용)
+        self.round_offset = round_offset
         self.mlflow_enabled = mlflow is not None
         self._mlflow_run = None
         self.round_start_time = None
@@ -65,22 +76,24 @@ class SaveFedAvg(FedAvg):
     def configure_fit(self, server_round, parameters, client_manager):
         """라운드 시작 시 시간 측정 시작"""
         self.round_start_time = time.time()
-        print(f"[Server] Round {server_round} started at {time.strftime('%Y-%m-%d %H:%M:%S')}")
+        print(f"[Server] Round {server_round + self.round_offset} started at {time.strftime('%Y-%m-%d %H:%M:%S')}")
         return super().configure_fit(server_round, parameters, client_manager)
     
     def aggregate_fit(self, server_round, results, failures):
+        global_round = server_round + self.round_offset
+
         # 라운드 실행시간 계산
         round_end_time = time.time()
         if self.round_start_time is not None:
             round_duration = round_end_time - self.round_start_time
-            self.round_times[server_round] = round_duration
-            print(f"[Server] Round {server_round} completed in {round_duration:.2f} seconds")
+            self.round_times[global_round] = round_duration
+            print(f"[Server] Round {global_round} completed in {round_duration:.2f} seconds")
             
             # MLflow에 라운드 시간 로깅
             if self.mlflow_enabled and self._mlflow_run:
-                mlflow.log_metric(f"round_duration_seconds", round_duration, step=server_round)
+                mlflow.log_metric(f"round_duration_seconds", round_duration, step=global_round)
         
-        # 표준 FedAvg 집계
+        # 선택된 전략의 집계
         aggregated_params, aggregated_metrics = super().aggregate_fit(server_round, results, failures)
 
         # 체크포인트 저장
@@ -91,7 +104,7 @@ class SaveFedAvg(FedAvg):
 
             ckpt_dir = Path("./checkpoints")
             ckpt_dir.mkdir(parents=True, exist_ok=True)
-            ckpt_path = ckpt_dir / f"round-{server_round:03d}.pt"
+            ckpt_path = ckpt_dir / f"round-{global_round:03d}.pt"
 
             try:
                 import torch
@@ -100,16 +113,16 @@ class SaveFedAvg(FedAvg):
                 if self.mlflow_enabled and self._mlflow_run:
                     mlflow.log_artifact(ckpt_path.as_posix(), artifact_path="checkpoints")
             except Exception as e:
-                print(f"[Server] Failed to save checkpoint for round {server_round}: {e}")
+                print(f"[Server] Failed to save checkpoint for round {global_round}: {e}")
 
         # fit 메트릭 로깅 (train_loss 등)
         if aggregated_metrics:
-            self._ml_log(aggregated_metrics, step=server_round)
+            self._ml_log(aggregated_metrics, step=global_round)
 
         return aggregated_params, aggregated_metrics
     
     def aggregate_evaluate(self, server_round, results, failures):
-        # 표준 FedAvg 평가 집계(평균 loss 반환)
+        # 선택된 전략의 평가 집계(평균 loss 반환)
         aggregated_loss, aggregated_metrics = super().aggregate_evaluate(server_round, results, failures)
 
         # eval 메트릭 로깅 (val_loss + accuracy)
@@ -118,7 +131,7 @@ class SaveFedAvg(FedAvg):
             to_log["val_loss"] = float(aggregated_loss)
         if aggregated_metrics:
             to_log.update(aggregated_metrics)
-        self._ml_log(to_log, step=server_round)
+        self._ml_log(to_log, step=server_round + self.round_offset)
 
         return aggregated_loss, aggregated_metrics
 
@@ -160,7 +173,36 @@ class SaveFedAvg(FedAvg):
                 mlflow.end_run()
             except Exception:
                 pass       
-    
+
+
+def build_strategy(config: dict, **kwargs):
+    """설정의 algorithm과 하이퍼파라미터로 체크포인트 저장 전략을 생성합니다."""
+    algorithm = str(config.get("algorithm", "fedavg")).strip().lower()
+    base = STRATEGIES.get(algorithm)
+    if base is None:
+        raise ValueError(
+            f"Unsupported aggregation algorithm: {algorithm} (supported: {', '.join(STRATEGIES)})"
+        )
+
+    if algorithm == "fedprox":
+        kwargs["proximal_mu"] = float(config.get("proximal-mu", 0.01))
+    elif algorithm in ("fedadam", "fedyogi", "fedadagrad"):
+        kwargs["eta"] = float(config.get("server-learning-rate", 0.1))
+        kwargs["eta_l"] = float(config.get("client-learning-rate", 0.1))
+        kwargs["tau"] = float(config.get("tau", 1e-9 if algorithm == "fedadagrad" else 1e-3))
+        if algorithm != "fedadagrad":
+            kwargs["beta_1"] = float(config.get("beta1", 0.9))
+            kwargs["beta_2"] = float(config.get("beta2", 0.99))
+
+    strategy_cls = type(f"Save{base.__name__}", (SaveStrategyMixin, base), {})
+    print(f"[Server] Aggregation strategy: {base.__name__}")
+    return strategy_cls(**kwargs)
+
+
+def strategy_params(config: dict) -> dict:
+    """MLflow에 기록할 전략 하이퍼파라미터를 반환합니다."""
+    keys = ("algorithm", "proximal-mu", "server-learning-rate", "client-learning-rate", "beta1", "beta2", "tau")
+    return {k.replace("-", "_"): config[k] for k in keys if k in config}
 
 
 def server_fn(context: Context) -> ServerAppComponents:
@@ -172,7 +214,8 @@ def server_fn(context: Context) -> ServerAppComponents:
     initial_parameters = ndarrays_to_parameters(get_weights(Net()))
 
     # 전략: 집계 함수/MLflow 포함
-    strategy = SaveFedAvg(
+    strategy = build_strategy(
+        context.run_config,
         fraction_fit=fraction_fit,
         fraction_evaluate=1.0,                 # 필요 시 0.3~0.5로 낮추면 메모리 절약
         min_fit_clients=int(context.run_config.get("min-fit-clients", 1)),
@@ -198,6 +241,7 @@ def server_fn(context: Context) -> ServerAppComponents:
         le = context.run_config.get("local-epochs")
         if le is not None:
             params["local_epochs"] = le
+        params.update(strategy_params(context.run_config))
         mlflow.log_params(params)
 
     config = ServerConfig(num_rounds=num_rounds)
@@ -208,6 +252,16 @@ def server_fn(context: Context) -> ServerAppComponents:
 app = ServerApp(server_fn=server_fn)
 
 
+def load_initial_parameters(checkpoint: str | None):
+    """체크포인트가 주어지면 그 가중치로, 아니면 새로 초기화한 모델로 초기 파라미터를 만듭니다."""
+    net = Net()
+    if checkpoint:
+        import torch
+        net.load_state_dict(torch.load(checkpoint, map_location="cpu"))
+        print(f"[Server] Warm start from checkpoint: {checkpoint}")
+    return ndarrays_to_parameters(get_weights(net))
+
+
 # TOML 파일에서 설정을 읽는 함수
 def read_toml_config():
     try:
@@ -246,6 +300,10 @@ def main():
                        help="Minimum number of available clients")
     parser.add_argument("--fraction-fit", type=float, default=1.0,
                        help="Fraction of clients to use for fit")
+    parser.add_argument("--initial-checkpoint", default=None,
+                       help="Checkpoint (state_dict) to warm-start the global model from")
+    parser.add_argument("--start-round", type=int, default=0,
+                       help="Rounds already completed by the checkpoint (training resumes at start-round + 1)")
     
     args = parser.parse_args()
     
@@ -257,20 +315,31 @@ def main():
     min_fit_clients = args.min_fit_clients or toml_config.get("min-fit-clients", 1)
     min_available_clients = args.min_available_clients or toml_config.get("min-available-clients", 1)
     fraction_fit = args.fraction_fit if args.fraction_fit != 1.0 else toml_config.get("fraction-fit", 1.0)
+
+    # 체크포인트 재시작이면 남은 라운드만 실행
+    start_round = max(args.start_round, 0)
+    remaining_rounds = num_rounds - start_round
+    if remaining_rounds <= 0:
+        print(f"Error: start round {start_round} must be less than the number of rounds {num_rounds}")
+        sys.exit(1)
     
     print(f"=== Flower Server Configuration ===")
     print(f"Server address: {args.server_address}")
     print(f"Number of rounds: {num_rounds}")
+    if start_round > 0:
+        print(f"Resuming after round: {start_round} ({remaining_rounds} rounds remaining)")
     print(f"Min fit clients: {min_fit_clients}")
     print(f"Min available clients: {min_available_clients}")
     print(f"Fraction fit: {fraction_fit}")
+    print(f"Algorithm: {toml_config.get('algorithm', 'fedavg')}")
     print(f"===================================")
     
-    # 초기 파라미터
-    initial_parameters = ndarrays_to_parameters(get_weights(Net()))
+    # 초기 파라미터 (재시작이면 체크포인트 가중치)
+    initial_parameters = load_initial_parameters(args.initial_checkpoint)
     
     # 전략 생성
-    strategy = SaveFedAvg(
+    strategy = build_strategy(
+        toml_config,
         fraction_fit=fraction_fit,
         fraction_evaluate=1.0,
         min_fit_clients=min_fit_clients,
@@ -278,6 +347,7 @@ def main():
         initial_parameters=initial_parameters,
         fit_metrics_aggregation_fn=_metrics_agg_fit,
         evaluate_metrics_aggregation_fn=_metrics_agg_eval,
+        round_offset=start_round,
         mlflow_conf={
             "tracking_uri": os.environ.get("MLFLOW_TRACKING_URI", "file:./mlruns"),
             "experiment_name": os.environ.get("MLFLOW_EXPERIMENT_NAME", "flower-demo"),
@@ -293,6 +363,9 @@ def main():
             "min_fit_clients": min_fit_clients,
             "min_available_clients": min_available_clients
         }
+        if start_round > 0:
+            params["start_round"] = start_round
+        params.update(strategy_params(toml_config))
         mlflow.log_params(params)
     
     # 서버 시작
@@ -304,7 +377,7 @@ def main():
     try:
         fl.server.start_server(
             server_address=args.server_address,
-            config=fl.server.ServerConfig(num_rounds=num_rounds),
+            config=fl.server.ServerConfig(num_rounds=remaining_rounds),
             strategy=strategy,
         )
     finally:
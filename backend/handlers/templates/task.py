@@ -141,15 +141,17 @@ def load_data(partition_id: int = 0, num_partitions: int = 1) -> Tuple[DataLoade
 # =========================
 # 3) Train / Eval
 # =========================
-def train(net: nn.Module, trainloader: DataLoader, epochs: int, device: torch.device) -> float:
+def train(net: nn.Module, trainloader: DataLoader, epochs: int, device: torch.device, proximal_mu: float = 0.0) -> float:
     """
     로컬 학습 루프. 평균 train loss를 반환합니다.
     - 손실: CrossEntropyLoss (Keras categorical/sparse 대응)
     - 옵티마이저: Adam(lr=1e-3)
+    - proximal_mu > 0 이면 FedProx proximal term (mu/2 * ||w - w_global||^2)을 손실에 더합니다
     """
     net.to(device)
     criterion = nn.CrossEntropyLoss().to(device)
     optimizer = torch.optim.Adam(net.parameters(), lr=float(os.environ.get("LR", "1e-3")))
+    global_params = [p.detach().clone() for p in net.parameters()] if proximal_mu > 0 else None
 
     net.train()
     running_loss = 0.0
@@ -163,6 +165,12 @@ def train(net: nn.Module, trainloader: DataLoader, epochs: int, device: torch.de
             optimizer.zero_grad(set_to_none=True)
             logits = net(images)               # (B, 3)
             loss = criterion(logits, labels)   # CE
+            if global_params is not None:
+                proximal_term = sum(
+                    torch.sum((local - global_) ** 2)
+                    for local, global_ in zip(net.parameters(), global_params)
+                )
+                loss = loss + (proximal_mu / 2) * proximal_term
             loss.backward()
             optimizer.step()
 
@@ -9,7 +9,7 @@ import (
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
 	"github.com/Mungge/Fleecy-Cloud/services"
-	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
 	"github.com/gin-gonic/gin"
 )
 
@@ -30,16 +30,8 @@ func NewVirtualMachineHandler(participantRepo *repository.ParticipantRepository)
 }
 
 func (h *VirtualMachineHandler) SelectOptimalVM(c *gin.Context) {
-	userID := utils.GetUserIDFromMiddleware(c)
-	participantID := c.Param("id")
-
-	participant, err := h.participantRepo.GetByID(participantID)
-	if err != nil || participant == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "참여자를 찾을 수 없습니다"})
-		return
-	}
-	if participant.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "권한이 없습니다"})
+	participant, err := h.getParticipantWithAuth(c, authz.ActionOperate)
+	if err != nil {
 		return
 	}
 
@@ -114,7 +106,7 @@ func createMockCriteria(modelSizeMB int) services.VMSelectionCriteria {
 
 func (h *VirtualMachineHandler) GetVMStats(c *gin.Context) {
 	// 권한 확인 로직...
-	participant, err := h.getParticipantWithAuth(c)
+	participant, err := h.getParticipantWithAuth(c, authz.ActionView)
 	if err != nil {
 		return // 에러는 getParticipantWithAuth에서 처리
 	}
@@ -142,7 +134,7 @@ func (h *VirtualMachineHandler) GetVMStats(c *gin.Context) {
 }
 
 func (h *VirtualMachineHandler) GetVMRequests(c *gin.Context) {
-	participant, err := h.getParticipantWithAuth(c)
+	participant, err := h.getParticipantWithAuth(c, authz.ActionView)
 	if err != nil {
 		return
 	}
@@ -171,7 +163,7 @@ func (h *VirtualMachineHandler) GetVMRequests(c *gin.Context) {
 
 // 나머지 메서드들...
 func (h *VirtualMachineHandler) GetVMUtilizations(c *gin.Context) {
-	participant, err := h.getParticipantWithAuth(c)
+	participant, err := h.getParticipantWithAuth(c, authz.ActionView)
 	if err != nil {
 		return
 	}
@@ -193,7 +185,7 @@ func (h *VirtualMachineHandler) GetVMUtilizations(c *gin.Context) {
 }
 
 func (h *VirtualMachineHandler) ResetVMSelectionRoundRobin(c *gin.Context) {
-	participant, err := h.getParticipantWithAuth(c)
+	participant, err := h.getParticipantWithAuth(c, authz.ActionOperate)
 	if err != nil {
 		return
 	}
@@ -206,17 +198,18 @@ func (h *VirtualMachineHandler) ResetVMSelectionRoundRobin(c *gin.Context) {
 }
 
 // 헬퍼 함수들
-func (h *VirtualMachineHandler) getParticipantWithAuth(c *gin.Context) (*models.Participant, error) {
-	userID := utils.GetUserIDFromMiddleware(c)
+func (h *VirtualMachineHandler) getParticipantWithAuth(c *gin.Context, action authz.Action) (*models.Participant, error) {
 	participantID := c.Param("id")
 
 	participant, err := h.participantRepo.GetByID(participantID)
 	if err != nil || participant == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "참여자를 찾을 수 없습니다"})
+		if err == nil {
+			err = fmt.Errorf("참여자 없음")
+		}
 		return nil, err
 	}
-	if participant.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "권한이 없습니다"})
+	if !authz.Authorize(c, participant.OrganizationID, action) {
 		return nil, fmt.Errorf("권한 없음")
 	}
 	return participant, nil
@@ -2,8 +2,12 @@ package initialization
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Mungge/Fleecy-Cloud/config"
 	aggregatorhandler "github.com/Mungge/Fleecy-Cloud/handlers/aggregator"
@@ -11,6 +15,10 @@ import (
 	"github.com/Mungge/Fleecy-Cloud/repository"
 	"github.com/Mungge/Fleecy-Cloud/services"
 	aggregatorservice "github.com/Mungge/Fleecy-Cloud/services/aggregator"
+	"github.com/Mungge/Fleecy-Cloud/services/optimizer"
+	"github.com/Mungge/Fleecy-Cloud/services/pricing"
+	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/utils/secrets"
 	"github.com/joho/godotenv"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"gorm.io/gorm"
@@ -18,17 +26,28 @@ import (
 
 // Repositories는 애플리케이션에서 사용되는 모든 리포지토리를 포함합니다
 type Repositories struct {
-	UserRepo         *repository.UserRepository
-	RefreshTokenRepo *repository.RefreshTokenRepository
-	CloudRepo        *repository.CloudRepository
-	FLRepo           *repository.FederatedLearningRepository
-	ParticipantRepo  *repository.ParticipantRepository
-	AggregatorRepo   *repository.AggregatorRepository
-	ProviderRepo     *repository.ProviderRepository
-	RegionRepo       *repository.RegionRepository
-	CloudPriceRepo   *repository.CloudPriceRepository
-	CloudLatencyRepo *repository.CloudLatencyRepository
-	SSHKeypairRepo   *repository.SSHKeypairRepository
+	UserRepo          *repository.UserRepository
+	RefreshTokenRepo  *repository.RefreshTokenRepository
+	UserTokenRepo     *repository.UserTokenRepository
+	OrgRepo           *repository.OrganizationRepository
+	APITokenRepo      *repository.APITokenRepository
+	AuditLogRepo      *repository.AuditLogRepository
+	CloudRepo         *repository.CloudRepository
+	FLRepo            *repository.FederatedLearningRepository
+	ParticipantRepo   *repository.ParticipantRepository
+	AggregatorRepo    *repository.AggregatorRepository
+	ProviderRepo      *repository.ProviderRepository
+	RegionRepo        *repository.RegionRepository
+	CloudPriceRepo    *repository.CloudPriceRepository
+	CloudLatencyRepo  *repository.CloudLatencyRepository
+	SSHKeypairRepo    *repository.SSHKeypairRepository
+	DeploymentRepo    *repository.AggregatorDeploymentRepository
+	FLStepRepo        *repository.FederatedLearningStepRepository
+	LatencySampleRepo *repository.LatencySampleRepository
+	PriceSnapshotRepo *repository.PriceSnapshotRepository
+	TaskBundleRepo    *repository.TaskBundleRepository
+	ModelVersionRepo  *repository.ModelVersionRepository
+	AgentRepo         *repository.ParticipantAgentRepository
 }
 
 // Dependencies는 애플리케이션의 모든 의존성을 관리합니다
@@ -41,6 +60,7 @@ type Dependencies struct {
 	MetricsService      *aggregatorservice.AggregatorMetricsService
 	TrainingService     *aggregatorservice.AggregatorTrainingService
 	OptimizationService aggregatorservice.OptimizationService
+	CostMeter           *aggregatorservice.CostMeter
 
 	// Aggregator Handler
 	AggregatorHandler *aggregatorhandler.AggregatorHandler
@@ -53,6 +73,20 @@ func InitializeApplication() error {
 	// 0. 환경 변수 로드(.env)
 	loadDotEnv()
 
+	// 0-1. 비밀 값 암호화 키 제공자 (모델 훅에서 사용하므로 DB 조회 전에 준비)
+	envelope, err := secrets.InitFromEnv()
+	if err != nil {
+		return fmt.Errorf("비밀 값 암호화 초기화 실패: %v", err)
+	}
+	log.Printf("비밀 값 암호화 제공자: %s (활성 키: %s)", envelope.Provider().Name(), envelope.Provider().ActiveKeyID())
+
+	// 0-2. JWT 서명/검증 키셋 (설정 오류는 첫 로그인 시점이 아니라 시작 시 드러나도록)
+	tokenService, err := utils.InitJWTFromEnv()
+	if err != nil {
+		return fmt.Errorf("JWT 키 초기화 실패: %v", err)
+	}
+	log.Printf("JWT 서명 키: %s (%s), 검증 허용 알고리즘: %v", tokenService.Keys().Active().ID, tokenService.Keys().Active().Method.Alg(), tokenService.Keys().Algorithms())
+
 	// 1. 데이터베이스 연결
 	if err := config.ConnectDatabase(); err != nil {
 		return err
@@ -83,11 +117,21 @@ func RunDatabaseMigration() error {
 		log.Printf("데이터 정리 중 오류 발생: %v", err)
 		// 오류가 발생해도 마이그레이션을 계속 진행
 	}
+
+	// 이메일 인증 도입 전 가입한 사용자는 인증 완료로 간주 (컬럼이 처음 추가될 때 한 번만)
+	backfillEmailVerified := db.Migrator().HasTable(&models.User{}) &&
+		!db.Migrator().HasColumn(&models.User{}, "EmailVerifiedAt")
+
 	err := db.AutoMigrate(
 		&models.Provider{},
 		&models.Region{},
 		&models.User{},
 		&models.RefreshToken{},
+		&models.UserToken{},
+		&models.APIToken{},
+		&models.AuditLog{},
+		&models.Organization{},
+		&models.OrganizationMember{},
 		&models.CloudConnection{},
 		&models.CloudPrice{},
 		&models.CloudLatency{},
@@ -97,15 +141,87 @@ func RunDatabaseMigration() error {
 		&models.ParticipantFederatedLearning{},
 		&models.TrainingRound{},
 		&models.SSHKeypair{},
+		&models.AggregatorDeployment{},
+		&models.FederatedLearningStep{},
+		&models.FederatedLearningStatusTransition{},
+		&models.LatencySample{},
+		&models.PriceSnapshot{},
+		&models.PriceSnapshotItem{},
+		&models.AggregatorRunPeriod{},
+		&models.RateLimitEntry{},
+		&models.TaskBundle{},
+		&models.ModelVersion{},
+		&models.ParticipantAgentCredential{},
 	)
 	if err != nil {
 		return err
 	}
 
+	if backfillEmailVerified {
+		result := db.Exec("UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL")
+		if result.Error != nil {
+			return fmt.Errorf("기존 사용자 이메일 인증 상태 갱신 실패: %v", result.Error)
+		}
+		log.Printf("기존 사용자 %d명을 이메일 인증 완료로 처리했습니다", result.RowsAffected)
+	}
+
+	if err := migrateLegacyAlgorithms(db); err != nil {
+		return err
+	}
+
+	// 감사 로그는 추가만 허용 (수정/삭제 시 DB에서 거부)
+	if err := db.Exec(auditLogAppendOnlySQL).Error; err != nil {
+		return fmt.Errorf("감사 로그 추가 전용 트리거 생성 실패: %v", err)
+	}
+
+	// 조직 도입 이전 사용자/리소스를 개인 조직으로 이전
+	created, err := repository.NewOrganizationRepository(db).BackfillPersonalOrganizations()
+	if err != nil {
+		return fmt.Errorf("개인 조직 생성 및 리소스 이전 실패: %v", err)
+	}
+	if created > 0 {
+		log.Printf("기존 사용자 %d명의 개인 조직을 생성했습니다", created)
+	}
+
 	log.Println("데이터베이스 마이그레이션 완료")
 	return nil
 }
 
+// auditLogAppendOnlySQL은 audit_logs 테이블의 UPDATE/DELETE/TRUNCATE를 막는 트리거입니다
+const auditLogAppendOnlySQL = `
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only (% not allowed)', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_no_update_delete ON audit_logs;
+CREATE TRIGGER audit_logs_no_update_delete
+	BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+
+DROP TRIGGER IF EXISTS audit_logs_no_truncate ON audit_logs;
+CREATE TRIGGER audit_logs_no_truncate
+	BEFORE TRUNCATE ON audit_logs
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
+`
+
+// migrateLegacyAlgorithms는 이전 버전이 저장한 알고리즘 이름(fedopt, scaffold)을 현재 알고리즘으로 바꿉니다
+func migrateLegacyAlgorithms(db *gorm.DB) error {
+	for _, table := range []string{"federated_learnings", "aggregators"} {
+		for legacy, current := range models.LegacyAlgorithms {
+			result := db.Exec(fmt.Sprintf("UPDATE %s SET algorithm = ? WHERE LOWER(TRIM(algorithm)) = ?", table), current, legacy)
+			if result.Error != nil {
+				return fmt.Errorf("%s 알고리즘 이름 변환 실패: %v", table, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				log.Printf("%s의 %s 알고리즘 %d건을 %s로 변환했습니다", table, legacy, result.RowsAffected, current)
+			}
+		}
+	}
+	return nil
+}
+
 // cleanupInvalidForeignKeys는 외래키 제약조건 위반 데이터를 정리합니다
 func cleanupInvalidForeignKeys(db *gorm.DB) error {
 	log.Println("외래키 제약조건 위반 데이터 정리 시작...")
@@ -142,6 +258,17 @@ func LoadInitialData() error {
 		return err
 	}
 
+	// 가격 스냅샷이 없으면 초기 가격표를 첫 스냅샷으로 저장
+	catalog := pricing.NewCatalog(
+		repository.NewPriceSnapshotRepository(db),
+		repository.NewCloudPriceRepository(db),
+		repository.NewProviderRepository(db),
+		repository.NewRegionRepository(db),
+	)
+	if err := catalog.SeedFromCloudPrices(); err != nil {
+		log.Printf("초기 가격 스냅샷 생성 실패: %v", err)
+	}
+
 	log.Println("초기 데이터 로드 완료")
 	return nil
 }
@@ -173,13 +300,28 @@ func InitializeRepositories() *Repositories {
 	db := config.GetDB()
 
 	repos := &Repositories{
-		UserRepo:         repository.NewUserRepository(db),
-		RefreshTokenRepo: repository.NewRefreshTokenRepository(db),
-		CloudRepo:        repository.NewCloudRepository(db),
-		FLRepo:           repository.NewFederatedLearningRepository(db),
-		ParticipantRepo:  repository.NewParticipantRepository(db),
-		AggregatorRepo:   repository.NewAggregatorRepository(db),
-		SSHKeypairRepo:   repository.NewSSHKeypairRepository(db),
+		UserRepo:          repository.NewUserRepository(db),
+		RefreshTokenRepo:  repository.NewRefreshTokenRepository(db),
+		UserTokenRepo:     repository.NewUserTokenRepository(db),
+		OrgRepo:           repository.NewOrganizationRepository(db),
+		APITokenRepo:      repository.NewAPITokenRepository(db),
+		AuditLogRepo:      repository.NewAuditLogRepository(db),
+		CloudRepo:         repository.NewCloudRepository(db),
+		FLRepo:            repository.NewFederatedLearningRepository(db),
+		ParticipantRepo:   repository.NewParticipantRepository(db),
+		AggregatorRepo:    repository.NewAggregatorRepository(db),
+		ProviderRepo:      repository.NewProviderRepository(db),
+		RegionRepo:        repository.NewRegionRepository(db),
+		CloudPriceRepo:    repository.NewCloudPriceRepository(db),
+		CloudLatencyRepo:  repository.NewCloudLatencyRepository(db),
+		SSHKeypairRepo:    repository.NewSSHKeypairRepository(db),
+		DeploymentRepo:    repository.NewAggregatorDeploymentRepository(db),
+		FLStepRepo:        repository.NewFederatedLearningStepRepository(db),
+		LatencySampleRepo: repository.NewLatencySampleRepository(db),
+		PriceSnapshotRepo: repository.NewPriceSnapshotRepository(db),
+		TaskBundleRepo:    repository.NewTaskBundleRepository(db),
+		ModelVersionRepo:  repository.NewModelVersionRepository(db),
+		AgentRepo:         repository.NewParticipantAgentRepository(db),
 	}
 
 	log.Println("리포지토리 초기화 완료")
@@ -198,15 +340,19 @@ func NewDependencies() *Dependencies {
 		mlflowURL = "http://localhost:5000"
 	}
 	// Aggregator Service 초기화 (새로운 구조)
-	aggregatorService := aggregatorservice.NewAggregatorService(repos.AggregatorRepo, repos.FLRepo, repos.SSHKeypairRepo, repos.CloudRepo, mlflowURL)
-	metricsService := aggregatorservice.NewAggregatorMetricsService(repos.AggregatorRepo)
+	aggregatorService := aggregatorservice.NewAggregatorService(repos.AggregatorRepo, repos.FLRepo, repos.SSHKeypairRepo, repos.CloudRepo, repos.DeploymentRepo, mlflowURL)
+	costMeter := aggregatorservice.NewCostMeter(repos.AggregatorRepo, NewPriceCatalog(repos))
+	costMeter.Start(context.Background(), getCostMeterInterval())
+	metricsService := aggregatorservice.NewAggregatorMetricsService(repos.AggregatorRepo, costMeter)
 	trainingService := aggregatorservice.NewAggregatorTrainingService(repos.AggregatorRepo)
 
-	// OptimizationService 어댑터 사용
-	originalOptimizationService := services.NewOptimizationService()
-	optimizationService := aggregatorservice.NewOptimizationServiceAdapter(originalOptimizationService)
+	// OptimizationService 초기화 (기본: Go NSGA-II, OPTIMIZER_BACKEND=python이면 Python 스크립트)
+	optimizationService := newOptimizationService(repos)
 
 	// Aggregator Handler 초기화 (새로운 구조)
+	// 비동기 배포 워커 풀 시작
+	aggregatorService.StartDeploymentWorkers(getDeploymentWorkerCount())
+
 	aggregatorHandler := aggregatorhandler.NewAggregatorHandler(
 		aggregatorService,
 		metricsService,
@@ -222,10 +368,49 @@ func NewDependencies() *Dependencies {
 		MetricsService:      metricsService,
 		TrainingService:     trainingService,
 		OptimizationService: optimizationService,
+		CostMeter:           costMeter,
 		AggregatorHandler:   aggregatorHandler,
 	}
 }
 
+// newOptimizationService는 OPTIMIZER_BACKEND 환경변수에 따라 최적화 백엔드를 선택합니다
+func newOptimizationService(repos *Repositories) aggregatorservice.OptimizationService {
+	if strings.EqualFold(os.Getenv("OPTIMIZER_BACKEND"), "python") {
+		log.Println("집계자 배치 최적화 백엔드: Python 스크립트")
+		return aggregatorservice.NewOptimizationServiceAdapter(services.NewOptimizationService())
+	}
+
+	log.Println("집계자 배치 최적화 백엔드: Go NSGA-II")
+	opt := optimizer.NewOptimizer(NewPriceCatalog(repos), repos.CloudLatencyRepo, optimizer.DefaultConfig())
+	return aggregatorservice.NewNativeOptimizationService(opt)
+}
+
+// NewPriceCatalog는 버전이 지정된 가격표 카탈로그를 생성합니다
+func NewPriceCatalog(repos *Repositories) *pricing.Catalog {
+	return pricing.NewCatalog(repos.PriceSnapshotRepo, repos.CloudPriceRepo, repos.ProviderRepo, repos.RegionRepo)
+}
+
+// getDeploymentWorkerCount는 AGGREGATOR_DEPLOY_WORKERS 환경변수로 배포 워커 수를 결정합니다
+func getDeploymentWorkerCount() int {
+	if value := os.Getenv("AGGREGATOR_DEPLOY_WORKERS"); value != "" {
+		if count, err := strconv.Atoi(value); err == nil && count > 0 {
+			return count
+		}
+	}
+	return 2
+}
+
+// getCostMeterInterval은 AGGREGATOR_COST_INTERVAL 환경변수로 누적 비용 갱신 주기를 결정합니다
+func getCostMeterInterval() time.Duration {
+	if value := os.Getenv("AGGREGATOR_COST_INTERVAL"); value != "" {
+		if interval, err := time.ParseDuration(value); err == nil && interval > 0 {
+			return interval
+		}
+		log.Printf("AGGREGATOR_COST_INTERVAL 값이 올바르지 않아 기본값을 사용합니다: %q", value)
+	}
+	return time.Minute
+}
+
 // ShutdownTracer는 트레이서를 안전하게 종료합니다
 func ShutdownTracer(tp *sdktrace.TracerProvider) {
 	if err := tp.Shutdown(context.Background()); err != nil {
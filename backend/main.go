@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/Mungge/Fleecy-Cloud/config"
 	"github.com/Mungge/Fleecy-Cloud/handlers"
 	"github.com/Mungge/Fleecy-Cloud/handlers/aggregator"
 	authHandlers "github.com/Mungge/Fleecy-Cloud/handlers/auth"
@@ -12,6 +15,12 @@ import (
 	"github.com/Mungge/Fleecy-Cloud/middlewares"
 	"github.com/Mungge/Fleecy-Cloud/routes"
 	"github.com/Mungge/Fleecy-Cloud/services"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/services/latency"
+	"github.com/Mungge/Fleecy-Cloud/services/mail"
+	"github.com/Mungge/Fleecy-Cloud/services/modelregistry"
+	"github.com/Mungge/Fleecy-Cloud/services/oidc"
+	"github.com/Mungge/Fleecy-Cloud/services/ratelimit"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,28 +35,122 @@ func main() {
 	// 리포지토리 초기화
 	repos := initialization.InitializeRepositories()
 
+	// 감사 로그 기록기 초기화 (서비스 훅보다 먼저)
+	auditRecorder := audit.NewRecorder(repos.AuditLogRepo)
+	audit.SetDefault(auditRecorder)
+
 	// Aggregator 의존성 초기화
 	aggregatorDeps := initialization.NewDependencies()
 
 	// SSH 키페어 핸들러 초기화
 	sshKeypairService := services.NewSSHKeypairService(repos.SSHKeypairRepo)
-	sshKeypairHandler := handlers.NewSSHKeypairHandler(sshKeypairService)
+	sshKeypairHandler := handlers.NewSSHKeypairHandler(sshKeypairService, repos.AggregatorRepo)
+
+	// 모델 레지스트리 초기화 (MODEL_STORE=filesystem|s3)
+	modelStore, err := modelregistry.NewStoreFromEnv()
+	if err != nil {
+		log.Fatalf("모델 저장소 초기화 실패: %v", err)
+	}
+	modelRegistry := modelregistry.NewRegistry(repos.ModelVersionRepo, repos.FLRepo, repos.AggregatorRepo, sshKeypairService, modelStore)
+	aggregatorDeps.AggregatorService.SetCheckpointCollector(modelRegistry)
+	modelSyncInterval := 2 * time.Minute
+	if interval := os.Getenv("MODEL_REGISTRY_SYNC_INTERVAL"); interval == "off" {
+		modelSyncInterval = 0
+	} else if interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil || d <= 0 {
+			log.Printf("MODEL_REGISTRY_SYNC_INTERVAL 값이 올바르지 않아 기본값(%v)을 사용합니다: %q", modelSyncInterval, interval)
+		} else {
+			modelSyncInterval = d
+		}
+	}
+	if modelSyncInterval > 0 {
+		modelRegistry.Start(context.Background(), modelSyncInterval)
+	}
+
+	// 메일 발송기 초기화 (SMTP_HOST 또는 MAIL_DRIVER=memory 필요)
+	mailer, err := mail.NewMailerFromEnv()
+	if err != nil {
+		log.Fatalf("메일 발송기 초기화 실패: %v", err)
+	}
+
+	// OIDC 로그인 제공자 초기화 (OIDC_PROVIDERS_CONFIG 미설정 시 사용 안 함)
+	oidcProviders, err := oidc.NewRegistryFromEnv()
+	if err != nil {
+		log.Fatalf("OIDC 제공자 설정 로드 실패: %v", err)
+	}
+
+	// 요청 제한 및 로그인 잠금 초기화 (RATE_LIMIT_STORE=postgres면 여러 인스턴스가 공유)
+	rateLimitConfig, err := ratelimit.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("요청 제한 설정 로드 실패: %v", err)
+	}
+	rateLimitStore, err := ratelimit.NewStore(rateLimitConfig.Store, config.GetDB())
+	if err != nil {
+		log.Fatalf("요청 제한 저장소 초기화 실패: %v", err)
+	}
+	limiter := ratelimit.NewLimiter(rateLimitStore, rateLimitConfig.Policies)
+	limiter.StartCleanup(context.Background(), 10*time.Minute)
+	loginGuard := ratelimit.NewLoginGuard(rateLimitStore, rateLimitConfig.Lockout)
 
 	// 핸들러 초기화
 	authHandler := authHandlers.NewAuthHandler(
 		repos.UserRepo,
 		repos.RefreshTokenRepo,
+		repos.UserTokenRepo,
+		repos.OrgRepo,
+		mailer,
 		os.Getenv("GITHUB_CLIENT_ID"),
 		os.Getenv("GITHUB_CLIENT_SECRET"),
+		oidcProviders,
+		loginGuard,
 	)
 	cloudHandler := handlers.NewCloudHandler(repos.CloudRepo)
-	flHandler := handlers.NewFederatedLearningHandler(repos.FLRepo, repos.ParticipantRepo, repos.AggregatorRepo, sshKeypairService)
-	participantHandler := handlers.NewParticipantHandler(repos.ParticipantRepo)
+	flHandler := handlers.NewFederatedLearningHandler(repos.FLRepo, repos.ParticipantRepo, repos.AggregatorRepo, repos.TaskBundleRepo, repos.CloudRepo, sshKeypairService, repos.FLStepRepo, modelRegistry, repos.AgentRepo)
+	participantHandler := handlers.NewParticipantHandler(repos.ParticipantRepo, repos.AgentRepo)
+	participantAgentHandler := handlers.NewParticipantAgentHandler(repos.AgentRepo)
+	organizationHandler := handlers.NewOrganizationHandler(repos.OrgRepo, repos.UserRepo)
+	apiTokenHandler := handlers.NewAPITokenHandler(repos.APITokenRepo)
+	auditHandler := handlers.NewAuditHandler(repos.AuditLogRepo)
+	taskBundleHandler := handlers.NewTaskBundleHandler(repos.TaskBundleRepo)
+	modelRegistryHandler := handlers.NewModelRegistryHandler(modelRegistry, repos.ModelVersionRepo, repos.FLRepo, repos.TaskBundleRepo)
 	aggregatorHandler := aggregatorDeps.AggregatorHandler
 
 	// SSH 키페어 핸들러 초기화
 	sshKeypairService = services.NewSSHKeypairService(repos.SSHKeypairRepo)
-	sshKeypairHandler = handlers.NewSSHKeypairHandler(sshKeypairService)
+	sshKeypairHandler = handlers.NewSSHKeypairHandler(sshKeypairService, repos.AggregatorRepo)
+
+	// 가격표 카탈로그 핸들러 초기화
+	priceCatalog := initialization.NewPriceCatalog(repos)
+	priceCatalogHandler := handlers.NewPriceCatalogHandler(priceCatalog)
+
+	// 지연시간 측정기 초기화 (LATENCY_PROBE_INTERVAL 설정 시 주기 측정)
+	latencyConfig, err := latency.DefaultConfig()
+	if err != nil {
+		log.Printf("지연시간 측정 설정 로드 실패, 기본값 사용: %v", err)
+	}
+	latencyProber := latency.NewProber(
+		latency.NewAgentProbe(latencyConfig.ProbeTimeout, repos.AgentRepo),
+		repos.LatencySampleRepo,
+		repos.CloudLatencyRepo,
+		repos.ProviderRepo,
+		repos.RegionRepo,
+		priceCatalog,
+		repos.ParticipantRepo,
+		latencyConfig,
+	)
+	latencyHandler := handlers.NewLatencyHandler(latencyProber, repos.LatencySampleRepo)
+	if interval := os.Getenv("LATENCY_PROBE_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil || d <= 0 {
+			log.Printf("LATENCY_PROBE_INTERVAL 값이 올바르지 않아 주기 측정을 비활성화합니다: %q", interval)
+		} else {
+			latencyProber.Start(context.Background(), d)
+		}
+	}
+
+	// 서버 재시작 전에 완료되지 못한 연합학습 실행 재개
+	flHandler.ResumeOrchestration()
 
 	// Prometheus 서비스 초기화
 	prometheusURL := os.Getenv("PROMETHEUS_URL")
@@ -64,12 +167,24 @@ func main() {
 	// Gin 라우터 설정
 	r := gin.Default()
 
+	// IP별 요청 제한이 위조된 X-Forwarded-For로 우회되지 않도록 지정한 프록시만 신뢰 (미설정 시 직접 연결 주소 사용)
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("TRUSTED_PROXIES 설정 실패: %v", err)
+	}
+
 	// CORS 설정
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Organization-ID, X-Request-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Retry-After")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -78,6 +193,9 @@ func main() {
 		c.Next()
 	})
 
+	// 요청 ID 부여 및 상태 변경 요청 감사 로그 기록
+	r.Use(middlewares.RequestIDMiddleware(), middlewares.AuditMiddleware(auditRecorder))
+
 	// 헬스체크 엔드포인트 (인증 불필요)
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -89,21 +207,33 @@ func main() {
 
 	// 라우트 설정 - 각 도메인별로 개별 설정
 	// 인증 라우트 (인증 미들웨어 없음)
-	routes.SetupAuthRoutes(r, authHandler)
+	routes.SetupAuthRoutes(r, authHandler, middlewares.RateLimitMiddleware(limiter, ratelimit.PolicyAuth))
+
+	// 참여자 에이전트 라우트 (사용자 인증 대신 참여자별 서명 토큰)
+	routes.SetupParticipantAgentRoutes(r, participantAgentHandler)
 
 	// 인증이 필요한 라우트 그룹
 	authorized := r.Group("/api")
-	authorized.Use(middlewares.AuthMiddleware())
+	authMiddleware := middlewares.AuthMiddleware(repos.APITokenRepo)
+	organizationMiddleware := middlewares.OrganizationMiddleware(repos.UserRepo, repos.OrgRepo)
+	authorized.Use(authMiddleware, organizationMiddleware, middlewares.RateLimitMiddleware(limiter, ratelimit.PolicyAPI))
 
 	// 각 도메인별 라우트 설정
+	routes.SetupOrganizationRoutes(authorized, organizationHandler)
+	routes.SetupAPITokenRoutes(authorized, apiTokenHandler)
+	routes.SetupAuditRoutes(authorized, auditHandler)
 	routes.SetupCloudRoutes(authorized, cloudHandler)
 	routes.SetupParticipantRoutes(authorized, participantHandler)
 	routes.SetupFederatedLearningRoutes(authorized, flHandler)
-	routes.SetupAggregatorRoutes(authorized, aggregatorHandler, mlflowHandler)
+	routes.SetupTaskBundleRoutes(authorized, taskBundleHandler)
+	routes.SetupModelRegistryRoutes(authorized, modelRegistryHandler)
+	routes.SetupAggregatorRoutes(authorized, aggregatorHandler, mlflowHandler, middlewares.RateLimitMiddleware(limiter, ratelimit.PolicyOptimization))
 	routes.SetupSSHKeypairRoutes(authorized, sshKeypairHandler)
+	routes.SetupLatencyRoutes(authorized, latencyHandler, middlewares.AdminMiddleware(repos.UserRepo))
+	routes.SetupPriceCatalogRoutes(authorized, priceCatalogHandler, middlewares.AdminMiddleware(repos.UserRepo))
 
 	// VM 라우트 설정 (전체 엔진에 설정, 인증은 내부에서 처리)
-	routes.SetupVirtualMachineRoutes(r, repos.ParticipantRepo)
+	routes.SetupVirtualMachineRoutes(r, repos.ParticipantRepo, authMiddleware, organizationMiddleware)
 
 	// 서버 시작 정보 로깅
 	port := os.Getenv("PORT")
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/repository"
+)
+
+// AdminMiddleware는 ADMIN_EMAILS 환경변수(쉼표 구분)에 등록된 사용자만 통과시키는 미들웨어입니다
+// AuthMiddleware 뒤에 등록해야 합니다
+func AdminMiddleware(userRepo *repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetInt64("userID")
+		user, err := userRepo.GetUserByID(userID)
+		if err != nil || user == nil || !isAdminEmail(user.Email) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "관리자 권한이 필요합니다"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func isAdminEmail(email string) bool {
+	for _, admin := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		admin = strings.TrimSpace(admin)
+		if admin != "" && strings.EqualFold(admin, email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+)
+
+// maxAuditBodyBytes 감사 로그 payload로 읽는 요청 본문 최대 크기
+const maxAuditBodyBytes = 64 * 1024
+
+// AuditMiddleware는 상태를 변경하는 요청(POST/PUT/PATCH/DELETE)과
+// 핸들러가 audit.Annotate로 표시한 조회 요청을 감사 로그로 기록합니다
+// 인증 정보를 읽기 위해 응답 이후에 기록하므로 라우터 전체에 등록해도 됩니다
+func AuditMiddleware(recorder *audit.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stateChanging := isStateChangingMethod(c.Request.Method)
+
+		var payload map[string]interface{}
+		if stateChanging {
+			payload = captureJSONBody(c)
+		}
+
+		c.Next()
+
+		annotation := audit.AnnotationFrom(c)
+		if !stateChanging && annotation == nil {
+			return
+		}
+		if c.FullPath() == "" {
+			// 라우트가 없는 요청 (404)
+			return
+		}
+
+		entry := newAuditEntry(c, annotation)
+		if payload == nil {
+			payload = multipartSummary(c)
+		}
+		if annotation != nil && len(annotation.Detail) > 0 {
+			if payload == nil {
+				payload = map[string]interface{}{}
+			}
+			for k, v := range annotation.Detail {
+				payload[k] = v
+			}
+		}
+		entry.Payload = payload
+
+		recorder.Record(entry)
+	}
+}
+
+func newAuditEntry(c *gin.Context, annotation *audit.Annotation) *models.AuditLog {
+	status := c.Writer.Status()
+	entry := &models.AuditLog{
+		RequestID:      c.GetString("requestID"),
+		ActorType:      models.AuditActorAnonymous,
+		ActorUserID:    c.GetInt64("userID"),
+		OrganizationID: c.GetInt64("orgID"),
+		Action:         c.Request.Method + " " + c.FullPath(),
+		Method:         c.Request.Method,
+		Path:           c.Request.URL.Path,
+		StatusCode:     status,
+		Outcome:        auditOutcome(status),
+		SourceIP:       c.ClientIP(),
+		UserAgent:      truncate(c.Request.UserAgent(), 512),
+	}
+	entry.ResourceType, entry.ResourceID = resourceFromRoute(c)
+
+	if annotation != nil {
+		if annotation.Action != "" {
+			entry.Action = annotation.Action
+		}
+		if annotation.ResourceType != "" {
+			entry.ResourceType = annotation.ResourceType
+		}
+		if annotation.ResourceID != "" {
+			entry.ResourceID = annotation.ResourceID
+		}
+		if entry.ActorUserID == 0 {
+			entry.ActorUserID = annotation.ActorUserID
+		}
+	}
+
+	if entry.ActorUserID != 0 {
+		entry.ActorType = models.AuditActorUser
+	}
+	if tokenID, exists := c.Get(apiTokenIDKey); exists {
+		if id, ok := tokenID.(int64); ok {
+			entry.ActorType = models.AuditActorAPIToken
+			entry.APITokenID = &id
+		}
+	}
+	return entry
+}
+
+// resourceFromRoute는 라우트 경로에서 리소스 종류와 ID를 추정합니다
+// 예: /api/aggregators/:id/status -> ("aggregators", id)
+func resourceFromRoute(c *gin.Context) (string, string) {
+	segments := strings.Split(strings.TrimPrefix(c.FullPath(), "/api/"), "/")
+	resourceType := segments[0]
+
+	for _, segment := range segments[1:] {
+		if strings.HasPrefix(segment, ":") {
+			return resourceType, c.Param(strings.TrimPrefix(segment, ":"))
+		}
+	}
+	return resourceType, ""
+}
+
+func auditOutcome(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return models.AuditOutcomeDenied
+	case status >= http.StatusBadRequest:
+		return models.AuditOutcomeFailure
+	default:
+		return models.AuditOutcomeSuccess
+	}
+}
+
+func isStateChangingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// captureJSONBody는 JSON 요청 본문을 읽어 payload로 반환하고 핸들러가 다시 읽을 수 있도록 복원합니다
+func captureJSONBody(c *gin.Context) map[string]interface{} {
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBodyBytes+1))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
+	if err != nil || len(head) == 0 {
+		return nil
+	}
+	if len(head) > maxAuditBodyBytes {
+		return map[string]interface{}{"_truncated": true, "_size_over": maxAuditBodyBytes}
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(head, &body); err != nil {
+		return map[string]interface{}{"_unparsed": true}
+	}
+	if m, ok := body.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{"body": body}
+}
+
+// multipartSummary는 업로드 요청의 필드 값 대신 필드 이름과 파일 정보만 기록합니다
+func multipartSummary(c *gin.Context) map[string]interface{} {
+	form := c.Request.MultipartForm
+	if form == nil {
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(form.Value))
+	for key, values := range form.Value {
+		if len(values) > 0 {
+			fields[key] = values[0]
+		}
+	}
+
+	files := make([]map[string]interface{}, 0, len(form.File))
+	for field, headers := range form.File {
+		for _, h := range headers {
+			files = append(files, map[string]interface{}{
+				"field":    field,
+				"filename": h.Filename,
+				"size":     h.Size,
+			})
+		}
+	}
+
+	return map[string]interface{}{"fields": fields, "files": files}
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.ToValidUTF8(s[:max], "")
+}
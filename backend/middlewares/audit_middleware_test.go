@@ -0,0 +1,140 @@
+package middlewares
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+)
+
+type auditEnv struct {
+	t      *testing.T
+	repo   *repository.AuditLogRepository
+	router *gin.Engine
+	// received는 마지막 요청에서 핸들러가 읽은 본문입니다
+	received []byte
+}
+
+func newAuditEnv(t *testing.T) *auditEnv {
+	gin.SetMode(gin.TestMode)
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.AuditLog{}); err != nil {
+		t.Fatal(err)
+	}
+	env := &auditEnv{t: t, repo: repository.NewAuditLogRepository(db)}
+
+	env.router = gin.New()
+	env.router.Use(func(c *gin.Context) { c.Set("userID", int64(7)) }, AuditMiddleware(audit.NewRecorder(env.repo)))
+	env.router.POST("/api/clouds/:id", func(c *gin.Context) {
+		env.received, _ = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusOK)
+	})
+	env.router.POST("/api/bundles", func(c *gin.Context) {
+		if _, err := c.MultipartForm(); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+	return env
+}
+
+func (e *auditEnv) post(path, contentType string, body []byte) {
+	e.t.Helper()
+	e.received = nil
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	if w.Code >= http.StatusBadRequest {
+		e.t.Fatalf("POST %s: status %d", path, w.Code)
+	}
+}
+
+// lastPayload는 가장 최근에 저장된 감사 로그의 payload를 반환합니다
+func (e *auditEnv) lastPayload() map[string]interface{} {
+	e.t.Helper()
+	logs, _, err := e.repo.List(models.AuditLogFilter{Limit: 1})
+	if err != nil || len(logs) != 1 {
+		e.t.Fatalf("audit logs = %v, %v", logs, err)
+	}
+	return logs[0].Payload
+}
+
+func TestAuditMiddlewareRedactsJSONBody(t *testing.T) {
+	env := newAuditEnv(t)
+	body := []byte(`{"name": "aws-prod", "credentials": {"access_key": "AKIA", "region": "us-east-1"},
+		"ssh": {"private_key": "k", "public": "-----BEGIN PUBLIC KEY-----\nMIIB\n-----END PUBLIC KEY-----"}}`)
+
+	env.post("/api/clouds/conn-1", "application/json; charset=utf-8", body)
+
+	// 핸들러는 마스킹 전 원래 본문을 그대로 읽음
+	if !bytes.Equal(env.received, body) {
+		t.Fatalf("handler body = %q", env.received)
+	}
+	payload := env.lastPayload()
+	ssh, _ := payload["ssh"].(map[string]interface{})
+	if payload["name"] != "aws-prod" || payload["credentials"] != "[REDACTED]" || ssh["private_key"] != "[REDACTED]" || ssh["public"] != "[REDACTED]" {
+		t.Fatalf("payload = %#v", payload)
+	}
+}
+
+func TestAuditMiddlewareTruncatesOversizedBody(t *testing.T) {
+	env := newAuditEnv(t)
+	body := []byte(`{"data": "` + strings.Repeat("x", maxAuditBodyBytes) + `"}`)
+
+	env.post("/api/clouds/conn-1", "application/json", body)
+
+	if !bytes.Equal(env.received, body) {
+		t.Fatalf("handler read %d of %d bytes", len(env.received), len(body))
+	}
+	payload := env.lastPayload()
+	if payload["_truncated"] != true || payload["data"] != nil {
+		t.Fatalf("payload = %v", payload)
+	}
+
+	// 본문이 JSON이 아니어도 핸들러에는 그대로 전달
+	env.post("/api/clouds/conn-1", "application/json", []byte("password=hunter2"))
+	if string(env.received) != "password=hunter2" {
+		t.Fatalf("handler body = %q", env.received)
+	}
+	if payload := env.lastPayload(); len(payload) != 1 || payload["_unparsed"] != true {
+		t.Fatalf("unparsed payload = %v", payload)
+	}
+}
+
+func TestAuditMiddlewareSummarizesMultipart(t *testing.T) {
+	env := newAuditEnv(t)
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("name", "resnet-bundle")
+	form.WriteField("api_token", "tok-123")
+	file, _ := form.CreateFormFile("bundle", "task.py")
+	file.Write([]byte("print('train')"))
+	form.Close()
+
+	env.post("/api/bundles", form.FormDataContentType(), body.Bytes())
+
+	payload := env.lastPayload()
+	fields, _ := payload["fields"].(map[string]interface{})
+	if fields["name"] != "resnet-bundle" || fields["api_token"] != "[REDACTED]" {
+		t.Fatalf("fields = %#v", payload["fields"])
+	}
+	// 파일 내용은 기록하지 않고 이름과 크기만
+	files, _ := payload["files"].([]interface{})
+	if len(files) != 1 {
+		t.Fatalf("files = %#v", payload["files"])
+	}
+	if f, _ := files[0].(map[string]interface{}); f["filename"] != "task.py" || f["size"] != float64(len("print('train')")) || f["field"] != "bundle" {
+		t.Fatalf("file = %#v", files[0])
+	}
+}
@@ -1,16 +1,26 @@
 package middlewares
 
 import (
+	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
 	"github.com/Mungge/Fleecy-Cloud/utils"
 )
 
-// AuthMiddleware는 JWT 토큰 인증을 확인하는 미들웨어입니다
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware는 JWT 토큰 또는 개인 액세스 토큰 인증을 확인하는 미들웨어입니다
+func AuthMiddleware(apiTokenRepo *repository.APITokenRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 개인 액세스 토큰 (flc_ 접두사)
+		if tokenString := bearerToken(c); models.IsAPIToken(tokenString) {
+			authenticateAPIToken(c, apiTokenRepo, tokenString)
+			return
+		}
+
 		// utils에서 사용자 ID 확인
 		userID, err := utils.GetUserIDFromContext(c)
 		if err != nil {
@@ -21,4 +31,36 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("userID", userID)
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
+
+// authenticateAPIToken은 개인 액세스 토큰을 확인하고 사용자와 스코프를 Context에 설정합니다
+func authenticateAPIToken(c *gin.Context, apiTokenRepo *repository.APITokenRepository, tokenString string) {
+	token, err := apiTokenRepo.GetTokenByHash(models.HashUserToken(tokenString))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "토큰 확인 중 오류가 발생했습니다"})
+		c.Abort()
+		return
+	}
+	if token == nil || !token.IsValid() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "유효하지 않거나 만료된 API 토큰입니다"})
+		c.Abort()
+		return
+	}
+
+	if err := apiTokenRepo.TouchLastUsed(token.ID, c.ClientIP()); err != nil {
+		log.Printf("API 토큰 마지막 사용 시각 갱신 실패 (ID: %d): %v", token.ID, err)
+	}
+
+	c.Set("userID", token.UserID)
+	c.Set(apiTokenIDKey, token.ID)
+	c.Set(apiTokenScopesKey, token.ScopeList())
+	c.Next()
+}
+
+func bearerToken(c *gin.Context) string {
+	authHeader := c.GetHeader("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+}
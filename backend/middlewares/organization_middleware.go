@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+)
+
+// OrganizationHeader는 요청할 조직을 지정하는 헤더입니다 (없으면 개인 조직)
+const OrganizationHeader = "X-Organization-ID"
+
+// OrganizationMiddleware는 사용자의 조직 멤버십을 조회해 요청 컨텍스트에 Principal을 설정하는 미들웨어입니다
+// AuthMiddleware 뒤에 등록해야 하며, 개인 조직이 없는 사용자는 이때 개인 조직이 생성됩니다
+func OrganizationMiddleware(userRepo *repository.UserRepository, orgRepo *repository.OrganizationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetInt64("userID")
+		user, err := userRepo.GetUserByID(userID)
+		if err != nil || user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "인증이 필요합니다"})
+			c.Abort()
+			return
+		}
+
+		personal, err := orgRepo.EnsurePersonalOrganization(user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "조직 정보를 불러오지 못했습니다"})
+			c.Abort()
+			return
+		}
+
+		memberships, err := orgRepo.ListMembershipsByUserID(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "조직 정보를 불러오지 못했습니다"})
+			c.Abort()
+			return
+		}
+
+		activeOrgID := personal.ID
+		if header := c.GetHeader(OrganizationHeader); header != "" {
+			id, err := strconv.ParseInt(header, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 조직 ID입니다"})
+				c.Abort()
+				return
+			}
+			activeOrgID = id
+		}
+
+		principal := authz.NewPrincipal(userID, activeOrgID, memberships)
+		if !principal.IsMember(activeOrgID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "해당 조직의 멤버가 아닙니다"})
+			c.Abort()
+			return
+		}
+
+		authz.SetPrincipal(c, principal)
+		c.Set("orgID", activeOrgID)
+		c.Next()
+	}
+}
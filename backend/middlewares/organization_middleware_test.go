@@ -0,0 +1,126 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+)
+
+// newTestDB는 테스트마다 분리된 메모리 SQLite DB를 생성합니다
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", regexp.MustCompile(`\W`).ReplaceAllString(t.Name(), "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.User{}, &models.Organization{}, &models.OrganizationMember{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestOrganizationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := newTestDB(t)
+	users := repository.NewUserRepository(db)
+	orgs := repository.NewOrganizationRepository(db)
+
+	member := &models.User{Email: "alice@example.com", Name: "alice", PasswordHash: "x"}
+	other := &models.User{Email: "bob@example.com", Name: "bob", PasswordHash: "x"}
+	for _, user := range []*models.User{member, other} {
+		if err := users.CreateUser(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	team := &models.Organization{Name: "team"}
+	if err := orgs.CreateOrganization(team, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	shared := &models.Organization{Name: "shared"}
+	if err := orgs.CreateOrganization(shared, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := orgs.AddMember(&models.OrganizationMember{OrganizationID: shared.ID, UserID: member.ID, Role: models.OrganizationRoleViewer}); err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		userID, _ := strconv.ParseInt(c.GetHeader("X-Test-User"), 10, 64)
+		c.Set("userID", userID)
+	}, OrganizationMiddleware(users, orgs))
+	var active *authz.Principal
+	router.GET("/resources", func(c *gin.Context) {
+		active = authz.PrincipalFromContext(c)
+		c.Status(http.StatusOK)
+	})
+
+	request := func(userID int64, orgHeader string) int {
+		active = nil
+		req := httptest.NewRequest(http.MethodGet, "/resources", nil)
+		req.Header.Set("X-Test-User", strconv.FormatInt(userID, 10))
+		if orgHeader != "" {
+			req.Header.Set(OrganizationHeader, orgHeader)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 헤더가 없으면 개인 조직 (첫 요청 시 생성)
+	if code := request(member.ID, ""); code != http.StatusOK || active == nil {
+		t.Fatalf("personal org: status %d", code)
+	}
+	personal, err := orgs.GetPersonalOrganization(member.ID)
+	if err != nil || personal == nil || active.ActiveOrganizationID != personal.ID || active.ActiveRole() != models.OrganizationRoleOwner {
+		t.Fatalf("personal org = %+v, %v; principal %+v", personal, err, active)
+	}
+
+	if code := request(member.ID, strconv.FormatInt(shared.ID, 10)); code != http.StatusOK || active.ActiveRole() != models.OrganizationRoleViewer {
+		t.Fatalf("shared org: status %d, principal %+v", code, active)
+	}
+
+	otherPersonal, err := orgs.EnsurePersonalOrganization(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 멤버가 아닌 조직을 지정하면 핸들러까지 가지 않고 403
+	cases := []struct {
+		name   string
+		userID int64
+		header string
+		want   int
+	}{
+		{"non-member org", member.ID, strconv.FormatInt(team.ID, 10), http.StatusForbidden},
+		{"missing org", member.ID, "999", http.StatusForbidden},
+		{"other user's personal org", member.ID, strconv.FormatInt(otherPersonal.ID, 10), http.StatusForbidden},
+		{"malformed header", member.ID, "team", http.StatusBadRequest},
+		{"unknown user", 999, "", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		if code := request(tc.userID, tc.header); code != tc.want || active != nil {
+			t.Errorf("%s: status = %d, want %d (handler reached: %v)", tc.name, code, tc.want, active != nil)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/services/ratelimit"
+)
+
+// RateLimitMiddleware는 정책 이름에 해당하는 IP별/사용자별 토큰 버킷 제한을 적용합니다
+// 사용자별 제한은 AuthMiddleware 뒤에서만 적용되며, 초과 시 429와 Retry-After 헤더를 반환합니다
+func RateLimitMiddleware(limiter *ratelimit.Limiter, policyName string) gin.HandlerFunc {
+	policy, ok := limiter.Policy(policyName)
+	if !ok {
+		log.Printf("알 수 없는 요청 제한 정책입니다, 제한 없이 진행합니다: %s", policyName)
+	}
+
+	return func(c *gin.Context) {
+		if policy.PerIP != nil {
+			key := fmt.Sprintf("ip:%s:%s", policyName, c.ClientIP())
+			if result := limiter.Allow(c.Request.Context(), key, *policy.PerIP); !result.Allowed {
+				abortRateLimited(c, result)
+				return
+			}
+		}
+
+		if userID := c.GetInt64("userID"); policy.PerUser != nil && userID != 0 {
+			key := fmt.Sprintf("user:%s:%d", policyName, userID)
+			if result := limiter.Allow(c.Request.Context(), key, *policy.PerUser); !result.Allowed {
+				abortRateLimited(c, result)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func abortRateLimited(c *gin.Context, result ratelimit.Result) {
+	seconds := ratelimit.RetryAfterSeconds(result.RetryAfter)
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error":       "요청이 너무 많습니다. 잠시 후 다시 시도해주세요",
+		"retry_after": seconds,
+	})
+}
@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader는 요청 ID를 주고받는 헤더입니다
+const RequestIDHeader = "X-Request-ID"
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware는 요청마다 ID를 부여하고 응답 헤더와 Context("requestID")에 설정합니다
+// 클라이언트가 보낸 X-Request-ID가 올바른 형식이면 그대로 사용합니다
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("requestID", requestID)
+		c.Writer.Header().Set(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
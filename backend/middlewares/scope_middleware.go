@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+const (
+	apiTokenIDKey     = "apiTokenID"
+	apiTokenScopesKey = "apiTokenScopes"
+)
+
+// RequireScope는 개인 액세스 토큰으로 인증된 요청에 리소스 스코프를 요구하는 미들웨어입니다
+// 조회(GET/HEAD)는 read, 삭제(DELETE)는 admin, 그 외 변경 요청은 write 이상이 필요합니다
+// 로그인 세션(JWT) 요청은 스코프 제한 없이 통과합니다
+func RequireScope(resource string) gin.HandlerFunc {
+	return requireScope(resource, func(c *gin.Context) string {
+		return requiredScopeLevel(c.Request.Method)
+	})
+}
+
+// RequireScopeLevel은 HTTP 메서드와 관계없이 지정한 수준의 리소스 스코프를 요구합니다
+// 비밀 값을 내려주는 조회처럼 메서드만으로 위험도를 판단할 수 없는 라우트에 사용합니다
+func RequireScopeLevel(resource, level string) gin.HandlerFunc {
+	return requireScope(resource, func(*gin.Context) string {
+		return level
+	})
+}
+
+func requireScope(resource string, levelFor func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get(apiTokenScopesKey)
+		if !exists {
+			c.Next()
+			return
+		}
+
+		scopes, _ := value.([]string)
+		level := levelFor(c)
+		if !models.APITokenScopesAllow(scopes, resource, level) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API 토큰에 필요한 스코프가 없습니다: " + resource + ":" + level})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// SessionOnly는 로그인 세션(JWT)으로만 사용할 수 있는 라우트에서 API 토큰을 거부합니다
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsAPITokenRequest(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API 토큰으로는 사용할 수 없는 기능입니다"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// IsAPITokenRequest는 요청이 개인 액세스 토큰으로 인증되었는지 확인합니다
+func IsAPITokenRequest(c *gin.Context) bool {
+	_, exists := c.Get(apiTokenIDKey)
+	return exists
+}
+
+func requiredScopeLevel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return models.APITokenScopeRead
+	case http.MethodDelete:
+		return models.APITokenScopeAdmin
+	default:
+		return models.APITokenScopeWrite
+	}
+}
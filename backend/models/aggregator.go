@@ -3,12 +3,13 @@ package models
 import "time"
 
 type Aggregator struct {
-	ID            string `json:"id" gorm:"primaryKey"`
-	UserID        int64  `json:"user_id" gorm:"not null;index"`
-	Name          string `json:"name" gorm:"not null"`
-	Status        string `json:"status" gorm:"default:pending"` // pending, running, completed, error
-	Algorithm     string `json:"algorithm" gorm:"not null"`     // FedAvg, FedProx, FedAdam, etc.
-	CloudProvider string `json:"cloud_provider" gorm:"not null"`
+	ID             string `json:"id" gorm:"primaryKey"`
+	UserID         int64  `json:"user_id" gorm:"not null;index"`
+	OrganizationID int64  `json:"organization_id" gorm:"not null;default:0;index"` // 소유 조직 (UserID는 생성자)
+	Name           string `json:"name" gorm:"not null"`
+	Status         string `json:"status" gorm:"default:pending"` // pending, running, completed, error
+	Algorithm      string `json:"algorithm" gorm:"not null"`     // FedAvg, FedProx, FedAdam, etc.
+	CloudProvider  string `json:"cloud_provider" gorm:"not null"`
 
 	// 클라우드 공통 필드
 	ProjectName  string `json:"project_name" gorm:"not null"`
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+)
+
+// 집계자 배포 상태 머신 단계
+// creating → keypair → workspace → applying → running / failed
+const (
+	DeploymentStateCreating  = "creating"
+	DeploymentStateKeypair   = "keypair"
+	DeploymentStateWorkspace = "workspace"
+	DeploymentStateApplying  = "applying"
+	DeploymentStateRunning   = "running"
+	DeploymentStateFailed    = "failed"
+)
+
+// DeploymentTotalStages는 배포 상태 머신의 전체 단계 수입니다
+const DeploymentTotalStages = 5
+
+// deploymentStateStages는 각 상태에 해당하는 진행 단계 번호입니다
+var deploymentStateStages = map[string]int{
+	DeploymentStateCreating:  1,
+	DeploymentStateKeypair:   2,
+	DeploymentStateWorkspace: 3,
+	DeploymentStateApplying:  4,
+	DeploymentStateRunning:   5,
+}
+
+// deploymentTransitions는 허용되는 상태 전이 목록입니다 (모든 진행 상태에서 failed로 전이 가능)
+var deploymentTransitions = map[string][]string{
+	DeploymentStateCreating:  {DeploymentStateKeypair, DeploymentStateFailed},
+	DeploymentStateKeypair:   {DeploymentStateWorkspace, DeploymentStateFailed},
+	DeploymentStateWorkspace: {DeploymentStateApplying, DeploymentStateFailed},
+	DeploymentStateApplying:  {DeploymentStateRunning, DeploymentStateFailed},
+}
+
+// AggregatorDeployment 집계자 비동기 배포 작업 모델
+type AggregatorDeployment struct {
+	ID           string     `json:"id" gorm:"primaryKey;size:255"`
+	AggregatorID string     `json:"aggregator_id" gorm:"not null;index;size:255"`
+	UserID       int64      `json:"user_id" gorm:"not null;index"`
+	State        string     `json:"state" gorm:"not null;size:20;index"`
+	Stage        int        `json:"stage" gorm:"not null;default:0"`
+	TotalStages  int        `json:"total_stages" gorm:"not null;default:5"`
+	Message      string     `json:"message" gorm:"type:text"`
+	Error        string     `json:"error,omitempty" gorm:"type:text"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName 테이블 이름 설정
+func (AggregatorDeployment) TableName() string {
+	return "aggregator_deployments"
+}
+
+// IsTerminal은 배포 작업이 종료 상태(running/failed)인지 확인합니다
+func (d *AggregatorDeployment) IsTerminal() bool {
+	return d.State == DeploymentStateRunning || d.State == DeploymentStateFailed
+}
+
+// IsBeforeApply는 Terraform apply 전 단계인지 확인합니다
+// 이 단계까지는 클라우드 인스턴스를 만들지 않았으므로 처음부터 다시 실행해도 됩니다
+func (d *AggregatorDeployment) IsBeforeApply() bool {
+	switch d.State {
+	case DeploymentStateCreating, DeploymentStateKeypair, DeploymentStateWorkspace:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo는 현재 상태에서 다음 상태로 전이 가능한지 확인합니다
+func (d *AggregatorDeployment) CanTransitionTo(next string) bool {
+	for _, allowed := range deploymentTransitions[d.State] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// DeploymentStageOf는 상태에 해당하는 진행 단계 번호를 반환합니다 (failed는 0)
+func DeploymentStageOf(state string) int {
+	return deploymentStateStages[state]
+}
@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestAggregatorDeploymentTransitions(t *testing.T) {
+	states := []string{DeploymentStateCreating, DeploymentStateKeypair, DeploymentStateWorkspace, DeploymentStateApplying, DeploymentStateRunning, DeploymentStateFailed}
+	allowed := map[string]map[string]bool{
+		DeploymentStateCreating:  {DeploymentStateKeypair: true, DeploymentStateFailed: true},
+		DeploymentStateKeypair:   {DeploymentStateWorkspace: true, DeploymentStateFailed: true},
+		DeploymentStateWorkspace: {DeploymentStateApplying: true, DeploymentStateFailed: true},
+		DeploymentStateApplying:  {DeploymentStateRunning: true, DeploymentStateFailed: true},
+	}
+	for _, from := range states {
+		deployment := &AggregatorDeployment{State: from}
+		for _, to := range states {
+			if got := deployment.CanTransitionTo(to); got != allowed[from][to] {
+				t.Errorf("%s -> %s: allowed = %v", from, to, got)
+			}
+		}
+	}
+}
+
+func TestAggregatorDeploymentStages(t *testing.T) {
+	cases := []struct {
+		state       string
+		stage       int
+		terminal    bool
+		beforeApply bool
+	}{
+		{DeploymentStateCreating, 1, false, true},
+		{DeploymentStateKeypair, 2, false, true},
+		{DeploymentStateWorkspace, 3, false, true},
+		{DeploymentStateApplying, 4, false, false},
+		{DeploymentStateRunning, DeploymentTotalStages, true, false},
+		{DeploymentStateFailed, 0, true, false},
+	}
+	for _, tc := range cases {
+		deployment := &AggregatorDeployment{State: tc.state}
+		if DeploymentStageOf(tc.state) != tc.stage || deployment.IsTerminal() != tc.terminal || deployment.IsBeforeApply() != tc.beforeApply {
+			t.Errorf("%s: stage %d, terminal %v, before apply %v", tc.state, DeploymentStageOf(tc.state), deployment.IsTerminal(), deployment.IsBeforeApply())
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// AggregatorStatusRunning은 과금 대상인 집계자 실행 상태입니다
+const AggregatorStatusRunning = "running"
+
+// 집계자 삭제 상태 (destroy 작업 대기/진행 중, 인프라 삭제 완료)
+const (
+	AggregatorStatusDeleting   = "deleting"
+	AggregatorStatusTerminated = "terminated"
+)
+
+// AggregatorRunPeriod는 집계자가 running 상태로 머문 구간입니다
+// 정지/재시작마다 구간이 새로 열리며, 누적 비용은 구간 길이의 합으로 계산합니다
+type AggregatorRunPeriod struct {
+	ID           uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	AggregatorID string     `json:"aggregator_id" gorm:"not null;index;size:255"`
+	StartedAt    time.Time  `json:"started_at" gorm:"not null"`
+	EndedAt      *time.Time `json:"ended_at,omitempty" gorm:"index"` // nil이면 실행 중
+
+	Aggregator *Aggregator `json:"-" gorm:"foreignKey:AggregatorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName 테이블 이름 설정
+func (AggregatorRunPeriod) TableName() string {
+	return "aggregator_run_periods"
+}
+
+// Duration은 구간 길이를 반환합니다 (실행 중이면 now까지)
+func (p *AggregatorRunPeriod) Duration(now time.Time) time.Duration {
+	end := now
+	if p.EndedAt != nil {
+		end = *p.EndedAt
+	}
+	if end.Before(p.StartedAt) {
+		return 0
+	}
+	return end.Sub(p.StartedAt)
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// APITokenPrefix는 개인 액세스 토큰 원문의 접두사입니다 (JWT와 구분하는 데 사용)
+const APITokenPrefix = "flc_"
+
+// API 토큰 스코프 수준 (admin ⊃ write ⊃ read)
+const (
+	APITokenScopeRead  = "read"
+	APITokenScopeWrite = "write"
+	APITokenScopeAdmin = "admin"
+)
+
+// APITokenResources는 스코프를 부여할 수 있는 리소스(라우트 그룹) 목록입니다
+var APITokenResources = []string{
+	"clouds",
+	"participants",
+	"fl",
+	"aggregators",
+	"keypairs",
+	"latency",
+	"organizations",
+	"prices",
+	"audit",
+}
+
+var apiTokenScopeLevels = map[string]int{
+	APITokenScopeRead:  1,
+	APITokenScopeWrite: 2,
+	APITokenScopeAdmin: 3,
+}
+
+// APIToken은 자동화/CI용 개인 액세스 토큰입니다
+// 원문 토큰은 생성 시 한 번만 보여주고 DB에는 SHA-256 해시만 저장합니다
+type APIToken struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64      `json:"user_id" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"not null"`
+	TokenHash  string     `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	Prefix     string     `json:"prefix" gorm:"size:16;not null"` // 토큰 식별용 앞부분 (예: flc_1a2b3c4d)
+	Scopes     string     `json:"scopes" gorm:"not null"`         // 공백 구분 스코프 목록 (예: "fl:write aggregators:read")
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" gorm:"index"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty" gorm:"size:64"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	// 관계 설정
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// GenerateAPIToken은 새 개인 액세스 토큰 원문과 저장용 해시를 생성합니다
+func GenerateAPIToken() (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+	token := APITokenPrefix + hex.EncodeToString(bytes)
+	return token, HashUserToken(token), nil
+}
+
+// IsAPIToken은 토큰 원문이 개인 액세스 토큰 형식인지 확인합니다
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
+
+// NormalizeAPITokenScopes는 스코프 목록을 검증하고 중복 제거 후 정렬합니다
+func NormalizeAPITokenScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		resource, level, ok := strings.Cut(scope, ":")
+		if !ok || !isAPITokenResource(resource) || apiTokenScopeLevels[level] == 0 {
+			return nil, fmt.Errorf("지원하지 않는 스코프입니다: %q", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("스코프를 하나 이상 지정해야 합니다")
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func isAPITokenResource(resource string) bool {
+	for _, r := range APITokenResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeList는 토큰의 스코프 목록을 반환합니다
+func (t *APIToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+// HasScope는 토큰이 리소스에 대해 요구 수준 이상의 스코프를 가지는지 확인합니다
+func (t *APIToken) HasScope(resource, level string) bool {
+	return APITokenScopesAllow(t.ScopeList(), resource, level)
+}
+
+// APITokenScopesAllow는 스코프 목록이 리소스에 대해 요구 수준 이상을 허용하는지 확인합니다
+func APITokenScopesAllow(scopes []string, resource, level string) bool {
+	required := apiTokenScopeLevels[level]
+	if required == 0 {
+		return false
+	}
+	for _, scope := range scopes {
+		r, l, ok := strings.Cut(scope, ":")
+		if ok && r == resource && apiTokenScopeLevels[l] >= required {
+			return true
+		}
+	}
+	return false
+}
+
+// 토큰이 만료되었는지 확인
+func (t *APIToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+// 토큰이 사용 가능한지 확인
+func (t *APIToken) IsValid() bool {
+	return t.RevokedAt == nil && !t.IsExpired()
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// 감사 로그 행위자 종류
+const (
+	AuditActorUser      = "user"
+	AuditActorAPIToken  = "api_token"
+	AuditActorSystem    = "system"
+	AuditActorAnonymous = "anonymous"
+)
+
+// 감사 로그 결과
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+	AuditOutcomeDenied  = "denied"
+)
+
+// AuditLog는 상태를 변경하는 작업의 감사 기록입니다
+// 추가만 가능하며 DB 트리거로 수정/삭제를 막습니다 (RunDatabaseMigration 참고)
+type AuditLog struct {
+	ID             int64                  `json:"id" gorm:"primaryKey;autoIncrement"`
+	CreatedAt      time.Time              `json:"created_at" gorm:"autoCreateTime;index"`
+	RequestID      string                 `json:"request_id,omitempty" gorm:"size:64;index"`
+	ActorType      string                 `json:"actor_type" gorm:"size:16;not null"`
+	ActorUserID    int64                  `json:"actor_user_id" gorm:"not null;default:0;index"` // 0: 익명/시스템
+	APITokenID     *int64                 `json:"api_token_id,omitempty"`
+	OrganizationID int64                  `json:"organization_id" gorm:"not null;default:0;index"`
+	Action         string                 `json:"action" gorm:"size:128;not null;index"` // 예: aggregator.create, POST /api/clouds
+	ResourceType   string                 `json:"resource_type,omitempty" gorm:"size:64;index:idx_audit_logs_resource"`
+	ResourceID     string                 `json:"resource_id,omitempty" gorm:"size:128;index:idx_audit_logs_resource"`
+	Method         string                 `json:"method,omitempty" gorm:"size:8"`
+	Path           string                 `json:"path,omitempty" gorm:"size:512"`
+	StatusCode     int                    `json:"status_code,omitempty"`
+	Outcome        string                 `json:"outcome" gorm:"size:16;not null;index"`
+	SourceIP       string                 `json:"source_ip,omitempty" gorm:"size:64"`
+	UserAgent      string                 `json:"user_agent,omitempty" gorm:"size:512"`
+	Payload        map[string]interface{} `json:"payload,omitempty" gorm:"serializer:json;type:text"` // 민감 값은 마스킹된 요청 본문/상세 정보
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditLogFilter는 감사 로그 조회 조건입니다 (빈 값은 조건에서 제외)
+type AuditLogFilter struct {
+	OrganizationID *int64
+	ActorUserID    *int64
+	Action         string // 접두사 일치 (예: "aggregator." )
+	ResourceType   string
+	ResourceID     string
+	Outcome        string
+	RequestID      string
+	From           *time.Time
+	To             *time.Time
+	Limit          int
+	Offset         int
+}
@@ -1,25 +1,72 @@
 package models
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/utils/secrets"
+	"gorm.io/gorm"
 )
 
 type CloudConnection struct {
-	ID             string    `json:"id" gorm:"primaryKey"`
-	UserID         int64     `json:"user_id" gorm:"not null;index"`
-	Provider       string    `json:"provider" gorm:"not null"`
-	Name           string    `json:"name" gorm:"not null"`
-	Region         string    `json:"region"`
-	Zone           string    `json:"zone"`
-	Status         string    `json:"status" gorm:"default:inactive"`
-	CredentialFile []byte    `json:"-" gorm:"type:bytea"`
-	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
-	
+	ID              string    `json:"id" gorm:"primaryKey"`
+	UserID          int64     `json:"user_id" gorm:"not null;index"`
+	OrganizationID  int64     `json:"organization_id" gorm:"not null;default:0;index"` // 소유 조직 (UserID는 생성자)
+	Provider        string    `json:"provider" gorm:"not null"`
+	Name            string    `json:"name" gorm:"not null"`
+	Region          string    `json:"region"`
+	Zone            string    `json:"zone"`
+	Status          string    `json:"status" gorm:"default:inactive"`
+	CredentialFile  []byte    `json:"-" gorm:"type:bytea"`              // 봉투 암호화되어 저장, 조회 시 평문으로 복호화
+	CredentialKeyID string    `json:"-" gorm:"type:varchar(100);index"` // 데이터 키를 감싼 마스터 키 ID (비어있으면 암호화 이전 평문)
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
 	// 관계 설정
-	User           *User      `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	plainCredential []byte `gorm:"-"`
 }
 
 func (CloudConnection) TableName() string {
 	return "cloud_connections"
-} 
\ No newline at end of file
+}
+
+// BeforeSave는 저장 전에 자격 증명 파일을 봉투 암호화합니다
+func (c *CloudConnection) BeforeSave(tx *gorm.DB) error {
+	if len(c.CredentialFile) == 0 || secrets.IsEnvelope(string(c.CredentialFile)) {
+		return nil
+	}
+
+	sealed, keyID, err := secrets.Seal(c.CredentialFile)
+	if err != nil {
+		return fmt.Errorf("자격 증명 암호화 실패: %v", err)
+	}
+	c.plainCredential = c.CredentialFile
+	c.CredentialFile = []byte(sealed)
+	c.CredentialKeyID = keyID
+	return nil
+}
+
+// AfterSave는 저장 후 메모리상의 자격 증명을 평문으로 되돌립니다
+func (c *CloudConnection) AfterSave(tx *gorm.DB) error {
+	if c.plainCredential != nil {
+		c.CredentialFile = c.plainCredential
+		c.plainCredential = nil
+	}
+	return nil
+}
+
+// AfterFind는 조회 후 자격 증명 파일을 복호화합니다
+func (c *CloudConnection) AfterFind(tx *gorm.DB) error {
+	if c.CredentialKeyID == "" || !secrets.IsEnvelope(string(c.CredentialFile)) {
+		return nil
+	}
+
+	plaintext, err := secrets.Open(string(c.CredentialFile), c.CredentialKeyID)
+	if err != nil {
+		return fmt.Errorf("자격 증명 복호화 실패: %v", err)
+	}
+	c.CredentialFile = plaintext
+	return nil
+}
@@ -3,21 +3,25 @@ package models
 import "time"
 
 type FederatedLearning struct {
-	ID                string     `json:"id" gorm:"primaryKey"`
-	UserID            int64      `json:"user_id" gorm:"not null;index"`
-	CloudConnectionID string     `json:"cloud_connection_id" gorm:"not null;index"` // CloudConnection 참조
-	AggregatorID      *string    `json:"aggregator_id,omitempty" gorm:"index"`      // 1:1 관계를 위한 aggregator ID
-	Name              string     `json:"name" gorm:"not null"`
-	Description       string     `json:"description"`
-	Status            string     `json:"status" gorm:"default:inactive"`
-	ParticipantCount  int        `json:"participant_count" gorm:"default:0"`
-	CompletedAt       *time.Time `json:"completed_at"`
-	Accuracy          string     `json:"accuracy"`
-	Rounds            int        `json:"rounds" gorm:"default:0"`
-	Algorithm         string     `json:"algorithm"`
-	ModelType         string     `json:"model_type"`
-	CreatedAt         time.Time  `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt         time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                string          `json:"id" gorm:"primaryKey"`
+	UserID            int64           `json:"user_id" gorm:"not null;index"`
+	OrganizationID    int64           `json:"organization_id" gorm:"not null;default:0;index"` // 소유 조직 (UserID는 생성자)
+	CloudConnectionID string          `json:"cloud_connection_id" gorm:"not null;index"`       // CloudConnection 참조
+	AggregatorID      *string         `json:"aggregator_id,omitempty" gorm:"index"`            // 1:1 관계를 위한 aggregator ID
+	Name              string          `json:"name" gorm:"not null"`
+	Description       string          `json:"description"`
+	Status            string          `json:"status" gorm:"default:inactive"`
+	ParticipantCount  int             `json:"participant_count" gorm:"default:0"`
+	CompletedAt       *time.Time      `json:"completed_at"`
+	Accuracy          string          `json:"accuracy"`
+	Rounds            int             `json:"rounds" gorm:"default:0"`
+	Algorithm         string          `json:"algorithm"`
+	StrategyConfig    *StrategyConfig `json:"strategy_config,omitempty" gorm:"serializer:json;type:text"` // 집계 전략 하이퍼파라미터
+	ModelType         string          `json:"model_type"`
+	TaskBundleID      *string         `json:"task_bundle_id,omitempty" gorm:"index"` // 사용자 번들 버전 (없으면 내장 템플릿 사용)
+	Attempt           int             `json:"attempt" gorm:"not null;default:0"`     // 체크포인트 재시작 횟수
+	CreatedAt         time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// 관계 설정
 	User            *User            `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
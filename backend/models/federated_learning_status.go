@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 연합학습 작업 상태
+const (
+	FLStatusReady     = "ready"
+	FLStatusStarting  = "starting"
+	FLStatusRunning   = "running"
+	FLStatusPaused    = "paused"
+	FLStatusStopped   = "stopped"
+	FLStatusFailed    = "failed"
+	FLStatusCompleted = "완료" // 프론트엔드 상태 표시와 맞춘 값
+)
+
+// ErrInvalidFLStatusTransition은 허용되지 않는 연합학습 상태 변경을 나타냅니다
+var ErrInvalidFLStatusTransition = errors.New("invalid federated learning status transition")
+
+// flStatusTransitions는 상태별로 바뀔 수 있는 다음 상태입니다
+// stopped/failed/완료에서 starting으로 가는 경로는 체크포인트 재시작입니다
+var flStatusTransitions = map[string][]string{
+	FLStatusReady:     {FLStatusStarting, FLStatusStopped},
+	FLStatusStarting:  {FLStatusRunning, FLStatusFailed, FLStatusStopped},
+	FLStatusRunning:   {FLStatusPaused, FLStatusStopped, FLStatusFailed, FLStatusCompleted},
+	FLStatusPaused:    {FLStatusRunning, FLStatusStopped},
+	FLStatusStopped:   {FLStatusStarting},
+	FLStatusFailed:    {FLStatusStarting, FLStatusStopped},
+	FLStatusCompleted: {FLStatusStarting},
+}
+
+// legacyFLStatuses는 이전 버전이 저장한 상태 값을 현재 상태로 대응시킵니다
+var legacyFLStatuses = map[string]string{
+	"inactive": FLStatusReady,
+	"대기중":      FLStatusReady,
+	"진행중":      FLStatusRunning,
+}
+
+// NormalizeFLStatus는 이전 상태 값을 현재 상태 값으로 바꿉니다
+func NormalizeFLStatus(status string) string {
+	if current, ok := legacyFLStatuses[status]; ok {
+		return current
+	}
+	return status
+}
+
+// IsValidFLStatus는 알려진 연합학습 상태인지 확인합니다
+func IsValidFLStatus(status string) bool {
+	_, ok := flStatusTransitions[NormalizeFLStatus(status)]
+	return ok
+}
+
+// ValidateFLStatusTransition은 from에서 to로 상태를 바꿀 수 있는지 확인합니다
+func ValidateFLStatusTransition(from, to string) error {
+	from, to = NormalizeFLStatus(from), NormalizeFLStatus(to)
+	if !IsValidFLStatus(to) {
+		return fmt.Errorf("%w: 알 수 없는 상태입니다: %s", ErrInvalidFLStatusTransition, to)
+	}
+	for _, next := range flStatusTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s 상태에서 %s 상태로 변경할 수 없습니다", ErrInvalidFLStatusTransition, from, to)
+}
+
+// FederatedLearningStatusTransition은 연합학습 상태 변경 이력입니다
+type FederatedLearningStatusTransition struct {
+	ID                  int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	FederatedLearningID string    `json:"federated_learning_id" gorm:"not null;index"`
+	FromStatus          string    `json:"from_status" gorm:"size:20"`
+	ToStatus            string    `json:"to_status" gorm:"not null;size:20"`
+	Reason              string    `json:"reason,omitempty" gorm:"type:text"`
+	UserID              *int64    `json:"user_id,omitempty"` // 시스템(오케스트레이터)이 바꾼 경우 비어 있음
+	CreatedAt           time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	FederatedLearning *FederatedLearning `json:"-" gorm:"foreignKey:FederatedLearningID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (FederatedLearningStatusTransition) TableName() string {
+	return "federated_learning_status_transitions"
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateFLStatusTransition(t *testing.T) {
+	allowed := []struct{ from, to string }{
+		{FLStatusReady, FLStatusStarting},
+		{FLStatusReady, FLStatusStopped},
+		{FLStatusStarting, FLStatusRunning},
+		{FLStatusStarting, FLStatusFailed},
+		{FLStatusRunning, FLStatusPaused},
+		{FLStatusRunning, FLStatusCompleted},
+		{FLStatusPaused, FLStatusRunning},
+		{FLStatusPaused, FLStatusStopped},
+		// 체크포인트 재시작
+		{FLStatusStopped, FLStatusStarting},
+		{FLStatusFailed, FLStatusStarting},
+		{FLStatusCompleted, FLStatusStarting},
+		// 이전 버전 상태 값
+		{"대기중", FLStatusStarting},
+		{"진행중", FLStatusPaused},
+		{"inactive", FLStatusStopped},
+	}
+	for _, tc := range allowed {
+		if err := ValidateFLStatusTransition(tc.from, tc.to); err != nil {
+			t.Errorf("%s -> %s: %v", tc.from, tc.to, err)
+		}
+	}
+
+	denied := []struct{ from, to string }{
+		{FLStatusReady, FLStatusRunning},
+		{FLStatusReady, FLStatusPaused},
+		{FLStatusStarting, FLStatusPaused},
+		{FLStatusPaused, FLStatusPaused},
+		{FLStatusPaused, FLStatusCompleted},
+		{FLStatusStopped, FLStatusRunning},
+		{FLStatusStopped, FLStatusStopped},
+		{FLStatusCompleted, FLStatusStopped},
+		{FLStatusCompleted, FLStatusRunning},
+		{FLStatusRunning, "archived"},
+		{"archived", FLStatusRunning},
+	}
+	for _, tc := range denied {
+		err := ValidateFLStatusTransition(tc.from, tc.to)
+		if !errors.Is(err, ErrInvalidFLStatusTransition) {
+			t.Errorf("%s -> %s: err = %v, want ErrInvalidFLStatusTransition", tc.from, tc.to, err)
+		}
+	}
+}
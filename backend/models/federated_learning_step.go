@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// 연합학습 오케스트레이션 단계 종류
+const (
+	FLStepUploadBundle        = "upload_bundle"
+	FLStepStartServer         = "start_server"
+	FLStepWaitReady           = "wait_ready"
+	FLStepDispatchParticipant = "dispatch_participant"
+)
+
+// 연합학습 오케스트레이션 단계 상태
+const (
+	FLStepStatusPending   = "pending"
+	FLStepStatusRunning   = "running"
+	FLStepStatusCompleted = "completed"
+	FLStepStatusFailed    = "failed"
+)
+
+// FederatedLearningStep 연합학습 실행 오케스트레이션의 개별 단계
+type FederatedLearningStep struct {
+	ID                  int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	FederatedLearningID string     `json:"federated_learning_id" gorm:"not null;index"`
+	Sequence            int        `json:"sequence" gorm:"not null"`
+	Name                string     `json:"name" gorm:"not null;size:50"`
+	ParticipantID       *string    `json:"participant_id,omitempty" gorm:"index"` // dispatch_participant 단계에서만 사용
+	Status              string     `json:"status" gorm:"not null;size:20;default:pending;index"`
+	Error               string     `json:"error,omitempty" gorm:"type:text"`
+	RetryCount          int        `json:"retry_count" gorm:"not null;default:0"`
+	MaxRetries          int        `json:"max_retries" gorm:"not null;default:0"`
+	StartedAt           *time.Time `json:"started_at,omitempty"`
+	CompletedAt         *time.Time `json:"completed_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	FederatedLearning *FederatedLearning `json:"-" gorm:"foreignKey:FederatedLearningID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (FederatedLearningStep) TableName() string {
+	return "federated_learning_steps"
+}
+
+// IsFinished는 단계가 완료 또는 실패 상태인지 확인합니다
+func (s *FederatedLearningStep) IsFinished() bool {
+	return s.Status == FLStepStatusCompleted || s.Status == FLStepStatusFailed
+}
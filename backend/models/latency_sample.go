@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// 지연시간 측정 유형
+const (
+	LatencyProbeRegion      = "region"      // 리전 에이전트 → 후보 집계자 리전
+	LatencyProbeParticipant = "participant" // 참여자 엔드포인트 → 후보 집계자 리전
+)
+
+// LatencySample은 리전 간 지연시간 측정 이력을 저장하는 구조체
+// CloudLatency의 avg/min/max는 최근 표본으로부터 다시 계산됩니다
+type LatencySample struct {
+	ID               int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	SourceProviderID int       `json:"source_provider_id" gorm:"not null;index:idx_latency_sample_pair"`
+	SourceRegionID   int       `json:"source_region_id" gorm:"not null;index:idx_latency_sample_pair"`
+	TargetProviderID int       `json:"target_provider_id" gorm:"not null;index:idx_latency_sample_pair"`
+	TargetRegionID   int       `json:"target_region_id" gorm:"not null;index:idx_latency_sample_pair"`
+	ProbeType        string    `json:"probe_type" gorm:"not null;size:20;index"`
+	ParticipantID    *string   `json:"participant_id,omitempty" gorm:"type:varchar(36);index"`
+	LatencyMs        *float64  `json:"latency_ms" gorm:"type:decimal(8,2)"` // 실패 시 NULL
+	Success          bool      `json:"success" gorm:"not null"`
+	Error            string    `json:"error,omitempty" gorm:"type:text"`
+	MeasuredAt       time.Time `json:"measured_at" gorm:"not null;index"`
+
+	// 관계 설정
+	SourceProvider Provider `json:"source_provider" gorm:"foreignKey:SourceProviderID"`
+	SourceRegion   Region   `json:"source_region" gorm:"foreignKey:SourceRegionID"`
+	TargetProvider Provider `json:"target_provider" gorm:"foreignKey:TargetProviderID"`
+	TargetRegion   Region   `json:"target_region" gorm:"foreignKey:TargetRegionID"`
+}
+
+// 테이블명 설정
+func (LatencySample) TableName() string {
+	return "latency_samples"
+}
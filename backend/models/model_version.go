@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// 모델 버전 단계 (staging/production 승격)
+const (
+	ModelStageNone       = "none"
+	ModelStageStaging    = "staging"
+	ModelStageProduction = "production"
+	ModelStageArchived   = "archived"
+)
+
+// MaxModelVersionTags는 모델 버전 하나에 붙일 수 있는 태그 수입니다
+const MaxModelVersionTags = 16
+
+var modelVersionTagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,31}$`)
+
+// ModelVersion은 집계자 체크포인트를 백엔드 저장소로 복사한 글로벌 모델 한 라운드입니다
+// 집계자 VM이 삭제된 뒤에도 다운로드할 수 있으며, 같은 작업의 이전 라운드 버전을 부모로 기록합니다
+type ModelVersion struct {
+	ID                  string      `json:"id" gorm:"primaryKey"`
+	OrganizationID      int64       `json:"organization_id" gorm:"not null;index"`
+	FederatedLearningID string      `json:"federated_learning_id" gorm:"not null;uniqueIndex:idx_model_version_round"`
+	Attempt             int         `json:"attempt" gorm:"not null;default:0;uniqueIndex:idx_model_version_round"` // 작업 재시작 횟수 (FederatedLearning.Attempt)
+	Round               int         `json:"round" gorm:"not null;uniqueIndex:idx_model_version_round"`
+	AggregatorID        string      `json:"aggregator_id"`
+	Algorithm           string      `json:"algorithm"`
+	ModelType           string      `json:"model_type"`
+	TaskBundleID        *string     `json:"task_bundle_id,omitempty"`    // 학습 코드 번들 버전
+	ParentVersionID     *string     `json:"parent_version_id,omitempty"` // 같은 작업의 직전 라운드 버전 (재시작 시 시작 체크포인트)
+	Metrics             ModelMetric `json:"metrics" gorm:"embedded;embeddedPrefix:metrics_"`
+	FileName            string      `json:"file_name"`
+	SourcePath          string      `json:"source_path"`                                // 집계자에서의 원본 경로
+	StorageKey          string      `json:"-" gorm:"not null"`                          // 모델 저장소 객체 키
+	ContentHash         string      `json:"content_hash" gorm:"size:64;not null;index"` // SHA-256
+	SizeBytes           int64       `json:"size_bytes"`
+	Stage               string      `json:"stage" gorm:"not null;default:'none';index"`
+	Tags                []string    `json:"tags" gorm:"serializer:json;type:text"`
+	PromotedAt          *time.Time  `json:"promoted_at,omitempty"`
+	PromotedByUserID    *int64      `json:"promoted_by_user_id,omitempty"`
+	CreatedAt           time.Time   `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (ModelVersion) TableName() string {
+	return "model_versions"
+}
+
+// IsValidModelStage는 지정 가능한 모델 단계인지 확인합니다
+func IsValidModelStage(stage string) bool {
+	switch stage {
+	case ModelStageNone, ModelStageStaging, ModelStageProduction, ModelStageArchived:
+		return true
+	}
+	return false
+}
+
+// NormalizeModelVersionTags는 태그 형식을 확인하고 중복을 제거합니다
+func NormalizeModelVersionTags(tags []string) ([]string, error) {
+	if len(tags) > MaxModelVersionTags {
+		return nil, fmt.Errorf("태그는 최대 %d개까지 지정할 수 있습니다", MaxModelVersionTags)
+	}
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !modelVersionTagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("태그는 소문자/숫자로 시작하고 소문자, 숫자, '.', '_', '-'만 사용할 수 있습니다 (최대 32자): %q", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
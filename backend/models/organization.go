@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// 조직 멤버 역할 (권한이 큰 순서)
+const (
+	OrganizationRoleOwner    = "owner"    // 조직 삭제, 소유자 지정까지 모든 권한
+	OrganizationRoleAdmin    = "admin"    // 멤버 관리, 리소스 생성/수정/삭제
+	OrganizationRoleOperator = "operator" // 학습 실행, 헬스체크 등 운영 작업
+	OrganizationRoleViewer   = "viewer"   // 조회만 가능
+)
+
+// IsValidOrganizationRole은 지원하는 역할인지 확인합니다
+func IsValidOrganizationRole(role string) bool {
+	switch role {
+	case OrganizationRoleOwner, OrganizationRoleAdmin, OrganizationRoleOperator, OrganizationRoleViewer:
+		return true
+	}
+	return false
+}
+
+// Organization은 클라우드 연결, 참여자, 집계자, 연합학습을 함께 소유하는 단위입니다
+// 모든 사용자는 자신만 속한 개인 조직(Personal)을 하나씩 가집니다
+//
+// TODO: 프로젝트(조직 안에서 리소스를 묶는 하위 단위)는 후속 작업으로 분리했습니다.
+// 지금은 리소스 범위와 역할이 조직 단위로만 정해지며, 프로젝트를 추가할 때 리소스에 ProjectID를 두고
+// 프로젝트별 역할로 조직 역할을 좁히도록 authz.Principal.Can을 확장합니다
+type Organization struct {
+	ID              int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name            string    `json:"name" gorm:"not null"`
+	Personal        bool      `json:"personal" gorm:"not null;default:false"`
+	CreatedByUserID int64     `json:"created_by_user_id" gorm:"not null;index"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// 관계 설정
+	Members []OrganizationMember `json:"members,omitempty" gorm:"foreignKey:OrganizationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// OrganizationMember는 조직과 사용자의 소속 및 역할입니다
+type OrganizationMember struct {
+	ID             int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrganizationID int64     `json:"organization_id" gorm:"not null;uniqueIndex:idx_org_members_org_user"`
+	UserID         int64     `json:"user_id" gorm:"not null;uniqueIndex:idx_org_members_org_user;index"`
+	Role           string    `json:"role" gorm:"type:varchar(20);not null;check:role IN ('owner','admin','operator','viewer')"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// 관계 설정
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (OrganizationMember) TableName() string {
+	return "organization_members"
+}
+
+// OrganizationWithRole은 사용자 기준 조직 목록 항목입니다
+type OrganizationWithRole struct {
+	Organization
+	Role string `json:"role"`
+}
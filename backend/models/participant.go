@@ -3,28 +3,30 @@ package models
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"time"
 
+	"github.com/Mungge/Fleecy-Cloud/utils/secrets"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Participant struct {
-	ID       string `json:"id" gorm:"primaryKey;type:varchar(36)"`
-	UserID   int64  `json:"user_id" gorm:"not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Name     string `json:"name" gorm:"not null;type:varchar(255)"`
-	Status   string `json:"status" gorm:"type:varchar(50);default:'inactive';check:status IN ('active','inactive')"`
-	Metadata string `json:"metadata,omitempty" gorm:"type:text"`
-	Region   string `json:"region,omitempty" gorm:"not null;type:varchar(100);default:Unknown"`
+	ID             string `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID         int64  `json:"user_id" gorm:"not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	OrganizationID int64  `json:"organization_id" gorm:"not null;default:0;index"` // 소유 조직 (UserID는 생성자)
+	Name           string `json:"name" gorm:"not null;type:varchar(255)"`
+	Status         string `json:"status" gorm:"type:varchar(50);default:'inactive';check:status IN ('active','inactive')"`
+	Metadata       string `json:"metadata,omitempty" gorm:"type:text"`
+	Region         string `json:"region,omitempty" gorm:"not null;type:varchar(100);default:Unknown"`
 
 	// OpenStack 클라우드 관련 필드
-	OpenStackEndpoint                    string `json:"openstack_endpoint,omitempty" gorm:"type:varchar(500)"`              // OpenStack 인증 엔드포인트
-	OpenStackRegion                      string `json:"openstack_region,omitempty" gorm:"type:varchar(100)"`                // OpenStack 리전
-	OpenStackApplicationCredentialID     string `json:"openstack_app_credential_id,omitempty" gorm:"type:varchar(255)"`     // Application Credential ID
-	OpenStackApplicationCredentialSecret string `json:"openstack_app_credential_secret,omitempty" gorm:"type:varchar(500)"` // Application Credential Secret -> 암호화 필요
+	OpenStackEndpoint                    string `json:"openstack_endpoint,omitempty" gorm:"type:varchar(500)"`          // OpenStack 인증 엔드포인트
+	OpenStackRegion                      string `json:"openstack_region,omitempty" gorm:"type:varchar(100)"`            // OpenStack 리전
+	OpenStackApplicationCredentialID     string `json:"openstack_app_credential_id,omitempty" gorm:"type:varchar(255)"` // Application Credential ID
+	OpenStackApplicationCredentialSecret string `json:"openstack_app_credential_secret,omitempty" gorm:"type:text"`     // Application Credential Secret (봉투 암호화 저장)
+	SecretKeyID                          string `json:"-" gorm:"type:varchar(100);index"`                               // Secret 데이터 키를 감싼 마스터 키 ID (비어있으면 이전 방식)
 
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
@@ -32,6 +34,8 @@ type Participant struct {
 	// 관계 설정
 	User               User                `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	FederatedLearnings []FederatedLearning `json:"federated_learnings,omitempty" gorm:"many2many:participant_federated_learnings;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	plainSecret string `gorm:"-"`
 }
 
 func (Participant) TableName() string {
@@ -73,55 +77,65 @@ func (p *Participant) AfterFind(tx *gorm.DB) error {
 	return nil
 }
 
-// 민감한 데이터 암호화 (실제 구현에서는 더 안전한 키 관리 필요)
-func (p *Participant) encryptSensitiveData() error {
-	key := []byte("32-byte-long-key-for-encryption!") // 실제로는 환경변수나 키 관리 시스템에서 가져와야 함
+// AfterSave는 저장 후 메모리상의 민감한 정보를 평문으로 되돌립니다
+func (p *Participant) AfterSave(tx *gorm.DB) error {
+	if p.plainSecret != "" {
+		p.OpenStackApplicationCredentialSecret = p.plainSecret
+		p.plainSecret = ""
+	}
+	return nil
+}
 
-	if p.OpenStackApplicationCredentialSecret != "" {
-		encrypted, err := encrypt(p.OpenStackApplicationCredentialSecret, key)
-		if err != nil {
-			return err
-		}
-		p.OpenStackApplicationCredentialSecret = encrypted
+// 민감한 데이터 봉투 암호화 (이미 암호문이면 그대로 둠)
+func (p *Participant) encryptSensitiveData() error {
+	if p.OpenStackApplicationCredentialSecret == "" || secrets.IsEnvelope(p.OpenStackApplicationCredentialSecret) {
+		return nil
 	}
 
+	sealed, keyID, err := secrets.Seal([]byte(p.OpenStackApplicationCredentialSecret))
+	if err != nil {
+		return err
+	}
+	p.plainSecret = p.OpenStackApplicationCredentialSecret
+	p.OpenStackApplicationCredentialSecret = sealed
+	p.SecretKeyID = keyID
 	return nil
 }
 
 // 민감한 데이터 복호화
 func (p *Participant) decryptSensitiveData() error {
-	key := []byte("32-byte-long-key-for-encryption!") // 실제로는 환경변수나 키 관리 시스템에서 가져와야 함
+	if p.OpenStackApplicationCredentialSecret == "" {
+		return nil
+	}
 
-	if p.OpenStackApplicationCredentialSecret != "" && len(p.OpenStackApplicationCredentialSecret) > 20 {
-		decrypted, err := decrypt(p.OpenStackApplicationCredentialSecret, key)
+	if p.SecretKeyID == "" {
+		decrypted, err := DecryptLegacySecret(p.OpenStackApplicationCredentialSecret)
 		if err != nil {
 			return err
 		}
 		p.OpenStackApplicationCredentialSecret = decrypted
+		return nil
 	}
 
-	return nil
-}
-
-// 간단한 AES 암호화 함수
-func encrypt(plaintext string, key []byte) (string, error) {
-	block, err := aes.NewCipher(key)
+	decrypted, err := secrets.Open(p.OpenStackApplicationCredentialSecret, p.SecretKeyID)
 	if err != nil {
-		return "", err
+		return err
 	}
+	p.OpenStackApplicationCredentialSecret = string(decrypted)
+	return nil
+}
 
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", err
-	}
+// legacySecretKey는 봉투 암호화 도입 전 고정 키입니다
+// 기존 레코드를 읽는 용도로만 남겨두며, secrets-rotate 명령으로 모두 재암호화한 뒤 제거합니다
+var legacySecretKey = []byte("32-byte-long-key-for-encryption!")
 
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := rand.Read(nonce); err != nil {
-		return "", err
+// DecryptLegacySecret은 봉투 암호화 도입 전 방식으로 저장된 Secret을 복호화합니다
+func DecryptLegacySecret(value string) (string, error) {
+	// 짧은 값은 암호화되지 않은 평문으로 저장된 값
+	if len(value) <= 20 {
+		return value, nil
 	}
-
-	ciphertext := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
+	return decrypt(value, legacySecretKey)
 }
 
 // 간단한 AES 복호화 함수
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ParticipantAgentCredential은 참여자 에이전트와의 요청 서명에 쓰는 참여자별 비밀 키입니다
+type ParticipantAgentCredential struct {
+	ParticipantID string    `json:"participant_id" gorm:"primaryKey"`
+	Secret        string    `json:"-" gorm:"type:text;not null"` // 봉투 암호화된 비밀 키 (JSON 응답에서 제외)
+	KeyID         string    `json:"-" gorm:"size:100;index"`     // 데이터 키를 감싼 마스터 키 ID
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Participant *Participant `json:"-" gorm:"foreignKey:ParticipantID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (ParticipantAgentCredential) TableName() string {
+	return "participant_agent_credentials"
+}
@@ -7,10 +7,17 @@ type ParticipantFederatedLearning struct {
 	ParticipantID       string    `json:"participant_id" gorm:"primaryKey"`
 	FederatedLearningID string    `json:"federated_learning_id" gorm:"primaryKey"`
 	JoinedAt            time.Time `json:"joined_at" gorm:"autoCreateTime"`
-	Status              string    `json:"status" gorm:"default:active"` // active, inactive, completed, failed
+	Status              string    `json:"status" gorm:"default:active"` // active, inactive, accepted, installing, training, completed, failed, stopped
 	TasksCompleted      int       `json:"tasks_completed" gorm:"default:0"`
 	LastTaskCompletedAt *time.Time `json:"last_task_completed_at,omitempty"`
-	
+
+	// 참여자 에이전트 작업 상태 (상태 알림으로 갱신)
+	JobID           string     `json:"job_id,omitempty" gorm:"size:255;index"` // 마지막으로 전송한 실행 요청의 작업 ID
+	CurrentRound    int        `json:"current_round" gorm:"default:0"`
+	LastSequence    int64      `json:"-" gorm:"default:0"` // 마지막으로 반영한 알림 순번 (중복/역순 알림 무시)
+	LastMessage     string     `json:"last_message,omitempty" gorm:"type:text"`
+	StatusUpdatedAt *time.Time `json:"status_updated_at,omitempty"`
+
 	// 관계 설정
 	Participant       Participant       `json:"participant,omitempty" gorm:"foreignKey:ParticipantID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	FederatedLearning FederatedLearning `json:"federated_learning,omitempty" gorm:"foreignKey:FederatedLearningID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
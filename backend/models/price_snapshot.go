@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+// PriceSnapshot은 가격표 임포트 단위의 버전 스냅샷입니다
+// effective_at 이전에는 이전 버전이 유효하며, 과거 배치 결정은 버전 번호로 재현할 수 있습니다
+type PriceSnapshot struct {
+	ID          int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Version     int       `json:"version" gorm:"not null;uniqueIndex"`
+	EffectiveAt time.Time `json:"effective_at" gorm:"not null;index"`
+	Source      string    `json:"source" gorm:"size:255"` // 파일명 또는 "seed"
+	Format      string    `json:"format" gorm:"size:10"`  // csv / json
+	Note        string    `json:"note,omitempty" gorm:"type:text"`
+	ItemCount   int       `json:"item_count" gorm:"not null;default:0"`
+	ContentHash string    `json:"content_hash" gorm:"size:64;index"`
+	CreatedBy   *int64    `json:"created_by,omitempty" gorm:"index"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName 테이블 이름 설정
+func (PriceSnapshot) TableName() string {
+	return "price_snapshots"
+}
+
+// PriceSnapshotItem은 스냅샷에 포함된 인스턴스 가격 한 건입니다
+type PriceSnapshotItem struct {
+	ID            int     `json:"id" gorm:"primaryKey;autoIncrement"`
+	SnapshotID    int     `json:"snapshot_id" gorm:"not null;index;uniqueIndex:idx_snapshot_item_unique"`
+	ProviderID    int     `json:"provider_id" gorm:"not null;uniqueIndex:idx_snapshot_item_unique"`
+	RegionID      int     `json:"region_id" gorm:"not null;uniqueIndex:idx_snapshot_item_unique"`
+	InstanceType  string  `json:"instance_type" gorm:"not null;size:30;uniqueIndex:idx_snapshot_item_unique"`
+	VCPUCount     int     `json:"vcpu_count" gorm:"column:v_cpu_count"`
+	MemoryGB      int     `json:"memory_gb" gorm:"not null"`
+	GPUCount      int     `json:"gpu_count" gorm:"column:gpu_count;default:0"`
+	OnDemandPrice float64 `json:"on_demand_price" gorm:"not null;type:decimal(10,6)"`
+
+	// 관계 설정
+	Snapshot PriceSnapshot `json:"-" gorm:"foreignKey:SnapshotID;constraint:OnDelete:CASCADE"`
+	Provider Provider      `json:"provider" gorm:"foreignKey:ProviderID"`
+	Region   Region        `json:"region" gorm:"foreignKey:RegionID"`
+}
+
+// TableName 테이블 이름 설정
+func (PriceSnapshotItem) TableName() string {
+	return "price_snapshot_items"
+}
+
+// ToCloudPrice는 스냅샷 항목을 최적화기 등에서 사용하는 CloudPrice 형식으로 변환합니다
+func (i *PriceSnapshotItem) ToCloudPrice() *CloudPrice {
+	return &CloudPrice{
+		ProviderID:    i.ProviderID,
+		RegionID:      i.RegionID,
+		InstanceType:  i.InstanceType,
+		VCPUCount:     i.VCPUCount,
+		MemoryGB:      i.MemoryGB,
+		GPUCount:      i.GPUCount,
+		OnDemandPrice: i.OnDemandPrice,
+		Provider:      i.Provider,
+		Region:        i.Region,
+	}
+}
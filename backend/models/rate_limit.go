@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// RateLimitEntry는 여러 서버 인스턴스가 공유하는 요청 제한 상태입니다 (RATE_LIMIT_STORE=postgres)
+// 토큰 버킷은 Tokens, 로그인 실패 카운터는 Count를 사용하며 키 접두사로 구분합니다
+type RateLimitEntry struct {
+	Key       string    `gorm:"primaryKey;size:255"`
+	Tokens    float64   `gorm:"not null;default:0"`
+	Count     int       `gorm:"not null;default:0"`
+	TouchedAt time.Time `gorm:"not null;index"`
+}
+
+func (RateLimitEntry) TableName() string {
+	return "rate_limit_entries"
+}
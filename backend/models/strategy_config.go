@@ -0,0 +1,153 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 지원하는 집계 알고리즘 (simulator/aggregators 및 Flower 내장 전략과 동일)
+const (
+	AlgorithmFedAvg     = "fedavg"
+	AlgorithmFedProx    = "fedprox"
+	AlgorithmFedAdam    = "fedadam"
+	AlgorithmFedYogi    = "fedyogi"
+	AlgorithmFedAdagrad = "fedadagrad"
+)
+
+// SupportedAlgorithms는 검증기, 최적화기, 서버 템플릿이 공통으로 허용하는 알고리즘 목록입니다
+var SupportedAlgorithms = []string{
+	AlgorithmFedAvg,
+	AlgorithmFedProx,
+	AlgorithmFedAdam,
+	AlgorithmFedYogi,
+	AlgorithmFedAdagrad,
+}
+
+// LegacyAlgorithms는 이전 버전이 허용하던 알고리즘 이름을 현재 지원하는 알고리즘으로 대응시킵니다
+// fedopt는 Flower의 FedOpt 계열 기본 전략인 FedAdam으로, 내장 전략이 없는 scaffold는
+// 같은 클라이언트 드리프트 보정 목적의 FedProx로 실행합니다
+var LegacyAlgorithms = map[string]string{
+	"fedopt":   AlgorithmFedAdam,
+	"scaffold": AlgorithmFedProx,
+}
+
+// ErrUnsupportedAlgorithm은 지원하지 않는 집계 알고리즘 요청 시 반환됩니다
+var ErrUnsupportedAlgorithm = errors.New("unsupported aggregation algorithm")
+
+// NormalizeAlgorithm은 알고리즘 이름을 소문자 식별자로 정규화하고 지원 여부를 확인합니다
+// 이전 버전의 알고리즘 이름은 LegacyAlgorithms에 따라 대응하는 알고리즘으로 바꿉니다
+func NormalizeAlgorithm(algorithm string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(algorithm))
+	if current, ok := LegacyAlgorithms[normalized]; ok {
+		normalized = current
+	}
+	for _, supported := range SupportedAlgorithms {
+		if normalized == supported {
+			return normalized, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s (지원: %s)", ErrUnsupportedAlgorithm, algorithm, strings.Join(SupportedAlgorithms, ", "))
+}
+
+// StrategyConfig는 Flower 서버 전략과 하이퍼파라미터 설정입니다
+type StrategyConfig struct {
+	Algorithm          string   `json:"algorithm"`
+	ProximalMu         *float64 `json:"proximal_mu,omitempty"`          // FedProx proximal term 계수
+	ServerLearningRate *float64 `json:"server_learning_rate,omitempty"` // FedOpt 계열 서버 학습률 (eta)
+	ClientLearningRate *float64 `json:"client_learning_rate,omitempty"` // FedOpt 계열 클라이언트 학습률 (eta_l)
+	Beta1              *float64 `json:"beta1,omitempty"`                // FedAdam/FedYogi 1차 모멘트 감쇠율
+	Beta2              *float64 `json:"beta2,omitempty"`                // FedAdam/FedYogi 2차 모멘트 감쇠율
+	Tau                *float64 `json:"tau,omitempty"`                  // FedOpt 계열 안정화 항
+}
+
+// strategyParams는 알고리즘별로 허용되는 하이퍼파라미터와 기본값입니다
+var strategyParams = map[string]map[string]float64{
+	AlgorithmFedAvg:     {},
+	AlgorithmFedProx:    {"proximal_mu": 0.01},
+	AlgorithmFedAdam:    {"server_learning_rate": 0.1, "client_learning_rate": 0.1, "beta1": 0.9, "beta2": 0.99, "tau": 1e-3},
+	AlgorithmFedYogi:    {"server_learning_rate": 0.1, "client_learning_rate": 0.1, "beta1": 0.9, "beta2": 0.99, "tau": 1e-3},
+	AlgorithmFedAdagrad: {"server_learning_rate": 0.1, "client_learning_rate": 0.1, "tau": 1e-9},
+}
+
+// params는 하이퍼파라미터 이름과 필드 포인터 목록을 반환합니다
+func (c *StrategyConfig) params() []struct {
+	name  string
+	value **float64
+} {
+	return []struct {
+		name  string
+		value **float64
+	}{
+		{"proximal_mu", &c.ProximalMu},
+		{"server_learning_rate", &c.ServerLearningRate},
+		{"client_learning_rate", &c.ClientLearningRate},
+		{"beta1", &c.Beta1},
+		{"beta2", &c.Beta2},
+		{"tau", &c.Tau},
+	}
+}
+
+// Resolve는 알고리즘을 정규화하고, 해당 알고리즘이 사용하지 않는 하이퍼파라미터를 거부하며,
+// 누락된 하이퍼파라미터를 기본값으로 채운 설정을 반환합니다
+func (c StrategyConfig) Resolve() (StrategyConfig, error) {
+	algorithm, err := NormalizeAlgorithm(c.Algorithm)
+	if err != nil {
+		return StrategyConfig{}, err
+	}
+
+	resolved := StrategyConfig{Algorithm: algorithm}
+	defaults := strategyParams[algorithm]
+
+	sourceParams := c.params()
+	for i, param := range resolved.params() {
+		value := *sourceParams[i].value
+		def, allowed := defaults[param.name]
+		if !allowed {
+			if value != nil {
+				return StrategyConfig{}, fmt.Errorf("%s 알고리즘은 %s 하이퍼파라미터를 사용하지 않습니다", algorithm, param.name)
+			}
+			continue
+		}
+		if value == nil {
+			v := def
+			value = &v
+		}
+		if err := validateStrategyParam(param.name, *value); err != nil {
+			return StrategyConfig{}, err
+		}
+		v := *value
+		*param.value = &v
+	}
+
+	return resolved, nil
+}
+
+// Values는 설정된 하이퍼파라미터를 이름-값 맵으로 반환합니다
+func (c StrategyConfig) Values() map[string]float64 {
+	values := make(map[string]float64)
+	for _, param := range c.params() {
+		if *param.value != nil {
+			values[param.name] = **param.value
+		}
+	}
+	return values
+}
+
+func validateStrategyParam(name string, value float64) error {
+	switch name {
+	case "proximal_mu":
+		if value < 0 {
+			return fmt.Errorf("proximal_mu는 0 이상이어야 합니다: %g", value)
+		}
+	case "beta1", "beta2":
+		if value < 0 || value >= 1 {
+			return fmt.Errorf("%s는 0 이상 1 미만이어야 합니다: %g", name, value)
+		}
+	default:
+		if value <= 0 {
+			return fmt.Errorf("%s는 0보다 커야 합니다: %g", name, value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestNormalizeAlgorithm(t *testing.T) {
+	cases := map[string]string{
+		"fedavg":     AlgorithmFedAvg,
+		" FedProx ":  AlgorithmFedProx,
+		"FEDYOGI":    AlgorithmFedYogi,
+		"fedadagrad": AlgorithmFedAdagrad,
+		// 이전 버전 이름
+		"fedopt":   AlgorithmFedAdam,
+		"SCAFFOLD": AlgorithmFedProx,
+	}
+	for input, want := range cases {
+		got, err := NormalizeAlgorithm(input)
+		if err != nil || got != want {
+			t.Errorf("NormalizeAlgorithm(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "fednova", "fed avg"} {
+		if _, err := NormalizeAlgorithm(input); !errors.Is(err, ErrUnsupportedAlgorithm) {
+			t.Errorf("NormalizeAlgorithm(%q) error = %v, want ErrUnsupportedAlgorithm", input, err)
+		}
+	}
+}
+
+func TestStrategyConfigResolveDefaults(t *testing.T) {
+	cases := []struct {
+		algorithm string
+		want      map[string]float64
+	}{
+		{"FedAvg", map[string]float64{}},
+		{"fedprox", map[string]float64{"proximal_mu": 0.01}},
+		{"fedadam", map[string]float64{"server_learning_rate": 0.1, "client_learning_rate": 0.1, "beta1": 0.9, "beta2": 0.99, "tau": 1e-3}},
+		{"fedyogi", map[string]float64{"server_learning_rate": 0.1, "client_learning_rate": 0.1, "beta1": 0.9, "beta2": 0.99, "tau": 1e-3}},
+		{"fedadagrad", map[string]float64{"server_learning_rate": 0.1, "client_learning_rate": 0.1, "tau": 1e-9}},
+		{"scaffold", map[string]float64{"proximal_mu": 0.01}},
+	}
+	for _, tc := range cases {
+		resolved, err := StrategyConfig{Algorithm: tc.algorithm}.Resolve()
+		if err != nil {
+			t.Errorf("%s: %v", tc.algorithm, err)
+			continue
+		}
+		if got := resolved.Values(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: values = %v, want %v", tc.algorithm, got, tc.want)
+		}
+	}
+
+	// 지정한 값은 유지하고 나머지만 기본값으로 채움
+	resolved, err := StrategyConfig{Algorithm: "fedopt", ServerLearningRate: floatPtr(0.5)}.Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Algorithm != AlgorithmFedAdam || *resolved.ServerLearningRate != 0.5 || *resolved.Beta1 != 0.9 {
+		t.Fatalf("resolved = %+v", resolved.Values())
+	}
+}
+
+func TestStrategyConfigResolveRejectsInvalidParams(t *testing.T) {
+	cases := []struct {
+		name   string
+		config StrategyConfig
+	}{
+		{"unknown algorithm", StrategyConfig{Algorithm: "fednova"}},
+		{"fedavg with proximal_mu", StrategyConfig{Algorithm: "fedavg", ProximalMu: floatPtr(0.1)}},
+		{"fedprox with beta1", StrategyConfig{Algorithm: "fedprox", Beta1: floatPtr(0.9)}},
+		{"fedadagrad with beta2", StrategyConfig{Algorithm: "fedadagrad", Beta2: floatPtr(0.99)}},
+		{"negative proximal_mu", StrategyConfig{Algorithm: "fedprox", ProximalMu: floatPtr(-0.1)}},
+		{"beta1 of 1", StrategyConfig{Algorithm: "fedadam", Beta1: floatPtr(1)}},
+		{"negative beta2", StrategyConfig{Algorithm: "fedyogi", Beta2: floatPtr(-0.5)}},
+		{"zero server learning rate", StrategyConfig{Algorithm: "fedadam", ServerLearningRate: floatPtr(0)}},
+		{"negative tau", StrategyConfig{Algorithm: "fedadagrad", Tau: floatPtr(-1e-9)}},
+	}
+	for _, tc := range cases {
+		if _, err := tc.config.Resolve(); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+
+	// 경계값은 허용
+	if _, err := (StrategyConfig{Algorithm: "fedprox", ProximalMu: floatPtr(0)}).Resolve(); err != nil {
+		t.Errorf("proximal_mu 0: %v", err)
+	}
+	if _, err := (StrategyConfig{Algorithm: "fedyogi", Beta1: floatPtr(0), Beta2: floatPtr(0.999)}).Resolve(); err != nil {
+		t.Errorf("beta bounds: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TaskBundleEntryFile은 번들의 진입 모듈입니다 (client_app.py/server_app.py가 이 모듈을 import)
+const TaskBundleEntryFile = "task.py"
+
+// 번들 크기 제한
+const (
+	MaxTaskBundleFiles        = 32
+	MaxTaskBundleSizeBytes    = 1 << 20 // 파일 내용 합계 1MB
+	MaxTaskBundleDependencies = 64
+)
+
+// TaskBundleRequiredSymbols는 진입 모듈이 정의해야 하는 이름 목록입니다
+var TaskBundleRequiredSymbols = []string{"Net", "load_data", "train", "test", "get_weights", "set_weights"}
+
+// taskBundleReservedFiles는 플랫폼이 생성하므로 번들에 포함할 수 없는 파일 이름입니다
+var taskBundleReservedFiles = map[string]bool{
+	"client_app.py":      true,
+	"server_app.py":      true,
+	"__init__.py":        true,
+	"pyproject.toml":     true,
+	"run_server.sh":      true,
+	"bundle_config.json": true,
+}
+
+// taskBundleReservedConfigKeys는 플랫폼이 작업 설정에서 채우는 [tool.flwr.app.config] 키입니다
+var taskBundleReservedConfigKeys = map[string]bool{
+	"model-type":            true,
+	"num-server-rounds":     true,
+	"algorithm":             true,
+	"fraction-fit":          true,
+	"min-fit-clients":       true,
+	"min-available-clients": true,
+	"proximal-mu":           true,
+	"server-learning-rate":  true,
+	"client-learning-rate":  true,
+	"beta1":                 true,
+	"beta2":                 true,
+	"tau":                   true,
+}
+
+var (
+	taskBundleNamePattern     = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,62}$`)
+	taskBundleFilePattern     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\.(py|json|txt|yaml)$`)
+	taskBundleHyperparamKey   = regexp.MustCompile(`^[a-z][a-z0-9-]{0,63}$`)
+	taskBundleDependencyRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*(\[[A-Za-z0-9._,-]+\])?((==|!=|<=|>=|~=|<|>)[A-Za-z0-9.*+!_-]+)?(,(==|!=|<=|>=|~=|<|>)[A-Za-z0-9.*+!_-]+)*$`)
+)
+
+// TaskBundle은 사용자가 업로드한 모델/학습 코드 번들의 한 버전입니다
+// 같은 조직의 같은 이름 번들은 업로드할 때마다 버전이 1씩 증가하며, 한 번 저장된 버전은 변경하지 않습니다
+type TaskBundle struct {
+	ID              string                 `json:"id" gorm:"primaryKey"`
+	OrganizationID  int64                  `json:"organization_id" gorm:"not null;uniqueIndex:idx_task_bundle_version"`
+	CreatedByUserID int64                  `json:"created_by_user_id" gorm:"not null"`
+	Name            string                 `json:"name" gorm:"size:64;not null;uniqueIndex:idx_task_bundle_version"`
+	Version         int                    `json:"version" gorm:"not null;uniqueIndex:idx_task_bundle_version"`
+	Description     string                 `json:"description"`
+	Files           map[string]string      `json:"files,omitempty" gorm:"serializer:json;type:text;not null"` // 파일 이름 → 내용 (목록 조회 시 제외)
+	Hyperparameters map[string]interface{} `json:"hyperparameters" gorm:"serializer:json;type:text"`          // [tool.flwr.app.config]에 추가되는 학습 하이퍼파라미터
+	Dependencies    []string               `json:"dependencies" gorm:"serializer:json;type:text"`             // 추가로 설치할 Python 패키지 (PEP 508 이름/버전 지정자)
+	FileNames       []string               `json:"file_names" gorm:"-"`
+	ContentHash     string                 `json:"content_hash" gorm:"size:64;not null;index"` // 파일/하이퍼파라미터/의존성의 SHA-256
+	SizeBytes       int                    `json:"size_bytes"`
+	CreatedAt       time.Time              `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (TaskBundle) TableName() string {
+	return "task_bundles"
+}
+
+// ValidateTaskBundleName은 번들 이름 형식을 확인합니다 (소문자, 숫자, '.', '_', '-')
+func ValidateTaskBundleName(name string) error {
+	if !taskBundleNamePattern.MatchString(name) {
+		return fmt.Errorf("번들 이름은 소문자/숫자로 시작하고 소문자, 숫자, '.', '_', '-'만 사용할 수 있습니다 (최대 63자): %q", name)
+	}
+	return nil
+}
+
+// Validate는 번들 내용(파일, 하이퍼파라미터, 의존성)을 검증하고 의존성 목록을 정렬합니다
+func (b *TaskBundle) Validate() error {
+	if err := ValidateTaskBundleName(b.Name); err != nil {
+		return err
+	}
+
+	if len(b.Files) == 0 {
+		return fmt.Errorf("번들 파일이 없습니다 (%s 필수)", TaskBundleEntryFile)
+	}
+	if len(b.Files) > MaxTaskBundleFiles {
+		return fmt.Errorf("번들 파일은 최대 %d개까지 포함할 수 있습니다", MaxTaskBundleFiles)
+	}
+	size := 0
+	for name, content := range b.Files {
+		if taskBundleReservedFiles[name] {
+			return fmt.Errorf("%s은(는) 플랫폼이 생성하는 파일이므로 번들에 포함할 수 없습니다", name)
+		}
+		if !taskBundleFilePattern.MatchString(name) {
+			return fmt.Errorf("허용되지 않는 파일 이름입니다 (디렉토리 없이 .py, .json, .txt, .yaml만 허용): %q", name)
+		}
+		if strings.ContainsRune(content, 0) {
+			return fmt.Errorf("%s: 텍스트 파일만 포함할 수 있습니다", name)
+		}
+		size += len(content)
+	}
+	if size > MaxTaskBundleSizeBytes {
+		return fmt.Errorf("번들 크기가 제한(%d바이트)을 초과합니다: %d바이트", MaxTaskBundleSizeBytes, size)
+	}
+	b.SizeBytes = size
+
+	entry, ok := b.Files[TaskBundleEntryFile]
+	if !ok {
+		return fmt.Errorf("진입 모듈 %s이(가) 필요합니다", TaskBundleEntryFile)
+	}
+	if missing := missingPythonSymbols(entry, TaskBundleRequiredSymbols); len(missing) > 0 {
+		return fmt.Errorf("%s에 필요한 정의가 없습니다: %s", TaskBundleEntryFile, strings.Join(missing, ", "))
+	}
+
+	for key, value := range b.Hyperparameters {
+		if !taskBundleHyperparamKey.MatchString(key) {
+			return fmt.Errorf("하이퍼파라미터 이름은 소문자로 시작하고 소문자, 숫자, '-'만 사용할 수 있습니다: %q", key)
+		}
+		if taskBundleReservedConfigKeys[key] {
+			return fmt.Errorf("%s은(는) 연합학습 작업 설정으로 지정하는 값이므로 번들 하이퍼파라미터로 사용할 수 없습니다", key)
+		}
+		if err := validateHyperparameterValue(key, value); err != nil {
+			return err
+		}
+	}
+
+	if len(b.Dependencies) > MaxTaskBundleDependencies {
+		return fmt.Errorf("의존성은 최대 %d개까지 지정할 수 있습니다", MaxTaskBundleDependencies)
+	}
+	deps := make([]string, 0, len(b.Dependencies))
+	seen := make(map[string]bool)
+	for _, dep := range b.Dependencies {
+		dep = strings.ReplaceAll(strings.TrimSpace(dep), " ", "")
+		if !taskBundleDependencyRegex.MatchString(dep) {
+			return fmt.Errorf("의존성은 패키지 이름과 버전 지정자만 허용됩니다 (URL, 경로, 옵션 불가): %q", dep)
+		}
+		if !seen[dep] {
+			seen[dep] = true
+			deps = append(deps, dep)
+		}
+	}
+	sort.Strings(deps)
+	b.Dependencies = deps
+	return nil
+}
+
+// ComputeContentHash는 파일, 하이퍼파라미터, 의존성을 정렬된 순서로 직렬화한 SHA-256을 반환합니다
+// 이름, 설명, 버전은 포함하지 않으므로 같은 내용을 다시 업로드하면 같은 해시가 됩니다
+func (b *TaskBundle) ComputeContentHash() string {
+	names := b.SortedFileNames()
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "file %d:%s %d:", len(name), name, len(b.Files[name]))
+		h.Write([]byte(b.Files[name]))
+		h.Write([]byte{'\n'})
+	}
+	// encoding/json은 맵 키를 정렬해서 직렬화합니다
+	hyperparams, _ := json.Marshal(b.Hyperparameters)
+	fmt.Fprintf(h, "hyperparameters %s\n", hyperparams)
+	for _, dep := range b.Dependencies {
+		fmt.Fprintf(h, "dependency %s\n", dep)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SortedFileNames는 번들 파일 이름을 정렬해서 반환합니다
+func (b *TaskBundle) SortedFileNames() []string {
+	names := make([]string, 0, len(b.Files))
+	for name := range b.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateHyperparameterValue(key string, value interface{}) error {
+	switch v := value.(type) {
+	case bool:
+		return nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("하이퍼파라미터 %s 값이 올바르지 않습니다", key)
+		}
+		return nil
+	case string:
+		if len(v) > 256 || strings.IndexFunc(v, unicode.IsControl) >= 0 {
+			return fmt.Errorf("하이퍼파라미터 %s 문자열은 제어 문자 없이 256자 이하여야 합니다", key)
+		}
+		return nil
+	default:
+		return fmt.Errorf("하이퍼파라미터 %s는 숫자, 문자열, 불리언만 허용됩니다", key)
+	}
+}
+
+// missingPythonSymbols는 모듈 최상위에서 정의(def/class/대입)되지 않은 이름 목록을 반환합니다
+func missingPythonSymbols(source string, symbols []string) []string {
+	var missing []string
+	for _, symbol := range symbols {
+		pattern := regexp.MustCompile(`(?m)^(def|class)\s+` + symbol + `\b|^` + symbol + `\s*=|^from\s+\S+\s+import\s+.*\b` + symbol + `\b`)
+		if !pattern.MatchString(source) {
+			missing = append(missing, symbol)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+const testTaskEntry = `import torch
+from torch.utils.data import DataLoader
+
+class Net(torch.nn.Module):
+    pass
+
+def load_data(partition_id, num_partitions):
+    pass
+
+def train(net, loader, epochs, device):
+    pass
+
+def test(net, loader, device):
+    pass
+
+get_weights = lambda net: []
+
+def set_weights(net, parameters):
+    pass
+`
+
+func newTestTaskBundle() *TaskBundle {
+	return &TaskBundle{
+		Name:            "mnist-cnn",
+		Files:           map[string]string{TaskBundleEntryFile: testTaskEntry, "utils.py": "def helper():\n    pass\n"},
+		Hyperparameters: map[string]interface{}{"local-epochs": float64(3), "batch-size": float64(32), "optimizer": "adam", "augment": true},
+		Dependencies:    []string{"timm==1.0.9", " numpy >= 1.26 ", "timm==1.0.9"},
+	}
+}
+
+func TestTaskBundleValidate(t *testing.T) {
+	bundle := newTestTaskBundle()
+	if err := bundle.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	// 의존성은 공백 제거, 중복 제거 후 정렬
+	if got := strings.Join(bundle.Dependencies, " "); got != "numpy>=1.26 timm==1.0.9" {
+		t.Fatalf("dependencies = %q", got)
+	}
+	if bundle.SizeBytes != len(testTaskEntry)+len("def helper():\n    pass\n") {
+		t.Fatalf("size = %d", bundle.SizeBytes)
+	}
+
+	cases := []struct {
+		name   string
+		mutate func(*TaskBundle)
+		want   string
+	}{
+		{"invalid name", func(b *TaskBundle) { b.Name = "Bad Name" }, "번들 이름"},
+		{"no files", func(b *TaskBundle) { b.Files = nil }, "번들 파일이 없습니다"},
+		{"missing entry", func(b *TaskBundle) { delete(b.Files, TaskBundleEntryFile) }, TaskBundleEntryFile},
+		{"missing symbol", func(b *TaskBundle) {
+			b.Files[TaskBundleEntryFile] = strings.Replace(testTaskEntry, "def set_weights", "def other", 1)
+		}, "set_weights"},
+		{"reserved file", func(b *TaskBundle) { b.Files["client_app.py"] = "" }, "client_app.py"},
+		{"nested path", func(b *TaskBundle) { b.Files["../evil.py"] = "" }, "허용되지 않는 파일 이름"},
+		{"binary content", func(b *TaskBundle) { b.Files["data.txt"] = "a\x00b" }, "텍스트 파일"},
+		{"too large", func(b *TaskBundle) { b.Files["big.txt"] = strings.Repeat("a", MaxTaskBundleSizeBytes) }, "번들 크기"},
+		{"reserved config key", func(b *TaskBundle) { b.Hyperparameters["num-server-rounds"] = float64(5) }, "num-server-rounds"},
+		{"model type key", func(b *TaskBundle) { b.Hyperparameters["model-type"] = "resnet" }, "model-type"},
+		{"invalid key", func(b *TaskBundle) { b.Hyperparameters["Learning_Rate"] = 0.1 }, "하이퍼파라미터 이름"},
+		{"nested value", func(b *TaskBundle) { b.Hyperparameters["layers"] = []interface{}{1, 2} }, "layers"},
+		{"control character", func(b *TaskBundle) { b.Hyperparameters["optimizer"] = "adam\"\nx = 1" }, "제어 문자"},
+		{"url dependency", func(b *TaskBundle) { b.Dependencies = []string{"git+https://example.com/pkg.git"} }, "의존성"},
+		{"option dependency", func(b *TaskBundle) { b.Dependencies = []string{"--index-url=https://evil"} }, "의존성"},
+		{"quoted dependency", func(b *TaskBundle) { b.Dependencies = []string{"numpy'; rm -rf /'"} }, "의존성"},
+	}
+	for _, tc := range cases {
+		bundle := newTestTaskBundle()
+		tc.mutate(bundle)
+		err := bundle.Validate()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestTaskBundleComputeContentHash(t *testing.T) {
+	bundle := newTestTaskBundle()
+	if err := bundle.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	hash := bundle.ComputeContentHash()
+	if len(hash) != 64 {
+		t.Fatalf("hash = %q", hash)
+	}
+
+	// 이름, 설명, 버전은 해시에 포함하지 않음
+	renamed := newTestTaskBundle()
+	renamed.Validate()
+	renamed.Name, renamed.Description, renamed.Version = "other", "설명", 7
+	if renamed.ComputeContentHash() != hash {
+		t.Fatal("metadata must not change the hash")
+	}
+
+	cases := map[string]func(*TaskBundle){
+		"file content":   func(b *TaskBundle) { b.Files["utils.py"] += "#" },
+		"file name":      func(b *TaskBundle) { b.Files["helpers.py"] = b.Files["utils.py"]; delete(b.Files, "utils.py") },
+		"hyperparameter": func(b *TaskBundle) { b.Hyperparameters["local-epochs"] = float64(4) },
+		"value type":     func(b *TaskBundle) { b.Hyperparameters["local-epochs"] = "3" },
+		"dependency":     func(b *TaskBundle) { b.Dependencies = append(b.Dependencies, "scipy") },
+		// 파일 경계가 길이로 구분되어 내용을 옮겨도 다른 해시
+		"moved boundary": func(b *TaskBundle) { b.Files["utils.py"] = "def helper():\n"; b.Files["x.py"] = "    pass\n" },
+	}
+	for name, mutate := range cases {
+		changed := newTestTaskBundle()
+		changed.Validate()
+		mutate(changed)
+		if changed.ComputeContentHash() == hash {
+			t.Errorf("%s: hash did not change", name)
+		}
+	}
+}
@@ -5,12 +5,14 @@ import (
 )
 
 type User struct {
-	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
-	Email        string    `json:"email" gorm:"uniqueIndex:idx_users_email;not null"`
-	PasswordHash string    `json:"-" gorm:"column:password_hash;not null"`
-	Name         string    `json:"name" gorm:"not null"`
-	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID           int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	Email        string `json:"email" gorm:"uniqueIndex:idx_users_email;not null"`
+	PasswordHash string `json:"-" gorm:"column:password_hash;not null"`
+	Name         string `json:"name" gorm:"not null"`
+	// 이메일 인증 완료 시각 (nil이면 미인증, 로컬 회원가입 후 인증 전까지 로그인 불가)
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Releationships
 	CloudConnections   []CloudConnection   `json:"cloud_connections,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
@@ -23,3 +25,7 @@ func (User) TableName() string {
 	return "users"
 }
 
+// IsEmailVerified는 이메일 인증이 완료되었는지 확인합니다
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// 일회용 사용자 토큰 용도
+const (
+	UserTokenPurposePasswordReset     = "password_reset"
+	UserTokenPurposeEmailVerification = "email_verification"
+)
+
+// UserToken은 비밀번호 재설정, 이메일 인증 등에 쓰이는 일회용 토큰입니다
+// 원문 토큰은 메일로만 전달하고 DB에는 SHA-256 해시만 저장합니다
+type UserToken struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"user_id" gorm:"not null;index"`
+	Purpose   string     `json:"purpose" gorm:"size:32;not null;index"`
+	TokenHash string     `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null;index"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	// 관계 설정
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
+
+// GenerateUserToken은 새 일회용 토큰 원문과 저장용 해시를 생성합니다
+func GenerateUserToken() (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(bytes)
+	return token, HashUserToken(token), nil
+}
+
+// HashUserToken은 토큰 원문의 SHA-256 해시를 반환합니다
+func HashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// 토큰이 사용 가능한지 확인
+func (t *UserToken) IsValid() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+)
+
+type AggregatorDeploymentRepository struct {
+	db *gorm.DB
+}
+
+func NewAggregatorDeploymentRepository(db *gorm.DB) *AggregatorDeploymentRepository {
+	return &AggregatorDeploymentRepository{db: db}
+}
+
+// CreateDeployment 배포 작업 생성
+func (r *AggregatorDeploymentRepository) CreateDeployment(deployment *models.AggregatorDeployment) error {
+	return r.db.Create(deployment).Error
+}
+
+// GetDeploymentByID ID로 배포 작업 조회
+func (r *AggregatorDeploymentRepository) GetDeploymentByID(id string) (*models.AggregatorDeployment, error) {
+	var deployment models.AggregatorDeployment
+	if err := r.db.Where("id = ?", id).First(&deployment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// GetLatestDeploymentByAggregatorID 집계자의 가장 최근 배포 작업 조회
+func (r *AggregatorDeploymentRepository) GetLatestDeploymentByAggregatorID(aggregatorID string) (*models.AggregatorDeployment, error) {
+	var deployment models.AggregatorDeployment
+	err := r.db.Where("aggregator_id = ?", aggregatorID).
+		Order("created_at DESC").
+		First(&deployment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &deployment, nil
+}
+
+// GetUnfinishedDeployments 종료되지 않은 배포 작업 목록 조회
+func (r *AggregatorDeploymentRepository) GetUnfinishedDeployments() ([]*models.AggregatorDeployment, error) {
+	var deployments []*models.AggregatorDeployment
+	err := r.db.Where("state NOT IN ?", []string{models.DeploymentStateRunning, models.DeploymentStateFailed}).
+		Order("created_at ASC").
+		Find(&deployments).Error
+	return deployments, err
+}
+
+// UpdateDeploymentState 배포 작업 상태 전이 기록
+func (r *AggregatorDeploymentRepository) UpdateDeploymentState(deployment *models.AggregatorDeployment) error {
+	updates := map[string]interface{}{
+		"state":      deployment.State,
+		"stage":      deployment.Stage,
+		"message":    deployment.Message,
+		"error":      deployment.Error,
+		"updated_at": time.Now(),
+	}
+	if deployment.StartedAt != nil {
+		updates["started_at"] = deployment.StartedAt
+	}
+	if deployment.FinishedAt != nil {
+		updates["finished_at"] = deployment.FinishedAt
+	}
+	return r.db.Model(&models.AggregatorDeployment{}).Where("id = ?", deployment.ID).Updates(updates).Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/google/uuid"
@@ -22,9 +23,10 @@ func (r *AggregatorRepository) CreateAggregator(aggregator *models.Aggregator) e
 	return r.db.Create(aggregator).Error
 }
 
-func (r *AggregatorRepository) GetAggregatorsByUserID(userID int64) ([]*models.Aggregator, error) {
+// GetAggregatorsByOrganizationID 조직의 집계자 목록 조회
+func (r *AggregatorRepository) GetAggregatorsByOrganizationID(orgID int64) ([]*models.Aggregator, error) {
 	var aggregators []*models.Aggregator
-	err := r.db.Where("user_id = ?", userID).
+	err := r.db.Where("organization_id = ?", orgID).
 		Order("created_at DESC").
 		Find(&aggregators).Error
 	return aggregators, err
@@ -61,8 +63,120 @@ func (r *AggregatorRepository) UpdateAggregator(aggregator *models.Aggregator) e
 	return r.db.Save(aggregator).Error
 }
 
+// UpdateAggregatorStatus는 상태를 변경하고 비용 계산용 running 구간을 함께 기록합니다
 func (r *AggregatorRepository) UpdateAggregatorStatus(id string, status string) error {
-	return r.db.Model(&models.Aggregator{}).Where("id = ?", id).Update("status", status).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Aggregator{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+			return err
+		}
+		return recordRunPeriod(tx, id, status == models.AggregatorStatusRunning, time.Now())
+	})
+}
+
+// MarkAggregatorDeleting은 삭제 중이거나 삭제된 집계자가 아닐 때만 상태를 deleting으로 바꿉니다
+// 동시에 들어온 삭제 요청 중 하나만 destroy 작업을 등록하도록 변경 여부를 반환합니다
+func (r *AggregatorRepository) MarkAggregatorDeleting(id string) (bool, error) {
+	marked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Aggregator{}).
+			Where("id = ? AND status NOT IN ?", id, []string{models.AggregatorStatusDeleting, models.AggregatorStatusTerminated}).
+			Update("status", models.AggregatorStatusDeleting)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		marked = true
+		return recordRunPeriod(tx, id, false, time.Now())
+	})
+	return marked, err
+}
+
+// recordRunPeriod는 running 진입 시 구간을 열고, 이탈 시 열린 구간을 닫습니다
+func recordRunPeriod(tx *gorm.DB, aggregatorID string, running bool, at time.Time) error {
+	var open int64
+	if err := tx.Model(&models.AggregatorRunPeriod{}).
+		Where("aggregator_id = ? AND ended_at IS NULL", aggregatorID).
+		Count(&open).Error; err != nil {
+		return err
+	}
+
+	if running {
+		if open > 0 {
+			return nil
+		}
+		return tx.Create(&models.AggregatorRunPeriod{AggregatorID: aggregatorID, StartedAt: at}).Error
+	}
+
+	if open == 0 {
+		return nil
+	}
+	return tx.Model(&models.AggregatorRunPeriod{}).
+		Where("aggregator_id = ? AND ended_at IS NULL", aggregatorID).
+		Update("ended_at", at).Error
+}
+
+// EnsureRunPeriod는 running 상태이지만 열린 구간이 없는 집계자(구간 기록 도입 전 배포)에 구간을 엽니다
+func (r *AggregatorRepository) EnsureRunPeriod(aggregatorID string, startedAt time.Time) error {
+	return recordRunPeriod(r.db, aggregatorID, true, startedAt)
+}
+
+// GetRunPeriodsByAggregatorIDs는 집계자별 running 구간을 조회합니다
+func (r *AggregatorRepository) GetRunPeriodsByAggregatorIDs(ids []string) (map[string][]*models.AggregatorRunPeriod, error) {
+	result := make(map[string][]*models.AggregatorRunPeriod)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var periods []*models.AggregatorRunPeriod
+	if err := r.db.Where("aggregator_id IN ?", ids).Order("started_at ASC").Find(&periods).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range periods {
+		result[p.AggregatorID] = append(result[p.AggregatorID], p)
+	}
+	return result, nil
+}
+
+// GetMeteredAggregators는 비용을 갱신해야 하는 집계자를 조회합니다
+// 실행 중이거나, running 구간이 since 이후에 닫힌 집계자가 대상입니다
+func (r *AggregatorRepository) GetMeteredAggregators(since time.Time) ([]*models.Aggregator, error) {
+	var aggregators []*models.Aggregator
+	err := r.db.Where("status = ?", models.AggregatorStatusRunning).
+		Or("id IN (?)", r.db.Model(&models.AggregatorRunPeriod{}).Select("aggregator_id").Where("ended_at >= ?", since)).
+		Find(&aggregators).Error
+	return aggregators, err
+}
+
+// GetAggregatorsWithFederatedLearningByOrganizationID는 연합학습과 생성자 정보를 포함해 조직의 집계자를 조회합니다
+func (r *AggregatorRepository) GetAggregatorsWithFederatedLearningByOrganizationID(orgID int64) ([]*models.Aggregator, error) {
+	var aggregators []*models.Aggregator
+	err := r.db.Where("organization_id = ?", orgID).
+		Preload("FederatedLearning").
+		Preload("User").
+		Order("created_at DESC").
+		Find(&aggregators).Error
+	return aggregators, err
+}
+
+// GetAverageRoundDuration은 완료된 라운드의 평균 소요 시간(초)과 라운드 수를 반환합니다
+func (r *AggregatorRepository) GetAverageRoundDuration(aggregatorID string) (float64, int64, error) {
+	var result struct {
+		Average float64
+		Count   int64
+	}
+	err := r.db.Model(&models.TrainingRound{}).
+		Select("COALESCE(AVG(duration), 0) AS average, COUNT(*) AS count").
+		Where("aggregator_id = ? AND duration > 0", aggregatorID).
+		Scan(&result).Error
+	return result.Average, result.Count, err
+}
+
+// UpdateAggregatorCurrentCost는 누적 비용을 갱신합니다
+func (r *AggregatorRepository) UpdateAggregatorCurrentCost(id string, cost float64) error {
+	return r.db.Model(&models.Aggregator{}).Where("id = ?", id).
+		UpdateColumn("current_cost", cost).Error
 }
 
 func (r *AggregatorRepository) UpdateAggregatorMLflowInfo(id, experimentID, experimentName string) error {
@@ -116,11 +230,15 @@ func (r *AggregatorRepository) DeleteAggregator(id string) error {
 		}
 	}()
 
-	// 1. 먼저 관련된 training_rounds 삭제
+	// 1. 먼저 관련된 training_rounds, 비용 계산용 running 구간 삭제
 	if err := tx.Where("aggregator_id = ?", id).Delete(&models.TrainingRound{}).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
+	if err := tx.Where("aggregator_id = ?", id).Delete(&models.AggregatorRunPeriod{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
 
 	// 2. 그 다음 aggregator 삭제
 	if err := tx.Delete(&models.Aggregator{}, "id = ?", id).Error; err != nil {
@@ -164,16 +282,16 @@ func (r *AggregatorRepository) UpdateTrainingRound(round *models.TrainingRound)
 }
 
 // Aggregator 통계 메서드들
-func (r *AggregatorRepository) GetAggregatorStats(userID int64) (map[string]interface{}, error) {
+func (r *AggregatorRepository) GetAggregatorStats(orgID int64) (map[string]interface{}, error) {
 	var total, running, completed, pending int64
 
-	r.db.Model(&models.Aggregator{}).Where("user_id = ?", userID).Count(&total)
-	r.db.Model(&models.Aggregator{}).Where("user_id = ? AND status = ?", userID, "running").Count(&running)
-	r.db.Model(&models.Aggregator{}).Where("user_id = ? AND status = ?", userID, "completed").Count(&completed)
-	r.db.Model(&models.Aggregator{}).Where("user_id = ? AND status = ?", userID, "pending").Count(&pending)
+	r.db.Model(&models.Aggregator{}).Where("organization_id = ?", orgID).Count(&total)
+	r.db.Model(&models.Aggregator{}).Where("organization_id = ? AND status = ?", orgID, "running").Count(&running)
+	r.db.Model(&models.Aggregator{}).Where("organization_id = ? AND status = ?", orgID, "completed").Count(&completed)
+	r.db.Model(&models.Aggregator{}).Where("organization_id = ? AND status = ?", orgID, "pending").Count(&pending)
 
 	var totalCost float64
-	r.db.Model(&models.Aggregator{}).Where("user_id = ?", userID).Select("COALESCE(SUM(current_cost), 0)").Scan(&totalCost)
+	r.db.Model(&models.Aggregator{}).Where("organization_id = ?", orgID).Select("COALESCE(SUM(current_cost), 0)").Scan(&totalCost)
 
 	return map[string]interface{}{
 		"total":      total,
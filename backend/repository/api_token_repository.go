@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+)
+
+// apiTokenTouchInterval 마지막 사용 시각 갱신 최소 간격 (요청마다 쓰기가 발생하지 않도록)
+const apiTokenTouchInterval = time.Minute
+
+type APITokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) *APITokenRepository {
+	return &APITokenRepository{db: db}
+}
+
+// CreateToken 개인 액세스 토큰 저장
+func (r *APITokenRepository) CreateToken(token *models.APIToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetTokenByHash 해시로 토큰 조회 (폐기/만료 여부와 관계없이 조회)
+func (r *APITokenRepository) GetTokenByHash(tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetTokenByID 사용자 소유 토큰 조회
+func (r *APITokenRepository) GetTokenByID(userID, id int64) (*models.APIToken, error) {
+	var token models.APIToken
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&token).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListTokensByUserID 사용자의 토큰 목록 조회 (최신순)
+func (r *APITokenRepository) ListTokensByUserID(userID int64) ([]*models.APIToken, error) {
+	var tokens []*models.APIToken
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// RevokeToken 토큰 폐기
+func (r *APITokenRepository) RevokeToken(userID, id int64) error {
+	return r.db.Model(&models.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllUserTokens 사용자의 모든 토큰 폐기
+func (r *APITokenRepository) RevokeAllUserTokens(userID int64) error {
+	return r.db.Model(&models.APIToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// TouchLastUsed 마지막 사용 시각과 IP 갱신 (최근에 갱신했으면 건너뜀)
+func (r *APITokenRepository) TouchLastUsed(id int64, ip string) error {
+	now := time.Now()
+	return r.db.Model(&models.APIToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-apiTokenTouchInterval)).
+		Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip}).Error
+}
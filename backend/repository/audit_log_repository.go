@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+)
+
+// auditExportBatchSize 내보내기 시 한 번에 읽는 행 수
+const auditExportBatchSize = 500
+
+// AuditLogRepository 감사 로그 저장소 (추가/조회만 제공)
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// Create 감사 로그 추가
+func (r *AuditLogRepository) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// List 조건에 맞는 감사 로그를 최신순으로 조회하고 전체 건수를 함께 반환
+func (r *AuditLogRepository) List(filter models.AuditLogFilter) ([]*models.AuditLog, int64, error) {
+	query := r.filtered(filter)
+
+	var total int64
+	if err := query.Model(&models.AuditLog{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []*models.AuditLog
+	q := r.filtered(filter).Order("id DESC")
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		q = q.Offset(filter.Offset)
+	}
+	if err := q.Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// Export 조건에 맞는 감사 로그를 오래된 순으로 배치 단위로 전달
+func (r *AuditLogRepository) Export(filter models.AuditLogFilter, fn func([]*models.AuditLog) error) error {
+	var batch []*models.AuditLog
+	return r.filtered(filter).Order("id ASC").FindInBatches(&batch, auditExportBatchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+func (r *AuditLogRepository) filtered(filter models.AuditLogFilter) *gorm.DB {
+	query := r.db.Model(&models.AuditLog{})
+	if filter.OrganizationID != nil {
+		query = query.Where("organization_id = ?", *filter.OrganizationID)
+	}
+	if filter.ActorUserID != nil {
+		query = query.Where("actor_user_id = ?", *filter.ActorUserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action LIKE ?", escapeLike(filter.Action)+"%")
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CloudLatencyRepository struct {
@@ -13,6 +14,13 @@ func NewCloudLatencyRepository(db *gorm.DB) *CloudLatencyRepository {
 	return &CloudLatencyRepository{db: db}
 }
 
+func (r *CloudLatencyRepository) GetAllCloudLatencies() ([]*models.CloudLatency, error) {
+	var latencies []*models.CloudLatency
+	err := r.db.Preload("SourceProvider").Preload("SourceRegion").Preload("TargetProvider").Preload("TargetRegion").
+		Order("id ASC").Find(&latencies).Error
+	return latencies, err
+}
+
 func (r *CloudLatencyRepository) GetCloudLatencyByRegion(regionID int) ([]*models.CloudLatency, error) {
 	var latencies []*models.CloudLatency
 	err := r.db.Preload("SourceProvider").Preload("SourceRegion").Preload("TargetProvider").Preload("TargetRegion").
@@ -30,4 +38,12 @@ func (r *CloudLatencyRepository) GetCloudLatencyByRegionName(regionName string)
 
 func (r *CloudLatencyRepository) UpdateCloudLatency(latency *models.CloudLatency) error {
 	return r.db.Save(latency).Error
+}
+
+// UpsertCloudLatency는 리전 쌍의 지연시간을 생성하거나 avg/min/max 값을 갱신합니다
+func (r *CloudLatencyRepository) UpsertCloudLatency(latency *models.CloudLatency) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source_provider_id"}, {Name: "source_region_id"}, {Name: "target_provider_id"}, {Name: "target_region_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"avg_latency", "min_latency", "max_latency"}),
+	}).Create(latency).Error
 }
\ No newline at end of file
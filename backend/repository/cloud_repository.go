@@ -20,9 +20,10 @@ func (r *CloudRepository) CreateCloudConnection(conn *models.CloudConnection) er
 	return r.db.Create(conn).Error
 }
 
-func (r *CloudRepository) GetCloudConnectionsByUserID(userID int64) ([]*models.CloudConnection, error) {
+// GetCloudConnectionsByOrganizationID 조직의 클라우드 연결 목록 조회
+func (r *CloudRepository) GetCloudConnectionsByOrganizationID(orgID int64) ([]*models.CloudConnection, error) {
 	var connections []*models.CloudConnection
-	err := r.db.Where("user_id = ?", userID).Find(&connections).Error
+	err := r.db.Where("organization_id = ?", orgID).Find(&connections).Error
 	return connections, err
 }
 
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // FederatedLearningRepository는 연합학습 모델의 데이터 액세스 계층입니다
@@ -55,10 +59,10 @@ func (r *FederatedLearningRepository) CreateWithParticipants(fl *models.Federate
 	})
 }
 
-// GetByUserID는 사용자 ID로 연합학습 목록을 조회합니다
-func (r *FederatedLearningRepository) GetByUserID(userID int64) ([]*models.FederatedLearning, error) {
+// GetByOrganizationID는 조직 ID로 연합학습 목록을 조회합니다
+func (r *FederatedLearningRepository) GetByOrganizationID(orgID int64) ([]*models.FederatedLearning, error) {
 	var learnings []*models.FederatedLearning
-	err := r.db.Preload("Participants").Where("user_id = ?", userID).Find(&learnings).Error
+	err := r.db.Preload("Participants").Where("organization_id = ?", orgID).Find(&learnings).Error
 	return learnings, err
 }
 
@@ -76,13 +80,66 @@ func (r *FederatedLearningRepository) GetByID(id string) (*models.FederatedLearn
 }
 
 // Update는 연합학습 정보를 업데이트합니다
+// 상태, 완료 시간, 재시작 횟수는 TransitionStatus/IncrementAttempt로만 바꿉니다
 func (r *FederatedLearningRepository) Update(fl *models.FederatedLearning) error {
-	return r.db.Save(fl).Error
+	return r.db.Omit("status", "completed_at", "attempt").Save(fl).Error
+}
+
+// TransitionStatus는 현재 상태에서 허용된 경우에만 연합학습 상태를 바꾸고 변경 이력을 기록합니다
+// 허용되지 않으면 models.ErrInvalidFLStatusTransition을 감싼 오류를 반환합니다 (userID가 nil이면 시스템 변경)
+func (r *FederatedLearningRepository) TransitionStatus(id, status, reason string, userID *int64) (*models.FederatedLearningStatusTransition, error) {
+	var transition *models.FederatedLearningStatusTransition
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var fl models.FederatedLearning
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&fl).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("연합학습을 찾을 수 없습니다 (ID: %s)", id)
+			}
+			return err
+		}
+		if err := models.ValidateFLStatusTransition(fl.Status, status); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"status": status}
+		switch status {
+		case models.FLStatusCompleted:
+			updates["completed_at"] = time.Now()
+		case models.FLStatusStarting:
+			updates["completed_at"] = nil
+		}
+		if err := tx.Model(&models.FederatedLearning{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		transition = &models.FederatedLearningStatusTransition{
+			FederatedLearningID: id,
+			FromStatus:          fl.Status,
+			ToStatus:            status,
+			Reason:              reason,
+			UserID:              userID,
+		}
+		return tx.Create(transition).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transition, nil
+}
+
+// GetStatusTransitions는 연합학습의 상태 변경 이력을 시간순으로 조회합니다
+func (r *FederatedLearningRepository) GetStatusTransitions(id string) ([]*models.FederatedLearningStatusTransition, error) {
+	var transitions []*models.FederatedLearningStatusTransition
+	err := r.db.Where("federated_learning_id = ?", id).Order("id ASC").Find(&transitions).Error
+	return transitions, err
 }
 
-// UpdateStatus는 연합학습 상태를 업데이트합니다
-func (r *FederatedLearningRepository) UpdateStatus(id string, status string) error {
-	return r.db.Model(&models.FederatedLearning{}).Where("id = ?", id).Update("status", status).Error
+// IncrementAttempt는 체크포인트 재시작 횟수를 1 늘리고 새 값을 반환합니다
+func (r *FederatedLearningRepository) IncrementAttempt(id string) (int, error) {
+	var fl models.FederatedLearning
+	err := r.db.Model(&fl).Clauses(clause.Returning{Columns: []clause.Column{{Name: "attempt"}}}).
+		Where("id = ?", id).Update("attempt", gorm.Expr("attempt + 1")).Error
+	return fl.Attempt, err
 }
 
 // Delete는 연합학습을 삭제합니다
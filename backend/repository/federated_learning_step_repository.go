@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+)
+
+// FederatedLearningStepRepository는 연합학습 오케스트레이션 단계의 데이터 액세스 계층입니다
+type FederatedLearningStepRepository struct {
+	db *gorm.DB
+}
+
+// NewFederatedLearningStepRepository는 새 FederatedLearningStepRepository 인스턴스를 생성합니다
+func NewFederatedLearningStepRepository(db *gorm.DB) *FederatedLearningStepRepository {
+	return &FederatedLearningStepRepository{db: db}
+}
+
+// CreateSteps는 연합학습의 실행 단계들을 한 번에 생성합니다
+func (r *FederatedLearningStepRepository) CreateSteps(steps []*models.FederatedLearningStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	return r.db.Create(&steps).Error
+}
+
+// GetByFederatedLearningID는 연합학습의 실행 단계를 순서대로 조회합니다
+func (r *FederatedLearningStepRepository) GetByFederatedLearningID(flID string) ([]*models.FederatedLearningStep, error) {
+	var steps []*models.FederatedLearningStep
+	err := r.db.Where("federated_learning_id = ?", flID).Order("sequence ASC").Find(&steps).Error
+	return steps, err
+}
+
+// GetUnfinishedFederatedLearningIDs는 완료되지 않은 단계가 남아있는 연합학습 ID 목록을 조회합니다
+func (r *FederatedLearningStepRepository) GetUnfinishedFederatedLearningIDs() ([]string, error) {
+	var ids []string
+	err := r.db.Model(&models.FederatedLearningStep{}).
+		Where("status IN ?", []string{models.FLStepStatusPending, models.FLStepStatusRunning}).
+		Distinct().
+		Pluck("federated_learning_id", &ids).Error
+	return ids, err
+}
+
+// Update는 실행 단계의 상태를 저장합니다
+func (r *FederatedLearningStepRepository) Update(step *models.FederatedLearningStep) error {
+	return r.db.Save(step).Error
+}
+
+// DeleteByFederatedLearningID는 연합학습의 모든 실행 단계를 삭제합니다
+func (r *FederatedLearningStepRepository) DeleteByFederatedLearningID(flID string) error {
+	return r.db.Where("federated_learning_id = ?", flID).Delete(&models.FederatedLearningStep{}).Error
+}
+
+// FailUnfinished는 대기/실행 중인 단계를 실패로 표시해 서버 재시작 시 재개되지 않도록 합니다
+func (r *FederatedLearningStepRepository) FailUnfinished(flID, reason string) error {
+	return r.db.Model(&models.FederatedLearningStep{}).
+		Where("federated_learning_id = ? AND status IN ?", flID, []string{models.FLStepStatusPending, models.FLStepStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":       models.FLStepStatusFailed,
+			"error":        reason,
+			"completed_at": time.Now(),
+		}).Error
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+)
+
+// LatencySampleRepository는 지연시간 측정 이력의 데이터 액세스 계층입니다
+type LatencySampleRepository struct {
+	db *gorm.DB
+}
+
+// NewLatencySampleRepository는 새 LatencySampleRepository 인스턴스를 생성합니다
+func NewLatencySampleRepository(db *gorm.DB) *LatencySampleRepository {
+	return &LatencySampleRepository{db: db}
+}
+
+// CreateSamples는 측정 표본들을 한 번에 저장합니다
+func (r *LatencySampleRepository) CreateSamples(samples []*models.LatencySample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	return r.db.Create(&samples).Error
+}
+
+// GetSuccessfulSamplesSince는 리전 쌍의 since 이후 성공한 표본을 조회합니다
+func (r *LatencySampleRepository) GetSuccessfulSamplesSince(sourceProviderID, sourceRegionID, targetProviderID, targetRegionID int, since time.Time) ([]*models.LatencySample, error) {
+	var samples []*models.LatencySample
+	err := r.db.Where("source_provider_id = ? AND source_region_id = ? AND target_provider_id = ? AND target_region_id = ?",
+		sourceProviderID, sourceRegionID, targetProviderID, targetRegionID).
+		Where("success = ? AND measured_at >= ?", true, since).
+		Order("measured_at DESC").
+		Find(&samples).Error
+	return samples, err
+}
+
+// LatencySampleFilter는 측정 이력 조회 조건입니다
+type LatencySampleFilter struct {
+	SourceRegion  string
+	TargetRegion  string
+	ParticipantID string
+	Since         *time.Time
+	Limit         int
+}
+
+// GetSamples는 조건에 맞는 측정 이력을 최신순으로 조회합니다
+func (r *LatencySampleRepository) GetSamples(filter LatencySampleFilter) ([]*models.LatencySample, error) {
+	var samples []*models.LatencySample
+	query := r.db.Preload("SourceProvider").Preload("SourceRegion").Preload("TargetProvider").Preload("TargetRegion")
+
+	if filter.SourceRegion != "" {
+		query = query.Where("source_region_id IN (?)", r.db.Model(&models.Region{}).Select("id").Where("name = ?", filter.SourceRegion))
+	}
+	if filter.TargetRegion != "" {
+		query = query.Where("target_region_id IN (?)", r.db.Model(&models.Region{}).Select("id").Where("name = ?", filter.TargetRegion))
+	}
+	if filter.ParticipantID != "" {
+		query = query.Where("participant_id = ?", filter.ParticipantID)
+	}
+	if filter.Since != nil {
+		query = query.Where("measured_at >= ?", *filter.Since)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	err := query.Order("measured_at DESC").Limit(limit).Find(&samples).Error
+	return samples, err
+}
+
+// DeleteBefore는 보존 기간이 지난 측정 이력을 삭제합니다
+func (r *LatencySampleRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("measured_at < ?", before).Delete(&models.LatencySample{})
+	return result.RowsAffected, result.Error
+}
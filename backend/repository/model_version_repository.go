@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+)
+
+// ModelVersionRepository는 글로벌 모델 레지스트리의 데이터 액세스 계층입니다
+type ModelVersionRepository struct {
+	db *gorm.DB
+}
+
+// NewModelVersionRepository는 새 ModelVersionRepository 인스턴스를 생성합니다
+func NewModelVersionRepository(db *gorm.DB) *ModelVersionRepository {
+	return &ModelVersionRepository{db: db}
+}
+
+// Create는 모델 버전을 저장합니다
+func (r *ModelVersionRepository) Create(version *models.ModelVersion) error {
+	return r.db.Create(version).Error
+}
+
+// GetByID는 모델 버전을 조회합니다
+func (r *ModelVersionRepository) GetByID(id string) (*models.ModelVersion, error) {
+	var version models.ModelVersion
+	if err := r.db.Where("id = ?", id).First(&version).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// GetByFederatedLearningID는 연합학습 작업의 모델 버전을 라운드 순으로 조회합니다
+func (r *ModelVersionRepository) GetByFederatedLearningID(flID string) ([]*models.ModelVersion, error) {
+	var versions []*models.ModelVersion
+	err := r.db.Where("federated_learning_id = ?", flID).Order("round ASC").Find(&versions).Error
+	return versions, err
+}
+
+// ModelVersionFilter는 모델 버전 목록 조회 조건입니다
+type ModelVersionFilter struct {
+	OrganizationID      int64
+	FederatedLearningID string
+	Stage               string
+	Tag                 string
+}
+
+// List는 조건에 맞는 조직의 모델 버전을 최신순으로 조회합니다
+func (r *ModelVersionRepository) List(filter ModelVersionFilter) ([]*models.ModelVersion, error) {
+	var versions []*models.ModelVersion
+	query := r.db.Where("organization_id = ?", filter.OrganizationID)
+	if filter.FederatedLearningID != "" {
+		query = query.Where("federated_learning_id = ?", filter.FederatedLearningID)
+	}
+	if filter.Stage != "" {
+		query = query.Where("stage = ?", filter.Stage)
+	}
+	if filter.Tag != "" {
+		// 태그는 JSON 배열 텍스트로 저장되므로 따옴표를 포함해 정확히 일치하는 요소만 찾음
+		tag := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(filter.Tag)
+		query = query.Where("tags LIKE ?", `%"`+tag+`"%`)
+	}
+	err := query.Order("created_at DESC, round DESC").Find(&versions).Error
+	return versions, err
+}
+
+// UpdateTags는 모델 버전의 태그를 교체합니다
+func (r *ModelVersionRepository) UpdateTags(id string, tags []string) error {
+	return r.db.Model(&models.ModelVersion{ID: id}).Select("tags").Updates(&models.ModelVersion{Tags: tags}).Error
+}
+
+// SetStage는 모델 버전의 단계를 변경합니다
+// production으로 승격하면 같은 작업의 기존 production 버전은 archived로 내립니다
+func (r *ModelVersionRepository) SetStage(version *models.ModelVersion, stage string, userID int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if stage == models.ModelStageProduction {
+			err := tx.Model(&models.ModelVersion{}).
+				Where("federated_learning_id = ? AND stage = ? AND id <> ?", version.FederatedLearningID, models.ModelStageProduction, version.ID).
+				Update("stage", models.ModelStageArchived).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		err := tx.Model(&models.ModelVersion{}).Where("id = ?", version.ID).Updates(map[string]interface{}{
+			"stage":               stage,
+			"promoted_at":         now,
+			"promoted_by_user_id": userID,
+		}).Error
+		if err != nil {
+			return err
+		}
+		version.Stage = stage
+		version.PromotedAt = &now
+		version.PromotedByUserID = &userID
+		return nil
+	})
+}
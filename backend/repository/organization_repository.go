@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// CreateOrganization 조직 생성 (생성자를 owner로 등록)
+func (r *OrganizationRepository) CreateOrganization(org *models.Organization, ownerUserID int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createOrganizationWithOwner(tx, org, ownerUserID)
+	})
+}
+
+func createOrganizationWithOwner(tx *gorm.DB, org *models.Organization, ownerUserID int64) error {
+	org.CreatedByUserID = ownerUserID
+	if err := tx.Create(org).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         ownerUserID,
+		Role:           models.OrganizationRoleOwner,
+	}).Error
+}
+
+// GetOrganizationByID ID로 조직 조회
+func (r *OrganizationRepository) GetOrganizationByID(id int64) (*models.Organization, error) {
+	var org models.Organization
+	err := r.db.First(&org, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+// GetPersonalOrganization 사용자의 개인 조직 조회
+func (r *OrganizationRepository) GetPersonalOrganization(userID int64) (*models.Organization, error) {
+	var org models.Organization
+	err := r.db.Where("personal = ? AND created_by_user_id = ?", true, userID).First(&org).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+// EnsurePersonalOrganization 사용자의 개인 조직이 없으면 생성
+func (r *OrganizationRepository) EnsurePersonalOrganization(user *models.User) (*models.Organization, error) {
+	org, err := r.GetPersonalOrganization(user.ID)
+	if err != nil || org != nil {
+		return org, err
+	}
+
+	org = &models.Organization{
+		Name:     personalOrganizationName(user),
+		Personal: true,
+	}
+	if err := r.CreateOrganization(org, user.ID); err != nil {
+		// 동시 요청으로 이미 생성된 경우 다시 조회
+		if existing, getErr := r.GetPersonalOrganization(user.ID); getErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return org, nil
+}
+
+func personalOrganizationName(user *models.User) string {
+	if user.Name != "" {
+		return fmt.Sprintf("%s (개인)", user.Name)
+	}
+	return fmt.Sprintf("%s (개인)", user.Email)
+}
+
+// ListOrganizationsByUserID 사용자가 속한 조직 목록과 역할 조회
+func (r *OrganizationRepository) ListOrganizationsByUserID(userID int64) ([]*models.OrganizationWithRole, error) {
+	var members []*models.OrganizationMember
+	if err := r.db.Where("user_id = ?", userID).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return []*models.OrganizationWithRole{}, nil
+	}
+
+	roles := make(map[int64]string, len(members))
+	ids := make([]int64, 0, len(members))
+	for _, m := range members {
+		roles[m.OrganizationID] = m.Role
+		ids = append(ids, m.OrganizationID)
+	}
+
+	var orgs []models.Organization
+	if err := r.db.Where("id IN ?", ids).Order("personal DESC, name ASC").Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.OrganizationWithRole, 0, len(orgs))
+	for _, org := range orgs {
+		result = append(result, &models.OrganizationWithRole{Organization: org, Role: roles[org.ID]})
+	}
+	return result, nil
+}
+
+// UpdateOrganization 조직 정보 업데이트
+func (r *OrganizationRepository) UpdateOrganization(org *models.Organization) error {
+	return r.db.Save(org).Error
+}
+
+// DeleteOrganization 조직 삭제 (멤버십은 함께 삭제)
+func (r *OrganizationRepository) DeleteOrganization(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Organization{}, id).Error
+	})
+}
+
+// CountOrganizationResources 조직이 소유한 리소스 수 조회
+func (r *OrganizationRepository) CountOrganizationResources(id int64) (int64, error) {
+	var total int64
+	for _, model := range []interface{}{
+		&models.CloudConnection{},
+		&models.Participant{},
+		&models.Aggregator{},
+		&models.FederatedLearning{},
+	} {
+		var count int64
+		if err := r.db.Model(model).Where("organization_id = ?", id).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// ListMembershipsByUserID 사용자의 모든 조직 멤버십 조회
+func (r *OrganizationRepository) ListMembershipsByUserID(userID int64) ([]*models.OrganizationMember, error) {
+	var members []*models.OrganizationMember
+	err := r.db.Where("user_id = ?", userID).Find(&members).Error
+	return members, err
+}
+
+// GetMember 조직의 특정 멤버 조회
+func (r *OrganizationRepository) GetMember(orgID, userID int64) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := r.db.Where("organization_id = ? AND user_id = ?", orgID, userID).First(&member).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// ListMembers 조직의 멤버 목록 조회 (사용자 정보 포함)
+func (r *OrganizationRepository) ListMembers(orgID int64) ([]*models.OrganizationMember, error) {
+	var members []*models.OrganizationMember
+	err := r.db.Preload("User").
+		Where("organization_id = ?", orgID).
+		Order("created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+// AddMember 조직에 멤버 추가
+func (r *OrganizationRepository) AddMember(member *models.OrganizationMember) error {
+	return r.db.Create(member).Error
+}
+
+// UpdateMemberRole 멤버 역할 변경
+func (r *OrganizationRepository) UpdateMemberRole(orgID, userID int64, role string) error {
+	return r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Update("role", role).Error
+}
+
+// RemoveMember 조직에서 멤버 제거
+func (r *OrganizationRepository) RemoveMember(orgID, userID int64) error {
+	return r.db.Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&models.OrganizationMember{}).Error
+}
+
+// CountOwners 조직의 owner 수 조회
+func (r *OrganizationRepository) CountOwners(orgID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, models.OrganizationRoleOwner).
+		Count(&count).Error
+	return count, err
+}
+
+// BackfillPersonalOrganizations 개인 조직이 없는 사용자에게 개인 조직을 만들고
+// 조직이 지정되지 않은(organization_id = 0) 리소스를 생성자의 개인 조직으로 옮깁니다
+func (r *OrganizationRepository) BackfillPersonalOrganizations() (int, error) {
+	var users []*models.User
+	err := r.db.Where("id NOT IN (?)",
+		r.db.Model(&models.Organization{}).Select("created_by_user_id").Where("personal = ?", true),
+	).Find(&users).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, user := range users {
+		if _, err := r.EnsurePersonalOrganization(user); err != nil {
+			return 0, fmt.Errorf("사용자 %d 개인 조직 생성 실패: %v", user.ID, err)
+		}
+	}
+
+	for _, table := range []string{"cloud_connections", "participants", "aggregators", "federated_learnings"} {
+		err := r.db.Exec(fmt.Sprintf(`
+			UPDATE %s t
+			SET organization_id = o.id
+			FROM organizations o
+			WHERE t.organization_id = 0
+			AND o.personal = TRUE
+			AND o.created_by_user_id = t.user_id`, table)).Error
+		if err != nil {
+			return 0, fmt.Errorf("%s 조직 지정 실패: %v", table, err)
+		}
+	}
+
+	return len(users), nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+	"github.com/Mungge/Fleecy-Cloud/utils/secrets"
+)
+
+// 상태 알림 반영 실패 사유
+var (
+	ErrAgentJobNotFound = errors.New("participant is not part of the federated learning job")
+	ErrAgentJobMismatch = errors.New("job id does not match the latest dispatched job")
+)
+
+// agentStateStatuses는 에이전트 작업 상태를 ParticipantFederatedLearning.Status 값으로 대응시킵니다
+var agentStateStatuses = map[string]string{
+	participantagent.StateAccepted:   "accepted",
+	participantagent.StateInstalling: "installing",
+	participantagent.StateTraining:   "training",
+	participantagent.StateFinished:   "completed",
+	participantagent.StateFailed:     "failed",
+	participantagent.StateStopped:    "stopped",
+}
+
+// ParticipantAgentRepository는 참여자 에이전트 인증 정보와 작업 상태의 데이터 액세스 계층입니다
+type ParticipantAgentRepository struct {
+	db *gorm.DB
+}
+
+// NewParticipantAgentRepository는 새 ParticipantAgentRepository 인스턴스를 생성합니다
+func NewParticipantAgentRepository(db *gorm.DB) *ParticipantAgentRepository {
+	return &ParticipantAgentRepository{db: db}
+}
+
+// IssueCredential은 참여자의 새 비밀 키를 발급해 암호화 저장하고 평문을 반환합니다 (기존 키는 즉시 무효)
+func (r *ParticipantAgentRepository) IssueCredential(participantID string) (string, error) {
+	secret, err := participantagent.NewSecret()
+	if err != nil {
+		return "", err
+	}
+	sealed, keyID, err := secrets.Seal([]byte(secret))
+	if err != nil {
+		return "", err
+	}
+
+	credential := &models.ParticipantAgentCredential{
+		ParticipantID: participantID,
+		Secret:        sealed,
+		KeyID:         keyID,
+	}
+	err = r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "participant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "key_id", "updated_at"}),
+	}).Create(credential).Error
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// GetSecret은 참여자의 비밀 키 평문을 반환합니다 (발급되지 않았으면 빈 문자열)
+func (r *ParticipantAgentRepository) GetSecret(participantID string) (string, error) {
+	var credential models.ParticipantAgentCredential
+	if err := r.db.Where("participant_id = ?", participantID).First(&credential).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	plain, err := secrets.Open(credential.Secret, credential.KeyID)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// HasCredential은 참여자에게 비밀 키가 발급되었는지 확인합니다
+func (r *ParticipantAgentRepository) HasCredential(participantID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ParticipantAgentCredential{}).Where("participant_id = ?", participantID).Count(&count).Error
+	return count > 0, err
+}
+
+// RecordDispatch는 참여자에게 보낸 실행 요청의 작업 ID를 저장하고 알림 순번을 초기화합니다
+// 이후에는 이 작업 ID의 상태 알림만 반영됩니다
+// 같은 작업 ID가 이미 기록되어 있으면 (재개된 단계의 재전송) 받은 알림을 지우지 않고 기존 기록을 반환합니다
+func (r *ParticipantAgentRepository) RecordDispatch(flID, participantID, jobID string) (*models.ParticipantFederatedLearning, error) {
+	var pfl models.ParticipantFederatedLearning
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("federated_learning_id = ? AND participant_id = ?", flID, participantID).
+			First(&pfl).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrAgentJobNotFound
+			}
+			return err
+		}
+		if pfl.JobID == jobID {
+			return nil
+		}
+
+		now := time.Now()
+		return tx.Model(&models.ParticipantFederatedLearning{}).
+			Where("federated_learning_id = ? AND participant_id = ?", flID, participantID).
+			Updates(map[string]interface{}{
+				"job_id":            jobID,
+				"status":            agentStateStatuses[participantagent.StateAccepted],
+				"current_round":     0,
+				"last_sequence":     0,
+				"last_message":      "",
+				"status_updated_at": now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pfl, nil
+}
+
+// ApplyStatusCallback은 에이전트 상태 알림을 참여자 작업 상태에 반영합니다
+// 이미 반영한 순번 이하의 알림(재전송/역순 도착)은 무시하고 false를 반환합니다
+func (r *ParticipantAgentRepository) ApplyStatusCallback(cb *participantagent.StatusCallback) (*models.ParticipantFederatedLearning, bool, error) {
+	var pfl models.ParticipantFederatedLearning
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("federated_learning_id = ? AND participant_id = ?", cb.FederatedLearningID, cb.ParticipantID).
+			First(&pfl).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrAgentJobNotFound
+			}
+			return err
+		}
+		if pfl.JobID != cb.JobID {
+			return ErrAgentJobMismatch
+		}
+		if cb.Sequence <= pfl.LastSequence {
+			return nil
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"status":            agentStateStatuses[cb.State],
+			"last_sequence":     cb.Sequence,
+			"last_message":      cb.Message,
+			"status_updated_at": now,
+		}
+
+		// training은 진행 중인 라운드이므로 직전 라운드까지 완료, finished는 마지막 라운드까지 완료
+		completed := pfl.TasksCompleted
+		switch cb.State {
+		case participantagent.StateTraining:
+			if cb.Round > 0 {
+				updates["current_round"] = cb.Round
+				completed = cb.Round - 1
+			}
+		case participantagent.StateFinished:
+			if cb.Round > 0 {
+				updates["current_round"] = cb.Round
+				completed = cb.Round
+			}
+		}
+		if completed > pfl.TasksCompleted {
+			updates["tasks_completed"] = completed
+			updates["last_task_completed_at"] = now
+		}
+
+		if err := tx.Model(&models.ParticipantFederatedLearning{}).
+			Where("federated_learning_id = ? AND participant_id = ?", cb.FederatedLearningID, cb.ParticipantID).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+		applied = true
+		return tx.Where("federated_learning_id = ? AND participant_id = ?", cb.FederatedLearningID, cb.ParticipantID).
+			First(&pfl).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &pfl, applied, nil
+}
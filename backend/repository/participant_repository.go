@@ -25,10 +25,17 @@ func (r *ParticipantRepository) Create(participant *models.Participant) error {
 	return r.db.Create(participant).Error
 }
 
-// GetByUserID는 사용자 ID로 참여자 목록을 조회합니다
-func (r *ParticipantRepository) GetByUserID(userID int64) ([]*models.Participant, error) {
+// GetByOrganizationID는 조직 ID로 참여자 목록을 조회합니다
+func (r *ParticipantRepository) GetByOrganizationID(orgID int64) ([]*models.Participant, error) {
 	var participants []*models.Participant
-	err := r.db.Where("user_id = ?", userID).Find(&participants).Error
+	err := r.db.Where("organization_id = ?", orgID).Find(&participants).Error
+	return participants, err
+}
+
+// GetAllWithEndpoint는 OpenStack 엔드포인트가 설정된 모든 참여자를 조회합니다
+func (r *ParticipantRepository) GetAllWithEndpoint() ([]*models.Participant, error) {
+	var participants []*models.Participant
+	err := r.db.Where("open_stack_endpoint <> ''").Order("created_at ASC").Find(&participants).Error
 	return participants, err
 }
 
@@ -61,13 +68,13 @@ func (r *ParticipantRepository) Delete(id string) error {
 }
 
 // GetByStatus는 상태로 참여자 목록을 조회합니다
-func (r *ParticipantRepository) GetByStatus(userID int64, status string) ([]*models.Participant, error) {
+func (r *ParticipantRepository) GetByStatus(orgID int64, status string) ([]*models.Participant, error) {
 	var participants []*models.Participant
-	err := r.db.Where("user_id = ? AND status = ?", userID, status).Find(&participants).Error
+	err := r.db.Where("organization_id = ? AND status = ?", orgID, status).Find(&participants).Error
 	return participants, err
 }
 
 // GetAvailable는 사용 가능한 참여자 목록을 조회합니다 (active 상태)
-func (r *ParticipantRepository) GetAvailable(userID int64) ([]*models.Participant, error) {
-	return r.GetByStatus(userID, "active")
+func (r *ParticipantRepository) GetAvailable(orgID int64) ([]*models.Participant, error) {
+	return r.GetByStatus(orgID, "active")
 }
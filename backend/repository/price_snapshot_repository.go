@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+)
+
+// PriceSnapshotRepository는 가격 스냅샷 데이터 액세스 계층입니다
+type PriceSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewPriceSnapshotRepository는 새 PriceSnapshotRepository 인스턴스를 생성합니다
+func NewPriceSnapshotRepository(db *gorm.DB) *PriceSnapshotRepository {
+	return &PriceSnapshotRepository{db: db}
+}
+
+// CreateSnapshot은 다음 버전 번호를 할당해 스냅샷과 항목들을 한 트랜잭션으로 저장합니다
+func (r *PriceSnapshotRepository) CreateSnapshot(snapshot *models.PriceSnapshot, items []*models.PriceSnapshotItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var maxVersion int
+		if err := tx.Model(&models.PriceSnapshot{}).Select("COALESCE(MAX(version), 0)").Scan(&maxVersion).Error; err != nil {
+			return err
+		}
+		snapshot.Version = maxVersion + 1
+		snapshot.ItemCount = len(items)
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			item.SnapshotID = snapshot.ID
+		}
+		if len(items) > 0 {
+			if err := tx.CreateInBatches(items, 1000).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetSnapshots는 스냅샷 목록을 최신 버전순으로 조회합니다
+func (r *PriceSnapshotRepository) GetSnapshots() ([]*models.PriceSnapshot, error) {
+	var snapshots []*models.PriceSnapshot
+	err := r.db.Order("version DESC").Find(&snapshots).Error
+	return snapshots, err
+}
+
+// GetByVersion은 버전 번호로 스냅샷을 조회합니다
+func (r *PriceSnapshotRepository) GetByVersion(version int) (*models.PriceSnapshot, error) {
+	var snapshot models.PriceSnapshot
+	if err := r.db.Where("version = ?", version).First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetEffectiveAt은 at 시점에 유효한 스냅샷 (effective_at <= at 중 가장 최근 버전)을 조회합니다
+func (r *PriceSnapshotRepository) GetEffectiveAt(at time.Time) (*models.PriceSnapshot, error) {
+	var snapshot models.PriceSnapshot
+	err := r.db.Where("effective_at <= ?", at).
+		Order("effective_at DESC").Order("version DESC").
+		First(&snapshot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetPreviousVersion은 주어진 버전 직전의 스냅샷을 조회합니다
+func (r *PriceSnapshotRepository) GetPreviousVersion(version int) (*models.PriceSnapshot, error) {
+	var snapshot models.PriceSnapshot
+	err := r.db.Where("version < ?", version).Order("version DESC").First(&snapshot).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetItems는 스냅샷에 포함된 가격 항목을 조회합니다
+func (r *PriceSnapshotRepository) GetItems(snapshotID int) ([]*models.PriceSnapshotItem, error) {
+	var items []*models.PriceSnapshotItem
+	err := r.db.Preload("Provider").Preload("Region").
+		Where("snapshot_id = ?", snapshotID).
+		Order("provider_id, region_id, instance_type").
+		Find(&items).Error
+	return items, err
+}
+
+// Count는 저장된 스냅샷 수를 반환합니다
+func (r *PriceSnapshotRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PriceSnapshot{}).Count(&count).Error
+	return count, err
+}
+
+// GetLatest는 가장 최근 버전의 스냅샷을 조회합니다
+func (r *PriceSnapshotRepository) GetLatest() (*models.PriceSnapshot, error) {
+	var snapshot models.PriceSnapshot
+	if err := r.db.Order("version DESC").First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetOldest는 가장 오래된 버전의 스냅샷을 조회합니다
+func (r *PriceSnapshotRepository) GetOldest() (*models.PriceSnapshot, error) {
+	var snapshot models.PriceSnapshot
+	if err := r.db.Order("version ASC").First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"gorm.io/gorm"
 )
@@ -48,15 +50,40 @@ func (r *SSHKeypairRepository) DeleteKeypairByAggregatorID(aggregatorID string)
 	return r.db.Where("aggregator_id = ?", aggregatorID).Delete(&models.SSHKeypair{}).Error
 }
 
-// ListKeypairsByUserID 사용자의 모든 SSH 키페어 목록 조회 (집계자를 통해)
-func (r *SSHKeypairRepository) ListKeypairsByUserID(userID int64) ([]*models.SSHKeypair, error) {
+// ListKeypairsByOrganizationID 조직의 모든 SSH 키페어 목록 조회 (집계자를 통해)
+func (r *SSHKeypairRepository) ListKeypairsByOrganizationID(orgID int64) ([]*models.SSHKeypair, error) {
 	var keypairs []*models.SSHKeypair
 
-	// 집계자 테이블과 조인하여 사용자의 키페어만 조회
+	// 집계자 테이블과 조인하여 조직의 키페어만 조회
 	err := r.db.Table("ssh_keypairs").
+		Select("ssh_keypairs.*").
 		Joins("JOIN aggregators ON ssh_keypairs.aggregator_id = aggregators.id").
-		Where("aggregators.user_id = ?", userID).
+		Where("aggregators.organization_id = ?", orgID).
 		Find(&keypairs).Error
 
 	return keypairs, err
 }
+
+// PinHostKeyIfUnset은 고정된 호스트 키가 없을 때만 호스트 키를 저장합니다 (TOFU)
+// 동시에 여러 연결이 첫 접속을 시도해도 하나의 키만 고정되며, 고정했으면 true를 반환합니다
+func (r *SSHKeypairRepository) PinHostKeyIfUnset(aggregatorID, hostKey, fingerprint string, pinnedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.SSHKeypair{}).
+		Where("aggregator_id = ? AND (host_key IS NULL OR host_key = '')", aggregatorID).
+		UpdateColumns(map[string]interface{}{
+			"host_key":             hostKey,
+			"host_key_fingerprint": fingerprint,
+			"host_key_pinned_at":   pinnedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// SetHostKey는 호스트 키를 덮어씁니다 (재고정, hostKey가 비어있으면 고정 해제)
+func (r *SSHKeypairRepository) SetHostKey(aggregatorID, hostKey, fingerprint string, pinnedAt *time.Time) error {
+	return r.db.Model(&models.SSHKeypair{}).
+		Where("aggregator_id = ?", aggregatorID).
+		UpdateColumns(map[string]interface{}{
+			"host_key":             hostKey,
+			"host_key_fingerprint": fingerprint,
+			"host_key_pinned_at":   pinnedAt,
+		}).Error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// taskBundleListColumns는 목록 조회 시 파일 내용을 제외하고 읽는 컬럼입니다
+var taskBundleListColumns = []string{
+	"id", "organization_id", "created_by_user_id", "name", "version", "description",
+	"hyperparameters", "dependencies", "content_hash", "size_bytes", "created_at",
+}
+
+// TaskBundleRepository는 사용자 모델/학습 번들의 데이터 액세스 계층입니다
+type TaskBundleRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskBundleRepository는 새 TaskBundleRepository 인스턴스를 생성합니다
+func NewTaskBundleRepository(db *gorm.DB) *TaskBundleRepository {
+	return &TaskBundleRepository{db: db}
+}
+
+// CreateNextVersion은 같은 조직/이름의 마지막 버전 다음 번호로 번들을 저장합니다
+// 마지막 버전과 내용 해시가 같으면 새 버전을 만들지 않고 기존 버전을 반환합니다 (created=false)
+func (r *TaskBundleRepository) CreateNextVersion(bundle *models.TaskBundle) (*models.TaskBundle, bool, error) {
+	var result *models.TaskBundle
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 마지막 버전 행을 잠가 같은 이름의 동시 업로드를 직렬화
+		// (첫 버전끼리의 경합은 (organization_id, name, version) 고유 인덱스가 막음)
+		var latest models.TaskBundle
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("organization_id = ? AND name = ?", bundle.OrganizationID, bundle.Name).
+			Order("version DESC").First(&latest).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err == nil && latest.ContentHash == bundle.ContentHash {
+			result = &latest
+			return nil
+		}
+
+		bundle.Version = latest.Version + 1
+		if err := tx.Create(bundle).Error; err != nil {
+			return err
+		}
+		result = bundle
+		created = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return result, created, nil
+}
+
+// GetByID는 번들 버전을 파일 내용과 함께 조회합니다
+func (r *TaskBundleRepository) GetByID(id string) (*models.TaskBundle, error) {
+	var bundle models.TaskBundle
+	if err := r.db.Where("id = ?", id).First(&bundle).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// ListByOrganizationID는 조직의 번들 버전 목록을 이름, 최신 버전 순으로 조회합니다 (파일 내용 제외)
+// name이 비어 있지 않으면 해당 이름의 버전만 조회합니다
+func (r *TaskBundleRepository) ListByOrganizationID(orgID int64, name string) ([]*models.TaskBundle, error) {
+	var bundles []*models.TaskBundle
+	query := r.db.Select(taskBundleListColumns).Where("organization_id = ?", orgID)
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+	err := query.Order("name ASC, version DESC").Find(&bundles).Error
+	return bundles, err
+}
+
+// IsReferenced는 번들 버전을 사용하는 연합학습 작업이 있는지 확인합니다
+func (r *TaskBundleRepository) IsReferenced(id string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.FederatedLearning{}).Where("task_bundle_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// Delete는 번들 버전을 삭제합니다
+func (r *TaskBundleRepository) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&models.TaskBundle{}).Error
+}
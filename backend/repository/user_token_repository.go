@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+// CreateToken 일회용 토큰 저장 (같은 용도의 기존 미사용 토큰은 무효화)
+func (r *UserTokenRepository) CreateToken(userID int64, purpose, tokenHash string, ttl time.Duration) (*models.UserToken, error) {
+	now := time.Now()
+	token := &models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// ConsumeToken 유효한 토큰을 사용 처리하고 반환 (없거나 만료/사용된 경우 nil)
+// 행 잠금 후 사용 처리하므로 같은 토큰으로 동시에 요청해도 한 번만 성공합니다
+func (r *UserTokenRepository) ConsumeToken(purpose, tokenHash string) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, now).
+			First(&token).Error; err != nil {
+			return err
+		}
+		token.UsedAt = &now
+		return tx.Model(&token).Update("used_at", now).Error
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// InvalidateUserTokens 사용자의 특정 용도 미사용 토큰 모두 무효화
+func (r *UserTokenRepository) InvalidateUserTokens(userID int64, purpose string) error {
+	return r.db.Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
+
+// CleanupExpiredTokens 만료된 토큰들 정리
+func (r *UserTokenRepository) CleanupExpiredTokens() error {
+	return r.db.Where("expires_at < ?", time.Now()).
+		Delete(&models.UserToken{}).Error
+}
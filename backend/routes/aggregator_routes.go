@@ -2,14 +2,16 @@ package routes
 
 import (
 	"github.com/Mungge/Fleecy-Cloud/handlers/aggregator"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupAggregatorRoutes(authorized *gin.RouterGroup, aggregatorHandler *aggregator.AggregatorHandler, mlflowHandler *aggregator.MLflowHandler) {
+func SetupAggregatorRoutes(authorized *gin.RouterGroup, aggregatorHandler *aggregator.AggregatorHandler, mlflowHandler *aggregator.MLflowHandler, optimizationRateLimit gin.HandlerFunc) {
 	aggregators := authorized.Group("/aggregators")
+	aggregators.Use(middlewares.RequireScope("aggregators"))
 	{
 		// Aggregator 배치 최적화
-		aggregators.POST("/optimization", aggregatorHandler.OptimizeAggregatorPlacement)
+		aggregators.POST("/optimization", optimizationRateLimit, aggregatorHandler.OptimizeAggregatorPlacement)
 
 		// Aggregator 통계 조회
 		aggregators.GET("/stats", aggregatorHandler.GetAggregatorStats)
@@ -23,6 +25,12 @@ func SetupAggregatorRoutes(authorized *gin.RouterGroup, aggregatorHandler *aggre
 		// 특정 Aggregator 조회
 		aggregators.GET("/:id", aggregatorHandler.GetAggregator)
 
+		// Aggregator 배포 진행 상황 조회
+		aggregators.GET("/:id/progress", aggregatorHandler.GetAggregatorProgress)
+
+		// Aggregator 배포 진행 상황 스트림 (SSE)
+		aggregators.GET("/:id/progress/stream", aggregatorHandler.WebSocketProgress)
+
 		// Aggregator 상태 업데이트
 		aggregators.PUT("/:id/status", aggregatorHandler.UpdateAggregatorStatus)
 
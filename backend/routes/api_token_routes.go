@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAPITokenRoutes(authorized *gin.RouterGroup, apiTokenHandler *handlers.APITokenHandler) {
+	// 토큰 관리는 로그인 세션으로만 가능 (토큰으로 토큰을 발급할 수 없도록)
+	tokens := authorized.Group("/tokens")
+	tokens.Use(middlewares.SessionOnly())
+	{
+		tokens.GET("", apiTokenHandler.GetAPITokens)
+		tokens.GET("/scopes", apiTokenHandler.GetAPITokenScopes)
+		tokens.POST("", apiTokenHandler.CreateAPIToken)
+		tokens.DELETE("/:id", apiTokenHandler.RevokeAPIToken)
+	}
+}
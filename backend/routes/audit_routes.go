@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAuditRoutes(authorized *gin.RouterGroup, auditHandler *handlers.AuditHandler) {
+	auditRoutes := authorized.Group("/audit")
+	auditRoutes.Use(middlewares.RequireScope("audit"))
+	{
+		// 활성 조직 감사 로그 조회 (필터: actor_user_id, action, resource_type, resource_id, outcome, request_id, from, to)
+		auditRoutes.GET("", auditHandler.GetAuditLogs)
+
+		// 본인이 수행한 작업 감사 로그 조회
+		auditRoutes.GET("/me", auditHandler.GetMyAuditLogs)
+
+		// 활성 조직 감사 로그 JSON Lines 내보내기
+		auditRoutes.GET("/export", auditHandler.ExportAuditLogs)
+	}
+}
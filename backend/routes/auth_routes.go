@@ -5,14 +5,22 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupAuthRoutes(r *gin.Engine, authHandler *authHandlers.AuthHandler) {
+func SetupAuthRoutes(r *gin.Engine, authHandler *authHandlers.AuthHandler, authRateLimit gin.HandlerFunc) {
 	auth := r.Group("/api/auth")
 	{
-		auth.POST("/register", authHandler.RegisterHandler)
-		auth.POST("/login", authHandler.LoginHandler)
+		auth.POST("/register", authRateLimit, authHandler.RegisterHandler)
+		auth.POST("/login", authRateLimit, authHandler.LoginHandler)
 		auth.POST("/logout", authHandler.LogoutHandler)
 		auth.POST("/refresh", authHandler.RefreshTokenHandler)
+		auth.GET("/jwks.json", authHandler.JWKSHandler)
+		auth.POST("/password/reset", authRateLimit, authHandler.ResetPasswordHandler)
+		auth.POST("/password/reset/confirm", authRateLimit, authHandler.ConfirmPasswordResetHandler)
+		auth.POST("/verify-email", authRateLimit, authHandler.VerifyEmailHandler)
+		auth.POST("/verify-email/resend", authRateLimit, authHandler.ResendVerificationHandler)
 		auth.GET("/github", authHandler.GitHubLoginHandler)
 		auth.GET("/github/callback", authHandler.GitHubCallbackHandler)
+		auth.GET("/oidc/providers", authHandler.OIDCProvidersHandler)
+		auth.GET("/oidc/:provider/login", authHandler.OIDCLoginHandler)
+		auth.GET("/oidc/:provider/callback", authHandler.OIDCCallbackHandler)
 	}
 }
@@ -2,11 +2,13 @@ package routes
 
 import (
 	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupCloudRoutes(authorized *gin.RouterGroup, cloudHandler *handlers.CloudHandler) {
 	clouds := authorized.Group("/clouds")
+	clouds.Use(middlewares.RequireScope("clouds"))
 	{
 		clouds.GET("", cloudHandler.GetClouds)
 		clouds.POST("", cloudHandler.AddCloud)
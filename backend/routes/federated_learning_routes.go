@@ -2,11 +2,13 @@ package routes
 
 import (
 	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupFederatedLearningRoutes(authorized *gin.RouterGroup, federatedLearningHandler *handlers.FederatedLearningHandler) {
 	federated := authorized.Group("/federated-learning")
+	federated.Use(middlewares.RequireScope("fl"))
 	{
 		// 연합학습 생성 (특정 Aggregator에)
 		federated.POST("", federatedLearningHandler.CreateFederatedLearning)
@@ -17,12 +19,18 @@ func SetupFederatedLearningRoutes(authorized *gin.RouterGroup, federatedLearning
 		// 특정 연합학습 작업 조회
 		federated.GET("/:id", federatedLearningHandler.GetFederatedLearning)
 
+		// 특정 연합학습 작업의 실행 단계 조회
+		federated.GET("/:id/steps", federatedLearningHandler.GetFederatedLearningSteps)
+
 		// 특정 연합학습 작업의 로그 조회
 		federated.GET("/:id/logs", federatedLearningHandler.GetFederatedLearningLogs)
 
 		// 특정 연합학습 작업의 로그 스트리밍
 		federated.GET("/:id/logs/stream", federatedLearningHandler.StreamFederatedLearningLogs)
 
+		// 참여자 에이전트의 로컬 학습 로그 (?attempt=N&offset=바이트&follow=true)
+		federated.GET("/:id/participants/:participantId/logs", federatedLearningHandler.GetParticipantLogs)
+
 		// 특정 연합학습 작업의 MLflow 대시보드 URL 조회
 		federated.GET("/:id/mlflow", federatedLearningHandler.GetMLflowDashboardURL)
 
@@ -38,6 +46,15 @@ func SetupFederatedLearningRoutes(authorized *gin.RouterGroup, federatedLearning
 		// 특정 연합학습 작업의 저장된 학습 히스토리 조회 (DB에서)
 		federated.GET("/:id/training-history", federatedLearningHandler.GetStoredTrainingHistory)
 
+		// 특정 연합학습 작업의 상태 변경 이력 조회
+		federated.GET("/:id/transitions", federatedLearningHandler.GetFederatedLearningTransitions)
+
+		// 학습 중지 / 일시정지 / 재개 / 체크포인트 재시작 (?fromRound=N)
+		federated.POST("/:id/stop", federatedLearningHandler.StopFederatedLearning)
+		federated.POST("/:id/pause", federatedLearningHandler.PauseFederatedLearning)
+		federated.POST("/:id/resume", federatedLearningHandler.ResumeFederatedLearning)
+		federated.POST("/:id/restart", federatedLearningHandler.RestartFederatedLearning)
+
 		// 특정 연합학습 작업 업데이트
 		federated.PUT("/:id", federatedLearningHandler.UpdateFederatedLearning)
 
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupLatencyRoutes는 지연시간 측정 라우트를 등록합니다
+// 측정은 모든 조직의 참여자 엔드포인트를 대상으로 하고 이력에 참여자 ID가 포함되므로 관리자 전용입니다
+func SetupLatencyRoutes(authorized *gin.RouterGroup, latencyHandler *handlers.LatencyHandler, adminOnly gin.HandlerFunc) {
+	latency := authorized.Group("/latency")
+	latency.Use(middlewares.RequireScope("latency"), adminOnly)
+	{
+		// 지연시간 즉시 측정
+		latency.POST("/probe", latencyHandler.TriggerLatencyProbe)
+
+		// 지연시간 측정 이력 조회
+		latency.GET("/samples", latencyHandler.GetLatencySamples)
+	}
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupModelRegistryRoutes(authorized *gin.RouterGroup, modelRegistryHandler *handlers.ModelRegistryHandler) {
+	modelRoutes := authorized.Group("/models")
+	modelRoutes.Use(middlewares.RequireScope("fl"))
+	{
+		// 모델 버전 목록 조회 (필터: federated_learning_id, stage, tag)
+		modelRoutes.GET("", modelRegistryHandler.GetModelVersions)
+
+		// 연합학습 작업의 새 체크포인트 즉시 수집
+		modelRoutes.POST("/collect", modelRegistryHandler.CollectModelVersions)
+
+		// 모델 버전 조회
+		modelRoutes.GET("/:id", modelRegistryHandler.GetModelVersion)
+
+		// 모델 버전 계보 (이전 라운드, 학습 코드 번들)
+		modelRoutes.GET("/:id/lineage", modelRegistryHandler.GetModelVersionLineage)
+
+		// 모델 파일 다운로드 (집계자 실행 여부와 무관)
+		modelRoutes.GET("/:id/download", modelRegistryHandler.DownloadModelVersion)
+
+		// 모델 버전 태그 교체
+		modelRoutes.PUT("/:id/tags", modelRegistryHandler.UpdateModelVersionTags)
+
+		// 모델 버전 단계 변경 (staging/production 승격)
+		modelRoutes.POST("/:id/promote", modelRegistryHandler.PromoteModelVersion)
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupOrganizationRoutes(authorized *gin.RouterGroup, organizationHandler *handlers.OrganizationHandler) {
+	organizations := authorized.Group("/organizations")
+	organizations.Use(middlewares.RequireScope("organizations"))
+	{
+		// 조직 CRUD 라우트
+		organizations.GET("", organizationHandler.GetOrganizations)
+		organizations.POST("", organizationHandler.CreateOrganization)
+		organizations.GET("/:orgId", organizationHandler.GetOrganization)
+		organizations.PUT("/:orgId", organizationHandler.UpdateOrganization)
+		organizations.DELETE("/:orgId", organizationHandler.DeleteOrganization)
+
+		// 멤버 관리 라우트
+		organizations.GET("/:orgId/members", organizationHandler.GetMembers)
+		organizations.POST("/:orgId/members", organizationHandler.AddMember)
+		organizations.PUT("/:orgId/members/:userId", organizationHandler.UpdateMemberRole)
+		organizations.DELETE("/:orgId/members/:userId", organizationHandler.RemoveMember)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupParticipantAgentRoutes는 참여자 에이전트가 호출하는 라우트를 설정합니다
+// 사용자 인증 미들웨어 없이 참여자별 서명 토큰으로 핸들러에서 인증합니다
+func SetupParticipantAgentRoutes(r *gin.Engine, agentHandler *handlers.ParticipantAgentHandler) {
+	agent := r.Group("/api/participant-agent/v1")
+	{
+		agent.GET("/schema", agentHandler.GetProtocolSchema)
+		agent.POST("/participants/:participantId/status", agentHandler.ReceiveStatusCallback)
+	}
+}
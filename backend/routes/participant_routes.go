@@ -2,11 +2,13 @@ package routes
 
 import (
 	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupParticipantRoutes(authorized *gin.RouterGroup, participantHandler *handlers.ParticipantHandler) {
 	participants := authorized.Group("/participants")
+	participants.Use(middlewares.RequireScope("participants"))
 	{
 		// 기본 CRUD 라우트
 		participants.GET("", participantHandler.GetParticipants)
@@ -18,5 +20,9 @@ func SetupParticipantRoutes(authorized *gin.RouterGroup, participantHandler *han
 		
 		// 헬스체크 라우트
 		participants.POST("/:id/health-check", participantHandler.HealthCheckParticipant)
+
+		// 참여자 에이전트 인증 정보 (비밀 키 재발급)
+		participants.GET("/:id/agent-credentials", participantHandler.GetAgentCredentialStatus)
+		participants.POST("/:id/agent-credentials", participantHandler.IssueAgentCredential)
 	}
 }
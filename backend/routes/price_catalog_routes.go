@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPriceCatalogRoutes(authorized *gin.RouterGroup, priceCatalogHandler *handlers.PriceCatalogHandler, adminOnly gin.HandlerFunc) {
+	prices := authorized.Group("/prices")
+	prices.Use(middlewares.RequireScope("prices"))
+	{
+		// 가격 스냅샷 목록/상세/변경 내역 조회
+		prices.GET("/snapshots", priceCatalogHandler.GetPriceSnapshots)
+		prices.GET("/snapshots/:version", priceCatalogHandler.GetPriceSnapshot)
+		prices.GET("/snapshots/:version/diff", priceCatalogHandler.GetPriceSnapshotDiff)
+
+		// 가격표 임포트 (관리자 전용)
+		prices.POST("/snapshots", adminOnly, priceCatalogHandler.ImportPriceSheet)
+	}
+}
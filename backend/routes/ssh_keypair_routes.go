@@ -2,17 +2,25 @@ package routes
 
 import (
 	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
+	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupSSHKeypairRoutes(router *gin.RouterGroup, handler *handlers.SSHKeypairHandler) {
 	keypairRoutes := router.Group("/keypairs")
+	keypairRoutes.Use(middlewares.RequireScope("keypairs"))
 	{
 		// 집계자별 키페어 관리
 		keypairRoutes.GET("/aggregator/:aggregatorId", handler.GetKeypairByAggregatorID)
-		keypairRoutes.GET("/aggregator/:aggregatorId/private-key", handler.DownloadPrivateKey)
+		// Private Key 다운로드는 조회지만 집계자 접근 권한을 넘겨주므로 keypairs:admin 필요
+		keypairRoutes.GET("/aggregator/:aggregatorId/private-key", middlewares.RequireScopeLevel("keypairs", models.APITokenScopeAdmin), handler.DownloadPrivateKey)
 		keypairRoutes.DELETE("/aggregator/:aggregatorId", handler.DeleteKeypairByAggregatorID)
 
+		// 집계자 SSH 호스트 키 고정 (TOFU) 조회 및 재고정
+		keypairRoutes.GET("/aggregator/:aggregatorId/host-key", handler.GetHostKey)
+		keypairRoutes.POST("/aggregator/:aggregatorId/host-key/repin", handler.RepinHostKey)
+
 		// 사용자별 키페어 목록
 		keypairRoutes.GET("/user/:userId", handler.ListKeypairsByUser)
 	}
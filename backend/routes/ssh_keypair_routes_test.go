@@ -0,0 +1,102 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services"
+)
+
+type keypairRoutesEnv struct {
+	t      *testing.T
+	tokens *repository.APITokenRepository
+	router *gin.Engine
+}
+
+func newKeypairRoutesEnv(t *testing.T) *keypairRoutesEnv {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open("file:keypair_routes?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.User{}, &models.APIToken{}, &models.Aggregator{}); err != nil {
+		t.Fatal(err)
+	}
+
+	env := &keypairRoutesEnv{t: t, tokens: repository.NewAPITokenRepository(db), router: gin.New()}
+	handler := handlers.NewSSHKeypairHandler(services.NewSSHKeypairService(repository.NewSSHKeypairRepository(db)), repository.NewAggregatorRepository(db))
+	SetupSSHKeypairRoutes(env.router.Group("/api", middlewares.AuthMiddleware(env.tokens)), handler)
+	return env
+}
+
+func (e *keypairRoutesEnv) token(scopes string) string {
+	e.t.Helper()
+	raw, hash, err := models.GenerateAPIToken()
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	if err := e.tokens.CreateToken(&models.APIToken{UserID: 1, Name: scopes, TokenHash: hash, Prefix: raw[:12], Scopes: scopes}); err != nil {
+		e.t.Fatal(err)
+	}
+	return raw
+}
+
+func (e *keypairRoutesEnv) request(method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestKeypairRouteScopes(t *testing.T) {
+	env := newKeypairRoutesEnv(t)
+	read := env.token("keypairs:read")
+	write := env.token("keypairs:write")
+	admin := env.token("keypairs:admin")
+	other := env.token("aggregators:admin")
+
+	cases := []struct {
+		name    string
+		method  string
+		path    string
+		token   string
+		allowed bool
+	}{
+		{"read keypair", http.MethodGet, "/api/keypairs/aggregator/agg-1", read, true},
+		{"read host key", http.MethodGet, "/api/keypairs/aggregator/agg-1/host-key", read, true},
+		{"private key with read", http.MethodGet, "/api/keypairs/aggregator/agg-1/private-key", read, false},
+		{"private key with write", http.MethodGet, "/api/keypairs/aggregator/agg-1/private-key", write, false},
+		{"private key with admin", http.MethodGet, "/api/keypairs/aggregator/agg-1/private-key", admin, true},
+		{"private key with other resource", http.MethodGet, "/api/keypairs/aggregator/agg-1/private-key", other, false},
+		{"repin with read", http.MethodPost, "/api/keypairs/aggregator/agg-1/host-key/repin", read, false},
+		{"repin with write", http.MethodPost, "/api/keypairs/aggregator/agg-1/host-key/repin", write, true},
+		{"delete with write", http.MethodDelete, "/api/keypairs/aggregator/agg-1", write, false},
+		{"delete with admin", http.MethodDelete, "/api/keypairs/aggregator/agg-1", admin, true},
+	}
+	for _, tc := range cases {
+		w := env.request(tc.method, tc.path, tc.token)
+		denied := w.Code == http.StatusForbidden && strings.Contains(w.Body.String(), "스코프")
+		if denied == tc.allowed {
+			t.Errorf("%s: status %d, body %s", tc.name, w.Code, w.Body.String())
+		}
+		// 스코프를 통과한 요청은 핸들러에서 없는 집계자로 처리
+		if tc.allowed && w.Code != http.StatusNotFound {
+			t.Errorf("%s: status %d, want 404 from handler", tc.name, w.Code)
+		}
+	}
+}
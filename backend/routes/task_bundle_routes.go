@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/handlers"
+	"github.com/Mungge/Fleecy-Cloud/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTaskBundleRoutes(authorized *gin.RouterGroup, taskBundleHandler *handlers.TaskBundleHandler) {
+	bundles := authorized.Group("/task-bundles")
+	bundles.Use(middlewares.RequireScope("fl"))
+	{
+		// 번들 버전 목록 조회 (?name=으로 특정 번들의 버전만 조회)
+		bundles.GET("", taskBundleHandler.GetTaskBundles)
+
+		// 번들 업로드 (같은 이름이면 다음 버전으로 저장)
+		bundles.POST("", taskBundleHandler.CreateTaskBundle)
+
+		// 번들 버전 조회 (파일 내용 포함)
+		bundles.GET("/:id", taskBundleHandler.GetTaskBundle)
+
+		// 사용하지 않는 번들 버전 삭제
+		bundles.DELETE("/:id", taskBundleHandler.DeleteTaskBundle)
+	}
+}
@@ -7,12 +7,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupVirtualMachineRoutes(r *gin.Engine, participantRepo *repository.ParticipantRepository) {
+func SetupVirtualMachineRoutes(r *gin.Engine, participantRepo *repository.ParticipantRepository, authMiddleware, organizationMiddleware gin.HandlerFunc) {
 	vmHandler := handlers.NewVirtualMachineHandler(participantRepo)
 
 	// VM 관리 라우트
 	vmRoutes := r.Group("/api/participants/:id/vms")
-	vmRoutes.Use(middlewares.AuthMiddleware())
+	vmRoutes.Use(authMiddleware, organizationMiddleware, middlewares.RequireScope("participants"))
 	{
 		// OpenStack VM 직접 조회
 		vmRoutes.GET("/all", vmHandler.GetVMRequests)
@@ -0,0 +1,23 @@
+package aggregator
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
+)
+
+// authorizeAggregator는 Aggregator를 조회하고 소유 조직에서의 역할로 작업 권한을 확인합니다
+// 조직 멤버가 아니면 존재 여부를 드러내지 않도록 ErrAggregatorNotFound를 반환합니다
+func authorizeAggregator(repo *repository.AggregatorRepository, id string, principal *authz.Principal, action authz.Action) (*models.Aggregator, error) {
+	aggregator, err := repo.GetAggregatorByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if aggregator == nil || !principal.IsMember(aggregator.OrganizationID) {
+		return nil, ErrAggregatorNotFound
+	}
+	if !principal.Can(aggregator.OrganizationID, action) {
+		return nil, ErrAggregatorAccessDenied
+	}
+	return aggregator, nil
+}
@@ -0,0 +1,347 @@
+package aggregator
+
+import (
+	"context"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+)
+
+const defaultUSDToKRW = 1300.0
+
+// PriceSource는 실행 구간 시점의 인스턴스 시간당 가격을 찾기 위한 가격표 조회 인터페이스입니다 (pricing.Catalog)
+type PriceSource interface {
+	EffectiveVersionAt(at time.Time) (int, error)
+	GetCloudPricesAt(version *int) ([]*models.CloudPrice, int, error)
+}
+
+// AggregatorCost는 집계자 한 대의 비용 내역입니다 (금액은 KRW)
+type AggregatorCost struct {
+	AggregatorID  string `json:"aggregator_id"`
+	Name          string `json:"name"`
+	UserID        int64  `json:"user_id"` // 집계자를 만든 사용자
+	Status        string `json:"status"`
+	CloudProvider string `json:"cloud_provider"`
+	Region        string `json:"region"`
+	InstanceType  string `json:"instance_type"`
+
+	PriceFound    bool    `json:"price_found"`     // 가격표에 인스턴스 가격이 없으면 false (비용 0으로 계산)
+	HourlyRateUSD float64 `json:"hourly_rate_usd"` // 현재 가격표의 CloudPrice.HourlyRate()
+	RunningHours  float64 `json:"running_hours"`   // running 상태 누적 시간 (정지 구간 제외)
+	RunPeriods    int     `json:"run_periods"`     // 시작/재시작 횟수
+	AccruedCost   float64 `json:"accrued_cost"`    // 구간마다 시작 시점에 유효했던 가격표로 계산
+	EstimatedCost float64 `json:"estimated_cost"`  // 생성 시 예상 월 비용
+
+	CurrentRound           int      `json:"current_round"`
+	TotalRounds            int      `json:"total_rounds"`
+	AvgRoundSeconds        float64  `json:"avg_round_seconds,omitempty"`
+	ProjectedRemainingCost *float64 `json:"projected_remaining_cost,omitempty"` // 남은 라운드 × 관측 라운드 시간 × 시간당 가격
+	ProjectedTotalCost     *float64 `json:"projected_total_cost,omitempty"`
+}
+
+// UserCost는 집계자를 만든 사용자별 비용 합계입니다
+type UserCost struct {
+	UserID                  int64   `json:"user_id"`
+	UserName                string  `json:"user_name"`
+	Aggregators             int     `json:"aggregators"`
+	TotalAccruedCost        float64 `json:"total_accrued_cost"`
+	TotalProjectedRemaining float64 `json:"total_projected_remaining_cost"`
+	RunningHourlyCost       float64 `json:"running_hourly_cost"`
+}
+
+// CostSummary는 조직의 집계자 비용 합계와 사용자별, 집계자별 내역입니다
+type CostSummary struct {
+	Currency                string            `json:"currency"`
+	USDToKRW                float64           `json:"usd_to_krw"`
+	TotalAccruedCost        float64           `json:"total_accrued_cost"`
+	TotalProjectedRemaining float64           `json:"total_projected_remaining_cost"`
+	RunningHourlyCost       float64           `json:"running_hourly_cost"` // 현재 running 집계자의 시간당 비용 합계
+	Users                   []*UserCost       `json:"users"`               // 누적 비용이 큰 순서
+	Aggregators             []*AggregatorCost `json:"aggregators"`
+}
+
+// CostMeter는 running 구간과 인스턴스 시간당 가격으로 집계자 누적 비용을 계산하고
+// 주기적으로 Aggregator.CurrentCost를 갱신합니다
+type CostMeter struct {
+	repo     *repository.AggregatorRepository
+	prices   PriceSource
+	usdToKRW float64
+	now      func() time.Time
+}
+
+// NewCostMeter는 새 CostMeter 인스턴스를 생성합니다
+// 환율은 최적화기와 같은 USD_TO_KRW 환경변수를 사용합니다
+func NewCostMeter(repo *repository.AggregatorRepository, prices PriceSource) *CostMeter {
+	rate := defaultUSDToKRW
+	if value, err := strconv.ParseFloat(os.Getenv("USD_TO_KRW"), 64); err == nil && value > 0 {
+		rate = value
+	}
+	return &CostMeter{
+		repo:     repo,
+		prices:   prices,
+		usdToKRW: rate,
+		now:      time.Now,
+	}
+}
+
+// Start는 interval마다 누적 비용을 갱신하는 백그라운드 루프를 시작합니다
+func (m *CostMeter) Start(ctx context.Context, interval time.Duration) {
+	if err := m.reconcileRunPeriods(); err != nil {
+		log.Printf("running 구간 보정 실패: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastRun := m.now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// 지난 실행 이후 종료된 구간까지 반영
+				since := lastRun.Add(-interval)
+				lastRun = m.now()
+				if err := m.UpdateCosts(since); err != nil {
+					log.Printf("집계자 누적 비용 갱신 실패: %v", err)
+				}
+			}
+		}
+	}()
+	log.Printf("집계자 비용 측정 시작 (간격: %v)", interval)
+}
+
+// reconcileRunPeriods는 구간 기록 도입 전부터 running이던 집계자에 지금부터의 구간을 엽니다
+func (m *CostMeter) reconcileRunPeriods() error {
+	running, err := m.repo.GetAggregatorsByStatus(models.AggregatorStatusRunning)
+	if err != nil {
+		return err
+	}
+	for _, aggregator := range running {
+		if err := m.repo.EnsureRunPeriod(aggregator.ID, m.now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateCosts는 실행 중이거나 since 이후 정지된 집계자의 CurrentCost를 갱신합니다
+func (m *CostMeter) UpdateCosts(since time.Time) error {
+	aggregators, err := m.repo.GetMeteredAggregators(since)
+	if err != nil {
+		return err
+	}
+	if len(aggregators) == 0 {
+		return nil
+	}
+
+	costs, err := m.calculate(aggregators, false)
+	if err != nil {
+		return err
+	}
+	for i, cost := range costs {
+		if cost.AccruedCost == aggregators[i].CurrentCost {
+			continue
+		}
+		if err := m.repo.UpdateAggregatorCurrentCost(cost.AggregatorID, cost.AccruedCost); err != nil {
+			log.Printf("집계자 %s 누적 비용 저장 실패: %v", cost.AggregatorID, err)
+		}
+	}
+	return nil
+}
+
+// GetOrganizationCostSummary는 조직 집계자의 누적/예상 비용 내역을 계산합니다
+func (m *CostMeter) GetOrganizationCostSummary(orgID int64) (*CostSummary, error) {
+	aggregators, err := m.repo.GetAggregatorsWithFederatedLearningByOrganizationID(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	costs, err := m.calculate(aggregators, true)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &CostSummary{
+		Currency:    "KRW",
+		USDToKRW:    m.usdToKRW,
+		Users:       []*UserCost{},
+		Aggregators: costs,
+	}
+	users := make(map[int64]*UserCost)
+	for i, cost := range costs {
+		user, ok := users[cost.UserID]
+		if !ok {
+			user = &UserCost{UserID: cost.UserID, UserName: aggregators[i].User.Name}
+			users[cost.UserID] = user
+			summary.Users = append(summary.Users, user)
+		}
+		user.Aggregators++
+		user.TotalAccruedCost += cost.AccruedCost
+		summary.TotalAccruedCost += cost.AccruedCost
+		if cost.ProjectedRemainingCost != nil {
+			user.TotalProjectedRemaining += *cost.ProjectedRemainingCost
+			summary.TotalProjectedRemaining += *cost.ProjectedRemainingCost
+		}
+		if cost.Status == models.AggregatorStatusRunning {
+			user.RunningHourlyCost += cost.HourlyRateUSD * m.usdToKRW
+			summary.RunningHourlyCost += cost.HourlyRateUSD * m.usdToKRW
+		}
+	}
+	for _, user := range summary.Users {
+		user.TotalAccruedCost = roundCost(user.TotalAccruedCost)
+		user.TotalProjectedRemaining = roundCost(user.TotalProjectedRemaining)
+		user.RunningHourlyCost = roundCost(user.RunningHourlyCost)
+	}
+	sort.SliceStable(summary.Users, func(i, j int) bool {
+		return summary.Users[i].TotalAccruedCost > summary.Users[j].TotalAccruedCost
+	})
+	summary.TotalAccruedCost = roundCost(summary.TotalAccruedCost)
+	summary.TotalProjectedRemaining = roundCost(summary.TotalProjectedRemaining)
+	summary.RunningHourlyCost = roundCost(summary.RunningHourlyCost)
+	return summary, nil
+}
+
+// calculate는 집계자별 누적 비용을 계산하며, withProjection이면 남은 라운드 예상 비용도 계산합니다
+// running 구간마다 구간 시작 시점에 유효했던 가격을 적용하므로 가격표가 바뀌어도 지난 비용은 달라지지 않습니다
+func (m *CostMeter) calculate(aggregators []*models.Aggregator, withProjection bool) ([]*AggregatorCost, error) {
+	book := &priceBook{prices: m.prices, versions: make(map[int]map[string]float64)}
+	now := m.now()
+	current, err := book.ratesAt(now)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(aggregators))
+	for i, aggregator := range aggregators {
+		ids[i] = aggregator.ID
+	}
+	periods, err := m.repo.GetRunPeriodsByAggregatorIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	costs := make([]*AggregatorCost, 0, len(aggregators))
+	for _, aggregator := range aggregators {
+		cost := &AggregatorCost{
+			AggregatorID:  aggregator.ID,
+			Name:          aggregator.Name,
+			UserID:        aggregator.UserID,
+			Status:        aggregator.Status,
+			CloudProvider: aggregator.CloudProvider,
+			Region:        aggregator.Region,
+			InstanceType:  aggregator.InstanceType,
+			EstimatedCost: aggregator.EstimatedCost,
+			CurrentRound:  aggregator.CurrentRound,
+			RunPeriods:    len(periods[aggregator.ID]),
+		}
+		key := priceKey(aggregator.CloudProvider, aggregator.Region, aggregator.InstanceType)
+		cost.HourlyRateUSD, cost.PriceFound = current[key]
+
+		var running time.Duration
+		var accruedUSD float64
+		for _, period := range periods[aggregator.ID] {
+			rates, err := book.ratesAt(period.StartedAt)
+			if err != nil {
+				return nil, err
+			}
+			duration := period.Duration(now)
+			running += duration
+			accruedUSD += duration.Hours() * rates[key]
+		}
+		cost.RunningHours = math.Round(running.Hours()*1000) / 1000
+		cost.AccruedCost = roundCost(accruedUSD * m.usdToKRW)
+
+		if withProjection {
+			m.project(aggregator, cost, running)
+		}
+		costs = append(costs, cost)
+	}
+	return costs, nil
+}
+
+// project는 남은 라운드 수와 관측된 라운드 소요 시간으로 남은 비용을 예측합니다
+// 완료된 라운드 기록이 없으면 누적 실행 시간을 현재 라운드 수로 나눈 값을 사용합니다
+func (m *CostMeter) project(aggregator *models.Aggregator, cost *AggregatorCost, running time.Duration) {
+	if aggregator.FederatedLearning != nil {
+		cost.TotalRounds = aggregator.FederatedLearning.Rounds
+	}
+	if cost.TotalRounds == 0 || !cost.PriceFound {
+		return
+	}
+
+	remaining := cost.TotalRounds - aggregator.CurrentRound
+	if remaining <= 0 || aggregator.Status != models.AggregatorStatusRunning {
+		zero := 0.0
+		cost.ProjectedRemainingCost = &zero
+		total := cost.AccruedCost
+		cost.ProjectedTotalCost = &total
+		return
+	}
+
+	avg, count, err := m.repo.GetAverageRoundDuration(aggregator.ID)
+	if err != nil {
+		log.Printf("집계자 %s 라운드 소요 시간 조회 실패: %v", aggregator.ID, err)
+	}
+	if count == 0 && aggregator.CurrentRound > 0 {
+		avg = running.Seconds() / float64(aggregator.CurrentRound)
+	}
+	if avg <= 0 {
+		return
+	}
+
+	cost.AvgRoundSeconds = math.Round(avg*10) / 10
+	projected := roundCost(float64(remaining) * avg / 3600 * cost.HourlyRateUSD * m.usdToKRW)
+	total := roundCost(cost.AccruedCost + projected)
+	cost.ProjectedRemainingCost = &projected
+	cost.ProjectedTotalCost = &total
+}
+
+// priceBook은 비용 계산 한 번 동안 가격표 버전별 시간당 가격을 캐시합니다
+type priceBook struct {
+	prices   PriceSource
+	versions map[int]map[string]float64
+}
+
+// ratesAt은 at 시점에 유효했던 provider/region/instance_type별 시간당 가격(USD)을 반환합니다
+func (b *priceBook) ratesAt(at time.Time) (map[string]float64, error) {
+	version, err := b.prices.EffectiveVersionAt(at)
+	if err != nil {
+		return nil, err
+	}
+	if rates, ok := b.versions[version]; ok {
+		return rates, nil
+	}
+
+	// 버전 0은 스냅샷이 없는 경우 (cloud_price 테이블)
+	var pinned *int
+	if version > 0 {
+		pinned = &version
+	}
+	prices, _, err := b.prices.GetCloudPricesAt(pinned)
+	if err != nil {
+		return nil, err
+	}
+	rates := make(map[string]float64, len(prices))
+	for _, price := range prices {
+		rates[priceKey(price.Provider.Name, price.Region.Name, price.InstanceType)] = price.HourlyRate()
+	}
+	b.versions[version] = rates
+	return rates, nil
+}
+
+func priceKey(provider, region, instanceType string) string {
+	return strings.ToLower(provider) + "/" + region + "/" + instanceType
+}
+
+func roundCost(value float64) float64 {
+	return math.Round(value*100) / 100
+}
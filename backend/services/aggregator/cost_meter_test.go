@@ -0,0 +1,233 @@
+package aggregator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+)
+
+// fakePriceVersion은 effectiveAt부터 유효한 가격표 버전입니다 (e2-medium 한 종류)
+type fakePriceVersion struct {
+	effectiveAt time.Time
+	hourlyRate  float64
+}
+
+// fakePriceSource는 버전 목록(적용 시점 순서)으로 pricing.Catalog를 흉내 냅니다
+type fakePriceSource struct {
+	versions []fakePriceVersion
+}
+
+func (f *fakePriceSource) EffectiveVersionAt(at time.Time) (int, error) {
+	if len(f.versions) == 0 {
+		return 0, nil
+	}
+	version := 1
+	for i, v := range f.versions {
+		if !v.effectiveAt.After(at) {
+			version = i + 1
+		}
+	}
+	return version, nil
+}
+
+func (f *fakePriceSource) GetCloudPricesAt(version *int) ([]*models.CloudPrice, int, error) {
+	v := f.versions[*version-1]
+	return []*models.CloudPrice{{
+		Provider:      models.Provider{Name: "GCP"},
+		Region:        models.Region{Name: "asia-northeast3"},
+		InstanceType:  "e2-medium",
+		OnDemandPrice: v.hourlyRate,
+	}}, *version, nil
+}
+
+var costEpoch = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+type costMeterEnv struct {
+	t     *testing.T
+	db    *gorm.DB
+	repo  *repository.AggregatorRepository
+	meter *CostMeter
+	now   time.Time
+}
+
+func newCostMeterEnv(t *testing.T, prices *fakePriceSource) *costMeterEnv {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.FederatedLearning{}, &models.TrainingRound{}); err != nil {
+		t.Fatal(err)
+	}
+	env := &costMeterEnv{t: t, db: db, repo: repository.NewAggregatorRepository(db), now: costEpoch}
+	env.meter = NewCostMeter(env.repo, prices)
+	env.meter.usdToKRW = 1000
+	env.meter.now = func() time.Time { return env.now }
+	return env
+}
+
+func (e *costMeterEnv) createAggregator(userID int64, status string) *models.Aggregator {
+	e.t.Helper()
+	aggregator := &models.Aggregator{Name: "agg", Status: status, Algorithm: "fedavg", CloudProvider: "gcp",
+		ProjectName: "fl", Region: "asia-northeast3", Zone: "asia-northeast3-a", InstanceType: "e2-medium", UserID: userID, OrganizationID: 1}
+	if err := e.repo.CreateAggregator(aggregator); err != nil {
+		e.t.Fatal(err)
+	}
+	return aggregator
+}
+
+// addPeriod는 시작 시각으로부터 시간 단위로 running 구간을 기록합니다 (endHours < 0이면 열린 구간)
+func (e *costMeterEnv) addPeriod(aggregatorID string, startHours, endHours float64) {
+	e.t.Helper()
+	period := &models.AggregatorRunPeriod{AggregatorID: aggregatorID, StartedAt: costEpoch.Add(hours(startHours))}
+	if endHours >= 0 {
+		ended := costEpoch.Add(hours(endHours))
+		period.EndedAt = &ended
+	}
+	if err := e.db.Create(period).Error; err != nil {
+		e.t.Fatal(err)
+	}
+}
+
+func hours(h float64) time.Duration {
+	return time.Duration(h * float64(time.Hour))
+}
+
+func findCost(summary *CostSummary, aggregatorID string) *AggregatorCost {
+	for _, cost := range summary.Aggregators {
+		if cost.AggregatorID == aggregatorID {
+			return cost
+		}
+	}
+	return nil
+}
+
+func TestCostMeterPricesEachPeriodAtItsStart(t *testing.T) {
+	// 2.5시간 시점에 시간당 0.1 → 0.2 USD로 인상
+	env := newCostMeterEnv(t, &fakePriceSource{versions: []fakePriceVersion{
+		{effectiveAt: costEpoch.Add(-24 * time.Hour), hourlyRate: 0.1},
+		{effectiveAt: costEpoch.Add(hours(2.5)), hourlyRate: 0.2},
+	}})
+	for _, user := range []*models.User{{ID: 1, Name: "alice", Email: "alice@example.com"}, {ID: 2, Name: "bob", Email: "bob@example.com"}} {
+		if err := env.db.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 0~2시간 (0.1), 3시간~현재 (0.2)
+	restarted := env.createAggregator(1, models.AggregatorStatusRunning)
+	env.addPeriod(restarted.ID, 0, 2)
+	env.addPeriod(restarted.ID, 3, -1)
+	// 인상 전에 시작해 인상 후까지 이어진 구간은 시작 시점 가격
+	stopped := env.createAggregator(2, "stopped")
+	env.addPeriod(stopped.ID, 1, 3.5)
+	env.now = costEpoch.Add(4 * time.Hour)
+
+	summary, err := env.meter.GetOrganizationCostSummary(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost := findCost(summary, restarted.ID)
+	if cost == nil || cost.AccruedCost != 400 || cost.RunningHours != 3 || cost.RunPeriods != 2 || cost.HourlyRateUSD != 0.2 || !cost.PriceFound {
+		t.Fatalf("restarted = %+v", cost)
+	}
+	if cost := findCost(summary, stopped.ID); cost == nil || cost.AccruedCost != 250 {
+		t.Fatalf("stopped = %+v", cost)
+	}
+	if summary.TotalAccruedCost != 650 || summary.RunningHourlyCost != 200 {
+		t.Fatalf("summary totals = %+v", summary)
+	}
+
+	if len(summary.Users) != 2 {
+		t.Fatalf("users = %+v", summary.Users)
+	}
+	alice, bob := summary.Users[0], summary.Users[1]
+	if alice.UserID != 1 || alice.UserName != "alice" || alice.Aggregators != 1 || alice.TotalAccruedCost != 400 || alice.RunningHourlyCost != 200 {
+		t.Fatalf("alice = %+v", alice)
+	}
+	if bob.UserID != 2 || bob.TotalAccruedCost != 250 || bob.RunningHourlyCost != 0 {
+		t.Fatalf("bob = %+v", bob)
+	}
+
+	// 가격이 다시 바뀌어도 지난 구간의 비용은 그대로
+	prices := env.meter.prices.(*fakePriceSource)
+	prices.versions = append(prices.versions, fakePriceVersion{effectiveAt: env.now, hourlyRate: 1})
+	if summary, err := env.meter.GetOrganizationCostSummary(1); err != nil || summary.TotalAccruedCost != 650 {
+		t.Fatalf("after price change: %+v, %v", summary, err)
+	}
+}
+
+func TestCostMeterProjectsRemainingRounds(t *testing.T) {
+	env := newCostMeterEnv(t, &fakePriceSource{versions: []fakePriceVersion{{effectiveAt: costEpoch.Add(-time.Hour), hourlyRate: 0.2}}})
+
+	observed := env.createAggregator(1, models.AggregatorStatusRunning)
+	env.addPeriod(observed.ID, 0, -1)
+	estimated := env.createAggregator(1, models.AggregatorStatusRunning)
+	env.addPeriod(estimated.ID, 0, -1)
+	for i, aggregator := range []*models.Aggregator{observed, estimated} {
+		id := aggregator.ID
+		fl := &models.FederatedLearning{ID: "fl-" + id, UserID: 1, OrganizationID: 1, CloudConnectionID: "conn", AggregatorID: &id, Name: "fl", Rounds: 10}
+		if err := env.db.Create(fl).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := env.db.Model(aggregator).Update("current_round", 4+i).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 완료된 라운드 평균 360초
+	for i, duration := range []int{300, 420} {
+		round := &models.TrainingRound{ID: fmt.Sprintf("%s-%d", observed.ID, i), AggregatorID: observed.ID, Round: i + 1, Duration: duration, StartedAt: costEpoch}
+		if err := env.db.Create(round).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	env.now = costEpoch.Add(time.Hour)
+
+	summary, err := env.meter.GetOrganizationCostSummary(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 남은 6라운드 × 0.1시간 × 0.2 USD × 1000
+	cost := findCost(summary, observed.ID)
+	if cost == nil || cost.TotalRounds != 10 || cost.AvgRoundSeconds != 360 || cost.ProjectedRemainingCost == nil ||
+		*cost.ProjectedRemainingCost != 120 || *cost.ProjectedTotalCost != 320 {
+		t.Fatalf("observed = %+v", cost)
+	}
+	// 라운드 기록이 없으면 누적 실행 시간 / 현재 라운드 (1시간 / 5라운드 = 720초)
+	cost = findCost(summary, estimated.ID)
+	if cost == nil || cost.AvgRoundSeconds != 720 || cost.ProjectedRemainingCost == nil || *cost.ProjectedRemainingCost != 200 {
+		t.Fatalf("estimated = %+v", cost)
+	}
+	if summary.TotalProjectedRemaining != 320 || summary.Users[0].TotalProjectedRemaining != 320 {
+		t.Fatalf("summary = %+v, users %+v", summary, summary.Users[0])
+	}
+}
+
+func TestCostMeterReconcilesAfterRestart(t *testing.T) {
+	env := newCostMeterEnv(t, &fakePriceSource{versions: []fakePriceVersion{{effectiveAt: costEpoch.Add(-time.Hour), hourlyRate: 0.1}}})
+
+	// 구간 기록 없이 running으로 남은 집계자 (구간 기록 도입 전 배포 또는 기록 누락)
+	aggregator := env.createAggregator(1, models.AggregatorStatusRunning)
+	if err := env.meter.reconcileRunPeriods(); err != nil {
+		t.Fatal(err)
+	}
+	// 다시 시작해도 열린 구간을 중복으로 만들지 않음
+	env.now = costEpoch.Add(30 * time.Minute)
+	if err := env.meter.reconcileRunPeriods(); err != nil {
+		t.Fatal(err)
+	}
+	periods, err := env.repo.GetRunPeriodsByAggregatorIDs([]string{aggregator.ID})
+	if err != nil || len(periods[aggregator.ID]) != 1 || !periods[aggregator.ID][0].StartedAt.Equal(costEpoch) {
+		t.Fatalf("periods = %+v, %v", periods[aggregator.ID], err)
+	}
+
+	env.now = costEpoch.Add(2 * time.Hour)
+	if err := env.meter.UpdateCosts(costEpoch); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := env.repo.GetAggregatorByID(aggregator.ID)
+	if err != nil || stored == nil || stored.CurrentCost != 200 {
+		t.Fatalf("stored cost = %+v, %v", stored, err)
+	}
+}
@@ -0,0 +1,282 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+)
+
+const (
+	// deploymentQueueSize는 대기 가능한 배포 작업 수입니다
+	deploymentQueueSize = 100
+	// deploymentTimeout은 배포 작업 하나에 허용되는 최대 시간입니다
+	deploymentTimeout = 30 * time.Minute
+	// destroyTimeout은 삭제(terraform destroy) 작업 하나에 허용되는 최대 시간입니다
+	destroyTimeout = 30 * time.Minute
+	// hostKeyCaptureTimeout은 프로비저닝 직후 sshd 응답을 기다려 호스트 키를 고정하는 최대 시간입니다
+	hostKeyCaptureTimeout = 3 * time.Minute
+)
+
+// StartDeploymentWorkers는 비동기 배포/삭제 작업을 처리하는 워커 풀을 시작합니다
+// 서버 재시작으로 중단된 배포 작업 중 apply 전 단계는 처음부터 다시 등록하고, apply 중이던 작업은
+// failed로 기록합니다 (Terraform 상태는 보관되므로 삭제로 정리 가능)
+// 중단된 삭제 작업은 destroy가 멱등이므로 다시 등록합니다
+func (s *AggregatorService) StartDeploymentWorkers(workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	s.recoverInterruptedDeployments()
+
+	for i := 0; i < workers; i++ {
+		go s.deploymentWorker(i + 1)
+	}
+
+	s.resumeInterruptedDestroys()
+	log.Printf("집계자 배포 워커 %d개 시작", workers)
+}
+
+// GetLatestDeployment는 집계자의 가장 최근 배포 작업을 조회합니다
+func (s *AggregatorService) GetLatestDeployment(aggregatorID string) (*models.AggregatorDeployment, error) {
+	return s.deploymentRepo.GetLatestDeploymentByAggregatorID(aggregatorID)
+}
+
+// enqueueDeployment는 배포 작업을 워커 큐에 추가합니다
+func (s *AggregatorService) enqueueDeployment(deploymentID string) error {
+	select {
+	case s.deploymentQueue <- deploymentID:
+		return nil
+	default:
+		return ErrDeploymentQueueFull
+	}
+}
+
+// enqueueDestroy는 집계자 삭제 작업을 워커 큐에 추가합니다
+func (s *AggregatorService) enqueueDestroy(aggregatorID string) error {
+	select {
+	case s.destroyQueue <- aggregatorID:
+		return nil
+	default:
+		return ErrDeploymentQueueFull
+	}
+}
+
+// deploymentWorker는 큐에서 배포/삭제 작업을 꺼내 순차적으로 실행합니다
+func (s *AggregatorService) deploymentWorker(workerID int) {
+	for {
+		select {
+		case deploymentID := <-s.deploymentQueue:
+			log.Printf("[worker-%d] 배포 작업 시작: %s", workerID, deploymentID)
+			s.runDeployment(deploymentID)
+		case aggregatorID := <-s.destroyQueue:
+			log.Printf("[worker-%d] 삭제 작업 시작: %s", workerID, aggregatorID)
+			s.runDestroy(aggregatorID)
+		}
+	}
+}
+
+// runDestroy는 삭제 작업 하나를 요청과 분리된 컨텍스트에서 실행합니다
+// 클라이언트 연결이 끊겨도 destroy가 중간에 취소되지 않습니다
+func (s *AggregatorService) runDestroy(aggregatorID string) {
+	aggregator, err := s.repo.GetAggregatorByID(aggregatorID)
+	if err != nil || aggregator == nil {
+		log.Printf("Failed to load aggregator %s for destroy: %v", aggregatorID, err)
+		return
+	}
+	if aggregator.Status != models.AggregatorStatusDeleting {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), destroyTimeout)
+	defer cancel()
+
+	err = s.destroyAggregator(ctx, aggregator)
+	audit.RecordSystemEvent(audit.SystemEvent{
+		Action:         "aggregator.destroy",
+		ResourceType:   "aggregators",
+		ResourceID:     aggregator.ID,
+		OrganizationID: aggregator.OrganizationID,
+		UserID:         aggregator.UserID,
+		Err:            err,
+		Detail: map[string]interface{}{
+			"cloud_provider": aggregator.CloudProvider,
+			"region":         aggregator.Region,
+		},
+	})
+}
+
+// resumeInterruptedDestroys는 이전 프로세스에서 완료되지 못한 삭제 작업을 다시 등록합니다
+func (s *AggregatorService) resumeInterruptedDestroys() {
+	aggregators, err := s.repo.GetAggregatorsByStatus(models.AggregatorStatusDeleting)
+	if err != nil {
+		log.Printf("Failed to load interrupted destroys: %v", err)
+		return
+	}
+
+	for _, aggregator := range aggregators {
+		log.Printf("중단된 삭제 작업을 다시 등록합니다: %s", aggregator.ID)
+		if err := s.enqueueDestroy(aggregator.ID); err != nil {
+			log.Printf("Failed to resume destroy for aggregator %s: %v", aggregator.ID, err)
+		}
+	}
+}
+
+// runDeployment는 배포 작업 하나를 실행하고 최종 상태(running/failed)를 기록합니다
+func (s *AggregatorService) runDeployment(deploymentID string) {
+	deployment, err := s.deploymentRepo.GetDeploymentByID(deploymentID)
+	if err != nil || deployment == nil {
+		log.Printf("Failed to load deployment %s: %v", deploymentID, err)
+		return
+	}
+	if deployment.IsTerminal() {
+		return
+	}
+
+	aggregator, err := s.repo.GetAggregatorByID(deployment.AggregatorID)
+	if err != nil || aggregator == nil {
+		s.failDeployment(deployment, fmt.Errorf("aggregator %s not found: %v", deployment.AggregatorID, err))
+		return
+	}
+
+	now := time.Now()
+	deployment.StartedAt = &now
+
+	ctx, cancel := context.WithTimeout(context.Background(), deploymentTimeout)
+	defer cancel()
+
+	if err := s.deploy(ctx, aggregator, deployment); err != nil {
+		log.Printf("Terraform deployment failed for aggregator %s: %v", aggregator.ID, err)
+		s.failDeployment(deployment, err)
+		recordDeploymentAudit(aggregator, deployment, err)
+		return
+	}
+
+	if err := s.transitionDeployment(deployment, models.DeploymentStateRunning, "집계자 배포가 성공적으로 완료되었습니다"); err != nil {
+		log.Printf("Failed to record deployment %s as running: %v", deployment.ID, err)
+	}
+
+	log.Printf("Terraform deployment successful for aggregator %s", aggregator.ID)
+	if err := s.repo.UpdateAggregatorStatus(aggregator.ID, "running"); err != nil {
+		log.Printf("Failed to update aggregator status to running: %v", err)
+	}
+	recordDeploymentAudit(aggregator, deployment, nil)
+}
+
+// recordDeploymentAudit은 배포 작업의 최종 결과를 감사 로그에 남깁니다
+func recordDeploymentAudit(aggregator *models.Aggregator, deployment *models.AggregatorDeployment, err error) {
+	audit.RecordSystemEvent(audit.SystemEvent{
+		Action:         "aggregator.deploy",
+		ResourceType:   "aggregators",
+		ResourceID:     aggregator.ID,
+		OrganizationID: aggregator.OrganizationID,
+		UserID:         aggregator.UserID,
+		Err:            err,
+		Detail: map[string]interface{}{
+			"deployment_id":  deployment.ID,
+			"cloud_provider": aggregator.CloudProvider,
+			"region":         aggregator.Region,
+		},
+	})
+}
+
+// transitionDeployment는 배포 작업을 다음 상태로 전이시키고 DB와 진행 상황 스트림에 기록합니다
+func (s *AggregatorService) transitionDeployment(deployment *models.AggregatorDeployment, next, message string) error {
+	if !deployment.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidDeploymentTransition, deployment.State, next)
+	}
+
+	deployment.State = next
+	deployment.Message = message
+	if next != models.DeploymentStateFailed {
+		deployment.Stage = models.DeploymentStageOf(next)
+	}
+	if next == models.DeploymentStateRunning || next == models.DeploymentStateFailed {
+		now := time.Now()
+		deployment.FinishedAt = &now
+	}
+
+	if err := s.deploymentRepo.UpdateDeploymentState(deployment); err != nil {
+		return fmt.Errorf("failed to record deployment state: %v", err)
+	}
+
+	switch next {
+	case models.DeploymentStateRunning:
+		s.progressTracker.SendSuccess(deployment.AggregatorID, message)
+	case models.DeploymentStateFailed:
+		s.progressTracker.SendError(deployment.AggregatorID, deployment.Stage, message, fmt.Errorf("%s", deployment.Error))
+	default:
+		s.progressTracker.SendProgress(deployment.AggregatorID, deployment.Stage, message)
+	}
+	return nil
+}
+
+// updateDeploymentMessage는 상태 전이 없이 현재 단계의 진행 메시지만 갱신합니다
+func (s *AggregatorService) updateDeploymentMessage(deployment *models.AggregatorDeployment, message string) {
+	deployment.Message = message
+	if err := s.deploymentRepo.UpdateDeploymentState(deployment); err != nil {
+		log.Printf("Failed to update deployment message %s: %v", deployment.ID, err)
+	}
+	s.progressTracker.SendProgress(deployment.AggregatorID, deployment.Stage, message)
+}
+
+// failDeployment는 배포 작업과 집계자를 failed 상태로 기록합니다
+func (s *AggregatorService) failDeployment(deployment *models.AggregatorDeployment, cause error) {
+	deployment.Error = deploymentErrorMessage(cause)
+	if err := s.transitionDeployment(deployment, models.DeploymentStateFailed, "집계자 배포 실패"); err != nil {
+		log.Printf("Failed to record deployment %s as failed: %v", deployment.ID, err)
+	}
+
+	if err := s.repo.UpdateAggregatorStatus(deployment.AggregatorID, "failed"); err != nil {
+		log.Printf("Failed to update aggregator status to failed: %v", err)
+	}
+}
+
+// recoverInterruptedDeployments는 이전 프로세스에서 완료되지 못한 배포 작업을 정리합니다
+// apply 전 단계의 작업은 클라우드 리소스를 만들지 않았으므로 creating으로 되돌려 다시 등록하고,
+// apply 중이던 작업은 리소스가 일부 생성되었을 수 있으므로 failed로 기록합니다
+func (s *AggregatorService) recoverInterruptedDeployments() {
+	deployments, err := s.deploymentRepo.GetUnfinishedDeployments()
+	if err != nil {
+		log.Printf("Failed to load unfinished deployments: %v", err)
+		return
+	}
+
+	for _, deployment := range deployments {
+		if !deployment.IsBeforeApply() {
+			log.Printf("중단된 배포 작업을 실패 처리합니다: %s (state=%s)", deployment.ID, deployment.State)
+			s.failDeployment(deployment, ErrDeploymentInterrupted)
+			continue
+		}
+
+		log.Printf("중단된 배포 작업을 다시 등록합니다: %s (state=%s)", deployment.ID, deployment.State)
+		deployment.State = models.DeploymentStateCreating
+		deployment.Stage = models.DeploymentStageOf(models.DeploymentStateCreating)
+		deployment.Message = "서버 재시작으로 중단된 배포 작업을 다시 시작합니다"
+		if err := s.deploymentRepo.UpdateDeploymentState(deployment); err != nil {
+			log.Printf("Failed to reset deployment %s: %v", deployment.ID, err)
+			continue
+		}
+		if err := s.enqueueDeployment(deployment.ID); err != nil {
+			s.failDeployment(deployment, err)
+		}
+	}
+}
+
+// deploymentErrorMessage는 배포 에러를 사용자 친화적인 메시지로 변환합니다
+func deploymentErrorMessage(err error) string {
+	switch {
+	case strings.Contains(err.Error(), "active AWS connection not found"):
+		return "AWS 클라우드 연결이 설정되지 않았습니다. 먼저 클라우드 인증 정보를 등록해주세요."
+	case strings.Contains(err.Error(), "active GCP connection not found"):
+		return "GCP 클라우드 연결이 설정되지 않았습니다. 먼저 클라우드 인증 정보를 등록해주세요."
+	case strings.Contains(err.Error(), "AWS credentials"):
+		return "AWS 자격증명이 올바르지 않습니다. 클라우드 인증 정보를 다시 확인해주세요."
+	default:
+		return fmt.Sprintf("집계자 배포 실패: %v", err)
+	}
+}
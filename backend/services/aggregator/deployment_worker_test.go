@@ -0,0 +1,178 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+)
+
+// newTestDB는 테스트마다 분리된 메모리 SQLite DB를 생성합니다
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", regexp.MustCompile(`\W`).ReplaceAllString(t.Name(), "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.User{}, &models.Aggregator{}, &models.AggregatorRunPeriod{}, &models.AggregatorDeployment{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// fakeTerraform은 Terraform 없이 배포 단계를 흉내 내는 프로비저닝 함수입니다
+type fakeTerraform struct {
+	service  *AggregatorService
+	applyErr error
+	calls    int
+}
+
+func (f *fakeTerraform) deploy(ctx context.Context, aggregator *models.Aggregator, deployment *models.AggregatorDeployment) error {
+	f.calls++
+	for _, state := range []string{models.DeploymentStateKeypair, models.DeploymentStateWorkspace, models.DeploymentStateApplying} {
+		if err := f.service.transitionDeployment(deployment, state, state); err != nil {
+			return err
+		}
+	}
+	if f.applyErr != nil {
+		return fmt.Errorf("%w: %v", ErrTerraformDeployFailed, f.applyErr)
+	}
+	return nil
+}
+
+type deploymentWorkerEnv struct {
+	t           *testing.T
+	service     *AggregatorService
+	terraform   *fakeTerraform
+	aggregators *repository.AggregatorRepository
+	deployments *repository.AggregatorDeploymentRepository
+}
+
+func newDeploymentWorkerEnv(t *testing.T) *deploymentWorkerEnv {
+	db := newTestDB(t)
+	env := &deploymentWorkerEnv{
+		t:           t,
+		aggregators: repository.NewAggregatorRepository(db),
+		deployments: repository.NewAggregatorDeploymentRepository(db),
+	}
+	env.service = NewAggregatorService(env.aggregators, nil, nil, nil, env.deployments, "")
+	env.terraform = &fakeTerraform{service: env.service}
+	env.service.deploy = env.terraform.deploy
+	return env
+}
+
+// createDeployment는 지정한 상태에서 멈춘 배포 작업을 만듭니다
+func (e *deploymentWorkerEnv) createDeployment(state string) *models.AggregatorDeployment {
+	e.t.Helper()
+	aggregator := &models.Aggregator{Name: "agg", Status: "creating", Algorithm: "fedavg", CloudProvider: "gcp",
+		ProjectName: "fl", Region: "asia-northeast3", Zone: "asia-northeast3-a", InstanceType: "e2-medium", UserID: 1, OrganizationID: 1}
+	if err := e.aggregators.CreateAggregator(aggregator); err != nil {
+		e.t.Fatal(err)
+	}
+	deployment := &models.AggregatorDeployment{ID: "dep-" + aggregator.ID, AggregatorID: aggregator.ID, UserID: 1,
+		State: state, Stage: models.DeploymentStageOf(state), TotalStages: models.DeploymentTotalStages}
+	if err := e.deployments.CreateDeployment(deployment); err != nil {
+		e.t.Fatal(err)
+	}
+	return deployment
+}
+
+func (e *deploymentWorkerEnv) reload(deployment *models.AggregatorDeployment) (*models.AggregatorDeployment, *models.Aggregator) {
+	e.t.Helper()
+	reloaded, err := e.deployments.GetDeploymentByID(deployment.ID)
+	if err != nil || reloaded == nil {
+		e.t.Fatalf("reload deployment: %v", err)
+	}
+	aggregator, err := e.aggregators.GetAggregatorByID(deployment.AggregatorID)
+	if err != nil || aggregator == nil {
+		e.t.Fatalf("reload aggregator: %v", err)
+	}
+	return reloaded, aggregator
+}
+
+func TestRunDeployment(t *testing.T) {
+	env := newDeploymentWorkerEnv(t)
+
+	succeeded := env.createDeployment(models.DeploymentStateCreating)
+	env.service.runDeployment(succeeded.ID)
+	deployment, aggregator := env.reload(succeeded)
+	if deployment.State != models.DeploymentStateRunning || deployment.Stage != models.DeploymentTotalStages || deployment.FinishedAt == nil {
+		t.Fatalf("deployment = %+v", deployment)
+	}
+	if aggregator.Status != models.AggregatorStatusRunning {
+		t.Fatalf("aggregator status = %s", aggregator.Status)
+	}
+	// 종료된 작업은 다시 실행하지 않음
+	env.service.runDeployment(succeeded.ID)
+	if env.terraform.calls != 1 {
+		t.Fatalf("terminal deployment ran again: %d calls", env.terraform.calls)
+	}
+
+	env.terraform.applyErr = fmt.Errorf("quota exceeded")
+	failed := env.createDeployment(models.DeploymentStateCreating)
+	env.service.runDeployment(failed.ID)
+	deployment, aggregator = env.reload(failed)
+	if deployment.State != models.DeploymentStateFailed || deployment.Stage != models.DeploymentStageOf(models.DeploymentStateApplying) || deployment.Error == "" {
+		t.Fatalf("deployment = %+v", deployment)
+	}
+	if aggregator.Status != "failed" {
+		t.Fatalf("aggregator status = %s", aggregator.Status)
+	}
+}
+
+func TestRecoverInterruptedDeployments(t *testing.T) {
+	env := newDeploymentWorkerEnv(t)
+	requeued := map[string]*models.AggregatorDeployment{}
+	for _, state := range []string{models.DeploymentStateCreating, models.DeploymentStateKeypair, models.DeploymentStateWorkspace} {
+		requeued[env.createDeployment(state).ID] = nil
+	}
+	applying := env.createDeployment(models.DeploymentStateApplying)
+	running := env.createDeployment(models.DeploymentStateRunning)
+
+	env.service.recoverInterruptedDeployments()
+
+	// apply 중이던 작업만 실패 처리
+	if deployment, aggregator := env.reload(applying); deployment.State != models.DeploymentStateFailed || aggregator.Status != "failed" {
+		t.Fatalf("applying deployment = %s, aggregator = %s", deployment.State, aggregator.Status)
+	}
+	if deployment, _ := env.reload(running); deployment.State != models.DeploymentStateRunning {
+		t.Fatalf("finished deployment changed to %s", deployment.State)
+	}
+
+	// apply 전 단계 작업은 creating으로 되돌려 다시 등록
+	if len(env.service.deploymentQueue) != len(requeued) {
+		t.Fatalf("queued %d deployments, want %d", len(env.service.deploymentQueue), len(requeued))
+	}
+	for len(env.service.deploymentQueue) > 0 {
+		id := <-env.service.deploymentQueue
+		if _, ok := requeued[id]; !ok {
+			t.Fatalf("unexpected deployment queued: %s", id)
+		}
+		deployment, err := env.deployments.GetDeploymentByID(id)
+		if err != nil || deployment == nil {
+			t.Fatalf("load %s: %v", id, err)
+		}
+		if deployment.State != models.DeploymentStateCreating || deployment.Stage != 1 {
+			t.Fatalf("requeued deployment = %s (stage %d)", deployment.State, deployment.Stage)
+		}
+		env.service.runDeployment(id)
+		if deployment, _ := env.reload(deployment); deployment.State != models.DeploymentStateRunning {
+			t.Fatalf("requeued deployment finished as %s: %s", deployment.State, deployment.Error)
+		}
+	}
+}
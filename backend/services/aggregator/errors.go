@@ -5,13 +5,21 @@ import "errors"
 // Aggregator 서비스 관련 에러들
 var (
 	ErrAggregatorNotFound     = errors.New("aggregator not found or access denied")
+	ErrAggregatorAccessDenied = errors.New("aggregator action not permitted for role")
 	ErrInvalidUserID          = errors.New("invalid user ID")
 	ErrInvalidAggregatorID    = errors.New("invalid aggregator ID")
 	ErrAggregatorCreateFailed = errors.New("failed to create aggregator")
 	ErrAggregatorUpdateFailed = errors.New("failed to update aggregator")
 	ErrAggregatorDeleteFailed = errors.New("failed to delete aggregator")
 	ErrTerraformDeployFailed  = errors.New("terraform deployment failed")
+	ErrTerraformDestroyFailed = errors.New("terraform destroy failed")
+	ErrTerraformStateNotFound = errors.New("terraform state not found")
 	ErrInvalidMetricsData     = errors.New("invalid metrics data")
 	ErrInvalidStatus          = errors.New("invalid status")
 	ErrGCPNeedsProjectID      = errors.New("need project ID for GCP cloud provider")
+
+	// 비동기 배포 작업 관련 에러들
+	ErrDeploymentQueueFull         = errors.New("deployment queue is full")
+	ErrInvalidDeploymentTransition = errors.New("invalid deployment state transition")
+	ErrDeploymentInterrupted       = errors.New("deployment interrupted by server restart")
 )
\ No newline at end of file
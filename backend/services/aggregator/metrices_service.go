@@ -2,49 +2,59 @@ package aggregator
 
 import (
 	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
 )
 
 // AggregatorMetricsService는 Aggregator 메트릭 관련 비즈니스 로직을 처리합니다
 type AggregatorMetricsService struct {
-	repo *repository.AggregatorRepository
+	repo      *repository.AggregatorRepository
+	costMeter *CostMeter
 }
 
 // NewAggregatorMetricsService는 새 AggregatorMetricsService 인스턴스를 생성합니다
-func NewAggregatorMetricsService(repo *repository.AggregatorRepository) *AggregatorMetricsService {
+func NewAggregatorMetricsService(repo *repository.AggregatorRepository, costMeter *CostMeter) *AggregatorMetricsService {
 	return &AggregatorMetricsService{
-		repo: repo,
+		repo:      repo,
+		costMeter: costMeter,
 	}
 }
 
 // UpdateStatus는 Aggregator의 상태를 업데이트합니다
-func (s *AggregatorMetricsService) UpdateStatus(aggregatorID string, userID int64, status string) error {
+func (s *AggregatorMetricsService) UpdateStatus(aggregatorID string, principal *authz.Principal, status string) error {
 	// 권한 확인
-	aggregator, err := s.repo.GetAggregatorByID(aggregatorID)
-	if err != nil {
+	if _, err := authorizeAggregator(s.repo, aggregatorID, principal, authz.ActionOperate); err != nil {
 		return err
 	}
-	if aggregator == nil || aggregator.UserID != userID {
-		return ErrAggregatorNotFound
-	}
 
 	return s.repo.UpdateAggregatorStatus(aggregatorID, status)
 }
 
 // UpdateMetrics는 Aggregator의 메트릭을 업데이트합니다
-func (s *AggregatorMetricsService) UpdateMetrics(aggregatorID string, userID int64, cpuUsage, memoryUsage, networkUsage float64) error {
+func (s *AggregatorMetricsService) UpdateMetrics(aggregatorID string, principal *authz.Principal, cpuUsage, memoryUsage, networkUsage float64) error {
 	// 권한 확인
-	aggregator, err := s.repo.GetAggregatorByID(aggregatorID)
-	if err != nil {
+	if _, err := authorizeAggregator(s.repo, aggregatorID, principal, authz.ActionOperate); err != nil {
 		return err
 	}
-	if aggregator == nil || aggregator.UserID != userID {
-		return ErrAggregatorNotFound
-	}
 
 	return s.repo.UpdateAggregatorMetrics(aggregatorID, cpuUsage, memoryUsage, networkUsage)
 }
 
-// GetStats는 사용자의 Aggregator 통계를 조회합니다
-func (s *AggregatorMetricsService) GetStats(userID int64) (map[string]interface{}, error) {
-	return s.repo.GetAggregatorStats(userID)
+// GetStats는 조직의 Aggregator 통계와 집계자별 비용 내역을 조회합니다
+func (s *AggregatorMetricsService) GetStats(orgID int64) (map[string]interface{}, error) {
+	stats, err := s.repo.GetAggregatorStats(orgID)
+	if err != nil {
+		return nil, err
+	}
+	if s.costMeter == nil {
+		return stats, nil
+	}
+
+	costs, err := s.costMeter.GetOrganizationCostSummary(orgID)
+	if err != nil {
+		return nil, err
+	}
+	// total_cost는 저장된 CurrentCost 합계 대신 현재 시점까지의 누적 비용을 사용
+	stats["total_cost"] = costs.TotalAccruedCost
+	stats["costs"] = costs
+	return stats, nil
 }
\ No newline at end of file
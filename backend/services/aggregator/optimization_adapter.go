@@ -16,19 +16,21 @@ func NewOptimizationServiceAdapter(service *services.OptimizationService) Optimi
 	}
 }
 
-// ValidatePythonEnvironment은 Python 환경을 검증합니다
-func (a *OptimizationServiceAdapter) ValidatePythonEnvironment() error {
-	return a.service.ValidatePythonEnvironment()
-}
-
-// ValidatePythonScript는 Python 스크립트를 검증합니다
-func (a *OptimizationServiceAdapter) ValidatePythonScript() error {
+// ValidateEnvironment는 Python 환경과 최적화 스크립트 존재 여부를 검증합니다
+func (a *OptimizationServiceAdapter) ValidateEnvironment() error {
+	if err := a.service.ValidatePythonEnvironment(); err != nil {
+		return err
+	}
 	return a.service.ValidatePythonScript()
 }
 
 // RunOptimization은 최적화를 실행합니다
 func (a *OptimizationServiceAdapter) RunOptimization(request OptimizationRequest) (interface{}, error) {
-	// 타입 변환: aggregator.OptimizationRequest -> services.OptimizationRequest
+	return a.service.RunOptimization(toServiceOptimizationRequest(request))
+}
+
+// toServiceOptimizationRequest는 aggregator.OptimizationRequest를 services.OptimizationRequest로 변환합니다
+func toServiceOptimizationRequest(request OptimizationRequest) services.OptimizationRequest {
 	serviceRequest := services.OptimizationRequest{
 		FederatedLearning: struct {
 			Name          string                `json:"name"`
@@ -46,15 +48,9 @@ func (a *OptimizationServiceAdapter) RunOptimization(request OptimizationRequest
 			Rounds:        request.FederatedLearning.Rounds,
 			ModelFileName: nil, // 기본값 nil
 		},
-		AggregatorConfig: struct {
-			MaxBudget  int `json:"maxBudget"`
-			MaxLatency int `json:"maxLatency"`
-			WeightBalance *int `json:"weightBalance,omitempty"`
-		}{
-			MaxBudget:  request.AggregatorConfig.MaxBudget,
-			MaxLatency: request.AggregatorConfig.MaxLatency,
-			WeightBalance: request.AggregatorConfig.WeightBalance,
-		},
+		AggregatorConfig:     request.AggregatorConfig,
+		PriceSnapshotVersion: request.PriceSnapshotVersion,
+
 	}
 
 	// Participants 변환
@@ -72,5 +68,5 @@ func (a *OptimizationServiceAdapter) RunOptimization(request OptimizationRequest
 		)
 	}
 
-	return a.service.RunOptimization(serviceRequest)
+	return serviceRequest
 }
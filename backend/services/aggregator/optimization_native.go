@@ -0,0 +1,28 @@
+package aggregator
+
+import (
+	"github.com/Mungge/Fleecy-Cloud/services/optimizer"
+)
+
+// NativeOptimizationService는 Go NSGA-II 최적화기로 집계자 배치 최적화를 수행합니다
+// Python 런타임이나 별도 DB 접속 정보 없이 기존 리포지토리로 가격/지연시간을 조회합니다
+type NativeOptimizationService struct {
+	optimizer *optimizer.Optimizer
+}
+
+// NewNativeOptimizationService는 새로운 NativeOptimizationService 인스턴스를 생성합니다
+func NewNativeOptimizationService(opt *optimizer.Optimizer) OptimizationService {
+	return &NativeOptimizationService{
+		optimizer: opt,
+	}
+}
+
+// ValidateEnvironment는 외부 런타임 의존성이 없으므로 항상 성공합니다
+func (s *NativeOptimizationService) ValidateEnvironment() error {
+	return nil
+}
+
+// RunOptimization은 최적화를 실행합니다
+func (s *NativeOptimizationService) RunOptimization(request OptimizationRequest) (interface{}, error) {
+	return s.optimizer.Optimize(toServiceOptimizationRequest(request))
+}
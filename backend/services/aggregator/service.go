@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -18,6 +19,7 @@ import (
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
 	"github.com/Mungge/Fleecy-Cloud/services"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
 	"github.com/Mungge/Fleecy-Cloud/utils"
 )
 
@@ -27,8 +29,30 @@ type AggregatorService struct {
 	flRepo          *repository.FederatedLearningRepository
 	sshKeypairRepo  *repository.SSHKeypairRepository
 	cloudRepo       *repository.CloudRepository
+	deploymentRepo  *repository.AggregatorDeploymentRepository
 	progressTracker *SSEProgressTracker
 	mlflowClient    *MLflowClient
+
+	// 비동기 배포 작업 큐 (배포 작업 ID 전달)
+	deploymentQueue chan string
+	// 비동기 삭제 작업 큐 (집계자 ID 전달, 배포 워커가 함께 처리)
+	destroyQueue chan string
+
+	// 인프라 삭제 전 체크포인트 보관 (설정하지 않으면 건너뜀)
+	checkpointCollector CheckpointCollector
+
+	// 배포 작업의 프로비저닝 단계 (기본: deployWithTerraformContext, 테스트에서 Terraform 없이 워커를 검증할 때 교체)
+	deploy func(ctx context.Context, aggregator *models.Aggregator, deployment *models.AggregatorDeployment) error
+}
+
+// CheckpointCollector는 집계자 삭제 전에 라운드 체크포인트를 모델 레지스트리로 옮기는 인터페이스입니다
+type CheckpointCollector interface {
+	CollectForAggregator(ctx context.Context, aggregatorID string) error
+}
+
+// SetCheckpointCollector는 집계자 삭제 전에 사용할 체크포인트 수집기를 지정합니다
+func (s *AggregatorService) SetCheckpointCollector(collector CheckpointCollector) {
+	s.checkpointCollector = collector
 }
 
 // NewAggregatorService는 새 AggregatorService 인스턴스를 생성합니다
@@ -37,6 +61,7 @@ func NewAggregatorService(
     flRepo *repository.FederatedLearningRepository, 
     sshKeypairRepo *repository.SSHKeypairRepository, 
     cloudRepo *repository.CloudRepository,
+    deploymentRepo *repository.AggregatorDeploymentRepository,
     mlflowURL string,  // MLflow URL 파라미터 추가
 ) *AggregatorService {
     var mlflowClient *MLflowClient
@@ -44,23 +69,29 @@ func NewAggregatorService(
         mlflowClient = NewMLflowClient(mlflowURL)
     }
 
-    return &AggregatorService{
+    s := &AggregatorService{
         repo:            repo,
         flRepo:          flRepo,
         sshKeypairRepo:  sshKeypairRepo,
         cloudRepo:       cloudRepo,
+        deploymentRepo:  deploymentRepo,
         progressTracker: NewWebSocketProgressTracker(),
         mlflowClient:    mlflowClient,
+        deploymentQueue: make(chan string, deploymentQueueSize),
+        destroyQueue:    make(chan string, deploymentQueueSize),
     }
+    s.deploy = s.deployWithTerraformContext
+    return s
 }
 
 // CreateAggregatorInput Aggregator 생성 입력
 type CreateAggregatorInput struct {
-	Name          string `json:"name" validate:"required"`
-	Algorithm     string `json:"algorithm" validate:"required"`
-	Storage       string `json:"storage" validate:"required"`
-	UserID        int64  `json:"user_id" validate:"required"`
-	CloudProvider string `json:"cloud_provider" validate:"required,oneof=aws gcp"`
+	Name           string `json:"name" validate:"required"`
+	Algorithm      string `json:"algorithm" validate:"required"`
+	Storage        string `json:"storage" validate:"required"`
+	UserID         int64  `json:"user_id" validate:"required"`
+	OrganizationID int64  `json:"organization_id" validate:"required"`
+	CloudProvider  string `json:"cloud_provider" validate:"required,oneof=aws gcp"`
 
 	// 공통 필드
 	ProjectName  string `json:"project_name" validate:"required"`
@@ -73,6 +104,7 @@ type CreateAggregatorInput struct {
 // CreateAggregatorResult Aggregator 생성 결과
 type CreateAggregatorResult struct {
 	AggregatorID    string `json:"aggregator_id"`
+	DeploymentID    string `json:"deployment_id"`
 	Status          string `json:"status"`
 	TerraformStatus string `json:"terraform_status"`
 }
@@ -86,11 +118,8 @@ type OptimizationRequest struct {
 		Rounds       int           `json:"rounds"`
 		Participants []Participant `json:"participants"`
 	} `json:"federatedLearning"`
-	AggregatorConfig struct {
-		MaxBudget  int `json:"maxBudget"`
-		MaxLatency int `json:"maxLatency"`
-		WeightBalance *int `json:"weightBalance,omitempty"`
-	} `json:"aggregatorConfig"`
+	AggregatorConfig     services.AggregatorConfig `json:"aggregatorConfig"`
+	PriceSnapshotVersion *int                      `json:"priceSnapshotVersion,omitempty"`
 }
 
 // Participant 참여자 정보
@@ -102,9 +131,9 @@ type Participant struct {
 }
 
 // OptimizationService 인터페이스 (기존 services.OptimizationService 대체)
+// 기본 구현은 Go NSGA-II 최적화기이며, Python 스크립트 구현은 선택적 백엔드로 사용할 수 있습니다
 type OptimizationService interface {
-	ValidatePythonEnvironment() error
-	ValidatePythonScript() error
+	ValidateEnvironment() error
 	RunOptimization(request OptimizationRequest) (interface{}, error)
 }
 
@@ -130,8 +159,8 @@ func (s *AggregatorService) CreateAggregator(input CreateAggregatorInput) (*Crea
 // CreateAggregatorWithContext는 컨텍스트를 지원하는 새로운 Aggregator 생성 메서드입니다
 func (s *AggregatorService) CreateAggregatorWithContext(ctx context.Context, input CreateAggregatorInput) (*CreateAggregatorResult, error) {
 
-	// 동일한 사용자의 동일한 이름 집계자가 이미 존재하는지 확인
-	existingAggregators, err := s.repo.GetAggregatorsByUserID(input.UserID)
+	// 동일한 조직의 동일한 이름 집계자가 이미 존재하는지 확인
+	existingAggregators, err := s.repo.GetAggregatorsByOrganizationID(input.OrganizationID)
 	if err != nil {
 		return nil, fmt.Errorf("기존 집계자 조회 실패: %w", err)
 	}
@@ -154,6 +183,7 @@ func (s *AggregatorService) CreateAggregatorWithContext(ctx context.Context, inp
 	aggregator := &models.Aggregator{
 		ID:            uuid.New().String(),
 		UserID:        input.UserID,
+		OrganizationID: input.OrganizationID,
 		Name:          input.Name,
 		Status:        "creating",
 		Algorithm:     input.Algorithm,
@@ -172,97 +202,159 @@ func (s *AggregatorService) CreateAggregatorWithContext(ctx context.Context, inp
 		return nil, err
 	}
 
-	// Terraform 배포 시작 (동기) - 사용자가 결과를 즉시 확인 가능
-	log.Printf("Starting synchronous Terraform deployment for aggregator %s", aggregator.ID)
-
-	if err := s.deployWithTerraformContext(ctx, aggregator); err != nil {
-		log.Printf("Terraform deployment failed for aggregator %s: %v", aggregator.ID, err)
-
-		// 배포 실패 시 상태 업데이트
-		aggregator.Status = "failed"
+	// 배포 작업 생성 후 워커 풀에 전달 (Terraform 배포는 백그라운드에서 진행)
+	deployment := &models.AggregatorDeployment{
+		ID:           uuid.New().String(),
+		AggregatorID: aggregator.ID,
+		UserID:       aggregator.UserID,
+		State:        models.DeploymentStateCreating,
+		Stage:        models.DeploymentStageOf(models.DeploymentStateCreating),
+		TotalStages:  models.DeploymentTotalStages,
+		Message:      "배포 대기 중...",
+	}
+	if err := s.deploymentRepo.CreateDeployment(deployment); err != nil {
 		if updateErr := s.repo.UpdateAggregatorStatus(aggregator.ID, "failed"); updateErr != nil {
 			log.Printf("Failed to update aggregator status to failed: %v", updateErr)
 		}
-
-		// 사용자 친화적인 에러 메시지 생성
-		var userMessage string
-		if strings.Contains(err.Error(), "active AWS connection not found") {
-			userMessage = "AWS 클라우드 연결이 설정되지 않았습니다. 먼저 클라우드 인증 정보를 등록해주세요."
-		} else if strings.Contains(err.Error(), "active GCP connection not found") {
-			userMessage = "GCP 클라우드 연결이 설정되지 않았습니다. 먼저 클라우드 인증 정보를 등록해주세요."
-		} else if strings.Contains(err.Error(), "AWS credentials") {
-			userMessage = "AWS 자격증명이 올바르지 않습니다. 클라우드 인증 정보를 다시 확인해주세요."
-		} else {
-			userMessage = fmt.Sprintf("집계자 배포 실패: %v", err)
-		}
-
-		return nil, fmt.Errorf("%s", userMessage)
+		return nil, fmt.Errorf("배포 작업 생성 실패: %w", err)
 	}
 
-	// 배포 성공 시 상태 업데이트
-	log.Printf("Terraform deployment successful for aggregator %s", aggregator.ID)
-	aggregator.Status = "running"
-	if updateErr := s.repo.UpdateAggregatorStatus(aggregator.ID, "running"); updateErr != nil {
-		log.Printf("Failed to update aggregator status to running: %v", updateErr)
-		// 상태 업데이트 실패해도 배포는 성공했으므로 계속 진행
+	if err := s.enqueueDeployment(deployment.ID); err != nil {
+		s.failDeployment(deployment, err)
+		return nil, err
 	}
 
+	log.Printf("Queued Terraform deployment %s for aggregator %s", deployment.ID, aggregator.ID)
+
 	// 결과 반환
 	result := &CreateAggregatorResult{
 		AggregatorID:    aggregator.ID,
-		Status:          aggregator.Status, // 실제 상태 반환
-		TerraformStatus: "completed",       // 배포 완료
+		DeploymentID:    deployment.ID,
+		Status:          aggregator.Status, // creating
+		TerraformStatus: "queued",
 	}
 
 	return result, nil
 }
 
-// GetAggregatorByID는 ID로 Aggregator를 조회하고 권한을 확인합니다
-func (s *AggregatorService) GetAggregatorByID(id string, userID int64) (*models.Aggregator, error) {
-	aggregator, err := s.repo.GetAggregatorByID(id)
+// GetAggregatorByID는 ID로 Aggregator를 조회하고 조회 권한을 확인합니다
+func (s *AggregatorService) GetAggregatorByID(id string, principal *authz.Principal) (*models.Aggregator, error) {
+	aggregator, err := authorizeAggregator(s.repo, id, principal, authz.ActionView)
+	if errors.Is(err, ErrAggregatorNotFound) || errors.Is(err, ErrAggregatorAccessDenied) {
+		return nil, nil // 권한 없음 또는 존재하지 않음
+	}
+	return aggregator, err
+}
+
+// DeleteAggregator는 Aggregator 인프라 삭제(terraform destroy) 작업을 배포 워커에 등록합니다
+// destroy는 요청과 분리된 컨텍스트에서 실행되며 진행 상황은 진행 상황 스트림으로 전달됩니다
+// 반환하는 상태는 deleting(등록됨 또는 이미 진행 중) 또는 terminated(이미 삭제됨)입니다
+func (s *AggregatorService) DeleteAggregator(id string, principal *authz.Principal) (string, error) {
+	// 권한 확인
+	aggregator, err := authorizeAggregator(s.repo, id, principal, authz.ActionManage)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	if aggregator.Status == models.AggregatorStatusTerminated || aggregator.Status == models.AggregatorStatusDeleting {
+		return aggregator.Status, nil
 	}
 
-	if aggregator == nil || aggregator.UserID != userID {
-		return nil, nil // 권한 없음 또는 존재하지 않음
+	// 인프라가 생성되기 전에 실패한 집계자가 아니면 destroy에 상태 파일이 필요
+	if aggregator.Status != "failed" && !utils.HasTerraformState(utils.GetTerraformWorkspaceDir(aggregator.ID)) {
+		return "", ErrTerraformStateNotFound
 	}
 
-	return aggregator, nil
+	marked, err := s.repo.MarkAggregatorDeleting(aggregator.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to mark aggregator deleting: %v", err)
+	}
+	if !marked {
+		// 동시에 들어온 다른 삭제 요청이 이미 등록함
+		return models.AggregatorStatusDeleting, nil
+	}
+
+	if err := s.enqueueDestroy(aggregator.ID); err != nil {
+		if restoreErr := s.repo.UpdateAggregatorStatus(aggregator.ID, aggregator.Status); restoreErr != nil {
+			log.Printf("Failed to restore aggregator status %s: %v", aggregator.ID, restoreErr)
+		}
+		return "", err
+	}
+
+	s.progressTracker.SendProgress(aggregator.ID, 0, "삭제 작업 대기 중...")
+	return models.AggregatorStatusDeleting, nil
 }
 
-// DeleteAggregator는 Aggregator를 삭제합니다
-func (s *AggregatorService) DeleteAggregator(id string, userID int64) error {
-	// 권한 확인
-	aggregator, err := s.GetAggregatorByID(id, userID)
-	if err != nil {
-		return err
+// destroyAggregator는 Aggregator 인프라를 terraform destroy로 삭제하고 terminated 상태로 변경합니다
+// destroy가 성공한 경우에만 상태를 terminated로 변경하며, 실패 시 상태 파일을 보관하고 failed로 기록해 재시도할 수 있게 합니다
+func (s *AggregatorService) destroyAggregator(ctx context.Context, aggregator *models.Aggregator) error {
+	workspaceDir := utils.GetTerraformWorkspaceDir(aggregator.ID)
+
+	log.Printf("[%s] 1/5 Terraform 상태 확인 중...", aggregator.ID)
+	s.progressTracker.SendProgress(aggregator.ID, 1, "Terraform 상태 확인 중...")
+
+	if !utils.HasTerraformState(workspaceDir) {
+		// 인프라가 생성되기 전에 실패한 집계자는 삭제할 리소스가 없음 (DeleteAggregator에서 확인)
+		log.Printf("[%s] Terraform 상태가 없어 destroy를 건너뜁니다", aggregator.ID)
+	} else {
+		// 인스턴스와 함께 사라지기 전에 체크포인트를 모델 레지스트리로 복사 (실패해도 삭제는 진행)
+		if s.checkpointCollector != nil {
+			log.Printf("[%s] 2/5 체크포인트 보관 중...", aggregator.ID)
+			s.progressTracker.SendProgress(aggregator.ID, 2, "모델 체크포인트 보관 중...")
+			if err := s.checkpointCollector.CollectForAggregator(ctx, aggregator.ID); err != nil {
+				log.Printf("[%s] 체크포인트 보관 실패 (삭제 계속 진행): %v", aggregator.ID, err)
+			}
+		}
+
+		// 인스턴스가 사라지므로 공유 SSH 연결 정리
+		services.CloseAggregatorConnection(aggregator.ID)
+
+		log.Printf("[%s] 3/5 Terraform destroy 실행 중...", aggregator.ID)
+		s.progressTracker.SendProgress(aggregator.ID, 3, "Terraform destroy 실행 중... (시간이 소요될 수 있습니다)")
+
+		if err := utils.DestroyWithTerraformContext(ctx, workspaceDir); err != nil {
+			log.Printf("Terraform destroy failed for aggregator %s: %v", aggregator.ID, err)
+			s.progressTracker.SendError(aggregator.ID, 3, "Terraform destroy 실패", err)
+			if statusErr := s.repo.UpdateAggregatorStatus(aggregator.ID, "failed"); statusErr != nil {
+				log.Printf("Failed to update aggregator status to failed: %v", statusErr)
+			}
+			return fmt.Errorf("%w: %v", ErrTerraformDestroyFailed, err)
+		}
 	}
-	if aggregator == nil {
-		return ErrAggregatorNotFound
+
+	s.progressTracker.SendProgress(aggregator.ID, 5, "집계자 상태 업데이트 중...")
+	if err := s.repo.UpdateAggregatorStatus(aggregator.ID, models.AggregatorStatusTerminated); err != nil {
+		s.progressTracker.SendError(aggregator.ID, 5, "상태 업데이트 실패", err)
+		return fmt.Errorf("failed to mark aggregator terminated: %v", err)
 	}
 
-	return s.repo.DeleteAggregator(id)
+	// destroy가 완료되었으므로 작업 디렉토리 정리
+	if err := os.RemoveAll(workspaceDir); err != nil {
+		log.Printf("Failed to cleanup workspace directory %s: %v", workspaceDir, err)
+	}
+
+	s.progressTracker.SendSuccess(aggregator.ID, "집계자 인프라가 성공적으로 삭제되었습니다")
+	log.Printf("Terraform destroy successful for aggregator %s", aggregator.ID)
+	return nil
 }
 
-// GetAggregatorsByUser는 사용자의 모든 Aggregator를 조회합니다
-func (s *AggregatorService) GetAggregatorsByUser(userID int64) ([]*models.Aggregator, error) {
-	return s.repo.GetAggregatorsByUserID(userID)
+// GetAggregatorsByOrganization은 조직의 모든 Aggregator를 조회합니다
+func (s *AggregatorService) GetAggregatorsByOrganization(orgID int64) ([]*models.Aggregator, error) {
+	return s.repo.GetAggregatorsByOrganizationID(orgID)
 }
 
 // deployWithTerraformContext는 컨텍스트를 지원하는 Terraform 배포 메서드입니다
-func (s *AggregatorService) deployWithTerraformContext(ctx context.Context, aggregator *models.Aggregator) error {
+// 각 단계 진입 시 배포 작업의 상태를 전이시키고 aggregator_deployments 테이블에 기록합니다
+func (s *AggregatorService) deployWithTerraformContext(ctx context.Context, aggregator *models.Aggregator, deployment *models.AggregatorDeployment) error {
 	log.Printf("[%s] 1/5 클라우드 연결 정보 조회 중...", aggregator.ID)
-	s.progressTracker.SendProgress(aggregator.ID, 1, "클라우드 연결 정보 조회 중...")
+	s.updateDeploymentMessage(deployment, "클라우드 연결 정보 조회 중...")
 
 	// 컨텍스트 취소 확인
 	if ctx.Err() != nil {
-		s.progressTracker.SendError(aggregator.ID, 1, "배포 취소됨", ctx.Err())
 		return fmt.Errorf("deployment cancelled: %v", ctx.Err())
 	}
 
-	// 사용자의 클라우드 연결 정보 가져오기
-	cloudConnections, err := s.cloudRepo.GetCloudConnectionsByUserID(aggregator.UserID)
+	// 조직의 클라우드 연결 정보 가져오기
+	cloudConnections, err := s.cloudRepo.GetCloudConnectionsByOrganizationID(aggregator.OrganizationID)
 	if err != nil {
 		return fmt.Errorf("failed to get cloud connections: %v", err)
 	}
@@ -277,11 +369,11 @@ func (s *AggregatorService) deployWithTerraformContext(ctx context.Context, aggr
 	}
 
 	if cloudConn == nil {
-		return fmt.Errorf("no %s cloud connection found for user %d", aggregator.CloudProvider, aggregator.UserID)
+		return fmt.Errorf("no %s cloud connection found for organization %d", aggregator.CloudProvider, aggregator.OrganizationID)
 	}
 
-	log.Printf("[%s] 2/5 클라우드 자격증명 파싱 중...", aggregator.ID)
-	s.progressTracker.SendProgress(aggregator.ID, 2, "클라우드 자격증명 파싱 중...")
+	log.Printf("[%s] 1/5 클라우드 자격증명 파싱 중...", aggregator.ID)
+	s.updateDeploymentMessage(deployment, "클라우드 자격증명 파싱 중...")
 
 	// 자격증명 파싱
 	var awsAccessKey, awsSecretKey string
@@ -329,15 +421,16 @@ func (s *AggregatorService) deployWithTerraformContext(ctx context.Context, aggr
 		}
 	}
 
-	log.Printf("[%s] 3/5 SSH 키페어 생성/조회 중...", aggregator.ID)
-	s.progressTracker.SendProgress(aggregator.ID, 3, "SSH 키페어 생성/조회 중...")
-
 	// 컨텍스트 취소 확인
 	if ctx.Err() != nil {
-		s.progressTracker.SendError(aggregator.ID, 3, "배포 취소됨", ctx.Err())
 		return fmt.Errorf("deployment cancelled: %v", ctx.Err())
 	}
 
+	log.Printf("[%s] 2/5 SSH 키페어 생성/조회 중...", aggregator.ID)
+	if err := s.transitionDeployment(deployment, models.DeploymentStateKeypair, "SSH 키페어 생성/조회 중..."); err != nil {
+		return err
+	}
+
 	// 클라우드 키페어 서비스 초기화
 	keypairService := services.NewCloudKeypairService(s.cloudRepo)
 
@@ -349,7 +442,7 @@ func (s *AggregatorService) deployWithTerraformContext(ctx context.Context, aggr
 	switch strings.ToLower(aggregator.CloudProvider) {
 	case "aws":
 		log.Printf("Creating AWS keypair for aggregator %s in region %s", aggregator.ID, aggregator.Region)
-		keypairInfo, err := keypairService.GetOrCreateAWSKeypair(aggregator.UserID, aggregator.Region, keyName)
+		keypairInfo, err := keypairService.GetOrCreateAWSKeypair(aggregator.OrganizationID, aggregator.Region, keyName)
 		if err != nil {
 			return fmt.Errorf("failed to get or create AWS keypair: %v", err)
 		}
@@ -364,7 +457,7 @@ func (s *AggregatorService) deployWithTerraformContext(ctx context.Context, aggr
 			return fmt.Errorf("failed to generate SSH keypair for GCP: %v", err)
 		}
 
-		_, err = keypairService.GetOrCreateGCPKeypair(aggregator.UserID, keyName, keyPair.PublicKey, keyPair.PrivateKey)
+		_, err = keypairService.GetOrCreateGCPKeypair(aggregator.OrganizationID, keyName, keyPair.PublicKey, keyPair.PrivateKey)
 		if err != nil {
 			return fmt.Errorf("failed to get or create GCP keypair: %v", err)
 		}
@@ -406,15 +499,16 @@ func (s *AggregatorService) deployWithTerraformContext(ctx context.Context, aggr
         }
     }
 
-	log.Printf("[%s] 4/5 Terraform 워크스페이스 생성 중...", aggregator.ID)
-	s.progressTracker.SendProgress(aggregator.ID, 4, "Terraform 워크스페이스 생성 중...")
-
 	// 컨텍스트 취소 확인
 	if ctx.Err() != nil {
-		s.progressTracker.SendError(aggregator.ID, 4, "배포 취소됨", ctx.Err())
 		return fmt.Errorf("deployment cancelled: %v", ctx.Err())
 	}
 
+	log.Printf("[%s] 3/5 Terraform 워크스페이스 생성 중...", aggregator.ID)
+	if err := s.transitionDeployment(deployment, models.DeploymentStateWorkspace, "Terraform 워크스페이스 생성 중..."); err != nil {
+		return err
+	}
+
 	// Terraform 설정 생성
 	config := utils.TerraformConfig{
 		CloudProvider: aggregator.CloudProvider,
@@ -440,37 +534,24 @@ func (s *AggregatorService) deployWithTerraformContext(ctx context.Context, aggr
 		return err
 	}
 
-	s.progressTracker.SendProgress(aggregator.ID, 5, "Terraform 배포 실행 중... (시간이 소요될 수 있습니다)")
-
 	// 컨텍스트 취소 확인
 	if ctx.Err() != nil {
-		s.progressTracker.SendError(aggregator.ID, 5, "배포 취소됨", ctx.Err())
 		return fmt.Errorf("deployment cancelled: %v", ctx.Err())
 	}
 
+	log.Printf("[%s] 4/5 Terraform 배포 실행 중...", aggregator.ID)
+	if err := s.transitionDeployment(deployment, models.DeploymentStateApplying, "Terraform 배포 실행 중... (시간이 소요될 수 있습니다)"); err != nil {
+		return err
+	}
+
 	// Terraform 배포 실행 (terraform-exec 사용, 컨텍스트 지원)
 	result, err := utils.DeployWithTerraformContext(ctx, workspaceDir)
 
-	// 배포 완료 후 workspace 디렉토리 정리 (성공/실패 상관없이)
-	defer func() {
-		// Terraform 상태 파일도 함께 정리
-		utils.CleanupTerraformState(workspaceDir)
-		
-		if cleanupErr := os.RemoveAll(workspaceDir); cleanupErr != nil {
-			log.Printf("Failed to cleanup workspace directory %s: %v", workspaceDir, cleanupErr)
-		} else {
-			log.Printf("Cleaned up workspace directory: %s", workspaceDir)
-		}
-	}()
-
+	// 작업 디렉토리와 상태 파일은 이후 destroy를 위해 보관합니다 (실패 시에도 부분 생성된 리소스 정리에 필요)
 	if err != nil {
-		s.progressTracker.SendError(aggregator.ID, 5, "Terraform 배포 실패", err)
-		return err
+		return fmt.Errorf("%w: %v", ErrTerraformDeployFailed, err)
 	}
 
-	// 배포 성공
-	s.progressTracker.SendSuccess(aggregator.ID, "집계자 배포가 성공적으로 완료되었습니다")
-
 	// Terraform 결과 로깅
 	log.Printf("[%s] Terraform deployment result: InstanceID=%s, PublicIP=%s, PrivateIP=%s", 
 		aggregator.ID, result.InstanceID, result.PublicIP, result.PrivateIP)
@@ -489,9 +570,28 @@ func (s *AggregatorService) deployWithTerraformContext(ctx context.Context, aggr
 		log.Printf("Updated aggregator %s with IP info: Public=%s, Private=%s", aggregator.ID, result.PublicIP, result.PrivateIP)
 	}
 
+	// 프로비저닝 직후 호스트 키 고정 (실패해도 첫 SSH 접속 시 고정되므로 배포는 계속 진행)
+	if privateKey != "" && result.PublicIP != "" {
+		s.pinAggregatorHostKey(ctx, aggregator.ID, result.PublicIP)
+	}
+
 	return nil
 }
 
+// pinAggregatorHostKey는 새로 프로비저닝한 집계자의 SSH 호스트 키를 고정합니다
+func (s *AggregatorService) pinAggregatorHostKey(ctx context.Context, aggregatorID, publicIP string) {
+	captureCtx, cancel := context.WithTimeout(ctx, hostKeyCaptureTimeout)
+	defer cancel()
+
+	sshKeypairService := services.NewSSHKeypairService(s.sshKeypairRepo)
+	keypair, err := sshKeypairService.CaptureHostKey(captureCtx, aggregatorID, publicIP)
+	if err != nil {
+		log.Printf("[%s] SSH 호스트 키 고정 실패 (첫 접속 시 고정): %v", aggregatorID, err)
+		return
+	}
+	log.Printf("[%s] SSH 호스트 키 고정 완료: %s", aggregatorID, keypair.HostKeyFingerprint)
+}
+
 // HandleWebSocketProgress WebSocket 진행 상황 연결 처리
 func (s *AggregatorService) HandleWebSocketProgress(w http.ResponseWriter, r *http.Request, aggregatorID string) {
 	s.progressTracker.HandleWebSocket(w, r, aggregatorID)
@@ -3,6 +3,7 @@ package aggregator
 import (
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/authz"
 )
 
 // AggregatorTrainingService는 Aggregator 학습 관련 비즈니스 로직을 처리합니다
@@ -18,15 +19,11 @@ func NewAggregatorTrainingService(repo *repository.AggregatorRepository) *Aggreg
 }
 
 // GetTrainingHistory는 Aggregator의 학습 히스토리를 조회합니다
-func (s *AggregatorTrainingService) GetTrainingHistory(aggregatorID string, userID int64) ([]*models.TrainingRound, error) {
+func (s *AggregatorTrainingService) GetTrainingHistory(aggregatorID string, principal *authz.Principal) ([]*models.TrainingRound, error) {
 	// 권한 확인
-	aggregator, err := s.repo.GetAggregatorByID(aggregatorID)
-	if err != nil {
+	if _, err := authorizeAggregator(s.repo, aggregatorID, principal, authz.ActionView); err != nil {
 		return nil, err
 	}
-	if aggregator == nil || aggregator.UserID != userID {
-		return nil, ErrAggregatorNotFound
-	}
 
 	return s.repo.GetTrainingRoundsByAggregatorID(aggregatorID)
 }
\ No newline at end of file
@@ -5,12 +5,25 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 )
 
+const (
+	// defaultProgressHistorySize는 집계자별로 보관하는 과거 진행 메시지 수입니다
+	defaultProgressHistorySize = 64
+	// defaultProgressHeartbeat는 SSE 연결 유지를 위한 하트비트 간격입니다
+	defaultProgressHeartbeat = 15 * time.Second
+	// defaultProgressRetention은 구독자가 없는 집계자의 이력을 보관하는 시간입니다
+	defaultProgressRetention = time.Hour
+	// subscriberBufferSize는 구독자별 전송 대기 버퍼 크기입니다
+	subscriberBufferSize = 32
+)
+
 // ProgressMessage 진행 상황 메시지
 type ProgressMessage struct {
+	EventID      uint64 `json:"event_id"`
 	AggregatorID string `json:"aggregator_id"`
 	Stage        int    `json:"stage"`        // 1-5
 	TotalStages  int    `json:"total_stages"` // 5
@@ -20,46 +33,206 @@ type ProgressMessage struct {
 	Timestamp    int64  `json:"timestamp"`
 }
 
-// SSEProgressTracker Server-Sent Events를 통한 진행 상황 추적
+// ProgressSubscription 집계자 진행 상황 구독
+// Messages 채널은 구독 해제 또는 느린 구독자 정리 시 닫힙니다
+type ProgressSubscription struct {
+	aggregatorID string
+	messages     chan ProgressMessage
+	closeOnce    sync.Once
+}
+
+// Messages 구독 중 발생하는 진행 메시지 채널
+func (s *ProgressSubscription) Messages() <-chan ProgressMessage {
+	return s.messages
+}
+
+func (s *ProgressSubscription) close() {
+	s.closeOnce.Do(func() { close(s.messages) })
+}
+
+// progressTopic 집계자별 구독자 목록과 이력 링 버퍼
+type progressTopic struct {
+	subscribers  map[*ProgressSubscription]struct{}
+	history      []ProgressMessage // 링 버퍼
+	next         int               // 다음에 기록할 위치
+	size         int               // 현재 보관 중인 메시지 수
+	lastActivity time.Time
+}
+
+// replaySince는 lastEventID 이후의 이력을 오래된 순서로 반환합니다
+func (t *progressTopic) replaySince(lastEventID uint64) []ProgressMessage {
+	replay := make([]ProgressMessage, 0, t.size)
+	start := (t.next - t.size + len(t.history)) % len(t.history)
+	for i := 0; i < t.size; i++ {
+		msg := t.history[(start+i)%len(t.history)]
+		if msg.EventID > lastEventID {
+			replay = append(replay, msg)
+		}
+	}
+	return replay
+}
+
+func (t *progressTopic) append(msg ProgressMessage) {
+	t.history[t.next] = msg
+	t.next = (t.next + 1) % len(t.history)
+	if t.size < len(t.history) {
+		t.size++
+	}
+}
+
+// SSEProgressTracker Server-Sent Events를 통한 진행 상황 pub/sub 허브
+// 집계자별로 여러 구독자를 지원하며, 최근 메시지를 링 버퍼에 보관해 재연결 시 재전송합니다
 type SSEProgressTracker struct {
-	connections map[string]http.ResponseWriter
-	mutex       sync.RWMutex
+	topics      map[string]*progressTopic
+	lastEventID uint64 // 토픽 정리 후에도 단조 증가하도록 허브 전체에서 공유
+	mutex       sync.Mutex
+	historySize int
+	heartbeat   time.Duration
+	retention   time.Duration
+	now         func() time.Time
 }
 
-// NewWebSocketProgressTracker 새 SSE 진행 상황 추적기 생성
+// NewWebSocketProgressTracker 기본 설정으로 새 SSE 진행 상황 허브 생성
 func NewWebSocketProgressTracker() *SSEProgressTracker {
+	return NewSSEProgressTracker(defaultProgressHistorySize, defaultProgressHeartbeat)
+}
+
+// NewSSEProgressTracker 이력 크기와 하트비트 간격을 지정해 새 SSE 진행 상황 허브 생성
+func NewSSEProgressTracker(historySize int, heartbeat time.Duration) *SSEProgressTracker {
+	if historySize <= 0 {
+		historySize = defaultProgressHistorySize
+	}
+	if heartbeat <= 0 {
+		heartbeat = defaultProgressHeartbeat
+	}
 	return &SSEProgressTracker{
-		connections: make(map[string]http.ResponseWriter),
+		topics:      make(map[string]*progressTopic),
+		historySize: historySize,
+		heartbeat:   heartbeat,
+		retention:   defaultProgressRetention,
+		now:         time.Now,
+	}
+}
+
+// topicLocked는 집계자의 토픽을 반환하며 없으면 생성합니다 (mutex 보유 상태에서 호출)
+func (tracker *SSEProgressTracker) topicLocked(aggregatorID string) *progressTopic {
+	topic, exists := tracker.topics[aggregatorID]
+	if !exists {
+		topic = &progressTopic{
+			subscribers: make(map[*ProgressSubscription]struct{}),
+			history:     make([]ProgressMessage, tracker.historySize),
+		}
+		tracker.topics[aggregatorID] = topic
 	}
+	topic.lastActivity = tracker.now()
+	return topic
+}
+
+// pruneLocked는 구독자가 없고 보관 기간이 지난 토픽을 정리합니다 (mutex 보유 상태에서 호출)
+func (tracker *SSEProgressTracker) pruneLocked() {
+	cutoff := tracker.now().Add(-tracker.retention)
+	for id, topic := range tracker.topics {
+		if len(topic.subscribers) == 0 && topic.lastActivity.Before(cutoff) {
+			delete(tracker.topics, id)
+		}
+	}
+}
+
+// Subscribe는 집계자의 진행 상황을 구독하고 lastEventID 이후의 이력을 함께 반환합니다
+// lastEventID가 0이면 보관 중인 전체 이력을 반환합니다
+func (tracker *SSEProgressTracker) Subscribe(aggregatorID string, lastEventID uint64) (*ProgressSubscription, []ProgressMessage) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.pruneLocked()
+	topic := tracker.topicLocked(aggregatorID)
+
+	sub := &ProgressSubscription{
+		aggregatorID: aggregatorID,
+		messages:     make(chan ProgressMessage, subscriberBufferSize),
+	}
+	topic.subscribers[sub] = struct{}{}
+
+	return sub, topic.replaySince(lastEventID)
+}
+
+// Unsubscribe는 구독을 해제하고 메시지 채널을 닫습니다 (여러 번 호출해도 안전)
+func (tracker *SSEProgressTracker) Unsubscribe(sub *ProgressSubscription) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if topic, exists := tracker.topics[sub.aggregatorID]; exists {
+		delete(topic.subscribers, sub)
+		topic.lastActivity = tracker.now()
+	}
+	sub.close()
+}
+
+// SubscriberCount는 집계자의 현재 구독자 수를 반환합니다
+func (tracker *SSEProgressTracker) SubscriberCount(aggregatorID string) int {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if topic, exists := tracker.topics[aggregatorID]; exists {
+		return len(topic.subscribers)
+	}
+	return 0
 }
 
 // HandleWebSocket SSE 연결 처리
+// Last-Event-ID 헤더(또는 lastEventId 쿼리)가 있으면 그 이후의 메시지만 재전송합니다
 func (tracker *SSEProgressTracker) HandleWebSocket(w http.ResponseWriter, r *http.Request, aggregatorID string) {
 	// SSE 헤더 설정
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	
-	tracker.mutex.Lock()
-	tracker.connections[aggregatorID] = w
-	tracker.mutex.Unlock()
 
-	log.Printf("SSE connected for aggregator: %s", aggregatorID)
+	sub, replay := tracker.Subscribe(aggregatorID, parseLastEventID(r))
+	defer tracker.Unsubscribe(sub)
 
-	// 연결 유지 (컨텍스트가 취소될 때까지)
-	<-r.Context().Done()
-	log.Printf("SSE connection closed for aggregator %s", aggregatorID)
+	log.Printf("SSE connected for aggregator: %s (replay=%d)", aggregatorID, len(replay))
 
-	// 연결 정리
-	tracker.mutex.Lock()
-	delete(tracker.connections, aggregatorID)
-	tracker.mutex.Unlock()
+	// 헤더를 즉시 전송해 클라이언트가 연결을 인식하도록 함
+	w.WriteHeader(http.StatusOK)
+	flush(w)
+
+	for _, msg := range replay {
+		if err := writeProgressEvent(w, msg); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(tracker.heartbeat)
+	defer heartbeat.Stop()
+
+	// 모든 쓰기는 요청 고루틴에서만 수행
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Printf("SSE connection closed for aggregator %s", aggregatorID)
+			return
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				// 느린 구독자로 정리됨 - 클라이언트는 Last-Event-ID로 재연결
+				return
+			}
+			if err := writeProgressEvent(w, msg); err != nil {
+				log.Printf("Failed to send SSE message: %v", err)
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flush(w)
+		}
+	}
 }
 
 // SendProgress 진행 상황 전송
 func (tracker *SSEProgressTracker) SendProgress(aggregatorID string, stage int, message string) {
-	tracker.sendMessage(ProgressMessage{
+	tracker.publish(ProgressMessage{
 		AggregatorID: aggregatorID,
 		Stage:        stage,
 		TotalStages:  5,
@@ -71,7 +244,7 @@ func (tracker *SSEProgressTracker) SendProgress(aggregatorID string, stage int,
 
 // SendSuccess 성공 메시지 전송
 func (tracker *SSEProgressTracker) SendSuccess(aggregatorID string, message string) {
-	tracker.sendMessage(ProgressMessage{
+	tracker.publish(ProgressMessage{
 		AggregatorID: aggregatorID,
 		Stage:        5,
 		TotalStages:  5,
@@ -87,8 +260,8 @@ func (tracker *SSEProgressTracker) SendError(aggregatorID string, stage int, mes
 	if err != nil {
 		errorMsg = err.Error()
 	}
-	
-	tracker.sendMessage(ProgressMessage{
+
+	tracker.publish(ProgressMessage{
 		AggregatorID: aggregatorID,
 		Stage:        stage,
 		TotalStages:  5,
@@ -99,34 +272,60 @@ func (tracker *SSEProgressTracker) SendError(aggregatorID string, stage int, mes
 	})
 }
 
-// sendMessage 메시지 전송 (내부 메서드)
-func (tracker *SSEProgressTracker) sendMessage(msg ProgressMessage) {
-	tracker.mutex.RLock()
-	w, exists := tracker.connections[msg.AggregatorID]
-	tracker.mutex.RUnlock()
+// publish 메시지에 이벤트 ID를 부여해 이력에 기록하고 모든 구독자에게 전달 (내부 메서드)
+// 버퍼가 가득 찬 구독자는 블로킹 대신 구독을 해제합니다
+func (tracker *SSEProgressTracker) publish(msg ProgressMessage) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
 
-	if !exists {
-		return // 연결이 없으면 무시
+	topic := tracker.topicLocked(msg.AggregatorID)
+	tracker.lastEventID++
+	msg.EventID = tracker.lastEventID
+	topic.append(msg)
+
+	for sub := range topic.subscribers {
+		select {
+		case sub.messages <- msg:
+		default:
+			log.Printf("Dropping slow SSE subscriber for aggregator %s", msg.AggregatorID)
+			delete(topic.subscribers, sub)
+			sub.close()
+		}
 	}
+}
 
+// writeProgressEvent SSE 형식으로 메시지를 기록하고 플러시
+func writeProgressEvent(w http.ResponseWriter, msg ProgressMessage) error {
 	data, err := json.Marshal(msg)
 	if err != nil {
 		log.Printf("Failed to marshal progress message: %v", err)
-		return
+		return nil
 	}
 
-	// SSE 형식으로 전송
-	sseData := fmt.Sprintf("data: %s\n\n", string(data))
-	if _, err := fmt.Fprint(w, sseData); err != nil {
-		log.Printf("Failed to send SSE message: %v", err)
-		// 연결이 끊어진 경우 정리
-		tracker.mutex.Lock()
-		delete(tracker.connections, msg.AggregatorID)
-		tracker.mutex.Unlock()
-		return
+	if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", msg.EventID, data); err != nil {
+		return err
 	}
+	flush(w)
+	return nil
+}
+
+// parseLastEventID Last-Event-ID 헤더 또는 lastEventId 쿼리 파라미터 파싱
+func parseLastEventID(r *http.Request) uint64 {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return 0
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
 
-	// 응답 플러시
+func flush(w http.ResponseWriter) {
 	if flusher, ok := w.(http.Flusher); ok {
 		flusher.Flush()
 	}
@@ -0,0 +1,306 @@
+package aggregator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, sub *ProgressSubscription) ProgressMessage {
+	t.Helper()
+	select {
+	case msg, ok := <-sub.Messages():
+		if !ok {
+			t.Fatal("subscription channel closed unexpectedly")
+		}
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for progress message")
+	}
+	return ProgressMessage{}
+}
+
+func TestProgressHubDeliversToAllSubscribers(t *testing.T) {
+	hub := NewSSEProgressTracker(8, time.Minute)
+
+	first, _ := hub.Subscribe("agg-1", 0)
+	second, _ := hub.Subscribe("agg-1", 0)
+	other, _ := hub.Subscribe("agg-2", 0)
+
+	hub.SendProgress("agg-1", 2, "keypair")
+
+	for _, sub := range []*ProgressSubscription{first, second} {
+		msg := receive(t, sub)
+		if msg.Stage != 2 || msg.Message != "keypair" || msg.Status != "progress" {
+			t.Fatalf("unexpected message: %+v", msg)
+		}
+	}
+
+	select {
+	case msg := <-other.Messages():
+		t.Fatalf("subscriber of another aggregator received %+v", msg)
+	default:
+	}
+
+	if got := hub.SubscriberCount("agg-1"); got != 2 {
+		t.Fatalf("expected 2 subscribers, got %d", got)
+	}
+}
+
+func TestProgressHubReplaysHistoryOnSubscribe(t *testing.T) {
+	hub := NewSSEProgressTracker(8, time.Minute)
+
+	hub.SendProgress("agg-1", 1, "creating")
+	hub.SendProgress("agg-1", 2, "keypair")
+	hub.SendSuccess("agg-1", "done")
+
+	_, replay := hub.Subscribe("agg-1", 0)
+	if len(replay) != 3 {
+		t.Fatalf("expected 3 replayed messages, got %d", len(replay))
+	}
+	for i := 1; i < len(replay); i++ {
+		if replay[i].EventID <= replay[i-1].EventID {
+			t.Fatalf("replay not in event order: %+v", replay)
+		}
+	}
+	if replay[2].Status != "success" {
+		t.Fatalf("expected last replayed message to be success, got %q", replay[2].Status)
+	}
+}
+
+func TestProgressHubReplayHonoursLastEventID(t *testing.T) {
+	hub := NewSSEProgressTracker(8, time.Minute)
+
+	hub.SendProgress("agg-1", 1, "creating")
+	hub.SendProgress("agg-1", 2, "keypair")
+	hub.SendProgress("agg-1", 3, "workspace")
+
+	_, all := hub.Subscribe("agg-1", 0)
+	_, replay := hub.Subscribe("agg-1", all[0].EventID)
+
+	if len(replay) != 2 {
+		t.Fatalf("expected 2 messages after event %d, got %d", all[0].EventID, len(replay))
+	}
+	if replay[0].Message != "keypair" || replay[1].Message != "workspace" {
+		t.Fatalf("unexpected replay: %+v", replay)
+	}
+
+	_, none := hub.Subscribe("agg-1", all[2].EventID)
+	if len(none) != 0 {
+		t.Fatalf("expected no replay after latest event, got %d", len(none))
+	}
+}
+
+func TestProgressHubRingBufferIsBounded(t *testing.T) {
+	hub := NewSSEProgressTracker(3, time.Minute)
+
+	for stage := 1; stage <= 5; stage++ {
+		hub.SendProgress("agg-1", stage, "stage")
+	}
+
+	_, replay := hub.Subscribe("agg-1", 0)
+	if len(replay) != 3 {
+		t.Fatalf("expected ring buffer to keep 3 messages, got %d", len(replay))
+	}
+	for i, msg := range replay {
+		if msg.Stage != i+3 {
+			t.Fatalf("expected stages 3..5, got %+v", replay)
+		}
+	}
+}
+
+func TestProgressHubUnsubscribe(t *testing.T) {
+	hub := NewSSEProgressTracker(8, time.Minute)
+
+	sub, _ := hub.Subscribe("agg-1", 0)
+	hub.Unsubscribe(sub)
+	hub.Unsubscribe(sub) // 두 번 호출해도 panic이 없어야 함
+
+	if _, ok := <-sub.Messages(); ok {
+		t.Fatal("expected channel to be closed after unsubscribe")
+	}
+	if got := hub.SubscriberCount("agg-1"); got != 0 {
+		t.Fatalf("expected 0 subscribers, got %d", got)
+	}
+
+	// 해제된 구독자가 있어도 발행이 블로킹되지 않아야 함
+	hub.SendProgress("agg-1", 1, "after unsubscribe")
+}
+
+func TestProgressHubDropsSlowSubscriber(t *testing.T) {
+	hub := NewSSEProgressTracker(8, time.Minute)
+
+	slow, _ := hub.Subscribe("agg-1", 0)
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		hub.SendProgress("agg-1", 1, "flood")
+	}
+
+	if got := hub.SubscriberCount("agg-1"); got != 0 {
+		t.Fatalf("expected slow subscriber to be removed, got %d subscribers", got)
+	}
+
+	drained := 0
+	for range slow.Messages() {
+		drained++
+	}
+	if drained != subscriberBufferSize {
+		t.Fatalf("expected %d buffered messages before close, got %d", subscriberBufferSize, drained)
+	}
+}
+
+func TestProgressHubPrunesIdleTopics(t *testing.T) {
+	hub := NewSSEProgressTracker(8, time.Minute)
+	now := time.Now()
+	hub.now = func() time.Time { return now }
+
+	hub.SendProgress("agg-old", 1, "old")
+	lastID := hub.lastEventID
+
+	now = now.Add(defaultProgressRetention + time.Minute)
+	_, replay := hub.Subscribe("agg-new", 0)
+	if len(replay) != 0 {
+		t.Fatalf("unexpected replay for new topic: %+v", replay)
+	}
+
+	if _, exists := hub.topics["agg-old"]; exists {
+		t.Fatal("expected idle topic to be pruned")
+	}
+
+	// 정리 이후에도 이벤트 ID는 단조 증가해야 함
+	hub.SendProgress("agg-old", 1, "again")
+	_, replay = hub.Subscribe("agg-old", 0)
+	if len(replay) != 1 || replay[0].EventID <= lastID {
+		t.Fatalf("expected monotonic event id after prune, got %+v", replay)
+	}
+}
+
+func TestHandleWebSocketStreamsReplayAndLiveEvents(t *testing.T) {
+	hub := NewSSEProgressTracker(8, 20*time.Millisecond)
+
+	hub.SendProgress("agg-1", 1, "creating")
+	hub.SendProgress("agg-1", 2, "keypair")
+	_, history := hub.Subscribe("agg-1", 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub.HandleWebSocket(w, r, "agg-1")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", "1")
+	if history[0].EventID != 1 {
+		t.Fatalf("expected first event id 1, got %d", history[0].EventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	lines := make(chan string, 64)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	nextData := func() ProgressMessage {
+		t.Helper()
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatal("stream closed before data event")
+				}
+				if strings.HasPrefix(line, "data: ") {
+					var msg ProgressMessage
+					if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg); err != nil {
+						t.Fatalf("invalid event payload %q: %v", line, err)
+					}
+					return msg
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("timed out waiting for SSE data")
+			}
+		}
+	}
+
+	// Last-Event-ID: 1 이후의 이력만 재전송
+	if msg := nextData(); msg.Message != "keypair" {
+		t.Fatalf("expected replay of keypair, got %+v", msg)
+	}
+
+	// 연결 이후 발행된 이벤트 수신
+	deadline := time.Now().Add(time.Second)
+	for hub.SubscriberCount("agg-1") < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	hub.SendSuccess("agg-1", "done")
+	if msg := nextData(); msg.Status != "success" {
+		t.Fatalf("expected live success event, got %+v", msg)
+	}
+
+	// 하트비트 수신
+	sawHeartbeat := false
+	timeout := time.After(2 * time.Second)
+	for !sawHeartbeat {
+		select {
+		case line := <-lines:
+			sawHeartbeat = line == ": heartbeat"
+		case <-timeout:
+			t.Fatal("timed out waiting for heartbeat")
+		}
+	}
+
+	// 클라이언트 종료 시 구독 해제
+	cancel()
+	deadline = time.Now().Add(2 * time.Second)
+	for hub.SubscriberCount("agg-1") > 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := hub.SubscriberCount("agg-1"); got != 1 {
+		t.Fatalf("expected handler subscription to be removed, got %d subscribers", got)
+	}
+}
+
+func TestParseLastEventID(t *testing.T) {
+	cases := []struct {
+		header string
+		query  string
+		want   uint64
+	}{
+		{"", "", 0},
+		{"42", "", 42},
+		{"", "7", 7},
+		{"5", "9", 5},
+		{"not-a-number", "", 0},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/progress?lastEventId="+tc.query, nil)
+		if tc.header != "" {
+			r.Header.Set("Last-Event-ID", tc.header)
+		}
+		if got := parseLastEventID(r); got != tc.want {
+			t.Errorf("parseLastEventID(header=%q, query=%q) = %d, want %d", tc.header, tc.query, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/utils"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	aggregatorConnectionsOnce sync.Once
+	aggregatorConnections     *utils.SSHConnectionManager
+)
+
+// AggregatorConnections는 프로세스 전체에서 공유하는 집계자 SSH 연결 관리자를 반환합니다
+//
+//	AGGREGATOR_SSH_MAX_SESSIONS   연결당 동시 세션 수 (기본 8)
+//	AGGREGATOR_SSH_KEEPALIVE      keepalive 주기 (기본 30s)
+//	AGGREGATOR_SSH_IDLE_TIMEOUT   유휴 연결 종료 시간 (기본 10m)
+func AggregatorConnections() *utils.SSHConnectionManager {
+	aggregatorConnectionsOnce.Do(func() {
+		config := utils.DefaultSSHConnectionConfig()
+		if v, err := strconv.Atoi(os.Getenv("AGGREGATOR_SSH_MAX_SESSIONS")); err == nil && v > 0 {
+			config.MaxSessions = v
+		}
+		config.KeepaliveInterval = durationFromEnv("AGGREGATOR_SSH_KEEPALIVE", config.KeepaliveInterval)
+		config.IdleTimeout = durationFromEnv("AGGREGATOR_SSH_IDLE_TIMEOUT", config.IdleTimeout)
+		aggregatorConnections = utils.NewSSHConnectionManager(config)
+	})
+	return aggregatorConnections
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("잘못된 %s 값(%s), 기본값 %s 사용", name, value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// AggregatorConnection은 집계자별로 공유되는 SSH 연결을 반환합니다
+// 실제 연결(재연결 포함) 시마다 키페어와 고정된 호스트 키를 다시 읽으므로 재고정 후에도 새 키로 검증합니다
+func (s *SSHKeypairService) AggregatorConnection(aggregatorID, host string) *utils.SSHConnection {
+	return AggregatorConnections().Get(aggregatorID, host, func() (*ssh.Client, error) {
+		keypair, err := s.GetKeypairWithPrivateKey(aggregatorID)
+		if err != nil {
+			return nil, err
+		}
+		client, err := s.NewAggregatorSSHClient(keypair, host)
+		if err != nil {
+			return nil, err
+		}
+		return client.Connect()
+	})
+}
+
+// CloseAggregatorConnection은 집계자의 공유 SSH 연결을 닫습니다
+func CloseAggregatorConnection(aggregatorID string) {
+	AggregatorConnections().Close(aggregatorID)
+}
@@ -0,0 +1,63 @@
+package audit
+
+import "github.com/gin-gonic/gin"
+
+const annotationKey = "auditAnnotation"
+
+// Annotation은 핸들러가 감사 미들웨어에 전달하는 작업 정보입니다
+type Annotation struct {
+	Action       string
+	ResourceType string
+	ResourceID   string
+	ActorUserID  int64 // 인증 전 요청(로그인 등)에서 확인된 사용자
+	Detail       map[string]interface{}
+}
+
+// Annotate는 현재 요청의 감사 로그 작업 이름과 대상 리소스를 지정합니다
+// 조회(GET) 요청도 Annotate하면 기록됩니다 (예: 개인 키 다운로드)
+func Annotate(c *gin.Context, action, resourceType, resourceID string) {
+	a := annotation(c)
+	a.Action = action
+	a.ResourceType = resourceType
+	if resourceID != "" {
+		a.ResourceID = resourceID
+	}
+}
+
+// SetResourceID는 생성 후에 알게 된 리소스 ID를 지정합니다
+func SetResourceID(c *gin.Context, resourceID string) {
+	annotation(c).ResourceID = resourceID
+}
+
+// SetActor는 인증 미들웨어를 거치지 않는 요청에서 확인된 사용자를 지정합니다
+func SetActor(c *gin.Context, userID int64) {
+	annotation(c).ActorUserID = userID
+}
+
+// AddDetail은 감사 로그 payload에 상세 정보를 추가합니다 (민감 값은 저장 시 마스킹)
+func AddDetail(c *gin.Context, key string, value interface{}) {
+	a := annotation(c)
+	if a.Detail == nil {
+		a.Detail = map[string]interface{}{}
+	}
+	a.Detail[key] = value
+}
+
+// AnnotationFrom은 요청에 지정된 감사 정보를 반환합니다 (없으면 nil)
+func AnnotationFrom(c *gin.Context) *Annotation {
+	if v, exists := c.Get(annotationKey); exists {
+		if a, ok := v.(*Annotation); ok {
+			return a
+		}
+	}
+	return nil
+}
+
+func annotation(c *gin.Context) *Annotation {
+	if a := AnnotationFrom(c); a != nil {
+		return a
+	}
+	a := &Annotation{}
+	c.Set(annotationKey, a)
+	return a
+}
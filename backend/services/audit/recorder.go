@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"log"
+	"sync"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+)
+
+// Recorder는 감사 로그를 저장합니다
+type Recorder struct {
+	repo *repository.AuditLogRepository
+}
+
+// NewRecorder는 새 Recorder 인스턴스를 생성합니다
+func NewRecorder(repo *repository.AuditLogRepository) *Recorder {
+	return &Recorder{repo: repo}
+}
+
+// Record는 감사 로그를 저장합니다
+// 감사 기록 실패가 원래 작업을 실패시키지 않도록 에러는 로그로만 남깁니다
+func (r *Recorder) Record(entry *models.AuditLog) {
+	if r == nil || entry == nil {
+		return
+	}
+	if entry.ActorType == "" {
+		entry.ActorType = models.AuditActorSystem
+	}
+	if entry.Outcome == "" {
+		entry.Outcome = models.AuditOutcomeSuccess
+	}
+	entry.Payload = RedactMap(entry.Payload)
+
+	if err := r.repo.Create(entry); err != nil {
+		log.Printf("감사 로그 저장 실패 (action: %s, resource: %s/%s): %v", entry.Action, entry.ResourceType, entry.ResourceID, err)
+	}
+}
+
+var (
+	defaultMu       sync.RWMutex
+	defaultRecorder *Recorder
+)
+
+// SetDefault는 서비스 훅(Record, RecordSystemEvent)이 사용할 Recorder를 지정합니다
+func SetDefault(r *Recorder) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultRecorder = r
+}
+
+// Default는 기본 Recorder를 반환합니다 (설정되지 않았으면 nil)
+func Default() *Recorder {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultRecorder
+}
+
+// Record는 기본 Recorder로 감사 로그를 저장합니다
+func Record(entry *models.AuditLog) {
+	Default().Record(entry)
+}
+
+// SystemEvent는 요청과 무관하게 백그라운드 작업에서 발생한 이벤트입니다
+type SystemEvent struct {
+	Action         string
+	ResourceType   string
+	ResourceID     string
+	OrganizationID int64
+	UserID         int64 // 작업을 시작한 사용자 (없으면 0)
+	Err            error // nil이 아니면 failure로 기록
+	Detail         map[string]interface{}
+}
+
+// RecordSystemEvent는 서비스 계층의 백그라운드 작업 결과를 기록합니다
+func RecordSystemEvent(event SystemEvent) {
+	entry := &models.AuditLog{
+		ActorType:      models.AuditActorSystem,
+		ActorUserID:    event.UserID,
+		OrganizationID: event.OrganizationID,
+		Action:         event.Action,
+		ResourceType:   event.ResourceType,
+		ResourceID:     event.ResourceID,
+		Outcome:        models.AuditOutcomeSuccess,
+		Payload:        event.Detail,
+	}
+	if event.Err != nil {
+		entry.Outcome = models.AuditOutcomeFailure
+		if entry.Payload == nil {
+			entry.Payload = map[string]interface{}{}
+		}
+		entry.Payload["error"] = event.Err.Error()
+	}
+	Record(entry)
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"strings"
+)
+
+const (
+	redactedValue     = "[REDACTED]"
+	maxPayloadString  = 1024
+	maxPayloadDepth   = 6
+	maxPayloadEntries = 100
+)
+
+// sensitiveKeyParts는 값을 마스킹할 필드 이름의 일부입니다 (대소문자, _/- 무시)
+var sensitiveKeyParts = []string{
+	"password",
+	"passphrase",
+	"secret",
+	"token",
+	"privatekey",
+	"credential",
+	"authorization",
+	"accesskey",
+	"apikey",
+	"cookie",
+}
+
+// IsSensitiveKey는 필드 이름이 민감 정보를 담는 필드인지 확인합니다
+func IsSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactMap은 민감 필드를 마스킹하고 긴 문자열/큰 구조를 잘라낸 사본을 반환합니다
+func RedactMap(payload map[string]interface{}) map[string]interface{} {
+	if payload == nil {
+		return nil
+	}
+	redacted, _ := redactValue(payload, 0).(map[string]interface{})
+	return redacted
+}
+
+func redactValue(value interface{}, depth int) interface{} {
+	if depth > maxPayloadDepth {
+		return "[TRUNCATED]"
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		count := 0
+		for key, item := range v {
+			if count >= maxPayloadEntries {
+				result["_truncated"] = true
+				break
+			}
+			count++
+			if IsSensitiveKey(key) {
+				result[key] = redactedValue
+				continue
+			}
+			result[key] = redactValue(item, depth+1)
+		}
+		return result
+	case []interface{}:
+		limit := len(v)
+		if limit > maxPayloadEntries {
+			limit = maxPayloadEntries
+		}
+		result := make([]interface{}, 0, limit)
+		for _, item := range v[:limit] {
+			result = append(result, redactValue(item, depth+1))
+		}
+		return result
+	case string:
+		if strings.Contains(v, "-----BEGIN") {
+			return redactedValue
+		}
+		if len(v) > maxPayloadString {
+			return strings.ToValidUTF8(v[:maxPayloadString], "") + "...[TRUNCATED]"
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,123 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Action은 조직 리소스에 대해 수행하려는 작업입니다
+type Action string
+
+const (
+	ActionView               Action = "view"                // 조회
+	ActionOperate            Action = "operate"             // 학습 실행, 헬스체크, VM 선택 등 운영 작업
+	ActionManage             Action = "manage"              // 리소스 생성/수정/삭제
+	ActionManageMembers      Action = "manage_members"      // 멤버 초대/역할 변경/제거, 조직 정보 수정
+	ActionDeleteOrganization Action = "delete_organization" // 조직 삭제, owner 지정
+)
+
+// rolePermissions는 역할별로 허용되는 작업 목록입니다 (권한 정책의 단일 출처)
+var rolePermissions = map[string]map[Action]bool{
+	models.OrganizationRoleOwner: {
+		ActionView: true, ActionOperate: true, ActionManage: true,
+		ActionManageMembers: true, ActionDeleteOrganization: true,
+	},
+	models.OrganizationRoleAdmin: {
+		ActionView: true, ActionOperate: true, ActionManage: true,
+		ActionManageMembers: true,
+	},
+	models.OrganizationRoleOperator: {
+		ActionView: true, ActionOperate: true,
+	},
+	models.OrganizationRoleViewer: {
+		ActionView: true,
+	},
+}
+
+// RoleAllows는 역할이 작업을 허용하는지 확인합니다
+func RoleAllows(role string, action Action) bool {
+	return rolePermissions[role][action]
+}
+
+// Principal은 요청한 사용자와 그 사용자의 조직별 역할입니다
+type Principal struct {
+	UserID               int64
+	ActiveOrganizationID int64 // 목록 조회/생성 시 사용할 현재 조직
+	roles                map[int64]string
+}
+
+// NewPrincipal은 멤버십 목록으로 Principal을 생성합니다
+func NewPrincipal(userID, activeOrganizationID int64, memberships []*models.OrganizationMember) *Principal {
+	roles := make(map[int64]string, len(memberships))
+	for _, m := range memberships {
+		roles[m.OrganizationID] = m.Role
+	}
+	return &Principal{
+		UserID:               userID,
+		ActiveOrganizationID: activeOrganizationID,
+		roles:                roles,
+	}
+}
+
+// Role은 조직에서의 역할을 반환합니다 (멤버가 아니면 빈 문자열)
+func (p *Principal) Role(orgID int64) string {
+	if p == nil {
+		return ""
+	}
+	return p.roles[orgID]
+}
+
+// IsMember는 조직의 멤버인지 확인합니다
+func (p *Principal) IsMember(orgID int64) bool {
+	return p.Role(orgID) != ""
+}
+
+// Can은 조직 리소스에 대한 작업이 허용되는지 확인합니다
+func (p *Principal) Can(orgID int64, action Action) bool {
+	return RoleAllows(p.Role(orgID), action)
+}
+
+// ActiveRole은 현재 조직에서의 역할을 반환합니다
+func (p *Principal) ActiveRole() string {
+	return p.Role(p.ActiveOrganizationID)
+}
+
+const principalContextKey = "principal"
+
+// SetPrincipal은 요청 컨텍스트에 Principal을 저장합니다
+func SetPrincipal(c *gin.Context, p *Principal) {
+	c.Set(principalContextKey, p)
+}
+
+// PrincipalFromContext는 요청 컨텍스트의 Principal을 반환합니다
+// OrganizationMiddleware를 거치지 않은 요청이면 nil을 반환합니다
+func PrincipalFromContext(c *gin.Context) *Principal {
+	v, ok := c.Get(principalContextKey)
+	if !ok {
+		return nil
+	}
+	p, _ := v.(*Principal)
+	return p
+}
+
+// Authorize는 핸들러에서 리소스 접근 권한을 확인하는 공통 정책 함수입니다
+// 허용되지 않으면 403 응답을 쓰고 false를 반환하므로 호출한 핸들러는 바로 반환하면 됩니다
+func Authorize(c *gin.Context, orgID int64, action Action) bool {
+	if PrincipalFromContext(c).Can(orgID, action) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "권한이 없습니다"})
+	return false
+}
+
+// AuthorizeActive는 현재 조직에 대한 권한을 확인합니다 (목록 조회, 리소스 생성 등)
+func AuthorizeActive(c *gin.Context, action Action) (*Principal, bool) {
+	p := PrincipalFromContext(c)
+	if p == nil || !p.Can(p.ActiveOrganizationID, action) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "권한이 없습니다"})
+		return nil, false
+	}
+	return p, true
+}
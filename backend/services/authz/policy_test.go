@@ -0,0 +1,100 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+func TestRoleAllows(t *testing.T) {
+	actions := []Action{ActionView, ActionOperate, ActionManage, ActionManageMembers, ActionDeleteOrganization}
+	// 각 역할에 허용되는 작업 (actions와 같은 순서)
+	cases := map[string][]bool{
+		models.OrganizationRoleOwner:    {true, true, true, true, true},
+		models.OrganizationRoleAdmin:    {true, true, true, true, false},
+		models.OrganizationRoleOperator: {true, true, false, false, false},
+		models.OrganizationRoleViewer:   {true, false, false, false, false},
+		"":                              {false, false, false, false, false},
+		"superuser":                     {false, false, false, false, false},
+	}
+	for role, allowed := range cases {
+		for i, action := range actions {
+			if got := RoleAllows(role, action); got != allowed[i] {
+				t.Errorf("RoleAllows(%q, %s) = %v, want %v", role, action, got, allowed[i])
+			}
+		}
+	}
+	if RoleAllows(models.OrganizationRoleOwner, Action("unknown")) {
+		t.Error("owner allowed an unknown action")
+	}
+}
+
+func TestPrincipalCan(t *testing.T) {
+	principal := NewPrincipal(7, 1, []*models.OrganizationMember{
+		{OrganizationID: 1, UserID: 7, Role: models.OrganizationRoleOwner},
+		{OrganizationID: 2, UserID: 7, Role: models.OrganizationRoleOperator},
+		{OrganizationID: 3, UserID: 7, Role: models.OrganizationRoleViewer},
+	})
+	cases := []struct {
+		orgID  int64
+		action Action
+		want   bool
+	}{
+		{1, ActionDeleteOrganization, true},
+		{2, ActionOperate, true},
+		{2, ActionManage, false},
+		{3, ActionView, true},
+		{3, ActionOperate, false},
+		// 멤버가 아닌 조직은 조회도 불가
+		{4, ActionView, false},
+	}
+	for _, tc := range cases {
+		if got := principal.Can(tc.orgID, tc.action); got != tc.want {
+			t.Errorf("Can(%d, %s) = %v, want %v", tc.orgID, tc.action, got, tc.want)
+		}
+	}
+	if principal.ActiveRole() != models.OrganizationRoleOwner || principal.IsMember(4) {
+		t.Errorf("active role = %q, member of 4 = %v", principal.ActiveRole(), principal.IsMember(4))
+	}
+
+	var anonymous *Principal
+	if anonymous.Can(1, ActionView) || anonymous.IsMember(1) {
+		t.Error("nil principal is allowed")
+	}
+}
+
+func TestAuthorizeWritesForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	principal := NewPrincipal(7, 2, []*models.OrganizationMember{{OrganizationID: 2, UserID: 7, Role: models.OrganizationRoleViewer}})
+
+	cases := []struct {
+		name      string
+		principal *Principal
+		authorize func(c *gin.Context) bool
+		want      int
+	}{
+		{"view", principal, func(c *gin.Context) bool { return Authorize(c, 2, ActionView) }, http.StatusOK},
+		{"manage", principal, func(c *gin.Context) bool { return Authorize(c, 2, ActionManage) }, http.StatusForbidden},
+		{"active view", principal, func(c *gin.Context) bool { _, ok := AuthorizeActive(c, ActionView); return ok }, http.StatusOK},
+		{"active operate", principal, func(c *gin.Context) bool { _, ok := AuthorizeActive(c, ActionOperate); return ok }, http.StatusForbidden},
+		// OrganizationMiddleware를 거치지 않은 요청
+		{"no principal", nil, func(c *gin.Context) bool { _, ok := AuthorizeActive(c, ActionView); return ok }, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		if tc.principal != nil {
+			SetPrincipal(c, tc.principal)
+		}
+		if tc.authorize(c) {
+			c.Status(http.StatusOK)
+		}
+		if w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+}
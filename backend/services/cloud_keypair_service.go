@@ -37,9 +37,9 @@ type KeypairInfo struct {
 }
 
 // GetOrCreateAWSKeypair AWS EC2 키페어 조회 또는 생성
-func (s *CloudKeypairService) GetOrCreateAWSKeypair(userID int64, region, keyName string) (*KeypairInfo, error) {
-	// 사용자의 AWS 클라우드 연결 찾기
-	cloudConnections, err := s.cloudRepo.GetCloudConnectionsByUserID(userID)
+func (s *CloudKeypairService) GetOrCreateAWSKeypair(orgID int64, region, keyName string) (*KeypairInfo, error) {
+	// 조직의 AWS 클라우드 연결 찾기
+	cloudConnections, err := s.cloudRepo.GetCloudConnectionsByOrganizationID(orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cloud connections: %v", err)
 	}
@@ -54,7 +54,7 @@ func (s *CloudKeypairService) GetOrCreateAWSKeypair(userID int64, region, keyNam
 	}
 
 	if awsConn == nil {
-		return nil, fmt.Errorf("active AWS connection not found for user %d", userID)
+		return nil, fmt.Errorf("active AWS connection not found for organization %d", orgID)
 	}
 
 	// AWS 자격증명 파싱
@@ -152,9 +152,9 @@ func (s *CloudKeypairService) GetOrCreateAWSKeypair(userID int64, region, keyNam
 }
 
 // GetOrCreateGCPKeypair GCP Compute Engine 키페어 조회 또는 생성
-func (s *CloudKeypairService) GetOrCreateGCPKeypair(userID int64, keyName, publicKeyContent, privateKeyContent string) (*KeypairInfo, error) {
-    // 사용자의 GCP 클라우드 연결 찾기
-    cloudConnections, err := s.cloudRepo.GetCloudConnectionsByUserID(userID)
+func (s *CloudKeypairService) GetOrCreateGCPKeypair(orgID int64, keyName, publicKeyContent, privateKeyContent string) (*KeypairInfo, error) {
+    // 조직의 GCP 클라우드 연결 찾기
+    cloudConnections, err := s.cloudRepo.GetCloudConnectionsByOrganizationID(orgID)
     if err != nil {
         return nil, fmt.Errorf("failed to get cloud connections: %v", err)
     }
@@ -169,7 +169,7 @@ func (s *CloudKeypairService) GetOrCreateGCPKeypair(userID int64, keyName, publi
     }
 
     if gcpConn == nil {
-        return nil, fmt.Errorf("active GCP connection not found for user %d", userID)
+        return nil, fmt.Errorf("active GCP connection not found for organization %d", orgID)
     }
 
     // GCP 자격 증명 파일에서 projectID 추출
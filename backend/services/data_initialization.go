@@ -255,19 +255,9 @@ func processCloudPriceCSV(db *gorm.DB, filename string) error {
 }
 
 // initializeCloudLatencies는 latency_results.csv 파일로부터 지연시간 데이터를 초기화합니다
+// 기존 행(지연시간 측정기가 갱신한 값 포함)은 유지하고 매트릭스에 없는 리전 쌍만 채웁니다
 func initializeCloudLatencies(db *gorm.DB) error {
-	// 이미 데이터가 있는지 확인
-	var count int64
-	if err := db.Model(&models.CloudLatency{}).Count(&count).Error; err != nil {
-		return fmt.Errorf("failed to count existing cloud latencies: %w", err)
-	}
-
-	if count > 0 {
-		log.Printf("Cloud latency data already exists (%d records), skipping initialization", count)
-		return nil
-	}
-
-	log.Println("Initializing cloud latency data...")
+	log.Println("Initializing cloud latency data (missing pairs only)...")
 
 	providerRepo := repository.NewProviderRepository(db)
 	regionRepo := repository.NewRegionRepository(db)
@@ -414,7 +404,7 @@ func initializeCloudLatencies(db *gorm.DB) error {
 		}).CreateInBatches(cloudLatencies, 1000).Error; err != nil {
 			return fmt.Errorf("failed to insert cloud latencies: %w", err)
 		}
-		log.Printf("Successfully inserted %d cloud latency records", len(cloudLatencies))
+		log.Printf("Successfully seeded cloud latencies (%d records in CSV, existing pairs kept)", len(cloudLatencies))
 	} else {
 		log.Println("No valid cloud latency records found to insert")
 	}
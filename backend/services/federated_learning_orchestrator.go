@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+)
+
+// 단계별 최대 재시도 횟수
+var flStepMaxRetries = map[string]int{
+	models.FLStepUploadBundle:        3,
+	models.FLStepStartServer:         2,
+	models.FLStepWaitReady:           1,
+	models.FLStepDispatchParticipant: 3,
+}
+
+// cancelWaitTimeout은 Cancel이 실행 중인 단계가 끝나기를 기다리는 최대 시간입니다
+const cancelWaitTimeout = 30 * time.Second
+
+// ErrPermanentStepFailure는 재시도해도 성공할 수 없는 단계 실패를 나타냅니다
+var ErrPermanentStepFailure = errors.New("permanent step failure")
+
+// FLStepExecutor는 오케스트레이터의 각 단계를 실제로 수행하는 실행기 인터페이스입니다
+// 서버 재시작 후 running으로 남은 단계는 다시 실행되므로, 각 메서드는 이미 반영된 결과를 확인해
+// 중복 실행하지 않아야 합니다 (서버 PID/포트, 참여자 작업 ID 확인)
+type FLStepExecutor interface {
+	UploadAggregatorBundle(ctx context.Context, fl *models.FederatedLearning) error
+	StartAggregatorServer(ctx context.Context, fl *models.FederatedLearning) error
+	WaitAggregatorReady(ctx context.Context, fl *models.FederatedLearning) error
+	DispatchToParticipant(ctx context.Context, fl *models.FederatedLearning, participantID string) error
+}
+
+// FederatedLearningOrchestrator는 연합학습 실행 단계를 DB에 기록하며 순차적으로 수행합니다
+// 서버가 재시작되어도 완료되지 않은 단계부터 이어서 실행할 수 있습니다
+type FederatedLearningOrchestrator struct {
+	stepRepo   *repository.FederatedLearningStepRepository
+	flRepo     *repository.FederatedLearningRepository
+	executor   FLStepExecutor
+	retryDelay time.Duration
+
+	mutex  sync.Mutex
+	active map[string]*flRun // 실행 중인 연합학습 (중복 실행 방지, 취소)
+}
+
+// flRun은 백그라운드에서 실행 중인 연합학습 오케스트레이션입니다
+type flRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewFederatedLearningOrchestrator는 새 FederatedLearningOrchestrator 인스턴스를 생성합니다
+func NewFederatedLearningOrchestrator(stepRepo *repository.FederatedLearningStepRepository, flRepo *repository.FederatedLearningRepository, executor FLStepExecutor) *FederatedLearningOrchestrator {
+	return &FederatedLearningOrchestrator{
+		stepRepo:   stepRepo,
+		flRepo:     flRepo,
+		executor:   executor,
+		retryDelay: 10 * time.Second,
+		active:     make(map[string]*flRun),
+	}
+}
+
+// Start는 연합학습의 실행 단계를 생성하고 백그라운드에서 실행을 시작합니다
+func (o *FederatedLearningOrchestrator) Start(fl *models.FederatedLearning, participantIDs []string) error {
+	if err := o.stepRepo.CreateSteps(newFLSteps(fl.ID, participantIDs)); err != nil {
+		return fmt.Errorf("실행 단계 생성 실패: %v", err)
+	}
+	if _, err := o.flRepo.TransitionStatus(fl.ID, models.FLStatusStarting, "학습 실행 시작", &fl.UserID); err != nil {
+		return fmt.Errorf("연합학습 상태 업데이트 실패: %w", err)
+	}
+	fl.Status = models.FLStatusStarting
+
+	o.launch(fl.ID)
+	return nil
+}
+
+// Restart는 중지/실패/완료된 연합학습의 실행 단계를 새로 만들고 다시 실행합니다
+// 체크포인트 준비(warm start)는 호출자가 upload_bundle 단계 전에 집계자에 마련해 둡니다
+func (o *FederatedLearningOrchestrator) Restart(fl *models.FederatedLearning, participantIDs []string, reason string, userID int64) error {
+	o.Cancel(fl.ID, "재시작으로 취소되었습니다")
+
+	if _, err := o.flRepo.TransitionStatus(fl.ID, models.FLStatusStarting, reason, &userID); err != nil {
+		return fmt.Errorf("연합학습 상태 업데이트 실패: %w", err)
+	}
+	fl.Status = models.FLStatusStarting
+
+	if err := o.stepRepo.DeleteByFederatedLearningID(fl.ID); err != nil {
+		o.markFailed(fl.ID, "이전 실행 단계 삭제 실패")
+		return fmt.Errorf("이전 실행 단계 삭제 실패: %v", err)
+	}
+	if err := o.stepRepo.CreateSteps(newFLSteps(fl.ID, participantIDs)); err != nil {
+		o.markFailed(fl.ID, "실행 단계 생성 실패")
+		return fmt.Errorf("실행 단계 생성 실패: %v", err)
+	}
+
+	o.launch(fl.ID)
+	return nil
+}
+
+// Cancel은 실행 중인 오케스트레이션을 취소하고 남은 단계를 실패로 표시합니다
+// 실행 중인 단계가 끝나기를 cancelWaitTimeout까지 기다립니다
+func (o *FederatedLearningOrchestrator) Cancel(flID, reason string) {
+	o.mutex.Lock()
+	run := o.active[flID]
+	o.mutex.Unlock()
+
+	if run != nil {
+		run.cancel()
+		select {
+		case <-run.done:
+		case <-time.After(cancelWaitTimeout):
+			log.Printf("연합학습 %s 실행 단계가 %v 내에 취소되지 않았습니다", flID, cancelWaitTimeout)
+		}
+	}
+
+	if err := o.stepRepo.FailUnfinished(flID, reason); err != nil {
+		log.Printf("남은 실행 단계 정리 실패 (ID: %s): %v", flID, err)
+	}
+}
+
+// ResumeUnfinished는 이전 프로세스에서 완료되지 못한 연합학습 실행을 재개합니다
+func (o *FederatedLearningOrchestrator) ResumeUnfinished() {
+	flIDs, err := o.stepRepo.GetUnfinishedFederatedLearningIDs()
+	if err != nil {
+		log.Printf("미완료 연합학습 실행 단계 조회 실패: %v", err)
+		return
+	}
+
+	for _, flID := range flIDs {
+		log.Printf("연합학습 실행 재개: %s", flID)
+		o.launch(flID)
+	}
+}
+
+// GetSteps는 연합학습의 실행 단계 목록을 반환합니다
+func (o *FederatedLearningOrchestrator) GetSteps(flID string) ([]*models.FederatedLearningStep, error) {
+	return o.stepRepo.GetByFederatedLearningID(flID)
+}
+
+// launch는 중복 실행을 방지하며 백그라운드 고루틴으로 실행합니다
+func (o *FederatedLearningOrchestrator) launch(flID string) {
+	o.mutex.Lock()
+	if o.active[flID] != nil {
+		o.mutex.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &flRun{cancel: cancel, done: make(chan struct{})}
+	o.active[flID] = run
+	o.mutex.Unlock()
+
+	go func() {
+		defer func() {
+			o.mutex.Lock()
+			delete(o.active, flID)
+			o.mutex.Unlock()
+			cancel()
+			close(run.done)
+		}()
+		o.run(ctx, flID)
+	}()
+}
+
+// run은 완료되지 않은 단계를 순서대로 수행하고 연합학습 상태를 running/failed로 변경합니다
+func (o *FederatedLearningOrchestrator) run(ctx context.Context, flID string) {
+	fl, err := o.flRepo.GetByID(flID)
+	if err != nil || fl == nil {
+		log.Printf("연합학습 조회 실패 (ID: %s): %v", flID, err)
+		return
+	}
+
+	steps, err := o.stepRepo.GetByFederatedLearningID(flID)
+	if err != nil {
+		log.Printf("연합학습 실행 단계 조회 실패 (ID: %s): %v", flID, err)
+		return
+	}
+
+	for _, step := range steps {
+		if step.Status == models.FLStepStatusCompleted {
+			continue
+		}
+		if ctx.Err() != nil {
+			log.Printf("연합학습 %s 실행 취소됨 (단계 %d 이전)", flID, step.Sequence)
+			return
+		}
+		if step.Status == models.FLStepStatusFailed {
+			o.markFailed(flID, fmt.Sprintf("%s 단계 실패", step.Name))
+			return
+		}
+
+		if step.Status == models.FLStepStatusRunning {
+			log.Printf("연합학습 %s 단계 %d(%s)가 이전 프로세스에서 실행 중이었습니다 - 반영 여부를 확인하며 재실행", flID, step.Sequence, step.Name)
+		}
+		if err := o.runStep(ctx, fl, step); err != nil {
+			// 중지/재시작으로 취소된 경우 상태는 취소한 쪽에서 바꿈
+			if ctx.Err() != nil {
+				log.Printf("연합학습 %s 단계 %d(%s) 취소됨", flID, step.Sequence, step.Name)
+				return
+			}
+			log.Printf("연합학습 %s 단계 %d(%s) 실패: %v", flID, step.Sequence, step.Name, err)
+			o.markFailed(flID, fmt.Sprintf("%s 단계 실패: %v", step.Name, err))
+			recordOrchestrationAudit(fl, step, err)
+			return
+		}
+	}
+
+	if _, err := o.flRepo.TransitionStatus(flID, models.FLStatusRunning, "실행 단계 완료", nil); err != nil {
+		log.Printf("연합학습 상태 업데이트 실패 (ID: %s): %v", flID, err)
+		return
+	}
+	log.Printf("연합학습 %s 실행 단계 완료 - 상태: %s", flID, models.FLStatusRunning)
+	recordOrchestrationAudit(fl, nil, nil)
+}
+
+// recordOrchestrationAudit은 학습 실행 오케스트레이션 결과를 감사 로그에 남깁니다
+func recordOrchestrationAudit(fl *models.FederatedLearning, failedStep *models.FederatedLearningStep, err error) {
+	detail := map[string]interface{}{}
+	if fl.AggregatorID != nil {
+		detail["aggregator_id"] = *fl.AggregatorID
+	}
+	if failedStep != nil {
+		detail["failed_step"] = failedStep.Name
+		detail["failed_sequence"] = failedStep.Sequence
+	}
+
+	audit.RecordSystemEvent(audit.SystemEvent{
+		Action:         "fl.orchestrate",
+		ResourceType:   "federated-learning",
+		ResourceID:     fl.ID,
+		OrganizationID: fl.OrganizationID,
+		UserID:         fl.UserID,
+		Err:            err,
+		Detail:         detail,
+	})
+}
+
+// runStep은 단일 단계를 최대 재시도 횟수까지 실행하며 매 시도 결과를 DB에 기록합니다
+func (o *FederatedLearningOrchestrator) runStep(ctx context.Context, fl *models.FederatedLearning, step *models.FederatedLearningStep) error {
+	for {
+		now := time.Now()
+		step.Status = models.FLStepStatusRunning
+		step.StartedAt = &now
+		o.saveStep(step)
+
+		err := o.execute(ctx, fl, step)
+		if err == nil {
+			completedAt := time.Now()
+			step.Status = models.FLStepStatusCompleted
+			step.Error = ""
+			step.CompletedAt = &completedAt
+			o.saveStep(step)
+			return nil
+		}
+
+		step.Error = err.Error()
+		if errors.Is(err, ErrPermanentStepFailure) || step.RetryCount >= step.MaxRetries || ctx.Err() != nil {
+			completedAt := time.Now()
+			step.Status = models.FLStepStatusFailed
+			step.CompletedAt = &completedAt
+			o.saveStep(step)
+			return err
+		}
+
+		step.RetryCount++
+		step.Status = models.FLStepStatusPending
+		o.saveStep(step)
+
+		delay := o.retryDelay * time.Duration(step.RetryCount)
+		log.Printf("연합학습 %s 단계 %s 재시도 %d/%d (%v 후): %v", fl.ID, step.Name, step.RetryCount, step.MaxRetries, delay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// execute는 단계 종류에 맞는 실행기 메서드를 호출합니다
+func (o *FederatedLearningOrchestrator) execute(ctx context.Context, fl *models.FederatedLearning, step *models.FederatedLearningStep) error {
+	switch step.Name {
+	case models.FLStepUploadBundle:
+		return o.executor.UploadAggregatorBundle(ctx, fl)
+	case models.FLStepStartServer:
+		return o.executor.StartAggregatorServer(ctx, fl)
+	case models.FLStepWaitReady:
+		return o.executor.WaitAggregatorReady(ctx, fl)
+	case models.FLStepDispatchParticipant:
+		if step.ParticipantID == nil {
+			return fmt.Errorf("%w: 참여자 ID가 없습니다", ErrPermanentStepFailure)
+		}
+		return o.executor.DispatchToParticipant(ctx, fl, *step.ParticipantID)
+	default:
+		return fmt.Errorf("%w: 알 수 없는 단계 %s", ErrPermanentStepFailure, step.Name)
+	}
+}
+
+func (o *FederatedLearningOrchestrator) saveStep(step *models.FederatedLearningStep) {
+	if err := o.stepRepo.Update(step); err != nil {
+		log.Printf("실행 단계 저장 실패 (FL: %s, 단계: %s): %v", step.FederatedLearningID, step.Name, err)
+	}
+}
+
+func (o *FederatedLearningOrchestrator) markFailed(flID, reason string) {
+	if _, err := o.flRepo.TransitionStatus(flID, models.FLStatusFailed, reason, nil); err != nil {
+		log.Printf("연합학습 상태 업데이트 실패 (ID: %s): %v", flID, err)
+	}
+}
+
+// newFLSteps는 집계자 준비부터 참여자 요청 전송까지의 실행 단계를 만듭니다
+func newFLSteps(flID string, participantIDs []string) []*models.FederatedLearningStep {
+	steps := []*models.FederatedLearningStep{
+		newFLStep(flID, 1, models.FLStepUploadBundle, nil),
+		newFLStep(flID, 2, models.FLStepStartServer, nil),
+		newFLStep(flID, 3, models.FLStepWaitReady, nil),
+	}
+	for i, participantID := range participantIDs {
+		id := participantID
+		steps = append(steps, newFLStep(flID, 4+i, models.FLStepDispatchParticipant, &id))
+	}
+	return steps
+}
+
+func newFLStep(flID string, sequence int, name string, participantID *string) *models.FederatedLearningStep {
+	return &models.FederatedLearningStep{
+		FederatedLearningID: flID,
+		Sequence:            sequence,
+		Name:                name,
+		ParticipantID:       participantID,
+		Status:              models.FLStepStatusPending,
+		MaxRetries:          flStepMaxRetries[name],
+	}
+}
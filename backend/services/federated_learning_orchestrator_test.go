@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+)
+
+// fakeFLExecutor는 단계 호출을 기록하고 미리 정한 오류를 차례로 반환하는 실행기입니다
+type fakeFLExecutor struct {
+	mu    sync.Mutex
+	calls []string
+	errs  map[string][]error
+	hooks map[string]func(ctx context.Context)
+}
+
+func (e *fakeFLExecutor) step(ctx context.Context, name string) error {
+	e.mu.Lock()
+	e.calls = append(e.calls, name)
+	var err error
+	if queue := e.errs[name]; len(queue) > 0 {
+		err, e.errs[name] = queue[0], queue[1:]
+	}
+	hook := e.hooks[name]
+	e.mu.Unlock()
+
+	if hook != nil {
+		hook(ctx)
+	}
+	return err
+}
+
+func (e *fakeFLExecutor) UploadAggregatorBundle(ctx context.Context, fl *models.FederatedLearning) error {
+	return e.step(ctx, models.FLStepUploadBundle)
+}
+
+func (e *fakeFLExecutor) StartAggregatorServer(ctx context.Context, fl *models.FederatedLearning) error {
+	return e.step(ctx, models.FLStepStartServer)
+}
+
+func (e *fakeFLExecutor) WaitAggregatorReady(ctx context.Context, fl *models.FederatedLearning) error {
+	return e.step(ctx, models.FLStepWaitReady)
+}
+
+func (e *fakeFLExecutor) DispatchToParticipant(ctx context.Context, fl *models.FederatedLearning, participantID string) error {
+	return e.step(ctx, models.FLStepDispatchParticipant+":"+participantID)
+}
+
+func (e *fakeFLExecutor) called() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.calls...)
+}
+
+type orchestratorEnv struct {
+	t            *testing.T
+	steps        *repository.FederatedLearningStepRepository
+	learnings    *repository.FederatedLearningRepository
+	executor     *fakeFLExecutor
+	orchestrator *FederatedLearningOrchestrator
+}
+
+func newOrchestratorEnv(t *testing.T) *orchestratorEnv {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", regexp.MustCompile(`\W`).ReplaceAllString(t.Name(), "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.FederatedLearning{}, &models.FederatedLearningStep{}, &models.FederatedLearningStatusTransition{}); err != nil {
+		t.Fatal(err)
+	}
+
+	env := &orchestratorEnv{
+		t:         t,
+		steps:     repository.NewFederatedLearningStepRepository(db),
+		learnings: repository.NewFederatedLearningRepository(db),
+		executor:  &fakeFLExecutor{errs: map[string][]error{}, hooks: map[string]func(context.Context){}},
+	}
+	env.orchestrator = NewFederatedLearningOrchestrator(env.steps, env.learnings, env.executor)
+	env.orchestrator.retryDelay = time.Millisecond
+	return env
+}
+
+func (e *orchestratorEnv) createFL(status string) *models.FederatedLearning {
+	e.t.Helper()
+	fl := &models.FederatedLearning{ID: "fl-" + status, UserID: 1, OrganizationID: 1, CloudConnectionID: "conn", Name: "test", Status: status}
+	if err := e.learnings.Create(fl); err != nil {
+		e.t.Fatal(err)
+	}
+	return fl
+}
+
+// wait는 백그라운드 실행이 끝날 때까지 기다립니다
+func (e *orchestratorEnv) wait(flID string) {
+	e.t.Helper()
+	e.orchestrator.mutex.Lock()
+	run := e.orchestrator.active[flID]
+	e.orchestrator.mutex.Unlock()
+	if run == nil {
+		return
+	}
+	select {
+	case <-run.done:
+	case <-time.After(5 * time.Second):
+		e.t.Fatalf("orchestration of %s did not finish", flID)
+	}
+}
+
+func (e *orchestratorEnv) status(flID string) string {
+	e.t.Helper()
+	fl, err := e.learnings.GetByID(flID)
+	if err != nil || fl == nil {
+		e.t.Fatalf("GetByID(%s) = %v, %v", flID, fl, err)
+	}
+	return fl.Status
+}
+
+func (e *orchestratorEnv) stepsOf(flID string) []*models.FederatedLearningStep {
+	e.t.Helper()
+	steps, err := e.steps.GetByFederatedLearningID(flID)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return steps
+}
+
+func TestOrchestratorRetriesTransientError(t *testing.T) {
+	env := newOrchestratorEnv(t)
+	env.executor.errs[models.FLStepUploadBundle] = []error{errors.New("connection reset")}
+	fl := env.createFL(models.FLStatusReady)
+
+	if err := env.orchestrator.Start(fl, []string{"p1", "p2"}); err != nil {
+		t.Fatal(err)
+	}
+	env.wait(fl.ID)
+
+	want := []string{
+		models.FLStepUploadBundle, models.FLStepUploadBundle,
+		models.FLStepStartServer, models.FLStepWaitReady,
+		models.FLStepDispatchParticipant + ":p1", models.FLStepDispatchParticipant + ":p2",
+	}
+	if got := env.executor.called(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("calls = %v, want %v", got, want)
+	}
+	if got := env.status(fl.ID); got != models.FLStatusRunning {
+		t.Fatalf("status = %s, want running", got)
+	}
+	steps := env.stepsOf(fl.ID)
+	for _, step := range steps {
+		if step.Status != models.FLStepStatusCompleted || step.Error != "" {
+			t.Fatalf("step %s = %s (%q)", step.Name, step.Status, step.Error)
+		}
+	}
+	if steps[0].RetryCount != 1 {
+		t.Fatalf("upload retry count = %d, want 1", steps[0].RetryCount)
+	}
+}
+
+func TestOrchestratorFailsAfterRetries(t *testing.T) {
+	t.Run("retries exhausted", func(t *testing.T) {
+		env := newOrchestratorEnv(t)
+		// wait_ready는 한 번만 재시도
+		env.executor.errs[models.FLStepWaitReady] = []error{errors.New("not ready"), errors.New("still not ready")}
+		fl := env.createFL(models.FLStatusReady)
+
+		if err := env.orchestrator.Start(fl, []string{"p1"}); err != nil {
+			t.Fatal(err)
+		}
+		env.wait(fl.ID)
+
+		if got := env.status(fl.ID); got != models.FLStatusFailed {
+			t.Fatalf("status = %s, want failed", got)
+		}
+		steps := env.stepsOf(fl.ID)
+		if ready := steps[2]; ready.Status != models.FLStepStatusFailed || ready.RetryCount != 1 || ready.Error != "still not ready" {
+			t.Fatalf("wait_ready = %+v", ready)
+		}
+		if dispatch := steps[3]; dispatch.Status != models.FLStepStatusPending {
+			t.Fatalf("dispatch after failure = %s, want pending", dispatch.Status)
+		}
+
+		// 실패한 학습은 새 단계로 다시 실행
+		if err := env.orchestrator.Restart(fl, []string{"p1"}, "재시작", fl.UserID); err != nil {
+			t.Fatal(err)
+		}
+		env.wait(fl.ID)
+		if got := env.status(fl.ID); got != models.FLStatusRunning {
+			t.Fatalf("status after restart = %s, want running", got)
+		}
+		for _, step := range env.stepsOf(fl.ID) {
+			if step.Status != models.FLStepStatusCompleted || step.RetryCount != 0 {
+				t.Fatalf("restarted step %s = %s (retries %d)", step.Name, step.Status, step.RetryCount)
+			}
+		}
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		env := newOrchestratorEnv(t)
+		env.executor.errs[models.FLStepDispatchParticipant+":p1"] = []error{fmt.Errorf("%w: 참여자 인증 실패", ErrPermanentStepFailure)}
+		fl := env.createFL(models.FLStatusReady)
+
+		if err := env.orchestrator.Start(fl, []string{"p1"}); err != nil {
+			t.Fatal(err)
+		}
+		env.wait(fl.ID)
+
+		if got := env.status(fl.ID); got != models.FLStatusFailed {
+			t.Fatalf("status = %s, want failed", got)
+		}
+		if dispatch := env.stepsOf(fl.ID)[3]; dispatch.Status != models.FLStepStatusFailed || dispatch.RetryCount != 0 {
+			t.Fatalf("dispatch = %+v, want failed without retry", dispatch)
+		}
+	})
+}
+
+func TestOrchestratorResumesRunningStep(t *testing.T) {
+	env := newOrchestratorEnv(t)
+	fl := env.createFL(models.FLStatusStarting)
+
+	// 이전 프로세스가 start_server 실행 중에 종료된 상태
+	steps := newFLSteps(fl.ID, []string{"p1"})
+	steps[0].Status = models.FLStepStatusCompleted
+	steps[1].Status = models.FLStepStatusRunning
+	if err := env.steps.CreateSteps(steps); err != nil {
+		t.Fatal(err)
+	}
+	done := env.createFL(models.FLStatusRunning)
+	doneSteps := newFLSteps(done.ID, nil)
+	for _, step := range doneSteps {
+		step.Status = models.FLStepStatusCompleted
+	}
+	if err := env.steps.CreateSteps(doneSteps); err != nil {
+		t.Fatal(err)
+	}
+
+	env.orchestrator.ResumeUnfinished()
+	env.wait(fl.ID)
+
+	want := []string{models.FLStepStartServer, models.FLStepWaitReady, models.FLStepDispatchParticipant + ":p1"}
+	if got := env.executor.called(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("calls = %v, want %v", got, want)
+	}
+	if got := env.status(fl.ID); got != models.FLStatusRunning {
+		t.Fatalf("status = %s, want running", got)
+	}
+	for _, step := range env.stepsOf(fl.ID) {
+		if step.Status != models.FLStepStatusCompleted {
+			t.Fatalf("step %s = %s", step.Name, step.Status)
+		}
+	}
+}
+
+func TestOrchestratorCancelStopsBetweenSteps(t *testing.T) {
+	env := newOrchestratorEnv(t)
+	started := make(chan struct{})
+	// start_server는 취소될 때까지 붙잡고 있다가 정상 완료
+	env.executor.hooks[models.FLStepStartServer] = func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+	}
+	fl := env.createFL(models.FLStatusReady)
+
+	if err := env.orchestrator.Start(fl, []string{"p1"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("start_server was not executed")
+	}
+	env.orchestrator.Cancel(fl.ID, "사용자가 중지했습니다")
+
+	want := []string{models.FLStepUploadBundle, models.FLStepStartServer}
+	if got := env.executor.called(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("calls = %v, want %v", got, want)
+	}
+	steps := env.stepsOf(fl.ID)
+	if steps[1].Status != models.FLStepStatusCompleted {
+		t.Fatalf("start_server = %s, want completed", steps[1].Status)
+	}
+	for _, step := range steps[2:] {
+		if step.Status != models.FLStepStatusFailed || step.Error != "사용자가 중지했습니다" {
+			t.Fatalf("step %s = %s (%q), want failed by cancel", step.Name, step.Status, step.Error)
+		}
+	}
+	// 상태 변경은 취소한 쪽의 몫
+	if got := env.status(fl.ID); got != models.FLStatusStarting {
+		t.Fatalf("status = %s, want starting", got)
+	}
+
+	// 취소된 단계는 재개 대상이 아님
+	env.orchestrator.ResumeUnfinished()
+	env.wait(fl.ID)
+	if got := len(env.executor.called()); got != len(want) {
+		t.Fatalf("resume after cancel executed %d steps", got-len(want))
+	}
+}
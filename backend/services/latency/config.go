@@ -0,0 +1,57 @@
+package latency
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config는 지연시간 측정 설정입니다
+type Config struct {
+	SamplesPerProbe int           // 출발지-도착지 쌍마다 측정 횟수
+	StatsWindow     time.Duration // avg/min/max 재계산에 사용하는 최근 표본 기간
+	Retention       time.Duration // 측정 이력 보존 기간
+	ProbeTimeout    time.Duration // 쌍별 측정 제한 시간
+
+	// 리전 에이전트 (출발지) 목록과 후보 집계자 리전 (도착지) 주소
+	Agents  []Endpoint `json:"agents"`
+	Targets []Endpoint `json:"targets"`
+}
+
+// DefaultConfig는 기본 측정 설정을 반환합니다
+// LATENCY_PROBE_CONFIG 환경변수가 가리키는 JSON 파일에서 agents/targets 목록을 읽습니다
+func DefaultConfig() (Config, error) {
+	config := Config{
+		SamplesPerProbe: 5,
+		StatsWindow:     7 * 24 * time.Hour,
+		Retention:       30 * 24 * time.Hour,
+		ProbeTimeout:    30 * time.Second,
+	}
+
+	path := os.Getenv("LATENCY_PROBE_CONFIG")
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("지연시간 측정 설정 파일 읽기 실패 (%s): %v", path, err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("지연시간 측정 설정 파일 파싱 실패 (%s): %v", path, err)
+	}
+	return config, nil
+}
+
+// DefaultTargetAddress는 제공자가 공개하는 리전별 엔드포인트 주소를 반환합니다
+// 알려진 엔드포인트가 없으면 빈 문자열을 반환하며, 설정 파일의 targets로 지정해야 합니다
+func DefaultTargetAddress(provider, region string) string {
+	switch strings.ToLower(provider) {
+	case "aws":
+		return fmt.Sprintf("ec2.%s.amazonaws.com:443", region)
+	default:
+		return ""
+	}
+}
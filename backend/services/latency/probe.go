@@ -0,0 +1,202 @@
+package latency
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+// Endpoint는 지연시간 측정의 출발지 또는 도착지입니다
+type Endpoint struct {
+	Provider string `json:"provider"`
+	Region   string `json:"region"`
+	// 도착지는 host:port, 출발지 에이전트는 http(s)://host:port 형식의 주소
+	Address string `json:"address"`
+
+	participantID string // 참여자 출발지인 경우 참여자 ID
+}
+
+// Probe는 source에서 target까지의 왕복 지연시간을 count회 측정하는 인터페이스입니다
+type Probe interface {
+	Measure(ctx context.Context, source, target Endpoint, count int) ([]time.Duration, error)
+}
+
+// TCPProbe는 이 서버에서 target으로 TCP 연결을 맺는 시간을 측정합니다
+// source는 무시되므로 백엔드가 위치한 리전을 출발지로 설정한 경우에만 사용해야 합니다
+type TCPProbe struct {
+	Timeout time.Duration
+}
+
+// NewTCPProbe는 새 TCPProbe 인스턴스를 생성합니다
+func NewTCPProbe(timeout time.Duration) *TCPProbe {
+	return &TCPProbe{Timeout: timeout}
+}
+
+// Measure는 TCP 핸드셰이크 시간을 count회 측정합니다
+func (p *TCPProbe) Measure(ctx context.Context, source, target Endpoint, count int) ([]time.Duration, error) {
+	dialer := &net.Dialer{Timeout: p.Timeout}
+
+	samples := make([]time.Duration, 0, count)
+	for i := 0; i < count; i++ {
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", target.Address)
+		if err != nil {
+			return samples, fmt.Errorf("TCP 연결 실패 (%s): %v", target.Address, err)
+		}
+		samples = append(samples, time.Since(start))
+		conn.Close()
+	}
+	return samples, nil
+}
+
+// AgentSecretSource는 참여자 에이전트 서명 키 조회 인터페이스입니다 (repository.ParticipantAgentRepository)
+type AgentSecretSource interface {
+	GetSecret(participantID string) (string, error)
+}
+
+// AgentProbe는 출발지 에이전트(참여자 또는 리전 에이전트)에게 target 측정을 요청합니다
+// 에이전트는 POST participantagent.LatencyProbePath 요청에 LatencyProbeResponse로 응답합니다
+// 참여자 에이전트에는 참여자별 비밀 키로 서명한 토큰을 보내고, 설정된 리전 에이전트에는 서명 없이 보냅니다
+type AgentProbe struct {
+	timeout time.Duration
+	secrets AgentSecretSource
+}
+
+// NewAgentProbe는 새 AgentProbe 인스턴스를 생성합니다
+func NewAgentProbe(timeout time.Duration, secrets AgentSecretSource) *AgentProbe {
+	return &AgentProbe{timeout: timeout, secrets: secrets}
+}
+
+// Measure는 source 에이전트에서 target까지 측정한 결과를 받아옵니다
+func (p *AgentProbe) Measure(ctx context.Context, source, target Endpoint, count int) ([]time.Duration, error) {
+	if source.Address == "" {
+		return nil, fmt.Errorf("출발지 에이전트 주소가 없습니다 (%s/%s)", source.Provider, source.Region)
+	}
+
+	client, err := participantagent.NewHTTPClient(p.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("에이전트 HTTP 클라이언트 생성 실패: %v", err)
+	}
+	request := participantagent.LatencyProbeRequest{
+		ProtocolVersion: participantagent.ProtocolVersion,
+		Target:          target.Address,
+		Count:           count,
+	}
+	url := strings.TrimRight(source.Address, "/") + participantagent.LatencyProbePath
+
+	var statusCode int
+	var respBody []byte
+	if source.participantID != "" {
+		statusCode, respBody, err = p.postParticipant(ctx, client, url, source.participantID, request)
+	} else {
+		statusCode, respBody, err = postUnsigned(ctx, client, url, request)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("에이전트 측정 요청 실패 (%s): %v", url, err)
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("에이전트 응답 오류 (상태 코드: %d): %s", statusCode, string(respBody))
+	}
+
+	var result participantagent.LatencyProbeResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("에이전트 응답 파싱 실패: %v", err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("에이전트 측정 실패: %s", result.Error)
+	}
+
+	samples := make([]time.Duration, 0, len(result.SamplesMs))
+	for _, ms := range result.SamplesMs {
+		samples = append(samples, time.Duration(ms*float64(time.Millisecond)))
+	}
+	return samples, nil
+}
+
+// postParticipant는 참여자 비밀 키로 서명한 측정 요청을 보냅니다
+func (p *AgentProbe) postParticipant(ctx context.Context, client *http.Client, url, participantID string, request participantagent.LatencyProbeRequest) (int, []byte, error) {
+	if p.secrets == nil {
+		return 0, nil, fmt.Errorf("참여자 에이전트 비밀 키 저장소가 설정되지 않았습니다")
+	}
+	secret, err := p.secrets.GetSecret(participantID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("에이전트 비밀 키 조회 실패: %v", err)
+	}
+	if secret == "" {
+		return 0, nil, fmt.Errorf("참여자 %s의 에이전트 비밀 키가 발급되지 않았습니다", participantID)
+	}
+	return participantagent.PostSigned(ctx, client, url, secret, participantID, participantagent.AudienceAgent, request)
+}
+
+// postUnsigned는 운영자가 설정한 리전 에이전트에 측정 요청을 보냅니다
+func postUnsigned(ctx context.Context, client *http.Client, url string, request participantagent.LatencyProbeRequest) (int, []byte, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, fmt.Errorf("측정 요청 생성 실패: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return resp.StatusCode, respBody, err
+}
+
+// FakeProbe는 테스트용 고정 지연시간 측정기입니다
+// Latencies 키는 "출발지 리전->도착지 리전" 형식입니다
+type FakeProbe struct {
+	Latencies map[string][]time.Duration
+	Errors    map[string]error
+	Calls     []string
+}
+
+// NewFakeProbe는 새 FakeProbe 인스턴스를 생성합니다
+func NewFakeProbe() *FakeProbe {
+	return &FakeProbe{
+		Latencies: make(map[string][]time.Duration),
+		Errors:    make(map[string]error),
+	}
+}
+
+// Set은 리전 쌍의 측정 결과를 설정합니다
+func (p *FakeProbe) Set(sourceRegion, targetRegion string, samples ...time.Duration) {
+	p.Latencies[fakeProbeKey(sourceRegion, targetRegion)] = samples
+}
+
+// Measure는 설정된 결과를 count개까지 반환합니다
+func (p *FakeProbe) Measure(ctx context.Context, source, target Endpoint, count int) ([]time.Duration, error) {
+	key := fakeProbeKey(source.Region, target.Region)
+	p.Calls = append(p.Calls, key)
+
+	if err := p.Errors[key]; err != nil {
+		return nil, err
+	}
+	samples, ok := p.Latencies[key]
+	if !ok {
+		return nil, fmt.Errorf("측정 결과가 설정되지 않았습니다: %s", key)
+	}
+	if len(samples) > count {
+		samples = samples[:count]
+	}
+	return samples, nil
+}
+
+func fakeProbeKey(sourceRegion, targetRegion string) string {
+	return sourceRegion + "->" + targetRegion
+}
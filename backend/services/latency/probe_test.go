@@ -0,0 +1,87 @@
+package latency
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+type fakeAgentSecrets map[string]string
+
+func (f fakeAgentSecrets) GetSecret(participantID string) (string, error) {
+	return f[participantID], nil
+}
+
+// newProbeAgent는 측정 요청을 기록하고 고정 결과로 응답하는 에이전트입니다
+func newProbeAgent(t *testing.T, handle func(r *http.Request, body []byte) int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != participantagent.LatencyProbePath {
+			t.Errorf("request %s %s", r.Method, r.URL.Path)
+		}
+		var req participantagent.LatencyProbeRequest
+		if err := json.Unmarshal(body, &req); err != nil || req.Validate() != nil || req.Target != "target.example:443" || req.Count != 2 {
+			t.Errorf("probe request = %s", body)
+		}
+		if status := handle(r, body); status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		json.NewEncoder(w).Encode(participantagent.LatencyProbeResponse{
+			ProtocolVersion: participantagent.ProtocolVersion,
+			SamplesMs:       []float64{12.5, 14},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAgentProbeSignsParticipantRequests(t *testing.T) {
+	var verified bool
+	server := newProbeAgent(t, func(r *http.Request, body []byte) int {
+		token := participantagent.BearerToken(r.Header.Get("Authorization"))
+		if err := participantagent.VerifyRequest("secret-1", token, "p-1", participantagent.AudienceAgent, body); err != nil {
+			return http.StatusUnauthorized
+		}
+		verified = true
+		return http.StatusOK
+	})
+	probe := NewAgentProbe(time.Second, fakeAgentSecrets{"p-1": "secret-1"})
+	target := Endpoint{Region: "ap-northeast-2", Address: "target.example:443"}
+
+	samples, err := probe.Measure(context.Background(), Endpoint{Region: "seoul-site", Address: server.URL, participantID: "p-1"}, target, 2)
+	if err != nil || !verified {
+		t.Fatalf("Measure = %v, %v (verified %v)", samples, err, verified)
+	}
+	if len(samples) != 2 || samples[0] != 12500*time.Microsecond || samples[1] != 14*time.Millisecond {
+		t.Fatalf("samples = %v", samples)
+	}
+
+	// 비밀 키가 발급되지 않은 참여자에게는 요청하지 않음
+	verified = false
+	if _, err := probe.Measure(context.Background(), Endpoint{Address: server.URL, participantID: "p-2"}, target, 2); err == nil || verified {
+		t.Fatalf("participant without secret: err = %v", err)
+	}
+}
+
+func TestAgentProbeRegionAgentIsUnsigned(t *testing.T) {
+	server := newProbeAgent(t, func(r *http.Request, body []byte) int {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("region agent request carries a token")
+		}
+		return http.StatusOK
+	})
+	probe := NewAgentProbe(time.Second, fakeAgentSecrets{})
+
+	samples, err := probe.Measure(context.Background(), Endpoint{Region: "us-east-1", Address: server.URL + "/"}, Endpoint{Address: "target.example:443"}, 2)
+	if err != nil || len(samples) != 2 {
+		t.Fatalf("Measure = %v, %v", samples, err)
+	}
+}
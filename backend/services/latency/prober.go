@@ -0,0 +1,393 @@
+package latency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services/participantagent"
+)
+
+// 참여자 OpenStack 사이트를 지연시간 매트릭스에 기록할 때 사용하는 제공자 이름
+const participantProvider = "openstack"
+
+// ErrProbeRunning은 이미 측정이 진행 중일 때 반환됩니다
+var ErrProbeRunning = errors.New("latency probe already running")
+
+// RunResult는 한 번의 측정 실행 결과입니다
+type RunResult struct {
+	Pairs     int       `json:"pairs"`
+	Samples   int       `json:"samples"`
+	Failures  int       `json:"failures"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  float64   `json:"duration_seconds"`
+}
+
+// PriceSource는 후보 집계자 리전을 얻기 위한 가격표 조회 인터페이스입니다
+type PriceSource interface {
+	GetAllCloudPrices() ([]*models.CloudPrice, error)
+}
+
+// SampleStore는 측정 이력 저장소 인터페이스입니다 (repository.LatencySampleRepository)
+type SampleStore interface {
+	CreateSamples(samples []*models.LatencySample) error
+	GetSuccessfulSamplesSince(sourceProviderID, sourceRegionID, targetProviderID, targetRegionID int, since time.Time) ([]*models.LatencySample, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// LatencyStore는 리전 간 지연시간 매트릭스 저장소 인터페이스입니다 (repository.CloudLatencyRepository)
+type LatencyStore interface {
+	UpsertCloudLatency(latency *models.CloudLatency) error
+}
+
+// ProviderStore는 제공자 조회/생성 인터페이스입니다 (repository.ProviderRepository)
+type ProviderStore interface {
+	CreateOrGetProvider(name string) (*models.Provider, error)
+}
+
+// RegionStore는 리전 조회/생성 인터페이스입니다 (repository.RegionRepository)
+type RegionStore interface {
+	CreateOrGetRegion(name string) (*models.Region, error)
+}
+
+// ParticipantSource는 측정 출발지로 쓸 참여자 조회 인터페이스입니다 (repository.ParticipantRepository)
+type ParticipantSource interface {
+	GetAllWithEndpoint() ([]*models.Participant, error)
+}
+
+// Prober는 리전 에이전트와 참여자에서 후보 집계자 리전까지 지연시간을 측정해
+// 이력을 저장하고 CloudLatency 매트릭스의 avg/min/max를 갱신합니다
+type Prober struct {
+	probe        Probe
+	sampleRepo   SampleStore
+	latencyRepo  LatencyStore
+	providerRepo ProviderStore
+	regionRepo   RegionStore
+	prices       PriceSource
+	participants ParticipantSource
+	config       Config
+
+	mutex   sync.Mutex
+	running bool
+}
+
+// NewProber는 새 Prober 인스턴스를 생성합니다
+func NewProber(
+	probe Probe,
+	sampleRepo SampleStore,
+	latencyRepo LatencyStore,
+	providerRepo ProviderStore,
+	regionRepo RegionStore,
+	prices PriceSource,
+	participants ParticipantSource,
+	config Config,
+) *Prober {
+	return &Prober{
+		probe:        probe,
+		sampleRepo:   sampleRepo,
+		latencyRepo:  latencyRepo,
+		providerRepo: providerRepo,
+		regionRepo:   regionRepo,
+		prices:       prices,
+		participants: participants,
+		config:       config,
+	}
+}
+
+// Start는 interval마다 측정을 실행하는 백그라운드 루프를 시작합니다
+func (p *Prober) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := p.RunOnce(ctx); err != nil && !errors.Is(err, ErrProbeRunning) {
+					log.Printf("지연시간 주기 측정 실패: %v", err)
+				}
+			}
+		}
+	}()
+	log.Printf("지연시간 주기 측정 시작 (간격: %v)", interval)
+}
+
+// RunOnce는 모든 출발지-도착지 쌍을 한 번 측정합니다
+func (p *Prober) RunOnce(ctx context.Context) (*RunResult, error) {
+	if err := p.acquire(); err != nil {
+		return nil, err
+	}
+	defer p.release()
+	return p.run(ctx)
+}
+
+// RunAsync는 측정을 백그라운드에서 시작합니다
+// 이미 실행 중이면 ErrProbeRunning을 즉시 반환합니다
+func (p *Prober) RunAsync(ctx context.Context) error {
+	if err := p.acquire(); err != nil {
+		return err
+	}
+	go func() {
+		defer p.release()
+		if _, err := p.run(ctx); err != nil {
+			log.Printf("지연시간 측정 실패: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (p *Prober) acquire() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.running {
+		return ErrProbeRunning
+	}
+	p.running = true
+	return nil
+}
+
+func (p *Prober) release() {
+	p.mutex.Lock()
+	p.running = false
+	p.mutex.Unlock()
+}
+
+func (p *Prober) run(ctx context.Context) (*RunResult, error) {
+	result := &RunResult{StartedAt: time.Now()}
+
+	targets, err := p.candidateTargets()
+	if err != nil {
+		return nil, err
+	}
+	sources, err := p.sources()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("지연시간 측정 시작 - 출발지 %d개, 도착지 %d개", len(sources), len(targets))
+
+	for _, source := range sources {
+		for _, target := range targets {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			if strings.EqualFold(source.Provider, target.Provider) && source.Region == target.Region {
+				continue
+			}
+
+			samples, failed, err := p.MeasurePair(ctx, source, target)
+			if err != nil {
+				log.Printf("지연시간 기록 실패 (%s/%s → %s/%s): %v", source.Provider, source.Region, target.Provider, target.Region, err)
+			}
+			result.Pairs++
+			result.Samples += samples
+			if failed {
+				result.Failures++
+			}
+		}
+	}
+
+	if p.config.Retention > 0 {
+		if deleted, err := p.sampleRepo.DeleteBefore(time.Now().Add(-p.config.Retention)); err != nil {
+			log.Printf("오래된 지연시간 이력 삭제 실패: %v", err)
+		} else if deleted > 0 {
+			log.Printf("오래된 지연시간 이력 %d건 삭제", deleted)
+		}
+	}
+
+	result.Duration = time.Since(result.StartedAt).Seconds()
+	log.Printf("지연시간 측정 완료 - 쌍: %d, 표본: %d, 실패: %d", result.Pairs, result.Samples, result.Failures)
+	return result, nil
+}
+
+// MeasurePair는 한 쌍을 측정해 표본을 저장하고 avg/min/max를 재계산합니다
+// 반환값은 저장한 성공 표본 수와 측정 실패 여부입니다
+func (p *Prober) MeasurePair(ctx context.Context, source, target Endpoint) (int, bool, error) {
+	sourceProvider, err := p.providerRepo.CreateOrGetProvider(strings.ToLower(source.Provider))
+	if err != nil {
+		return 0, false, err
+	}
+	sourceRegion, err := p.regionRepo.CreateOrGetRegion(source.Region)
+	if err != nil {
+		return 0, false, err
+	}
+	targetProvider, err := p.providerRepo.CreateOrGetProvider(strings.ToLower(target.Provider))
+	if err != nil {
+		return 0, false, err
+	}
+	targetRegion, err := p.regionRepo.CreateOrGetRegion(target.Region)
+	if err != nil {
+		return 0, false, err
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, p.config.ProbeTimeout)
+	durations, probeErr := p.probe.Measure(probeCtx, source, target, p.config.SamplesPerProbe)
+	cancel()
+
+	measuredAt := time.Now()
+	newSample := func() *models.LatencySample {
+		sample := &models.LatencySample{
+			SourceProviderID: sourceProvider.ID,
+			SourceRegionID:   sourceRegion.ID,
+			TargetProviderID: targetProvider.ID,
+			TargetRegionID:   targetRegion.ID,
+			ProbeType:        models.LatencyProbeRegion,
+			MeasuredAt:       measuredAt,
+		}
+		if source.participantID != "" {
+			participantID := source.participantID
+			sample.ProbeType = models.LatencyProbeParticipant
+			sample.ParticipantID = &participantID
+		}
+		return sample
+	}
+
+	var samples []*models.LatencySample
+	for _, d := range durations {
+		ms := math.Round(float64(d)/float64(time.Millisecond)*100) / 100
+		sample := newSample()
+		sample.LatencyMs = &ms
+		sample.Success = true
+		samples = append(samples, sample)
+	}
+	if probeErr != nil {
+		sample := newSample()
+		sample.Error = probeErr.Error()
+		samples = append(samples, sample)
+	}
+
+	if err := p.sampleRepo.CreateSamples(samples); err != nil {
+		return 0, probeErr != nil, fmt.Errorf("측정 이력 저장 실패: %v", err)
+	}
+	if len(durations) == 0 {
+		return 0, true, nil
+	}
+
+	if err := p.recompute(sourceProvider.ID, sourceRegion.ID, targetProvider.ID, targetRegion.ID); err != nil {
+		return len(durations), probeErr != nil, err
+	}
+	return len(durations), probeErr != nil, nil
+}
+
+// recompute는 최근 성공 표본으로 리전 쌍의 avg/min/max를 다시 계산해 CloudLatency에 반영합니다
+func (p *Prober) recompute(sourceProviderID, sourceRegionID, targetProviderID, targetRegionID int) error {
+	since := time.Now().Add(-p.config.StatsWindow)
+	samples, err := p.sampleRepo.GetSuccessfulSamplesSince(sourceProviderID, sourceRegionID, targetProviderID, targetRegionID, since)
+	if err != nil {
+		return fmt.Errorf("최근 측정 이력 조회 실패: %v", err)
+	}
+
+	avg, min, max, ok := summarize(samples)
+	if !ok {
+		return nil
+	}
+
+	return p.latencyRepo.UpsertCloudLatency(&models.CloudLatency{
+		SourceProviderID: sourceProviderID,
+		SourceRegionID:   sourceRegionID,
+		TargetProviderID: targetProviderID,
+		TargetRegionID:   targetRegionID,
+		AvgLatency:       avg,
+		MinLatency:       &min,
+		MaxLatency:       &max,
+	})
+}
+
+// summarize는 성공 표본의 평균/최소/최대 지연시간을 계산합니다
+func summarize(samples []*models.LatencySample) (avg, min, max float64, ok bool) {
+	var sum float64
+	count := 0
+	for _, s := range samples {
+		if !s.Success || s.LatencyMs == nil {
+			continue
+		}
+		v := *s.LatencyMs
+		if count == 0 || v < min {
+			min = v
+		}
+		if count == 0 || v > max {
+			max = v
+		}
+		sum += v
+		count++
+	}
+	if count == 0 {
+		return 0, 0, 0, false
+	}
+	avg = math.Round(sum/float64(count)*100) / 100
+	return avg, min, max, true
+}
+
+// candidateTargets는 가격 정보가 있는 후보 집계자 리전과 측정 주소를 반환합니다
+func (p *Prober) candidateTargets() ([]Endpoint, error) {
+	configured := make(map[string]Endpoint)
+	for _, t := range p.config.Targets {
+		configured[endpointKey(t.Provider, t.Region)] = t
+	}
+
+	prices, err := p.prices.GetAllCloudPrices()
+	if err != nil {
+		return nil, fmt.Errorf("후보 리전 조회 실패: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	var targets []Endpoint
+	add := func(t Endpoint) {
+		key := endpointKey(t.Provider, t.Region)
+		if seen[key] || t.Address == "" {
+			return
+		}
+		seen[key] = true
+		targets = append(targets, t)
+	}
+
+	for _, t := range p.config.Targets {
+		add(t)
+	}
+	for _, price := range prices {
+		key := endpointKey(price.Provider.Name, price.Region.Name)
+		if _, ok := configured[key]; ok {
+			continue
+		}
+		add(Endpoint{
+			Provider: strings.ToLower(price.Provider.Name),
+			Region:   price.Region.Name,
+			Address:  DefaultTargetAddress(price.Provider.Name, price.Region.Name),
+		})
+	}
+	return targets, nil
+}
+
+// sources는 설정된 리전 에이전트와 참여자 엔드포인트를 출발지로 반환합니다
+func (p *Prober) sources() ([]Endpoint, error) {
+	sources := append([]Endpoint{}, p.config.Agents...)
+
+	participants, err := p.participants.GetAllWithEndpoint()
+	if err != nil {
+		return nil, fmt.Errorf("참여자 조회 실패: %v", err)
+	}
+	for _, participant := range participants {
+		if participant.Region == "" || len(participant.Region) > 30 {
+			log.Printf("참여자 %s의 리전 이름이 유효하지 않아 측정에서 제외합니다: %q", participant.ID, participant.Region)
+			continue
+		}
+		sources = append(sources, Endpoint{
+			Provider:      participantProvider,
+			Region:        participant.Region,
+			Address:       participantagent.AgentURL(participant.OpenStackEndpoint, ""),
+			participantID: participant.ID,
+		})
+	}
+	return sources, nil
+}
+
+func endpointKey(provider, region string) string {
+	return strings.ToLower(provider) + "/" + region
+}
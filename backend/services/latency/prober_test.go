@@ -0,0 +1,237 @@
+package latency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+type fakeSampleStore struct {
+	samples []*models.LatencySample
+}
+
+func (f *fakeSampleStore) CreateSamples(samples []*models.LatencySample) error {
+	f.samples = append(f.samples, samples...)
+	return nil
+}
+
+func (f *fakeSampleStore) GetSuccessfulSamplesSince(sourceProviderID, sourceRegionID, targetProviderID, targetRegionID int, since time.Time) ([]*models.LatencySample, error) {
+	var result []*models.LatencySample
+	for _, s := range f.samples {
+		if s.Success && !s.MeasuredAt.Before(since) &&
+			s.SourceProviderID == sourceProviderID && s.SourceRegionID == sourceRegionID &&
+			s.TargetProviderID == targetProviderID && s.TargetRegionID == targetRegionID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeSampleStore) DeleteBefore(before time.Time) (int64, error) {
+	kept := f.samples[:0]
+	for _, s := range f.samples {
+		if !s.MeasuredAt.Before(before) {
+			kept = append(kept, s)
+		}
+	}
+	deleted := int64(len(f.samples) - len(kept))
+	f.samples = kept
+	return deleted, nil
+}
+
+type fakeLatencyStore struct {
+	latencies []*models.CloudLatency
+}
+
+func (f *fakeLatencyStore) UpsertCloudLatency(latency *models.CloudLatency) error {
+	for i, existing := range f.latencies {
+		if existing.SourceProviderID == latency.SourceProviderID && existing.SourceRegionID == latency.SourceRegionID &&
+			existing.TargetProviderID == latency.TargetProviderID && existing.TargetRegionID == latency.TargetRegionID {
+			f.latencies[i] = latency
+			return nil
+		}
+	}
+	f.latencies = append(f.latencies, latency)
+	return nil
+}
+
+// fakeNames는 이름마다 고정 ID를 부여하는 제공자/리전 저장소입니다
+type fakeNames map[string]int
+
+func (f fakeNames) id(name string) int {
+	if _, ok := f[name]; !ok {
+		f[name] = len(f) + 1
+	}
+	return f[name]
+}
+
+func (f fakeNames) CreateOrGetProvider(name string) (*models.Provider, error) {
+	return &models.Provider{ID: f.id(name), Name: name}, nil
+}
+
+func (f fakeNames) CreateOrGetRegion(name string) (*models.Region, error) {
+	return &models.Region{ID: f.id(name), Name: name}, nil
+}
+
+type fakePrices []*models.CloudPrice
+
+func (f fakePrices) GetAllCloudPrices() ([]*models.CloudPrice, error) { return f, nil }
+
+type fakeParticipants []*models.Participant
+
+func (f fakeParticipants) GetAllWithEndpoint() ([]*models.Participant, error) { return f, nil }
+
+type testProber struct {
+	*Prober
+	probe     *FakeProbe
+	samples   *fakeSampleStore
+	latencies *fakeLatencyStore
+	providers fakeNames
+	regions   fakeNames
+}
+
+func newTestProber(participants ...*models.Participant) *testProber {
+	tp := &testProber{
+		probe:     NewFakeProbe(),
+		samples:   &fakeSampleStore{},
+		latencies: &fakeLatencyStore{},
+		providers: fakeNames{},
+		regions:   fakeNames{},
+	}
+	prices := fakePrices{
+		{Provider: models.Provider{Name: "AWS"}, Region: models.Region{Name: "ap-northeast-2"}, InstanceType: "t3.medium"},
+		{Provider: models.Provider{Name: "AWS"}, Region: models.Region{Name: "ap-northeast-2"}, InstanceType: "t3.large"},
+		{Provider: models.Provider{Name: "GCP"}, Region: models.Region{Name: "asia-northeast3"}, InstanceType: "e2-medium"},
+		{Provider: models.Provider{Name: "GCP"}, Region: models.Region{Name: "us-central1"}, InstanceType: "e2-medium"},
+	}
+	config := Config{
+		SamplesPerProbe: 3,
+		StatsWindow:     time.Hour,
+		Retention:       24 * time.Hour,
+		ProbeTimeout:    time.Second,
+		Agents:          []Endpoint{{Provider: "aws", Region: "us-east-1", Address: "http://agent.us-east-1:8080"}},
+		// us-central1은 기본 주소가 없고 설정되지 않았으므로 측정 대상에서 제외
+		Targets: []Endpoint{{Provider: "gcp", Region: "asia-northeast3", Address: "asia-northeast3.example:443"}},
+	}
+	tp.Prober = NewProber(tp.probe, tp.samples, tp.latencies, tp.providers, tp.regions, prices, fakeParticipants(participants), config)
+	return tp
+}
+
+func (tp *testProber) latency(sourceProvider, sourceRegion, targetProvider, targetRegion string) *models.CloudLatency {
+	for _, l := range tp.latencies.latencies {
+		if l.SourceProviderID == tp.providers[sourceProvider] && l.SourceRegionID == tp.regions[sourceRegion] &&
+			l.TargetProviderID == tp.providers[targetProvider] && l.TargetRegionID == tp.regions[targetRegion] {
+			return l
+		}
+	}
+	return nil
+}
+
+func TestRunOnceMeasuresAgentsAndParticipants(t *testing.T) {
+	tp := newTestProber(&models.Participant{ID: "p-1", Region: "seoul-site", OpenStackEndpoint: "http://10.0.0.1/"})
+	ms := time.Millisecond
+	tp.probe.Set("us-east-1", "ap-northeast-2", 180*ms, 200*ms, 190*ms, 999*ms)
+	tp.probe.Set("us-east-1", "asia-northeast3", 170*ms, 175*ms, 180*ms)
+	tp.probe.Set("seoul-site", "ap-northeast-2", 5*ms, 7*ms, 6*ms)
+	tp.probe.Errors["seoul-site->asia-northeast3"] = errors.New("agent unreachable")
+
+	result, err := tp.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Pairs != 4 || result.Samples != 9 || result.Failures != 1 {
+		t.Fatalf("result = %+v, want 4 pairs, 9 samples, 1 failure", result)
+	}
+
+	// SamplesPerProbe(3)개까지만 사용
+	l := tp.latency("aws", "us-east-1", "aws", "ap-northeast-2")
+	if l == nil || l.AvgLatency != 190 || *l.MinLatency != 180 || *l.MaxLatency != 200 {
+		t.Fatalf("aws us-east-1 -> ap-northeast-2 = %+v", l)
+	}
+	l = tp.latency("openstack", "seoul-site", "aws", "ap-northeast-2")
+	if l == nil || l.AvgLatency != 6 {
+		t.Fatalf("participant latency = %+v", l)
+	}
+	if l := tp.latency("openstack", "seoul-site", "gcp", "asia-northeast3"); l != nil {
+		t.Fatalf("failed pair must not update the matrix: %+v", l)
+	}
+
+	var participantSamples, failed int
+	for _, s := range tp.samples.samples {
+		if s.ProbeType == models.LatencyProbeParticipant {
+			participantSamples++
+			if s.ParticipantID == nil || *s.ParticipantID != "p-1" {
+				t.Fatalf("participant sample without participant id: %+v", s)
+			}
+		}
+		if !s.Success {
+			failed++
+			if s.LatencyMs != nil || s.Error != "agent unreachable" {
+				t.Fatalf("failure sample = %+v", s)
+			}
+		}
+	}
+	if participantSamples != 4 || failed != 1 {
+		t.Fatalf("participant samples = %d, failed = %d", participantSamples, failed)
+	}
+}
+
+func TestRunOnceSkipsSameRegionAndInvalidParticipants(t *testing.T) {
+	tp := newTestProber(&models.Participant{ID: "p-2", Region: "", OpenStackEndpoint: "http://10.0.0.2"})
+	tp.config.Agents[0].Region = "ap-northeast-2"
+	tp.probe.Set("ap-northeast-2", "asia-northeast3", 30*time.Millisecond)
+
+	result, err := tp.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Pairs != 1 {
+		t.Fatalf("pairs = %d, want 1", result.Pairs)
+	}
+	if len(tp.probe.Calls) != 1 || tp.probe.Calls[0] != "ap-northeast-2->asia-northeast3" {
+		t.Fatalf("probe calls = %v", tp.probe.Calls)
+	}
+}
+
+func TestRecomputeUsesOnlyRecentSamples(t *testing.T) {
+	tp := newTestProber()
+	tp.probe.Set("us-east-1", "ap-northeast-2", 100*time.Millisecond)
+
+	// 통계 기간(1시간)을 벗어난 표본은 평균에 포함하지 않음
+	old := 10.0
+	tp.samples.samples = append(tp.samples.samples, &models.LatencySample{
+		SourceProviderID: tp.providers.id("aws"),
+		SourceRegionID:   tp.regions.id("us-east-1"),
+		TargetProviderID: tp.providers.id("aws"),
+		TargetRegionID:   tp.regions.id("ap-northeast-2"),
+		LatencyMs:        &old,
+		Success:          true,
+		MeasuredAt:       time.Now().Add(-2 * time.Hour),
+	})
+
+	source := Endpoint{Provider: "aws", Region: "us-east-1"}
+	target := Endpoint{Provider: "aws", Region: "ap-northeast-2"}
+	if _, _, err := tp.MeasurePair(context.Background(), source, target); err != nil {
+		t.Fatal(err)
+	}
+	if l := tp.latency("aws", "us-east-1", "aws", "ap-northeast-2"); l == nil || l.AvgLatency != 100 || *l.MinLatency != 100 {
+		t.Fatalf("latency = %+v", l)
+	}
+}
+
+func TestRunOnceRejectsConcurrentRun(t *testing.T) {
+	tp := newTestProber()
+	if err := tp.acquire(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tp.RunOnce(context.Background()); !errors.Is(err, ErrProbeRunning) {
+		t.Fatalf("err = %v, want ErrProbeRunning", err)
+	}
+	if err := tp.RunAsync(context.Background()); !errors.Is(err, ErrProbeRunning) {
+		t.Fatalf("async err = %v, want ErrProbeRunning", err)
+	}
+	tp.release()
+}
@@ -0,0 +1,132 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Message는 발송할 메일 한 통입니다
+type Message struct {
+	To      string
+	Subject string
+	Body    string // text/plain 본문
+}
+
+// Mailer는 메일 발송 구현체의 공통 인터페이스입니다
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// memoryMailerCapacity는 MemoryMailer가 보관하는 최근 메일 수입니다
+const memoryMailerCapacity = 100
+
+// NewMailerFromEnv는 환경 변수로 Mailer를 생성합니다
+//
+//	MAIL_DRIVER=smtp   - SMTP_HOST/SMTP_PORT/SMTP_USERNAME/SMTP_PASSWORD/SMTP_FROM (SMTP_HOST가 있으면 기본값)
+//	MAIL_DRIVER=memory - 메일을 발송하지 않음 (개발용, 운영 모드에서는 사용 불가)
+//
+// 메일에는 재설정/인증 토큰이 담기므로 설정이 빠졌을 때 메모리 구현으로 조용히 대체하지 않습니다
+func NewMailerFromEnv() (Mailer, error) {
+	host := os.Getenv("SMTP_HOST")
+	driver := strings.ToLower(os.Getenv("MAIL_DRIVER"))
+	if driver == "" && host != "" {
+		driver = "smtp"
+	}
+
+	switch driver {
+	case "smtp":
+	case "memory":
+		mode := os.Getenv("GIN_MODE")
+		if mode == "release" || mode == "production" {
+			return nil, fmt.Errorf("MAIL_DRIVER=memory는 운영 모드에서 사용할 수 없습니다")
+		}
+		log.Printf("MAIL_DRIVER=memory: 메일을 실제로 발송하지 않습니다 (개발용)")
+		return NewMemoryMailer(), nil
+	case "":
+		return nil, fmt.Errorf("SMTP_HOST가 설정되지 않았습니다 (개발 환경에서는 MAIL_DRIVER=memory를 명시하세요)")
+	default:
+		return nil, fmt.Errorf("지원하지 않는 MAIL_DRIVER입니다: %q", driver)
+	}
+
+	if host == "" {
+		return nil, fmt.Errorf("SMTP_HOST가 설정되지 않았습니다")
+	}
+
+	port := 587
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil || p <= 0 {
+			return nil, fmt.Errorf("SMTP_PORT 값이 올바르지 않습니다: %q", v)
+		}
+		port = p
+	}
+
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		return nil, fmt.Errorf("SMTP_FROM이 설정되지 않았습니다")
+	}
+
+	return NewSMTPMailer(SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}), nil
+}
+
+// MemoryMailer는 메일을 발송하지 않고 최근 메일만 메모리에 보관합니다 (테스트/개발용)
+// 본문에는 토큰이 담기므로 로그로 남기지 않습니다
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+// NewMemoryMailer는 새 MemoryMailer 인스턴스를 생성합니다
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send는 메일을 메모리에 보관합니다 (memoryMailerCapacity를 넘으면 오래된 메일부터 버림)
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	if len(m.sent) >= memoryMailerCapacity {
+		m.sent = append(m.sent[:0], m.sent[len(m.sent)-memoryMailerCapacity+1:]...)
+	}
+	m.sent = append(m.sent, msg)
+	m.mu.Unlock()
+
+	log.Printf("메일 (미발송) to=%s subject=%q", msg.To, msg.Subject)
+	return nil
+}
+
+// Sent는 지금까지 보관된 메일 목록의 복사본을 반환합니다
+func (m *MemoryMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// LastTo는 수신자에게 마지막으로 보낸 메일을 반환합니다
+func (m *MemoryMailer) LastTo(to string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.sent) - 1; i >= 0; i-- {
+		if m.sent[i].To == to {
+			return m.sent[i], true
+		}
+	}
+	return Message{}, false
+}
+
+// Reset은 보관된 메일을 비웁니다
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	m.sent = nil
+	m.mu.Unlock()
+}
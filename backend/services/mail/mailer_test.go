@@ -0,0 +1,61 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestNewMailerFromEnv(t *testing.T) {
+	cases := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+		want    string
+	}{
+		{name: "no driver", env: map[string]string{}, wantErr: true},
+		{name: "explicit memory", env: map[string]string{"MAIL_DRIVER": "memory"}, want: "*mail.MemoryMailer"},
+		{name: "memory in production", env: map[string]string{"MAIL_DRIVER": "memory", "GIN_MODE": "release"}, wantErr: true},
+		{name: "smtp from host", env: map[string]string{"SMTP_HOST": "smtp.example.com", "SMTP_FROM": "no-reply@example.com"}, want: "*mail.SMTPMailer"},
+		{name: "smtp without host", env: map[string]string{"MAIL_DRIVER": "smtp"}, wantErr: true},
+		{name: "unknown driver", env: map[string]string{"MAIL_DRIVER": "sendmail"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{"MAIL_DRIVER", "SMTP_HOST", "SMTP_PORT", "SMTP_FROM", "GIN_MODE"} {
+				t.Setenv(key, tc.env[key])
+			}
+			mailer, err := NewMailerFromEnv()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %T", mailer)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprintf("%T", mailer); got != tc.want {
+				t.Fatalf("mailer = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMemoryMailerKeepsRecentMessages(t *testing.T) {
+	m := NewMemoryMailer()
+	for i := 0; i < memoryMailerCapacity+10; i++ {
+		m.Send(context.Background(), Message{To: fmt.Sprintf("user%d@example.com", i), Subject: "subject"})
+	}
+
+	sent := m.Sent()
+	if len(sent) != memoryMailerCapacity {
+		t.Fatalf("kept %d messages, want %d", len(sent), memoryMailerCapacity)
+	}
+	if sent[0].To != "user10@example.com" {
+		t.Fatalf("oldest kept message = %s", sent[0].To)
+	}
+	if _, ok := m.LastTo(fmt.Sprintf("user%d@example.com", memoryMailerCapacity+9)); !ok {
+		t.Fatal("latest message was dropped")
+	}
+}
@@ -0,0 +1,126 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig는 SMTP 서버 접속 설정입니다
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// SMTPMailer는 SMTP 서버를 통해 메일을 발송합니다
+// 465 포트는 암묵적 TLS, 그 외에는 서버가 지원하면 STARTTLS를 사용합니다
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+// NewSMTPMailer는 새 SMTPMailer 인스턴스를 생성합니다
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	if config.Timeout <= 0 {
+		config.Timeout = 15 * time.Second
+	}
+	return &SMTPMailer{config: config}
+}
+
+// Send는 메일을 발송합니다
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := &net.Dialer{Timeout: m.config.Timeout}
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+
+	var conn net.Conn
+	var err error
+	if m.config.Port == 465 {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("SMTP 서버 연결 실패: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(m.config.Timeout))
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP 세션 시작 실패: %v", err)
+	}
+	defer client.Close()
+
+	if m.config.Port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("STARTTLS 실패: %v", err)
+			}
+		}
+	}
+
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP 인증 실패: %v", err)
+		}
+	}
+
+	if err := client.Mail(m.config.From); err != nil {
+		return fmt.Errorf("발신자 설정 실패: %v", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("수신자 설정 실패: %v", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("메일 본문 전송 시작 실패: %v", err)
+	}
+	if _, err := w.Write(m.buildMessage(msg)); err != nil {
+		w.Close()
+		return fmt.Errorf("메일 본문 전송 실패: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("메일 본문 전송 완료 실패: %v", err)
+	}
+
+	return client.Quit()
+}
+
+// buildMessage는 헤더와 base64로 인코딩한 본문으로 RFC 5322 메시지를 구성합니다
+func (m *SMTPMailer) buildMessage(msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + m.config.From + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	b.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+
+	return []byte(b.String())
+}
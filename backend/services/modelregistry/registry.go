@@ -0,0 +1,372 @@
+package modelregistry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+	"github.com/Mungge/Fleecy-Cloud/services"
+	"github.com/Mungge/Fleecy-Cloud/services/audit"
+	"github.com/Mungge/Fleecy-Cloud/utils"
+)
+
+// ErrCheckpointNotFound는 요청한 라운드의 체크포인트가 레지스트리와 집계자 어디에도 없음을 나타냅니다
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// checkpointFilePattern은 server_app.py가 라운드마다 저장하는 체크포인트 파일 이름입니다 (round-001.pt)
+var checkpointFilePattern = regexp.MustCompile(`^round-(\d+)\.pt$`)
+
+// Registry는 집계자의 라운드 체크포인트를 모델 저장소로 복사하고 버전으로 기록합니다
+type Registry struct {
+	repo           *repository.ModelVersionRepository
+	flRepo         *repository.FederatedLearningRepository
+	aggregatorRepo *repository.AggregatorRepository
+	store          Store
+	// connect는 집계자 연결을 반환합니다 (테스트에서 교체)
+	connect func(aggregatorID, host string) aggregatorConn
+
+	mutex    sync.Mutex
+	jobLocks map[string]*sync.Mutex // 연합학습 작업별 수집 직렬화
+}
+
+// NewRegistry는 새 Registry 인스턴스를 생성합니다
+func NewRegistry(repo *repository.ModelVersionRepository, flRepo *repository.FederatedLearningRepository, aggregatorRepo *repository.AggregatorRepository, sshService *services.SSHKeypairService, store Store) *Registry {
+	return &Registry{
+		repo:           repo,
+		flRepo:         flRepo,
+		aggregatorRepo: aggregatorRepo,
+		store:          store,
+		connect: func(aggregatorID, host string) aggregatorConn {
+			return sshService.AggregatorConnection(aggregatorID, host)
+		},
+		jobLocks: make(map[string]*sync.Mutex),
+	}
+}
+
+// Start는 interval마다 실행 중인 연합학습 작업의 새 체크포인트를 수집하는 백그라운드 루프를 시작합니다
+func (r *Registry) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.collectRunning(ctx)
+			}
+		}
+	}()
+	log.Printf("모델 체크포인트 수집 시작 (간격: %v, 저장소: %s)", interval, r.store.Name())
+}
+
+func (r *Registry) collectRunning(ctx context.Context) {
+	running, err := r.flRepo.GetByStatus(models.FLStatusRunning)
+	if err != nil {
+		log.Printf("실행 중인 연합학습 조회 실패: %v", err)
+		return
+	}
+	for _, fl := range running {
+		if _, err := r.CollectCheckpoints(ctx, fl); err != nil {
+			log.Printf("[%s] 체크포인트 수집 실패: %v", fl.ID, err)
+		}
+	}
+}
+
+// CollectForAggregator는 집계자를 사용하는 모든 연합학습 작업의 체크포인트를 수집합니다
+// 집계자 인프라를 삭제하기 전에 호출해 체크포인트가 사라지지 않도록 합니다
+func (r *Registry) CollectForAggregator(ctx context.Context, aggregatorID string) error {
+	fls, err := r.flRepo.GetByAggregatorID(aggregatorID)
+	if err != nil {
+		return fmt.Errorf("연합학습 작업 조회 실패: %v", err)
+	}
+	var errs []error
+	for _, fl := range fls {
+		if _, err := r.CollectCheckpoints(ctx, fl); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fl.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CollectCheckpoints는 집계자에 있지만 아직 등록되지 않은 라운드 체크포인트를 복사하고 등록한 수를 반환합니다
+func (r *Registry) CollectCheckpoints(ctx context.Context, fl *models.FederatedLearning) (int, error) {
+	lock := r.jobLock(fl.ID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// 수집 도중 재시작되었을 수 있으므로 최신 재시작 횟수로 기록
+	if current, err := r.flRepo.GetByID(fl.ID); err == nil && current != nil {
+		fl = current
+	}
+
+	aggregator, conn, err := r.aggregatorConnection(fl)
+	if err != nil {
+		return 0, err
+	}
+	dir := checkpointDir(fl.ID)
+	output, _, err := conn.ExecuteCommand(fmt.Sprintf("ls -1 %s 2>/dev/null || true", dir))
+	if err != nil {
+		return 0, fmt.Errorf("체크포인트 목록 조회 실패: %v", err)
+	}
+	files := make(map[int]string)
+	for _, name := range strings.Split(output, "\n") {
+		name = strings.TrimSpace(name)
+		if m := checkpointFilePattern.FindStringSubmatch(name); m != nil {
+			if round, err := strconv.Atoi(m[1]); err == nil && round > 0 {
+				files[round] = name
+			}
+		}
+	}
+	if len(files) == 0 {
+		return 0, nil
+	}
+
+	existing, err := r.repo.GetByFederatedLearningID(fl.ID)
+	if err != nil {
+		return 0, fmt.Errorf("등록된 모델 버전 조회 실패: %v", err)
+	}
+	// 현재 재시작 회차에 이미 등록된 라운드
+	versions := make(map[int]*models.ModelVersion, len(existing))
+	for _, version := range existing {
+		if version.Attempt == fl.Attempt {
+			versions[version.Round] = version
+		}
+	}
+
+	metrics := make(map[int]models.ModelMetric)
+	if rounds, err := r.aggregatorRepo.GetTrainingRoundsByAggregatorID(aggregator.ID); err == nil {
+		for _, round := range rounds {
+			metrics[round.Round] = round.ModelMetrics
+		}
+	} else {
+		log.Printf("[%s] 라운드 메트릭 조회 실패 (메트릭 없이 등록): %v", fl.ID, err)
+	}
+
+	rounds := make([]int, 0, len(files))
+	for round := range files {
+		if versions[round] == nil {
+			rounds = append(rounds, round)
+		}
+	}
+	sort.Ints(rounds)
+
+	collected := 0
+	for _, round := range rounds {
+		if err := ctx.Err(); err != nil {
+			return collected, err
+		}
+		version := &models.ModelVersion{
+			ID:                  uuid.New().String(),
+			OrganizationID:      fl.OrganizationID,
+			FederatedLearningID: fl.ID,
+			Attempt:             fl.Attempt,
+			Round:               round,
+			AggregatorID:        aggregator.ID,
+			Algorithm:           fl.Algorithm,
+			ModelType:           fl.ModelType,
+			TaskBundleID:        fl.TaskBundleID,
+			ParentVersionID:     parentVersionID(existing, fl.Attempt, round),
+			Metrics:             metrics[round],
+			FileName:            files[round],
+			SourcePath:          dir + "/" + files[round],
+			Stage:               models.ModelStageNone,
+		}
+		if err := r.copyCheckpoint(ctx, conn, version); err != nil {
+			return collected, fmt.Errorf("라운드 %d 체크포인트 복사 실패: %v", round, err)
+		}
+		versions[round] = version
+		existing = append(existing, version)
+		collected++
+
+		log.Printf("[%s] 라운드 %d 모델 등록 (%d바이트, %s)", fl.ID, round, version.SizeBytes, version.ContentHash)
+		audit.RecordSystemEvent(audit.SystemEvent{
+			Action:         "model.version.create",
+			ResourceType:   "model-versions",
+			ResourceID:     version.ID,
+			OrganizationID: fl.OrganizationID,
+			Detail: map[string]interface{}{
+				"federated_learning_id": fl.ID,
+				"round":                 round,
+				"content_hash":          version.ContentHash,
+			},
+		})
+	}
+	return collected, nil
+}
+
+// RestoreCheckpoint는 라운드 체크포인트를 모델 저장소에서 집계자의 remotePath로 복사합니다 (warm start용)
+// 레지스트리에 없으면 집계자에서 먼저 수집하고, 여러 재시작 회차에 같은 라운드가 있으면 가장 최근 회차를 사용합니다
+func (r *Registry) RestoreCheckpoint(ctx context.Context, fl *models.FederatedLearning, round int, remotePath string) (*models.ModelVersion, error) {
+	version, err := r.findRoundVersion(fl, round)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		if _, err := r.CollectCheckpoints(ctx, fl); err != nil {
+			log.Printf("[%s] 재시작 전 체크포인트 수집 실패: %v", fl.ID, err)
+		}
+		if version, err = r.findRoundVersion(fl, round); err != nil {
+			return nil, err
+		}
+	}
+	if version == nil {
+		return nil, fmt.Errorf("%w: 라운드 %d", ErrCheckpointNotFound, round)
+	}
+
+	_, conn, err := r.aggregatorConnection(fl)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := r.store.Open(ctx, version.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("모델 저장소 읽기 실패: %w", err)
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err := conn.UploadReader(io.TeeReader(reader, hash), remotePath); err != nil {
+		return nil, fmt.Errorf("체크포인트 업로드 실패: %v", err)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != version.ContentHash {
+		return nil, fmt.Errorf("체크포인트 해시가 일치하지 않습니다 (저장: %s, 복사: %s)", version.ContentHash, sum)
+	}
+	return version, nil
+}
+
+// findRoundVersion은 현재 회차 이하에서 round의 가장 최근 버전을 찾습니다
+func (r *Registry) findRoundVersion(fl *models.FederatedLearning, round int) (*models.ModelVersion, error) {
+	versions, err := r.repo.GetByFederatedLearningID(fl.ID)
+	if err != nil {
+		return nil, fmt.Errorf("모델 버전 조회 실패: %v", err)
+	}
+	var found *models.ModelVersion
+	for _, version := range versions {
+		if version.Round == round && version.Attempt <= fl.Attempt && (found == nil || version.Attempt > found.Attempt) {
+			found = version
+		}
+	}
+	return found, nil
+}
+
+// aggregatorConn은 체크포인트 수집/복원에 사용하는 집계자 연결입니다 (*utils.SSHConnection)
+type aggregatorConn interface {
+	checkpointSource
+	ExecuteCommand(command string) (string, string, error)
+	UploadReader(r io.Reader, remotePath string) (int64, error)
+}
+
+var _ aggregatorConn = (*utils.SSHConnection)(nil)
+
+// aggregatorConnection은 연합학습 작업의 집계자와 공유 SSH 연결을 반환합니다
+func (r *Registry) aggregatorConnection(fl *models.FederatedLearning) (*models.Aggregator, aggregatorConn, error) {
+	if fl.AggregatorID == nil {
+		return nil, nil, fmt.Errorf("집계자가 설정되지 않았습니다")
+	}
+	aggregator, err := r.aggregatorRepo.GetAggregatorByID(*fl.AggregatorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("집계자 조회 실패: %v", err)
+	}
+	if aggregator == nil {
+		return nil, nil, fmt.Errorf("집계자를 찾을 수 없습니다")
+	}
+	if aggregator.PublicIP == "" {
+		return nil, nil, fmt.Errorf("집계자 %s의 Public IP가 설정되지 않았습니다", aggregator.Name)
+	}
+	return aggregator, r.connect(aggregator.ID, aggregator.PublicIP), nil
+}
+
+// checkpointSource는 체크포인트를 내려받을 수 있는 집계자 연결입니다
+type checkpointSource interface {
+	DownloadFile(remotePath string, w io.Writer) (int64, error)
+}
+
+// copyCheckpoint는 체크포인트를 임시 파일로 내려받으며 해시를 계산하고, 저장소에 올린 뒤 버전을 저장합니다
+func (r *Registry) copyCheckpoint(ctx context.Context, source checkpointSource, version *models.ModelVersion) error {
+	tmp, err := os.CreateTemp("", "model_checkpoint_*")
+	if err != nil {
+		return fmt.Errorf("임시 파일 생성 실패: %v", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := source.DownloadFile(version.SourcePath, io.MultiWriter(tmp, hash))
+	if err != nil {
+		return fmt.Errorf("다운로드 실패: %v", err)
+	}
+	version.SizeBytes = size
+	version.ContentHash = hex.EncodeToString(hash.Sum(nil))
+	version.StorageKey = fmt.Sprintf("organizations/%d/federated-learning/%s/round-%03d-%s.pt",
+		version.OrganizationID, version.FederatedLearningID, version.Round, version.ContentHash[:12])
+
+	if err := r.store.Put(ctx, version.StorageKey, tmp, size); err != nil {
+		return fmt.Errorf("모델 저장소 업로드 실패: %v", err)
+	}
+	if err := r.repo.Create(version); err != nil {
+		if delErr := r.store.Delete(ctx, version.StorageKey); delErr != nil {
+			log.Printf("등록 실패한 모델 객체 정리 실패 (%s): %v", version.StorageKey, delErr)
+		}
+		return fmt.Errorf("모델 버전 저장 실패: %v", err)
+	}
+	return nil
+}
+
+// Open은 모델 버전 파일을 저장소에서 읽습니다 (집계자가 없어도 가능)
+func (r *Registry) Open(ctx context.Context, version *models.ModelVersion) (io.ReadCloser, error) {
+	return r.store.Open(ctx, version.StorageKey)
+}
+
+func (r *Registry) jobLock(flID string) *sync.Mutex {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	lock, ok := r.jobLocks[flID]
+	if !ok {
+		lock = &sync.Mutex{}
+		r.jobLocks[flID] = lock
+	}
+	return lock
+}
+
+// parentVersionID는 round보다 앞선 라운드 중 (회차, 라운드)가 가장 큰 버전의 ID를 반환합니다
+// 재시작한 회차의 첫 라운드는 이전 회차의 시작 체크포인트를 부모로 가집니다
+func parentVersionID(versions []*models.ModelVersion, attempt, round int) *string {
+	var parent *models.ModelVersion
+	for _, version := range versions {
+		if version.Attempt > attempt || version.Round >= round {
+			continue
+		}
+		if parent == nil || version.Attempt > parent.Attempt ||
+			(version.Attempt == parent.Attempt && version.Round > parent.Round) {
+			parent = version
+		}
+	}
+	if parent == nil {
+		return nil
+	}
+	id := parent.ID
+	return &id
+}
+
+// checkpointDir는 집계자 작업 디렉토리의 체크포인트 경로입니다 (server_app.py의 ./checkpoints)
+func checkpointDir(flID string) string {
+	return fmt.Sprintf("/home/ubuntu/fl-aggregator-%s/checkpoints", flID)
+}
@@ -0,0 +1,338 @@
+package modelregistry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+)
+
+// fakeAggregator는 체크포인트 디렉토리만 가진 집계자 연결입니다
+type fakeAggregator struct {
+	mu       sync.Mutex
+	files    map[string]string // 원격 경로 -> 내용
+	uploaded map[string]string
+}
+
+func (f *fakeAggregator) ExecuteCommand(command string) (string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fields := strings.Fields(command)
+	if len(fields) < 3 || fields[0] != "ls" {
+		return "", "", fmt.Errorf("unexpected command %q", command)
+	}
+	var names []string
+	for p := range f.files {
+		if path.Dir(p) == fields[2] {
+			names = append(names, path.Base(p))
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, "\n") + "\n", "", nil
+}
+
+func (f *fakeAggregator) DownloadFile(remotePath string, w io.Writer) (int64, error) {
+	f.mu.Lock()
+	content, ok := f.files[remotePath]
+	f.mu.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("no such file: %s", remotePath)
+	}
+	n, err := io.WriteString(w, content)
+	return int64(n), err
+}
+
+func (f *fakeAggregator) UploadReader(r io.Reader, remotePath string) (int64, error) {
+	data, err := io.ReadAll(r)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.uploaded == nil {
+		f.uploaded = make(map[string]string)
+	}
+	f.uploaded[remotePath] = string(data)
+	return int64(len(data)), err
+}
+
+func (f *fakeAggregator) put(flID string, round int, content string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[fmt.Sprintf("%s/round-%03d.pt", checkpointDir(flID), round)] = content
+}
+
+type registryEnv struct {
+	t          *testing.T
+	db         *gorm.DB
+	repo       *repository.ModelVersionRepository
+	store      *FileStore
+	registry   *Registry
+	aggregator *fakeAggregator
+	fl         *models.FederatedLearning
+}
+
+func newRegistryEnv(t *testing.T) *registryEnv {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", regexp.MustCompile(`\W`).ReplaceAllString(t.Name(), "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.User{}, &models.Aggregator{}, &models.TrainingRound{}, &models.Participant{},
+		&models.FederatedLearning{}, &models.ModelVersion{}); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &registryEnv{t: t, db: db, repo: repository.NewModelVersionRepository(db), store: store, aggregator: &fakeAggregator{files: map[string]string{}}}
+	aggregatorRepo := repository.NewAggregatorRepository(db)
+	env.registry = NewRegistry(env.repo, repository.NewFederatedLearningRepository(db), aggregatorRepo, nil, store)
+	env.registry.connect = func(aggregatorID, host string) aggregatorConn {
+		if aggregatorID != *env.fl.AggregatorID || host != "203.0.113.10" {
+			t.Errorf("connect(%s, %s)", aggregatorID, host)
+		}
+		return env.aggregator
+	}
+
+	aggregator := &models.Aggregator{Name: "agg", Status: models.AggregatorStatusRunning, Algorithm: "fedavg", CloudProvider: "gcp",
+		ProjectName: "fl", Region: "asia-northeast3", InstanceType: "e2-medium", PublicIP: "203.0.113.10", UserID: 1, OrganizationID: 3}
+	if err := aggregatorRepo.CreateAggregator(aggregator); err != nil {
+		t.Fatal(err)
+	}
+	aggregatorID := aggregator.ID
+	env.fl = &models.FederatedLearning{ID: "fl-1", UserID: 1, OrganizationID: 3, CloudConnectionID: "conn", AggregatorID: &aggregatorID,
+		Name: "fl", Status: models.FLStatusRunning, Rounds: 5, Algorithm: "fedavg", ModelType: "cnn"}
+	if err := db.Create(env.fl).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.TrainingRound{ID: "r-1", AggregatorID: aggregatorID, Round: 1, ModelMetrics: models.ModelMetric{Accuracy: floatPtr(0.8)}}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func (e *registryEnv) collect(want int) []*models.ModelVersion {
+	e.t.Helper()
+	collected, err := e.registry.CollectCheckpoints(context.Background(), e.fl)
+	if err != nil || collected != want {
+		e.t.Fatalf("collected = %d, %v; want %d", collected, err, want)
+	}
+	versions, err := e.repo.GetByFederatedLearningID(e.fl.ID)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return versions
+}
+
+func (e *registryEnv) stored(version *models.ModelVersion) string {
+	e.t.Helper()
+	reader, err := e.registry.Open(context.Background(), version)
+	if err != nil {
+		e.t.Fatalf("open %s: %v", version.StorageKey, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return string(data)
+}
+
+// findVersion은 (회차, 라운드)의 버전을 찾습니다
+func findVersion(versions []*models.ModelVersion, attempt, round int) *models.ModelVersion {
+	for _, version := range versions {
+		if version.Attempt == attempt && version.Round == round {
+			return version
+		}
+	}
+	return nil
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCollectCheckpoints(t *testing.T) {
+	env := newRegistryEnv(t)
+	env.aggregator.put(env.fl.ID, 1, "weights-1")
+	env.aggregator.put(env.fl.ID, 2, "weights-2")
+	// 체크포인트 이름 형식이 아닌 파일은 무시
+	env.aggregator.files[checkpointDir(env.fl.ID)+"/round-000.pt"] = "invalid"
+	env.aggregator.files[checkpointDir(env.fl.ID)+"/latest.pt"] = "latest"
+
+	versions := env.collect(2)
+	if len(versions) != 2 {
+		t.Fatalf("versions = %d", len(versions))
+	}
+	first, second := versions[0], versions[1]
+	if first.Round != 1 || first.OrganizationID != 3 || first.AggregatorID != *env.fl.AggregatorID || first.FileName != "round-001.pt" ||
+		first.SourcePath != checkpointDir(env.fl.ID)+"/round-001.pt" || first.Stage != models.ModelStageNone {
+		t.Fatalf("first = %+v", first)
+	}
+	if first.Metrics.Accuracy == nil || *first.Metrics.Accuracy != 0.8 || second.Metrics.Accuracy != nil {
+		t.Fatalf("metrics = %+v, %+v", first.Metrics, second.Metrics)
+	}
+	for content, version := range map[string]*models.ModelVersion{"weights-1": first, "weights-2": second} {
+		if version.ContentHash != sha256Hex(content) || version.SizeBytes != int64(len(content)) || env.stored(version) != content {
+			t.Errorf("round %d: hash %s, size %d", version.Round, version.ContentHash, version.SizeBytes)
+		}
+		if !strings.HasPrefix(version.StorageKey, "organizations/3/federated-learning/fl-1/") {
+			t.Errorf("round %d: storage key %s", version.Round, version.StorageKey)
+		}
+	}
+
+	// 이미 등록된 라운드는 다시 복사하지 않음
+	env.aggregator.put(env.fl.ID, 1, "weights-1-overwritten")
+	env.collect(0)
+	if env.stored(first) != "weights-1" {
+		t.Fatal("registered checkpoint was replaced")
+	}
+
+	// 집계자를 삭제하기 전 수집도 같은 경로를 사용
+	env.aggregator.put(env.fl.ID, 3, "weights-3")
+	if err := env.registry.CollectForAggregator(context.Background(), *env.fl.AggregatorID); err != nil {
+		t.Fatal(err)
+	}
+	if versions := env.collect(0); len(versions) != 3 {
+		t.Fatalf("versions after CollectForAggregator = %d", len(versions))
+	}
+}
+
+func TestCollectCheckpointsRecordsLineage(t *testing.T) {
+	env := newRegistryEnv(t)
+	for round := 1; round <= 3; round++ {
+		env.aggregator.put(env.fl.ID, round, fmt.Sprintf("attempt-0-round-%d", round))
+	}
+	versions := env.collect(3)
+	round1, round2, round3 := findVersion(versions, 0, 1), findVersion(versions, 0, 2), findVersion(versions, 0, 3)
+	if round1.ParentVersionID != nil || *round2.ParentVersionID != round1.ID || *round3.ParentVersionID != round2.ID {
+		t.Fatalf("parents = %v, %v, %v", round1.ParentVersionID, round2.ParentVersionID, round3.ParentVersionID)
+	}
+
+	// 라운드 2 체크포인트에서 재시작: 새 회차의 라운드 3은 이전 회차의 라운드 2를 부모로 가짐
+	restore, err := env.registry.RestoreCheckpoint(context.Background(), env.fl, 2, "/home/ubuntu/warm-start.pt")
+	if err != nil || restore.ID != round2.ID || env.aggregator.uploaded["/home/ubuntu/warm-start.pt"] != "attempt-0-round-2" {
+		t.Fatalf("restore = %+v, %v", restore, err)
+	}
+	if err := env.db.Model(env.fl).Update("attempt", 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	env.aggregator.files = map[string]string{}
+	env.aggregator.put(env.fl.ID, 3, "attempt-1-round-3")
+	env.aggregator.put(env.fl.ID, 4, "attempt-1-round-4")
+
+	versions = env.collect(2)
+	restarted3, restarted4 := findVersion(versions, 1, 3), findVersion(versions, 1, 4)
+	if restarted3 == nil || restarted4 == nil || len(versions) != 5 {
+		t.Fatalf("versions = %d", len(versions))
+	}
+	if *restarted3.ParentVersionID != round2.ID || *restarted4.ParentVersionID != restarted3.ID {
+		t.Fatalf("restarted parents = %v, %v", *restarted3.ParentVersionID, *restarted4.ParentVersionID)
+	}
+	if env.stored(round3) != "attempt-0-round-3" || env.stored(restarted3) != "attempt-1-round-3" {
+		t.Fatal("attempts share a storage object")
+	}
+
+	// 같은 라운드가 여러 회차에 있으면 최근 회차를 복원
+	env.fl.Attempt = 1
+	if restore, err := env.registry.RestoreCheckpoint(context.Background(), env.fl, 3, "/home/ubuntu/warm-start.pt"); err != nil || restore.ID != restarted3.ID {
+		t.Fatalf("restore round 3 = %+v, %v", restore, err)
+	}
+	if _, err := env.registry.RestoreCheckpoint(context.Background(), env.fl, 9, "/home/ubuntu/warm-start.pt"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Fatalf("missing round error = %v", err)
+	}
+}
+
+func TestSetStageArchivesPreviousProduction(t *testing.T) {
+	env := newRegistryEnv(t)
+	for round := 1; round <= 3; round++ {
+		env.aggregator.put(env.fl.ID, round, fmt.Sprintf("weights-%d", round))
+	}
+	versions := env.collect(3)
+	// 다른 작업의 production 버전
+	other := &models.ModelVersion{ID: "other", OrganizationID: 3, FederatedLearningID: "fl-2", Round: 1, StorageKey: "k", ContentHash: "h", Stage: models.ModelStageProduction}
+	if err := env.repo.Create(other); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := env.repo.SetStage(versions[0], models.ModelStageProduction, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.repo.SetStage(versions[2], models.ModelStageStaging, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.repo.SetStage(versions[1], models.ModelStageProduction, 8); err != nil {
+		t.Fatal(err)
+	}
+	if versions[1].Stage != models.ModelStageProduction || versions[1].PromotedAt == nil || *versions[1].PromotedByUserID != 8 {
+		t.Fatalf("promoted version = %+v", versions[1])
+	}
+
+	want := map[string]string{
+		versions[0].ID: models.ModelStageArchived,
+		versions[1].ID: models.ModelStageProduction,
+		versions[2].ID: models.ModelStageStaging,
+		other.ID:       models.ModelStageProduction,
+	}
+	for id, stage := range want {
+		version, err := env.repo.GetByID(id)
+		if err != nil || version == nil || version.Stage != stage {
+			t.Errorf("%s: stage = %+v, %v; want %s", id, version, err, stage)
+		}
+	}
+
+	// 같은 버전을 다시 승격해도 자기 자신을 archived로 내리지 않음
+	if err := env.repo.SetStage(versions[1], models.ModelStageProduction, 8); err != nil {
+		t.Fatal(err)
+	}
+	if version, _ := env.repo.GetByID(versions[1].ID); version.Stage != models.ModelStageProduction {
+		t.Fatalf("re-promoted stage = %s", version.Stage)
+	}
+}
+
+func TestCopyCheckpointCleansUpOnRegisterFailure(t *testing.T) {
+	env := newRegistryEnv(t)
+	source := &fakeAggregator{files: map[string]string{"/ckpt/round-001.pt": "weights"}}
+	version := &models.ModelVersion{ID: "v-1", OrganizationID: 3, FederatedLearningID: env.fl.ID, Round: 1, SourcePath: "/ckpt/round-001.pt"}
+	if err := env.registry.copyCheckpoint(context.Background(), source, version); err != nil {
+		t.Fatal(err)
+	}
+
+	// 같은 (작업, 회차, 라운드)는 등록 실패 → 저장소에 올린 객체도 삭제
+	duplicate := &models.ModelVersion{ID: "v-2", OrganizationID: 3, FederatedLearningID: env.fl.ID, Round: 1, SourcePath: "/ckpt/round-001.pt"}
+	source.files["/ckpt/round-001.pt"] = "weights-2"
+	if err := env.registry.copyCheckpoint(context.Background(), source, duplicate); err == nil {
+		t.Fatal("duplicate version registered")
+	}
+	if _, err := env.store.Open(context.Background(), duplicate.StorageKey); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("orphaned object: %v", err)
+	}
+	if data := env.stored(version); data != "weights" {
+		t.Fatalf("original object = %q", data)
+	}
+}
@@ -0,0 +1,105 @@
+package modelregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Store는 S3 또는 S3 호환 저장소(MinIO 등)에 모델 파일을 저장합니다
+type S3Store struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3StoreFromEnv는 환경변수로 새 S3Store 인스턴스를 생성합니다
+//
+//	MODEL_STORE_S3_BUCKET (필수), MODEL_STORE_S3_PREFIX (객체 키 접두사)
+//	MODEL_STORE_S3_ENDPOINT (MinIO 등, 지정 시 path-style 주소 사용), MODEL_STORE_S3_REGION (기본 us-east-1)
+//	MODEL_STORE_S3_ACCESS_KEY / MODEL_STORE_S3_SECRET_KEY (생략 시 AWS 기본 자격 증명 체인)
+func NewS3StoreFromEnv(ctx context.Context) (*S3Store, error) {
+	bucket := os.Getenv("MODEL_STORE_S3_BUCKET")
+	if bucket == "" {
+		return nil, fmt.Errorf("MODEL_STORE=s3에는 MODEL_STORE_S3_BUCKET이 필요합니다")
+	}
+	region := os.Getenv("MODEL_STORE_S3_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if accessKey := os.Getenv("MODEL_STORE_S3_ACCESS_KEY"); accessKey != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(accessKey, os.Getenv("MODEL_STORE_S3_SECRET_KEY"), ""),
+		))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("S3 설정 로드 실패: %v", err)
+	}
+
+	endpoint := os.Getenv("MODEL_STORE_S3_ENDPOINT")
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	prefix := strings.Trim(os.Getenv("MODEL_STORE_S3_PREFIX"), "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3Store{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, file *os.File, size int64) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(s.prefix + key),
+		Body:          file,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String("application/octet-stream"),
+	})
+	return err
+}
+
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	return err
+}
+
+func (s *S3Store) Name() string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, s.prefix)
+}
@@ -0,0 +1,127 @@
+package modelregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrObjectNotFound는 저장소에 객체가 없음을 나타냅니다
+var ErrObjectNotFound = errors.New("model object not found")
+
+// Store는 모델 체크포인트 파일을 보관하는 저장소 인터페이스입니다
+type Store interface {
+	// Put은 key에 파일 내용을 저장합니다 (같은 key가 있으면 덮어씀)
+	Put(ctx context.Context, key string, file *os.File, size int64) error
+	// Open은 key의 객체를 읽습니다 (없으면 ErrObjectNotFound)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete는 key의 객체를 삭제합니다 (없어도 오류 아님)
+	Delete(ctx context.Context, key string) error
+	// Name은 로그에 표시할 저장소 이름입니다
+	Name() string
+}
+
+// NewStoreFromEnv는 MODEL_STORE 환경변수에 따라 모델 저장소를 생성합니다
+//
+//	MODEL_STORE=filesystem (기본, MODEL_STORE_PATH 디렉토리, 기본 ./model-store)
+//	MODEL_STORE=s3 (MODEL_STORE_S3_BUCKET, MODEL_STORE_S3_ENDPOINT로 MinIO 등 S3 호환 저장소 지정)
+func NewStoreFromEnv() (Store, error) {
+	switch strings.ToLower(os.Getenv("MODEL_STORE")) {
+	case "", "filesystem", "file":
+		root := os.Getenv("MODEL_STORE_PATH")
+		if root == "" {
+			root = "./model-store"
+		}
+		return NewFileStore(root)
+	case "s3":
+		return NewS3StoreFromEnv(context.Background())
+	default:
+		return nil, fmt.Errorf("알 수 없는 MODEL_STORE 값입니다: %q (filesystem, s3)", os.Getenv("MODEL_STORE"))
+	}
+}
+
+// FileStore는 로컬 디렉토리에 모델 파일을 저장합니다
+type FileStore struct {
+	root string
+}
+
+// NewFileStore는 root 디렉토리를 만들고 새 FileStore 인스턴스를 생성합니다
+func NewFileStore(root string) (*FileStore, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, 0o700); err != nil {
+		return nil, fmt.Errorf("모델 저장소 디렉토리 생성 실패 (%s): %v", abs, err)
+	}
+	return &FileStore{root: abs}, nil
+}
+
+func (s *FileStore) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, s.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("잘못된 객체 키입니다: %q", key)
+	}
+	return path, nil
+}
+
+// Put은 임시 파일에 쓴 뒤 이름을 바꿔 저장 도중의 파일이 읽히지 않도록 합니다
+func (s *FileStore) Put(ctx context.Context, key string, file *os.File, size int64) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := io.Copy(tmp, file); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *FileStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return f, err
+}
+
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) Name() string {
+	return "filesystem:" + s.root
+}
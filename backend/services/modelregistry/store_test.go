@@ -0,0 +1,94 @@
+package modelregistry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewFileStore(filepath.Join(root, "models"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.CreateTemp(t.TempDir(), "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString("weights"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 파일 위치와 무관하게 처음부터 저장
+	const key = "organizations/1/federated-learning/fl-1/round-001-abc.pt"
+	if err := store.Put(ctx, key, file, 7); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := store.Open(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "weights" {
+		t.Fatalf("stored = %q", data)
+	}
+	entries, _ := os.ReadDir(filepath.Join(root, "models", "organizations", "1", "federated-learning", "fl-1"))
+	if len(entries) != 1 {
+		t.Fatalf("temporary upload files left behind: %v", entries)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Open(ctx, key); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("open deleted = %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("delete missing = %v", err)
+	}
+}
+
+func TestFileStoreRejectsEscapingKeys(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewFileStore(filepath.Join(root, "models"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 저장소 밖 파일 (models-backup은 접두사만 같은 형제 디렉토리)
+	outside := filepath.Join(root, "models-backup", "secret.pt")
+	if err := os.MkdirAll(filepath.Dir(outside), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", ".", "../models-backup/secret.pt", "organizations/../../models-backup/secret.pt", "a/../.."} {
+		if _, err := store.path(key); err == nil {
+			t.Errorf("path(%q) accepted", key)
+		}
+		if _, err := store.Open(ctx, key); err == nil || errors.Is(err, ErrObjectNotFound) {
+			t.Errorf("Open(%q) = %v", key, err)
+		}
+		if err := store.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) accepted", key)
+		}
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Fatalf("file outside the store was removed: %v", err)
+	}
+
+	// 정리된 경로가 저장소 안이면 허용
+	if path, err := store.path("organizations/1/../2/round.pt"); err != nil || path != filepath.Join(root, "models", "organizations", "2", "round.pt") {
+		t.Fatalf("path = %s, %v", path, err)
+	}
+}
@@ -0,0 +1,133 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+var validProviderName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// ProviderConfig는 OIDC 제공자(IdP) 하나의 설정입니다
+type ProviderConfig struct {
+	Name         string   `json:"name"`         // 로그인 경로에 사용하는 식별자 (예: keycloak -> /api/auth/oidc/keycloak/login)
+	DisplayName  string   `json:"display_name"` // 로그인 화면 버튼 이름
+	Issuer       string   `json:"issuer"`       // discovery 문서의 issuer와 정확히 같아야 함
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"` // 비워두면 OIDC_<NAME>_CLIENT_SECRET 환경변수 사용, 둘 다 없으면 공개 클라이언트(PKCE만 사용)
+	RedirectURL  string   `json:"redirect_url"`  // 예: http://localhost:8080/api/auth/oidc/keycloak/callback
+	Scopes       []string `json:"scopes"`        // 기본값: openid email profile
+
+	// ID 토큰/userinfo 클레임을 사용자 정보로 매핑 (점으로 중첩 클레임 지정 가능, 예: realm_access.roles)
+	Claims ClaimMapping `json:"claims"`
+
+	// email_verified 클레임이 없는 IdP(Azure AD 등)에서 이메일을 검증된 것으로 취급할지 여부 (기본 false)
+	// 위험: 같은 이메일의 기존 계정에 로그인되므로, 사용자가 이메일을 직접 바꿀 수 없고 IdP 관리자만
+	// 지정하는 디렉터리에서만 켜야 합니다. 클레임이 있으면 이 값과 관계없이 클레임 값을 따릅니다
+	TrustEmail bool `json:"trust_email"`
+
+	// IdP 그룹을 조직 역할로 매핑 (선택)
+	GroupRoles *GroupRoleMapping `json:"group_roles,omitempty"`
+}
+
+// ClaimMapping은 사용자 정보로 사용할 클레임 이름입니다
+type ClaimMapping struct {
+	Email         string `json:"email"`
+	EmailVerified string `json:"email_verified"`
+	Name          string `json:"name"`
+	Groups        string `json:"groups"`
+}
+
+// GroupRoleMapping은 IdP 그룹을 한 조직의 멤버 역할로 매핑합니다
+// 여러 그룹이 매핑되면 가장 높은 역할을 부여하며, owner는 부여할 수 없습니다
+type GroupRoleMapping struct {
+	OrganizationID int64             `json:"organization_id"`
+	Roles          map[string]string `json:"roles"` // 그룹 이름 -> admin|operator|viewer
+}
+
+// LoadConfigsFromEnv는 OIDC_PROVIDERS_CONFIG 환경변수가 가리키는 JSON 파일에서 제공자 목록을 읽습니다
+// 파일 형식: {"providers":[{"name":"keycloak","issuer":"...","client_id":"...",...}]}
+// 환경변수가 비어 있으면 OIDC 로그인을 사용하지 않습니다
+func LoadConfigsFromEnv() ([]ProviderConfig, error) {
+	path := os.Getenv("OIDC_PROVIDERS_CONFIG")
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC 제공자 설정 파일 읽기 실패 (%s): %v", path, err)
+	}
+	var file struct {
+		Providers []ProviderConfig `json:"providers"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("OIDC 제공자 설정 파일 파싱 실패 (%s): %v", path, err)
+	}
+
+	for i := range file.Providers {
+		cfg := &file.Providers[i]
+		if cfg.ClientSecret == "" {
+			cfg.ClientSecret = os.Getenv(clientSecretEnv(cfg.Name))
+		}
+	}
+	return file.Providers, nil
+}
+
+func clientSecretEnv(name string) string {
+	return "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_CLIENT_SECRET"
+}
+
+// normalize는 기본값을 채우고 설정을 검증합니다
+func (c *ProviderConfig) normalize() error {
+	if !validProviderName.MatchString(c.Name) {
+		return fmt.Errorf("OIDC 제공자 이름이 올바르지 않습니다: %q (소문자, 숫자, - 만 사용)", c.Name)
+	}
+	if c.Issuer == "" || c.ClientID == "" || c.RedirectURL == "" {
+		return fmt.Errorf("OIDC 제공자 %s: issuer, client_id, redirect_url은 필수입니다", c.Name)
+	}
+	if c.DisplayName == "" {
+		c.DisplayName = c.Name
+	}
+	if len(c.Scopes) == 0 {
+		c.Scopes = []string{"openid", "email", "profile"}
+	}
+	hasOpenID := false
+	for _, scope := range c.Scopes {
+		if scope == "openid" {
+			hasOpenID = true
+		}
+	}
+	if !hasOpenID {
+		c.Scopes = append([]string{"openid"}, c.Scopes...)
+	}
+
+	if c.Claims.Email == "" {
+		c.Claims.Email = "email"
+	}
+	if c.Claims.EmailVerified == "" {
+		c.Claims.EmailVerified = "email_verified"
+	}
+	if c.Claims.Name == "" {
+		c.Claims.Name = "name"
+	}
+	if c.Claims.Groups == "" {
+		c.Claims.Groups = "groups"
+	}
+
+	if c.GroupRoles != nil {
+		if c.GroupRoles.OrganizationID <= 0 {
+			return fmt.Errorf("OIDC 제공자 %s: group_roles.organization_id가 필요합니다", c.Name)
+		}
+		for group, role := range c.GroupRoles.Roles {
+			if !models.IsValidOrganizationRole(role) || role == models.OrganizationRoleOwner {
+				return fmt.Errorf("OIDC 제공자 %s: 그룹 %q에 부여할 수 없는 역할입니다: %q", c.Name, group, role)
+			}
+		}
+	}
+	return nil
+}
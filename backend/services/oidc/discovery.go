@@ -0,0 +1,82 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxResponseBytes는 IdP 응답 본문 최대 크기입니다
+const maxResponseBytes = 1 << 20
+
+// Discovery는 OpenID Provider 메타데이터(/.well-known/openid-configuration) 중 사용하는 항목입니다
+type Discovery struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	UserinfoEndpoint              string   `json:"userinfo_endpoint"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	IDTokenSigningAlgs            []string `json:"id_token_signing_alg_values_supported"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+}
+
+// FetchDiscovery는 issuer의 discovery 문서를 조회하고 issuer 일치 여부를 검증합니다
+func FetchDiscovery(ctx context.Context, client *http.Client, issuer string) (*Discovery, error) {
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+
+	var doc Discovery
+	if err := getJSON(ctx, client, wellKnown, "", &doc); err != nil {
+		return nil, fmt.Errorf("discovery 문서 조회 실패: %w", err)
+	}
+
+	// OpenID Connect Discovery 4.3: 응답의 issuer는 요청한 issuer와 정확히 같아야 함
+	if doc.Issuer != issuer {
+		return nil, fmt.Errorf("discovery issuer 불일치: 설정=%s, 응답=%s", issuer, doc.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery 문서에 필수 엔드포인트가 없습니다")
+	}
+	if len(doc.CodeChallengeMethodsSupported) > 0 && !contains(doc.CodeChallengeMethodsSupported, "S256") {
+		return nil, fmt.Errorf("IdP가 PKCE S256을 지원하지 않습니다")
+	}
+	return &doc, nil
+}
+
+// getJSON은 GET 요청 결과를 JSON으로 디코딩합니다 (bearer가 있으면 Authorization 헤더 포함)
+func getJSON(ctx context.Context, client *http.Client, url, bearer string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: HTTP %d", url, resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
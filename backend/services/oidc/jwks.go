@@ -0,0 +1,154 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minJWKSRefreshInterval은 모르는 kid로 인한 JWKS 재조회 최소 간격입니다 (키 교체 대응, 과도한 조회 방지)
+const minJWKSRefreshInterval = time.Minute
+
+// jsonWebKey는 JWKS 항목 중 서명 검증에 필요한 필드입니다
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// remoteKeySet은 IdP의 jwks_uri에서 가져온 공개 키를 kid 기준으로 캐시합니다
+type remoteKeySet struct {
+	uri    string
+	client *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	lastFetched time.Time
+}
+
+func newRemoteKeySet(uri string, client *http.Client) *remoteKeySet {
+	return &remoteKeySet{uri: uri, client: client, keys: map[string]crypto.PublicKey{}}
+}
+
+// key는 kid에 해당하는 공개 키를 반환합니다
+// 캐시에 없으면 최소 간격이 지난 경우에만 JWKS를 다시 조회합니다
+func (s *remoteKeySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if !s.lastFetched.IsZero() && time.Since(s.lastFetched) < minJWKSRefreshInterval {
+		return nil, fmt.Errorf("알 수 없는 ID 토큰 서명 키입니다: %s", kid)
+	}
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("알 수 없는 ID 토큰 서명 키입니다: %s", kid)
+}
+
+func (s *remoteKeySet) refresh(ctx context.Context) error {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	s.lastFetched = time.Now()
+	if err := getJSON(ctx, s.client, s.uri, "", &doc); err != nil {
+		return fmt.Errorf("JWKS 조회 실패: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// 지원하지 않는 키 형식은 건너뜀 (다른 키로 서명된 토큰은 계속 검증 가능)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	s.keys = keys
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("RSA 공개 지수가 올바르지 않습니다")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("지원하지 않는 EC 곡선입니다: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("EC 공개 키가 곡선 위에 있지 않습니다")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("지원하지 않는 OKP 곡선입니다: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("Ed25519 공개 키가 올바르지 않습니다")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("지원하지 않는 키 형식입니다: %s", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("JWK 값 디코딩 실패")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,318 @@
+// Package oidctest는 OIDC 로그인 흐름을 로컬에서 확인하기 위한 모의 IdP를 제공합니다
+// 인가 요청을 받으면 로그인 화면 없이 SetUser로 지정한 사용자로 바로 코드를 발급합니다
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Mungge/Fleecy-Cloud/services/oidc"
+)
+
+const mockKeyID = "mock-idp-key"
+
+// authRequest는 발급한 인가 코드에 묶인 요청 정보입니다
+type authRequest struct {
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	claims        map[string]interface{}
+	expiresAt     time.Time
+}
+
+// MockIdP는 discovery, authorize, token, userinfo, jwks 엔드포인트를 제공하는 모의 OIDC 제공자입니다
+type MockIdP struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string // 비어 있으면 공개 클라이언트로 동작 (PKCE만 검증)
+	TokenTTL     time.Duration
+
+	key    *rsa.PrivateKey
+	server *httptest.Server
+
+	mu           sync.Mutex
+	user         map[string]interface{}
+	overrides    map[string]interface{}
+	codes        map[string]*authRequest
+	accessTokens map[string]map[string]interface{}
+}
+
+// NewMockIdP는 지정한 issuer 주소로 동작하는 모의 IdP를 생성합니다 (직접 http.Server에 연결할 때 사용)
+func NewMockIdP(issuer, clientID, clientSecret string) (*MockIdP, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return &MockIdP{
+		Issuer:       strings.TrimSuffix(issuer, "/"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenTTL:     5 * time.Minute,
+		key:          key,
+		user: map[string]interface{}{
+			"sub":            "mock-user",
+			"email":          "mock.user@example.com",
+			"email_verified": true,
+			"name":           "Mock User",
+			"groups":         []string{},
+		},
+		codes:        map[string]*authRequest{},
+		accessTokens: map[string]map[string]interface{}{},
+	}, nil
+}
+
+// StartMockIdP는 임의 포트의 로컬 서버로 모의 IdP를 시작합니다 (사용 후 Close 호출)
+func StartMockIdP(clientID, clientSecret string) (*MockIdP, error) {
+	idp, err := NewMockIdP("", clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	idp.server = httptest.NewUnstartedServer(idp)
+	idp.server.Start()
+	idp.Issuer = idp.server.URL
+	return idp, nil
+}
+
+// Close는 StartMockIdP로 시작한 서버를 종료합니다
+func (m *MockIdP) Close() {
+	if m.server != nil {
+		m.server.Close()
+	}
+}
+
+// SetUser는 다음 인가 요청부터 로그인한 것으로 간주할 사용자 클레임을 지정합니다 (sub, email, name, groups 등)
+func (m *MockIdP) SetUser(claims map[string]interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.user = claims
+}
+
+// SetIDTokenOverrides는 발급하는 ID 토큰의 클레임(iss, aud, azp 등)을 덮어씁니다 (잘못된 토큰 검증 테스트용, nil이면 해제)
+func (m *MockIdP) SetIDTokenOverrides(claims map[string]interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.overrides = claims
+}
+
+// ProviderConfig는 이 모의 IdP를 가리키는 제공자 설정을 반환합니다
+func (m *MockIdP) ProviderConfig(name, redirectURL string) oidc.ProviderConfig {
+	return oidc.ProviderConfig{
+		Name:         name,
+		DisplayName:  "Mock IdP",
+		Issuer:       m.Issuer,
+		ClientID:     m.ClientID,
+		ClientSecret: m.ClientSecret,
+		RedirectURL:  redirectURL,
+	}
+}
+
+// ServeHTTP는 모의 IdP 엔드포인트를 처리합니다
+func (m *MockIdP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		m.handleDiscovery(w)
+	case "/authorize":
+		m.handleAuthorize(w, r)
+	case "/token":
+		m.handleToken(w, r)
+	case "/userinfo":
+		m.handleUserinfo(w, r)
+	case "/jwks":
+		m.handleJWKS(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (m *MockIdP) handleDiscovery(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                m.Issuer,
+		"authorization_endpoint":                m.Issuer + "/authorize",
+		"token_endpoint":                        m.Issuer + "/token",
+		"userinfo_endpoint":                     m.Issuer + "/userinfo",
+		"jwks_uri":                              m.Issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+func (m *MockIdP) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != m.ClientID {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "code flow with PKCE S256 is required", http.StatusBadRequest)
+		return
+	}
+	if !strings.Contains(" "+q.Get("scope")+" ", " openid ") {
+		http.Error(w, "openid scope is required", http.StatusBadRequest)
+		return
+	}
+
+	code, err := oidc.RandomToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	m.mu.Lock()
+	m.codes[code] = &authRequest{
+		redirectURI:   q.Get("redirect_uri"),
+		codeChallenge: q.Get("code_challenge"),
+		nonce:         q.Get("nonce"),
+		claims:        m.user,
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	m.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (m *MockIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeOAuthError(w, "unsupported_grant_type")
+		return
+	}
+	if !m.authenticateClient(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// 인가 코드는 한 번만 사용 가능
+	code := r.PostForm.Get("code")
+	m.mu.Lock()
+	req, ok := m.codes[code]
+	delete(m.codes, code)
+	m.mu.Unlock()
+	if !ok || time.Now().After(req.expiresAt) || req.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeOAuthError(w, "invalid_grant")
+		return
+	}
+	if oidc.CodeChallengeS256(r.PostForm.Get("code_verifier")) != req.codeChallenge {
+		writeOAuthError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{}
+	for k, v := range req.claims {
+		claims[k] = v
+	}
+	claims["iss"] = m.Issuer
+	claims["aud"] = m.ClientID
+	claims["azp"] = m.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(m.TokenTTL).Unix()
+	if req.nonce != "" {
+		claims["nonce"] = req.nonce
+	}
+	m.mu.Lock()
+	for k, v := range m.overrides {
+		claims[k] = v
+	}
+	m.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = mockKeyID
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	accessToken, err := oidc.RandomToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	m.mu.Lock()
+	m.accessTokens[accessToken] = req.claims
+	m.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int64(m.TokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+// authenticateClient는 client_secret_basic 또는 client_secret_post 인증을 확인합니다
+func (m *MockIdP) authenticateClient(r *http.Request) bool {
+	clientID, secret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+		secret = r.PostForm.Get("client_secret")
+	}
+	if clientID != m.ClientID {
+		return false
+	}
+	return m.ClientSecret == "" || secret == m.ClientSecret
+}
+
+func (m *MockIdP) handleUserinfo(w http.ResponseWriter, r *http.Request) {
+	accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	m.mu.Lock()
+	claims, ok := m.accessTokens[accessToken]
+	m.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, http.StatusOK, claims)
+}
+
+func (m *MockIdP) handleJWKS(w http.ResponseWriter) {
+	pub := m.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": mockKeyID,
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func writeOAuthError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
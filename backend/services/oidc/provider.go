@@ -0,0 +1,402 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+// idTokenLeeway는 IdP와 서버 간 시계 오차 허용 범위입니다
+const idTokenLeeway = time.Minute
+
+// supportedIDTokenAlgs는 ID 토큰 서명으로 허용하는 비대칭 알고리즘입니다 (none/HS* 제외)
+var supportedIDTokenAlgs = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// organizationRoleRank는 그룹 매핑 시 더 높은 역할을 고르기 위한 순위입니다
+var organizationRoleRank = map[string]int{
+	models.OrganizationRoleViewer:   1,
+	models.OrganizationRoleOperator: 2,
+	models.OrganizationRoleAdmin:    3,
+}
+
+// TokenResponse는 토큰 엔드포인트 응답입니다
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Identity는 ID 토큰(필요 시 userinfo)에서 매핑한 사용자 정보입니다
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+// Provider는 설정된 OIDC 제공자 하나입니다
+// discovery 문서와 JWKS는 처음 사용할 때 가져오므로 IdP가 잠시 내려가 있어도 서버는 시작됩니다
+type Provider struct {
+	config ProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *Discovery
+	keys      *remoteKeySet
+}
+
+// NewProvider는 설정을 검증하고 새 Provider 인스턴스를 생성합니다
+func NewProvider(config ProviderConfig, client *http.Client) (*Provider, error) {
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if config.TrustEmail {
+		log.Printf("경고: OIDC 제공자 %s는 trust_email이 켜져 있어 email_verified 클레임이 없는 이메일도 검증된 것으로 취급합니다", config.Name)
+	}
+	return &Provider{config: config, client: client}, nil
+}
+
+// Name은 제공자 식별자를 반환합니다
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// DisplayName은 로그인 화면에 표시할 이름을 반환합니다
+func (p *Provider) DisplayName() string {
+	return p.config.DisplayName
+}
+
+// Discovery는 캐시된 discovery 문서를 반환하고 없으면 조회합니다
+func (p *Provider) Discovery(ctx context.Context) (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	doc, err := FetchDiscovery(ctx, p.client, p.config.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	p.discovery = doc
+	p.keys = newRemoteKeySet(doc.JWKSURI, p.client)
+	return doc, nil
+}
+
+// AuthCodeURL은 PKCE(S256)와 nonce를 포함한 인가 요청 URL을 생성합니다
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	doc, err := p.Discovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("authorization_endpoint가 올바르지 않습니다: %v", err)
+	}
+	q := authURL.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.config.ClientID)
+	q.Set("redirect_uri", p.config.RedirectURL)
+	q.Set("scope", strings.Join(p.config.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", CodeChallengeS256(codeVerifier))
+	q.Set("code_challenge_method", "S256")
+	authURL.RawQuery = q.Encode()
+	return authURL.String(), nil
+}
+
+// Exchange는 인가 코드를 PKCE code_verifier와 함께 토큰으로 교환합니다
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*TokenResponse, error) {
+	doc, err := p.Discovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		// client_secret_basic (RFC 6749 2.3.1: 값은 form-urlencoded 후 사용)
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("토큰 요청 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("토큰 응답 읽기 실패: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		json.Unmarshal(body, &oauthErr)
+		return nil, fmt.Errorf("토큰 요청 실패: HTTP %d %s %s", resp.StatusCode, oauthErr.Error, oauthErr.Description)
+	}
+
+	var token TokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("토큰 응답 파싱 실패: %v", err)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("토큰 응답에 id_token이 없습니다")
+	}
+	return &token, nil
+}
+
+// VerifyIDToken은 ID 토큰의 서명, iss, aud, azp, exp, iat, nonce를 검증하고 클레임을 반환합니다
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (jwt.MapClaims, error) {
+	doc, err := p.Discovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.keys.key(ctx, kid)
+		},
+		jwt.WithValidMethods(p.idTokenAlgs(doc)),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ID 토큰 검증 실패: %w", err)
+	}
+
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return nil, fmt.Errorf("ID 토큰 nonce가 일치하지 않습니다")
+	}
+	// OIDC Core 3.1.3.7: azp가 있으면 client_id와 같아야 함
+	if azp, ok := claims["azp"].(string); ok && azp != "" && azp != p.config.ClientID {
+		return nil, fmt.Errorf("ID 토큰 azp가 client_id와 다릅니다")
+	}
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, fmt.Errorf("ID 토큰에 sub 클레임이 없습니다")
+	}
+	return claims, nil
+}
+
+// idTokenAlgs는 IdP가 광고한 알고리즘 중 허용 목록에 있는 것만 반환합니다 (광고가 없으면 RS256)
+func (p *Provider) idTokenAlgs(doc *Discovery) []string {
+	var algs []string
+	for _, alg := range doc.IDTokenSigningAlgs {
+		if contains(supportedIDTokenAlgs, alg) {
+			algs = append(algs, alg)
+		}
+	}
+	if len(algs) == 0 {
+		algs = []string{"RS256"}
+	}
+	return algs
+}
+
+// Identity는 검증된 ID 토큰 클레임을 설정의 클레임 매핑에 따라 사용자 정보로 변환합니다
+// ID 토큰에 이메일이 없으면 userinfo 엔드포인트에서 보충합니다
+func (p *Provider) Identity(ctx context.Context, claims jwt.MapClaims, accessToken string) (*Identity, error) {
+	merged := map[string]interface{}(claims)
+
+	if lookupString(merged, p.config.Claims.Email) == "" && accessToken != "" {
+		doc, err := p.Discovery(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if doc.UserinfoEndpoint != "" {
+			var userinfo map[string]interface{}
+			if err := getJSON(ctx, p.client, doc.UserinfoEndpoint, accessToken, &userinfo); err != nil {
+				return nil, fmt.Errorf("userinfo 조회 실패: %w", err)
+			}
+			// OIDC Core 5.3.2: userinfo의 sub는 ID 토큰의 sub와 같아야 함
+			if userinfo["sub"] != claims["sub"] {
+				return nil, fmt.Errorf("userinfo sub가 ID 토큰과 다릅니다")
+			}
+			merged = make(map[string]interface{}, len(claims)+len(userinfo))
+			for k, v := range userinfo {
+				merged[k] = v
+			}
+			for k, v := range claims {
+				merged[k] = v
+			}
+		}
+	}
+
+	identity := &Identity{
+		Subject: lookupString(merged, "sub"),
+		Email:   strings.ToLower(strings.TrimSpace(lookupString(merged, p.config.Claims.Email))),
+		Name:    lookupString(merged, p.config.Claims.Name),
+		Groups:  lookupStrings(merged, p.config.Claims.Groups),
+	}
+	// trust_email은 IdP가 email_verified 클레임을 아예 보내지 않을 때만 적용 (명시적인 false는 그대로 거부)
+	if _, ok := lookup(merged, p.config.Claims.EmailVerified); ok {
+		identity.EmailVerified = lookupBool(merged, p.config.Claims.EmailVerified)
+	} else {
+		identity.EmailVerified = p.config.TrustEmail
+	}
+	if identity.Name == "" {
+		identity.Name = lookupString(merged, "preferred_username")
+	}
+	return identity, nil
+}
+
+// RoleForGroups는 사용자의 그룹에 매핑된 조직과 가장 높은 역할을 반환합니다
+func (p *Provider) RoleForGroups(groups []string) (int64, string, bool) {
+	mapping := p.config.GroupRoles
+	if mapping == nil {
+		return 0, "", false
+	}
+
+	best := ""
+	for _, group := range groups {
+		role, ok := mapping.Roles[group]
+		if ok && organizationRoleRank[role] > organizationRoleRank[best] {
+			best = role
+		}
+	}
+	if best == "" {
+		return 0, "", false
+	}
+	return mapping.OrganizationID, best, true
+}
+
+// lookup은 점으로 구분한 경로로 중첩 클레임 값을 찾습니다 (예: realm_access.roles)
+func lookup(claims map[string]interface{}, path string) (interface{}, bool) {
+	if v, ok := claims[path]; ok {
+		return v, true
+	}
+	var current interface{} = claims
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func lookupString(claims map[string]interface{}, path string) string {
+	v, _ := lookup(claims, path)
+	s, _ := v.(string)
+	return s
+}
+
+func lookupBool(claims map[string]interface{}, path string) bool {
+	v, _ := lookup(claims, path)
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		// 일부 IdP는 문자열 "true"로 보냄
+		return b == "true"
+	}
+	return false
+}
+
+func lookupStrings(claims map[string]interface{}, path string) []string {
+	v, _ := lookup(claims, path)
+	switch list := v.(type) {
+	case []interface{}:
+		result := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok && s != "" {
+				result = append(result, s)
+			}
+		}
+		return result
+	case string:
+		return strings.Fields(strings.ReplaceAll(list, ",", " "))
+	}
+	return nil
+}
+
+// Registry는 설정된 OIDC 제공자 목록입니다
+type Registry struct {
+	providers map[string]*Provider
+}
+
+// NewRegistry는 제공자 설정 목록으로 Registry를 생성합니다
+func NewRegistry(configs []ProviderConfig, client *http.Client) (*Registry, error) {
+	registry := &Registry{providers: make(map[string]*Provider, len(configs))}
+	for _, config := range configs {
+		provider, err := NewProvider(config, client)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := registry.providers[provider.Name()]; exists {
+			return nil, fmt.Errorf("OIDC 제공자 이름이 중복되었습니다: %s", provider.Name())
+		}
+		registry.providers[provider.Name()] = provider
+	}
+	return registry, nil
+}
+
+// NewRegistryFromEnv는 OIDC_PROVIDERS_CONFIG 설정으로 Registry를 생성합니다
+func NewRegistryFromEnv() (*Registry, error) {
+	configs, err := LoadConfigsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewRegistry(configs, nil)
+}
+
+// Get은 이름으로 제공자를 찾습니다
+func (r *Registry) Get(name string) (*Provider, bool) {
+	if r == nil {
+		return nil, false
+	}
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// List는 이름 순으로 정렬한 제공자 목록을 반환합니다
+func (r *Registry) List() []*Provider {
+	if r == nil {
+		return nil
+	}
+	providers := make([]*Provider, 0, len(r.providers))
+	for _, provider := range r.providers {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name() < providers[j].Name() })
+	return providers
+}
@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
 )
 
 // 최적화 요청 구조체
@@ -20,11 +22,30 @@ type OptimizationRequest struct {
 		Participants  []Participant `json:"participants"`
 		ModelFileName *string       `json:"modelFileName,omitempty"`
 	} `json:"federatedLearning"`
-	AggregatorConfig struct {
-		MaxBudget  int `json:"maxBudget"`
-		MaxLatency int `json:"maxLatency"`
-		WeightBalance *int `json:"weightBalance,omitempty"`
-	} `json:"aggregatorConfig"`
+	AggregatorConfig AggregatorConfig `json:"aggregatorConfig"`
+
+	// 가격 스냅샷 버전 고정 (비어있으면 현재 유효한 가격표 사용, 과거 배치 결정 재현용)
+	PriceSnapshotVersion *int `json:"priceSnapshotVersion,omitempty"`
+}
+
+// 집계자 배치 제약사항
+type AggregatorConfig struct {
+	MaxBudget     int  `json:"maxBudget"`
+	MaxLatency    int  `json:"maxLatency"`
+	WeightBalance *int `json:"weightBalance,omitempty"`
+
+	// what-if 제약사항 (Go 최적화 백엔드에서 지원)
+	MinMemoryRequirement int      `json:"minMemoryRequirement,omitempty"` // GB 단위 (기본 4GB)
+	MinVCPU              int      `json:"minVcpu,omitempty"`
+	RequireGPU           bool     `json:"requireGpu,omitempty"`
+	AllowedProviders     []string `json:"allowedProviders,omitempty"` // 비어있으면 모든 제공자 허용
+	ExcludedRegions      []string `json:"excludedRegions,omitempty"`
+	PayloadSizeMB        float64  `json:"payloadSizeMB,omitempty"` // 라운드당 모델 전송 크기 (라운드 전송 시간 추정용)
+}
+
+// HasExtendedConstraints는 Python 최적화 스크립트가 지원하지 않는 제약사항이 설정되었는지 확인합니다
+func (c AggregatorConfig) HasExtendedConstraints() bool {
+	return c.MinVCPU > 0 || c.RequireGPU || len(c.AllowedProviders) > 0 || len(c.ExcludedRegions) > 0 || c.PayloadSizeMB > 0
 }
 
 // 참여자 구조체
@@ -41,8 +62,12 @@ type OptimizationResponse struct {
 	Status           string              `json:"status"`
 	Summary          OptimizationSummary `json:"summary"`
 	OptimizedOptions []AggregatorOption  `json:"optimizedOptions"`
+	ParetoFront      []AggregatorOption  `json:"paretoFront,omitempty"`      // 비용/평균 지연시간/최대 지연시간 기준 비지배 옵션 전체
+	ParetoObjectives []string            `json:"paretoObjectives,omitempty"` // Pareto front 계산에 사용한 목적 함수 (모두 최소화)
 	Message          string              `json:"message"`
 	ExecutionTime    float64             `json:"executionTime,omitempty"` // Go에서 추가
+
+	PriceSnapshotVersion int `json:"priceSnapshotVersion,omitempty"` // 계산에 사용한 가격 스냅샷 버전 (0이면 스냅샷 이전 가격표)
 }
 
 // 최적화 요약 정보
@@ -68,6 +93,17 @@ type AggregatorOption struct {
 	VCPU                 int     `json:"vcpu"`
 	Memory               int     `json:"memory"`
 	RecommendationScore  float64 `json:"recommendationScore"`
+
+	GPU                      int              `json:"gpu,omitempty"`
+	EstimatedRoundTransferMs float64          `json:"estimatedRoundTransferMs,omitempty"` // payloadSizeMB 지정 시 라운드당 예상 전송 시간
+	Dominance                *ParetoDominance `json:"dominance,omitempty"`
+}
+
+// Pareto 지배 관계 메타데이터
+type ParetoDominance struct {
+	ParetoRank  int `json:"paretoRank"`  // 비지배 정렬 순위 (0이면 Pareto front)
+	DominatedBy int `json:"dominatedBy"` // 이 옵션을 지배하는 후보 수
+	Dominates   int `json:"dominates"`   // 이 옵션이 지배하는 후보 수
 }
 
 // 최적화 결과 구조체
@@ -100,6 +136,20 @@ func NewOptimizationService() *OptimizationService {
 func (s *OptimizationService) RunOptimization(request OptimizationRequest) (*OptimizationResponse, error) {
 	startTime := time.Now()
 
+	// 서버 템플릿이 지원하지 않는 알고리즘은 배치 최적화 전에 거부
+	algorithm, err := models.NormalizeAlgorithm(request.FederatedLearning.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	request.FederatedLearning.Algorithm = algorithm
+
+	if request.AggregatorConfig.HasExtendedConstraints() {
+		return nil, fmt.Errorf("Python 최적화 백엔드는 제공자/리전/vCPU/GPU/페이로드 제약을 지원하지 않습니다")
+	}
+	if request.PriceSnapshotVersion != nil {
+		return nil, fmt.Errorf("Python 최적화 백엔드는 가격 스냅샷 버전 고정을 지원하지 않습니다")
+	}
+
 	if err := os.MkdirAll(s.tempBaseDir, 0o755); err != nil { // ✅ 베이스 temp 보장
 		return nil, fmt.Errorf("temp 베이스 디렉토리 생성 실패: %w", err)
 	}
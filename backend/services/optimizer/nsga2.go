@@ -0,0 +1,254 @@
+package optimizer
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// individual은 NSGA-II 개체입니다 (유전자: 후보 옵션 인덱스)
+type individual struct {
+	genome     []int
+	objectives []float64
+	evaluated  bool
+	rank       int     // 비지배 front 순위 (0이 최상위)
+	crowding   float64 // 같은 front 내 밀집 거리
+}
+
+func (ind *individual) clone() *individual {
+	genome := make([]int, len(ind.genome))
+	copy(genome, ind.genome)
+	objectives := make([]float64, len(ind.objectives))
+	copy(objectives, ind.objectives)
+	return &individual{
+		genome:     genome,
+		objectives: objectives,
+		evaluated:  ind.evaluated,
+		rank:       ind.rank,
+		crowding:   ind.crowding,
+	}
+}
+
+// nsga2Params는 NSGA-II 진화 파라미터입니다
+type nsga2Params struct {
+	populationSize   int
+	generations      int
+	genomeLength     int
+	numChoices       int     // 유전자 값 범위 [0, numChoices)
+	crossoverProb    float64 // 개체 쌍 교차 확률
+	mutationProb     float64 // 개체 변이 확률
+	geneMutationProb float64 // 변이 시 유전자별 변이 확률
+}
+
+// nsga2는 정수 유전자를 갖는 다목적 최소화 문제를 NSGA-II로 탐색합니다
+type nsga2 struct {
+	params   nsga2Params
+	rng      *rand.Rand
+	evaluate func(genome []int) []float64
+}
+
+// run은 진화를 수행하고 최종 모집단을 반환합니다
+func (n *nsga2) run() []*individual {
+	population := make([]*individual, n.params.populationSize)
+	for i := range population {
+		genome := make([]int, n.params.genomeLength)
+		for g := range genome {
+			genome[g] = n.rng.Intn(n.params.numChoices)
+		}
+		population[i] = &individual{genome: genome}
+	}
+	n.evaluateAll(population)
+
+	// 초기 순위/밀집 거리 할당 (토너먼트 선택에 필요)
+	population = selectNSGA2(population, len(population))
+
+	for gen := 0; gen < n.params.generations; gen++ {
+		offspring := make([]*individual, len(population))
+		for i := range offspring {
+			offspring[i] = n.tournament(population).clone()
+		}
+
+		// 교차와 변이
+		for i := 0; i+1 < len(offspring); i += 2 {
+			if n.rng.Float64() < n.params.crossoverProb {
+				n.crossover(offspring[i], offspring[i+1])
+			}
+		}
+		for _, child := range offspring {
+			if n.rng.Float64() < n.params.mutationProb {
+				n.mutate(child)
+			}
+		}
+		n.evaluateAll(offspring)
+
+		// 부모와 자식을 합친 뒤 엘리트 선택 (mu + lambda)
+		population = selectNSGA2(append(population, offspring...), n.params.populationSize)
+	}
+
+	return population
+}
+
+func (n *nsga2) evaluateAll(population []*individual) {
+	for _, ind := range population {
+		if !ind.evaluated {
+			ind.objectives = n.evaluate(ind.genome)
+			ind.evaluated = true
+		}
+	}
+}
+
+// tournament는 순위와 밀집 거리 기준 이진 토너먼트 선택입니다
+func (n *nsga2) tournament(population []*individual) *individual {
+	a := population[n.rng.Intn(len(population))]
+	b := population[n.rng.Intn(len(population))]
+	if a.rank != b.rank {
+		if a.rank < b.rank {
+			return a
+		}
+		return b
+	}
+	if a.crowding != b.crowding {
+		if a.crowding > b.crowding {
+			return a
+		}
+		return b
+	}
+	if n.rng.Float64() < 0.5 {
+		return a
+	}
+	return b
+}
+
+// crossover는 유전자별 50% 확률로 교환하는 균등 교차입니다
+func (n *nsga2) crossover(a, b *individual) {
+	for i := range a.genome {
+		if n.rng.Float64() < 0.5 {
+			a.genome[i], b.genome[i] = b.genome[i], a.genome[i]
+		}
+	}
+	a.evaluated = false
+	b.evaluated = false
+}
+
+// mutate는 유전자별 확률로 범위 내 임의 값으로 바꾸는 균등 정수 변이입니다
+func (n *nsga2) mutate(ind *individual) {
+	for i := range ind.genome {
+		if n.rng.Float64() < n.params.geneMutationProb {
+			ind.genome[i] = n.rng.Intn(n.params.numChoices)
+		}
+	}
+	ind.evaluated = false
+}
+
+// dominates는 a가 b를 지배하는지 확인합니다 (모든 목적 이하, 하나 이상 미만)
+func dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+		if a[i] < b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// sortNondominated는 모집단을 비지배 front로 나누고 각 개체의 순위를 기록합니다
+func sortNondominated(population []*individual) [][]*individual {
+	size := len(population)
+	dominatedBy := make([][]int, size) // i가 지배하는 개체 목록
+	dominationCount := make([]int, size)
+
+	var current []int
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			switch {
+			case dominates(population[i].objectives, population[j].objectives):
+				dominatedBy[i] = append(dominatedBy[i], j)
+				dominationCount[j]++
+			case dominates(population[j].objectives, population[i].objectives):
+				dominatedBy[j] = append(dominatedBy[j], i)
+				dominationCount[i]++
+			}
+		}
+	}
+	for i := 0; i < size; i++ {
+		if dominationCount[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	var fronts [][]*individual
+	for rank := 0; len(current) > 0; rank++ {
+		front := make([]*individual, 0, len(current))
+		var next []int
+		for _, i := range current {
+			population[i].rank = rank
+			front = append(front, population[i])
+			for _, j := range dominatedBy[i] {
+				dominationCount[j]--
+				if dominationCount[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		sort.Ints(next)
+		fronts = append(fronts, front)
+		current = next
+	}
+	return fronts
+}
+
+// assignCrowdingDistance는 front 내 각 개체의 밀집 거리를 계산합니다
+func assignCrowdingDistance(front []*individual) {
+	if len(front) == 0 {
+		return
+	}
+	for _, ind := range front {
+		ind.crowding = 0
+	}
+
+	sorted := make([]*individual, len(front))
+	for m := range front[0].objectives {
+		copy(sorted, front)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].objectives[m] < sorted[j].objectives[m]
+		})
+
+		sorted[0].crowding = math.Inf(1)
+		sorted[len(sorted)-1].crowding = math.Inf(1)
+
+		span := sorted[len(sorted)-1].objectives[m] - sorted[0].objectives[m]
+		if span == 0 {
+			continue
+		}
+		for i := 1; i < len(sorted)-1; i++ {
+			sorted[i].crowding += (sorted[i+1].objectives[m] - sorted[i-1].objectives[m]) / span
+		}
+	}
+}
+
+// selectNSGA2는 front 순위와 밀집 거리로 k개 개체를 선택합니다
+func selectNSGA2(population []*individual, k int) []*individual {
+	fronts := sortNondominated(population)
+
+	chosen := make([]*individual, 0, k)
+	for _, front := range fronts {
+		assignCrowdingDistance(front)
+		if len(chosen)+len(front) <= k {
+			chosen = append(chosen, front...)
+			continue
+		}
+
+		// 마지막 front는 밀집 거리가 큰 순서로 채움
+		last := make([]*individual, len(front))
+		copy(last, front)
+		sort.SliceStable(last, func(i, j int) bool {
+			return last[i].crowding > last[j].crowding
+		})
+		chosen = append(chosen, last[:k-len(chosen)]...)
+		break
+	}
+	return chosen
+}
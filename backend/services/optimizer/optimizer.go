@@ -0,0 +1,612 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services"
+)
+
+// 최적화 기본값 (scripts/aggregator_optimization.py와 동일)
+const (
+	defaultUSDToKRW             = 1300.0
+	defaultMaxBudget            = 100000000 // 1억원
+	defaultMaxLatency           = 1000.0    // ms
+	defaultMinMemoryRequirement = 4         // GB
+	defaultWeightBalance        = 4
+	defaultBandwidthMbps        = 100.0 // 라운드 전송 시간 추정에 사용하는 집계자-참여자 대역폭
+
+	// 참여자 리전 지연시간 정보가 없을 때 사용하는 값 (ms)
+	fallbackAvgLatency = 3.0
+	fallbackMaxLatency = 5.0
+
+	// 결과 수집 설정
+	collectFronts     = 3
+	collectCandidates = 25
+	maxResults        = 20
+	diversityPerAxis  = 5
+)
+
+// PriceSource는 클라우드 가격 정보를 제공합니다 (repository.CloudPriceRepository)
+type PriceSource interface {
+	GetAllCloudPrices() ([]*models.CloudPrice, error)
+}
+
+// SnapshotPriceSource는 버전이 지정된 가격 스냅샷을 조회할 수 있는 가격 소스입니다
+// version이 nil이면 현재 유효한 가격표와 그 버전을 반환합니다
+type SnapshotPriceSource interface {
+	PriceSource
+	GetCloudPricesAt(version *int) ([]*models.CloudPrice, int, error)
+}
+
+// LatencySource는 리전 간 지연시간 정보를 제공합니다 (repository.CloudLatencyRepository)
+type LatencySource interface {
+	GetAllCloudLatencies() ([]*models.CloudLatency, error)
+}
+
+// Config는 NSGA-II 탐색 설정입니다
+type Config struct {
+	Seed           int64 // 같은 시드와 입력이면 같은 결과를 반환
+	Generations    int
+	MinPopulation  int
+	MaxPopulation  int
+	CrossoverProb  float64
+	MutationProb   float64
+	GeneMutateProb float64
+	USDToKRW       float64
+	BandwidthMbps  float64
+}
+
+// DefaultConfig는 Python 구현과 같은 진화 파라미터를 반환합니다
+// OPTIMIZER_SEED 환경변수가 설정되면 고정 시드를 사용합니다
+func DefaultConfig() Config {
+	config := Config{
+		Seed:           time.Now().UnixNano(),
+		Generations:    300,
+		MinPopulation:  50,
+		MaxPopulation:  200,
+		CrossoverProb:  0.7,
+		MutationProb:   0.3,
+		GeneMutateProb: 0.3,
+		USDToKRW:       defaultUSDToKRW,
+		BandwidthMbps:  defaultBandwidthMbps,
+	}
+
+	if seed, err := strconv.ParseInt(os.Getenv("OPTIMIZER_SEED"), 10, 64); err == nil {
+		config.Seed = seed
+	}
+	if rate, err := strconv.ParseFloat(os.Getenv("USD_TO_KRW"), 64); err == nil && rate > 0 {
+		config.USDToKRW = rate
+	}
+	if bandwidth, err := strconv.ParseFloat(os.Getenv("OPTIMIZER_BANDWIDTH_MBPS"), 64); err == nil && bandwidth > 0 {
+		config.BandwidthMbps = bandwidth
+	}
+	return config
+}
+
+// Optimizer는 비용/지연시간 기준으로 집계자 배치 후보를 탐색하는 NSGA-II 최적화기입니다
+type Optimizer struct {
+	prices    PriceSource
+	latencies LatencySource
+	config    Config
+}
+
+// NewOptimizer는 새 Optimizer 인스턴스를 생성합니다
+func NewOptimizer(prices PriceSource, latencies LatencySource, config Config) *Optimizer {
+	return &Optimizer{
+		prices:    prices,
+		latencies: latencies,
+		config:    config,
+	}
+}
+
+// candidate는 리전/인스턴스 타입별 집계자 배치 후보입니다
+type candidate struct {
+	region        string
+	instanceType  string
+	cloudProvider string
+	cost          float64 // 월 예상 비용 (KRW)
+	avgLatency    float64
+	maxLatency    float64
+	vcpu          int
+	memoryMB      int
+	gpu           int
+	hourlyPrice   float64
+
+	roundTransferMs float64 // 페이로드 크기 지정 시 라운드당 예상 전송 시간
+	dominance       services.ParetoDominance
+}
+
+type latencyStats struct {
+	avg float64
+	max float64
+}
+
+// constraints는 요청에서 추출한 제약사항입니다
+type constraints struct {
+	maxBudget            float64
+	maxLatency           float64
+	minMemoryRequirement int
+	weightBalance        int
+	minVCPU              int
+	requireGPU           bool
+	allowedProviders     map[string]bool // 비어있으면 모든 제공자 허용 (소문자)
+	excludedRegions      map[string]bool // 소문자
+	payloadSizeMB        float64
+}
+
+// Optimize는 요청 조건에 맞는 집계자 옵션을 탐색하여 services.OptimizationResponse 형식으로 반환합니다
+func (o *Optimizer) Optimize(request services.OptimizationRequest) (*services.OptimizationResponse, error) {
+	startTime := time.Now()
+
+	if _, err := models.NormalizeAlgorithm(request.FederatedLearning.Algorithm); err != nil {
+		return nil, err
+	}
+
+	prices, snapshotVersion, err := o.loadPrices(request.PriceSnapshotVersion)
+	if err != nil {
+		return nil, err
+	}
+	latencies, err := o.latencies.GetAllCloudLatencies()
+	if err != nil {
+		return nil, fmt.Errorf("지연시간 정보 조회 실패: %v", err)
+	}
+
+	limits := extractConstraints(request)
+	candidates := o.generateCandidates(request.FederatedLearning.Participants, prices, buildLatencyMatrix(latencies), limits)
+
+	costWeight := float64(limits.weightBalance) / 10.0
+	latencyWeight := 1.0 - costWeight
+
+	var options, paretoFront []services.AggregatorOption
+	if len(candidates) > 0 {
+		front := analyzeDominance(candidates)
+		paretoFront = formatResults(front, costWeight, latencyWeight)
+
+		selected := o.search(candidates)
+		options = formatResults(selected, costWeight, latencyWeight)
+	}
+
+	message := fmt.Sprintf("%d개의 최적 집계자 옵션을 찾았습니다.", len(options))
+	if len(candidates) == 0 {
+		message = "사용자 조건에 맞는 집계자 옵션이 없습니다."
+	}
+
+	return &services.OptimizationResponse{
+		Status:           "completed",
+		Summary:          buildSummary(request, len(prices), len(candidates), limits, costWeight, latencyWeight),
+		OptimizedOptions: options,
+		ParetoFront:      paretoFront,
+		ParetoObjectives: paretoObjectives,
+		Message:          message,
+		ExecutionTime:    time.Since(startTime).Seconds(),
+
+		PriceSnapshotVersion: snapshotVersion,
+	}, nil
+}
+
+// loadPrices는 요청에 고정된 스냅샷 버전 또는 현재 가격표를 조회합니다
+func (o *Optimizer) loadPrices(version *int) ([]*models.CloudPrice, int, error) {
+	if source, ok := o.prices.(SnapshotPriceSource); ok {
+		prices, used, err := source.GetCloudPricesAt(version)
+		if err != nil {
+			return nil, 0, fmt.Errorf("클라우드 가격 정보 조회 실패: %w", err)
+		}
+		return prices, used, nil
+	}
+
+	if version != nil {
+		return nil, 0, fmt.Errorf("가격 스냅샷 버전 고정을 지원하지 않는 가격 소스입니다")
+	}
+	prices, err := o.prices.GetAllCloudPrices()
+	if err != nil {
+		return nil, 0, fmt.Errorf("클라우드 가격 정보 조회 실패: %v", err)
+	}
+	return prices, 0, nil
+}
+
+func extractConstraints(request services.OptimizationRequest) constraints {
+	config := request.AggregatorConfig
+	limits := constraints{
+		maxBudget:            defaultMaxBudget,
+		maxLatency:           defaultMaxLatency,
+		minMemoryRequirement: defaultMinMemoryRequirement,
+		weightBalance:        defaultWeightBalance,
+		minVCPU:              config.MinVCPU,
+		requireGPU:           config.RequireGPU,
+		allowedProviders:     lowerSet(config.AllowedProviders),
+		excludedRegions:      lowerSet(config.ExcludedRegions),
+		payloadSizeMB:        config.PayloadSizeMB,
+	}
+	if config.MaxBudget > 0 {
+		limits.maxBudget = float64(config.MaxBudget)
+	}
+	if config.MaxLatency > 0 {
+		limits.maxLatency = float64(config.MaxLatency)
+	}
+	if config.MinMemoryRequirement > 0 {
+		limits.minMemoryRequirement = config.MinMemoryRequirement
+	}
+	if wb := config.WeightBalance; wb != nil && *wb >= 0 && *wb <= 10 {
+		limits.weightBalance = *wb
+	}
+	return limits
+}
+
+// allows는 가격 항목이 제공자/리전/사양 제약을 만족하는지 확인합니다
+func (c constraints) allows(price *models.CloudPrice) bool {
+	if len(c.allowedProviders) > 0 && !c.allowedProviders[strings.ToLower(price.Provider.Name)] {
+		return false
+	}
+	if c.excludedRegions[strings.ToLower(price.Region.Name)] {
+		return false
+	}
+	if price.MemoryGB < c.minMemoryRequirement || price.VCPUCount < c.minVCPU {
+		return false
+	}
+	if c.requireGPU && price.GPUCount <= 0 {
+		return false
+	}
+	return true
+}
+
+func lowerSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			set[v] = true
+		}
+	}
+	return set
+}
+
+// buildLatencyMatrix는 source 리전 → target 리전 지연시간 매트릭스를 생성합니다
+func buildLatencyMatrix(latencies []*models.CloudLatency) map[string]map[string]latencyStats {
+	matrix := make(map[string]map[string]latencyStats)
+	for _, l := range latencies {
+		source, target := l.SourceRegion.Name, l.TargetRegion.Name
+		if matrix[source] == nil {
+			matrix[source] = make(map[string]latencyStats)
+		}
+
+		// NULL 값은 평균 지연시간으로 대체
+		stats := latencyStats{avg: l.AvgLatency, max: l.AvgLatency}
+		if l.MaxLatency != nil {
+			stats.max = *l.MaxLatency
+		}
+		matrix[source][target] = stats
+	}
+	return matrix
+}
+
+// lookupLatency는 두 리전 간 지연시간을 조회하며, 정방향 값이 없으면 역방향 값을 사용합니다
+func lookupLatency(matrix map[string]map[string]latencyStats, source, target string) (latencyStats, bool) {
+	if stats, ok := matrix[source][target]; ok {
+		return stats, true
+	}
+	stats, ok := matrix[target][source]
+	return stats, ok
+}
+
+// generateCandidates는 가격 정보와 참여자 리전 지연시간으로 제약을 만족하는 후보를 생성합니다
+func (o *Optimizer) generateCandidates(participants []services.Participant, prices []*models.CloudPrice, matrix map[string]map[string]latencyStats, limits constraints) []candidate {
+	// 실행마다 같은 순서를 보장하기 위해 제공자, 리전, 가격 순으로 정렬
+	sorted := make([]*models.CloudPrice, len(prices))
+	copy(sorted, prices)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Provider.Name != sorted[j].Provider.Name {
+			return sorted[i].Provider.Name < sorted[j].Provider.Name
+		}
+		if sorted[i].Region.Name != sorted[j].Region.Name {
+			return sorted[i].Region.Name < sorted[j].Region.Name
+		}
+		return sorted[i].OnDemandPrice < sorted[j].OnDemandPrice
+	})
+
+	var candidates []candidate
+	for _, price := range sorted {
+		if !limits.allows(price) {
+			continue
+		}
+		region := price.Region.Name
+
+		// 방향: aggregator_region → participant_region, 참여자별 지연시간의 합계 사용
+		// 참여자 측정값(participant_region → aggregator_region)만 있으면 역방향 값을 사용
+		var avgLatency, maxLatency, slowestParticipant float64
+		found := false
+		for _, participant := range participants {
+			if stats, ok := lookupLatency(matrix, region, participant.Region); ok {
+				avgLatency += stats.avg
+				maxLatency += stats.max
+				slowestParticipant = math.Max(slowestParticipant, stats.avg)
+				found = true
+			}
+		}
+		if !found {
+			avgLatency = fallbackAvgLatency
+			maxLatency = fallbackMaxLatency
+			slowestParticipant = fallbackAvgLatency
+		}
+
+		monthlyCost := price.MonthlyRate() * o.config.USDToKRW
+		if monthlyCost > limits.maxBudget || avgLatency > limits.maxLatency {
+			continue
+		}
+
+		candidates = append(candidates, candidate{
+			region:        region,
+			instanceType:  price.InstanceType,
+			cloudProvider: price.Provider.Name,
+			cost:          monthlyCost,
+			avgLatency:    avgLatency,
+			maxLatency:    maxLatency,
+			vcpu:          price.VCPUCount,
+			memoryMB:      price.MemoryGB * 1024,
+			gpu:           price.GPUCount,
+			hourlyPrice:   price.HourlyRate(),
+
+			roundTransferMs: o.estimateRoundTransferMs(limits.payloadSizeMB, slowestParticipant),
+		})
+	}
+	return candidates
+}
+
+// estimateRoundTransferMs는 라운드마다 모델을 내려보내고 업데이트를 받는 데 걸리는 시간을 추정합니다
+// 참여자는 병렬로 통신하므로 가장 느린 참여자 기준이며, 지연시간 값은 왕복 시간(RTT)으로 취급합니다
+func (o *Optimizer) estimateRoundTransferMs(payloadSizeMB, slowestLatencyMs float64) float64 {
+	if payloadSizeMB <= 0 {
+		return 0
+	}
+	bandwidth := o.config.BandwidthMbps
+	if bandwidth <= 0 {
+		bandwidth = defaultBandwidthMbps
+	}
+	transferMs := payloadSizeMB * 8 / bandwidth * 1000
+	return 2 * (transferMs + slowestLatencyMs)
+}
+
+// paretoObjectives는 Pareto front 계산에 사용하는 목적 함수입니다 (모두 최소화)
+var paretoObjectives = []string{"cost", "avgLatency", "maxLatency"}
+
+func candidateObjectives(c candidate) []float64 {
+	return []float64{c.cost, c.avgLatency, c.maxLatency}
+}
+
+// analyzeDominance는 전체 후보의 지배 관계를 계산해 기록하고 Pareto front 후보를 반환합니다
+func analyzeDominance(candidates []candidate) []candidate {
+	population := make([]*individual, len(candidates))
+	for i, c := range candidates {
+		population[i] = &individual{genome: []int{i}, objectives: candidateObjectives(c), evaluated: true}
+	}
+	fronts := sortNondominated(population)
+
+	for i := range candidates {
+		candidates[i].dominance = services.ParetoDominance{ParetoRank: population[i].rank}
+		for j := range candidates {
+			if i == j {
+				continue
+			}
+			if dominates(population[i].objectives, population[j].objectives) {
+				candidates[i].dominance.Dominates++
+			} else if dominates(population[j].objectives, population[i].objectives) {
+				candidates[i].dominance.DominatedBy++
+			}
+		}
+	}
+
+	front := make([]candidate, 0, len(fronts[0]))
+	for _, ind := range fronts[0] {
+		front = append(front, candidates[ind.genome[0]])
+	}
+	return front
+}
+
+// search는 NSGA-II로 비용/평균 지연시간/최대 지연시간 Pareto 후보를 찾아 최대 20개를 반환합니다
+func (o *Optimizer) search(candidates []candidate) []candidate {
+	// 목적 함수별 최대값으로 정규화
+	scale := make([]float64, len(paretoObjectives))
+	for _, c := range candidates {
+		for m, v := range candidateObjectives(c) {
+			scale[m] = math.Max(scale[m], v)
+		}
+	}
+	for m := range scale {
+		if scale[m] == 0 {
+			scale[m] = 1
+		}
+	}
+
+	populationSize := len(candidates)
+	if populationSize < o.config.MinPopulation {
+		populationSize = o.config.MinPopulation
+	}
+	if populationSize > o.config.MaxPopulation {
+		populationSize = o.config.MaxPopulation
+	}
+
+	engine := &nsga2{
+		params: nsga2Params{
+			populationSize:   populationSize,
+			generations:      o.config.Generations,
+			genomeLength:     1,
+			numChoices:       len(candidates),
+			crossoverProb:    o.config.CrossoverProb,
+			mutationProb:     o.config.MutationProb,
+			geneMutationProb: o.config.GeneMutateProb,
+		},
+		rng: rand.New(rand.NewSource(o.config.Seed)),
+		evaluate: func(genome []int) []float64 {
+			objectives := candidateObjectives(candidates[genome[0]])
+			for m := range objectives {
+				objectives[m] /= scale[m]
+			}
+			return objectives
+		},
+	}
+	population := engine.run()
+
+	// 상위 3개 front에서 해 수집
+	var collected []*individual
+	for i, front := range sortNondominated(population) {
+		if i >= collectFronts || len(collected) >= collectCandidates {
+			break
+		}
+		collected = append(collected, front...)
+	}
+	if len(collected) > collectCandidates {
+		collected = collected[:collectCandidates]
+	}
+
+	// 후보 인덱스 기준 중복 제거 (리전/인스턴스/제공자 조합은 가격 테이블에서 유일)
+	seen := make(map[int]bool)
+	var indices []int
+	for _, ind := range collected {
+		index := ind.genome[0]
+		if seen[index] {
+			continue
+		}
+		seen[index] = true
+		indices = append(indices, index)
+		if len(indices) >= maxResults {
+			break
+		}
+	}
+
+	// 결과가 부족하면 비용/지연시간 상위 후보로 다양성 보완
+	if len(indices) < maxResults {
+		byCost := rankedIndices(candidates, func(c candidate) float64 { return c.cost })
+		byLatency := rankedIndices(candidates, func(c candidate) float64 { return c.avgLatency })
+		for _, index := range append(byCost[:min(diversityPerAxis, len(byCost))], byLatency[:min(diversityPerAxis, len(byLatency))]...) {
+			if seen[index] {
+				continue
+			}
+			seen[index] = true
+			indices = append(indices, index)
+			if len(indices) >= maxResults {
+				break
+			}
+		}
+	}
+
+	selected := make([]candidate, len(indices))
+	for i, index := range indices {
+		selected[i] = candidates[index]
+	}
+	return selected
+}
+
+func rankedIndices(candidates []candidate, key func(candidate) float64) []int {
+	indices := make([]int, len(candidates))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return key(candidates[indices[i]]) < key(candidates[indices[j]])
+	})
+	return indices
+}
+
+// formatResults는 가중합 점수로 정렬하여 프론트엔드 응답 형식으로 변환합니다
+func formatResults(selected []candidate, costWeight, latencyWeight float64) []services.AggregatorOption {
+	maxCost, maxLatency := 0.0, 0.0
+	for _, c := range selected {
+		maxCost = math.Max(maxCost, c.cost)
+		maxLatency = math.Max(maxLatency, c.avgLatency)
+	}
+
+	scores := make([]float64, len(selected))
+	for i, c := range selected {
+		normCost, normLatency := 0.0, 0.0
+		if maxCost > 0 {
+			normCost = c.cost / maxCost
+		}
+		if maxLatency > 0 {
+			normLatency = c.avgLatency / maxLatency
+		}
+		scores[i] = costWeight*normCost + latencyWeight*normLatency
+	}
+
+	order := make([]int, len(selected))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] < scores[order[j]] })
+
+	options := make([]services.AggregatorOption, len(order))
+	for rank, i := range order {
+		c := selected[i]
+		dominance := c.dominance
+		options[rank] = services.AggregatorOption{
+			Rank:                 rank + 1,
+			Region:               c.region,
+			InstanceType:         c.instanceType,
+			CloudProvider:        c.cloudProvider,
+			EstimatedMonthlyCost: roundTo(c.cost, 0),
+			EstimatedHourlyPrice: roundTo(c.hourlyPrice, 4),
+			AvgLatency:           roundTo(c.avgLatency, 2),
+			MaxLatency:           roundTo(c.maxLatency, 2),
+			VCPU:                 c.vcpu,
+			Memory:               c.memoryMB,
+			RecommendationScore:  roundTo((1-scores[i])*100, 1), // 높을수록 좋음
+
+			GPU:                      c.gpu,
+			EstimatedRoundTransferMs: roundTo(c.roundTransferMs, 1),
+			Dominance:                &dominance,
+		}
+	}
+	return options
+}
+
+func buildSummary(request services.OptimizationRequest, totalCandidates, feasible int, limits constraints, costWeight, latencyWeight float64) services.OptimizationSummary {
+	regionSet := make(map[string]bool)
+	var regions []string
+	for _, p := range request.FederatedLearning.Participants {
+		region := p.Region
+		if region == "" {
+			region = "unknown"
+		}
+		if !regionSet[region] {
+			regionSet[region] = true
+			regions = append(regions, region)
+		}
+	}
+
+	return services.OptimizationSummary{
+		TotalParticipants:     len(request.FederatedLearning.Participants),
+		ParticipantRegions:    regions,
+		TotalCandidateOptions: totalCandidates,
+		FeasibleOptions:       feasible,
+		Constraints: map[string]interface{}{
+			"maxBudget":            limits.maxBudget,
+			"maxLatency":           limits.maxLatency,
+			"minMemoryRequirement": limits.minMemoryRequirement,
+			"weightBalance":        limits.weightBalance,
+			"minVcpu":              limits.minVCPU,
+			"requireGpu":           limits.requireGPU,
+			"allowedProviders":     request.AggregatorConfig.AllowedProviders,
+			"excludedRegions":      request.AggregatorConfig.ExcludedRegions,
+			"payloadSizeMB":        limits.payloadSizeMB,
+			"appliedWeights": map[string]float64{
+				"costWeight":    costWeight,
+				"latencyWeight": latencyWeight,
+			},
+		},
+		ModelInfo: map[string]interface{}{
+			"name":      request.FederatedLearning.Name,
+			"modelType": request.FederatedLearning.ModelType,
+			"rounds":    request.FederatedLearning.Rounds,
+		},
+	}
+}
+
+func roundTo(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
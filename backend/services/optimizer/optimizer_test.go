@@ -0,0 +1,221 @@
+package optimizer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/services"
+)
+
+type fakePrices []*models.CloudPrice
+
+func (f fakePrices) GetAllCloudPrices() ([]*models.CloudPrice, error) { return f, nil }
+
+type fakeLatencies []*models.CloudLatency
+
+func (f fakeLatencies) GetAllCloudLatencies() ([]*models.CloudLatency, error) { return f, nil }
+
+func price(provider, region, instanceType string, vcpu, memoryGB int, hourly float64) *models.CloudPrice {
+	return &models.CloudPrice{
+		Provider:      models.Provider{Name: provider},
+		Region:        models.Region{Name: region},
+		InstanceType:  instanceType,
+		VCPUCount:     vcpu,
+		MemoryGB:      memoryGB,
+		OnDemandPrice: hourly,
+	}
+}
+
+func latency(source, target string, avg float64) *models.CloudLatency {
+	return &models.CloudLatency{
+		SourceRegion: models.Region{Name: source},
+		TargetRegion: models.Region{Name: target},
+		AvgLatency:   avg,
+	}
+}
+
+func testOptimizer(seed int64) *Optimizer {
+	prices := fakePrices{
+		price("AWS", "ap-northeast-2", "t3.medium", 2, 4, 0.052),
+		price("AWS", "ap-northeast-2", "m5.large", 2, 8, 0.118),
+		price("AWS", "us-east-1", "t3.medium", 2, 4, 0.0416),
+		price("AWS", "us-east-1", "m5.large", 2, 8, 0.096),
+		price("GCP", "asia-northeast3", "e2-medium", 2, 4, 0.043),
+		price("GCP", "asia-northeast3", "e2-standard-4", 4, 16, 0.17),
+		price("GCP", "us-central1", "e2-medium", 2, 4, 0.0335),
+		price("GCP", "us-central1", "e2-micro", 2, 1, 0.0084), // 메모리 부족으로 제외
+	}
+	latencies := fakeLatencies{
+		latency("ap-northeast-2", "ap-northeast-2", 2),
+		latency("us-east-1", "ap-northeast-2", 180),
+		latency("asia-northeast3", "ap-northeast-2", 5),
+		latency("us-central1", "ap-northeast-2", 150),
+	}
+
+	config := DefaultConfig()
+	config.Seed = seed
+	config.Generations = 50
+	return NewOptimizer(prices, latencies, config)
+}
+
+func testRequest() services.OptimizationRequest {
+	var request services.OptimizationRequest
+	request.FederatedLearning.Name = "test"
+	request.FederatedLearning.Algorithm = "fedavg"
+	request.FederatedLearning.Rounds = 3
+	request.FederatedLearning.Participants = []services.Participant{
+		{ID: "p1", Name: "p1", Region: "ap-northeast-2"},
+	}
+	request.AggregatorConfig.MaxBudget = 1000000
+	request.AggregatorConfig.MaxLatency = 500
+	return request
+}
+
+func TestOptimizeDeterministicWithSeed(t *testing.T) {
+	first, err := testOptimizer(42).Optimize(testRequest())
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+	second, err := testOptimizer(42).Optimize(testRequest())
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	if !reflect.DeepEqual(first.OptimizedOptions, second.OptimizedOptions) {
+		t.Fatalf("same seed produced different options:\n%+v\n%+v", first.OptimizedOptions, second.OptimizedOptions)
+	}
+}
+
+func TestOptimizeAppliesConstraintsAndRanks(t *testing.T) {
+	result, err := testOptimizer(1).Optimize(testRequest())
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+
+	if result.Summary.TotalCandidateOptions != 8 || result.Summary.FeasibleOptions != 7 {
+		t.Fatalf("unexpected summary counts: %+v", result.Summary)
+	}
+	if len(result.OptimizedOptions) != 7 {
+		t.Fatalf("expected all 7 feasible options, got %d", len(result.OptimizedOptions))
+	}
+
+	for i, option := range result.OptimizedOptions {
+		if option.Rank != i+1 {
+			t.Fatalf("option %d has rank %d", i, option.Rank)
+		}
+		if option.InstanceType == "e2-micro" {
+			t.Fatalf("option below minimum memory was returned: %+v", option)
+		}
+		if i > 0 && option.RecommendationScore > result.OptimizedOptions[i-1].RecommendationScore {
+			t.Fatalf("options not sorted by score: %+v", result.OptimizedOptions)
+		}
+	}
+
+	// 월 비용 = 시간당 가격 * 24 * 30 * 환율
+	want := 0.052 * 24 * 30 * defaultUSDToKRW
+	for _, option := range result.OptimizedOptions {
+		if option.Region == "ap-northeast-2" && option.InstanceType == "t3.medium" {
+			if option.EstimatedMonthlyCost != roundTo(want, 0) || option.AvgLatency != 2 {
+				t.Fatalf("unexpected cost/latency: %+v", option)
+			}
+		}
+	}
+}
+
+func TestOptimizeRejectsUnsupportedAlgorithm(t *testing.T) {
+	request := testRequest()
+	request.FederatedLearning.Algorithm = "fednova"
+	if _, err := testOptimizer(1).Optimize(request); err == nil {
+		t.Fatal("expected unsupported algorithm error")
+	}
+}
+
+func TestSelectNSGA2KeepsFirstFront(t *testing.T) {
+	population := []*individual{
+		{genome: []int{0}, objectives: []float64{1, 5}},
+		{genome: []int{1}, objectives: []float64{2, 2}},
+		{genome: []int{2}, objectives: []float64{5, 1}},
+		{genome: []int{3}, objectives: []float64{3, 3}}, // (2,2)에 지배됨
+		{genome: []int{4}, objectives: []float64{6, 6}}, // 모두에게 지배됨
+	}
+
+	fronts := sortNondominated(population)
+	if len(fronts) != 3 || len(fronts[0]) != 3 {
+		t.Fatalf("unexpected fronts: %d fronts, first size %d", len(fronts), len(fronts[0]))
+	}
+
+	chosen := selectNSGA2(population, 3)
+	for _, ind := range chosen {
+		if ind.rank != 0 {
+			t.Fatalf("selected dominated individual %v", ind.genome)
+		}
+	}
+}
+
+func TestOptimizeReturnsParetoFrontWithDominance(t *testing.T) {
+	result, err := testOptimizer(7).Optimize(testRequest())
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+	if len(result.ParetoFront) == 0 {
+		t.Fatal("expected a non-empty Pareto front")
+	}
+
+	for _, option := range result.ParetoFront {
+		if option.Dominance == nil || option.Dominance.ParetoRank != 0 || option.Dominance.DominatedBy != 0 {
+			t.Fatalf("front option has unexpected dominance: %+v", option)
+		}
+	}
+
+	// 가장 저렴한 후보와 가장 지연시간이 낮은 후보는 항상 front에 포함
+	var hasCheapest, hasFastest bool
+	for _, option := range result.ParetoFront {
+		if option.Region == "us-central1" && option.InstanceType == "e2-medium" {
+			hasCheapest = true
+		}
+		if option.Region == "ap-northeast-2" && option.InstanceType == "t3.medium" {
+			hasFastest = true
+		}
+		if option.Region == "us-east-1" && option.InstanceType == "m5.large" {
+			t.Fatalf("dominated option in Pareto front: %+v", option)
+		}
+	}
+	if !hasCheapest || !hasFastest {
+		t.Fatalf("front missing extreme options: %+v", result.ParetoFront)
+	}
+}
+
+func TestOptimizeAppliesWhatIfConstraints(t *testing.T) {
+	request := testRequest()
+	request.AggregatorConfig.AllowedProviders = []string{"gcp"}
+	request.AggregatorConfig.ExcludedRegions = []string{"us-central1"}
+	request.AggregatorConfig.MinVCPU = 4
+	request.AggregatorConfig.PayloadSizeMB = 50
+
+	result, err := testOptimizer(3).Optimize(request)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+	if len(result.OptimizedOptions) != 1 {
+		t.Fatalf("expected only e2-standard-4, got %+v", result.OptimizedOptions)
+	}
+
+	option := result.OptimizedOptions[0]
+	if option.InstanceType != "e2-standard-4" {
+		t.Fatalf("unexpected option: %+v", option)
+	}
+	// 50MB * 8 / 100Mbps = 4000ms, (4000 + 5ms RTT) * 2
+	if option.EstimatedRoundTransferMs != 8010 {
+		t.Fatalf("unexpected round transfer estimate: %v", option.EstimatedRoundTransferMs)
+	}
+
+	request.AggregatorConfig.RequireGPU = true
+	result, err = testOptimizer(3).Optimize(request)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+	if len(result.OptimizedOptions) != 0 {
+		t.Fatalf("expected no GPU options, got %+v", result.OptimizedOptions)
+	}
+}
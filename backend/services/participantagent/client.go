@@ -0,0 +1,132 @@
+package participantagent
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// mTLS 설정 환경 변수 (선택, 에이전트가 https로 수신할 때 사용)
+const (
+	EnvCAFile         = "PARTICIPANT_AGENT_CA_FILE"          // 상대 인증서를 검증할 CA 번들
+	EnvClientCertFile = "PARTICIPANT_AGENT_CLIENT_CERT_FILE" // 요청 시 제시할 클라이언트 인증서
+	EnvClientKeyFile  = "PARTICIPANT_AGENT_CLIENT_KEY_FILE"
+)
+
+// maxResponseBytes는 에이전트 응답 본문의 최대 읽기 크기입니다
+const maxResponseBytes = 64 * 1024
+
+// TLSConfigFromEnv는 환경 변수의 CA/클라이언트 인증서로 TLS 설정을 만듭니다 (설정이 없으면 nil)
+func TLSConfigFromEnv() (*tls.Config, error) {
+	caFile := os.Getenv(EnvCAFile)
+	certFile := os.Getenv(EnvClientCertFile)
+	keyFile := os.Getenv(EnvClientKeyFile)
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("CA 파일 읽기 실패: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA 파일에 인증서가 없습니다: %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("클라이언트 인증서 읽기 실패: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// NewHTTPClient는 환경 변수의 mTLS 설정을 적용한 HTTP 클라이언트를 생성합니다
+func NewHTTPClient(timeout time.Duration) (*http.Client, error) {
+	tlsConfig, err := TLSConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: timeout}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+	return client, nil
+}
+
+// PostSigned는 payload를 JSON으로 인코딩해 서명 토큰과 함께 POST하고 상태 코드와 응답 본문을 반환합니다
+func PostSigned(ctx context.Context, client *http.Client, url, secret, participantID, audience string, payload interface{}) (int, []byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, nil, fmt.Errorf("JSON 인코딩 실패: %w", err)
+	}
+	token, err := SignRequest(secret, participantID, audience, body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("요청 서명 실패: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, fmt.Errorf("HTTP 요청 생성 실패: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(ProtocolHeader, ProtocolVersion)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("HTTP 요청 전송 실패: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("응답 본문 읽기 실패: %w", err)
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+// GetSigned는 경로+쿼리에 대한 서명 토큰과 함께 GET 요청을 보냅니다 (응답 본문은 호출자가 닫아야 함)
+func GetSigned(ctx context.Context, client *http.Client, url, secret, participantID, audience string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP 요청 생성 실패: %w", err)
+	}
+	token, err := SignRequest(secret, participantID, audience, []byte(req.URL.RequestURI()))
+	if err != nil {
+		return nil, fmt.Errorf("요청 서명 실패: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(ProtocolHeader, ProtocolVersion)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP 요청 전송 실패: %w", err)
+	}
+	return resp, nil
+}
+
+// BearerToken은 Authorization 헤더에서 Bearer 토큰을 꺼냅니다
+func BearerToken(header string) string {
+	const prefix = "Bearer "
+	if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+	return ""
+}
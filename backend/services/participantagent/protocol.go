@@ -0,0 +1,252 @@
+// Package participantagent는 백엔드와 참여자 VM의 에이전트가 주고받는 API 규약(v1)입니다
+//
+// 백엔드 → 에이전트: POST {agent}/api/fl/execute-local (ExecuteRequest), POST {agent}/api/fl/stop-local (StopRequest),
+// GET {agent}/api/fl/logs?job_id=...&offset=...&follow=true (학습 로그, text/plain),
+// POST {agent}/api/latency/probe (LatencyProbeRequest, 후보 집계자 리전까지 지연시간 측정)
+// 에이전트 → 백엔드: POST {callback_url} (StatusCallback, CallbackPath)
+//
+// 모든 요청은 참여자별 비밀 키로 서명한 토큰(Authorization: Bearer)으로 양방향 인증하며,
+// 토큰에 요청 본문(GET은 경로+쿼리)의 SHA-256을 넣어 변조를 막습니다 (token.go)
+package participantagent
+
+import (
+	_ "embed"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ProtocolVersion은 현재 에이전트 API 버전입니다 (요청 본문 protocol_version과 ProtocolHeader에 사용)
+const ProtocolVersion = "v1"
+
+// ProtocolHeader는 요청/응답의 프로토콜 버전 헤더입니다
+const ProtocolHeader = "X-Agent-Protocol-Version"
+
+// 에이전트 API 경로
+const (
+	ExecutePath = "/api/fl/execute-local"
+	StopPath    = "/api/fl/stop-local"
+	HealthPath  = "/api/fl/health"
+	LogsPath    = "/api/fl/logs"
+
+	LatencyProbePath = "/api/latency/probe"
+)
+
+// 로그 응답 헤더
+const (
+	LogOffsetHeader = "X-Log-Offset" // 응답 본문이 시작하는 로그 바이트 위치 (이어 받기용)
+	JobStateHeader  = "X-Job-State"  // 응답 시점의 작업 상태
+)
+
+// CallbackPath는 백엔드의 상태 알림 수신 경로입니다 (%s = 참여자 ID)
+const CallbackPath = "/api/participant-agent/" + ProtocolVersion + "/participants/%s/status"
+
+// AgentPort는 참여자 VM에서 에이전트가 수신하는 포트입니다
+const AgentPort = 5000
+
+// SchemaV1은 v1 요청/응답의 JSON Schema 문서입니다
+//
+//go:embed schema_v1.json
+var SchemaV1 []byte
+
+// 참여자 작업 상태 (StatusCallback.State)
+const (
+	StateAccepted   = "accepted"   // 실행 요청 수신
+	StateInstalling = "installing" // 가상환경/의존성 설치 중
+	StateTraining   = "training"   // Flower 클라이언트 학습 중 (Round = 진행 중인 라운드)
+	StateFinished   = "finished"   // 정상 종료
+	StateFailed     = "failed"     // 설치/실행 실패 또는 타임아웃
+	StateStopped    = "stopped"    // 중지 요청으로 종료
+)
+
+// IsValidState는 알려진 작업 상태인지 확인합니다
+func IsValidState(state string) bool {
+	switch state {
+	case StateAccepted, StateInstalling, StateTraining, StateFinished, StateFailed, StateStopped:
+		return true
+	}
+	return false
+}
+
+// IsTerminalState는 더 이상 바뀌지 않는 작업 상태인지 확인합니다
+func IsTerminalState(state string) bool {
+	return state == StateFinished || state == StateFailed || state == StateStopped
+}
+
+// maxMessageLength는 StatusCallback.Message의 최대 길이입니다
+const maxMessageLength = 4096
+
+var jobIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,200}$`)
+
+// JobID는 연합학습 작업의 재시작 회차와 참여자로 정해지는 작업 ID입니다
+// 같은 회차의 재전송은 같은 ID이므로 에이전트가 중복 실행하지 않습니다
+func JobID(flID string, attempt int, participantID string) string {
+	return fmt.Sprintf("%s.%d.%s", flID, attempt, participantID)
+}
+
+// ExecuteRequest는 참여자에게 로컬 학습 실행을 요청합니다
+type ExecuteRequest struct {
+	ProtocolVersion     string            `json:"protocol_version"`
+	JobID               string            `json:"job_id"` // 멱등 키 (JobID)
+	FederatedLearningID string            `json:"federated_learning_id"`
+	ParticipantID       string            `json:"participant_id"`
+	Attempt             int               `json:"attempt"`
+	ServerAddress       string            `json:"server_address"` // Flower 서버 host:port
+	LocalEpochs         int               `json:"local_epochs"`
+	Timeout             int               `json:"timeout"` // 초, 전체 실행 제한 시간
+	Files               map[string]string `json:"files"`   // 파일 이름 → 내용 (client_app.py, task.py 등)
+	Dependencies        []string          `json:"dependencies,omitempty"`
+	CallbackURL         string            `json:"callback_url"` // StatusCallback을 보낼 주소
+}
+
+// Validate는 실행 요청의 필수 값과 형식을 확인합니다
+func (r *ExecuteRequest) Validate() error {
+	if r.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("지원하지 않는 프로토콜 버전입니다: %q", r.ProtocolVersion)
+	}
+	if !jobIDPattern.MatchString(r.JobID) {
+		return fmt.Errorf("job_id 형식이 올바르지 않습니다")
+	}
+	if r.FederatedLearningID == "" || r.ParticipantID == "" {
+		return fmt.Errorf("federated_learning_id와 participant_id가 필요합니다")
+	}
+	if r.ServerAddress == "" {
+		return fmt.Errorf("server_address가 필요합니다")
+	}
+	if r.LocalEpochs <= 0 || r.Timeout <= 0 {
+		return fmt.Errorf("local_epochs와 timeout은 0보다 커야 합니다")
+	}
+	if len(r.Files) == 0 {
+		return fmt.Errorf("files가 비어 있습니다")
+	}
+	for name := range r.Files {
+		if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+			return fmt.Errorf("잘못된 파일 이름입니다: %q", name)
+		}
+	}
+	if r.CallbackURL == "" {
+		return fmt.Errorf("callback_url이 필요합니다")
+	}
+	return nil
+}
+
+// StopRequest는 참여자에게 로컬 학습 중지를 요청합니다
+type StopRequest struct {
+	ProtocolVersion     string `json:"protocol_version"`
+	JobID               string `json:"job_id,omitempty"` // 비어 있으면 작업의 모든 회차
+	FederatedLearningID string `json:"federated_learning_id"`
+	Reason              string `json:"reason,omitempty"`
+}
+
+// Validate는 중지 요청의 필수 값을 확인합니다
+func (r *StopRequest) Validate() error {
+	if r.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("지원하지 않는 프로토콜 버전입니다: %q", r.ProtocolVersion)
+	}
+	if r.FederatedLearningID == "" {
+		return fmt.Errorf("federated_learning_id가 필요합니다")
+	}
+	if r.JobID != "" && !jobIDPattern.MatchString(r.JobID) {
+		return fmt.Errorf("job_id 형식이 올바르지 않습니다")
+	}
+	return nil
+}
+
+// JobResponse는 실행/중지 요청에 대한 에이전트 응답입니다
+type JobResponse struct {
+	ProtocolVersion string `json:"protocol_version"`
+	JobID           string `json:"job_id"`
+	State           string `json:"state"`
+	Duplicate       bool   `json:"duplicate,omitempty"` // 이미 받은 job_id (새로 실행하지 않음)
+}
+
+// MaxLatencyProbeCount는 지연시간 측정 요청 한 번의 최대 측정 횟수입니다
+const MaxLatencyProbeCount = 20
+
+// LatencyProbeRequest는 참여자 에이전트에게 target까지의 TCP 연결 시간 측정을 요청합니다
+type LatencyProbeRequest struct {
+	ProtocolVersion string `json:"protocol_version"`
+	Target          string `json:"target"` // host:port
+	Count           int    `json:"count"`
+}
+
+// Validate는 측정 요청의 필수 값과 범위를 확인합니다
+func (r *LatencyProbeRequest) Validate() error {
+	if r.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("지원하지 않는 프로토콜 버전입니다: %q", r.ProtocolVersion)
+	}
+	if _, port, err := net.SplitHostPort(r.Target); err != nil || port == "" {
+		return fmt.Errorf("target은 host:port 형식이어야 합니다: %q", r.Target)
+	}
+	if r.Count <= 0 || r.Count > MaxLatencyProbeCount {
+		return fmt.Errorf("count는 1 이상 %d 이하여야 합니다", MaxLatencyProbeCount)
+	}
+	return nil
+}
+
+// LatencyProbeResponse는 지연시간 측정 결과입니다 (측정 중 실패하면 그때까지의 결과와 Error)
+type LatencyProbeResponse struct {
+	ProtocolVersion string    `json:"protocol_version"`
+	SamplesMs       []float64 `json:"samples_ms"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// ErrorResponse는 에이전트/백엔드의 오류 응답입니다
+type ErrorResponse struct {
+	ProtocolVersion string `json:"protocol_version"`
+	Error           string `json:"error"`
+}
+
+// StatusCallback은 에이전트가 작업 상태가 바뀔 때마다 백엔드로 보내는 알림입니다
+type StatusCallback struct {
+	ProtocolVersion     string    `json:"protocol_version"`
+	JobID               string    `json:"job_id"`
+	FederatedLearningID string    `json:"federated_learning_id"`
+	ParticipantID       string    `json:"participant_id"`
+	Sequence            int64     `json:"sequence"` // 작업별로 1부터 증가 (중복/역순 전달 무시)
+	State               string    `json:"state"`
+	Round               int       `json:"round,omitempty"` // training: 진행 중인 라운드, finished: 마지막 라운드
+	Message             string    `json:"message,omitempty"`
+	Timestamp           time.Time `json:"timestamp"`
+}
+
+// Validate는 상태 알림의 필수 값과 형식을 확인합니다
+func (c *StatusCallback) Validate() error {
+	if c.ProtocolVersion != ProtocolVersion {
+		return fmt.Errorf("지원하지 않는 프로토콜 버전입니다: %q", c.ProtocolVersion)
+	}
+	if !jobIDPattern.MatchString(c.JobID) {
+		return fmt.Errorf("job_id 형식이 올바르지 않습니다")
+	}
+	if c.FederatedLearningID == "" || c.ParticipantID == "" {
+		return fmt.Errorf("federated_learning_id와 participant_id가 필요합니다")
+	}
+	if c.Sequence <= 0 {
+		return fmt.Errorf("sequence는 1 이상이어야 합니다")
+	}
+	if !IsValidState(c.State) {
+		return fmt.Errorf("알 수 없는 상태입니다: %q", c.State)
+	}
+	if c.Round < 0 {
+		return fmt.Errorf("round는 0 이상이어야 합니다")
+	}
+	if len(c.Message) > maxMessageLength {
+		return fmt.Errorf("message는 %d바이트 이하여야 합니다", maxMessageLength)
+	}
+	if c.Timestamp.IsZero() {
+		return fmt.Errorf("timestamp가 필요합니다")
+	}
+	return nil
+}
+
+// CallbackURL은 백엔드 주소에 참여자의 상태 알림 수신 경로를 붙입니다
+func CallbackURL(baseURL, participantID string) string {
+	return strings.TrimRight(baseURL, "/") + fmt.Sprintf(CallbackPath, participantID)
+}
+
+// AgentURL은 참여자 엔드포인트(scheme://host)의 에이전트 주소에 path를 붙입니다
+func AgentURL(endpoint, path string) string {
+	return fmt.Sprintf("%s:%d%s", strings.TrimRight(endpoint, "/"), AgentPort, path)
+}
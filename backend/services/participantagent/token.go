@@ -0,0 +1,103 @@
+package participantagent
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 토큰 대상 (같은 비밀 키로 서명한 토큰을 반대 방향에 재사용하지 못하도록 구분)
+const (
+	AudienceAgent   = "participant-agent"           // 백엔드 → 에이전트 요청
+	AudienceBackend = "fleecy-cloud-agent-callback" // 에이전트 → 백엔드 상태 알림
+)
+
+const (
+	tokenIssuer = "fleecy-cloud"
+	tokenTTL    = 5 * time.Minute
+	tokenLeeway = 30 * time.Second
+	secretBytes = 32
+)
+
+// 토큰 검증 실패 사유
+var (
+	ErrMissingToken     = errors.New("인증 토큰이 없습니다")
+	ErrBodyHashMismatch = errors.New("요청 본문이 토큰과 일치하지 않습니다")
+)
+
+// requestClaims는 요청 서명 토큰의 클레임입니다 (sub = 참여자 ID)
+type requestClaims struct {
+	BodySHA256 string `json:"body_sha256"`
+	jwt.RegisteredClaims
+}
+
+// NewSecret은 참여자 에이전트용 새 비밀 키를 생성합니다 (base64url)
+func NewSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("비밀 키 생성 실패: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// SignRequest는 참여자 비밀 키로 요청 본문에 대한 토큰을 발급합니다
+func SignRequest(secret, participantID, audience string, body []byte) (string, error) {
+	if secret == "" {
+		return "", errors.New("비밀 키가 비어 있습니다")
+	}
+	now := time.Now()
+	claims := requestClaims{
+		BodySHA256: bodyHash(body),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   participantID,
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(tokenTTL)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
+// VerifyRequest는 토큰의 서명, 대상, 참여자, 만료 시각과 요청 본문 해시를 확인합니다
+func VerifyRequest(secret, token, participantID, audience string, body []byte) error {
+	if token == "" {
+		return ErrMissingToken
+	}
+	if secret == "" {
+		return errors.New("비밀 키가 비어 있습니다")
+	}
+
+	claims := &requestClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithAudience(audience),
+		jwt.WithSubject(participantID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(tokenLeeway),
+	)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.BodySHA256), []byte(bodyHash(body))) != 1 {
+		return ErrBodyHashMismatch
+	}
+	return nil
+}
+
+func bodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,64 @@
+package participantagent
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestSecret(t *testing.T) string {
+	t.Helper()
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret
+}
+
+func TestSignAndVerifyRequest(t *testing.T) {
+	secret := newTestSecret(t)
+	body := []byte(`{"protocol_version":"v1","job_id":"fl.0.p1"}`)
+
+	token, err := SignRequest(secret, "p1", AudienceAgent, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRequest(secret, token, "p1", AudienceAgent, body); err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+}
+
+func TestVerifyRequestRejects(t *testing.T) {
+	secret := newTestSecret(t)
+	body := []byte(`{"state":"training"}`)
+	token, err := SignRequest(secret, "p1", AudienceBackend, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name          string
+		secret        string
+		token         string
+		participantID string
+		audience      string
+		body          []byte
+	}{
+		{"missing token", secret, "", "p1", AudienceBackend, body},
+		{"other secret", newTestSecret(t), token, "p1", AudienceBackend, body},
+		{"other participant", secret, token, "p2", AudienceBackend, body},
+		{"other audience", secret, token, "p1", AudienceAgent, body},
+		{"tampered body", secret, token, "p1", AudienceBackend, []byte(`{"state":"finished"}`)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := VerifyRequest(tc.secret, tc.token, tc.participantID, tc.audience, tc.body); err == nil {
+				t.Fatal("expected verification to fail")
+			}
+		})
+	}
+
+	err = VerifyRequest(secret, token, "p1", AudienceBackend, []byte(`{}`))
+	if !errors.Is(err, ErrBodyHashMismatch) {
+		t.Fatalf("expected ErrBodyHashMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,328 @@
+package pricing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+)
+
+var (
+	// ErrSnapshotNotFound는 요청한 버전의 스냅샷이 없을 때 반환됩니다
+	ErrSnapshotNotFound = errors.New("price snapshot not found")
+	// ErrIdenticalSnapshot은 최신 스냅샷과 내용이 같은 가격표를 임포트할 때 반환됩니다
+	ErrIdenticalSnapshot = errors.New("price sheet is identical to the latest snapshot")
+)
+
+// ImportRequest는 가격표 임포트 요청입니다
+type ImportRequest struct {
+	Rows        []SheetRow
+	Format      string
+	Source      string
+	Note        string
+	EffectiveAt time.Time // 비어있으면 즉시 적용
+	CreatedBy   *int64
+}
+
+// ImportResult는 임포트로 생성된 스냅샷과 직전 스냅샷 대비 변경 내역입니다
+type ImportResult struct {
+	Snapshot *models.PriceSnapshot `json:"snapshot"`
+	Diff     *Diff                 `json:"diff"`
+}
+
+// Catalog는 버전이 지정된 클라우드 가격표를 관리합니다
+// 스냅샷이 하나도 없으면 기존 cloud_price 테이블을 현재 가격표로 사용합니다
+type Catalog struct {
+	snapshots *repository.PriceSnapshotRepository
+	prices    *repository.CloudPriceRepository
+	providers *repository.ProviderRepository
+	regions   *repository.RegionRepository
+}
+
+// NewCatalog는 새 Catalog 인스턴스를 생성합니다
+func NewCatalog(
+	snapshots *repository.PriceSnapshotRepository,
+	prices *repository.CloudPriceRepository,
+	providers *repository.ProviderRepository,
+	regions *repository.RegionRepository,
+) *Catalog {
+	return &Catalog{
+		snapshots: snapshots,
+		prices:    prices,
+		providers: providers,
+		regions:   regions,
+	}
+}
+
+// Import는 검증된 가격표를 새 스냅샷 버전으로 저장하고 직전 버전과의 차이를 반환합니다
+func (c *Catalog) Import(request ImportRequest) (*ImportResult, error) {
+	if request.EffectiveAt.IsZero() {
+		request.EffectiveAt = time.Now()
+	}
+	if err := validateRows(request.Rows); err != nil {
+		return nil, err
+	}
+
+	hash := contentHash(request.Rows)
+	latest, err := c.snapshots.GetLatest()
+	if err != nil {
+		return nil, fmt.Errorf("최신 가격 스냅샷 조회 실패: %v", err)
+	}
+	if latest != nil && latest.ContentHash == hash {
+		return nil, ErrIdenticalSnapshot
+	}
+
+	items, err := c.resolveItems(request.Rows)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.PriceSnapshot{
+		EffectiveAt: request.EffectiveAt,
+		Source:      request.Source,
+		Format:      request.Format,
+		Note:        request.Note,
+		ContentHash: hash,
+		CreatedBy:   request.CreatedBy,
+	}
+	if err := c.snapshots.CreateSnapshot(snapshot, items); err != nil {
+		return nil, fmt.Errorf("가격 스냅샷 저장 실패: %v", err)
+	}
+	log.Printf("가격 스냅샷 v%d 생성 - 항목: %d, 적용 시점: %s, 출처: %s",
+		snapshot.Version, snapshot.ItemCount, snapshot.EffectiveAt.Format(time.RFC3339), snapshot.Source)
+
+	var previous []SheetRow
+	fromVersion := 0
+	if latest != nil {
+		previousItems, err := c.snapshots.GetItems(latest.ID)
+		if err != nil {
+			return nil, fmt.Errorf("이전 가격 스냅샷 조회 실패: %v", err)
+		}
+		previous = itemsToRows(previousItems)
+		fromVersion = latest.Version
+	}
+
+	return &ImportResult{
+		Snapshot: snapshot,
+		Diff:     diffRows(fromVersion, snapshot.Version, previous, request.Rows),
+	}, nil
+}
+
+// resolveItems는 제공자/리전 이름을 ID로 변환해 스냅샷 항목을 만듭니다
+func (c *Catalog) resolveItems(rows []SheetRow) ([]*models.PriceSnapshotItem, error) {
+	providerIDs := make(map[string]int)
+	regionIDs := make(map[string]int)
+
+	items := make([]*models.PriceSnapshotItem, 0, len(rows))
+	for _, row := range rows {
+		providerID, ok := providerIDs[row.Provider]
+		if !ok {
+			provider, err := c.providers.CreateOrGetProvider(row.Provider)
+			if err != nil {
+				return nil, err
+			}
+			providerID = provider.ID
+			providerIDs[row.Provider] = providerID
+		}
+
+		regionID, ok := regionIDs[row.Region]
+		if !ok {
+			region, err := c.regions.CreateOrGetRegion(row.Region)
+			if err != nil {
+				return nil, err
+			}
+			regionID = region.ID
+			regionIDs[row.Region] = regionID
+		}
+
+		items = append(items, &models.PriceSnapshotItem{
+			ProviderID:    providerID,
+			RegionID:      regionID,
+			InstanceType:  row.InstanceType,
+			VCPUCount:     row.VCPUCount,
+			MemoryGB:      row.MemoryGB,
+			GPUCount:      row.GPUCount,
+			OnDemandPrice: row.OnDemandPrice,
+		})
+	}
+	return items, nil
+}
+
+// ListSnapshots는 스냅샷 목록을 최신 버전순으로 반환합니다
+func (c *Catalog) ListSnapshots() ([]*models.PriceSnapshot, error) {
+	return c.snapshots.GetSnapshots()
+}
+
+// GetSnapshot은 버전의 스냅샷과 가격 항목을 반환합니다
+func (c *Catalog) GetSnapshot(version int) (*models.PriceSnapshot, []*models.PriceSnapshotItem, error) {
+	snapshot, err := c.snapshots.GetByVersion(version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if snapshot == nil {
+		return nil, nil, ErrSnapshotNotFound
+	}
+	items, err := c.snapshots.GetItems(snapshot.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return snapshot, items, nil
+}
+
+// DiffVersions는 version과 against 버전의 차이를 계산합니다
+// against가 nil이면 직전 버전과 비교합니다
+func (c *Catalog) DiffVersions(version int, against *int) (*Diff, error) {
+	snapshot, items, err := c.GetSnapshot(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var base *models.PriceSnapshot
+	if against != nil {
+		if base, err = c.snapshots.GetByVersion(*against); err != nil {
+			return nil, err
+		}
+		if base == nil {
+			return nil, ErrSnapshotNotFound
+		}
+	} else if base, err = c.snapshots.GetPreviousVersion(version); err != nil {
+		return nil, err
+	}
+
+	var previous []SheetRow
+	fromVersion := 0
+	if base != nil {
+		baseItems, err := c.snapshots.GetItems(base.ID)
+		if err != nil {
+			return nil, err
+		}
+		previous = itemsToRows(baseItems)
+		fromVersion = base.Version
+	}
+	return diffRows(fromVersion, snapshot.Version, previous, itemsToRows(items)), nil
+}
+
+// GetAllCloudPrices는 현재 유효한 가격표를 반환합니다 (optimizer.PriceSource 구현)
+func (c *Catalog) GetAllCloudPrices() ([]*models.CloudPrice, error) {
+	prices, _, err := c.GetCloudPricesAt(nil)
+	return prices, err
+}
+
+// GetCloudPricesAt은 지정한 스냅샷 버전의 가격표와 실제 사용한 버전을 반환합니다
+// version이 nil이면 현재 유효한 스냅샷을 사용하며, 스냅샷이 없으면 cloud_price 테이블과 버전 0을 반환합니다
+func (c *Catalog) GetCloudPricesAt(version *int) ([]*models.CloudPrice, int, error) {
+	var snapshot *models.PriceSnapshot
+	var err error
+	if version != nil {
+		if snapshot, err = c.snapshots.GetByVersion(*version); err != nil {
+			return nil, 0, err
+		}
+		if snapshot == nil {
+			return nil, 0, fmt.Errorf("%w: v%d", ErrSnapshotNotFound, *version)
+		}
+	} else if snapshot, err = c.snapshots.GetEffectiveAt(time.Now()); err != nil {
+		return nil, 0, err
+	}
+
+	if snapshot == nil {
+		prices, err := c.prices.GetAllCloudPrices()
+		return prices, 0, err
+	}
+
+	items, err := c.snapshots.GetItems(snapshot.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	prices := make([]*models.CloudPrice, 0, len(items))
+	for _, item := range items {
+		prices = append(prices, item.ToCloudPrice())
+	}
+	return prices, snapshot.Version, nil
+}
+
+// EffectiveVersionAt은 at 시점에 유효했던 스냅샷 버전을 반환합니다 (지난 기간의 비용 계산용)
+// at 이전에 유효해진 스냅샷이 없으면 가장 오래된 버전을, 스냅샷이 하나도 없으면 0(cloud_price 테이블)을 반환합니다
+func (c *Catalog) EffectiveVersionAt(at time.Time) (int, error) {
+	snapshot, err := c.snapshots.GetEffectiveAt(at)
+	if err != nil {
+		return 0, err
+	}
+	if snapshot == nil {
+		if snapshot, err = c.snapshots.GetOldest(); err != nil || snapshot == nil {
+			return 0, err
+		}
+	}
+	return snapshot.Version, nil
+}
+
+// SeedFromCloudPrices는 스냅샷이 없을 때 기존 cloud_price 테이블 내용을 첫 스냅샷으로 저장합니다
+// 이후 임포트하는 가격표의 변경 내역을 초기 CSV 가격과 비교할 수 있게 합니다
+func (c *Catalog) SeedFromCloudPrices() error {
+	count, err := c.snapshots.Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	prices, err := c.prices.GetAllCloudPrices()
+	if err != nil {
+		return err
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+
+	rows := make([]SheetRow, 0, len(prices))
+	for _, price := range prices {
+		rows = append(rows, SheetRow{
+			Provider:      price.Provider.Name,
+			Region:        price.Region.Name,
+			InstanceType:  price.InstanceType,
+			VCPUCount:     price.VCPUCount,
+			MemoryGB:      price.MemoryGB,
+			GPUCount:      price.GPUCount,
+			OnDemandPrice: price.OnDemandPrice,
+		})
+	}
+
+	// 초기 CSV에는 검증 규칙을 만족하지 않는 행이 있을 수 있으므로 그대로 저장
+	items, err := c.resolveItems(rows)
+	if err != nil {
+		return err
+	}
+	snapshot := &models.PriceSnapshot{
+		EffectiveAt: time.Unix(0, 0).UTC(),
+		Source:      "seed",
+		Format:      FormatCSV,
+		Note:        "asset/cloud_price_*.csv 초기 가격표",
+		ContentHash: contentHash(rows),
+	}
+	if err := c.snapshots.CreateSnapshot(snapshot, items); err != nil {
+		return err
+	}
+	log.Printf("기존 가격표로 초기 가격 스냅샷 v%d 생성 (%d개 항목)", snapshot.Version, snapshot.ItemCount)
+	return nil
+}
+
+// contentHash는 행 순서와 무관한 가격표 내용 해시를 계산합니다
+func contentHash(rows []SheetRow) string {
+	sorted := make([]SheetRow, len(rows))
+	copy(sorted, rows)
+	sortRows(sorted)
+
+	h := sha256.New()
+	for _, row := range sorted {
+		fmt.Fprintf(h, "%s|%s|%s|%d|%d|%d|%s\n",
+			row.Provider, row.Region, row.InstanceType, row.VCPUCount, row.MemoryGB, row.GPUCount,
+			strconv.FormatFloat(row.OnDemandPrice, 'f', 6, 64))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,128 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/repository"
+)
+
+// newTestCatalog는 테스트마다 분리된 메모리 SQLite DB로 Catalog를 생성합니다
+func newTestCatalog(t *testing.T) (*Catalog, *gorm.DB) {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", regexp.MustCompile(`\W`).ReplaceAllString(t.Name(), "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.Provider{}, &models.Region{}, &models.CloudPrice{}, &models.PriceSnapshot{}, &models.PriceSnapshotItem{}); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := NewCatalog(
+		repository.NewPriceSnapshotRepository(db),
+		repository.NewCloudPriceRepository(db),
+		repository.NewProviderRepository(db),
+		repository.NewRegionRepository(db),
+	)
+	return catalog, db
+}
+
+func priceSheet(price float64) []SheetRow {
+	return []SheetRow{{Provider: "aws", Region: "ap-northeast-2", InstanceType: "t3.medium", VCPUCount: 2, MemoryGB: 4, OnDemandPrice: price}}
+}
+
+func TestGetCloudPricesAt(t *testing.T) {
+	catalog, db := newTestCatalog(t)
+
+	// 스냅샷이 없으면 cloud_price 테이블
+	provider := &models.Provider{Name: "aws"}
+	region := &models.Region{Name: "ap-northeast-2"}
+	db.Create(provider)
+	db.Create(region)
+	db.Create(&models.CloudPrice{ProviderID: provider.ID, RegionID: region.ID, InstanceType: "t3.medium", VCPUCount: 2, MemoryGB: 4, OnDemandPrice: 0.05})
+	prices, version, err := catalog.GetCloudPricesAt(nil)
+	if err != nil || version != 0 || len(prices) != 1 || prices[0].OnDemandPrice != 0.05 {
+		t.Fatalf("without snapshots = %v, v%d, %v", prices, version, err)
+	}
+
+	now := time.Now()
+	if _, err := catalog.Import(ImportRequest{Rows: priceSheet(0.1), Format: FormatCSV, EffectiveAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := catalog.Import(ImportRequest{Rows: priceSheet(0.2), Format: FormatCSV, EffectiveAt: now.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Snapshot.Version != 2 || result.Diff.FromVersion != 1 || len(result.Diff.Changed) != 1 {
+		t.Fatalf("import v2 = %+v, diff %+v", result.Snapshot, result.Diff)
+	}
+	if _, err := catalog.Import(ImportRequest{Rows: priceSheet(0.2), Format: FormatCSV}); !errors.Is(err, ErrIdenticalSnapshot) {
+		t.Fatalf("identical import error = %v", err)
+	}
+
+	cases := []struct {
+		name        string
+		version     *int
+		wantVersion int
+		wantPrice   float64
+	}{
+		// 아직 적용 시점이 지나지 않은 v2는 현재 가격이 아님
+		{"current", nil, 1, 0.1},
+		{"pinned v1", intPtr(1), 1, 0.1},
+		{"pinned future v2", intPtr(2), 2, 0.2},
+	}
+	for _, tc := range cases {
+		prices, version, err := catalog.GetCloudPricesAt(tc.version)
+		if err != nil || version != tc.wantVersion || len(prices) != 1 || prices[0].OnDemandPrice != tc.wantPrice {
+			t.Errorf("%s: %v, v%d, %v", tc.name, prices, version, err)
+			continue
+		}
+		if prices[0].Provider.Name != "aws" || prices[0].Region.Name != "ap-northeast-2" {
+			t.Errorf("%s: provider/region not loaded: %+v", tc.name, prices[0])
+		}
+	}
+
+	if _, _, err := catalog.GetCloudPricesAt(intPtr(9)); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Fatalf("missing version error = %v", err)
+	}
+
+	// 지난 기간 비용 계산용 시점별 버전 (첫 스냅샷 이전은 가장 오래된 버전)
+	versions := []struct {
+		at   time.Time
+		want int
+	}{
+		{now.Add(-2 * time.Hour), 1},
+		{now, 1},
+		{now.Add(time.Hour), 2},
+		{now.Add(48 * time.Hour), 2},
+	}
+	for _, tc := range versions {
+		if got, err := catalog.EffectiveVersionAt(tc.at); err != nil || got != tc.want {
+			t.Errorf("EffectiveVersionAt(%v) = %d, %v; want %d", tc.at.Sub(now), got, err, tc.want)
+		}
+	}
+}
+
+func TestEffectiveVersionWithoutSnapshots(t *testing.T) {
+	catalog, _ := newTestCatalog(t)
+	if version, err := catalog.EffectiveVersionAt(time.Now()); err != nil || version != 0 {
+		t.Fatalf("EffectiveVersionAt = %d, %v; want 0", version, err)
+	}
+}
+
+func intPtr(v int) *int { return &v }
@@ -0,0 +1,110 @@
+package pricing
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+// PriceChange는 두 스냅샷 사이에 값이 바뀐 인스턴스 가격입니다
+type PriceChange struct {
+	Provider      string   `json:"provider"`
+	Region        string   `json:"region"`
+	InstanceType  string   `json:"instance_type"`
+	OldPrice      float64  `json:"old_price"`
+	NewPrice      float64  `json:"new_price"`
+	ChangePercent *float64 `json:"change_percent,omitempty"` // 이전 가격이 0이면 생략
+	SpecChanged   bool     `json:"spec_changed"`             // vCPU/메모리/GPU 변경 여부
+}
+
+// Diff는 두 스냅샷의 차이입니다
+type Diff struct {
+	FromVersion int           `json:"from_version"` // 0이면 이전 스냅샷 없음
+	ToVersion   int           `json:"to_version"`
+	Added       []SheetRow    `json:"added"`
+	Removed     []SheetRow    `json:"removed"`
+	Changed     []PriceChange `json:"changed"`
+	Unchanged   int           `json:"unchanged"`
+}
+
+// itemsToRows는 스냅샷 항목을 비교 가능한 SheetRow로 변환합니다 (Provider/Region preload 필요)
+func itemsToRows(items []*models.PriceSnapshotItem) []SheetRow {
+	rows := make([]SheetRow, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, SheetRow{
+			Provider:      item.Provider.Name,
+			Region:        item.Region.Name,
+			InstanceType:  item.InstanceType,
+			VCPUCount:     item.VCPUCount,
+			MemoryGB:      item.MemoryGB,
+			GPUCount:      item.GPUCount,
+			OnDemandPrice: item.OnDemandPrice,
+		})
+	}
+	return rows
+}
+
+// diffRows는 이전 행 목록과 새 행 목록을 비교합니다
+func diffRows(fromVersion, toVersion int, previous, current []SheetRow) *Diff {
+	diff := &Diff{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Added:       []SheetRow{},
+		Removed:     []SheetRow{},
+		Changed:     []PriceChange{},
+	}
+
+	old := make(map[string]SheetRow, len(previous))
+	for _, row := range previous {
+		old[itemKey(row.Provider, row.Region, row.InstanceType)] = row
+	}
+
+	for _, row := range current {
+		key := itemKey(row.Provider, row.Region, row.InstanceType)
+		before, ok := old[key]
+		if !ok {
+			diff.Added = append(diff.Added, row)
+			continue
+		}
+		delete(old, key)
+
+		specChanged := before.VCPUCount != row.VCPUCount || before.MemoryGB != row.MemoryGB || before.GPUCount != row.GPUCount
+		if before.OnDemandPrice == row.OnDemandPrice && !specChanged {
+			diff.Unchanged++
+			continue
+		}
+
+		change := PriceChange{
+			Provider:     row.Provider,
+			Region:       row.Region,
+			InstanceType: row.InstanceType,
+			OldPrice:     before.OnDemandPrice,
+			NewPrice:     row.OnDemandPrice,
+			SpecChanged:  specChanged,
+		}
+		if before.OnDemandPrice != 0 {
+			pct := math.Round((row.OnDemandPrice-before.OnDemandPrice)/before.OnDemandPrice*10000) / 100
+			change.ChangePercent = &pct
+		}
+		diff.Changed = append(diff.Changed, change)
+	}
+	for _, row := range old {
+		diff.Removed = append(diff.Removed, row)
+	}
+
+	sortRows(diff.Added)
+	sortRows(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		a, b := diff.Changed[i], diff.Changed[j]
+		return itemKey(a.Provider, a.Region, a.InstanceType) < itemKey(b.Provider, b.Region, b.InstanceType)
+	})
+	return diff
+}
+
+func sortRows(rows []SheetRow) {
+	sort.Slice(rows, func(i, j int) bool {
+		return itemKey(rows[i].Provider, rows[i].Region, rows[i].InstanceType) <
+			itemKey(rows[j].Provider, rows[j].Region, rows[j].InstanceType)
+	})
+}
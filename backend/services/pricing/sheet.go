@@ -0,0 +1,252 @@
+package pricing
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 지원하는 가격표 형식
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// DB 컬럼 크기 제한 (models.Provider, models.Region, models.CloudPrice)
+const (
+	maxProviderNameLength = 10
+	maxRegionNameLength   = 30
+	maxInstanceTypeLength = 30
+)
+
+// ErrUnsupportedFormat은 지원하지 않는 가격표 형식일 때 반환됩니다
+var ErrUnsupportedFormat = errors.New("unsupported price sheet format")
+
+// SheetRow는 가격표의 인스턴스 가격 한 줄입니다
+type SheetRow struct {
+	Provider      string  `json:"provider"`
+	Region        string  `json:"region"`
+	InstanceType  string  `json:"instance_type"`
+	VCPUCount     int     `json:"vcpu_count"`
+	MemoryGB      int     `json:"memory_gb"`
+	GPUCount      int     `json:"gpu_count"`
+	OnDemandPrice float64 `json:"on_demand_price"`
+}
+
+// SheetError는 가격표 검증 오류 목록입니다
+type SheetError struct {
+	Problems []string
+}
+
+func (e *SheetError) Error() string {
+	const shown = 10
+	problems := e.Problems
+	suffix := ""
+	if len(problems) > shown {
+		suffix = fmt.Sprintf(" (외 %d건)", len(problems)-shown)
+		problems = problems[:shown]
+	}
+	return "가격표 검증 실패: " + strings.Join(problems, "; ") + suffix
+}
+
+// csv 헤더 별칭 (기존 asset/cloud_price_*.csv 헤더 포함)
+var csvColumnAliases = map[string]string{
+	"cloud_name":      "provider",
+	"cloud":           "provider",
+	"provider":        "provider",
+	"region_name":     "region",
+	"region":          "region",
+	"instance_type":   "instance_type",
+	"vcpu_count":      "vcpu_count",
+	"v_cpu_count":     "vcpu_count",
+	"vcpu":            "vcpu_count",
+	"memory_gb":       "memory_gb",
+	"gpu_count":       "gpu_count",
+	"on_demand_price": "on_demand_price",
+	"hourly_price":    "on_demand_price",
+}
+
+var requiredColumns = []string{"provider", "region", "instance_type", "vcpu_count", "memory_gb", "on_demand_price"}
+
+// ParseSheet는 CSV 또는 JSON 가격표를 읽어 검증된 행 목록을 반환합니다
+// 같은 provider/region/instance_type이 두 번 나오거나 값이 잘못된 행이 있으면 전체를 거부합니다
+func ParseSheet(r io.Reader, format string) ([]SheetRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("가격표 읽기 실패: %v", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM 제거
+
+	var rows []SheetRow
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		rows, err = parseCSV(data)
+	case FormatJSON:
+		rows, err = parseJSON(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateRows(rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// DetectFormat은 파일 이름 확장자로 가격표 형식을 추정합니다
+func DetectFormat(filename string) string {
+	lower := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lower, ".json"):
+		return FormatJSON
+	case strings.HasSuffix(lower, ".csv"):
+		return FormatCSV
+	default:
+		return ""
+	}
+}
+
+func parseCSV(data []byte) ([]SheetRow, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV 파싱 실패: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, &SheetError{Problems: []string{"빈 가격표입니다"}}
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		if canonical, ok := csvColumnAliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[canonical] = i
+		}
+	}
+	var missing []string
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &SheetError{Problems: []string{"필수 컬럼 누락: " + strings.Join(missing, ", ")}}
+	}
+
+	var rows []SheetRow
+	var problems []string
+	for i, record := range records[1:] {
+		line := i + 2
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		row := SheetRow{
+			Provider:     field("provider"),
+			Region:       field("region"),
+			InstanceType: field("instance_type"),
+		}
+		var parseErrs []string
+		if row.VCPUCount, err = strconv.Atoi(field("vcpu_count")); err != nil {
+			parseErrs = append(parseErrs, "vcpu_count")
+		}
+		if row.MemoryGB, err = strconv.Atoi(field("memory_gb")); err != nil {
+			parseErrs = append(parseErrs, "memory_gb")
+		}
+		if gpu := field("gpu_count"); gpu != "" {
+			if row.GPUCount, err = strconv.Atoi(gpu); err != nil {
+				parseErrs = append(parseErrs, "gpu_count")
+			}
+		}
+		if row.OnDemandPrice, err = strconv.ParseFloat(field("on_demand_price"), 64); err != nil {
+			parseErrs = append(parseErrs, "on_demand_price")
+		}
+		if len(parseErrs) > 0 {
+			problems = append(problems, fmt.Sprintf("%d행: 숫자 형식 오류 (%s)", line, strings.Join(parseErrs, ", ")))
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(problems) > 0 {
+		return nil, &SheetError{Problems: problems}
+	}
+	return rows, nil
+}
+
+func parseJSON(data []byte) ([]SheetRow, error) {
+	var rows []SheetRow
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		// {"prices": [...]} 형식
+		var wrapper struct {
+			Prices []SheetRow `json:"prices"`
+		}
+		if err := json.Unmarshal(trimmed, &wrapper); err != nil {
+			return nil, fmt.Errorf("JSON 파싱 실패: %v", err)
+		}
+		rows = wrapper.Prices
+	} else if err := json.Unmarshal(trimmed, &rows); err != nil {
+		return nil, fmt.Errorf("JSON 파싱 실패: %v", err)
+	}
+	for i := range rows {
+		rows[i].Provider = strings.TrimSpace(rows[i].Provider)
+		rows[i].Region = strings.TrimSpace(rows[i].Region)
+		rows[i].InstanceType = strings.TrimSpace(rows[i].InstanceType)
+	}
+	return rows, nil
+}
+
+// validateRows는 필수 값, 범위, 중복을 검증하고 제공자 이름을 소문자로 정규화합니다
+func validateRows(rows []SheetRow) error {
+	if len(rows) == 0 {
+		return &SheetError{Problems: []string{"가격 항목이 없습니다"}}
+	}
+
+	var problems []string
+	seen := make(map[string]int)
+	for i := range rows {
+		row := &rows[i]
+		row.Provider = strings.ToLower(row.Provider)
+		label := fmt.Sprintf("%d번째 항목", i+1)
+
+		switch {
+		case row.Provider == "" || row.Region == "" || row.InstanceType == "":
+			problems = append(problems, label+": provider, region, instance_type은 필수입니다")
+			continue
+		case len(row.Provider) > maxProviderNameLength || len(row.Region) > maxRegionNameLength || len(row.InstanceType) > maxInstanceTypeLength:
+			problems = append(problems, label+": 이름이 너무 깁니다")
+			continue
+		case row.VCPUCount <= 0 || row.MemoryGB <= 0 || row.GPUCount < 0:
+			problems = append(problems, label+": vcpu_count와 memory_gb는 양수, gpu_count는 0 이상이어야 합니다")
+			continue
+		case row.OnDemandPrice < 0:
+			problems = append(problems, label+": on_demand_price는 0 이상이어야 합니다")
+			continue
+		}
+
+		key := itemKey(row.Provider, row.Region, row.InstanceType)
+		if first, ok := seen[key]; ok {
+			problems = append(problems, fmt.Sprintf("%s: %d번째 항목과 중복 (%s)", label, first+1, key))
+			continue
+		}
+		seen[key] = i
+	}
+	if len(problems) > 0 {
+		return &SheetError{Problems: problems}
+	}
+	return nil
+}
+
+func itemKey(provider, region, instanceType string) string {
+	return strings.ToLower(provider) + "/" + region + "/" + instanceType
+}
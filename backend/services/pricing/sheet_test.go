@@ -0,0 +1,126 @@
+package pricing
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSheet(t *testing.T) {
+	want := []SheetRow{
+		{Provider: "aws", Region: "ap-northeast-2", InstanceType: "t3.medium", VCPUCount: 2, MemoryGB: 4, OnDemandPrice: 0.052},
+		{Provider: "gcp", Region: "asia-northeast3", InstanceType: "a2-highgpu-1g", VCPUCount: 12, MemoryGB: 85, GPUCount: 1, OnDemandPrice: 4.2},
+	}
+	cases := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{"csv", FormatCSV, "provider,region,instance_type,vcpu_count,memory_gb,gpu_count,on_demand_price\n" +
+			"AWS,ap-northeast-2,t3.medium,2,4,,0.052\n" +
+			"gcp,asia-northeast3,a2-highgpu-1g,12,85,1,4.2\n"},
+		// 기존 asset CSV 헤더와 BOM
+		{"legacy csv headers", "CSV", "\xef\xbb\xbfcloud_name,region_name,instance_type,v_cpu_count,memory_gb,gpu_count,hourly_price\n" +
+			" aws , ap-northeast-2 ,t3.medium,2,4,0,0.052\n" +
+			"GCP,asia-northeast3,a2-highgpu-1g,12,85,1,4.2\n"},
+		{"json array", FormatJSON, `[
+			{"provider": "AWS", "region": "ap-northeast-2", "instance_type": "t3.medium", "vcpu_count": 2, "memory_gb": 4, "on_demand_price": 0.052},
+			{"provider": "gcp", "region": "asia-northeast3", "instance_type": "a2-highgpu-1g", "vcpu_count": 12, "memory_gb": 85, "gpu_count": 1, "on_demand_price": 4.2}
+		]`},
+		{"json wrapper", FormatJSON, `{"prices": [
+			{"provider": "aws", "region": " ap-northeast-2", "instance_type": "t3.medium", "vcpu_count": 2, "memory_gb": 4, "on_demand_price": 0.052},
+			{"provider": "gcp", "region": "asia-northeast3", "instance_type": "a2-highgpu-1g", "vcpu_count": 12, "memory_gb": 85, "gpu_count": 1, "on_demand_price": 4.2}
+		]}`},
+	}
+	for _, tc := range cases {
+		rows, err := ParseSheet(strings.NewReader(tc.input), tc.format)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(rows, want) {
+			t.Errorf("%s: rows = %+v", tc.name, rows)
+		}
+	}
+}
+
+func TestParseSheetRejectsBadRows(t *testing.T) {
+	const header = "provider,region,instance_type,vcpu_count,memory_gb,gpu_count,on_demand_price\n"
+	cases := []struct {
+		name    string
+		format  string
+		input   string
+		problem string
+	}{
+		{"missing column", FormatCSV, "provider,region,instance_type,vcpu_count,on_demand_price\naws,r,t,2,0.1\n", "memory_gb"},
+		{"empty csv", FormatCSV, "", "빈 가격표"},
+		{"header only", FormatCSV, header, "가격 항목이 없습니다"},
+		{"bad number", FormatCSV, header + "aws,r,t,two,4,0,0.1\n", "2행: 숫자 형식 오류 (vcpu_count)"},
+		{"missing name", FormatCSV, header + "aws,,t,2,4,0,0.1\n", "필수"},
+		{"long provider", FormatCSV, header + "averylongprovider,r,t,2,4,0,0.1\n", "너무 깁니다"},
+		{"zero memory", FormatCSV, header + "aws,r,t,2,0,0,0.1\n", "memory_gb는 양수"},
+		{"negative price", FormatCSV, header + "aws,r,t,2,4,0,-0.1\n", "on_demand_price는 0 이상"},
+		// 제공자 이름은 대소문자를 구분하지 않음
+		{"duplicate", FormatCSV, header + "aws,r,t,2,4,0,0.1\nAWS,r,t,2,4,0,0.2\n", "1번째 항목과 중복"},
+		{"negative gpu json", FormatJSON, `[{"provider":"aws","region":"r","instance_type":"t","vcpu_count":2,"memory_gb":4,"gpu_count":-1}]`, "gpu_count는 0 이상"},
+	}
+	for _, tc := range cases {
+		_, err := ParseSheet(strings.NewReader(tc.input), tc.format)
+		var sheetErr *SheetError
+		if !errors.As(err, &sheetErr) || !strings.Contains(err.Error(), tc.problem) {
+			t.Errorf("%s: error = %v, want SheetError containing %q", tc.name, err, tc.problem)
+		}
+	}
+
+	if _, err := ParseSheet(strings.NewReader("[}"), FormatJSON); err == nil {
+		t.Error("malformed json: expected an error")
+	}
+	if _, err := ParseSheet(strings.NewReader(header), "xlsx"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("xlsx: error = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestDiffRows(t *testing.T) {
+	previous := []SheetRow{
+		{Provider: "aws", Region: "r1", InstanceType: "same", VCPUCount: 2, MemoryGB: 4, OnDemandPrice: 0.1},
+		{Provider: "aws", Region: "r1", InstanceType: "cheaper", VCPUCount: 2, MemoryGB: 4, OnDemandPrice: 0.2},
+		{Provider: "aws", Region: "r1", InstanceType: "resized", VCPUCount: 2, MemoryGB: 4, OnDemandPrice: 0.3},
+		{Provider: "aws", Region: "r1", InstanceType: "was-free", VCPUCount: 1, MemoryGB: 1, OnDemandPrice: 0},
+		{Provider: "gcp", Region: "r2", InstanceType: "retired", VCPUCount: 2, MemoryGB: 4, OnDemandPrice: 0.4},
+	}
+	current := []SheetRow{
+		{Provider: "gcp", Region: "r2", InstanceType: "new", VCPUCount: 4, MemoryGB: 16, OnDemandPrice: 0.5},
+		{Provider: "aws", Region: "r1", InstanceType: "same", VCPUCount: 2, MemoryGB: 4, OnDemandPrice: 0.1},
+		{Provider: "aws", Region: "r1", InstanceType: "cheaper", VCPUCount: 2, MemoryGB: 4, OnDemandPrice: 0.15},
+		{Provider: "aws", Region: "r1", InstanceType: "resized", VCPUCount: 4, MemoryGB: 4, OnDemandPrice: 0.3},
+		{Provider: "aws", Region: "r1", InstanceType: "was-free", VCPUCount: 1, MemoryGB: 1, OnDemandPrice: 0.01},
+	}
+
+	diff := diffRows(3, 4, previous, current)
+	if diff.FromVersion != 3 || diff.ToVersion != 4 || diff.Unchanged != 1 {
+		t.Fatalf("diff = %+v", diff)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].InstanceType != "new" {
+		t.Fatalf("added = %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].InstanceType != "retired" {
+		t.Fatalf("removed = %+v", diff.Removed)
+	}
+
+	pct := func(v float64) *float64 { return &v }
+	wantChanged := []PriceChange{
+		{Provider: "aws", Region: "r1", InstanceType: "cheaper", OldPrice: 0.2, NewPrice: 0.15, ChangePercent: pct(-25)},
+		{Provider: "aws", Region: "r1", InstanceType: "resized", OldPrice: 0.3, NewPrice: 0.3, ChangePercent: pct(0), SpecChanged: true},
+		// 이전 가격이 0이면 변화율 생략
+		{Provider: "aws", Region: "r1", InstanceType: "was-free", OldPrice: 0, NewPrice: 0.01},
+	}
+	if !reflect.DeepEqual(diff.Changed, wantChanged) {
+		t.Fatalf("changed = %+v", diff.Changed)
+	}
+
+	// 이전 스냅샷이 없으면 전부 추가
+	if first := diffRows(0, 1, nil, current); len(first.Added) != len(current) || len(first.Removed) != 0 || len(first.Changed) != 0 {
+		t.Fatalf("first diff = %+v", first)
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit은 토큰 버킷 설정입니다 (Period 동안 Count회, 한 번에 최대 Count회까지 몰아서 허용)
+type Limit struct {
+	Count  int
+	Period time.Duration
+}
+
+// ParseLimit은 "20/m" 형식(s, m, h 단위)의 제한을 해석합니다
+func ParseLimit(s string) (Limit, error) {
+	countStr, unit, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("제한 형식이 올바르지 않습니다 (예: 20/m): %q", s)
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count <= 0 {
+		return Limit{}, fmt.Errorf("제한 횟수가 올바르지 않습니다: %q", s)
+	}
+
+	var period time.Duration
+	switch unit {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("제한 단위는 s, m, h 중 하나여야 합니다: %q", s)
+	}
+	return Limit{Count: count, Period: period}, nil
+}
+
+// ratePerSecond는 초당 토큰 충전량입니다
+func (l Limit) ratePerSecond() float64 {
+	return float64(l.Count) / l.Period.Seconds()
+}
+
+// Result는 토큰 요청 결과입니다
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // 거부된 경우 다음 토큰이 충전될 때까지 남은 시간
+}
+
+// takeToken은 마지막 갱신 이후 충전량을 반영한 뒤 토큰 하나를 꺼냅니다
+// 메모리/Postgres 저장소가 같은 계산을 사용하도록 분리한 함수입니다
+func takeToken(tokens float64, touched, now time.Time, limit Limit) (Result, float64) {
+	burst := float64(limit.Count)
+	if elapsed := now.Sub(touched).Seconds(); elapsed > 0 {
+		tokens = math.Min(burst, tokens+elapsed*limit.ratePerSecond())
+	}
+
+	if tokens >= 1 {
+		tokens--
+		return Result{Allowed: true, Remaining: int(tokens)}, tokens
+	}
+	wait := time.Duration((1 - tokens) / limit.ratePerSecond() * float64(time.Second))
+	return Result{Allowed: false, RetryAfter: wait}, tokens
+}
+
+// RetryAfterSeconds는 Retry-After 헤더에 넣을 초 단위 값을 반환합니다 (올림, 최소 1초)
+func RetryAfterSeconds(d time.Duration) int {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
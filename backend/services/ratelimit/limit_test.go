@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock은 테스트에서 직접 움직이는 시계입니다
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func approxDuration(got, want time.Duration) bool {
+	diff := got - want
+	return diff > -time.Millisecond && diff < time.Millisecond
+}
+
+func TestTakeToken(t *testing.T) {
+	limit := Limit{Count: 10, Period: time.Minute} // 6초마다 토큰 하나
+	start := newFakeClock().Now()
+
+	cases := []struct {
+		name          string
+		tokens        float64
+		elapsed       time.Duration
+		wantAllowed   bool
+		wantRemaining int
+		wantTokens    float64
+		wantRetry     time.Duration
+	}{
+		{name: "full bucket", tokens: 10, wantAllowed: true, wantRemaining: 9, wantTokens: 9},
+		{name: "last token", tokens: 1, wantAllowed: true, wantRemaining: 0, wantTokens: 0},
+		{name: "empty bucket", tokens: 0, wantTokens: 0, wantRetry: 6 * time.Second},
+		{name: "partial refill", tokens: 0, elapsed: 3 * time.Second, wantTokens: 0.5, wantRetry: 3 * time.Second},
+		{name: "refill to one", tokens: 0, elapsed: 6 * time.Second, wantAllowed: true, wantRemaining: 0, wantTokens: 0},
+		{name: "refill capped at burst", tokens: 2, elapsed: time.Hour, wantAllowed: true, wantRemaining: 9, wantTokens: 9},
+		{name: "clock moved backwards", tokens: 0.5, elapsed: -time.Minute, wantTokens: 0.5, wantRetry: 3 * time.Second},
+	}
+	for _, tc := range cases {
+		result, tokens := takeToken(tc.tokens, start, start.Add(tc.elapsed), limit)
+		if result.Allowed != tc.wantAllowed || result.Remaining != tc.wantRemaining {
+			t.Errorf("%s: result = %+v", tc.name, result)
+		}
+		if tokens < tc.wantTokens-1e-9 || tokens > tc.wantTokens+1e-9 {
+			t.Errorf("%s: tokens = %v, want %v", tc.name, tokens, tc.wantTokens)
+		}
+		if !approxDuration(result.RetryAfter, tc.wantRetry) {
+			t.Errorf("%s: retry after = %s, want %s", tc.name, result.RetryAfter, tc.wantRetry)
+		}
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	cases := map[time.Duration]int{
+		-time.Second:            1,
+		0:                       1,
+		300 * time.Millisecond:  1,
+		time.Second:             1,
+		1500 * time.Millisecond: 2,
+		59*time.Second + 1:      60,
+	}
+	for d, want := range cases {
+		if got := RetryAfterSeconds(d); got != want {
+			t.Errorf("RetryAfterSeconds(%s) = %d, want %d", d, got, want)
+		}
+	}
+}
+
+type failingStore struct {
+	MemoryStore
+}
+
+func (*failingStore) Take(context.Context, string, Limit, time.Time) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func TestLimiterAllow(t *testing.T) {
+	clock := newFakeClock()
+	limiter := NewLimiter(NewMemoryStore(), nil)
+	limiter.now = clock.Now
+	limit := Limit{Count: 2, Period: time.Second}
+
+	for i := 0; i < 2; i++ {
+		if !limiter.Allow(context.Background(), "ip:1", limit).Allowed {
+			t.Fatalf("request %d denied", i+1)
+		}
+	}
+	if result := limiter.Allow(context.Background(), "ip:1", limit); result.Allowed || !approxDuration(result.RetryAfter, 500*time.Millisecond) {
+		t.Fatalf("third request = %+v", result)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if !limiter.Allow(context.Background(), "ip:1", limit).Allowed {
+		t.Fatal("refilled token denied")
+	}
+
+	// 저장소 오류는 서비스를 막지 않음
+	if !NewLimiter(&failingStore{}, nil).Allow(context.Background(), "ip:1", limit).Allowed {
+		t.Fatal("store errors must fail open")
+	}
+}
@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// 라우트 그룹별 정책 이름
+const (
+	PolicyAuth         = "auth"         // 로그인, 회원가입, 비밀번호 재설정 등 인증 요청
+	PolicyOptimization = "optimization" // 집계자 배치 최적화 (요청마다 최적화 프로세스 실행)
+	PolicyAPI          = "api"          // 인증된 API 전체
+)
+
+// entryRetention은 갱신되지 않은 항목을 지우기까지의 기간입니다
+// 제한 단위는 최대 1시간이고 로그인 잠금 초기화 기간도 이보다 짧게 제한하므로 지워도 결과가 달라지지 않습니다
+const entryRetention = 24 * time.Hour
+
+// Policy는 라우트 그룹에 적용할 IP별/사용자별 제한입니다 (nil이면 적용하지 않음)
+type Policy struct {
+	PerIP   *Limit
+	PerUser *Limit
+}
+
+// Config는 요청 제한 설정입니다
+type Config struct {
+	Store    string
+	Policies map[string]Policy
+	Lockout  LockoutPolicy
+}
+
+// DefaultConfig는 기본 요청 제한 설정을 반환합니다
+func DefaultConfig() Config {
+	return Config{
+		Store: StoreMemory,
+		Policies: map[string]Policy{
+			PolicyAuth:         {PerIP: &Limit{Count: 20, Period: time.Minute}},
+			PolicyOptimization: {PerIP: &Limit{Count: 30, Period: time.Minute}, PerUser: &Limit{Count: 10, Period: time.Minute}},
+			PolicyAPI:          {PerUser: &Limit{Count: 600, Period: time.Minute}},
+		},
+		Lockout: DefaultLockoutPolicy(),
+	}
+}
+
+// ConfigFromEnv는 환경변수로 기본 설정을 덮어씁니다
+//
+//	RATE_LIMIT_STORE=memory|postgres
+//	RATE_LIMIT_AUTH=ip=20/m            (정책별, "off"면 해제)
+//	RATE_LIMIT_OPTIMIZATION=ip=30/m,user=10/m
+//	RATE_LIMIT_API=user=600/m
+//	LOGIN_LOCKOUT_THRESHOLD, LOGIN_LOCKOUT_BASE, LOGIN_LOCKOUT_MAX, LOGIN_LOCKOUT_RESET
+func ConfigFromEnv() (Config, error) {
+	config := DefaultConfig()
+	if v := os.Getenv("RATE_LIMIT_STORE"); v != "" {
+		config.Store = v
+	}
+
+	for name := range config.Policies {
+		key := "RATE_LIMIT_" + strings.ToUpper(name)
+		v := strings.TrimSpace(os.Getenv(key))
+		if v == "" {
+			continue
+		}
+		policy, err := ParsePolicy(v)
+		if err != nil {
+			return config, fmt.Errorf("%s: %v", key, err)
+		}
+		config.Policies[name] = policy
+	}
+
+	lockout, err := lockoutPolicyFromEnv(config.Lockout)
+	if err != nil {
+		return config, err
+	}
+	config.Lockout = lockout
+	return config, nil
+}
+
+// ParsePolicy는 "ip=20/m,user=10/m" 형식의 정책을 해석합니다 ("off"는 제한 없음)
+func ParsePolicy(s string) (Policy, error) {
+	var policy Policy
+	if s == "off" {
+		return policy, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		scope, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return policy, fmt.Errorf("정책 형식이 올바르지 않습니다 (예: ip=20/m,user=10/m): %q", s)
+		}
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return policy, err
+		}
+		switch scope {
+		case "ip":
+			policy.PerIP = &limit
+		case "user":
+			policy.PerUser = &limit
+		default:
+			return policy, fmt.Errorf("정책 대상은 ip 또는 user여야 합니다: %q", scope)
+		}
+	}
+	return policy, nil
+}
+
+// Limiter는 정책 이름별 제한을 저장소에 적용합니다
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+	now      func() time.Time // 테스트에서 시계를 바꿀 수 있도록 분리
+}
+
+// NewLimiter는 새 Limiter 인스턴스를 생성합니다
+func NewLimiter(store Store, policies map[string]Policy) *Limiter {
+	return &Limiter{store: store, policies: policies, now: time.Now}
+}
+
+// Policy는 이름에 해당하는 정책을 반환합니다
+func (l *Limiter) Policy(name string) (Policy, bool) {
+	policy, ok := l.policies[name]
+	return policy, ok
+}
+
+// Allow는 key의 토큰을 하나 사용합니다
+// 저장소 오류 시에는 서비스 전체가 막히지 않도록 허용하고 로그만 남깁니다
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) Result {
+	result, err := l.store.Take(ctx, key, limit, l.now())
+	if err != nil {
+		log.Printf("요청 제한 저장소 오류 (키: %s): %v", key, err)
+		return Result{Allowed: true}
+	}
+	return result
+}
+
+// StartCleanup은 오래된 항목을 주기적으로 삭제하는 고루틴을 시작합니다
+func (l *Limiter) StartCleanup(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.store.Cleanup(ctx, l.now().Add(-entryRetention)); err != nil {
+					log.Printf("요청 제한 항목 정리 실패: %v", err)
+				}
+			}
+		}
+	}()
+}
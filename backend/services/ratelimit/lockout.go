@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LockoutPolicy는 계정별 로그인 실패 잠금 정책입니다
+// Threshold번째 실패부터 BaseDelay 동안 잠그고, 이후 실패할 때마다 잠금 시간을 두 배로 늘립니다 (최대 MaxDelay)
+type LockoutPolicy struct {
+	Threshold  int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	ResetAfter time.Duration // 마지막 실패 후 이 기간이 지나면 실패 횟수 초기화
+}
+
+// DefaultLockoutPolicy는 기본 잠금 정책을 반환합니다 (5회 실패 시 30초, 최대 30분, 1시간 후 초기화)
+func DefaultLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		Threshold:  5,
+		BaseDelay:  30 * time.Second,
+		MaxDelay:   30 * time.Minute,
+		ResetAfter: time.Hour,
+	}
+}
+
+func lockoutPolicyFromEnv(policy LockoutPolicy) (LockoutPolicy, error) {
+	if v := os.Getenv("LOGIN_LOCKOUT_THRESHOLD"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return policy, fmt.Errorf("LOGIN_LOCKOUT_THRESHOLD 값이 올바르지 않습니다: %q", v)
+		}
+		policy.Threshold = n
+	}
+	for key, target := range map[string]*time.Duration{
+		"LOGIN_LOCKOUT_BASE":  &policy.BaseDelay,
+		"LOGIN_LOCKOUT_MAX":   &policy.MaxDelay,
+		"LOGIN_LOCKOUT_RESET": &policy.ResetAfter,
+	} {
+		if v := os.Getenv(key); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return policy, fmt.Errorf("%s 값이 올바르지 않습니다: %q", key, v)
+			}
+			*target = d
+		}
+	}
+	if policy.MaxDelay < policy.BaseDelay {
+		return policy, fmt.Errorf("LOGIN_LOCKOUT_MAX는 LOGIN_LOCKOUT_BASE보다 작을 수 없습니다")
+	}
+	if policy.ResetAfter > entryRetention || policy.MaxDelay > entryRetention {
+		return policy, fmt.Errorf("LOGIN_LOCKOUT_RESET과 LOGIN_LOCKOUT_MAX는 %s 이하여야 합니다", entryRetention)
+	}
+	return policy, nil
+}
+
+// delay는 누적 실패 횟수에 따른 잠금 시간입니다
+func (p LockoutPolicy) delay(failures int) time.Duration {
+	if failures < p.Threshold {
+		return 0
+	}
+	d := p.BaseDelay
+	for i := p.Threshold; i < failures && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// LoginGuard는 계정(이메일) 단위로 로그인 실패를 세고 점진적으로 잠급니다
+// 존재하지 않는 이메일도 같은 방식으로 세어 잠금 여부로 계정 존재를 알 수 없도록 합니다
+type LoginGuard struct {
+	store  Store
+	policy LockoutPolicy
+	now    func() time.Time // 테스트에서 시계를 바꿀 수 있도록 분리
+}
+
+// NewLoginGuard는 새 LoginGuard 인스턴스를 생성합니다
+func NewLoginGuard(store Store, policy LockoutPolicy) *LoginGuard {
+	return &LoginGuard{store: store, policy: policy, now: time.Now}
+}
+
+func loginKey(account string) string {
+	return "login:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+// Check는 계정이 잠겨 있으면 남은 잠금 시간을 반환합니다 (잠겨 있지 않으면 0)
+func (g *LoginGuard) Check(ctx context.Context, account string) time.Duration {
+	if g == nil {
+		return 0
+	}
+	failures, last, err := g.store.Failures(ctx, loginKey(account))
+	if err != nil {
+		log.Printf("로그인 잠금 상태 조회 실패: %v", err)
+		return 0
+	}
+	now := g.now()
+	if failures == 0 || now.Sub(last) > g.policy.ResetAfter {
+		return 0
+	}
+	if remaining := last.Add(g.policy.delay(failures)).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// RecordFailure는 로그인 실패를 기록하고, 이번 실패로 잠겼다면 잠금 시간을 반환합니다
+func (g *LoginGuard) RecordFailure(ctx context.Context, account string) time.Duration {
+	if g == nil {
+		return 0
+	}
+	failures, err := g.store.RecordFailure(ctx, loginKey(account), g.now(), g.policy.ResetAfter)
+	if err != nil {
+		log.Printf("로그인 실패 기록 실패: %v", err)
+		return 0
+	}
+	return g.policy.delay(failures)
+}
+
+// Reset은 로그인 성공 시 실패 횟수를 초기화합니다
+func (g *LoginGuard) Reset(ctx context.Context, account string) {
+	if g == nil {
+		return
+	}
+	if err := g.store.ClearFailures(ctx, loginKey(account)); err != nil {
+		log.Printf("로그인 실패 횟수 초기화 실패: %v", err)
+	}
+}
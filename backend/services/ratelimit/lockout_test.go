@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestLoginGuard() (*LoginGuard, *fakeClock) {
+	clock := newFakeClock()
+	guard := NewLoginGuard(NewMemoryStore(), LockoutPolicy{
+		Threshold:  3,
+		BaseDelay:  10 * time.Second,
+		MaxDelay:   40 * time.Second,
+		ResetAfter: time.Hour,
+	})
+	guard.now = clock.Now
+	return guard, clock
+}
+
+func TestLoginGuardProgressiveDelay(t *testing.T) {
+	ctx := context.Background()
+	guard, clock := newTestLoginGuard()
+
+	// 임계값 전까지는 잠그지 않고, 이후 실패마다 두 배 (최대 MaxDelay)
+	for i, want := range []time.Duration{0, 0, 10 * time.Second, 20 * time.Second, 40 * time.Second, 40 * time.Second} {
+		if got := guard.RecordFailure(ctx, "user@example.com"); got != want {
+			t.Fatalf("failure %d: delay = %s, want %s", i+1, got, want)
+		}
+		if got := guard.Check(ctx, "user@example.com"); got != want {
+			t.Fatalf("failure %d: check = %s, want %s", i+1, got, want)
+		}
+	}
+
+	clock.Advance(15 * time.Second)
+	if got := guard.Check(ctx, "user@example.com"); got != 25*time.Second {
+		t.Fatalf("check after 15s = %s, want 25s", got)
+	}
+	clock.Advance(25 * time.Second)
+	if got := guard.Check(ctx, "user@example.com"); got != 0 {
+		t.Fatalf("check after delay = %s, want unlocked", got)
+	}
+	// 잠금이 풀려도 실패 횟수는 유지되어 다음 실패는 바로 최대 잠금
+	if got := guard.RecordFailure(ctx, "user@example.com"); got != 40*time.Second {
+		t.Fatalf("failure after unlock: delay = %s", got)
+	}
+}
+
+func TestLoginGuardResets(t *testing.T) {
+	ctx := context.Background()
+	guard, clock := newTestLoginGuard()
+
+	for i := 0; i < 3; i++ {
+		guard.RecordFailure(ctx, "User@Example.com ")
+	}
+	// 대소문자와 공백이 달라도 같은 계정
+	if guard.Check(ctx, "user@example.com") == 0 {
+		t.Fatal("account key must be normalized")
+	}
+	if guard.Check(ctx, "other@example.com") != 0 {
+		t.Fatal("other accounts must not be locked")
+	}
+
+	// 마지막 실패 후 ResetAfter가 지나면 처음부터 다시 셈
+	clock.Advance(time.Hour + time.Second)
+	if got := guard.Check(ctx, "user@example.com"); got != 0 {
+		t.Fatalf("check after reset period = %s", got)
+	}
+	if got := guard.RecordFailure(ctx, "user@example.com"); got != 0 {
+		t.Fatalf("first failure after reset period: delay = %s", got)
+	}
+
+	// 로그인 성공 시 초기화
+	guard.RecordFailure(ctx, "user@example.com")
+	guard.Reset(ctx, "user@example.com")
+	if got := guard.RecordFailure(ctx, "user@example.com"); got != 0 {
+		t.Fatalf("failure after reset: delay = %s", got)
+	}
+
+	var disabled *LoginGuard
+	if disabled.RecordFailure(ctx, "user@example.com") != 0 || disabled.Check(ctx, "user@example.com") != 0 {
+		t.Fatal("nil guard must not lock")
+	}
+	disabled.Reset(ctx, "user@example.com")
+}
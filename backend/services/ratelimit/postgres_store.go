@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+// PostgresStore는 rate_limit_entries 테이블에 상태를 보관해 여러 서버 인스턴스가 제한을 공유하도록 합니다
+// 항목마다 행 잠금(SELECT ... FOR UPDATE)으로 동시 요청을 직렬화합니다
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore는 새 PostgresStore 인스턴스를 생성합니다
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	var result Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entry, err := lockEntry(tx, key, float64(limit.Count), now)
+		if err != nil {
+			return err
+		}
+
+		var tokens float64
+		result, tokens = takeToken(entry.Tokens, entry.TouchedAt, now, limit)
+		return tx.Model(&models.RateLimitEntry{}).Where("key = ?", key).
+			Updates(map[string]interface{}{"tokens": tokens, "touched_at": now}).Error
+	})
+	return result, err
+}
+
+func (s *PostgresStore) RecordFailure(ctx context.Context, key string, now time.Time, resetAfter time.Duration) (int, error) {
+	var count int
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entry, err := lockEntry(tx, key, 0, now)
+		if err != nil {
+			return err
+		}
+
+		count = entry.Count + 1
+		if now.Sub(entry.TouchedAt) > resetAfter {
+			count = 1
+		}
+		return tx.Model(&models.RateLimitEntry{}).Where("key = ?", key).
+			Updates(map[string]interface{}{"count": count, "touched_at": now}).Error
+	})
+	return count, err
+}
+
+func (s *PostgresStore) Failures(ctx context.Context, key string) (int, time.Time, error) {
+	var entry models.RateLimitEntry
+	err := s.db.WithContext(ctx).Where("key = ?", key).First(&entry).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return entry.Count, entry.TouchedAt, nil
+}
+
+func (s *PostgresStore) ClearFailures(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ?", key).Delete(&models.RateLimitEntry{}).Error
+}
+
+func (s *PostgresStore) Cleanup(ctx context.Context, before time.Time) error {
+	return s.db.WithContext(ctx).Where("touched_at < ?", before).Delete(&models.RateLimitEntry{}).Error
+}
+
+// lockEntry는 항목이 없으면 초기값으로 만든 뒤 행 잠금을 걸고 조회합니다
+func lockEntry(tx *gorm.DB, key string, initialTokens float64, now time.Time) (*models.RateLimitEntry, error) {
+	initial := models.RateLimitEntry{Key: key, Tokens: initialTokens, TouchedAt: now}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&initial).Error; err != nil {
+		return nil, err
+	}
+
+	var entry models.RateLimitEntry
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 저장소 종류 (RATE_LIMIT_STORE)
+const (
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+)
+
+// Store는 토큰 버킷과 실패 카운터를 보관하는 저장소 인터페이스입니다
+// 서버 한 대는 메모리 저장소로 충분하고, 여러 인스턴스가 같은 제한을 공유하려면 Postgres 저장소를 사용합니다
+type Store interface {
+	// Take는 key의 토큰 버킷에서 토큰 하나를 꺼냅니다
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+	// RecordFailure는 실패 횟수를 1 늘리고 누적 횟수를 반환합니다 (마지막 실패 후 resetAfter가 지났으면 1부터 다시 셈)
+	RecordFailure(ctx context.Context, key string, now time.Time, resetAfter time.Duration) (int, error)
+	// Failures는 누적 실패 횟수와 마지막 실패 시각을 반환합니다
+	Failures(ctx context.Context, key string) (int, time.Time, error)
+	// ClearFailures는 실패 카운터를 삭제합니다
+	ClearFailures(ctx context.Context, key string) error
+	// Cleanup은 before 이후 갱신되지 않은 항목을 삭제합니다
+	Cleanup(ctx context.Context, before time.Time) error
+}
+
+// NewStore는 종류에 맞는 저장소를 생성합니다 (빈 값은 memory)
+func NewStore(kind string, db *gorm.DB) (Store, error) {
+	switch kind {
+	case "", StoreMemory:
+		return NewMemoryStore(), nil
+	case StorePostgres:
+		if db == nil {
+			return nil, fmt.Errorf("postgres 요청 제한 저장소에는 DB 연결이 필요합니다")
+		}
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("지원하지 않는 RATE_LIMIT_STORE입니다: %s", kind)
+	}
+}
+
+type memoryEntry struct {
+	tokens  float64
+	count   int
+	touched time.Time
+}
+
+// MemoryStore는 프로세스 메모리에 상태를 보관하는 저장소입니다
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+// NewMemoryStore는 새 MemoryStore 인스턴스를 생성합니다
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]*memoryEntry{}}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryEntry{tokens: float64(limit.Count), touched: now}
+		s.entries[key] = entry
+	}
+	result, tokens := takeToken(entry.tokens, entry.touched, now, limit)
+	entry.tokens, entry.touched = tokens, now
+	return result, nil
+}
+
+func (s *MemoryStore) RecordFailure(ctx context.Context, key string, now time.Time, resetAfter time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || now.Sub(entry.touched) > resetAfter {
+		entry = &memoryEntry{}
+		s.entries[key] = entry
+	}
+	entry.count++
+	entry.touched = now
+	return entry.count, nil
+}
+
+func (s *MemoryStore) Failures(ctx context.Context, key string) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok {
+		return entry.count, entry.touched, nil
+	}
+	return 0, time.Time{}, nil
+}
+
+func (s *MemoryStore) ClearFailures(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryStore) Cleanup(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, entry := range s.entries {
+		if entry.touched.Before(before) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
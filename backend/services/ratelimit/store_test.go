@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+)
+
+// newTestPostgresStore는 PostgresStore를 테스트할 DB를 엽니다
+// RATE_LIMIT_TEST_POSTGRES_DSN이 있으면 실제 Postgres를, 없으면 메모리 SQLite를 사용합니다
+func newTestPostgresStore(t *testing.T) *PostgresStore {
+	t.Helper()
+	dialector := sqlite.Open("file:ratelimit_store?mode=memory&cache=shared")
+	if dsn := os.Getenv("RATE_LIMIT_TEST_POSTGRES_DSN"); dsn != "" {
+		dialector = postgres.Open(dsn)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if db.Dialector.Name() == "sqlite" {
+		sqlDB.SetMaxOpenConns(1)
+	}
+	if err := db.AutoMigrate(&models.RateLimitEntry{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Where("key LIKE ?", "test:%").Delete(&models.RateLimitEntry{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return NewPostgresStore(db)
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(*testing.T) Store{
+		StoreMemory:   func(*testing.T) Store { return NewMemoryStore() },
+		StorePostgres: func(t *testing.T) Store { return newTestPostgresStore(t) },
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Run("take", func(t *testing.T) { testStoreTake(t, newStore(t)) })
+			t.Run("failures", func(t *testing.T) { testStoreFailures(t, newStore(t)) })
+		})
+	}
+}
+
+func testStoreTake(t *testing.T, store Store) {
+	ctx := context.Background()
+	clock := newFakeClock()
+	limit := Limit{Count: 3, Period: time.Minute}
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "test:take", limit, clock.Now())
+		if err != nil || !result.Allowed || result.Remaining != i {
+			t.Fatalf("take: %+v, %v (want remaining %d)", result, err, i)
+		}
+	}
+	result, err := store.Take(ctx, "test:take", limit, clock.Now())
+	if err != nil || result.Allowed || !approxDuration(result.RetryAfter, 20*time.Second) {
+		t.Fatalf("empty bucket: %+v, %v", result, err)
+	}
+	// 다른 키는 별도의 버킷
+	if result, _ := store.Take(ctx, "test:other", limit, clock.Now()); !result.Allowed {
+		t.Fatal("other key shares the bucket")
+	}
+
+	clock.Advance(20 * time.Second)
+	if result, _ := store.Take(ctx, "test:take", limit, clock.Now()); !result.Allowed {
+		t.Fatalf("refilled token denied: %+v", result)
+	}
+	if result, _ := store.Take(ctx, "test:take", limit, clock.Now()); result.Allowed {
+		t.Fatalf("only one token should have been refilled: %+v", result)
+	}
+
+	// 오래된 항목만 정리
+	clock.Advance(time.Hour)
+	if _, err := store.Take(ctx, "test:fresh", limit, clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Cleanup(ctx, clock.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := store.Take(ctx, "test:take", limit, clock.Now()); result.Remaining != 2 {
+		t.Fatalf("cleaned entry should start with a full bucket: %+v", result)
+	}
+	if result, _ := store.Take(ctx, "test:fresh", limit, clock.Now()); result.Remaining != 1 {
+		t.Fatalf("fresh entry must survive cleanup: %+v", result)
+	}
+}
+
+func testStoreFailures(t *testing.T, store Store) {
+	ctx := context.Background()
+	clock := newFakeClock()
+
+	if count, last, err := store.Failures(ctx, "test:login"); err != nil || count != 0 || !last.IsZero() {
+		t.Fatalf("unknown key: %d, %s, %v", count, last, err)
+	}
+	for want := 1; want <= 3; want++ {
+		clock.Advance(time.Minute)
+		if count, err := store.RecordFailure(ctx, "test:login", clock.Now(), time.Hour); err != nil || count != want {
+			t.Fatalf("record: %d, %v (want %d)", count, err, want)
+		}
+	}
+	count, last, err := store.Failures(ctx, "test:login")
+	if err != nil || count != 3 || !last.Equal(clock.Now()) {
+		t.Fatalf("failures: %d, %s, %v", count, last, err)
+	}
+
+	// resetAfter가 지난 뒤의 실패는 1부터 다시 셈
+	clock.Advance(time.Hour + time.Second)
+	if count, _ := store.RecordFailure(ctx, "test:login", clock.Now(), time.Hour); count != 1 {
+		t.Fatalf("count after reset period = %d", count)
+	}
+
+	if err := store.ClearFailures(ctx, "test:login"); err != nil {
+		t.Fatal(err)
+	}
+	if count, _, _ := store.Failures(ctx, "test:login"); count != 0 {
+		t.Fatalf("count after clear = %d", count)
+	}
+}
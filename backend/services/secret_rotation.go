@@ -0,0 +1,195 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Mungge/Fleecy-Cloud/models"
+	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/utils/secrets"
+	"gorm.io/gorm"
+)
+
+// secretColumn은 봉투 암호화된 값을 저장하는 테이블 컬럼 정보입니다
+type secretColumn struct {
+	Table       string
+	IDColumn    string
+	ValueColumn string
+	KeyColumn   string
+	Binary      bool // bytea 컬럼 여부
+	// legacy는 키 ID가 비어있는(봉투 암호화 이전) 값을 평문으로 바꿉니다
+	legacy func(value string) (string, error)
+}
+
+// secretColumns는 재암호화 대상인 모든 비밀 값 컬럼입니다
+var secretColumns = []secretColumn{
+	{
+		Table:       "cloud_connections",
+		IDColumn:    "id",
+		ValueColumn: "credential_file",
+		KeyColumn:   "credential_key_id",
+		Binary:      true,
+		legacy:      func(value string) (string, error) { return value, nil }, // 이전에는 평문 저장
+	},
+	{
+		Table:       "participants",
+		IDColumn:    "id",
+		ValueColumn: "open_stack_application_credential_secret",
+		KeyColumn:   "secret_key_id",
+		legacy:      models.DecryptLegacySecret,
+	},
+	{
+		Table:       "ssh_keypairs",
+		IDColumn:    "id",
+		ValueColumn: "encrypted_private_key",
+		KeyColumn:   "key_id",
+		legacy:      utils.DecryptPrivateKey,
+	},
+	{
+		Table:       "participant_agent_credentials",
+		IDColumn:    "participant_id",
+		ValueColumn: "secret",
+		KeyColumn:   "key_id",
+		legacy:      func(value string) (string, error) { return value, nil }, // 항상 봉투 암호화로 저장
+	},
+}
+
+// SecretRotationResult는 테이블별 재암호화 결과입니다
+type SecretRotationResult struct {
+	Table     string `json:"table"`
+	Rewrapped int    `json:"rewrapped"` // 다른 마스터 키로 감싸져 있던 데이터 키를 다시 감싼 건수
+	Migrated  int    `json:"migrated"`  // 봉투 암호화 이전 값을 새로 암호화한 건수
+	Skipped   int    `json:"skipped"`   // 처리 중 다른 요청이 먼저 갱신한 건수
+	Failed    int    `json:"failed"`
+}
+
+// SecretRotationService는 저장된 모든 비밀 값을 현재 활성 마스터 키로 다시 감쌉니다
+//
+// 데이터 자체는 다시 암호화하지 않고 레코드별 데이터 키만 다시 감싸므로 빠르며,
+// 행 단위로 "키 ID가 그대로일 때만" 갱신하기 때문에 서버를 내리지 않고 실행할 수 있습니다
+// (실행 중인 서버는 교체 전후의 마스터 키를 모두 알고 있어야 합니다)
+type SecretRotationService struct {
+	db        *gorm.DB
+	envelope  *secrets.Envelope
+	batchSize int
+}
+
+// NewSecretRotationService는 새 SecretRotationService 인스턴스를 생성합니다
+func NewSecretRotationService(db *gorm.DB, envelope *secrets.Envelope, batchSize int) *SecretRotationService {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &SecretRotationService{db: db, envelope: envelope, batchSize: batchSize}
+}
+
+// Rotate는 모든 비밀 값 컬럼을 재암호화합니다. dryRun이면 대상 건수만 셉니다
+func (s *SecretRotationService) Rotate(dryRun bool) ([]SecretRotationResult, error) {
+	activeKeyID := s.envelope.Provider().ActiveKeyID()
+	log.Printf("비밀 값 재암호화 시작 (제공자: %s, 활성 키: %s, dry-run: %v)", s.envelope.Provider().Name(), activeKeyID, dryRun)
+
+	results := make([]SecretRotationResult, 0, len(secretColumns))
+	for _, column := range secretColumns {
+		result, err := s.rotateColumn(column, activeKeyID, dryRun)
+		if err != nil {
+			return results, fmt.Errorf("%s 재암호화 실패: %v", column.Table, err)
+		}
+		log.Printf("%s: 재래핑 %d, 신규 암호화 %d, 건너뜀 %d, 실패 %d", column.Table, result.Rewrapped, result.Migrated, result.Skipped, result.Failed)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *SecretRotationService) rotateColumn(column secretColumn, activeKeyID string, dryRun bool) (SecretRotationResult, error) {
+	result := SecretRotationResult{Table: column.Table}
+
+	// 실패한 행이 다음 배치에 다시 잡히지 않도록 ID 커서로 진행
+	var cursor interface{}
+	for {
+		query := s.db.Table(column.Table).
+			Select(fmt.Sprintf("%s AS id, %s AS value, %s AS key_id", column.IDColumn, column.ValueColumn, column.KeyColumn)).
+			Where(fmt.Sprintf("length(%s) > 0", column.ValueColumn)).
+			Where(fmt.Sprintf("COALESCE(%s, '') <> ?", column.KeyColumn), activeKeyID).
+			Order(column.IDColumn).
+			Limit(s.batchSize)
+		if cursor != nil {
+			query = query.Where(fmt.Sprintf("%s > ?", column.IDColumn), cursor)
+		}
+
+		var rows []map[string]interface{}
+		if err := query.Find(&rows).Error; err != nil {
+			return result, err
+		}
+		if len(rows) == 0 {
+			return result, nil
+		}
+
+		for _, row := range rows {
+			cursor = row["id"]
+			keyID, _ := row["key_id"].(string)
+			value := columnString(row["value"])
+
+			if dryRun {
+				if keyID == "" {
+					result.Migrated++
+				} else {
+					result.Rewrapped++
+				}
+				continue
+			}
+
+			sealed, newKeyID, err := s.rewrap(column, value, keyID)
+			if err != nil {
+				log.Printf("%s %v 재암호화 실패: %v", column.Table, row["id"], err)
+				result.Failed++
+				continue
+			}
+
+			var newValue interface{} = sealed
+			if column.Binary {
+				newValue = []byte(sealed)
+			}
+			// 훅을 거치지 않도록 Table로 직접 갱신하고, 그 사이 값이 바뀐 행은 건너뜀
+			update := s.db.Table(column.Table).
+				Where(fmt.Sprintf("%s = ? AND COALESCE(%s, '') = ?", column.IDColumn, column.KeyColumn), row["id"], keyID).
+				UpdateColumns(map[string]interface{}{
+					column.ValueColumn: newValue,
+					column.KeyColumn:   newKeyID,
+				})
+			if update.Error != nil {
+				return result, update.Error
+			}
+			switch {
+			case update.RowsAffected == 0:
+				result.Skipped++
+			case keyID == "":
+				result.Migrated++
+			default:
+				result.Rewrapped++
+			}
+		}
+	}
+}
+
+// rewrap은 값을 활성 마스터 키로 감싼 봉투 암호문으로 바꿉니다
+func (s *SecretRotationService) rewrap(column secretColumn, value, keyID string) (string, string, error) {
+	if keyID != "" {
+		return s.envelope.Rewrap(value, keyID)
+	}
+
+	plaintext, err := column.legacy(value)
+	if err != nil {
+		return "", "", err
+	}
+	return s.envelope.Seal([]byte(plaintext))
+}
+
+func columnString(value interface{}) string {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Mungge/Fleecy-Cloud/utils/secrets"
+)
+
+func newRotationEnvelope(t *testing.T, active string) *secrets.Envelope {
+	t.Helper()
+	key := func(b byte) string {
+		return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+	}
+	t.Setenv("SECRETS_MASTER_KEYS", "old:"+key('a')+",new:"+key('b'))
+	t.Setenv("SECRETS_ACTIVE_KEY_ID", active)
+	provider, err := secrets.NewEnvKeyProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secrets.NewEnvelope(provider)
+}
+
+func rotationColumn(t *testing.T, table string) secretColumn {
+	t.Helper()
+	for _, column := range secretColumns {
+		if column.Table == table {
+			return column
+		}
+	}
+	t.Fatalf("no secret column for %s", table)
+	return secretColumn{}
+}
+
+// sealLegacy는 봉투 암호화 도입 전 participants 방식(고정 키 AES-GCM)으로 암호화합니다
+func sealLegacy(t *testing.T, plaintext string) string {
+	t.Helper()
+	block, err := aes.NewCipher([]byte("32-byte-long-key-for-encryption!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), nil))
+}
+
+func TestRotationRewrapsLegacyRows(t *testing.T) {
+	envelope := newRotationEnvelope(t, "new")
+	rotator := NewSecretRotationService(nil, envelope, 0)
+
+	cases := []struct {
+		table string
+		value string
+		want  string
+	}{
+		{"cloud_connections", `{"type": "service_account"}`, `{"type": "service_account"}`},
+		{"participants", sealLegacy(t, "openstack-application-secret"), "openstack-application-secret"},
+		{"participants", "short-plain", "short-plain"},
+	}
+	for _, tc := range cases {
+		sealed, keyID, err := rotator.rewrap(rotationColumn(t, tc.table), tc.value, "")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.table, err)
+		}
+		if keyID != "new" || !secrets.IsEnvelope(sealed) {
+			t.Fatalf("%s: sealed = %q, key id = %q", tc.table, sealed, keyID)
+		}
+		opened, err := envelope.Open(sealed, keyID)
+		if err != nil || string(opened) != tc.want {
+			t.Fatalf("%s: opened = %q, %v", tc.table, opened, err)
+		}
+	}
+}
+
+func TestRotationRewrapsPreviousMasterKey(t *testing.T) {
+	sealed, _, err := newRotationEnvelope(t, "old").Seal([]byte("agent-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	envelope := newRotationEnvelope(t, "new")
+	rotator := NewSecretRotationService(nil, envelope, 0)
+	column := rotationColumn(t, "participant_agent_credentials")
+
+	rewrapped, keyID, err := rotator.rewrap(column, sealed, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != "new" {
+		t.Fatalf("key id = %q, want new", keyID)
+	}
+	if opened, err := envelope.Open(rewrapped, keyID); err != nil || string(opened) != "agent-secret" {
+		t.Fatalf("opened = %q, %v", opened, err)
+	}
+
+	// 손상된 레거시 값은 실패로 집계되도록 오류를 반환
+	if _, _, err := rotator.rewrap(rotationColumn(t, "participants"), "this-is-not-valid-base64-ciphertext!", ""); err == nil {
+		t.Fatal("malformed legacy value must fail")
+	}
+}
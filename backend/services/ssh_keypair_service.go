@@ -1,15 +1,27 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/repository"
 	"github.com/Mungge/Fleecy-Cloud/utils"
+	"github.com/Mungge/Fleecy-Cloud/utils/secrets"
+	"golang.org/x/crypto/ssh"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrKeypairNotFound는 집계자에 저장된 SSH 키페어가 없을 때 반환됩니다
+	ErrKeypairNotFound = errors.New("keypair not found for aggregator")
+	// ErrHostKeyNotVerified는 재고정할 호스트 키를 검증할 수단(지문 또는 키)이 없을 때 반환됩니다
+	ErrHostKeyNotVerified = errors.New("host key must be provided or verified with an expected fingerprint")
+)
+
 type SSHKeypairService struct {
 	repo *repository.SSHKeypairRepository
 }
@@ -20,8 +32,11 @@ func NewSSHKeypairService(repo *repository.SSHKeypairRepository) *SSHKeypairServ
 
 // SaveKeypair SSH 키페어를 암호화하여 저장
 func (s *SSHKeypairService) SaveKeypair(aggregatorID, keyName, cloudProvider, region, publicKey, privateKey string) (*models.SSHKeypairResponse, error) {
-	// Private Key 암호화
-	encryptedPrivateKey, err := utils.EncryptPrivateKey(privateKey)
+	// Private Key 봉투 암호화
+	if privateKey == "" {
+		return nil, fmt.Errorf("private key is empty")
+	}
+	encryptedPrivateKey, keyID, err := secrets.Seal([]byte(privateKey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt private key: %v", err)
 	}
@@ -39,22 +54,19 @@ func (s *SSHKeypairService) SaveKeypair(aggregatorID, keyName, cloudProvider, re
 		existingKeypair.Region = region
 		existingKeypair.PublicKey = publicKey
 		existingKeypair.EncryptedPrivateKey = encryptedPrivateKey
+		existingKeypair.KeyID = keyID
+		// 키페어를 다시 저장하는 것은 인스턴스를 새로 프로비저닝하는 경우이므로 고정된 호스트 키 해제
+		existingKeypair.HostKey = ""
+		existingKeypair.HostKeyFingerprint = ""
+		existingKeypair.HostKeyPinnedAt = nil
 
 		if err := s.repo.UpdateKeypair(existingKeypair); err != nil {
 			return nil, fmt.Errorf("failed to update keypair: %v", err)
 		}
+		CloseAggregatorConnection(aggregatorID)
 
 		log.Printf("Updated SSH keypair for aggregator: %s", aggregatorID)
-		return &models.SSHKeypairResponse{
-			ID:            existingKeypair.ID,
-			AggregatorID:  existingKeypair.AggregatorID,
-			KeyName:       existingKeypair.KeyName,
-			CloudProvider: existingKeypair.CloudProvider,
-			Region:        existingKeypair.Region,
-			PublicKey:     existingKeypair.PublicKey,
-			CreatedAt:     existingKeypair.CreatedAt,
-			UpdatedAt:     existingKeypair.UpdatedAt,
-		}, nil
+		return newSSHKeypairResponse(existingKeypair), nil
 	}
 
 	// 새 키페어 생성
@@ -65,6 +77,7 @@ func (s *SSHKeypairService) SaveKeypair(aggregatorID, keyName, cloudProvider, re
 		Region:              region,
 		PublicKey:           publicKey,
 		EncryptedPrivateKey: encryptedPrivateKey,
+		KeyID:               keyID,
 	}
 
 	if err := s.repo.CreateKeypair(newKeypair); err != nil {
@@ -72,16 +85,7 @@ func (s *SSHKeypairService) SaveKeypair(aggregatorID, keyName, cloudProvider, re
 	}
 
 	log.Printf("Created SSH keypair for aggregator: %s", aggregatorID)
-	return &models.SSHKeypairResponse{
-		ID:            newKeypair.ID,
-		AggregatorID:  newKeypair.AggregatorID,
-		KeyName:       newKeypair.KeyName,
-		CloudProvider: newKeypair.CloudProvider,
-		Region:        newKeypair.Region,
-		PublicKey:     newKeypair.PublicKey,
-		CreatedAt:     newKeypair.CreatedAt,
-		UpdatedAt:     newKeypair.UpdatedAt,
-	}, nil
+	return newSSHKeypairResponse(newKeypair), nil
 }
 
 // GetKeypairByAggregatorID 집계자 ID로 키페어 조회 (Private Key 제외)
@@ -89,21 +93,12 @@ func (s *SSHKeypairService) GetKeypairByAggregatorID(aggregatorID string) (*mode
 	keypair, err := s.repo.GetKeypairByAggregatorID(aggregatorID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("keypair not found for aggregator: %s", aggregatorID)
+			return nil, fmt.Errorf("%w: %s", ErrKeypairNotFound, aggregatorID)
 		}
 		return nil, fmt.Errorf("failed to get keypair: %v", err)
 	}
 
-	return &models.SSHKeypairResponse{
-		ID:            keypair.ID,
-		AggregatorID:  keypair.AggregatorID,
-		KeyName:       keypair.KeyName,
-		CloudProvider: keypair.CloudProvider,
-		Region:        keypair.Region,
-		PublicKey:     keypair.PublicKey,
-		CreatedAt:     keypair.CreatedAt,
-		UpdatedAt:     keypair.UpdatedAt,
-	}, nil
+	return newSSHKeypairResponse(keypair), nil
 }
 
 // GetKeypairWithPrivateKey Private Key를 포함한 키페어 조회 (다운로드용)
@@ -111,29 +106,20 @@ func (s *SSHKeypairService) GetKeypairWithPrivateKey(aggregatorID string) (*mode
 	keypair, err := s.repo.GetKeypairByAggregatorID(aggregatorID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("keypair not found for aggregator: %s", aggregatorID)
+			return nil, fmt.Errorf("%w: %s", ErrKeypairNotFound, aggregatorID)
 		}
 		return nil, fmt.Errorf("failed to get keypair: %v", err)
 	}
 
 	// Private Key 복호화
-	privateKey, err := utils.DecryptPrivateKey(keypair.EncryptedPrivateKey)
+	privateKey, err := decryptKeypairPrivateKey(keypair)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt private key: %v", err)
 	}
 
 	return &models.SSHKeypairWithPrivateKey{
-		SSHKeypairResponse: models.SSHKeypairResponse{
-			ID:            keypair.ID,
-			AggregatorID:  keypair.AggregatorID,
-			KeyName:       keypair.KeyName,
-			CloudProvider: keypair.CloudProvider,
-			Region:        keypair.Region,
-			PublicKey:     keypair.PublicKey,
-			CreatedAt:     keypair.CreatedAt,
-			UpdatedAt:     keypair.UpdatedAt,
-		},
-		PrivateKey: privateKey,
+		SSHKeypairResponse: *newSSHKeypairResponse(keypair),
+		PrivateKey:         privateKey,
 	}, nil
 }
 
@@ -143,34 +129,165 @@ func (s *SSHKeypairService) DeleteKeypairByAggregatorID(aggregatorID string) err
 	_, err := s.repo.GetKeypairByAggregatorID(aggregatorID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return fmt.Errorf("keypair not found for aggregator: %s", aggregatorID)
+			return fmt.Errorf("%w: %s", ErrKeypairNotFound, aggregatorID)
 		}
 		return fmt.Errorf("failed to check keypair: %v", err)
 	}
 
+	CloseAggregatorConnection(aggregatorID)
 	return s.repo.DeleteKeypairByAggregatorID(aggregatorID)
 }
 
-// ListKeypairsByUserID 사용자의 모든 키페어 목록 조회
-func (s *SSHKeypairService) ListKeypairsByUserID(userID int64) ([]*models.SSHKeypairResponse, error) {
-	keypairs, err := s.repo.ListKeypairsByUserID(userID)
+// ListKeypairsByOrganizationID 조직의 모든 키페어 목록 조회
+func (s *SSHKeypairService) ListKeypairsByOrganizationID(orgID int64) ([]*models.SSHKeypairResponse, error) {
+	keypairs, err := s.repo.ListKeypairsByOrganizationID(orgID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list keypairs: %v", err)
 	}
 
 	responses := make([]*models.SSHKeypairResponse, len(keypairs))
 	for i, kp := range keypairs {
-		responses[i] = &models.SSHKeypairResponse{
-			ID:            kp.ID,
-			AggregatorID:  kp.AggregatorID,
-			KeyName:       kp.KeyName,
-			CloudProvider: kp.CloudProvider,
-			Region:        kp.Region,
-			PublicKey:     kp.PublicKey,
-			CreatedAt:     kp.CreatedAt,
-			UpdatedAt:     kp.UpdatedAt,
-		}
+		responses[i] = newSSHKeypairResponse(kp)
 	}
 
 	return responses, nil
 }
+
+// decryptKeypairPrivateKey는 키 ID에 따라 봉투 암호문 또는 이전 방식 암호문을 복호화합니다
+func decryptKeypairPrivateKey(keypair *models.SSHKeypair) (string, error) {
+	if keypair.KeyID == "" {
+		return utils.DecryptPrivateKey(keypair.EncryptedPrivateKey)
+	}
+
+	privateKey, err := secrets.Open(keypair.EncryptedPrivateKey, keypair.KeyID)
+	if err != nil {
+		return "", err
+	}
+	return string(privateKey), nil
+}
+
+// newSSHKeypairResponse는 키페어 모델을 응답 형식으로 변환합니다 (Private Key 제외)
+func newSSHKeypairResponse(keypair *models.SSHKeypair) *models.SSHKeypairResponse {
+	return &models.SSHKeypairResponse{
+		ID:                 keypair.ID,
+		AggregatorID:       keypair.AggregatorID,
+		KeyName:            keypair.KeyName,
+		CloudProvider:      keypair.CloudProvider,
+		Region:             keypair.Region,
+		PublicKey:          keypair.PublicKey,
+		HostKey:            keypair.HostKey,
+		HostKeyFingerprint: keypair.HostKeyFingerprint,
+		HostKeyPinnedAt:    keypair.HostKeyPinnedAt,
+		CreatedAt:          keypair.CreatedAt,
+		UpdatedAt:          keypair.UpdatedAt,
+	}
+}
+
+// NewAggregatorSSHClient는 고정된 호스트 키로 서버를 검증하는 집계자 SSH 클라이언트를 생성합니다
+// 고정된 호스트 키가 없으면 첫 접속 시 받은 키를 고정합니다 (TOFU)
+func (s *SSHKeypairService) NewAggregatorSSHClient(keypair *models.SSHKeypairWithPrivateKey, host string) (*utils.SSHClient, error) {
+	callback, err := utils.PinnedHostKeyCallback(keypair.HostKey, func(key ssh.PublicKey) error {
+		return s.pinOnFirstUse(keypair.AggregatorID, host, key)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("집계자 %s의 고정된 호스트 키가 올바르지 않습니다: %v", keypair.AggregatorID, err)
+	}
+
+	client := utils.NewSSHClient(host, "22", "ubuntu", keypair.PrivateKey)
+	client.HostKeyCallback = callback
+	if keypair.HostKey != "" {
+		if pinned, err := utils.ParseHostKey(keypair.HostKey); err == nil {
+			client.HostKeyAlgorithms = utils.HostKeyAlgorithmsFor(pinned)
+		}
+	}
+	return client, nil
+}
+
+// pinOnFirstUse는 첫 접속에서 받은 호스트 키를 고정합니다
+// 다른 연결이 먼저 고정했다면 그 키와 일치하는지 확인합니다
+func (s *SSHKeypairService) pinOnFirstUse(aggregatorID, host string, key ssh.PublicKey) error {
+	hostKey := utils.FormatHostKey(key)
+	fingerprint := utils.HostKeyFingerprint(key)
+
+	pinned, err := s.repo.PinHostKeyIfUnset(aggregatorID, hostKey, fingerprint, time.Now())
+	if err != nil {
+		return fmt.Errorf("호스트 키 저장 실패: %v", err)
+	}
+	if pinned {
+		log.Printf("Pinned SSH host key for aggregator %s (%s): %s", aggregatorID, host, fingerprint)
+		return nil
+	}
+
+	keypair, err := s.repo.GetKeypairByAggregatorID(aggregatorID)
+	if err != nil {
+		return fmt.Errorf("호스트 키 확인 실패: %v", err)
+	}
+	if keypair.HostKey == hostKey {
+		return nil
+	}
+	return &utils.HostKeyMismatchError{Host: host, Expected: keypair.HostKeyFingerprint, Actual: fingerprint}
+}
+
+// CaptureHostKey는 프로비저닝 직후 sshd가 응답할 때까지 기다려 집계자의 호스트 키를 고정합니다
+func (s *SSHKeypairService) CaptureHostKey(ctx context.Context, aggregatorID, host string) (*models.SSHKeypairResponse, error) {
+	key, err := utils.WaitForHostKey(ctx, host, "22", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.setHostKey(aggregatorID, key)
+}
+
+// RepinHostKey는 인스턴스 재구성 후 호스트 키를 다시 고정합니다
+// hostKey가 비어있으면 서버에서 조회하며, 이때는 중간자 공격으로 받은 키를 고정하지 않도록
+// 콘솔 등 다른 경로로 확인한 expectedFingerprint와 일치해야 합니다
+func (s *SSHKeypairService) RepinHostKey(aggregatorID, host, hostKey, expectedFingerprint string) (*models.SSHKeypairResponse, string, error) {
+	if hostKey == "" && expectedFingerprint == "" {
+		return nil, "", ErrHostKeyNotVerified
+	}
+
+	keypair, err := s.repo.GetKeypairByAggregatorID(aggregatorID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, "", fmt.Errorf("%w: %s", ErrKeypairNotFound, aggregatorID)
+		}
+		return nil, "", fmt.Errorf("failed to get keypair: %v", err)
+	}
+
+	var key ssh.PublicKey
+	if hostKey != "" {
+		key, err = utils.ParseHostKey(hostKey)
+	} else {
+		key, err = utils.ScanHostKey(host, "22", 15*time.Second)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if expectedFingerprint != "" && expectedFingerprint != utils.HostKeyFingerprint(key) {
+		return nil, "", &utils.HostKeyMismatchError{Host: host, Expected: expectedFingerprint, Actual: utils.HostKeyFingerprint(key)}
+	}
+
+	previous := keypair.HostKeyFingerprint
+	response, err := s.setHostKey(aggregatorID, key)
+	if err != nil {
+		return nil, "", err
+	}
+	// 이전 키로 검증된 공유 연결은 닫고 다음 사용 시 새 키로 재연결
+	CloseAggregatorConnection(aggregatorID)
+	log.Printf("Re-pinned SSH host key for aggregator %s: %s -> %s", aggregatorID, previous, response.HostKeyFingerprint)
+	return response, previous, nil
+}
+
+func (s *SSHKeypairService) setHostKey(aggregatorID string, key ssh.PublicKey) (*models.SSHKeypairResponse, error) {
+	now := time.Now()
+	if err := s.repo.SetHostKey(aggregatorID, utils.FormatHostKey(key), utils.HostKeyFingerprint(key), &now); err != nil {
+		return nil, fmt.Errorf("호스트 키 저장 실패: %v", err)
+	}
+
+	keypair, err := s.repo.GetKeypairByAggregatorID(aggregatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keypair: %v", err)
+	}
+	return newSSHKeypairResponse(keypair), nil
+}
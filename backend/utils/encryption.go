@@ -3,14 +3,15 @@ package utils
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
-	"io"
 	"os"
 )
 
+// 새 Private Key는 utils/secrets 봉투 암호화로 저장합니다
+// 아래 함수들은 봉투 암호화 도입 전(KeyID가 비어있는) 키페어를 읽는 용도로만 남겨둡니다
+
 // getEncryptionKey 환경변수에서 암호화 키를 가져오거나 기본값 사용
 func getEncryptionKey() []byte {
 	key := os.Getenv("SSH_ENCRYPTION_KEY")
@@ -24,41 +25,7 @@ func getEncryptionKey() []byte {
 	return hash[:]
 }
 
-// EncryptPrivateKey Private Key를 AES-256-GCM으로 암호화
-func EncryptPrivateKey(privateKey string) (string, error) {
-	if privateKey == "" {
-		return "", fmt.Errorf("private key is empty")
-	}
-
-	// 암호화 키 가져오기
-	key := getEncryptionKey()
-
-	// AES cipher 생성
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %v", err)
-	}
-
-	// GCM 모드 사용
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return "", fmt.Errorf("failed to create GCM: %v", err)
-	}
-
-	// 랜덤 nonce 생성
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", fmt.Errorf("failed to generate nonce: %v", err)
-	}
-
-	// 암호화
-	ciphertext := gcm.Seal(nonce, nonce, []byte(privateKey), nil)
-
-	// 문자열로 반환
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
-}
-
-// DecryptPrivateKey 암호화된 Private Key를 복호화
+// DecryptPrivateKey 봉투 암호화 도입 전 방식으로 암호화된 Private Key를 복호화
 func DecryptPrivateKey(encryptedPrivateKey string) (string, error) {
 	if encryptedPrivateKey == "" {
 		return "", fmt.Errorf("encrypted private key is empty")
@@ -1,7 +1,8 @@
 package utils
 
 import (
-	"encoding/json"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strconv"
@@ -9,7 +10,6 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 type TokenPair struct {
@@ -19,11 +19,6 @@ type TokenPair struct {
 	ExpiresIn int64 `json:"expires_in"`
 }
 
-func GetJWTSecret() []byte {
-	secret := os.Getenv("JWT_SECRET_KEY")
-	return []byte(secret)
-}
-
 // GetAccessTokenExpiration 액세스 토큰 만료 시간 반환 (기본 2시간)
 func GetAccessTokenExpiration() time.Duration {
 	hours := os.Getenv("ACCESS_TOKEN_EXPIRATION_HOURS")
@@ -49,23 +44,15 @@ func GetRefreshTokenExpiration() time.Duration {
 	return 7 * 24 * time.Hour
 }
 
-// GenerateAccessToken 액세스 토큰 생성
+// GenerateAccessToken 액세스 토큰 생성 (활성 키로 서명)
 func GenerateAccessToken(userID int64, email, name string) (string, error) {
-	expirationTime := time.Now().Add(GetAccessTokenExpiration())
-	
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"email":   email,
-		"name":    name,
-		"type":    "access",
-		"exp":     expirationTime.Unix(),
-		"iat":     time.Now().Unix(),
-	})
-	
-	return token.SignedString(GetJWTSecret())
+	service, err := getDefaultTokenService()
+	if err != nil {
+		return "", err
+	}
+	return service.IssueAccessToken(userID, email, name, GetAccessTokenExpiration())
 }
 
-
 // GenerateTokenPair 액세스 토큰과 리프레시 토큰 쌍 생성
 func GenerateTokenPair(userID int64, email, name string) (*TokenPair, string, error) {
 	// 액세스 토큰 생성
@@ -73,102 +60,64 @@ func GenerateTokenPair(userID int64, email, name string) (*TokenPair, string, er
 	if err != nil {
 		return nil, "", fmt.Errorf("액세스 토큰 생성 실패: %v", err)
 	}
-	
+
 	// 리프레시 토큰 생성
-	refreshToken := generateRandomToken()
-	
+	refreshToken, err := generateRandomToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("리프레시 토큰 생성 실패: %v", err)
+	}
+
 	tokenPair := &TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		TokenType:    "Bearer",
 		ExpiresIn:    int64(GetAccessTokenExpiration().Seconds()),
 	}
-	
+
 	return tokenPair, refreshToken, nil
 }
 
-// generateRandomToken 랜덤 토큰 생성
-func generateRandomToken() string {
-	// 간단한 랜덤 토큰 생성 (실제로는 crypto/rand 사용 권장)
-	return fmt.Sprintf("%d_%d", time.Now().UnixNano(), time.Now().Unix())
+// generateRandomToken 랜덤 토큰 생성 (crypto/rand 32바이트)
+func generateRandomToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
 }
 
-// ValidateAccessToken 액세스 토큰 검증
-func ValidateAccessToken(tokenString string) (*jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("예상치 못한 서명 방법: %v", token.Header["alg"])
-		}
-		return GetJWTSecret(), nil
-	})
-	
+// ValidateAccessToken 액세스 토큰 검증 (서명 알고리즘, kid, type, iss, aud, exp 확인)
+// 액세스 토큰을 검증하는 유일한 경로이며 AuthMiddleware도 이 함수를 사용합니다
+func ValidateAccessToken(tokenString string) (*AccessClaims, error) {
+	service, err := getDefaultTokenService()
 	if err != nil {
-		return nil, fmt.Errorf("토큰 파싱 실패: %v", err)
-	}
-	
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		// 토큰 타입 확인
-		if tokenType, exists := claims["type"]; !exists || tokenType != "access" {
-			return nil, fmt.Errorf("잘못된 토큰 타입")
-		}
-		return &claims, nil
+		return nil, err
 	}
-	
-	return nil, fmt.Errorf("유효하지 않은 토큰")
+	return service.VerifyAccessToken(tokenString)
 }
 
 // JWT 토큰에서 사용자 ID를 추출하는 함수
 func GetUserIDFromContext(c *gin.Context) (int64, error) {
-	// Authorization 헤더 확인
+	// 1. Authorization 헤더 확인
 	authHeader := c.GetHeader("Authorization")
 	if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// 토큰 서명 검증 (GetJWTSecret() 사용)
-			return GetJWTSecret(), nil
-		})
-		
+		claims, err := ValidateAccessToken(tokenString)
 		if err != nil {
-			return 0, fmt.Errorf("토큰 파싱 실패: %v", err)
-		}
-		
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			if userIDFloat, ok := claims["user_id"]; ok {
-				switch v := userIDFloat.(type) {
-				case float64:
-					return int64(v), nil
-				case json.Number:
-					userID, _ := v.Int64()
-					return userID, nil
-				}
-			}
+			return 0, fmt.Errorf("토큰 검증 실패: %v", err)
 		}
+		return claims.UserID, nil
 	}
-	
+
 	// 2. 쿠키에서 토큰 확인
-	tokenCookie, err := c.Cookie("token")
-	if err == nil {
-		token, err := jwt.Parse(tokenCookie, func(token *jwt.Token) (interface{}, error) {
-			return GetJWTSecret(), nil
-		})
-		
-		if err == nil && token.Valid {
-			if claims, ok := token.Claims.(jwt.MapClaims); ok {
-				if userIDFloat, ok := claims["user_id"]; ok {
-					switch v := userIDFloat.(type) {
-					case float64:
-						return int64(v), nil
-					case json.Number:
-						userID, _ := v.Int64()
-						return userID, nil
-					}
-				}
-			}
+	if tokenCookie, err := c.Cookie("token"); err == nil {
+		if claims, err := ValidateAccessToken(tokenCookie); err == nil {
+			return claims.UserID, nil
 		}
 	}
-	
+
 	return 0, fmt.Errorf("인증되지 않은 사용자")
-} 
+}
 
 func GetUserIDFromMiddleware(c *gin.Context) int64 {
     userIDInterface, exists := c.Get("userID")
@@ -0,0 +1,276 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 지원하는 JWT 서명 알고리즘
+const (
+	JWTAlgHS256 = "HS256"
+	JWTAlgRS256 = "RS256"
+	JWTAlgEdDSA = "EdDSA"
+)
+
+// JWTKey는 kid로 식별되는 서명/검증 키입니다
+// signKey가 없으면 검증 전용 키(교체 이전 키)입니다
+type JWTKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewHMACKey는 HS256 대칭 키를 생성합니다
+func NewHMACKey(kid string, secret []byte) (*JWTKey, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("HMAC 키(%s)가 비어 있습니다", kid)
+	}
+	return &JWTKey{ID: kid, Method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}, nil
+}
+
+// NewSigningKey는 RSA/Ed25519 개인 키로 서명 키를 생성합니다
+func NewSigningKey(kid string, privateKey crypto.Signer) (*JWTKey, error) {
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return &JWTKey{ID: kid, Method: jwt.SigningMethodRS256, signKey: k, verifyKey: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &JWTKey{ID: kid, Method: jwt.SigningMethodEdDSA, signKey: k, verifyKey: k.Public()}, nil
+	}
+	return nil, fmt.Errorf("지원하지 않는 개인 키 형식입니다: %T", privateKey)
+}
+
+// NewVerificationKey는 RSA/Ed25519 공개 키로 검증 전용 키를 생성합니다
+func NewVerificationKey(kid string, publicKey crypto.PublicKey) (*JWTKey, error) {
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		return &JWTKey{ID: kid, Method: jwt.SigningMethodRS256, verifyKey: k}, nil
+	case ed25519.PublicKey:
+		return &JWTKey{ID: kid, Method: jwt.SigningMethodEdDSA, verifyKey: k}, nil
+	}
+	return nil, fmt.Errorf("지원하지 않는 공개 키 형식입니다: %T", publicKey)
+}
+
+// CanSign은 서명에 사용할 수 있는 키인지 확인합니다
+func (k *JWTKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// JWTKeySet은 현재 서명 키와 검증에 허용되는 키 목록입니다
+// 키 교체 시 새 키를 활성 키로 두고 이전 키를 검증 전용으로 남겨두면
+// 이미 발급된 토큰이 만료될 때까지 계속 검증됩니다
+type JWTKeySet struct {
+	active *JWTKey
+	keys   map[string]*JWTKey
+}
+
+// NewJWTKeySet은 활성 서명 키와 검증 전용 키들로 키셋을 생성합니다
+func NewJWTKeySet(active *JWTKey, verifyOnly ...*JWTKey) (*JWTKeySet, error) {
+	if active == nil || !active.CanSign() {
+		return nil, fmt.Errorf("활성 키는 서명 가능한 키여야 합니다")
+	}
+	ks := &JWTKeySet{active: active, keys: map[string]*JWTKey{active.ID: active}}
+	for _, k := range verifyOnly {
+		if _, exists := ks.keys[k.ID]; exists {
+			return nil, fmt.Errorf("중복된 kid입니다: %s", k.ID)
+		}
+		ks.keys[k.ID] = k
+	}
+	return ks, nil
+}
+
+// Active는 현재 서명 키를 반환합니다
+func (ks *JWTKeySet) Active() *JWTKey {
+	return ks.active
+}
+
+// Key는 kid로 키를 조회합니다
+func (ks *JWTKeySet) Key(kid string) (*JWTKey, bool) {
+	k, ok := ks.keys[kid]
+	return k, ok
+}
+
+// Algorithms는 키셋에 포함된 서명 알고리즘 목록입니다 (검증 시 허용 알고리즘)
+func (ks *JWTKeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	var algs []string
+	for _, k := range ks.keys {
+		if alg := k.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// PublicJWKS는 비대칭 키의 공개 키를 JWKS 형식으로 반환합니다 (HMAC 키는 제외)
+func (ks *JWTKeySet) PublicJWKS() map[string]interface{} {
+	kids := make([]string, 0, len(ks.keys))
+	for kid := range ks.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := make([]map[string]string, 0, len(kids))
+	for _, kid := range kids {
+		k := ks.keys[kid]
+		switch pub := k.verifyKey.(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{
+				"kty": "RSA", "use": "sig", "alg": JWTAlgRS256, "kid": kid,
+				"n": base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			keys = append(keys, map[string]string{
+				"kty": "OKP", "use": "sig", "alg": JWTAlgEdDSA, "kid": kid, "crv": "Ed25519",
+				"x": base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return map[string]interface{}{"keys": keys}
+}
+
+// keyfunc는 토큰 헤더의 kid로 검증 키를 찾고 알고리즘이 키와 일치하는지 확인합니다
+func (ks *JWTKeySet) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrTokenMissingKeyID
+	}
+	k, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTokenUnknownKeyID, kid)
+	}
+	if token.Method.Alg() != k.Method.Alg() {
+		return nil, fmt.Errorf("%w: 키 %s는 %s 전용입니다 (토큰: %s)", ErrTokenAlgorithmMismatch, kid, k.Method.Alg(), token.Method.Alg())
+	}
+	return k.verifyKey, nil
+}
+
+// LoadJWTKeySetFromEnv는 환경 변수로 키셋을 구성합니다
+//
+//	JWT_SIGNING_ALG            HS256(기본) | RS256 | EdDSA
+//	JWT_KEY_ID                 활성 키의 kid (기본: default)
+//	JWT_SECRET_KEY             HS256 비밀 키
+//	JWT_PRIVATE_KEY_FILE       RS256/EdDSA 개인 키 PEM 파일 (PKCS#8, RSA는 PKCS#1도 허용)
+//	JWT_PREVIOUS_KEYS          교체 이전 검증 전용 키 목록 "kid=값,kid=값"
+//	                           HS256이면 비밀 키, 그 외에는 공개 키 PEM 파일 경로
+func LoadJWTKeySetFromEnv() (*JWTKeySet, error) {
+	alg := strings.TrimSpace(os.Getenv("JWT_SIGNING_ALG"))
+	if alg == "" {
+		alg = JWTAlgHS256
+	}
+	kid := strings.TrimSpace(os.Getenv("JWT_KEY_ID"))
+	if kid == "" {
+		kid = "default"
+	}
+
+	var active *JWTKey
+	var err error
+	switch alg {
+	case JWTAlgHS256:
+		active, err = NewHMACKey(kid, []byte(os.Getenv("JWT_SECRET_KEY")))
+		if err != nil {
+			return nil, fmt.Errorf("JWT_SECRET_KEY가 설정되지 않았습니다")
+		}
+	case JWTAlgRS256, JWTAlgEdDSA:
+		path := os.Getenv("JWT_PRIVATE_KEY_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("%s 서명에는 JWT_PRIVATE_KEY_FILE이 필요합니다", alg)
+		}
+		signer, err := readPrivateKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if active, err = NewSigningKey(kid, signer); err != nil {
+			return nil, err
+		}
+		if active.Method.Alg() != alg {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE 키 형식(%s)이 JWT_SIGNING_ALG(%s)와 다릅니다", active.Method.Alg(), alg)
+		}
+	default:
+		return nil, fmt.Errorf("지원하지 않는 JWT_SIGNING_ALG입니다: %s", alg)
+	}
+
+	var previous []*JWTKey
+	for _, entry := range strings.Split(os.Getenv("JWT_PREVIOUS_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prevKID, value, ok := strings.Cut(entry, "=")
+		if !ok || prevKID == "" || value == "" {
+			return nil, fmt.Errorf("JWT_PREVIOUS_KEYS 항목 형식이 올바르지 않습니다 (kid=값): %q", entry)
+		}
+		var k *JWTKey
+		if alg == JWTAlgHS256 {
+			k, err = NewHMACKey(prevKID, []byte(value))
+		} else {
+			var pub crypto.PublicKey
+			if pub, err = readPublicKeyFile(value); err == nil {
+				k, err = NewVerificationKey(prevKID, pub)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("이전 키(%s) 로드 실패: %v", prevKID, err)
+		}
+		previous = append(previous, k)
+	}
+
+	return NewJWTKeySet(active, previous...)
+}
+
+func readPrivateKeyFile(path string) (crypto.Signer, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("서명할 수 없는 개인 키 형식입니다: %s", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("개인 키를 해석할 수 없습니다: %s", path)
+}
+
+func readPublicKeyFile(path string) (crypto.PublicKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("공개 키를 해석할 수 없습니다: %s", path)
+}
+
+func readPEMFile(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("키 파일 읽기 실패: %v", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("PEM 형식이 아닙니다: %s", path)
+	}
+	return block, nil
+}
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 토큰 검증 실패 사유 (jwt 패키지 에러와 함께 errors.Is로 구분 가능)
+var (
+	ErrTokenMissingKeyID      = errors.New("토큰 헤더에 kid가 없습니다")
+	ErrTokenUnknownKeyID      = errors.New("알 수 없는 kid입니다")
+	ErrTokenAlgorithmMismatch = errors.New("토큰 서명 알고리즘이 키와 일치하지 않습니다")
+	ErrTokenInvalidType       = errors.New("잘못된 토큰 타입")
+	ErrTokenMissingUserID     = errors.New("토큰에 사용자 ID가 없습니다")
+)
+
+const (
+	accessTokenType     = "access"
+	defaultJWTIssuer    = "fleecy-cloud"
+	defaultJWTAudience  = "fleecy-cloud-api"
+	defaultJWTClockSkew = 30 * time.Second
+)
+
+// AccessClaims는 액세스 토큰의 클레임입니다
+type AccessClaims struct {
+	UserID int64  `json:"user_id"`
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	jwt.RegisteredClaims
+}
+
+// TokenService는 키셋으로 액세스 토큰을 발급하고 검증합니다
+type TokenService struct {
+	keys     *JWTKeySet
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// NewTokenService는 새 TokenService 인스턴스를 생성합니다
+func NewTokenService(keys *JWTKeySet, issuer, audience string) *TokenService {
+	return &TokenService{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		leeway:   defaultJWTClockSkew,
+		now:      time.Now,
+	}
+}
+
+// Keys는 서비스가 사용하는 키셋을 반환합니다
+func (s *TokenService) Keys() *JWTKeySet {
+	return s.keys
+}
+
+// IssueAccessToken은 활성 키로 서명한 액세스 토큰을 발급합니다 (헤더에 kid 포함)
+func (s *TokenService) IssueAccessToken(userID int64, email, name string, ttl time.Duration) (string, error) {
+	jti, err := generateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now()
+	claims := AccessClaims{
+		UserID: userID,
+		Email:  email,
+		Name:   name,
+		Type:   accessTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   strconv.FormatInt(userID, 10),
+			Audience:  jwt.ClaimStrings{s.audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        jti,
+		},
+	}
+
+	active := s.keys.Active()
+	token := jwt.NewWithClaims(active.Method, claims)
+	token.Header["kid"] = active.ID
+	return token.SignedString(active.signKey)
+}
+
+// VerifyAccessToken은 액세스 토큰의 서명과 클레임을 검증합니다
+// 허용 알고리즘은 키셋에 있는 키의 알고리즘으로 제한되고, kid의 키와 알고리즘이 일치해야 합니다
+func (s *TokenService) VerifyAccessToken(tokenString string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, s.keys.keyfunc,
+		jwt.WithValidMethods(s.keys.Algorithms()),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(s.leeway),
+		jwt.WithTimeFunc(s.now),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Type != accessTokenType {
+		return nil, ErrTokenInvalidType
+	}
+	if claims.UserID <= 0 {
+		return nil, ErrTokenMissingUserID
+	}
+	return claims, nil
+}
+
+var (
+	defaultTokenServiceMu sync.Mutex
+	defaultTokenService   *TokenService
+)
+
+// InitJWTFromEnv는 환경 변수로 기본 TokenService를 구성합니다 (서버 시작 시 호출)
+func InitJWTFromEnv() (*TokenService, error) {
+	keys, err := LoadJWTKeySetFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	service := NewTokenService(keys, GetJWTIssuer(), GetJWTAudience())
+	SetDefaultTokenService(service)
+	return service, nil
+}
+
+// SetDefaultTokenService는 GenerateAccessToken/ValidateAccessToken이 사용할 서비스를 지정합니다
+func SetDefaultTokenService(service *TokenService) {
+	defaultTokenServiceMu.Lock()
+	defer defaultTokenServiceMu.Unlock()
+	defaultTokenService = service
+}
+
+func getDefaultTokenService() (*TokenService, error) {
+	defaultTokenServiceMu.Lock()
+	service := defaultTokenService
+	defaultTokenServiceMu.Unlock()
+	if service != nil {
+		return service, nil
+	}
+
+	service, err := InitJWTFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("JWT 키 설정 오류: %v", err)
+	}
+	return service, nil
+}
+
+// GetJWTIssuer 토큰 발급자(iss) 반환
+func GetJWTIssuer() string {
+	if v := os.Getenv("JWT_ISSUER"); v != "" {
+		return v
+	}
+	return defaultJWTIssuer
+}
+
+// GetJWTAudience 토큰 대상(aud) 반환
+func GetJWTAudience() string {
+	if v := os.Getenv("JWT_AUDIENCE"); v != "" {
+		return v
+	}
+	return defaultJWTAudience
+}
+
+// JWKS는 기본 키셋의 공개 키 목록을 JWKS 형식으로 반환합니다
+func JWKS() (map[string]interface{}, error) {
+	service, err := getDefaultTokenService()
+	if err != nil {
+		return nil, err
+	}
+	return service.Keys().PublicJWKS(), nil
+}
@@ -0,0 +1,382 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testNow = time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+func newHMACService(t *testing.T, kid, secret string, previous ...*JWTKey) *TokenService {
+	t.Helper()
+	active, err := NewHMACKey(kid, []byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newService(t, active, previous...)
+}
+
+func newService(t *testing.T, active *JWTKey, previous ...*JWTKey) *TokenService {
+	t.Helper()
+	keys, err := NewJWTKeySet(active, previous...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewTokenService(keys, "test-issuer", "test-audience")
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func newRSAKey(t *testing.T, kid string) *JWTKey {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := NewSigningKey(kid, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func newEd25519Key(t *testing.T, kid string) *JWTKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := NewSigningKey(kid, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// validClaims는 test-issuer/test-audience 서비스에서 통과하는 액세스 토큰 클레임입니다
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"user_id": 42,
+		"email":   "user@example.com",
+		"name":    "tester",
+		"type":    "access",
+		"iss":     "test-issuer",
+		"aud":     "test-audience",
+		"iat":     testNow.Add(-time.Minute).Unix(),
+		"exp":     testNow.Add(time.Hour).Unix(),
+	}
+}
+
+// sign은 키와 kid로 임의의 클레임을 서명합니다 (kid가 빈 문자열이면 헤더에서 생략)
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestIssueAndVerifyAccessToken(t *testing.T) {
+	for name, active := range map[string]*JWTKey{
+		"HS256": mustHMACKey(t, "hs", "secret"),
+		"RS256": newRSAKey(t, "rs"),
+		"EdDSA": newEd25519Key(t, "ed"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := newService(t, active)
+			token, err := s.IssueAccessToken(42, "user@example.com", "tester", time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, err := s.VerifyAccessToken(token)
+			if err != nil {
+				t.Fatalf("expected token to verify: %v", err)
+			}
+			if claims.UserID != 42 || claims.Email != "user@example.com" || claims.Subject != "42" {
+				t.Fatalf("unexpected claims: %+v", claims)
+			}
+		})
+	}
+}
+
+func mustHMACKey(t *testing.T, kid, secret string) *JWTKey {
+	t.Helper()
+	k, err := NewHMACKey(kid, []byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestVerifyAccessTokenRejections(t *testing.T) {
+	s := newHMACService(t, "k1", "secret")
+	key := []byte("secret")
+
+	with := func(mutate func(jwt.MapClaims)) jwt.MapClaims {
+		c := validClaims()
+		mutate(c)
+		return c
+	}
+
+	cases := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{
+			name:  "expired",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { c["exp"] = testNow.Add(-time.Hour).Unix() })),
+			want:  jwt.ErrTokenExpired,
+		},
+		{
+			name:  "missing exp",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { delete(c, "exp") })),
+			want:  jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:  "not yet valid",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { c["nbf"] = testNow.Add(time.Hour).Unix() })),
+			want:  jwt.ErrTokenNotValidYet,
+		},
+		{
+			name:  "issued in the future",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { c["iat"] = testNow.Add(time.Hour).Unix() })),
+			want:  jwt.ErrTokenUsedBeforeIssued,
+		},
+		{
+			name:  "wrong issuer",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { c["iss"] = "someone-else" })),
+			want:  jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name:  "missing issuer",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { delete(c, "iss") })),
+			want:  jwt.ErrTokenRequiredClaimMissing,
+		},
+		{
+			name:  "wrong audience",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { c["aud"] = "other-api" })),
+			want:  jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:  "refresh type",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { c["type"] = "refresh" })),
+			want:  ErrTokenInvalidType,
+		},
+		{
+			name:  "missing type",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { delete(c, "type") })),
+			want:  ErrTokenInvalidType,
+		},
+		{
+			name:  "missing user id",
+			token: sign(t, jwt.SigningMethodHS256, "k1", key, with(func(c jwt.MapClaims) { delete(c, "user_id") })),
+			want:  ErrTokenMissingUserID,
+		},
+		{
+			name:  "missing kid",
+			token: sign(t, jwt.SigningMethodHS256, "", key, validClaims()),
+			want:  ErrTokenMissingKeyID,
+		},
+		{
+			name:  "unknown kid",
+			token: sign(t, jwt.SigningMethodHS256, "k2", key, validClaims()),
+			want:  ErrTokenUnknownKeyID,
+		},
+		{
+			name:  "wrong secret",
+			token: sign(t, jwt.SigningMethodHS256, "k1", []byte("forged"), validClaims()),
+			want:  jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:  "alg none",
+			token: sign(t, jwt.SigningMethodNone, "k1", jwt.UnsafeAllowNoneSignatureType, validClaims()),
+			want:  jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:  "alg not in keyset",
+			token: sign(t, jwt.SigningMethodHS512, "k1", key, validClaims()),
+			want:  jwt.ErrTokenSignatureInvalid,
+		},
+		{
+			name:  "malformed",
+			token: "not-a-jwt",
+			want:  jwt.ErrTokenMalformed,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := s.VerifyAccessToken(tc.token)
+			if err == nil {
+				t.Fatalf("expected rejection, got claims %+v", claims)
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestVerifyAccessTokenRejectsTamperedPayload(t *testing.T) {
+	s := newService(t, newRSAKey(t, "rs"))
+	token, err := s.IssueAccessToken(42, "user@example.com", "tester", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 다른 사용자 ID로 서명한 토큰의 payload를 붙여 넣기
+	other, err := s.IssueAccessToken(1, "admin@example.com", "admin", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts, otherParts := strings.Split(token, "."), strings.Split(other, ".")
+	forged := parts[0] + "." + otherParts[1] + "." + parts[2]
+
+	if _, err := s.VerifyAccessToken(forged); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Fatalf("expected signature error, got %v", err)
+	}
+}
+
+// HS256 토큰에 RSA 공개 키를 비밀 키로 사용하는 알고리즘 혼동 공격
+func TestVerifyAccessTokenRejectsAlgorithmConfusion(t *testing.T) {
+	rsKey := newRSAKey(t, "rs")
+	pubDER, err := x509.MarshalPKIXPublicKey(rsKey.verifyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("RS256 only keyset", func(t *testing.T) {
+		s := newService(t, rsKey)
+		token := sign(t, jwt.SigningMethodHS256, "rs", pubDER, validClaims())
+		if _, err := s.VerifyAccessToken(token); !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+			t.Fatalf("expected invalid method error, got %v", err)
+		}
+	})
+
+	t.Run("mixed keyset", func(t *testing.T) {
+		// HS256 이전 키가 남아 있어 HS256 자체는 허용되는 키셋에서도 kid의 알고리즘과 달라야 거부
+		s := newService(t, rsKey, mustHMACKey(t, "old-hs", "old-secret"))
+		token := sign(t, jwt.SigningMethodHS256, "rs", pubDER, validClaims())
+		if _, err := s.VerifyAccessToken(token); !errors.Is(err, ErrTokenAlgorithmMismatch) {
+			t.Fatalf("expected algorithm mismatch, got %v", err)
+		}
+	})
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey := newEd25519Key(t, "2024-12")
+	oldService := newService(t, oldKey)
+	oldToken, err := oldService.IssueAccessToken(42, "user@example.com", "tester", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 새 키로 교체하고 이전 키의 공개 키는 검증 전용으로 유지
+	oldPublic, err := NewVerificationKey(oldKey.ID, oldKey.verifyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey := newRSAKey(t, "2025-01")
+	rotated := newService(t, newKey, oldPublic)
+
+	if _, err := rotated.VerifyAccessToken(oldToken); err != nil {
+		t.Fatalf("token signed with previous key should verify during rotation: %v", err)
+	}
+
+	newToken, err := rotated.IssueAccessToken(42, "user@example.com", "tester", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Header["kid"] != "2025-01" || parsed.Method.Alg() != JWTAlgRS256 {
+		t.Fatalf("new tokens should be signed with the active key, got kid=%v alg=%s", parsed.Header["kid"], parsed.Method.Alg())
+	}
+
+	// 이전 키를 제거한 뒤에는 거부
+	retired := newService(t, newKey)
+	if _, err := retired.VerifyAccessToken(oldToken); !errors.Is(err, ErrTokenUnknownKeyID) && !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+		t.Fatalf("expected retired key to be rejected, got %v", err)
+	}
+}
+
+func TestNewJWTKeySetRequiresSigningKey(t *testing.T) {
+	verifyOnly, err := NewVerificationKey("pub", newRSAKey(t, "rs").verifyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJWTKeySet(verifyOnly); err == nil {
+		t.Fatal("expected error for verify-only active key")
+	}
+	if _, err := NewJWTKeySet(mustHMACKey(t, "a", "x"), mustHMACKey(t, "a", "y")); err == nil {
+		t.Fatal("expected error for duplicate kid")
+	}
+}
+
+func TestGetUserIDFromContextUsesVerificationPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newHMACService(t, "k1", "secret")
+	s.now = time.Now
+	SetDefaultTokenService(s)
+	t.Cleanup(func() { SetDefaultTokenService(nil) })
+
+	request := func(token string) (int64, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+		return GetUserIDFromContext(c)
+	}
+
+	valid, err := s.IssueAccessToken(7, "user@example.com", "tester", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userID, err := request(valid); err != nil || userID != 7 {
+		t.Fatalf("expected user 7, got %d (%v)", userID, err)
+	}
+
+	refresh := validClaims()
+	refresh["type"] = "refresh"
+	refresh["iat"] = time.Now().Unix()
+	refresh["exp"] = time.Now().Add(time.Hour).Unix()
+	if _, err := request(sign(t, jwt.SigningMethodHS256, "k1", []byte("secret"), refresh)); err == nil {
+		t.Fatal("refresh-type token must not authenticate")
+	}
+
+	if _, err := request(sign(t, jwt.SigningMethodNone, "k1", jwt.UnsafeAllowNoneSignatureType, validClaims())); err == nil {
+		t.Fatal("alg=none token must not authenticate")
+	}
+}
+
+func TestGenerateRandomToken(t *testing.T) {
+	a, err := generateRandomToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := generateRandomToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 64 || a == b {
+		t.Fatalf("expected distinct 32-byte hex tokens, got %q and %q", a, b)
+	}
+}
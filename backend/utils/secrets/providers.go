@@ -0,0 +1,339 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const masterKeySize = 32
+
+// keyRing은 ID별 마스터 키와 활성 키 ID를 보관합니다
+type keyRing struct {
+	keys   map[string][]byte
+	active string
+}
+
+func (r *keyRing) wrap(keyID string, dataKey []byte) ([]byte, error) {
+	key, ok := r.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return aesGCMSeal(key, dataKey)
+}
+
+func (r *keyRing) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := r.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return aesGCMOpen(key, wrapped)
+}
+
+func decodeMasterKey(keyID, encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("master key %s is not valid base64: %v", keyID, err)
+	}
+	if len(key) != masterKeySize {
+		return nil, fmt.Errorf("master key %s must be %d bytes, got %d", keyID, masterKeySize, len(key))
+	}
+	return key, nil
+}
+
+// EnvKeyProvider는 환경변수의 마스터 키를 사용합니다
+//
+//	SECRETS_MASTER_KEYS=2025-01:<base64 32바이트>,2024-06:<base64 32바이트>
+//	SECRETS_ACTIVE_KEY_ID=2025-01 (생략 시 첫 번째 키)
+type EnvKeyProvider struct {
+	ring keyRing
+}
+
+// NewEnvKeyProvider는 환경변수에서 마스터 키를 읽어 새 EnvKeyProvider 인스턴스를 생성합니다
+func NewEnvKeyProvider() (*EnvKeyProvider, error) {
+	ring := keyRing{keys: make(map[string][]byte)}
+	var first string
+	for _, entry := range strings.Split(os.Getenv("SECRETS_MASTER_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		keyID, encoded, ok := strings.Cut(entry, ":")
+		if !ok || keyID == "" {
+			return nil, fmt.Errorf("SECRETS_MASTER_KEYS entries must be <key-id>:<base64 key>")
+		}
+		key, err := decodeMasterKey(keyID, encoded)
+		if err != nil {
+			return nil, err
+		}
+		ring.keys[keyID] = key
+		if first == "" {
+			first = keyID
+		}
+	}
+	if len(ring.keys) == 0 {
+		return nil, fmt.Errorf("SECRETS_MASTER_KEYS is empty")
+	}
+
+	ring.active = os.Getenv("SECRETS_ACTIVE_KEY_ID")
+	if ring.active == "" {
+		ring.active = first
+	}
+	if _, ok := ring.keys[ring.active]; !ok {
+		return nil, fmt.Errorf("%w: SECRETS_ACTIVE_KEY_ID=%s", ErrUnknownKey, ring.active)
+	}
+	return &EnvKeyProvider{ring: ring}, nil
+}
+
+func (p *EnvKeyProvider) Name() string        { return "env" }
+func (p *EnvKeyProvider) ActiveKeyID() string { return p.ring.active }
+
+func (p *EnvKeyProvider) WrapKey(keyID string, dataKey []byte) ([]byte, error) {
+	return p.ring.wrap(keyID, dataKey)
+}
+
+func (p *EnvKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	return p.ring.unwrap(keyID, wrapped)
+}
+
+// keyFile은 파일/로컬 KMS 제공자가 사용하는 키 파일 형식입니다
+type keyFile struct {
+	ActiveKeyID string            `json:"active_key_id"`
+	Keys        map[string]string `json:"keys"` // key id -> base64 마스터 키
+}
+
+func readKeyFile(path string) (keyRing, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return keyRing{}, err
+	}
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return keyRing{}, fmt.Errorf("invalid key file %s: %v", path, err)
+	}
+
+	ring := keyRing{keys: make(map[string][]byte), active: file.ActiveKeyID}
+	for keyID, encoded := range file.Keys {
+		key, err := decodeMasterKey(keyID, encoded)
+		if err != nil {
+			return keyRing{}, err
+		}
+		ring.keys[keyID] = key
+	}
+	if _, ok := ring.keys[ring.active]; !ok {
+		return keyRing{}, fmt.Errorf("%w: active_key_id=%s in %s", ErrUnknownKey, ring.active, path)
+	}
+	return ring, nil
+}
+
+// reloadingRing은 모르는 키 ID를 만나면 키 파일을 다시 읽습니다
+// 교체 명령이 새 키로 다시 감싼 레코드를 실행 중인 서버가 재시작 없이 읽을 수 있게 합니다
+type reloadingRing struct {
+	mu   sync.RWMutex
+	path string
+	ring keyRing
+}
+
+func (r *reloadingRing) current() keyRing {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ring
+}
+
+func (r *reloadingRing) reload() error {
+	ring, err := readKeyFile(r.path)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.ring = ring
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *reloadingRing) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	ring := r.current()
+	if _, ok := ring.keys[keyID]; !ok {
+		if err := r.reload(); err != nil {
+			log.Printf("키 파일 다시 읽기 실패 (%s): %v", r.path, err)
+		}
+		ring = r.current()
+	}
+	return ring.unwrap(keyID, wrapped)
+}
+
+// FileKeyProvider는 JSON 키 파일의 마스터 키를 사용합니다
+//
+//	{"active_key_id": "2025-01", "keys": {"2025-01": "<base64>", "2024-06": "<base64>"}}
+type FileKeyProvider struct {
+	keys *reloadingRing
+}
+
+// NewFileKeyProvider는 키 파일을 읽어 새 FileKeyProvider 인스턴스를 생성합니다
+func NewFileKeyProvider(path string) (*FileKeyProvider, error) {
+	keys := &reloadingRing{path: path}
+	if err := keys.reload(); err != nil {
+		return nil, err
+	}
+	return &FileKeyProvider{keys: keys}, nil
+}
+
+func (p *FileKeyProvider) Name() string        { return "file" }
+func (p *FileKeyProvider) ActiveKeyID() string { return p.keys.current().active }
+
+func (p *FileKeyProvider) WrapKey(keyID string, dataKey []byte) ([]byte, error) {
+	ring := p.keys.current()
+	return ring.wrap(keyID, dataKey)
+}
+
+func (p *FileKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	return p.keys.unwrap(keyID, wrapped)
+}
+
+// LocalKMSProvider는 개발/테스트용 로컬 KMS 스텁입니다
+// 키 파일이 없으면 마스터 키를 생성해 저장하며, CreateKey로 새 키를 만들어 활성화할 수 있습니다
+// 실제 KMS처럼 마스터 키는 제공자 밖으로 노출하지 않습니다
+type LocalKMSProvider struct {
+	keys *reloadingRing
+}
+
+// NewLocalKMSProvider는 새 LocalKMSProvider 인스턴스를 생성합니다
+func NewLocalKMSProvider(path string) (*LocalKMSProvider, error) {
+	p := &LocalKMSProvider{keys: &reloadingRing{path: path}}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		log.Printf("로컬 KMS 키 파일이 없어 새 마스터 키를 생성합니다: %s", path)
+		if _, err := p.CreateKey(); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	if err := p.keys.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *LocalKMSProvider) Name() string        { return "local-kms" }
+func (p *LocalKMSProvider) ActiveKeyID() string { return p.keys.current().active }
+
+func (p *LocalKMSProvider) WrapKey(keyID string, dataKey []byte) ([]byte, error) {
+	ring := p.keys.current()
+	return ring.wrap(keyID, dataKey)
+}
+
+func (p *LocalKMSProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	return p.keys.unwrap(keyID, wrapped)
+}
+
+// CreateKey는 새 마스터 키를 생성해 활성 키로 지정하고 키 파일에 저장합니다 (기존 키는 복호화용으로 유지)
+func (p *LocalKMSProvider) CreateKey() (string, error) {
+	key := make([]byte, masterKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", fmt.Errorf("failed to generate master key: %v", err)
+	}
+	suffix := make([]byte, 4)
+	if _, err := io.ReadFull(rand.Reader, suffix); err != nil {
+		return "", fmt.Errorf("failed to generate key id: %v", err)
+	}
+	keyID := fmt.Sprintf("local-%s-%x", time.Now().UTC().Format("20060102T150405"), suffix)
+
+	p.keys.mu.Lock()
+	defer p.keys.mu.Unlock()
+
+	file := keyFile{ActiveKeyID: keyID, Keys: map[string]string{keyID: base64.StdEncoding.EncodeToString(key)}}
+	ids := make([]string, 0, len(p.keys.ring.keys))
+	for id := range p.keys.ring.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if id == keyID {
+			return "", fmt.Errorf("master key %s already exists", keyID)
+		}
+		file.Keys[id] = base64.StdEncoding.EncodeToString(p.keys.ring.keys[id])
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.keys.path), 0o700); err != nil {
+		return "", err
+	}
+	// 다른 프로세스가 반쯤 쓰인 파일을 읽지 않도록 임시 파일에 쓴 뒤 교체
+	tmp := p.keys.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, p.keys.path); err != nil {
+		return "", err
+	}
+
+	ring := keyRing{keys: make(map[string][]byte, len(file.Keys)), active: keyID}
+	for id, k := range p.keys.ring.keys {
+		ring.keys[id] = k
+	}
+	ring.keys[keyID] = key
+	p.keys.ring = ring
+	return keyID, nil
+}
+
+// NewProviderFromEnv는 SECRETS_PROVIDER 환경변수에 따라 KeyProvider를 생성합니다
+//
+//	env       - SECRETS_MASTER_KEYS / SECRETS_ACTIVE_KEY_ID
+//	file      - SECRETS_KEY_FILE
+//	local-kms - SECRETS_LOCAL_KMS_PATH (기본 ./.secrets/local-kms.json, 개발용)
+//
+// SECRETS_PROVIDER가 비어있으면 SECRETS_MASTER_KEYS가 있으면 env, 없으면 local-kms를 사용합니다
+// 운영 환경(GIN_MODE=release/production)에서는 키 파일을 자동 생성하는 local-kms를 허용하지 않으므로
+// env 또는 file 제공자를 명시적으로 설정해야 합니다
+func NewProviderFromEnv() (KeyProvider, error) {
+	kind := strings.ToLower(os.Getenv("SECRETS_PROVIDER"))
+	if kind == "" {
+		if os.Getenv("SECRETS_MASTER_KEYS") != "" {
+			kind = "env"
+		} else if isProduction() {
+			return nil, fmt.Errorf("%w: set SECRETS_PROVIDER=env with SECRETS_MASTER_KEYS or SECRETS_PROVIDER=file with SECRETS_KEY_FILE", ErrProviderNotConfigured)
+		} else {
+			kind = "local-kms"
+		}
+	}
+
+	switch kind {
+	case "env":
+		return NewEnvKeyProvider()
+	case "file":
+		path := os.Getenv("SECRETS_KEY_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("SECRETS_KEY_FILE is required for the file key provider")
+		}
+		return NewFileKeyProvider(path)
+	case "local-kms":
+		if isProduction() {
+			return nil, fmt.Errorf("%w: the local-kms provider is for development only, use env or file", ErrProviderNotConfigured)
+		}
+		path := os.Getenv("SECRETS_LOCAL_KMS_PATH")
+		if path == "" {
+			path = filepath.Join(".secrets", "local-kms.json")
+		}
+		log.Printf("경고: 로컬 KMS 스텁으로 비밀 값을 암호화합니다 (%s). 운영 환경에서는 env 또는 file 제공자를 사용하세요", path)
+		return NewLocalKMSProvider(path)
+	default:
+		return nil, fmt.Errorf("unsupported SECRETS_PROVIDER: %s", kind)
+	}
+}
+
+// isProduction은 운영 모드로 실행 중인지 확인합니다
+func isProduction() bool {
+	mode := os.Getenv("GIN_MODE")
+	return mode == "release" || mode == "production"
+}
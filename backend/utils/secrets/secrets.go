@@ -0,0 +1,239 @@
+// Package secrets는 저장 데이터 봉투 암호화(envelope encryption)를 제공합니다
+//
+// 레코드마다 임의의 데이터 키(DEK)로 AES-256-GCM 암호화하고, 데이터 키는 KeyProvider의
+// 마스터 키로 감싸서(wrap) 암호문과 함께 저장합니다. 레코드에는 마스터 키 ID를 함께 기록하므로
+// 마스터 키를 교체할 때 데이터는 다시 암호화하지 않고 데이터 키만 다시 감싸면 됩니다
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// 봉투 암호문 접두사 (형식 버전)
+const envelopePrefix = "env1."
+
+const dataKeySize = 32
+
+var (
+	// ErrUnknownKey는 KeyProvider에 해당 ID의 마스터 키가 없을 때 반환됩니다
+	ErrUnknownKey = errors.New("unknown master key id")
+	// ErrNotInitialized는 기본 Envelope가 초기화되지 않았을 때 반환됩니다
+	ErrNotInitialized = errors.New("secrets subsystem is not initialized")
+	// ErrMalformedEnvelope는 봉투 암호문 형식이 잘못되었을 때 반환됩니다
+	ErrMalformedEnvelope = errors.New("malformed envelope")
+	// ErrProviderNotConfigured는 운영 환경에서 마스터 키 제공자가 명시적으로 설정되지 않았을 때 반환됩니다
+	ErrProviderNotConfigured = errors.New("secrets key provider is not configured")
+)
+
+// KeyProvider는 데이터 키를 감싸고 푸는 마스터 키 제공자입니다
+type KeyProvider interface {
+	// Name은 제공자 종류를 반환합니다 (env, file, local-kms)
+	Name() string
+	// ActiveKeyID는 새 데이터 키를 감쌀 때 사용할 마스터 키 ID를 반환합니다
+	ActiveKeyID() string
+	// WrapKey는 keyID 마스터 키로 데이터 키를 감쌉니다
+	WrapKey(keyID string, dataKey []byte) ([]byte, error)
+	// UnwrapKey는 keyID 마스터 키로 감싼 데이터 키를 풉니다
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Envelope는 KeyProvider를 사용해 값을 봉투 암호화합니다
+type Envelope struct {
+	provider KeyProvider
+}
+
+// NewEnvelope는 새 Envelope 인스턴스를 생성합니다
+func NewEnvelope(provider KeyProvider) *Envelope {
+	return &Envelope{provider: provider}
+}
+
+// Provider는 사용 중인 KeyProvider를 반환합니다
+func (e *Envelope) Provider() KeyProvider {
+	return e.provider
+}
+
+// Seal은 새 데이터 키로 plaintext를 암호화하고 봉투 암호문과 마스터 키 ID를 반환합니다
+func (e *Envelope) Seal(plaintext []byte) (string, string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", "", fmt.Errorf("failed to generate data key: %v", err)
+	}
+
+	keyID := e.provider.ActiveKeyID()
+	wrapped, err := e.provider.WrapKey(keyID, dataKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	sealed, err := aesGCMSeal(dataKey, plaintext)
+	if err != nil {
+		return "", "", err
+	}
+	return encodeEnvelope(wrapped, sealed), keyID, nil
+}
+
+// Open은 keyID 마스터 키로 봉투 암호문을 복호화합니다
+func (e *Envelope) Open(envelope, keyID string) ([]byte, error) {
+	wrapped, sealed, err := decodeEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := e.provider.UnwrapKey(keyID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return aesGCMOpen(dataKey, sealed)
+}
+
+// Rewrap은 데이터는 그대로 두고 데이터 키만 현재 활성 마스터 키로 다시 감쌉니다
+// 이미 활성 키로 감싼 경우 입력을 그대로 반환합니다
+func (e *Envelope) Rewrap(envelope, keyID string) (string, string, error) {
+	activeKeyID := e.provider.ActiveKeyID()
+	if keyID == activeKeyID {
+		return envelope, keyID, nil
+	}
+
+	wrapped, sealed, err := decodeEnvelope(envelope)
+	if err != nil {
+		return "", "", err
+	}
+	dataKey, err := e.provider.UnwrapKey(keyID, wrapped)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	rewrapped, err := e.provider.WrapKey(activeKeyID, dataKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return encodeEnvelope(rewrapped, sealed), activeKeyID, nil
+}
+
+// IsEnvelope는 값이 봉투 암호문 형식인지 확인합니다
+func IsEnvelope(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// 봉투 형식: env1.base64( uint16(len(wrapped)) | wrapped | nonce | ciphertext )
+func encodeEnvelope(wrapped, sealed []byte) string {
+	buf := make([]byte, 2, 2+len(wrapped)+len(sealed))
+	binary.BigEndian.PutUint16(buf, uint16(len(wrapped)))
+	buf = append(buf, wrapped...)
+	buf = append(buf, sealed...)
+	return envelopePrefix + base64.RawStdEncoding.EncodeToString(buf)
+}
+
+func decodeEnvelope(envelope string) ([]byte, []byte, error) {
+	if !IsEnvelope(envelope) {
+		return nil, nil, ErrMalformedEnvelope
+	}
+	buf, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(envelope, envelopePrefix))
+	if err != nil || len(buf) < 2 {
+		return nil, nil, ErrMalformedEnvelope
+	}
+	n := int(binary.BigEndian.Uint16(buf))
+	if len(buf) < 2+n {
+		return nil, nil, ErrMalformedEnvelope
+	}
+	return buf[2 : 2+n], buf[2+n:], nil
+}
+
+// aesGCMSeal은 key로 plaintext를 암호화하고 nonce | ciphertext를 반환합니다
+func aesGCMSeal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %v", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// aesGCMOpen은 nonce | ciphertext를 key로 복호화합니다
+func aesGCMOpen(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %v", err)
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformedEnvelope
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %v", err)
+	}
+	return plaintext, nil
+}
+
+var (
+	defaultMu       sync.RWMutex
+	defaultEnvelope *Envelope
+)
+
+// SetDefault는 모델 훅 등에서 사용하는 기본 Envelope를 설정합니다
+func SetDefault(envelope *Envelope) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultEnvelope = envelope
+}
+
+// Default는 기본 Envelope를 반환합니다
+func Default() (*Envelope, error) {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	if defaultEnvelope == nil {
+		return nil, ErrNotInitialized
+	}
+	return defaultEnvelope, nil
+}
+
+// InitFromEnv는 환경변수 설정으로 KeyProvider를 만들고 기본 Envelope로 등록합니다
+func InitFromEnv() (*Envelope, error) {
+	provider, err := NewProviderFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	envelope := NewEnvelope(provider)
+	SetDefault(envelope)
+	return envelope, nil
+}
+
+// Seal은 기본 Envelope로 plaintext를 봉투 암호화합니다
+func Seal(plaintext []byte) (string, string, error) {
+	envelope, err := Default()
+	if err != nil {
+		return "", "", err
+	}
+	return envelope.Seal(plaintext)
+}
+
+// Open은 기본 Envelope로 봉투 암호문을 복호화합니다
+func Open(envelope, keyID string) ([]byte, error) {
+	e, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return e.Open(envelope, keyID)
+}
@@ -0,0 +1,205 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), masterKeySize)))
+}
+
+func newEnvProvider(t *testing.T, keys, active string) *EnvKeyProvider {
+	t.Helper()
+	t.Setenv("SECRETS_MASTER_KEYS", keys)
+	t.Setenv("SECRETS_ACTIVE_KEY_ID", active)
+	provider, err := NewEnvKeyProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
+
+func TestSealOpen(t *testing.T) {
+	envelope := NewEnvelope(newEnvProvider(t, "k1:"+testKey('a'), ""))
+
+	sealed, keyID, err := envelope.Seal([]byte("top secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != "k1" || !IsEnvelope(sealed) || strings.Contains(sealed, "top secret") {
+		t.Fatalf("sealed = %q, key id = %q", sealed, keyID)
+	}
+
+	opened, err := envelope.Open(sealed, keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opened) != "top secret" {
+		t.Fatalf("opened = %q", opened)
+	}
+
+	// 같은 값도 레코드마다 다른 데이터 키와 nonce를 사용
+	again, _, _ := envelope.Seal([]byte("top secret"))
+	if again == sealed {
+		t.Fatal("sealing the same plaintext twice must not produce the same envelope")
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	keys := "new:" + testKey('b') + ",old:" + testKey('a')
+	sealed, _, err := NewEnvelope(newEnvProvider(t, keys, "old")).Seal([]byte("credential"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	envelope := NewEnvelope(newEnvProvider(t, keys, "new"))
+	rewrapped, keyID, err := envelope.Rewrap(sealed, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != "new" || rewrapped == sealed {
+		t.Fatalf("rewrap returned key id %q", keyID)
+	}
+	opened, err := envelope.Open(rewrapped, "new")
+	if err != nil || string(opened) != "credential" {
+		t.Fatalf("open rewrapped = %q, %v", opened, err)
+	}
+	if _, err := envelope.Open(rewrapped, "old"); err == nil {
+		t.Fatal("rewrapped envelope must not open with the previous master key")
+	}
+
+	// 이미 활성 키로 감싼 값은 그대로
+	same, keyID, err := envelope.Rewrap(rewrapped, "new")
+	if err != nil || same != rewrapped || keyID != "new" {
+		t.Fatalf("rewrap with active key = %q, %q, %v", same, keyID, err)
+	}
+}
+
+func TestUnknownKeyID(t *testing.T) {
+	envelope := NewEnvelope(newEnvProvider(t, "k1:"+testKey('a'), ""))
+	sealed, _, err := envelope.Seal([]byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := envelope.Open(sealed, "missing"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("open err = %v, want ErrUnknownKey", err)
+	}
+	if _, _, err := envelope.Rewrap(sealed, "missing"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("rewrap err = %v, want ErrUnknownKey", err)
+	}
+
+	t.Setenv("SECRETS_MASTER_KEYS", "k1:"+testKey('a'))
+	t.Setenv("SECRETS_ACTIVE_KEY_ID", "k2")
+	if _, err := NewEnvKeyProvider(); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("unknown active key err = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestMalformedEnvelope(t *testing.T) {
+	envelope := NewEnvelope(newEnvProvider(t, "k1:"+testKey('a'), ""))
+	sealed, _, err := envelope.Seal([]byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oversized := make([]byte, 2)
+	binary.BigEndian.PutUint16(oversized, 100)
+	cases := map[string]string{
+		"empty":          "",
+		"no prefix":      "plaintext-secret",
+		"bad base64":     envelopePrefix + "!!!",
+		"too short":      envelopePrefix + base64.RawStdEncoding.EncodeToString([]byte{1}),
+		"wrapped length": envelopePrefix + base64.RawStdEncoding.EncodeToString(oversized),
+	}
+	for name, value := range cases {
+		if _, err := envelope.Open(value, "k1"); !errors.Is(err, ErrMalformedEnvelope) {
+			t.Errorf("%s: err = %v, want ErrMalformedEnvelope", name, err)
+		}
+	}
+
+	// 암호문이 변조되면 복호화 실패
+	wrapped, data, err := decodeEnvelope(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if _, err := envelope.Open(encodeEnvelope(wrapped, data), "k1"); err == nil {
+		t.Fatal("tampered ciphertext must not decrypt")
+	}
+}
+
+func TestFileKeyProviderReloadsUnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	writeKeyFile(t, path, keyFile{ActiveKeyID: "k1", Keys: map[string]string{"k1": testKey('a')}})
+
+	reader, err := NewFileKeyProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 교체 명령이 새 키로 감싼 값을 실행 중인 제공자가 파일을 다시 읽어 복호화
+	writeKeyFile(t, path, keyFile{ActiveKeyID: "k2", Keys: map[string]string{"k1": testKey('a'), "k2": testKey('b')}})
+	writer, err := NewFileKeyProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, keyID, err := NewEnvelope(writer).Seal([]byte("value"))
+	if err != nil || keyID != "k2" {
+		t.Fatalf("seal = %q, %v", keyID, err)
+	}
+	opened, err := NewEnvelope(reader).Open(sealed, keyID)
+	if err != nil || string(opened) != "value" {
+		t.Fatalf("open after reload = %q, %v", opened, err)
+	}
+}
+
+func TestNewProviderFromEnv(t *testing.T) {
+	t.Setenv("SECRETS_PROVIDER", "")
+	t.Setenv("SECRETS_MASTER_KEYS", "")
+	t.Setenv("SECRETS_LOCAL_KMS_PATH", filepath.Join(t.TempDir(), "local-kms.json"))
+
+	t.Setenv("GIN_MODE", "release")
+	if _, err := NewProviderFromEnv(); !errors.Is(err, ErrProviderNotConfigured) {
+		t.Fatalf("implicit provider in production: err = %v, want ErrProviderNotConfigured", err)
+	}
+	t.Setenv("SECRETS_PROVIDER", "local-kms")
+	if _, err := NewProviderFromEnv(); !errors.Is(err, ErrProviderNotConfigured) {
+		t.Fatalf("local-kms in production: err = %v, want ErrProviderNotConfigured", err)
+	}
+	if _, err := os.Stat(os.Getenv("SECRETS_LOCAL_KMS_PATH")); !os.IsNotExist(err) {
+		t.Fatal("refused provider must not create a key file")
+	}
+
+	t.Setenv("SECRETS_PROVIDER", "")
+	t.Setenv("SECRETS_MASTER_KEYS", "k1:"+testKey('a'))
+	provider, err := NewProviderFromEnv()
+	if err != nil || provider.Name() != "env" {
+		t.Fatalf("env provider in production = %v, %v", provider, err)
+	}
+
+	t.Setenv("GIN_MODE", "debug")
+	t.Setenv("SECRETS_MASTER_KEYS", "")
+	provider, err = NewProviderFromEnv()
+	if err != nil || provider.Name() != "local-kms" {
+		t.Fatalf("development default = %v, %v", provider, err)
+	}
+}
+
+func writeKeyFile(t *testing.T, path string, file keyFile) {
+	t.Helper()
+	data, err := json.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
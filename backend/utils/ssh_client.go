@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -17,6 +18,11 @@ type SSHClient struct {
 	Port       string
 	User       string
 	PrivateKey string
+
+	// HostKeyCallback은 서버 호스트 키 검증 함수입니다 (PinnedHostKeyCallback 사용)
+	HostKeyCallback ssh.HostKeyCallback
+	// HostKeyAlgorithms는 협상할 호스트 키 알고리즘입니다 (비어있으면 기본값)
+	HostKeyAlgorithms []string
 }
 
 // NewSSHClient SSH 클라이언트 생성
@@ -37,21 +43,32 @@ func (c *SSHClient) Connect() (*ssh.Client, error) {
 		return nil, fmt.Errorf("private key 파싱 실패: %v", err)
 	}
 
+	// 호스트 키 검증 없이는 연결하지 않음
+	if c.HostKeyCallback == nil {
+		return nil, ErrHostKeyNotConfigured
+	}
+
 	// SSH 클라이언트 설정
 	config := &ssh.ClientConfig{
 		User: c.User,
 		Auth: []ssh.AuthMethod{
 			ssh.PublicKeys(key),
 		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // 보안상 주의 - 실제 환경에서는 적절한 호스트 키 검증 필요
-		Timeout:         30 * time.Second,
+		HostKeyCallback:   c.HostKeyCallback,
+		HostKeyAlgorithms: c.HostKeyAlgorithms,
+		Timeout:           30 * time.Second,
 	}
 
 	// SSH 연결
 	address := net.JoinHostPort(c.Host, c.Port)
 	client, err := ssh.Dial("tcp", address, config)
 	if err != nil {
-		return nil, fmt.Errorf("SSH 연결 실패: %v", err)
+		// 호스트 키 불일치는 재시도로 해결되지 않으므로 원인 그대로 반환
+		var mismatch *HostKeyMismatchError
+		if errors.As(err, &mismatch) {
+			return nil, mismatch
+		}
+		return nil, fmt.Errorf("SSH 연결 실패: %w", err)
 	}
 
 	return client, nil
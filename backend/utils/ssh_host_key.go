@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// HostKeyMismatchError는 접속한 서버의 호스트 키가 고정된 키와 다를 때 반환됩니다
+type HostKeyMismatchError struct {
+	Host     string
+	Expected string // 고정된 호스트 키 지문
+	Actual   string // 서버가 제시한 호스트 키 지문
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("호스트 키 불일치: %s (고정된 지문 %s, 수신한 지문 %s) - 중간자 공격 가능성이 있어 연결을 중단했습니다. 인스턴스를 재구성했다면 호스트 키를 다시 고정하세요",
+		e.Host, e.Expected, e.Actual)
+}
+
+// ErrHostKeyNotConfigured는 SSHClient에 호스트 키 검증 설정이 없을 때 반환됩니다
+var ErrHostKeyNotConfigured = errors.New("호스트 키 검증 설정이 없습니다")
+
+// errHostKeyCaptured는 호스트 키 수집 후 인증 단계 전에 연결을 끊기 위한 내부 에러입니다
+var errHostKeyCaptured = errors.New("host key captured")
+
+// FormatHostKey는 호스트 키를 authorized_keys 형식 문자열로 변환합니다
+func FormatHostKey(key ssh.PublicKey) string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+}
+
+// ParseHostKey는 authorized_keys(또는 known_hosts 한 줄의 키 부분) 형식의 호스트 키를 파싱합니다
+func ParseHostKey(value string) (ssh.PublicKey, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(value)))
+	if err != nil {
+		return nil, fmt.Errorf("호스트 키 파싱 실패: %v", err)
+	}
+	return key, nil
+}
+
+// HostKeyFingerprint는 OpenSSH와 같은 SHA256 지문을 반환합니다
+func HostKeyFingerprint(key ssh.PublicKey) string {
+	return ssh.FingerprintSHA256(key)
+}
+
+// HostKeyAlgorithmsFor는 서버가 고정된 키와 같은 종류의 호스트 키를 제시하도록 협상 알고리즘을 제한합니다
+// 제한하지 않으면 서버가 다른 종류의 키(예: RSA 대신 ED25519)를 제시해 불일치로 오인할 수 있습니다
+func HostKeyAlgorithmsFor(key ssh.PublicKey) []string {
+	if key.Type() == ssh.KeyAlgoRSA {
+		return []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+	}
+	return []string{key.Type()}
+}
+
+// PinnedHostKeyCallback은 TOFU(trust-on-first-use) 방식으로 호스트 키를 검증합니다
+// pinned가 비어있으면 처음 받은 키를 onFirstUse로 저장하고 신뢰하며, 이후에는 고정된 키와 정확히 일치해야 합니다
+func PinnedHostKeyCallback(pinned string, onFirstUse func(key ssh.PublicKey) error) (ssh.HostKeyCallback, error) {
+	if pinned == "" {
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			if onFirstUse == nil {
+				return ErrHostKeyNotConfigured
+			}
+			return onFirstUse(key)
+		}, nil
+	}
+
+	expected, err := ParseHostKey(pinned)
+	if err != nil {
+		return nil, err
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if key.Type() == expected.Type() && bytes.Equal(key.Marshal(), expected.Marshal()) {
+			return nil
+		}
+		return &HostKeyMismatchError{
+			Host:     hostname,
+			Expected: HostKeyFingerprint(expected),
+			Actual:   HostKeyFingerprint(key),
+		}
+	}, nil
+}
+
+// ScanHostKey는 인증 없이 SSH 핸드셰이크만 수행해 서버의 호스트 키를 가져옵니다 (ssh-keyscan과 동일)
+func ScanHostKey(host, port string, timeout time.Duration) (ssh.PublicKey, error) {
+	var captured ssh.PublicKey
+	config := &ssh.ClientConfig{
+		User: "keyscan",
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			captured = key
+			return errHostKeyCaptured
+		},
+		Timeout: timeout,
+	}
+
+	client, err := ssh.Dial("tcp", net.JoinHostPort(host, port), config)
+	if client != nil {
+		client.Close()
+	}
+	if captured != nil {
+		return captured, nil
+	}
+	if err == nil {
+		err = errors.New("호스트 키를 받지 못했습니다")
+	}
+	return nil, fmt.Errorf("호스트 키 조회 실패: %v", err)
+}
+
+// WaitForHostKey는 프로비저닝 직후 sshd가 올라올 때까지 재시도하며 호스트 키를 가져옵니다
+func WaitForHostKey(ctx context.Context, host, port string, interval time.Duration) (ssh.PublicKey, error) {
+	for {
+		key, err := ScanHostKey(host, port, 10*time.Second)
+		if err == nil {
+			return key, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%v (마지막 오류: %v)", ctx.Err(), err)
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func newHostKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+var testRemote = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 5), Port: 22}
+
+func TestPinnedHostKeyCallbackTrustsOnFirstUse(t *testing.T) {
+	hostKey := newHostKey(t)
+
+	// 고정된 키가 없으면 처음 받은 키를 저장
+	var pinned string
+	callback, err := PinnedHostKeyCallback("", func(key ssh.PublicKey) error {
+		pinned = FormatHostKey(key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := callback("10.0.0.5:22", testRemote, hostKey); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	parsed, err := ParseHostKey(pinned)
+	if err != nil || HostKeyFingerprint(parsed) != HostKeyFingerprint(hostKey) {
+		t.Fatalf("pinned = %q, %v", pinned, err)
+	}
+
+	// 저장에 실패하면 연결도 실패
+	saveErr := errors.New("save failed")
+	callback, _ = PinnedHostKeyCallback("", func(ssh.PublicKey) error { return saveErr })
+	if err := callback("10.0.0.5:22", testRemote, hostKey); !errors.Is(err, saveErr) {
+		t.Fatalf("failed save: %v", err)
+	}
+
+	// 저장 방법이 없으면 어떤 키도 신뢰하지 않음
+	callback, _ = PinnedHostKeyCallback("", nil)
+	if err := callback("10.0.0.5:22", testRemote, hostKey); !errors.Is(err, ErrHostKeyNotConfigured) {
+		t.Fatalf("without onFirstUse: %v", err)
+	}
+}
+
+func TestPinnedHostKeyCallbackVerifiesPinnedKey(t *testing.T) {
+	hostKey := newHostKey(t)
+	firstUse := func(ssh.PublicKey) error {
+		t.Error("onFirstUse called for a pinned host")
+		return nil
+	}
+
+	callback, err := PinnedHostKeyCallback(FormatHostKey(hostKey)+" root@aggregator\n", firstUse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := callback("10.0.0.5:22", testRemote, hostKey); err != nil {
+		t.Fatalf("matching key: %v", err)
+	}
+
+	// 인스턴스 재구성 등으로 키가 바뀌면 거부
+	otherKey := newHostKey(t)
+	err = callback("10.0.0.5:22", testRemote, otherKey)
+	var mismatch *HostKeyMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("mismatched key: %v", err)
+	}
+	want := HostKeyMismatchError{Host: "10.0.0.5:22", Expected: HostKeyFingerprint(hostKey), Actual: HostKeyFingerprint(otherKey)}
+	if *mismatch != want {
+		t.Fatalf("mismatch = %+v, want %+v", *mismatch, want)
+	}
+
+	if _, err := PinnedHostKeyCallback("ssh-ed25519 not-base64", firstUse); err == nil {
+		t.Fatal("invalid pinned key: expected an error")
+	}
+}
+
+func TestHostKeyAlgorithmsFor(t *testing.T) {
+	if got := HostKeyAlgorithmsFor(newHostKey(t)); !reflect.DeepEqual(got, []string{ssh.KeyAlgoED25519}) {
+		t.Fatalf("ed25519 algorithms = %v", got)
+	}
+}
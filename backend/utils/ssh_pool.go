@@ -0,0 +1,541 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHConnectionConfig는 공유 SSH 연결 설정입니다
+type SSHConnectionConfig struct {
+	// MaxSessions는 한 연결에서 동시에 열 수 있는 세션(명령/SFTP 요청) 수입니다 (sshd 기본 MaxSessions=10)
+	MaxSessions int
+	// SessionWaitTimeout은 세션 자리를 기다리는 최대 시간입니다
+	SessionWaitTimeout time.Duration
+	// KeepaliveInterval은 keepalive 요청 주기입니다. 응답이 없으면 연결을 끊고 다음 사용 시 재연결합니다
+	KeepaliveInterval time.Duration
+	// IdleTimeout 동안 사용하지 않은 연결은 닫습니다 (다음 사용 시 재연결)
+	IdleTimeout time.Duration
+}
+
+// DefaultSSHConnectionConfig는 기본 공유 SSH 연결 설정을 반환합니다
+func DefaultSSHConnectionConfig() SSHConnectionConfig {
+	return SSHConnectionConfig{
+		MaxSessions:        8,
+		SessionWaitTimeout: 30 * time.Second,
+		KeepaliveInterval:  30 * time.Second,
+		IdleTimeout:        10 * time.Minute,
+	}
+}
+
+// SSHDialFunc는 새 SSH 연결을 맺는 함수입니다 (재연결 시마다 호출)
+type SSHDialFunc func() (*ssh.Client, error)
+
+// ErrSSHSessionWaitTimeout은 동시 세션 상한으로 세션을 얻지 못했을 때 반환됩니다
+var ErrSSHSessionWaitTimeout = errors.New("SSH 세션 대기 시간 초과")
+
+// ErrSSHConnectionClosed는 관리자에서 제거된 연결을 사용할 때 반환됩니다
+var ErrSSHConnectionClosed = errors.New("SSH 연결이 닫혔습니다")
+
+// SSHConnection은 여러 요청이 공유하는 하나의 다중화된 SSH 연결입니다
+// 세션 수를 제한하고, 끊어지면 다음 사용 시 재연결하며, 같은 연결 위에서 SFTP를 제공합니다
+type SSHConnection struct {
+	key    string
+	dial   SSHDialFunc
+	config SSHConnectionConfig
+
+	sessions chan struct{}
+
+	mu       sync.Mutex
+	client   *ssh.Client
+	sftp     *sftp.Client
+	dialing  *sshDial
+	lastUsed time.Time
+	closed   bool
+	stop     chan struct{}
+}
+
+// sshDial은 진행 중인 연결 시도입니다. 같은 시점에 연결이 필요한 요청들은 하나의 시도 결과를 공유합니다
+type sshDial struct {
+	done chan struct{}
+	err  error
+}
+
+func newSSHConnection(key string, dial SSHDialFunc, config SSHConnectionConfig) *SSHConnection {
+	return &SSHConnection{
+		key:      key,
+		dial:     dial,
+		config:   config,
+		sessions: make(chan struct{}, config.MaxSessions),
+		lastUsed: time.Now(),
+	}
+}
+
+// acquire는 세션 자리를 얻습니다
+func (c *SSHConnection) acquire() error {
+	timer := time.NewTimer(c.config.SessionWaitTimeout)
+	defer timer.Stop()
+
+	select {
+	case c.sessions <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%w: %s (동시 세션 %d개 사용 중)", ErrSSHSessionWaitTimeout, c.key, c.config.MaxSessions)
+	}
+}
+
+func (c *SSHConnection) release() {
+	<-c.sessions
+}
+
+// conn은 연결된 *ssh.Client를 반환하며, 연결이 없으면 새로 연결합니다
+// 연결은 잠금 밖에서 맺으므로 느린 연결 시도가 Close나 유휴 정리를 막지 않습니다
+func (c *SSHConnection) conn() (*ssh.Client, error) {
+	for {
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			return nil, ErrSSHConnectionClosed
+		}
+		c.lastUsed = time.Now()
+		if c.client != nil {
+			client := c.client
+			c.mu.Unlock()
+			return client, nil
+		}
+		if pending := c.dialing; pending != nil {
+			c.mu.Unlock()
+			<-pending.done
+			if pending.err != nil {
+				return nil, pending.err
+			}
+			continue
+		}
+		pending := &sshDial{done: make(chan struct{})}
+		c.dialing = pending
+		c.mu.Unlock()
+
+		client, err := c.dial()
+
+		c.mu.Lock()
+		c.dialing = nil
+		if err == nil && c.closed {
+			client.Close()
+			err = ErrSSHConnectionClosed
+		}
+		if err == nil {
+			c.client = client
+			c.stop = make(chan struct{})
+			go c.keepalive(client, c.stop)
+		}
+		pending.err = err
+		close(pending.done)
+		c.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+}
+
+// transportBroken은 세션/SFTP 생성 실패가 연결 자체가 끊어진 탓인지 확인합니다
+// 서버가 채널이나 서브시스템만 거부한 경우(MaxSessions 초과 등)는 다른 요청이 쓰는 공유 연결을 닫지 않습니다
+func transportBroken(client *ssh.Client, err error) bool {
+	var openErr *ssh.OpenChannelError
+	if errors.As(err, &openErr) {
+		return false
+	}
+	_, _, probeErr := client.SendRequest("keepalive@openssh.com", true, nil)
+	return probeErr != nil
+}
+
+// invalidate는 client가 현재 연결이면 닫아서 다음 사용 시 재연결하게 합니다
+func (c *SSHConnection) invalidate(client *ssh.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != client || client == nil {
+		return
+	}
+	c.closeLocked()
+}
+
+func (c *SSHConnection) closeLocked() {
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	if c.sftp != nil {
+		c.sftp.Close()
+		c.sftp = nil
+	}
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
+// Close는 연결을 닫습니다. 이후 사용은 ErrSSHConnectionClosed를 반환합니다
+func (c *SSHConnection) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	c.closeLocked()
+}
+
+func (c *SSHConnection) keepalive(client *ssh.Client, stop chan struct{}) {
+	if c.config.KeepaliveInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.config.KeepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				log.Printf("SSH keepalive 실패, 연결 정리 (%s): %v", c.key, err)
+				c.invalidate(client)
+				return
+			}
+		}
+	}
+}
+
+// disconnectIfIdle은 cutoff 이후 사용하지 않았고 사용 중인 세션이 없으면 네트워크 연결을 닫습니다
+func (c *SSHConnection) disconnectIfIdle(cutoff time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client == nil || len(c.sessions) > 0 || !c.lastUsed.Before(cutoff) {
+		return
+	}
+	log.Printf("유휴 SSH 연결 종료: %s", c.key)
+	c.closeLocked()
+}
+
+// withSession은 세션 자리를 얻어 새 세션에서 fn을 실행합니다
+// 세션을 열 수 없으면(연결 끊김) 한 번 재연결해서 다시 시도합니다
+func (c *SSHConnection) withSession(fn func(*ssh.Session) error) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+	defer c.release()
+
+	for attempt := 0; ; attempt++ {
+		client, err := c.conn()
+		if err != nil {
+			return err
+		}
+
+		session, err := client.NewSession()
+		if err != nil {
+			if transportBroken(client, err) {
+				c.invalidate(client)
+				if attempt == 0 {
+					continue
+				}
+			}
+			return fmt.Errorf("세션 생성 실패: %v", err)
+		}
+		defer session.Close()
+		return fn(session)
+	}
+}
+
+// SFTP는 세션 자리를 얻어 같은 연결 위의 SFTP 클라이언트를 반환합니다 (연결당 하나를 공유)
+// 사용이 끝나면 release를 호출해 세션 자리를 반납해야 합니다
+func (c *SSHConnection) SFTP() (client *sftp.Client, release func(), err error) {
+	if err := c.acquire(); err != nil {
+		return nil, nil, err
+	}
+	client, err = c.sftpClient()
+	if err != nil {
+		c.release()
+		return nil, nil, err
+	}
+	var once sync.Once
+	return client, func() { once.Do(c.release) }, nil
+}
+
+// sftpClient는 공유 SFTP 클라이언트를 반환하며, 없으면 새로 엽니다. 호출자가 세션 자리를 가지고 있어야 합니다
+func (c *SSHConnection) sftpClient() (*sftp.Client, error) {
+	for attempt := 0; ; attempt++ {
+		client, err := c.conn()
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		if c.client == client && c.sftp != nil {
+			sftpClient := c.sftp
+			c.mu.Unlock()
+			return sftpClient, nil
+		}
+		c.mu.Unlock()
+
+		sftpClient, err := sftp.NewClient(client)
+		if err != nil {
+			if transportBroken(client, err) {
+				c.invalidate(client)
+				if attempt == 0 {
+					continue
+				}
+			}
+			return nil, fmt.Errorf("SFTP 세션 생성 실패: %v", err)
+		}
+
+		c.mu.Lock()
+		if c.client != client {
+			// 그 사이 재연결됨
+			c.mu.Unlock()
+			sftpClient.Close()
+			continue
+		}
+		if c.sftp != nil {
+			c.mu.Unlock()
+			sftpClient.Close()
+			return c.sftp, nil
+		}
+		c.sftp = sftpClient
+		c.mu.Unlock()
+		return sftpClient, nil
+	}
+}
+
+// withSFTP는 세션 자리를 얻어 SFTP 작업을 실행합니다
+func (c *SSHConnection) withSFTP(fn func(*sftp.Client) error) error {
+	sftpClient, release, err := c.SFTP()
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn(sftpClient)
+}
+
+// ExecuteCommand는 공유 연결에서 원격 명령을 실행합니다
+func (c *SSHConnection) ExecuteCommand(command string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	err := c.withSession(func(session *ssh.Session) error {
+		session.Stdout = &stdout
+		session.Stderr = &stderr
+		return session.Run(command)
+	})
+	return stdout.String(), stderr.String(), err
+}
+
+// UploadFileContent는 SFTP로 파일 내용을 업로드합니다
+func (c *SSHConnection) UploadFileContent(content, remotePath string) error {
+	return c.withSFTP(func(client *sftp.Client) error {
+		remoteFile, err := client.Create(remotePath)
+		if err != nil {
+			return fmt.Errorf("원격 파일 생성 실패: %v", err)
+		}
+		defer remoteFile.Close()
+
+		if _, err := remoteFile.Write([]byte(content)); err != nil {
+			return fmt.Errorf("원격 파일 쓰기 실패: %v", err)
+		}
+		return nil
+	})
+}
+
+// UploadFile은 SFTP로 로컬 파일을 업로드합니다
+func (c *SSHConnection) UploadFile(localPath, remotePath string) error {
+	local, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("로컬 파일 읽기 실패: %v", err)
+	}
+	defer local.Close()
+
+	return c.withSFTP(func(client *sftp.Client) error {
+		if err := client.MkdirAll(path.Dir(remotePath)); err != nil {
+			return fmt.Errorf("원격 디렉토리 생성 실패: %v", err)
+		}
+		remoteFile, err := client.Create(remotePath)
+		if err != nil {
+			return fmt.Errorf("원격 파일 생성 실패: %v", err)
+		}
+		defer remoteFile.Close()
+
+		if _, err := io.Copy(remoteFile, local); err != nil {
+			return fmt.Errorf("원격 파일 쓰기 실패: %v", err)
+		}
+		return nil
+	})
+}
+
+// UploadReader는 SFTP로 r의 내용을 원격 파일에 쓰고 쓴 바이트 수를 반환합니다
+func (c *SSHConnection) UploadReader(r io.Reader, remotePath string) (int64, error) {
+	var written int64
+	err := c.withSFTP(func(client *sftp.Client) error {
+		if err := client.MkdirAll(path.Dir(remotePath)); err != nil {
+			return fmt.Errorf("원격 디렉토리 생성 실패: %v", err)
+		}
+		remoteFile, err := client.Create(remotePath)
+		if err != nil {
+			return fmt.Errorf("원격 파일 생성 실패: %v", err)
+		}
+		defer remoteFile.Close()
+
+		written, err = io.Copy(remoteFile, r)
+		if err != nil {
+			return fmt.Errorf("원격 파일 쓰기 실패: %v", err)
+		}
+		return nil
+	})
+	return written, err
+}
+
+// DownloadFile은 SFTP로 원격 파일을 w에 복사하고 복사한 바이트 수를 반환합니다
+func (c *SSHConnection) DownloadFile(remotePath string, w io.Writer) (int64, error) {
+	var written int64
+	err := c.withSFTP(func(client *sftp.Client) error {
+		remoteFile, err := client.Open(remotePath)
+		if err != nil {
+			return fmt.Errorf("원격 파일 열기 실패: %v", err)
+		}
+		defer remoteFile.Close()
+
+		written, err = io.Copy(w, remoteFile)
+		if err != nil {
+			return fmt.Errorf("원격 파일 읽기 실패: %v", err)
+		}
+		return nil
+	})
+	return written, err
+}
+
+// CheckConnection은 연결을 맺거나 기존 연결이 살아있는지 확인합니다
+func (c *SSHConnection) CheckConnection() error {
+	client, err := c.conn()
+	if err != nil {
+		return err
+	}
+	if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+		c.invalidate(client)
+		// 끊어진 연결이었으면 한 번 재연결
+		_, err = c.conn()
+		return err
+	}
+	return nil
+}
+
+// SSHConnectionManager는 키(예: 집계자 ID)별로 하나의 공유 SSH 연결을 관리합니다
+type SSHConnectionManager struct {
+	config SSHConnectionConfig
+
+	mu       sync.Mutex
+	conns    map[string]*SSHConnection
+	versions map[string]string
+	stop     chan struct{}
+}
+
+// NewSSHConnectionManager는 새 SSHConnectionManager 인스턴스를 생성하고 유휴 연결 정리를 시작합니다
+func NewSSHConnectionManager(config SSHConnectionConfig) *SSHConnectionManager {
+	defaults := DefaultSSHConnectionConfig()
+	if config.MaxSessions <= 0 {
+		config.MaxSessions = defaults.MaxSessions
+	}
+	if config.SessionWaitTimeout <= 0 {
+		config.SessionWaitTimeout = defaults.SessionWaitTimeout
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaults.IdleTimeout
+	}
+
+	m := &SSHConnectionManager{
+		config:   config,
+		conns:    make(map[string]*SSHConnection),
+		versions: make(map[string]string),
+		stop:     make(chan struct{}),
+	}
+	go m.reapIdle()
+	return m
+}
+
+// Get은 key의 공유 연결을 반환합니다
+// version(예: 접속 주소)이 바뀌면 기존 연결을 닫고 새 연결로 교체합니다. dial은 실제 연결 시에만 호출됩니다
+func (m *SSHConnectionManager) Get(key, version string, dial SSHDialFunc) *SSHConnection {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if conn, ok := m.conns[key]; ok {
+		if m.versions[key] == version {
+			return conn
+		}
+		conn.Close()
+	}
+
+	conn := newSSHConnection(key, dial, m.config)
+	m.conns[key] = conn
+	m.versions[key] = version
+	return conn
+}
+
+// Close는 key의 연결을 닫고 제거합니다 (집계자 삭제, 호스트 키 재고정 등)
+func (m *SSHConnectionManager) Close(key string) {
+	m.mu.Lock()
+	conn, ok := m.conns[key]
+	delete(m.conns, key)
+	delete(m.versions, key)
+	m.mu.Unlock()
+
+	if ok {
+		conn.Close()
+	}
+}
+
+// Shutdown은 모든 연결을 닫고 유휴 연결 정리를 멈춥니다
+func (m *SSHConnectionManager) Shutdown() {
+	m.mu.Lock()
+	conns := m.conns
+	m.conns = make(map[string]*SSHConnection)
+	m.versions = make(map[string]string)
+	m.mu.Unlock()
+
+	select {
+	case <-m.stop:
+	default:
+		close(m.stop)
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+func (m *SSHConnectionManager) reapIdle() {
+	ticker := time.NewTicker(m.config.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+
+		// 연결 객체는 유지하고 유휴 연결의 네트워크 연결만 닫음 (다음 사용 시 재연결)
+		cutoff := time.Now().Add(-m.config.IdleTimeout)
+		m.mu.Lock()
+		conns := make([]*SSHConnection, 0, len(m.conns))
+		for _, conn := range m.conns {
+			conns = append(conns, conn)
+		}
+		m.mu.Unlock()
+
+		for _, conn := range conns {
+			conn.disconnectIfIdle(cutoff)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// testSSHServer는 exec 요청에 명령 문자열을 그대로 출력하는 in-process SSH 서버입니다
+// "block" 명령은 unblock이 닫힐 때까지 끝나지 않고, sftp 서브시스템은 실제 파일시스템을 사용합니다
+type testSSHServer struct {
+	t        *testing.T
+	listener net.Listener
+	config   *ssh.ServerConfig
+	unblock  chan struct{}
+	// rejectSessions가 설정되면 세션 채널을 거부합니다 (sshd MaxSessions 초과와 같은 응답)
+	rejectSessions atomic.Bool
+
+	mu    sync.Mutex
+	conns []*ssh.ServerConn
+}
+
+func newTestSSHServer(t *testing.T) *testSSHServer {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testSSHServer{t: t, listener: listener, config: config, unblock: make(chan struct{})}
+	t.Cleanup(func() {
+		listener.Close()
+		s.dropConnections()
+	})
+	go s.serve()
+	return s
+}
+
+func (s *testSSHServer) serve() {
+	for {
+		netConn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			conn, channels, requests, err := ssh.NewServerConn(netConn, s.config)
+			if err != nil {
+				netConn.Close()
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+
+			go ssh.DiscardRequests(requests)
+			for newChannel := range channels {
+				if newChannel.ChannelType() != "session" || s.rejectSessions.Load() {
+					newChannel.Reject(ssh.ResourceShortage, "no more sessions")
+					continue
+				}
+				channel, channelRequests, err := newChannel.Accept()
+				if err != nil {
+					continue
+				}
+				go s.handleSession(channel, channelRequests)
+			}
+		}()
+	}
+}
+
+func (s *testSSHServer) handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+	for req := range requests {
+		switch req.Type {
+		case "exec":
+			req.Reply(true, nil)
+			command := string(req.Payload[4:])
+			if command == "block" {
+				<-s.unblock
+			}
+			channel.Write([]byte(command))
+			status := make([]byte, 4)
+			binary.BigEndian.PutUint32(status, 0)
+			channel.SendRequest("exit-status", false, status)
+			channel.Close()
+			return
+		case "subsystem":
+			req.Reply(true, nil)
+			server, err := sftp.NewServer(channel)
+			if err != nil {
+				channel.Close()
+				return
+			}
+			go func() {
+				server.Serve()
+				server.Close()
+			}()
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+// dropConnections는 서버 쪽에서 모든 연결을 끊습니다 (인스턴스 재시작, 네트워크 단절)
+func (s *testSSHServer) dropConnections() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = nil
+	s.mu.Unlock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+// dialer는 연결 횟수를 세는 SSHDialFunc를 반환합니다
+func (s *testSSHServer) dialer(dials *atomic.Int32) SSHDialFunc {
+	return func() (*ssh.Client, error) {
+		dials.Add(1)
+		return ssh.Dial("tcp", s.listener.Addr().String(), &ssh.ClientConfig{
+			User:            "fl",
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+			Timeout:         5 * time.Second,
+		})
+	}
+}
+
+func testConnectionConfig() SSHConnectionConfig {
+	return SSHConnectionConfig{MaxSessions: 2, SessionWaitTimeout: 100 * time.Millisecond, IdleTimeout: time.Hour}
+}
+
+func TestSSHConnectionLimitsSessions(t *testing.T) {
+	server := newTestSSHServer(t)
+	var dials atomic.Int32
+	conn := newSSHConnection("agg-1", server.dialer(&dials), testConnectionConfig())
+	defer conn.Close()
+
+	// 세션 자리 2개를 모두 사용 중이면 대기 후 실패
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if stdout, _, err := conn.ExecuteCommand("block"); err != nil || stdout != "block" {
+				t.Errorf("blocked command = %q, %v", stdout, err)
+			}
+		}()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(conn.sessions) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, _, err := conn.ExecuteCommand("echo"); !errors.Is(err, ErrSSHSessionWaitTimeout) {
+		t.Fatalf("third session error = %v", err)
+	}
+	// SFTP도 같은 세션 자리를 사용
+	if _, _, err := conn.SFTP(); !errors.Is(err, ErrSSHSessionWaitTimeout) {
+		t.Fatalf("sftp while full error = %v", err)
+	}
+
+	close(server.unblock)
+	wg.Wait()
+	if stdout, _, err := conn.ExecuteCommand("echo"); err != nil || stdout != "echo" {
+		t.Fatalf("after release = %q, %v", stdout, err)
+	}
+	// 동시에 연결이 필요했던 요청들도 하나의 연결을 공유
+	if got := dials.Load(); got != 1 {
+		t.Fatalf("dials = %d, want 1", got)
+	}
+
+	_, release, err := conn.SFTP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.sessions) != 1 {
+		t.Fatalf("sessions in use with sftp = %d", len(conn.sessions))
+	}
+	release()
+	release()
+	if len(conn.sessions) != 0 {
+		t.Fatalf("sessions after release = %d", len(conn.sessions))
+	}
+}
+
+func TestSSHConnectionReconnectsAfterDisconnect(t *testing.T) {
+	server := newTestSSHServer(t)
+	var dials atomic.Int32
+	conn := newSSHConnection("agg-1", server.dialer(&dials), testConnectionConfig())
+	defer conn.Close()
+
+	if _, _, err := conn.ExecuteCommand("echo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 서버가 세션만 거부하면 연결은 살아있으므로 공유 연결을 닫지 않음
+	server.rejectSessions.Store(true)
+	if _, _, err := conn.ExecuteCommand("echo"); err == nil || !strings.Contains(err.Error(), "no more sessions") {
+		t.Fatalf("rejected session error = %v", err)
+	}
+	server.rejectSessions.Store(false)
+	if _, _, err := conn.ExecuteCommand("echo"); err != nil || dials.Load() != 1 {
+		t.Fatalf("after rejected session: %v, dials = %d", err, dials.Load())
+	}
+
+	// 연결이 끊어지면 다음 사용 시 재연결
+	server.dropConnections()
+	if stdout, _, err := conn.ExecuteCommand("echo"); err != nil || stdout != "echo" || dials.Load() != 2 {
+		t.Fatalf("after disconnect = %q, %v, dials = %d", stdout, err, dials.Load())
+	}
+
+	// SFTP도 끊어진 연결을 재연결해서 사용
+	remotePath := filepath.Join(t.TempDir(), "models", "global.pt")
+	server.dropConnections()
+	if _, err := conn.UploadReader(strings.NewReader("weights"), remotePath); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(remotePath); err != nil || string(data) != "weights" {
+		t.Fatalf("uploaded = %q, %v", data, err)
+	}
+	if dials.Load() != 3 {
+		t.Fatalf("dials = %d, want 3", dials.Load())
+	}
+
+	conn.Close()
+	if _, _, err := conn.ExecuteCommand("echo"); !errors.Is(err, ErrSSHConnectionClosed) {
+		t.Fatalf("after close error = %v", err)
+	}
+}
+
+func TestSSHConnectionManagerReapsIdleConnections(t *testing.T) {
+	server := newTestSSHServer(t)
+	config := testConnectionConfig()
+	config.IdleTimeout = 40 * time.Millisecond
+	manager := NewSSHConnectionManager(config)
+	defer manager.Shutdown()
+
+	var dials atomic.Int32
+	conn := manager.Get("agg-1", "10.0.0.5", server.dialer(&dials))
+	if _, _, err := conn.ExecuteCommand("echo"); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn.mu.Lock()
+		disconnected := conn.client == nil
+		conn.mu.Unlock()
+		if disconnected {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("idle connection was not closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 연결 객체는 유지되고 다음 사용 시 재연결
+	if manager.Get("agg-1", "10.0.0.5", server.dialer(&dials)) != conn {
+		t.Fatal("manager replaced the idle connection")
+	}
+	if _, _, err := conn.ExecuteCommand("echo"); err != nil || dials.Load() != 2 {
+		t.Fatalf("after reap: %v, dials = %d", err, dials.Load())
+	}
+
+	// 접속 주소가 바뀌면 새 연결로 교체
+	if manager.Get("agg-1", "10.0.0.6", server.dialer(&dials)) == conn {
+		t.Fatal("manager kept the connection for a new address")
+	}
+	if _, _, err := conn.ExecuteCommand("echo"); !errors.Is(err, ErrSSHConnectionClosed) {
+		t.Fatalf("replaced connection error = %v", err)
+	}
+}
@@ -113,13 +113,33 @@ type TerraformResult struct {
 	WorkspaceDir string `json:"workspace_dir"`
 }
 
+// GetTerraformStateRoot는 집계자별 Terraform 상태가 보관되는 루트 디렉토리를 반환합니다
+// TERRAFORM_STATE_DIR 환경변수로 변경할 수 있으며, 재시작 후에도 유지되는 경로여야 합니다
+func GetTerraformStateRoot() string {
+	if dir := os.Getenv("TERRAFORM_STATE_DIR"); dir != "" {
+		return dir
+	}
+	return "terraform-state"
+}
+
+// GetTerraformWorkspaceDir는 집계자 ID에 해당하는 Terraform 작업 디렉토리 경로를 반환합니다
+func GetTerraformWorkspaceDir(aggregatorID string) string {
+	return filepath.Join(GetTerraformStateRoot(), aggregatorID)
+}
+
+// HasTerraformState는 작업 디렉토리에 Terraform 상태 파일이 존재하는지 확인합니다
+func HasTerraformState(workspaceDir string) bool {
+	info, err := os.Stat(filepath.Join(workspaceDir, "terraform.tfstate"))
+	return err == nil && !info.IsDir()
+}
+
 // CreateTerraformWorkspace creates a unique workspace for the deployment
 func CreateTerraformWorkspace(aggregatorID string, config TerraformConfig) (string, error) {
-	// 공통 작업 디렉토리 사용 (aggregator ID별로 구분)
-	workspaceDir := filepath.Join("/tmp", "terraform-workspaces", aggregatorID)
+	// 집계자 ID별 영구 작업 디렉토리 사용 (destroy 시 상태 파일 재사용)
+	workspaceDir := GetTerraformWorkspaceDir(aggregatorID)
 
 	// 디렉토리 생성
-	if err := os.MkdirAll(workspaceDir, 0755); err != nil {
+	if err := os.MkdirAll(workspaceDir, 0700); err != nil {
 		return "", fmt.Errorf("failed to create workspace directory: %v", err)
 	}
 
@@ -243,9 +263,9 @@ JSON
         return fmt.Errorf("unsupported cloud provider: %s", config.CloudProvider)
     }
 
-    // Write terraform.tfvars file
+    // Write terraform.tfvars file (자격증명이 포함되므로 소유자만 읽기 가능)
     varsPath := filepath.Join(workspaceDir, "terraform.tfvars")
-    if err := os.WriteFile(varsPath, []byte(varsContent), 0644); err != nil {
+    if err := os.WriteFile(varsPath, []byte(varsContent), 0600); err != nil {
         return fmt.Errorf("failed to write terraform vars file: %v", err)
     }
 
@@ -322,3 +342,30 @@ func DeployWithTerraformExec(ctx context.Context, workspaceDir string) (*Terrafo
 
     return result, nil
 }
+
+// DestroyWithTerraformContext는 보관된 상태 파일을 사용해 집계자 인프라를 삭제합니다
+func DestroyWithTerraformContext(ctx context.Context, workspaceDir string) error {
+    if !HasTerraformState(workspaceDir) {
+        return fmt.Errorf("terraform state not found in %s", workspaceDir)
+    }
+
+    terraformBinary, err := exec.LookPath("terraform")
+    if err != nil {
+        return fmt.Errorf("terraform binary not found in PATH: %v", err)
+    }
+
+    tf, err := tfexec.NewTerraform(workspaceDir, terraformBinary)
+    if err != nil {
+        return fmt.Errorf("failed to create terraform instance: %v", err)
+    }
+
+    if err := tf.Init(ctx); err != nil {
+        return fmt.Errorf("terraform init failed: %v", err)
+    }
+
+    if err := tf.Destroy(ctx); err != nil {
+        return fmt.Errorf("terraform destroy failed: %v", err)
+    }
+
+    return nil
+}
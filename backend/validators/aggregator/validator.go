@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Mungge/Fleecy-Cloud/models"
 	"github.com/Mungge/Fleecy-Cloud/services/aggregator"
 )
 
@@ -21,9 +22,8 @@ func ValidateCreateAggregatorRequest(name, algorithm, region, storage, instanceT
 		return fmt.Errorf("집계 알고리즘이 필요합니다")
 	}
 
-	validAlgorithms := []string{"fedavg", "fedprox", "fedopt", "scaffold"}
-	if !contains(validAlgorithms, strings.ToLower(algorithm)) {
-		return fmt.Errorf("지원하지 않는 알고리즘입니다: %s", algorithm)
+	if _, err := models.NormalizeAlgorithm(algorithm); err != nil {
+		return fmt.Errorf("지원하지 않는 알고리즘입니다: %s (지원: %s)", algorithm, strings.Join(models.SupportedAlgorithms, ", "))
 	}
 
 	if strings.TrimSpace(region) == "" {
@@ -87,6 +87,10 @@ func ValidateOptimizationRequest(request *aggregator.OptimizationRequest) error
 		return fmt.Errorf("집계 알고리즘이 필요합니다")
 	}
 
+	if _, err := models.NormalizeAlgorithm(request.FederatedLearning.Algorithm); err != nil {
+		return fmt.Errorf("지원하지 않는 알고리즘입니다: %s (지원: %s)", request.FederatedLearning.Algorithm, strings.Join(models.SupportedAlgorithms, ", "))
+	}
+
 	if request.FederatedLearning.Rounds <= 0 {
 		return fmt.Errorf("라운드 수는 1 이상이어야 합니다")
 	}
@@ -128,6 +132,37 @@ func ValidateOptimizationRequest(request *aggregator.OptimizationRequest) error
 		return fmt.Errorf("최대 지연시간이 너무 큽니다 (최대: 10,000ms)")
 	}
 
+	// 4. what-if 제약사항 검증
+	config := request.AggregatorConfig
+	if config.MinMemoryRequirement < 0 || config.MinVCPU < 0 {
+		return fmt.Errorf("최소 메모리/vCPU 요구사항은 0 이상이어야 합니다")
+	}
+
+	if config.PayloadSizeMB < 0 {
+		return fmt.Errorf("페이로드 크기는 0 이상이어야 합니다")
+	}
+
+	if config.PayloadSizeMB > 102400 { // 100GB
+		return fmt.Errorf("페이로드 크기가 너무 큽니다 (최대: 102,400MB)")
+	}
+
+	for _, provider := range config.AllowedProviders {
+		if strings.TrimSpace(provider) == "" {
+			return fmt.Errorf("허용 클라우드 제공자 이름이 비어있습니다")
+		}
+	}
+
+	for _, region := range config.ExcludedRegions {
+		if strings.TrimSpace(region) == "" {
+			return fmt.Errorf("제외 리전 이름이 비어있습니다")
+		}
+	}
+
+	// 5. 가격 스냅샷 버전 검증
+	if request.PriceSnapshotVersion != nil && *request.PriceSnapshotVersion <= 0 {
+		return fmt.Errorf("가격 스냅샷 버전은 1 이상이어야 합니다")
+	}
+
 	return nil
 }
 
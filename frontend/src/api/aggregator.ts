@@ -70,6 +70,12 @@ export interface AggregatorOptimizeConfig {
   maxBudget: number;
   maxLatency: number;
   weightBalance?: number;
+  minMemoryRequirement?: number; // GB 단위
+  minVcpu?: number;
+  requireGpu?: boolean;
+  allowedProviders?: string[];
+  excludedRegions?: string[];
+  payloadSizeMB?: number; // 라운드당 모델 전송 크기
 }
 
 export interface AggregatorConfig {
@@ -384,6 +390,13 @@ export interface AggregatorOption {
   vcpu: number;
   memory: number;
   recommendationScore: number;
+  gpu?: number;
+  estimatedRoundTransferMs?: number;
+  dominance?: {
+    paretoRank: number; // 0이면 Pareto front
+    dominatedBy: number;
+    dominates: number;
+  };
 }
 export interface OptimizationResponse {
   status: string;
@@ -403,22 +416,23 @@ export interface OptimizationResponse {
     };
   };
   optimizedOptions: AggregatorOption[];
+  paretoFront?: AggregatorOption[];
+  paretoObjectives?: string[];
   message: string;
+  priceSnapshotVersion?: number; // 계산에 사용한 가격 스냅샷 버전
 }
 
 // 집계자 배치 최적화 함수
 export const optimizeAggregatorPlacement = async (
   federatedLearningData: FederatedLearningData,
-  constraints: {
-    maxBudget: number;
-    maxLatency: number;
-    minMemoryRequirement?: number;
-  }
+  constraints: AggregatorOptimizeConfig,
+  priceSnapshotVersion?: number // 과거 결정 재현 시 가격 스냅샷 버전 고정
 ): Promise<OptimizationResponse> => {
   try {
     const requestBody = {
       federatedLearning: federatedLearningData,
       aggregatorConfig: constraints,
+      priceSnapshotVersion,
     };
 
     const response = await fetch(`${API_URL}/api/aggregators/optimization`, {
@@ -4,8 +4,9 @@
 export const AGGREGATION_ALGORITHMS = [
     { id: "fedavg", name: "FedAvg" },
     { id: "fedprox", name: "FedProx" },
-    { id: "scaffold", name: "SCAFFOLD" },
-    { id: "fedopt", name: "FedOpt" },
+    { id: "fedadam", name: "FedAdam" },
+    { id: "fedyogi", name: "FedYogi" },
+    { id: "fedadagrad", name: "FedAdagrad" },
   ];
   
   // 지원하는 모델 유형